@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pchchv/aas/pkg/src/adminconsole"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+)
+
+func main() {
+	config.Init("AdminConsole")
+	slog.Info("admin console started")
+	slog.Info("version: " + constants.Version + " (" + constants.BuildDate + ")")
+	slog.Info("base URL: " + config.Get().BaseURL)
+	slog.Info("auth server base URL: " + config.GetAuthServer().BaseURL)
+
+	db, err := database.NewDatabase()
+	if err != nil {
+		fatal(err)
+	}
+
+	isEmpty, err := db.IsEmpty()
+	if err != nil {
+		fatal(err)
+	}
+
+	if isEmpty {
+		slog.Info("database is empty, seeding")
+		if err = database.NewDatabaseSeeder(db).Seed(); err != nil {
+			fatal(err)
+		}
+	}
+
+	r := chi.NewRouter()
+	server := adminconsole.NewServer(r, db)
+	server.Start()
+}
+
+func fatal(err error) {
+	slog.Error(fmt.Sprintf("%+v", err))
+	os.Exit(1)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pchchv/aas/pkg/src/authserver"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	sessionstore "github.com/pchchv/aas/pkg/src/sqlstore"
+)
+
+func main() {
+	config.Init("AuthServer")
+	slog.Info("auth server started")
+	slog.Info("version: " + constants.Version + " (" + constants.BuildDate + ")")
+	slog.Info("base URL: " + config.Get().BaseURL)
+
+	db, err := database.NewDatabase()
+	if err != nil {
+		fatal(err)
+	}
+
+	isEmpty, err := db.IsEmpty()
+	if err != nil {
+		fatal(err)
+	}
+
+	if isEmpty {
+		slog.Info("database is empty, seeding")
+		if err = database.NewDatabaseSeeder(db).Seed(); err != nil {
+			fatal(err)
+		}
+	}
+
+	settings, err := db.GetSettingsById(nil, 1)
+	if err != nil {
+		fatal(err)
+	}
+
+	sqlStore := sessionstore.NewSQLStore(
+		db,
+		"/",
+		86400*365*2, // max age
+		true,        // http only
+		config.Get().SetCookieSecure,
+		http.SameSiteLaxMode,
+		settings.SessionAuthenticationKey,
+		settings.SessionEncryptionKey,
+	)
+	sqlStore.Cleanup(time.Minute * 10)
+
+	gob.Register(oauth.TokenResponse{})
+
+	r := chi.NewRouter()
+	server := authserver.NewServer(r, db, sqlStore)
+	server.Start(settings)
+}
+
+func fatal(err error) {
+	slog.Error(fmt.Sprintf("%+v", err))
+	os.Exit(1)
+}
@@ -0,0 +1,76 @@
+package apihandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+// decodeJsonBody decodes the JSON request body into dst, rejecting unknown fields.
+func decodeJsonBody(r *http.Request, dst interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return badRequest("The request body is not valid JSON: " + err.Error())
+	}
+	return nil
+}
+
+// getIdFromUrlParam parses a numeric id from the chi URL parameter with the given name.
+func getIdFromUrlParam(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, badRequest("The " + name + " is invalid.")
+	}
+	return id, nil
+}
+
+// getPageParams reads the page and pageSize query parameters, applying defaults and limits.
+func getPageParams(r *http.Request) (page int, pageSize int) {
+	page, pageSize = 1, 10
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	if ps, err := strconv.Atoi(r.URL.Query().Get("pageSize")); err == nil && ps > 0 {
+		pageSize = min(ps, 100)
+	}
+
+	return
+}
+
+// getLoggedInSubject returns the subject of the bearer token used to call the API.
+func getLoggedInSubject(r *http.Request) string {
+	if jwtToken, ok := r.Context().Value(constants.ContextKeyBearerToken).(oauth.Jwt); ok {
+		return jwtToken.GetStringClaim("sub")
+	}
+	return ""
+}
+
+// toValidationError turns the error details returned by the validators,
+// which carry neither a code nor a status, into a 400 response.
+func toValidationError(err error) error {
+	if errDetail, ok := err.(*customerrors.ErrorDetail); ok && errDetail.GetHttpStatusCode() == 0 {
+		return badRequest(errDetail.GetDescription())
+	}
+	return err
+}
+
+func badRequest(description string) error {
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", description, http.StatusBadRequest)
+}
+
+func notFound(description string) error {
+	return customerrors.NewErrorDetailWithHttpStatusCode("not_found", description, http.StatusNotFound)
+}
+
+func encodeJsonCreated(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	httpHelper.EncodeJson(w, r, data)
+}
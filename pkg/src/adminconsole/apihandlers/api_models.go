@@ -0,0 +1,387 @@
+package apihandlers
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+type ClientResponse struct {
+	Id                                      int64                `json:"id"`
+	ClientIdentifier                        string               `json:"clientIdentifier"`
+	ClientSecret                            string               `json:"clientSecret,omitempty"`
+	Description                             string               `json:"description"`
+	Enabled                                 bool                 `json:"enabled"`
+	ConsentRequired                         bool                 `json:"consentRequired"`
+	IsPublic                                bool                 `json:"isPublic"`
+	AuthorizationCodeEnabled                bool                 `json:"authorizationCodeEnabled"`
+	ClientCredentialsEnabled                bool                 `json:"clientCredentialsEnabled"`
+	DeviceCodeEnabled                       bool                 `json:"deviceCodeEnabled"`
+	TokenExchangeEnabled                    bool                 `json:"tokenExchangeEnabled"`
+	TokenExchangeImpersonationEnabled       bool                 `json:"tokenExchangeImpersonationEnabled"`
+	TokenExchangeSubjectClients             []string             `json:"tokenExchangeSubjectClients,omitempty"`
+	TokenExpirationInSeconds                int                  `json:"tokenExpirationInSeconds"`
+	RefreshTokenOfflineIdleTimeoutInSeconds int                  `json:"refreshTokenOfflineIdleTimeoutInSeconds"`
+	RefreshTokenOfflineMaxLifetimeInSeconds int                  `json:"refreshTokenOfflineMaxLifetimeInSeconds"`
+	RefreshTokenReuseGracePeriodInSeconds   *int                 `json:"refreshTokenReuseGracePeriodInSeconds"`
+	IncludeOpenIDConnectClaimsInAccessToken string               `json:"includeOpenIDConnectClaimsInAccessToken"`
+	DefaultAcrLevel                         string               `json:"defaultAcrLevel"`
+	PARRequired                             bool                 `json:"parRequired"`
+	RequireSignedRequestObject              bool                 `json:"requireSignedRequestObject"`
+	TokenEndpointAuthMethod                 string               `json:"tokenEndpointAuthMethod"`
+	JWKS                                    json.RawMessage      `json:"jwks,omitempty"`
+	JWKSURI                                 string               `json:"jwksUri,omitempty"`
+	TLSClientAuthSubjectDN                  string               `json:"tlsClientAuthSubjectDN,omitempty"`
+	FrontChannelLogoutURI                   string               `json:"frontChannelLogoutURI,omitempty"`
+	BackChannelLogoutURI                    string               `json:"backChannelLogoutURI,omitempty"`
+	SubjectType                             string               `json:"subjectType"`
+	SectorIdentifierURI                     string               `json:"sectorIdentifierURI,omitempty"`
+	SectorIdentifier                        string               `json:"sectorIdentifier,omitempty"`
+	IdTokenEncryptedResponseAlg             string               `json:"idTokenEncryptedResponseAlg,omitempty"`
+	IdTokenEncryptedResponseEnc             string               `json:"idTokenEncryptedResponseEnc,omitempty"`
+	UserInfoEncryptedResponseAlg            string               `json:"userInfoEncryptedResponseAlg,omitempty"`
+	UserInfoEncryptedResponseEnc            string               `json:"userInfoEncryptedResponseEnc,omitempty"`
+	AuthorizationEncryptedResponseAlg       string               `json:"authorizationEncryptedResponseAlg,omitempty"`
+	AuthorizationEncryptedResponseEnc       string               `json:"authorizationEncryptedResponseEnc,omitempty"`
+	CIBAEnabled                             bool                 `json:"cibaEnabled"`
+	BackChannelTokenDeliveryMode            string               `json:"backChannelTokenDeliveryMode,omitempty"`
+	BackChannelClientNotificationEndpoint   string               `json:"backChannelClientNotificationEndpoint,omitempty"`
+	AccessTokenFormat                       string               `json:"accessTokenFormat"`
+	IsSystemLevelClient                     bool                 `json:"isSystemLevelClient"`
+	IsDynamicallyRegistered                 bool                 `json:"isDynamicallyRegistered"`
+	RedirectURIs                            []string             `json:"redirectURIs,omitempty"`
+	PostLogoutRedirectURIs                  []string             `json:"postLogoutRedirectURIs,omitempty"`
+	WebOrigins                              []string             `json:"webOrigins,omitempty"`
+	Permissions                             []PermissionResponse `json:"permissions,omitempty"`
+	CreatedAt                               *time.Time           `json:"createdAt,omitempty"`
+	UpdatedAt                               *time.Time           `json:"updatedAt,omitempty"`
+}
+
+type ResourceResponse struct {
+	Id                    int64      `json:"id"`
+	ResourceIdentifier    string     `json:"resourceIdentifier"`
+	Description           string     `json:"description"`
+	IsSystemLevelResource bool       `json:"isSystemLevelResource"`
+	CreatedAt             *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt             *time.Time `json:"updatedAt,omitempty"`
+}
+
+type PermissionResponse struct {
+	Id                   int64      `json:"id"`
+	PermissionIdentifier string     `json:"permissionIdentifier"`
+	Description          string     `json:"description"`
+	ResourceId           int64      `json:"resourceId"`
+	ResourceIdentifier   string     `json:"resourceIdentifier,omitempty"`
+	Scope                string     `json:"scope,omitempty"`
+	CreatedAt            *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt            *time.Time `json:"updatedAt,omitempty"`
+}
+
+type UserResponse struct {
+	Id                  int64                `json:"id"`
+	Subject             string               `json:"subject"`
+	Enabled             bool                 `json:"enabled"`
+	Username            string               `json:"username"`
+	Email               string               `json:"email"`
+	EmailVerified       bool                 `json:"emailVerified"`
+	GivenName           string               `json:"givenName"`
+	MiddleName          string               `json:"middleName"`
+	FamilyName          string               `json:"familyName"`
+	Nickname            string               `json:"nickname"`
+	Website             string               `json:"website"`
+	Gender              string               `json:"gender"`
+	DateOfBirth         string               `json:"dateOfBirth"`
+	ZoneInfoCountryName string               `json:"zoneInfoCountryName"`
+	ZoneInfo            string               `json:"zoneInfo"`
+	Locale              string               `json:"locale"`
+	PhoneNumber         string               `json:"phoneNumber"`
+	PhoneNumberVerified bool                 `json:"phoneNumberVerified"`
+	OTPEnabled          bool                 `json:"otpEnabled"`
+	Groups              []GroupResponse      `json:"groups,omitempty"`
+	Permissions         []PermissionResponse `json:"permissions,omitempty"`
+	CreatedAt           *time.Time           `json:"createdAt,omitempty"`
+	UpdatedAt           *time.Time           `json:"updatedAt,omitempty"`
+}
+
+type GroupResponse struct {
+	Id                   int64                `json:"id"`
+	GroupIdentifier      string               `json:"groupIdentifier"`
+	Description          string               `json:"description"`
+	IncludeInIdToken     bool                 `json:"includeInIdToken"`
+	IncludeInAccessToken bool                 `json:"includeInAccessToken"`
+	Permissions          []PermissionResponse `json:"permissions,omitempty"`
+	CreatedAt            *time.Time           `json:"createdAt,omitempty"`
+	UpdatedAt            *time.Time           `json:"updatedAt,omitempty"`
+}
+
+type PagedResponse struct {
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+	Total    int         `json:"total"`
+	Items    interface{} `json:"items"`
+}
+
+type SettingsResponse struct {
+	AppName                                   string `json:"appName"`
+	Issuer                                    string `json:"issuer"`
+	UITheme                                   string `json:"uiTheme"`
+	PasswordPolicy                            string `json:"passwordPolicy"`
+	SelfRegistrationEnabled                   bool   `json:"selfRegistrationEnabled"`
+	SelfRegistrationRequiresEmailVerification bool   `json:"selfRegistrationRequiresEmailVerification"`
+	DynamicClientRegistrationEnabled          bool   `json:"dynamicClientRegistrationEnabled"`
+	DynamicClientRegistrationOpen             bool   `json:"dynamicClientRegistrationOpen"`
+	TokenExpirationInSeconds                  int    `json:"tokenExpirationInSeconds"`
+	RefreshTokenOfflineIdleTimeoutInSeconds   int    `json:"refreshTokenOfflineIdleTimeoutInSeconds"`
+	RefreshTokenOfflineMaxLifetimeInSeconds   int    `json:"refreshTokenOfflineMaxLifetimeInSeconds"`
+	RefreshTokenReuseGracePeriodInSeconds     int    `json:"refreshTokenReuseGracePeriodInSeconds"`
+	IncludeOpenIDConnectClaimsInAccessToken   bool   `json:"includeOpenIDConnectClaimsInAccessToken"`
+	SigningKeyAlgorithm                       string `json:"signingKeyAlgorithm"`
+	KeyRotationIntervalInSeconds              int    `json:"keyRotationIntervalInSeconds"`
+	UserSessionIdleTimeoutInSeconds           int    `json:"userSessionIdleTimeoutInSeconds"`
+	UserSessionMaxLifetimeInSeconds           int    `json:"userSessionMaxLifetimeInSeconds"`
+	SMTPEnabled                               bool   `json:"smtpEnabled"`
+}
+
+type InitialAccessTokenResponse struct {
+	Id          int64      `json:"id"`
+	Token       string     `json:"token,omitempty"`
+	Description string     `json:"description"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+type KeyResponse struct {
+	Id            int64           `json:"id"`
+	KeyIdentifier string          `json:"keyIdentifier"`
+	State         string          `json:"state"`
+	Type          string          `json:"type"`
+	Algorithm     string          `json:"algorithm"`
+	PublicKeyPEM  string          `json:"publicKeyPEM"`
+	PublicKeyJWK  json.RawMessage `json:"publicKeyJWK"`
+	CreatedAt     *time.Time      `json:"createdAt,omitempty"`
+}
+
+func newClientResponse(client *models.Client) ClientResponse {
+	resp := ClientResponse{
+		Id:                                      client.Id,
+		ClientIdentifier:                        client.ClientIdentifier,
+		Description:                             client.Description,
+		Enabled:                                 client.Enabled,
+		ConsentRequired:                         client.ConsentRequired,
+		IsPublic:                                client.IsPublic,
+		AuthorizationCodeEnabled:                client.AuthorizationCodeEnabled,
+		ClientCredentialsEnabled:                client.ClientCredentialsEnabled,
+		DeviceCodeEnabled:                       client.DeviceCodeEnabled,
+		TokenExchangeEnabled:                    client.TokenExchangeEnabled,
+		TokenExchangeImpersonationEnabled:       client.TokenExchangeImpersonationEnabled,
+		TokenExchangeSubjectClients:             strings.Fields(client.TokenExchangeSubjectClients),
+		TokenExpirationInSeconds:                client.TokenExpirationInSeconds,
+		RefreshTokenOfflineIdleTimeoutInSeconds: client.RefreshTokenOfflineIdleTimeoutInSeconds,
+		RefreshTokenOfflineMaxLifetimeInSeconds: client.RefreshTokenOfflineMaxLifetimeInSeconds,
+		IncludeOpenIDConnectClaimsInAccessToken: client.IncludeOpenIDConnectClaimsInAccessToken,
+		DefaultAcrLevel:                         client.DefaultAcrLevel.String(),
+		PARRequired:                             client.PARRequired,
+		RequireSignedRequestObject:              client.RequireSignedRequestObject,
+		TokenEndpointAuthMethod:                 client.TokenEndpointAuthMethod,
+		JWKSURI:                                 client.JWKSURI,
+		TLSClientAuthSubjectDN:                  client.TLSClientAuthSubjectDN,
+		FrontChannelLogoutURI:                   client.FrontChannelLogoutURI,
+		BackChannelLogoutURI:                    client.BackChannelLogoutURI,
+		SubjectType:                             client.SubjectType,
+		SectorIdentifierURI:                     client.SectorIdentifierURI,
+		SectorIdentifier:                        client.SectorIdentifier,
+		IdTokenEncryptedResponseAlg:             client.IdTokenEncryptedResponseAlg,
+		IdTokenEncryptedResponseEnc:             client.IdTokenEncryptedResponseEnc,
+		UserInfoEncryptedResponseAlg:            client.UserInfoEncryptedResponseAlg,
+		UserInfoEncryptedResponseEnc:            client.UserInfoEncryptedResponseEnc,
+		AuthorizationEncryptedResponseAlg:       client.AuthorizationEncryptedResponseAlg,
+		AuthorizationEncryptedResponseEnc:       client.AuthorizationEncryptedResponseEnc,
+		CIBAEnabled:                             client.CIBAEnabled,
+		BackChannelTokenDeliveryMode:            client.BackChannelTokenDeliveryMode,
+		BackChannelClientNotificationEndpoint:   client.BackChannelClientNotificationEndpoint,
+		AccessTokenFormat:                       client.AccessTokenFormat,
+		IsSystemLevelClient:                     client.IsSystemLevelClient(),
+		IsDynamicallyRegistered:                 client.IsDynamicallyRegistered(),
+		Permissions:                             newPermissionResponses(client.Permissions),
+		CreatedAt:                               nullTimeToPtr(client.CreatedAt.Valid, client.CreatedAt.Time),
+		UpdatedAt:                               nullTimeToPtr(client.UpdatedAt.Valid, client.UpdatedAt.Time),
+	}
+
+	if client.RefreshTokenReuseGracePeriodInSeconds.Valid {
+		gracePeriodInSeconds := int(client.RefreshTokenReuseGracePeriodInSeconds.Int32)
+		resp.RefreshTokenReuseGracePeriodInSeconds = &gracePeriodInSeconds
+	}
+
+	if len(resp.AccessTokenFormat) == 0 {
+		resp.AccessTokenFormat = constants.AccessTokenFormatJwt
+	}
+
+	if len(client.JWKS) > 0 {
+		resp.JWKS = json.RawMessage(client.JWKS)
+	}
+
+	for _, redirectURI := range client.RedirectURIs {
+		resp.RedirectURIs = append(resp.RedirectURIs, redirectURI.URI)
+	}
+
+	for _, postLogoutRedirectURI := range client.PostLogoutRedirectURIs {
+		resp.PostLogoutRedirectURIs = append(resp.PostLogoutRedirectURIs, postLogoutRedirectURI.URI)
+	}
+
+	for _, webOrigin := range client.WebOrigins {
+		resp.WebOrigins = append(resp.WebOrigins, webOrigin.Origin)
+	}
+
+	return resp
+}
+
+func newResourceResponse(resource *models.Resource) ResourceResponse {
+	return ResourceResponse{
+		Id:                    resource.Id,
+		ResourceIdentifier:    resource.ResourceIdentifier,
+		Description:           resource.Description,
+		IsSystemLevelResource: resource.IsSystemLevelResource(),
+		CreatedAt:             nullTimeToPtr(resource.CreatedAt.Valid, resource.CreatedAt.Time),
+		UpdatedAt:             nullTimeToPtr(resource.UpdatedAt.Valid, resource.UpdatedAt.Time),
+	}
+}
+
+func newPermissionResponse(permission *models.Permission) PermissionResponse {
+	resp := PermissionResponse{
+		Id:                   permission.Id,
+		PermissionIdentifier: permission.PermissionIdentifier,
+		Description:          permission.Description,
+		ResourceId:           permission.ResourceId,
+		CreatedAt:            nullTimeToPtr(permission.CreatedAt.Valid, permission.CreatedAt.Time),
+		UpdatedAt:            nullTimeToPtr(permission.UpdatedAt.Valid, permission.UpdatedAt.Time),
+	}
+
+	if len(permission.Resource.ResourceIdentifier) > 0 {
+		resp.ResourceIdentifier = permission.Resource.ResourceIdentifier
+		resp.Scope = permission.Resource.ResourceIdentifier + ":" + permission.PermissionIdentifier
+	}
+
+	return resp
+}
+
+func newPermissionResponses(permissions []models.Permission) []PermissionResponse {
+	resp := make([]PermissionResponse, 0, len(permissions))
+	for idx := range permissions {
+		resp = append(resp, newPermissionResponse(&permissions[idx]))
+	}
+	return resp
+}
+
+func newUserResponse(user *models.User) UserResponse {
+	resp := UserResponse{
+		Id:                  user.Id,
+		Subject:             user.Subject.String(),
+		Enabled:             user.Enabled,
+		Username:            user.Username,
+		Email:               user.Email,
+		EmailVerified:       user.EmailVerified,
+		GivenName:           user.GivenName,
+		MiddleName:          user.MiddleName,
+		FamilyName:          user.FamilyName,
+		Nickname:            user.Nickname,
+		Website:             user.Website,
+		Gender:              user.Gender,
+		DateOfBirth:         user.GetDateOfBirthFormatted(),
+		ZoneInfoCountryName: user.ZoneInfoCountryName,
+		ZoneInfo:            user.ZoneInfo,
+		Locale:              user.Locale,
+		PhoneNumber:         user.PhoneNumber,
+		PhoneNumberVerified: user.PhoneNumberVerified,
+		OTPEnabled:          user.OTPEnabled,
+		Permissions:         newPermissionResponses(user.Permissions),
+		CreatedAt:           nullTimeToPtr(user.CreatedAt.Valid, user.CreatedAt.Time),
+		UpdatedAt:           nullTimeToPtr(user.UpdatedAt.Valid, user.UpdatedAt.Time),
+	}
+
+	for idx := range user.Groups {
+		resp.Groups = append(resp.Groups, newGroupResponse(&user.Groups[idx]))
+	}
+
+	return resp
+}
+
+func newUserResponses(users []models.User) []UserResponse {
+	resp := make([]UserResponse, 0, len(users))
+	for idx := range users {
+		resp = append(resp, newUserResponse(&users[idx]))
+	}
+	return resp
+}
+
+func newGroupResponse(group *models.Group) GroupResponse {
+	return GroupResponse{
+		Id:                   group.Id,
+		GroupIdentifier:      group.GroupIdentifier,
+		Description:          group.Description,
+		IncludeInIdToken:     group.IncludeInIdToken,
+		IncludeInAccessToken: group.IncludeInAccessToken,
+		Permissions:          newPermissionResponses(group.Permissions),
+		CreatedAt:            nullTimeToPtr(group.CreatedAt.Valid, group.CreatedAt.Time),
+		UpdatedAt:            nullTimeToPtr(group.UpdatedAt.Valid, group.UpdatedAt.Time),
+	}
+}
+
+func newSettingsResponse(settings *models.Settings) SettingsResponse {
+	return SettingsResponse{
+		AppName:                 settings.AppName,
+		Issuer:                  settings.Issuer,
+		UITheme:                 settings.UITheme,
+		PasswordPolicy:          settings.PasswordPolicy.String(),
+		SelfRegistrationEnabled: settings.SelfRegistrationEnabled,
+		SelfRegistrationRequiresEmailVerification: settings.SelfRegistrationRequiresEmailVerification,
+		DynamicClientRegistrationEnabled:          settings.DynamicClientRegistrationEnabled,
+		DynamicClientRegistrationOpen:             settings.DynamicClientRegistrationOpen,
+		TokenExpirationInSeconds:                  settings.TokenExpirationInSeconds,
+		RefreshTokenOfflineIdleTimeoutInSeconds:   settings.RefreshTokenOfflineIdleTimeoutInSeconds,
+		RefreshTokenOfflineMaxLifetimeInSeconds:   settings.RefreshTokenOfflineMaxLifetimeInSeconds,
+		RefreshTokenReuseGracePeriodInSeconds:     settings.RefreshTokenReuseGracePeriodInSeconds,
+		IncludeOpenIDConnectClaimsInAccessToken:   settings.IncludeOpenIDConnectClaimsInAccessToken,
+		SigningKeyAlgorithm:                       settings.SigningKeyAlgorithm,
+		KeyRotationIntervalInSeconds:              settings.KeyRotationIntervalInSeconds,
+		UserSessionIdleTimeoutInSeconds:           settings.UserSessionIdleTimeoutInSeconds,
+		UserSessionMaxLifetimeInSeconds:           settings.UserSessionMaxLifetimeInSeconds,
+		SMTPEnabled:                               settings.SMTPEnabled,
+	}
+}
+
+func newInitialAccessTokenResponse(initialAccessToken *models.InitialAccessToken) InitialAccessTokenResponse {
+	return InitialAccessTokenResponse{
+		Id:          initialAccessToken.Id,
+		Description: initialAccessToken.Description,
+		ExpiresAt:   nullTimeToPtr(initialAccessToken.ExpiresAt.Valid, initialAccessToken.ExpiresAt.Time),
+		CreatedAt:   nullTimeToPtr(initialAccessToken.CreatedAt.Valid, initialAccessToken.CreatedAt.Time),
+	}
+}
+
+func newKeyResponse(keyPair *models.KeyPair) KeyResponse {
+	resp := KeyResponse{
+		Id:            keyPair.Id,
+		KeyIdentifier: keyPair.KeyIdentifier,
+		State:         keyPair.State,
+		Type:          keyPair.Type,
+		Algorithm:     keyPair.Algorithm,
+		PublicKeyPEM:  string(keyPair.PublicKeyPEM),
+		CreatedAt:     nullTimeToPtr(keyPair.CreatedAt.Valid, keyPair.CreatedAt.Time),
+	}
+
+	if len(keyPair.PublicKeyJWK) > 0 {
+		resp.PublicKeyJWK = json.RawMessage(keyPair.PublicKeyJWK)
+	}
+
+	return resp
+}
+
+func nullTimeToPtr(valid bool, t time.Time) *time.Time {
+	if !valid {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,922 @@
+package apihandlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/stringutil"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+const (
+	maxDescriptionLength = 100
+	maxLifetimeInSeconds = 160000000
+	// a stolen refresh token can be used without being detected during the reuse grace period
+	maxRefreshTokenReuseGracePeriodInSeconds = 300
+)
+
+type CreateClientRequest struct {
+	ClientIdentifier         string `json:"clientIdentifier"`
+	Description              string `json:"description"`
+	AuthorizationCodeEnabled bool   `json:"authorizationCodeEnabled"`
+	ClientCredentialsEnabled bool   `json:"clientCredentialsEnabled"`
+	DeviceCodeEnabled        bool   `json:"deviceCodeEnabled"`
+}
+
+type UpdateClientRequest struct {
+	ClientIdentifier                        string          `json:"clientIdentifier"`
+	Description                             string          `json:"description"`
+	Enabled                                 bool            `json:"enabled"`
+	ConsentRequired                         bool            `json:"consentRequired"`
+	IsPublic                                bool            `json:"isPublic"`
+	AuthorizationCodeEnabled                bool            `json:"authorizationCodeEnabled"`
+	ClientCredentialsEnabled                bool            `json:"clientCredentialsEnabled"`
+	DeviceCodeEnabled                       bool            `json:"deviceCodeEnabled"`
+	TokenExchangeEnabled                    bool            `json:"tokenExchangeEnabled"`
+	TokenExchangeImpersonationEnabled       bool            `json:"tokenExchangeImpersonationEnabled"`
+	TokenExchangeSubjectClients             []string        `json:"tokenExchangeSubjectClients"`
+	TokenExpirationInSeconds                int             `json:"tokenExpirationInSeconds"`
+	RefreshTokenOfflineIdleTimeoutInSeconds int             `json:"refreshTokenOfflineIdleTimeoutInSeconds"`
+	RefreshTokenOfflineMaxLifetimeInSeconds int             `json:"refreshTokenOfflineMaxLifetimeInSeconds"`
+	RefreshTokenReuseGracePeriodInSeconds   *int            `json:"refreshTokenReuseGracePeriodInSeconds"`
+	IncludeOpenIDConnectClaimsInAccessToken string          `json:"includeOpenIDConnectClaimsInAccessToken"`
+	DefaultAcrLevel                         string          `json:"defaultAcrLevel"`
+	PARRequired                             bool            `json:"parRequired"`
+	RequireSignedRequestObject              bool            `json:"requireSignedRequestObject"`
+	TokenEndpointAuthMethod                 string          `json:"tokenEndpointAuthMethod"`
+	JWKS                                    json.RawMessage `json:"jwks"`
+	JWKSURI                                 string          `json:"jwksUri"`
+	TLSClientAuthSubjectDN                  string          `json:"tlsClientAuthSubjectDN"`
+	FrontChannelLogoutURI                   string          `json:"frontChannelLogoutURI"`
+	BackChannelLogoutURI                    string          `json:"backChannelLogoutURI"`
+	SubjectType                             string          `json:"subjectType"`
+	SectorIdentifierURI                     string          `json:"sectorIdentifierURI"`
+	IdTokenEncryptedResponseAlg             string          `json:"idTokenEncryptedResponseAlg"`
+	IdTokenEncryptedResponseEnc             string          `json:"idTokenEncryptedResponseEnc"`
+	UserInfoEncryptedResponseAlg            string          `json:"userInfoEncryptedResponseAlg"`
+	UserInfoEncryptedResponseEnc            string          `json:"userInfoEncryptedResponseEnc"`
+	AuthorizationEncryptedResponseAlg       string          `json:"authorizationEncryptedResponseAlg"`
+	AuthorizationEncryptedResponseEnc       string          `json:"authorizationEncryptedResponseEnc"`
+	CIBAEnabled                             bool            `json:"cibaEnabled"`
+	BackChannelTokenDeliveryMode            string          `json:"backChannelTokenDeliveryMode"`
+	BackChannelClientNotificationEndpoint   string          `json:"backChannelClientNotificationEndpoint"`
+	AccessTokenFormat                       string          `json:"accessTokenFormat"`
+}
+
+type UpdateRedirectURIsRequest struct {
+	RedirectURIs []string `json:"redirectURIs"`
+}
+
+type UpdatePostLogoutRedirectURIsRequest struct {
+	PostLogoutRedirectURIs []string `json:"postLogoutRedirectURIs"`
+}
+
+type UpdateWebOriginsRequest struct {
+	WebOrigins []string `json:"webOrigins"`
+}
+
+type UpdatePermissionIdsRequest struct {
+	PermissionIds []int64 `json:"permissionIds"`
+}
+
+func HandleAPIClientsGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		clients, err := database.GetAllClients(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resp := make([]ClientResponse, 0, len(clients))
+		for idx := range clients {
+			resp = append(resp, newClientResponse(&clients[idx]))
+		}
+
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleAPIClientPost(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input CreateClientRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.ClientIdentifier = strings.TrimSpace(input.ClientIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err := validateClientIdentifierAndDescription(database, identifierValidator, 0, input.ClientIdentifier, input.Description); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		clientSecret := stringutil.GenerateSecurityRandomString(60)
+		clientSecretEncrypted, err := encryption.EncryptText(clientSecret, settings.AESEncryptionKey)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		client := &models.Client{
+			ClientIdentifier:                        input.ClientIdentifier,
+			Description:                             input.Description,
+			ClientSecretEncrypted:                   clientSecretEncrypted,
+			Enabled:                                 true,
+			ConsentRequired:                         false,
+			IsPublic:                                false,
+			AuthorizationCodeEnabled:                input.AuthorizationCodeEnabled,
+			ClientCredentialsEnabled:                input.ClientCredentialsEnabled,
+			DeviceCodeEnabled:                       input.DeviceCodeEnabled,
+			DefaultAcrLevel:                         enums.AcrLevel2Optional,
+			IncludeOpenIDConnectClaimsInAccessToken: enums.ThreeStateSettingDefault.String(),
+			SubjectType:                             constants.SubjectTypePublic,
+		}
+		if err = database.CreateClient(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedClient, map[string]interface{}{
+			"clientId":         client.Id,
+			"clientIdentifier": client.ClientIdentifier,
+			"loggedInUser":     getLoggedInSubject(r),
+		})
+
+		// the secret is only returned once, when the client is created
+		resp := newClientResponse(client)
+		resp.ClientSecret = clientSecret
+		encodeJsonCreated(w, r, httpHelper, resp)
+	}
+}
+
+func HandleAPIClientGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.ClientLoadRedirectURIs(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.ClientLoadPostLogoutRedirectURIs(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.ClientLoadWebOrigins(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.ClientLoadPermissions(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.PermissionsLoadResources(nil, client.Permissions); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newClientResponse(client))
+	}
+}
+
+func HandleAPIClientPut(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if client.IsSystemLevelClient() {
+			httpHelper.JsonError(w, r, badRequest("System level clients cannot be modified."))
+			return
+		}
+
+		var input UpdateClientRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.ClientIdentifier = strings.TrimSpace(input.ClientIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err = validateClientIdentifierAndDescription(database, identifierValidator, client.Id, input.ClientIdentifier, input.Description); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		acrLevel, err := enums.AcrLevelFromString(input.DefaultAcrLevel)
+		if err != nil {
+			httpHelper.JsonError(w, r, badRequest("The default ACR level is invalid."))
+			return
+		}
+
+		includeClaims, err := enums.ThreeStateSettingFromString(input.IncludeOpenIDConnectClaimsInAccessToken)
+		if err != nil {
+			httpHelper.JsonError(w, r, badRequest("The value for includeOpenIDConnectClaimsInAccessToken is invalid. Use on, off or default."))
+			return
+		}
+
+		if input.IsPublic && input.ClientCredentialsEnabled {
+			httpHelper.JsonError(w, r, badRequest("A public client cannot use the client credentials flow."))
+			return
+		}
+
+		// request objects are verified with the client secret or the registered keys, a public client has neither
+		if input.IsPublic && input.RequireSignedRequestObject {
+			httpHelper.JsonError(w, r, badRequest("A public client cannot require signed request objects."))
+			return
+		}
+
+		if input.IsPublic && input.TokenExchangeEnabled {
+			httpHelper.JsonError(w, r, badRequest("A public client cannot use token exchange."))
+			return
+		} else if input.TokenExchangeImpersonationEnabled && !input.TokenExchangeEnabled {
+			httpHelper.JsonError(w, r, badRequest("Impersonation requires token exchange to be enabled."))
+			return
+		}
+
+		// the client can always exchange the tokens issued to it or meant for it,
+		// the tokens of other clients only when they are listed
+		tokenExchangeSubjectClients := []string{}
+		if input.TokenExchangeEnabled {
+			for _, subjectClientIdentifier := range input.TokenExchangeSubjectClients {
+				subjectClientIdentifier = strings.TrimSpace(subjectClientIdentifier)
+				if len(subjectClientIdentifier) == 0 || slices.Contains(tokenExchangeSubjectClients, subjectClientIdentifier) {
+					continue
+				}
+
+				subjectClient, err := database.GetClientByClientIdentifier(nil, subjectClientIdentifier)
+				if err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				} else if subjectClient == nil {
+					httpHelper.JsonError(w, r, badRequest("The client "+subjectClientIdentifier+" whose tokens can be exchanged does not exist."))
+					return
+				}
+
+				tokenExchangeSubjectClients = append(tokenExchangeSubjectClients, subjectClientIdentifier)
+			}
+		}
+
+		// backchannel authentication requests are only accepted from confidential clients (OpenID Connect CIBA, section 7.1)
+		if input.IsPublic && input.CIBAEnabled {
+			httpHelper.JsonError(w, r, badRequest("A public client cannot use backchannel authentication."))
+			return
+		}
+
+		input.BackChannelClientNotificationEndpoint = strings.TrimSpace(input.BackChannelClientNotificationEndpoint)
+		if input.CIBAEnabled {
+			if input.BackChannelTokenDeliveryMode, err = validators.ValidateBackChannelTokenDelivery(
+				input.BackChannelTokenDeliveryMode, input.BackChannelClientNotificationEndpoint); err != nil {
+				httpHelper.JsonError(w, r, toValidationError(err))
+				return
+			}
+		} else {
+			input.BackChannelTokenDeliveryMode = ""
+			input.BackChannelClientNotificationEndpoint = ""
+		}
+
+		switch input.AccessTokenFormat {
+		case "", constants.AccessTokenFormatJwt:
+			input.AccessTokenFormat = constants.AccessTokenFormatJwt
+		case constants.AccessTokenFormatOpaque:
+		default:
+			httpHelper.JsonError(w, r, badRequest("The access token format is invalid. Use jwt or opaque."))
+			return
+		}
+
+		if err = validateTokenLifetimes(input.TokenExpirationInSeconds, input.RefreshTokenOfflineIdleTimeoutInSeconds, input.RefreshTokenOfflineMaxLifetimeInSeconds, true); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		// without a grace period, the client uses the one of the settings
+		refreshTokenReuseGracePeriod := sql.NullInt32{}
+		if input.RefreshTokenReuseGracePeriodInSeconds != nil {
+			if err = validateRefreshTokenReuseGracePeriod(*input.RefreshTokenReuseGracePeriodInSeconds); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+			refreshTokenReuseGracePeriod = sql.NullInt32{Int32: int32(*input.RefreshTokenReuseGracePeriodInSeconds), Valid: true}
+		}
+
+		if string(input.JWKS) == "null" {
+			input.JWKS = nil
+		}
+
+		// the symmetric encryption keys are derived from the client secret
+		hasClientSecret := !input.IsPublic && len(client.ClientSecretEncrypted) > 0
+		if input.IdTokenEncryptedResponseEnc, err = validators.ValidateResponseEncryption("id_token",
+			input.IdTokenEncryptedResponseAlg, input.IdTokenEncryptedResponseEnc, hasClientSecret); err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		if input.UserInfoEncryptedResponseEnc, err = validators.ValidateResponseEncryption("userinfo",
+			input.UserInfoEncryptedResponseAlg, input.UserInfoEncryptedResponseEnc, hasClientSecret); err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		if input.AuthorizationEncryptedResponseEnc, err = validators.ValidateResponseEncryption("authorization",
+			input.AuthorizationEncryptedResponseAlg, input.AuthorizationEncryptedResponseEnc, hasClientSecret); err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		usesEncryptionKeys := input.IdTokenEncryptedResponseAlg == encryption.JWEAlgorithmRSAOAEP256 ||
+			input.UserInfoEncryptedResponseAlg == encryption.JWEAlgorithmRSAOAEP256 ||
+			input.AuthorizationEncryptedResponseAlg == encryption.JWEAlgorithmRSAOAEP256
+		input.JWKSURI = strings.TrimSpace(input.JWKSURI)
+		input.TLSClientAuthSubjectDN = strings.TrimSpace(input.TLSClientAuthSubjectDN)
+		if err = validateTokenEndpointAuthMethod(input.IsPublic, input.TokenEndpointAuthMethod, input.JWKS, input.JWKSURI, input.TLSClientAuthSubjectDN, usesEncryptionKeys); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.FrontChannelLogoutURI = strings.TrimSpace(input.FrontChannelLogoutURI)
+		input.BackChannelLogoutURI = strings.TrimSpace(input.BackChannelLogoutURI)
+		for _, logoutURI := range []string{input.FrontChannelLogoutURI, input.BackChannelLogoutURI} {
+			if len(logoutURI) > 0 {
+				if err = validateClientURI(logoutURI, "logout URI"); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		if len(input.SubjectType) == 0 {
+			input.SubjectType = constants.SubjectTypePublic
+		}
+
+		input.SectorIdentifierURI = strings.TrimSpace(input.SectorIdentifierURI)
+		if input.SubjectType == constants.SubjectTypePairwise {
+			if err = database.ClientLoadRedirectURIs(nil, client); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+		}
+
+		sectorIdentifier, err := validators.ValidateSubjectType(input.SubjectType, input.SectorIdentifierURI, getRedirectURIValues(client.RedirectURIs))
+		if err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		client.ClientIdentifier = input.ClientIdentifier
+		client.Description = input.Description
+		client.Enabled = input.Enabled
+		client.ConsentRequired = input.ConsentRequired
+		client.IsPublic = input.IsPublic
+		client.AuthorizationCodeEnabled = input.AuthorizationCodeEnabled
+		client.ClientCredentialsEnabled = input.ClientCredentialsEnabled
+		client.DeviceCodeEnabled = input.DeviceCodeEnabled
+		client.TokenExchangeEnabled = input.TokenExchangeEnabled
+		client.TokenExchangeImpersonationEnabled = input.TokenExchangeImpersonationEnabled
+		client.TokenExchangeSubjectClients = strings.Join(tokenExchangeSubjectClients, " ")
+		client.TokenExpirationInSeconds = input.TokenExpirationInSeconds
+		client.RefreshTokenOfflineIdleTimeoutInSeconds = input.RefreshTokenOfflineIdleTimeoutInSeconds
+		client.RefreshTokenOfflineMaxLifetimeInSeconds = input.RefreshTokenOfflineMaxLifetimeInSeconds
+		client.RefreshTokenReuseGracePeriodInSeconds = refreshTokenReuseGracePeriod
+		client.IncludeOpenIDConnectClaimsInAccessToken = includeClaims.String()
+		client.DefaultAcrLevel = acrLevel
+		client.PARRequired = input.PARRequired
+		client.RequireSignedRequestObject = input.RequireSignedRequestObject
+		client.TokenEndpointAuthMethod = input.TokenEndpointAuthMethod
+		client.JWKS = input.JWKS
+		client.JWKSURI = input.JWKSURI
+		client.TLSClientAuthSubjectDN = input.TLSClientAuthSubjectDN
+		client.FrontChannelLogoutURI = input.FrontChannelLogoutURI
+		client.BackChannelLogoutURI = input.BackChannelLogoutURI
+		client.SubjectType = input.SubjectType
+		client.SectorIdentifierURI = input.SectorIdentifierURI
+		client.SectorIdentifier = sectorIdentifier
+		client.IdTokenEncryptedResponseAlg = input.IdTokenEncryptedResponseAlg
+		client.IdTokenEncryptedResponseEnc = input.IdTokenEncryptedResponseEnc
+		client.UserInfoEncryptedResponseAlg = input.UserInfoEncryptedResponseAlg
+		client.UserInfoEncryptedResponseEnc = input.UserInfoEncryptedResponseEnc
+		client.AuthorizationEncryptedResponseAlg = input.AuthorizationEncryptedResponseAlg
+		client.AuthorizationEncryptedResponseEnc = input.AuthorizationEncryptedResponseEnc
+		client.CIBAEnabled = input.CIBAEnabled
+		client.BackChannelTokenDeliveryMode = input.BackChannelTokenDeliveryMode
+		client.BackChannelClientNotificationEndpoint = input.BackChannelClientNotificationEndpoint
+		client.AccessTokenFormat = input.AccessTokenFormat
+		if err = database.UpdateClient(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedClientSettings, map[string]interface{}{
+			"clientId":     client.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newClientResponse(client))
+	}
+}
+
+func HandleAPIClientDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if client.IsSystemLevelClient() {
+			httpHelper.JsonError(w, r, badRequest("System level clients cannot be deleted."))
+			return
+		}
+
+		if err = database.DeleteClient(nil, client.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditDeletedClient, map[string]interface{}{
+			"clientId":         client.Id,
+			"clientIdentifier": client.ClientIdentifier,
+			"loggedInUser":     getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandleAPIClientRedirectURIsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if client.IsSystemLevelClient() {
+			httpHelper.JsonError(w, r, badRequest("System level clients cannot be modified."))
+			return
+		}
+
+		var input UpdateRedirectURIsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		redirectURIs, err := getClientURIs(input.RedirectURIs, "redirect URI")
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.ClientLoadRedirectURIs(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		// the pairwise subjects already issued to the client are bound to its sector
+		if client.UsesPairwiseSubject() {
+			sectorIdentifier, err := validators.ValidateSubjectType(client.SubjectType, client.SectorIdentifierURI, redirectURIs)
+			if err != nil {
+				httpHelper.JsonError(w, r, toValidationError(err))
+				return
+			} else if sectorIdentifier != client.SectorIdentifier {
+				httpHelper.JsonError(w, r, badRequest("The redirect URIs of a pairwise client must stay within its sector: "+client.SectorIdentifier+"."))
+				return
+			}
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		existing := make([]string, 0, len(client.RedirectURIs))
+		for _, redirectURI := range client.RedirectURIs {
+			existing = append(existing, redirectURI.URI)
+			if !slices.Contains(redirectURIs, redirectURI.URI) {
+				if err = database.DeleteRedirectURI(tx, redirectURI.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		for _, redirectURI := range redirectURIs {
+			if !slices.Contains(existing, redirectURI) {
+				if err = database.CreateRedirectURI(tx, &models.RedirectURI{ClientId: client.Id, URI: redirectURI}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedRedirectURIs, map[string]interface{}{
+			"clientId":     client.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, UpdateRedirectURIsRequest{RedirectURIs: redirectURIs})
+	}
+}
+
+func HandleAPIClientPostLogoutRedirectURIsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if client.IsSystemLevelClient() {
+			httpHelper.JsonError(w, r, badRequest("System level clients cannot be modified."))
+			return
+		}
+
+		var input UpdatePostLogoutRedirectURIsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		postLogoutRedirectURIs, err := getClientURIs(input.PostLogoutRedirectURIs, "post logout redirect URI")
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.ClientLoadPostLogoutRedirectURIs(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		existing := make([]string, 0, len(client.PostLogoutRedirectURIs))
+		for _, postLogoutRedirectURI := range client.PostLogoutRedirectURIs {
+			existing = append(existing, postLogoutRedirectURI.URI)
+			if !slices.Contains(postLogoutRedirectURIs, postLogoutRedirectURI.URI) {
+				if err = database.DeletePostLogoutRedirectURI(tx, postLogoutRedirectURI.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		for _, postLogoutRedirectURI := range postLogoutRedirectURIs {
+			if !slices.Contains(existing, postLogoutRedirectURI) {
+				if err = database.CreatePostLogoutRedirectURI(tx, &models.PostLogoutRedirectURI{ClientId: client.Id, URI: postLogoutRedirectURI}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedPostLogoutRedirectURIs, map[string]interface{}{
+			"clientId":     client.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, UpdatePostLogoutRedirectURIsRequest{PostLogoutRedirectURIs: postLogoutRedirectURIs})
+	}
+}
+
+func HandleAPIClientWebOriginsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if client.IsSystemLevelClient() {
+			httpHelper.JsonError(w, r, badRequest("System level clients cannot be modified."))
+			return
+		}
+
+		var input UpdateWebOriginsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		webOrigins := make([]string, 0, len(input.WebOrigins))
+		for _, webOrigin := range input.WebOrigins {
+			webOrigin = strings.ToLower(strings.TrimSpace(webOrigin))
+			if u, err := url.Parse(webOrigin); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+				len(u.Host) == 0 || len(strings.TrimSuffix(u.Path, "/")) > 0 || len(u.RawQuery) > 0 || len(u.Fragment) > 0 {
+				httpHelper.JsonError(w, r, badRequest("Invalid web origin: "+webOrigin+". It must contain only the scheme, host and optional port."))
+				return
+			}
+
+			webOrigin = strings.TrimSuffix(webOrigin, "/")
+			if !slices.Contains(webOrigins, webOrigin) {
+				webOrigins = append(webOrigins, webOrigin)
+			}
+		}
+
+		if err = database.ClientLoadWebOrigins(nil, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		existing := make([]string, 0, len(client.WebOrigins))
+		for _, webOrigin := range client.WebOrigins {
+			existing = append(existing, webOrigin.Origin)
+			if !slices.Contains(webOrigins, webOrigin.Origin) {
+				if err = database.DeleteWebOrigin(tx, webOrigin.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		for _, webOrigin := range webOrigins {
+			if !slices.Contains(existing, webOrigin) {
+				if err = database.CreateWebOrigin(tx, &models.WebOrigin{ClientId: client.Id, Origin: webOrigin}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedWebOrigins, map[string]interface{}{
+			"clientId":     client.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, UpdateWebOriginsRequest{WebOrigins: webOrigins})
+	}
+}
+
+func HandleAPIClientPermissionsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getClientFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if client.IsSystemLevelClient() {
+			httpHelper.JsonError(w, r, badRequest("System level clients cannot be modified."))
+			return
+		}
+
+		var input UpdatePermissionIdsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permissions, err := getPermissionsByIds(database, input.PermissionIds)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		clientPermissions, err := database.GetClientPermissionsByClientId(nil, client.Id)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		existing := make([]int64, 0, len(clientPermissions))
+		for _, clientPermission := range clientPermissions {
+			existing = append(existing, clientPermission.PermissionId)
+			if !slices.Contains(input.PermissionIds, clientPermission.PermissionId) {
+				if err = database.DeleteClientPermission(tx, clientPermission.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		for _, permission := range permissions {
+			if !slices.Contains(existing, permission.Id) {
+				if err = database.CreateClientPermission(tx, &models.ClientPermission{ClientId: client.Id, PermissionId: permission.Id}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedClientPermissions, map[string]interface{}{
+			"clientId":     client.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newPermissionResponses(permissions))
+	}
+}
+
+func getClientFromUrlParam(r *http.Request, database database.Database) (*models.Client, error) {
+	clientId, err := getIdFromUrlParam(r, "clientId")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := database.GetClientById(nil, clientId)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, notFound("Client not found.")
+	}
+
+	return client, nil
+}
+
+func validateClientIdentifierAndDescription(database database.Database, identifierValidator IdentifierValidator, clientId int64, clientIdentifier string, description string) error {
+	if len(clientIdentifier) == 0 {
+		return badRequest("Client identifier is required.")
+	}
+
+	if err := identifierValidator.ValidateIdentifier(clientIdentifier, true); err != nil {
+		return toValidationError(err)
+	}
+
+	if len(description) > maxDescriptionLength {
+		return badRequest("The description cannot exceed a maximum length of 100 characters.")
+	}
+
+	existingClient, err := database.GetClientByClientIdentifier(nil, clientIdentifier)
+	if err != nil {
+		return err
+	} else if existingClient != nil && existingClient.Id != clientId {
+		return badRequest("The client identifier is already in use.")
+	}
+
+	return nil
+}
+
+// getRedirectURIValues returns the URIs of the redirect URIs of a client
+func getRedirectURIValues(redirectURIs []models.RedirectURI) []string {
+	values := make([]string, 0, len(redirectURIs))
+	for _, redirectURI := range redirectURIs {
+		values = append(values, redirectURI.URI)
+	}
+
+	return values
+}
+
+// validateTokenLifetimes checks the token expiration and offline refresh token timeouts.
+// When allowZero is set, zero values are accepted and mean "use the global settings".
+// validateTokenEndpointAuthMethod accepts an empty method (the client secret) or one of
+// the client assertion and mutual TLS methods, with the keys or subject DN they require
+// getClientURIs trims and deduplicates the URIs, which must be absolute and have no fragment
+func getClientURIs(input []string, name string) ([]string, error) {
+	uris := make([]string, 0, len(input))
+	for _, uri := range input {
+		uri = strings.TrimSpace(uri)
+		if err := validateClientURI(uri, name); err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris, nil
+}
+
+func validateClientURI(uri string, name string) error {
+	if u, err := url.ParseRequestURI(uri); err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return badRequest("Invalid " + name + ": " + uri + ".")
+	} else if len(u.Fragment) > 0 {
+		return badRequest("The " + name + " must not include a fragment: " + uri + ".")
+	}
+
+	return nil
+}
+
+func validateTokenEndpointAuthMethod(isPublic bool, tokenEndpointAuthMethod string, jwks []byte, jwksURI string, tlsClientAuthSubjectDN string, usesEncryptionKeys bool) error {
+	switch tokenEndpointAuthMethod {
+	case "":
+	case constants.TokenEndpointAuthMethodPrivateKeyJwt, constants.TokenEndpointAuthMethodClientSecretJwt,
+		constants.TokenEndpointAuthMethodTLSClientAuth, constants.TokenEndpointAuthMethodSelfSignedTLS:
+		if isPublic {
+			return badRequest("A public client cannot use a token endpoint authentication method.")
+		}
+	default:
+		return badRequest("The token endpoint authentication method is invalid. Use private_key_jwt, client_secret_jwt, " +
+			"tls_client_auth, self_signed_tls_client_auth or leave it empty.")
+	}
+
+	return toValidationError(validators.ValidateClientAuthentication(tokenEndpointAuthMethod, jwks, jwksURI, tlsClientAuthSubjectDN, usesEncryptionKeys))
+}
+
+func validateTokenLifetimes(tokenExpiration, refreshTokenIdleTimeout, refreshTokenMaxLifetime int, allowZero bool) error {
+	minValue := 1
+	if allowZero {
+		minValue = 0
+	}
+
+	if tokenExpiration < minValue || tokenExpiration > maxLifetimeInSeconds {
+		return badRequest("The token expiration is out of range.")
+	}
+
+	if refreshTokenIdleTimeout < minValue || refreshTokenIdleTimeout > maxLifetimeInSeconds {
+		return badRequest("The refresh token offline idle timeout is out of range.")
+	}
+
+	if refreshTokenMaxLifetime < minValue || refreshTokenMaxLifetime > maxLifetimeInSeconds {
+		return badRequest("The refresh token offline max lifetime is out of range.")
+	}
+
+	if refreshTokenIdleTimeout > refreshTokenMaxLifetime {
+		return badRequest("The refresh token offline idle timeout cannot be greater than the max lifetime.")
+	}
+
+	return nil
+}
+
+func validateRefreshTokenReuseGracePeriod(gracePeriodInSeconds int) error {
+	if gracePeriodInSeconds < 0 || gracePeriodInSeconds > maxRefreshTokenReuseGracePeriodInSeconds {
+		return badRequest("The refresh token reuse grace period is out of range. Use a value between 0 and 300 seconds.")
+	}
+
+	return nil
+}
+
+// getPermissionsByIds loads the permissions with the given ids, failing if any of them does not exist.
+func getPermissionsByIds(database database.Database, permissionIds []int64) ([]models.Permission, error) {
+	if len(permissionIds) == 0 {
+		return []models.Permission{}, nil
+	}
+
+	permissions, err := database.GetPermissionsByIds(nil, permissionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, permissionId := range permissionIds {
+		if !slices.ContainsFunc(permissions, func(p models.Permission) bool { return p.Id == permissionId }) {
+			return nil, badRequest("Permission not found.")
+		}
+	}
+
+	if err = database.PermissionsLoadResources(nil, permissions); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,268 @@
+package apihandlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAPIRequest(method string, target string, body string, urlParams map[string]string) *http.Request {
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = strings.NewReader(body)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	for key, value := range urlParams {
+		rctx.URLParams.Add(key, value)
+	}
+
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, constants.ContextKeySettings, &models.Settings{
+		AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+	})
+	ctx = context.WithValue(ctx, constants.ContextKeyBearerToken, oauth.Jwt{
+		Claims: map[string]interface{}{"sub": "automation-client"},
+	})
+	return req.WithContext(ctx)
+}
+
+func isErrorWithStatus(status int) interface{} {
+	return mock.MatchedBy(func(err error) bool {
+		errDetail, ok := err.(*customerrors.ErrorDetail)
+		return ok && errDetail.GetHttpStatusCode() == status
+	})
+}
+
+func TestHandleAPIClientPost_CreatesClientWithSecret(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	identifierValidator := validatorsMocks.NewIdentifierValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	var createdClient *models.Client
+	identifierValidator.On("ValidateIdentifier", "my-client", true).Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "my-client").Return(nil, nil)
+	database.On("CreateClient", mock.Anything, mock.MatchedBy(func(c *models.Client) bool {
+		return c.ClientIdentifier == "my-client" && c.Enabled && c.ClientCredentialsEnabled &&
+			!c.AuthorizationCodeEnabled && c.DefaultAcrLevel == enums.AcrLevel2Optional && len(c.ClientSecretEncrypted) > 0
+	})).Run(func(args mock.Arguments) {
+		createdClient = args.Get(1).(*models.Client)
+		createdClient.Id = 7
+	}).Return(nil)
+	auditLogger.On("Log", constants.AuditCreatedClient, map[string]interface{}{
+		"clientId":         int64(7),
+		"clientIdentifier": "my-client",
+		"loggedInUser":     "automation-client",
+	}).Return()
+
+	var resp ClientResponse
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("apihandlers.ClientResponse")).Run(func(args mock.Arguments) {
+		resp = args.Get(2).(ClientResponse)
+	}).Return()
+
+	handler := HandleAPIClientPost(httpHelper, database, identifierValidator, auditLogger)
+	req := newAPIRequest("POST", "/api/v1/clients", `{"clientIdentifier":" my-client ","clientCredentialsEnabled":true}`, nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, int64(7), resp.Id)
+	assert.Len(t, resp.ClientSecret, 60)
+
+	// the secret in the response must match the encrypted one stored in the database
+	decrypted, err := encryption.DecryptText(createdClient.ClientSecretEncrypted, []byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+	assert.Equal(t, resp.ClientSecret, decrypted)
+}
+
+func TestHandleAPIClientPost_DuplicateIdentifier(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	identifierValidator := validatorsMocks.NewIdentifierValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	identifierValidator.On("ValidateIdentifier", "my-client", true).Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "my-client").Return(&models.Client{Id: 3}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIClientPost(httpHelper, database, identifierValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/clients", `{"clientIdentifier":"my-client"}`, nil))
+
+	database.AssertNotCalled(t, "CreateClient", mock.Anything, mock.Anything)
+	auditLogger.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIClientPost_InvalidIdentifier(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	identifierValidator := validatorsMocks.NewIdentifierValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	identifierValidator.On("ValidateIdentifier", "1abc", true).Return(customerrors.NewErrorDetail("", "Invalid identifier format."))
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		errDetail, ok := err.(*customerrors.ErrorDetail)
+		return ok && errDetail.GetHttpStatusCode() == http.StatusBadRequest && errDetail.GetDescription() == "Invalid identifier format."
+	})).Return()
+
+	handler := HandleAPIClientPost(httpHelper, database, identifierValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/clients", `{"clientIdentifier":"1abc"}`, nil))
+
+	database.AssertNotCalled(t, "CreateClient", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIClientPost_UnknownField(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	identifierValidator := validatorsMocks.NewIdentifierValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIClientPost(httpHelper, database, identifierValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/clients", `{"clientId":"my-client"}`, nil))
+
+	identifierValidator.AssertNotCalled(t, "ValidateIdentifier", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIClientGet_NotFound(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+
+	database.On("GetClientById", mock.Anything, int64(42)).Return(nil, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusNotFound)).Return()
+
+	handler := HandleAPIClientGet(httpHelper, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("GET", "/api/v1/clients/42", "", map[string]string{"clientId": "42"}))
+}
+
+func TestHandleAPIClientDelete_SystemLevelClient(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientById", mock.Anything, int64(1)).Return(&models.Client{
+		Id:               1,
+		ClientIdentifier: constants.AdminConsoleClientIdentifier,
+	}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIClientDelete(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("DELETE", "/api/v1/clients/1", "", map[string]string{"clientId": "1"}))
+
+	database.AssertNotCalled(t, "DeleteClient", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIClientDelete(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientById", mock.Anything, int64(5)).Return(&models.Client{Id: 5, ClientIdentifier: "my-client"}, nil)
+	database.On("DeleteClient", mock.Anything, int64(5)).Return(nil)
+	auditLogger.On("Log", constants.AuditDeletedClient, mock.Anything).Return()
+
+	handler := HandleAPIClientDelete(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("DELETE", "/api/v1/clients/5", "", map[string]string{"clientId": "5"}))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestHandleAPIClientRedirectURIsPut(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	client := &models.Client{Id: 5, ClientIdentifier: "my-client"}
+	database.On("GetClientById", mock.Anything, int64(5)).Return(client, nil)
+	database.On("ClientLoadRedirectURIs", mock.Anything, client).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).RedirectURIs = []models.RedirectURI{
+			{Id: 10, URI: "https://keep.example.com/callback"},
+			{Id: 11, URI: "https://remove.example.com/callback"},
+		}
+	}).Return(nil)
+	database.On("BeginTransaction").Return(nil, nil)
+	database.On("RollbackTransaction", mock.Anything).Return(nil)
+	database.On("DeleteRedirectURI", mock.Anything, int64(11)).Return(nil)
+	database.On("CreateRedirectURI", mock.Anything, mock.MatchedBy(func(uri *models.RedirectURI) bool {
+		return uri.ClientId == 5 && uri.URI == "https://new.example.com/callback"
+	})).Return(nil)
+	database.On("CommitTransaction", mock.Anything).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedRedirectURIs, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, UpdateRedirectURIsRequest{
+		RedirectURIs: []string{"https://keep.example.com/callback", "https://new.example.com/callback"},
+	}).Return()
+
+	handler := HandleAPIClientRedirectURIsPut(httpHelper, database, auditLogger)
+	body := `{"redirectURIs":["https://keep.example.com/callback","https://new.example.com/callback","https://new.example.com/callback"]}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/clients/5/redirect-uris", body, map[string]string{"clientId": "5"}))
+
+	database.AssertNumberOfCalls(t, "CreateRedirectURI", 1)
+}
+
+func TestHandleAPIClientRedirectURIsPut_InvalidURI(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientById", mock.Anything, int64(5)).Return(&models.Client{Id: 5, ClientIdentifier: "my-client"}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIClientRedirectURIsPut(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/clients/5/redirect-uris", `{"redirectURIs":["not a uri"]}`, map[string]string{"clientId": "5"}))
+
+	database.AssertNotCalled(t, "BeginTransaction")
+}
+
+func TestHandleAPIClientRedirectURIsPut_PairwiseClientOutsideSector(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	client := &models.Client{
+		Id:               5,
+		ClientIdentifier: "my-client",
+		SubjectType:      constants.SubjectTypePairwise,
+		SectorIdentifier: "app.example.com",
+	}
+	database.On("GetClientById", mock.Anything, int64(5)).Return(client, nil)
+	database.On("ClientLoadRedirectURIs", mock.Anything, client).Return(nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		errDetail, ok := err.(*customerrors.ErrorDetail)
+		return ok && errDetail.GetDescription() == "The redirect URIs of a pairwise client must stay within its sector: app.example.com."
+	})).Return()
+
+	handler := HandleAPIClientRedirectURIsPut(httpHelper, database, auditLogger)
+	body := `{"redirectURIs":["https://other.example.com/callback"]}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/clients/5/redirect-uris", body, map[string]string{"clientId": "5"}))
+
+	database.AssertNotCalled(t, "BeginTransaction")
+}
@@ -0,0 +1,300 @@
+package apihandlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+type GroupRequest struct {
+	GroupIdentifier      string `json:"groupIdentifier"`
+	Description          string `json:"description"`
+	IncludeInIdToken     bool   `json:"includeInIdToken"`
+	IncludeInAccessToken bool   `json:"includeInAccessToken"`
+}
+
+func HandleAPIGroupsGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groups, err := database.GetAllGroups(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resp := make([]GroupResponse, 0, len(groups))
+		for idx := range groups {
+			resp = append(resp, newGroupResponse(&groups[idx]))
+		}
+
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleAPIGroupPost(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input GroupRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.GroupIdentifier = strings.TrimSpace(input.GroupIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err := validateGroupInput(database, identifierValidator, 0, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		group := &models.Group{
+			GroupIdentifier:      input.GroupIdentifier,
+			Description:          input.Description,
+			IncludeInIdToken:     input.IncludeInIdToken,
+			IncludeInAccessToken: input.IncludeInAccessToken,
+		}
+		if err := database.CreateGroup(nil, group); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedGroup, map[string]interface{}{
+			"groupId":         group.Id,
+			"groupIdentifier": group.GroupIdentifier,
+			"loggedInUser":    getLoggedInSubject(r),
+		})
+
+		encodeJsonCreated(w, r, httpHelper, newGroupResponse(group))
+	}
+}
+
+func HandleAPIGroupGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := getGroupFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.GroupLoadPermissions(nil, group); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.PermissionsLoadResources(nil, group.Permissions); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newGroupResponse(group))
+	}
+}
+
+func HandleAPIGroupPut(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := getGroupFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input GroupRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.GroupIdentifier = strings.TrimSpace(input.GroupIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err = validateGroupInput(database, identifierValidator, group.Id, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		group.GroupIdentifier = input.GroupIdentifier
+		group.Description = input.Description
+		group.IncludeInIdToken = input.IncludeInIdToken
+		group.IncludeInAccessToken = input.IncludeInAccessToken
+		if err = database.UpdateGroup(nil, group); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedGroup, map[string]interface{}{
+			"groupId":         group.Id,
+			"groupIdentifier": group.GroupIdentifier,
+			"loggedInUser":    getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newGroupResponse(group))
+	}
+}
+
+func HandleAPIGroupDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := getGroupFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.DeleteGroup(nil, group.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditDeletedGroup, map[string]interface{}{
+			"groupId":         group.Id,
+			"groupIdentifier": group.GroupIdentifier,
+			"loggedInUser":    getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandleAPIGroupMembersGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := getGroupFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		page, pageSize := getPageParams(r)
+		users, total, err := database.GetGroupMembersPaginated(nil, group.Id, page, pageSize)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, PagedResponse{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+			Items:    newUserResponses(users),
+		})
+	}
+}
+
+func HandleAPIGroupPermissionsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		group, err := getGroupFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input UpdatePermissionIdsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permissions, err := getPermissionsByIds(database, input.PermissionIds)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		groupPermissions, err := database.GetGroupPermissionsByGroupId(nil, group.Id)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		var added, removed []int64
+		existing := make([]int64, 0, len(groupPermissions))
+		for _, groupPermission := range groupPermissions {
+			existing = append(existing, groupPermission.PermissionId)
+			if !slices.Contains(input.PermissionIds, groupPermission.PermissionId) {
+				if err = database.DeleteGroupPermission(tx, groupPermission.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+				removed = append(removed, groupPermission.PermissionId)
+			}
+		}
+
+		for _, permission := range permissions {
+			if !slices.Contains(existing, permission.Id) {
+				if err = database.CreateGroupPermission(tx, &models.GroupPermission{GroupId: group.Id, PermissionId: permission.Id}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+				added = append(added, permission.Id)
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		for _, permissionId := range added {
+			auditLogger.Log(constants.AuditAddedGroupPermission, map[string]interface{}{
+				"groupId":      group.Id,
+				"permissionId": permissionId,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		for _, permissionId := range removed {
+			auditLogger.Log(constants.AuditDeletedGroupPermission, map[string]interface{}{
+				"groupId":      group.Id,
+				"permissionId": permissionId,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		httpHelper.EncodeJson(w, r, newPermissionResponses(permissions))
+	}
+}
+
+func getGroupFromUrlParam(r *http.Request, database database.Database) (*models.Group, error) {
+	groupId, err := getIdFromUrlParam(r, "groupId")
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := database.GetGroupById(nil, groupId)
+	if err != nil {
+		return nil, err
+	} else if group == nil {
+		return nil, notFound("Group not found.")
+	}
+
+	return group, nil
+}
+
+func validateGroupInput(database database.Database, identifierValidator IdentifierValidator, groupId int64, input *GroupRequest) error {
+	if len(input.GroupIdentifier) == 0 {
+		return badRequest("Group identifier is required.")
+	}
+
+	if err := identifierValidator.ValidateIdentifier(input.GroupIdentifier, true); err != nil {
+		return toValidationError(err)
+	}
+
+	if len(input.Description) > maxDescriptionLength {
+		return badRequest("The description cannot exceed a maximum length of 100 characters.")
+	}
+
+	existingGroup, err := database.GetGroupByGroupIdentifier(nil, input.GroupIdentifier)
+	if err != nil {
+		return err
+	} else if existingGroup != nil && existingGroup.Id != groupId {
+		return badRequest("The group identifier is already in use.")
+	}
+
+	return nil
+}
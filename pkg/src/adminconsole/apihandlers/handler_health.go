@@ -0,0 +1,20 @@
+package apihandlers
+
+import (
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/database"
+)
+
+func HandleHealthCheckGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := database.IsEmpty(); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, map[string]string{
+			"status": "healthy",
+		})
+	}
+}
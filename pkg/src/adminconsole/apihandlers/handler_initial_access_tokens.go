@@ -0,0 +1,115 @@
+package apihandlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/stringutil"
+)
+
+type CreateInitialAccessTokenRequest struct {
+	Description      string `json:"description"`
+	ExpiresInSeconds int    `json:"expiresInSeconds"`
+}
+
+func HandleAPIInitialAccessTokensGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initialAccessTokens, err := database.GetAllInitialAccessTokens(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resp := make([]InitialAccessTokenResponse, 0, len(initialAccessTokens))
+		for idx := range initialAccessTokens {
+			resp = append(resp, newInitialAccessTokenResponse(&initialAccessTokens[idx]))
+		}
+
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleAPIInitialAccessTokenPost(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input CreateInitialAccessTokenRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.Description = strings.TrimSpace(input.Description)
+		if len(input.Description) > maxDescriptionLength {
+			httpHelper.JsonError(w, r, badRequest("The description cannot exceed a maximum length of 100 characters."))
+			return
+		}
+
+		if input.ExpiresInSeconds <= 0 || input.ExpiresInSeconds > maxLifetimeInSeconds {
+			httpHelper.JsonError(w, r, badRequest("The expiration is out of range."))
+			return
+		}
+
+		token := stringutil.GenerateSecurityRandomString(64)
+		tokenHash, err := hashutil.HashString(token)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		initialAccessToken := &models.InitialAccessToken{
+			TokenHash:   tokenHash,
+			Description: input.Description,
+			ExpiresAt:   sql.NullTime{Time: time.Now().UTC().Add(time.Duration(input.ExpiresInSeconds) * time.Second), Valid: true},
+		}
+		if err = database.CreateInitialAccessToken(nil, initialAccessToken); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedInitialAccessToken, map[string]interface{}{
+			"initialAccessTokenId": initialAccessToken.Id,
+			"loggedInUser":         getLoggedInSubject(r),
+		})
+
+		// only the hash is stored, so the token is returned once, when it is created
+		resp := newInitialAccessTokenResponse(initialAccessToken)
+		resp.Token = token
+		encodeJsonCreated(w, r, httpHelper, resp)
+	}
+}
+
+func HandleAPIInitialAccessTokenDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initialAccessTokenId, err := getIdFromUrlParam(r, "initialAccessTokenId")
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		initialAccessToken, err := database.GetInitialAccessTokenById(nil, initialAccessTokenId)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if initialAccessToken == nil {
+			httpHelper.JsonError(w, r, notFound("Initial access token not found."))
+			return
+		}
+
+		if err = database.DeleteInitialAccessToken(nil, initialAccessToken.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditDeletedInitialAccessToken, map[string]interface{}{
+			"initialAccessTokenId": initialAccessToken.Id,
+			"loggedInUser":         getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
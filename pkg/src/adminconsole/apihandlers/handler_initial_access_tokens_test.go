@@ -0,0 +1,81 @@
+package apihandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleAPIInitialAccessTokenPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	var createdToken *models.InitialAccessToken
+	database.On("CreateInitialAccessToken", mock.Anything, mock.MatchedBy(func(iat *models.InitialAccessToken) bool {
+		return iat.Description == "partner onboarding" && iat.ExpiresAt.Valid &&
+			iat.ExpiresAt.Time.After(time.Now().UTC().Add(59*time.Minute))
+	})).Run(func(args mock.Arguments) {
+		createdToken = args.Get(1).(*models.InitialAccessToken)
+		createdToken.Id = 4
+	}).Return(nil)
+	auditLogger.On("Log", constants.AuditCreatedInitialAccessToken, map[string]interface{}{
+		"initialAccessTokenId": int64(4),
+		"loggedInUser":         "automation-client",
+	}).Return()
+
+	var resp InitialAccessTokenResponse
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("apihandlers.InitialAccessTokenResponse")).Run(func(args mock.Arguments) {
+		resp = args.Get(2).(InitialAccessTokenResponse)
+	}).Return()
+
+	handler := HandleAPIInitialAccessTokenPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/initial-access-tokens", `{"description":" partner onboarding ","expiresInSeconds":3600}`, nil))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, int64(4), resp.Id)
+	assert.NotEmpty(t, resp.Token)
+
+	// only the hash of the returned token is stored
+	assert.True(t, hashutil.VerifyStringHash(createdToken.TokenHash, resp.Token))
+}
+
+func TestHandleAPIInitialAccessTokenPost_InvalidExpiration(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIInitialAccessTokenPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/initial-access-tokens", `{"description":"test","expiresInSeconds":0}`, nil))
+
+	database.AssertNotCalled(t, "CreateInitialAccessToken", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIInitialAccessTokenDelete_NotFound(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetInitialAccessTokenById", mock.Anything, int64(9)).Return(nil, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusNotFound)).Return()
+
+	handler := HandleAPIInitialAccessTokenDelete(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("DELETE", "/api/v1/initial-access-tokens/9", "", map[string]string{"initialAccessTokenId": "9"}))
+
+	database.AssertNotCalled(t, "DeleteInitialAccessToken", mock.Anything, mock.Anything)
+}
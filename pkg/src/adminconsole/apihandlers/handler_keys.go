@@ -0,0 +1,101 @@
+package apihandlers
+
+import (
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func HandleAPIKeysGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keyPairs, err := database.GetAllSigningKeys(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newKeyResponses(keyPairs))
+	}
+}
+
+func HandleAPIKeysRotatePost(httpHelper HttpHelper, database database.Database, keyRotator KeyRotator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		rotated, err := keyRotator.Rotate(settings.SigningKeyAlgorithm)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if !rotated {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("conflict",
+				"The signing keys were rotated by another request. Please reload the keys and try again.", http.StatusConflict))
+			return
+		}
+
+		auditLogger.Log(constants.AuditRotatedKeys, map[string]interface{}{
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		keyPairs, err := database.GetAllSigningKeys(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newKeyResponses(keyPairs))
+	}
+}
+
+func HandleAPIKeyDelete(httpHelper HttpHelper, database database.Database, keyRotator KeyRotator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keyPairId, err := getIdFromUrlParam(r, "keyId")
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		keyPair, err := database.GetKeyPairById(nil, keyPairId)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if keyPair == nil {
+			httpHelper.JsonError(w, r, notFound("The key was not found."))
+			return
+		}
+
+		if keyPair.State == enums.KeyStateCurrent.String() {
+			httpHelper.JsonError(w, r, badRequest("The current key cannot be revoked. Rotate the keys first."))
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		if revoked, err := keyRotator.RevokeKey(keyPair, settings.SigningKeyAlgorithm); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if !revoked {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("conflict",
+				"The key was changed by another request. Please reload the keys and try again.", http.StatusConflict))
+			return
+		}
+
+		auditLogger.Log(constants.AuditRevokedKey, map[string]interface{}{
+			"keyId":         keyPair.Id,
+			"keyIdentifier": keyPair.KeyIdentifier,
+			"state":         keyPair.State,
+			"loggedInUser":  getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newKeyResponses(keyPairs []models.KeyPair) []KeyResponse {
+	resp := make([]KeyResponse, 0, len(keyPairs))
+	for idx := range keyPairs {
+		resp = append(resp, newKeyResponse(&keyPairs[idx]))
+	}
+	return resp
+}
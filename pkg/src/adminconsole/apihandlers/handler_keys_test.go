@@ -0,0 +1,107 @@
+package apihandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	keyRotationMocks "github.com/pchchv/aas/pkg/src/keyrotation/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleAPIKeysRotatePost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	keyRotator.On("Rotate", mock.Anything).Return(true, nil)
+	auditLogger.On("Log", constants.AuditRotatedKeys, mock.Anything).Return()
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		{Id: 1, State: enums.KeyStatePrevious.String()},
+		{Id: 2, State: enums.KeyStateCurrent.String()},
+		{Id: 3, State: enums.KeyStateNext.String()},
+	}, nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp []KeyResponse) bool {
+		return len(resp) == 3
+	})).Return()
+
+	handler := HandleAPIKeysRotatePost(httpHelper, database, keyRotator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/keys/rotate", "", nil))
+}
+
+func TestHandleAPIKeysRotatePost_RotatedConcurrently(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	keyRotator.On("Rotate", mock.Anything).Return(false, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusConflict)).Return()
+
+	handler := HandleAPIKeysRotatePost(httpHelper, database, keyRotator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/keys/rotate", "", nil))
+
+	auditLogger.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIKeyDelete(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	keyPair := &models.KeyPair{Id: 1, KeyIdentifier: "old-key", State: enums.KeyStatePrevious.String()}
+	database.On("GetKeyPairById", mock.Anything, int64(1)).Return(keyPair, nil)
+	keyRotator.On("RevokeKey", keyPair, mock.Anything).Return(true, nil)
+	auditLogger.On("Log", constants.AuditRevokedKey, mock.Anything).Return()
+
+	handler := HandleAPIKeyDelete(httpHelper, database, keyRotator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("DELETE", "/api/v1/keys/1", "", map[string]string{"keyId": "1"}))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestHandleAPIKeyDelete_ChangedConcurrently(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	keyPair := &models.KeyPair{Id: 3, KeyIdentifier: "next-key", State: enums.KeyStateNext.String()}
+	database.On("GetKeyPairById", mock.Anything, int64(3)).Return(keyPair, nil)
+	keyRotator.On("RevokeKey", keyPair, mock.Anything).Return(false, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusConflict)).Return()
+
+	handler := HandleAPIKeyDelete(httpHelper, database, keyRotator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("DELETE", "/api/v1/keys/3", "", map[string]string{"keyId": "3"}))
+
+	auditLogger.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPIKeyDelete_CurrentKey(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetKeyPairById", mock.Anything, int64(2)).Return(&models.KeyPair{Id: 2, State: enums.KeyStateCurrent.String()}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIKeyDelete(httpHelper, database, keyRotator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("DELETE", "/api/v1/keys/2", "", map[string]string{"keyId": "2"}))
+
+	keyRotator.AssertNotCalled(t, "RevokeKey", mock.Anything, mock.Anything)
+}
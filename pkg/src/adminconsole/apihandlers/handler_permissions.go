@@ -0,0 +1,202 @@
+package apihandlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+type CreatePermissionRequest struct {
+	ResourceId           int64  `json:"resourceId"`
+	PermissionIdentifier string `json:"permissionIdentifier"`
+	Description          string `json:"description"`
+}
+
+type UpdatePermissionRequest struct {
+	PermissionIdentifier string `json:"permissionIdentifier"`
+	Description          string `json:"description"`
+}
+
+func HandleAPIPermissionPost(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input CreatePermissionRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resource, err := database.GetResourceById(nil, input.ResourceId)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if resource == nil {
+			httpHelper.JsonError(w, r, badRequest("Resource not found."))
+			return
+		} else if resource.IsSystemLevelResource() {
+			httpHelper.JsonError(w, r, badRequest("Permissions of system level resources cannot be modified."))
+			return
+		}
+
+		input.PermissionIdentifier = strings.TrimSpace(input.PermissionIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err = validatePermissionInput(database, identifierValidator, resource.Id, 0, input.PermissionIdentifier, input.Description); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permission := &models.Permission{
+			ResourceId:           resource.Id,
+			PermissionIdentifier: input.PermissionIdentifier,
+			Description:          input.Description,
+			Resource:             *resource,
+		}
+		if err = database.CreatePermission(nil, permission); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedResourcePermissions, map[string]interface{}{
+			"resourceId":   resource.Id,
+			"permissionId": permission.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		encodeJsonCreated(w, r, httpHelper, newPermissionResponse(permission))
+	}
+}
+
+func HandleAPIPermissionGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		permission, err := getPermissionFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newPermissionResponse(permission))
+	}
+}
+
+func HandleAPIPermissionPut(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		permission, err := getPermissionFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if permission.Resource.IsSystemLevelResource() {
+			httpHelper.JsonError(w, r, badRequest("Permissions of system level resources cannot be modified."))
+			return
+		}
+
+		var input UpdatePermissionRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.PermissionIdentifier = strings.TrimSpace(input.PermissionIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err = validatePermissionInput(database, identifierValidator, permission.ResourceId, permission.Id, input.PermissionIdentifier, input.Description); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permission.PermissionIdentifier = input.PermissionIdentifier
+		permission.Description = input.Description
+		if err = database.UpdatePermission(nil, permission); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedResourcePermissions, map[string]interface{}{
+			"resourceId":   permission.ResourceId,
+			"permissionId": permission.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newPermissionResponse(permission))
+	}
+}
+
+func HandleAPIPermissionDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		permission, err := getPermissionFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if permission.Resource.IsSystemLevelResource() {
+			httpHelper.JsonError(w, r, badRequest("Permissions of system level resources cannot be deleted."))
+			return
+		}
+
+		if err = database.DeletePermission(nil, permission.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedResourcePermissions, map[string]interface{}{
+			"resourceId":          permission.ResourceId,
+			"deletedPermissionId": permission.Id,
+			"loggedInUser":        getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getPermissionFromUrlParam(r *http.Request, database database.Database) (*models.Permission, error) {
+	permissionId, err := getIdFromUrlParam(r, "permissionId")
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := database.GetPermissionById(nil, permissionId)
+	if err != nil {
+		return nil, err
+	} else if permission == nil {
+		return nil, notFound("Permission not found.")
+	}
+
+	resource, err := database.GetResourceById(nil, permission.ResourceId)
+	if err != nil {
+		return nil, err
+	} else if resource != nil {
+		permission.Resource = *resource
+	}
+
+	return permission, nil
+}
+
+func validatePermissionInput(database database.Database, identifierValidator IdentifierValidator, resourceId int64, permissionId int64, permissionIdentifier string, description string) error {
+	if len(permissionIdentifier) == 0 {
+		return badRequest("Permission identifier is required.")
+	}
+
+	if err := identifierValidator.ValidateIdentifier(permissionIdentifier, true); err != nil {
+		return toValidationError(err)
+	}
+
+	if len(description) > maxDescriptionLength {
+		return badRequest("The description cannot exceed a maximum length of 100 characters.")
+	}
+
+	permissions, err := database.GetPermissionsByResourceId(nil, resourceId)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if permission.PermissionIdentifier == permissionIdentifier && permission.Id != permissionId {
+			return badRequest("The permission identifier is already in use for this resource.")
+		}
+	}
+
+	return nil
+}
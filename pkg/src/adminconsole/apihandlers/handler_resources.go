@@ -0,0 +1,210 @@
+package apihandlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+type ResourceRequest struct {
+	ResourceIdentifier string `json:"resourceIdentifier"`
+	Description        string `json:"description"`
+}
+
+func HandleAPIResourcesGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resources, err := database.GetAllResources(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resp := make([]ResourceResponse, 0, len(resources))
+		for idx := range resources {
+			resp = append(resp, newResourceResponse(&resources[idx]))
+		}
+
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleAPIResourcePost(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input ResourceRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.ResourceIdentifier = strings.TrimSpace(input.ResourceIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err := validateResourceInput(database, identifierValidator, 0, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resource := &models.Resource{
+			ResourceIdentifier: input.ResourceIdentifier,
+			Description:        input.Description,
+		}
+		if err := database.CreateResource(nil, resource); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedResource, map[string]interface{}{
+			"resourceId":         resource.Id,
+			"resourceIdentifier": resource.ResourceIdentifier,
+			"loggedInUser":       getLoggedInSubject(r),
+		})
+
+		encodeJsonCreated(w, r, httpHelper, newResourceResponse(resource))
+	}
+}
+
+func HandleAPIResourceGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resource, err := getResourceFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newResourceResponse(resource))
+	}
+}
+
+func HandleAPIResourcePut(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resource, err := getResourceFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if resource.IsSystemLevelResource() {
+			httpHelper.JsonError(w, r, badRequest("System level resources cannot be modified."))
+			return
+		}
+
+		var input ResourceRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.ResourceIdentifier = strings.TrimSpace(input.ResourceIdentifier)
+		input.Description = strings.TrimSpace(input.Description)
+		if err = validateResourceInput(database, identifierValidator, resource.Id, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		resource.ResourceIdentifier = input.ResourceIdentifier
+		resource.Description = input.Description
+		if err = database.UpdateResource(nil, resource); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedResource, map[string]interface{}{
+			"resourceId":         resource.Id,
+			"resourceIdentifier": resource.ResourceIdentifier,
+			"loggedInUser":       getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newResourceResponse(resource))
+	}
+}
+
+func HandleAPIResourceDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resource, err := getResourceFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if resource.IsSystemLevelResource() {
+			httpHelper.JsonError(w, r, badRequest("System level resources cannot be deleted."))
+			return
+		}
+
+		if err = database.DeleteResource(nil, resource.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditDeletedResource, map[string]interface{}{
+			"resourceId":         resource.Id,
+			"resourceIdentifier": resource.ResourceIdentifier,
+			"loggedInUser":       getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandleAPIResourcePermissionsGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resource, err := getResourceFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permissions, err := database.GetPermissionsByResourceId(nil, resource.Id)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		for idx := range permissions {
+			permissions[idx].Resource = *resource
+		}
+
+		httpHelper.EncodeJson(w, r, newPermissionResponses(permissions))
+	}
+}
+
+func getResourceFromUrlParam(r *http.Request, database database.Database) (*models.Resource, error) {
+	resourceId, err := getIdFromUrlParam(r, "resourceId")
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := database.GetResourceById(nil, resourceId)
+	if err != nil {
+		return nil, err
+	} else if resource == nil {
+		return nil, notFound("Resource not found.")
+	}
+
+	return resource, nil
+}
+
+func validateResourceInput(database database.Database, identifierValidator IdentifierValidator, resourceId int64, input *ResourceRequest) error {
+	if len(input.ResourceIdentifier) == 0 {
+		return badRequest("Resource identifier is required.")
+	}
+
+	if err := identifierValidator.ValidateIdentifier(input.ResourceIdentifier, true); err != nil {
+		return toValidationError(err)
+	}
+
+	if len(input.Description) > maxDescriptionLength {
+		return badRequest("The description cannot exceed a maximum length of 100 characters.")
+	}
+
+	existingResource, err := database.GetResourceByResourceIdentifier(nil, input.ResourceIdentifier)
+	if err != nil {
+		return err
+	} else if existingResource != nil && existingResource.Id != resourceId {
+		return badRequest("The resource identifier is already in use.")
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package apihandlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+// minKeyRotationIntervalInSeconds keeps the next key published for at least a day,
+// so relying parties pick it up before it starts signing tokens
+const minKeyRotationIntervalInSeconds = 86400
+
+type UpdateGeneralSettingsRequest struct {
+	AppName                                   string `json:"appName"`
+	Issuer                                    string `json:"issuer"`
+	SelfRegistrationEnabled                   bool   `json:"selfRegistrationEnabled"`
+	SelfRegistrationRequiresEmailVerification bool   `json:"selfRegistrationRequiresEmailVerification"`
+	DynamicClientRegistrationEnabled          bool   `json:"dynamicClientRegistrationEnabled"`
+	DynamicClientRegistrationOpen             bool   `json:"dynamicClientRegistrationOpen"`
+	PasswordPolicy                            string `json:"passwordPolicy"`
+}
+
+type UpdateSessionsSettingsRequest struct {
+	UserSessionIdleTimeoutInSeconds int `json:"userSessionIdleTimeoutInSeconds"`
+	UserSessionMaxLifetimeInSeconds int `json:"userSessionMaxLifetimeInSeconds"`
+}
+
+type UpdateTokensSettingsRequest struct {
+	TokenExpirationInSeconds                int    `json:"tokenExpirationInSeconds"`
+	RefreshTokenOfflineIdleTimeoutInSeconds int    `json:"refreshTokenOfflineIdleTimeoutInSeconds"`
+	RefreshTokenOfflineMaxLifetimeInSeconds int    `json:"refreshTokenOfflineMaxLifetimeInSeconds"`
+	RefreshTokenReuseGracePeriodInSeconds   int    `json:"refreshTokenReuseGracePeriodInSeconds"`
+	IncludeOpenIDConnectClaimsInAccessToken bool   `json:"includeOpenIDConnectClaimsInAccessToken"`
+	SigningKeyAlgorithm                     string `json:"signingKeyAlgorithm,omitempty"`
+	KeyRotationIntervalInSeconds            int    `json:"keyRotationIntervalInSeconds"`
+}
+
+func HandleAPISettingsGet(httpHelper HttpHelper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		httpHelper.EncodeJson(w, r, newSettingsResponse(settings))
+	}
+}
+
+func HandleAPISettingsGeneralPut(httpHelper HttpHelper, database database.Database, identifierValidator IdentifierValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input UpdateGeneralSettingsRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.AppName = strings.TrimSpace(input.AppName)
+		input.Issuer = strings.TrimSpace(input.Issuer)
+		if len(input.AppName) > 30 {
+			httpHelper.JsonError(w, r, badRequest("The app name cannot exceed a maximum length of 30 characters."))
+			return
+		}
+
+		if len(input.Issuer) == 0 {
+			httpHelper.JsonError(w, r, badRequest("The issuer is required."))
+			return
+		} else if len(input.Issuer) > 60 {
+			httpHelper.JsonError(w, r, badRequest("The issuer cannot exceed a maximum length of 60 characters."))
+			return
+		}
+
+		// the issuer is either a URL or a plain identifier
+		if u, err := url.ParseRequestURI(input.Issuer); err != nil || len(u.Host) == 0 {
+			if err = identifierValidator.ValidateIdentifier(input.Issuer, false); err != nil {
+				httpHelper.JsonError(w, r, badRequest("Invalid issuer. Please enter a valid URI or identifier."))
+				return
+			}
+		}
+
+		passwordPolicy, err := enums.PasswordPolicyFromString(input.PasswordPolicy)
+		if err != nil {
+			httpHelper.JsonError(w, r, badRequest("The password policy is invalid. Use none, low, medium or high."))
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		settings.AppName = input.AppName
+		settings.Issuer = input.Issuer
+		settings.SelfRegistrationEnabled = input.SelfRegistrationEnabled
+		settings.SelfRegistrationRequiresEmailVerification = input.SelfRegistrationRequiresEmailVerification
+		settings.DynamicClientRegistrationEnabled = input.DynamicClientRegistrationEnabled
+		settings.DynamicClientRegistrationOpen = input.DynamicClientRegistrationOpen
+		settings.PasswordPolicy = passwordPolicy
+		if err = database.UpdateSettings(nil, settings); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedGeneralSettings, map[string]interface{}{
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newSettingsResponse(settings))
+	}
+}
+
+func HandleAPISettingsSessionsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input UpdateSessionsSettingsRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if input.UserSessionIdleTimeoutInSeconds <= 0 || input.UserSessionIdleTimeoutInSeconds > maxLifetimeInSeconds {
+			httpHelper.JsonError(w, r, badRequest("The user session idle timeout is out of range."))
+			return
+		}
+
+		if input.UserSessionMaxLifetimeInSeconds <= 0 || input.UserSessionMaxLifetimeInSeconds > maxLifetimeInSeconds {
+			httpHelper.JsonError(w, r, badRequest("The user session max lifetime is out of range."))
+			return
+		}
+
+		if input.UserSessionIdleTimeoutInSeconds > input.UserSessionMaxLifetimeInSeconds {
+			httpHelper.JsonError(w, r, badRequest("The user session idle timeout cannot be greater than the max lifetime."))
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		settings.UserSessionIdleTimeoutInSeconds = input.UserSessionIdleTimeoutInSeconds
+		settings.UserSessionMaxLifetimeInSeconds = input.UserSessionMaxLifetimeInSeconds
+		if err := database.UpdateSettings(nil, settings); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedSessionsSettings, map[string]interface{}{
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newSettingsResponse(settings))
+	}
+}
+
+func HandleAPISettingsTokensPut(httpHelper HttpHelper, database database.Database, keyRotator KeyRotator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input UpdateTokensSettingsRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err := validateTokenLifetimes(input.TokenExpirationInSeconds, input.RefreshTokenOfflineIdleTimeoutInSeconds, input.RefreshTokenOfflineMaxLifetimeInSeconds, false); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err := validateRefreshTokenReuseGracePeriod(input.RefreshTokenReuseGracePeriodInSeconds); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		// 0 disables the scheduled rotation
+		if input.KeyRotationIntervalInSeconds != 0 &&
+			(input.KeyRotationIntervalInSeconds < minKeyRotationIntervalInSeconds || input.KeyRotationIntervalInSeconds > maxLifetimeInSeconds) {
+			httpHelper.JsonError(w, r, badRequest("The key rotation interval is out of range. Use 0 to disable it or at least one day."))
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		if len(input.SigningKeyAlgorithm) > 0 && input.SigningKeyAlgorithm != settings.SigningKeyAlgorithm {
+			if !slices.Contains(keyutil.SupportedAlgorithms, input.SigningKeyAlgorithm) {
+				httpHelper.JsonError(w, r, badRequest("The signing key algorithm is invalid. Use "+strings.Join(keyutil.SupportedAlgorithms, ", ")+"."))
+				return
+			}
+
+			if replaced, err := keyRotator.ReplaceNextKey(input.SigningKeyAlgorithm); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			} else if !replaced {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("conflict",
+					"The signing keys were changed by another request. Please reload the settings and try again.", http.StatusConflict))
+				return
+			}
+			settings.SigningKeyAlgorithm = input.SigningKeyAlgorithm
+		}
+
+		settings.TokenExpirationInSeconds = input.TokenExpirationInSeconds
+		settings.RefreshTokenOfflineIdleTimeoutInSeconds = input.RefreshTokenOfflineIdleTimeoutInSeconds
+		settings.RefreshTokenOfflineMaxLifetimeInSeconds = input.RefreshTokenOfflineMaxLifetimeInSeconds
+		settings.RefreshTokenReuseGracePeriodInSeconds = input.RefreshTokenReuseGracePeriodInSeconds
+		settings.IncludeOpenIDConnectClaimsInAccessToken = input.IncludeOpenIDConnectClaimsInAccessToken
+		settings.KeyRotationIntervalInSeconds = input.KeyRotationIntervalInSeconds
+		if err := database.UpdateSettings(nil, settings); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedTokensSettings, map[string]interface{}{
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newSettingsResponse(settings))
+	}
+}
@@ -0,0 +1,165 @@
+package apihandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	keyRotationMocks "github.com/pchchv/aas/pkg/src/keyrotation/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleAPISettingsGeneralPut(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	identifierValidator := validatorsMocks.NewIdentifierValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(s *models.Settings) bool {
+		return s.AppName == "My app" && s.Issuer == "https://auth.example.com" &&
+			s.PasswordPolicy == enums.PasswordPolicyHigh && s.SelfRegistrationEnabled
+	})).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedGeneralSettings, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("apihandlers.SettingsResponse")).Return()
+
+	handler := HandleAPISettingsGeneralPut(httpHelper, database, identifierValidator, auditLogger)
+	body := `{"appName":"My app","issuer":"https://auth.example.com","selfRegistrationEnabled":true,"passwordPolicy":"high"}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/general", body, nil))
+
+	identifierValidator.AssertNotCalled(t, "ValidateIdentifier", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPISettingsGeneralPut_InvalidPasswordPolicy(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	identifierValidator := validatorsMocks.NewIdentifierValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	identifierValidator.On("ValidateIdentifier", "my-issuer", false).Return(nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPISettingsGeneralPut(httpHelper, database, identifierValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/general", `{"issuer":"my-issuer","passwordPolicy":"extreme"}`, nil))
+
+	database.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPISettingsSessionsPut_IdleGreaterThanMax(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPISettingsSessionsPut(httpHelper, database, auditLogger)
+	body := `{"userSessionIdleTimeoutInSeconds":7200,"userSessionMaxLifetimeInSeconds":3600}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/sessions", body, nil))
+
+	database.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPISettingsTokensPut(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(s *models.Settings) bool {
+		return s.TokenExpirationInSeconds == 300 && s.RefreshTokenOfflineIdleTimeoutInSeconds == 3600 &&
+			s.RefreshTokenOfflineMaxLifetimeInSeconds == 7200 && s.IncludeOpenIDConnectClaimsInAccessToken
+	})).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedTokensSettings, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("apihandlers.SettingsResponse")).Return()
+
+	handler := HandleAPISettingsTokensPut(httpHelper, database, keyRotator, auditLogger)
+	body := `{"tokenExpirationInSeconds":300,"refreshTokenOfflineIdleTimeoutInSeconds":3600,` +
+		`"refreshTokenOfflineMaxLifetimeInSeconds":7200,"includeOpenIDConnectClaimsInAccessToken":true}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/tokens", body, nil))
+}
+
+func TestHandleAPISettingsTokensPut_SigningKeyAlgorithm(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	keyRotator.On("ReplaceNextKey", "ES256").Return(true, nil)
+	database.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(s *models.Settings) bool {
+		return s.SigningKeyAlgorithm == "ES256"
+	})).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedTokensSettings, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("apihandlers.SettingsResponse")).Return()
+
+	handler := HandleAPISettingsTokensPut(httpHelper, database, keyRotator, auditLogger)
+	body := `{"tokenExpirationInSeconds":300,"refreshTokenOfflineIdleTimeoutInSeconds":3600,` +
+		`"refreshTokenOfflineMaxLifetimeInSeconds":7200,"signingKeyAlgorithm":"ES256"}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/tokens", body, nil))
+}
+
+func TestHandleAPISettingsTokensPut_InvalidSigningKeyAlgorithm(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPISettingsTokensPut(httpHelper, database, keyRotator, auditLogger)
+	body := `{"tokenExpirationInSeconds":300,"refreshTokenOfflineIdleTimeoutInSeconds":3600,` +
+		`"refreshTokenOfflineMaxLifetimeInSeconds":7200,"signingKeyAlgorithm":"HS256"}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/tokens", body, nil))
+
+	keyRotator.AssertNotCalled(t, "ReplaceNextKey", mock.Anything)
+	database.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+}
+
+func TestHandleAPISettingsTokensPut_KeyRotationInterval(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(s *models.Settings) bool {
+		return s.KeyRotationIntervalInSeconds == 7776000
+	})).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedTokensSettings, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp SettingsResponse) bool {
+		return resp.KeyRotationIntervalInSeconds == 7776000
+	})).Return()
+
+	handler := HandleAPISettingsTokensPut(httpHelper, database, keyRotator, auditLogger)
+	body := `{"tokenExpirationInSeconds":300,"refreshTokenOfflineIdleTimeoutInSeconds":3600,` +
+		`"refreshTokenOfflineMaxLifetimeInSeconds":7200,"keyRotationIntervalInSeconds":7776000}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/tokens", body, nil))
+}
+
+func TestHandleAPISettingsTokensPut_KeyRotationIntervalTooShort(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	keyRotator := keyRotationMocks.NewKeyRotator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPISettingsTokensPut(httpHelper, database, keyRotator, auditLogger)
+	body := `{"tokenExpirationInSeconds":300,"refreshTokenOfflineIdleTimeoutInSeconds":3600,` +
+		`"refreshTokenOfflineMaxLifetimeInSeconds":7200,"keyRotationIntervalInSeconds":3600}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/settings/tokens", body, nil))
+
+	database.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+}
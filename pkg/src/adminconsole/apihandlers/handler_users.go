@@ -0,0 +1,573 @@
+package apihandlers
+
+import (
+	"database/sql"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/user"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+type CreateUserRequest struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+	GivenName     string `json:"givenName"`
+	MiddleName    string `json:"middleName"`
+	FamilyName    string `json:"familyName"`
+	Password      string `json:"password"`
+}
+
+type UpdateUserRequest struct {
+	Enabled             bool   `json:"enabled"`
+	Username            string `json:"username"`
+	GivenName           string `json:"givenName"`
+	MiddleName          string `json:"middleName"`
+	FamilyName          string `json:"familyName"`
+	Nickname            string `json:"nickname"`
+	Website             string `json:"website"`
+	Gender              string `json:"gender"`
+	DateOfBirth         string `json:"dateOfBirth"`
+	ZoneInfoCountryName string `json:"zoneInfoCountryName"`
+	ZoneInfo            string `json:"zoneInfo"`
+	Locale              string `json:"locale"`
+}
+
+type UpdateUserEmailRequest struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+}
+
+type UpdateUserPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+type UpdateGroupIdsRequest struct {
+	GroupIds []int64 `json:"groupIds"`
+}
+
+func HandleAPIUsersGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, pageSize := getPageParams(r)
+		query := strings.TrimSpace(r.URL.Query().Get("query"))
+		users, total, err := database.SearchUsersPaginated(nil, query, page, pageSize)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, PagedResponse{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+			Items:    newUserResponses(users),
+		})
+	}
+}
+
+func HandleAPIUserPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	userCreator UserCreator,
+	emailValidator EmailValidator,
+	profileValidator ProfileValidator,
+	passwordValidator PasswordValidator,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input CreateUserRequest
+		if err := decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+		if len(input.Email) == 0 {
+			httpHelper.JsonError(w, r, badRequest("Please enter an email address."))
+			return
+		}
+
+		if err := emailValidator.ValidateEmail(input.Email); err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		if len(input.Email) > 60 {
+			httpHelper.JsonError(w, r, badRequest("The email address cannot exceed a maximum length of 60 characters."))
+			return
+		}
+
+		existingUser, err := database.GetUserByEmail(nil, input.Email)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if existingUser != nil {
+			httpHelper.JsonError(w, r, badRequest("The email address is already registered."))
+			return
+		}
+
+		names := []struct{ value, field string }{
+			{input.GivenName, "given name"},
+			{input.MiddleName, "middle name"},
+			{input.FamilyName, "family name"},
+		}
+		for _, name := range names {
+			if err = profileValidator.ValidateName(strings.TrimSpace(name.value), name.field); err != nil {
+				httpHelper.JsonError(w, r, toValidationError(err))
+				return
+			}
+		}
+
+		// a user without a password can only sign in after one is set
+		var passwordHash string
+		if len(input.Password) > 0 {
+			if err = passwordValidator.ValidatePassword(r.Context(), input.Password); err != nil {
+				httpHelper.JsonError(w, r, toValidationError(err))
+				return
+			}
+
+			if passwordHash, err = hashutil.HashPassword(input.Password); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+		}
+
+		createdUser, err := userCreator.CreateUser(&user.CreateUserInput{
+			Email:         input.Email,
+			EmailVerified: input.EmailVerified,
+			GivenName:     strings.TrimSpace(input.GivenName),
+			MiddleName:    strings.TrimSpace(input.MiddleName),
+			FamilyName:    strings.TrimSpace(input.FamilyName),
+			PasswordHash:  passwordHash,
+		})
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedUser, map[string]interface{}{
+			"userId":       createdUser.Id,
+			"email":        createdUser.Email,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		encodeJsonCreated(w, r, httpHelper, newUserResponse(createdUser))
+	}
+}
+
+func HandleAPIUserGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.UserLoadGroups(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.UserLoadPermissions(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.PermissionsLoadResources(nil, user.Permissions); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newUserResponse(user))
+	}
+}
+
+func HandleAPIUserPut(httpHelper HttpHelper, database database.Database, profileValidator ProfileValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input UpdateUserRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.Username = strings.TrimSpace(input.Username)
+		input.GivenName = strings.TrimSpace(input.GivenName)
+		input.MiddleName = strings.TrimSpace(input.MiddleName)
+		input.FamilyName = strings.TrimSpace(input.FamilyName)
+		input.Nickname = strings.TrimSpace(input.Nickname)
+		input.Website = strings.TrimSpace(input.Website)
+		input.DateOfBirth = strings.TrimSpace(input.DateOfBirth)
+		err = profileValidator.ValidateProfile(&validators.ValidateProfileInput{
+			Username:            input.Username,
+			GivenName:           input.GivenName,
+			MiddleName:          input.MiddleName,
+			FamilyName:          input.FamilyName,
+			Nickname:            input.Nickname,
+			Website:             input.Website,
+			Gender:              input.Gender,
+			Locale:              input.Locale,
+			DateOfBirth:         input.DateOfBirth,
+			Subject:             user.Subject.String(),
+			ZoneInfo:            input.ZoneInfo,
+			ZoneInfoCountryName: input.ZoneInfoCountryName,
+		})
+		if err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		enabledChanged := user.Enabled != input.Enabled
+		user.Enabled = input.Enabled
+		user.Username = input.Username
+		user.GivenName = input.GivenName
+		user.MiddleName = input.MiddleName
+		user.FamilyName = input.FamilyName
+		user.Nickname = input.Nickname
+		user.Website = input.Website
+		user.Gender = input.Gender
+		user.ZoneInfoCountryName = input.ZoneInfoCountryName
+		user.ZoneInfo = input.ZoneInfo
+		user.Locale = input.Locale
+		user.BirthDate = sql.NullTime{}
+		if len(input.DateOfBirth) > 0 {
+			// already validated by the profile validator
+			dateOfBirth, _ := time.Parse("2006-01-02", input.DateOfBirth)
+			user.BirthDate = sql.NullTime{Time: dateOfBirth, Valid: true}
+		}
+
+		if err = database.UpdateUser(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if enabledChanged {
+			auditLogger.Log(constants.AuditUpdatedUserDetails, map[string]interface{}{
+				"userId":       user.Id,
+				"enabled":      user.Enabled,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		auditLogger.Log(constants.AuditUpdatedUserProfile, map[string]interface{}{
+			"userId":       user.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newUserResponse(user))
+	}
+}
+
+func HandleAPIUserEmailPut(httpHelper HttpHelper, database database.Database, emailValidator EmailValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input UpdateUserEmailRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+		err = emailValidator.ValidateEmailUpdate(&validators.ValidateEmailInput{
+			Email:             input.Email,
+			EmailConfirmation: input.Email,
+			Subject:           user.Subject.String(),
+		})
+		if err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		user.Email = input.Email
+		user.EmailVerified = input.EmailVerified
+		user.EmailVerificationCodeEncrypted = nil
+		user.EmailVerificationCodeIssuedAt = sql.NullTime{}
+		if err = database.UpdateUser(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedUserEmail, map[string]interface{}{
+			"userId":       user.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		httpHelper.EncodeJson(w, r, newUserResponse(user))
+	}
+}
+
+func HandleAPIUserPasswordPut(httpHelper HttpHelper, database database.Database, passwordValidator PasswordValidator, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input UpdateUserPasswordRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = passwordValidator.ValidatePassword(r.Context(), input.Password); err != nil {
+			httpHelper.JsonError(w, r, toValidationError(err))
+			return
+		}
+
+		if user.PasswordHash, err = hashutil.HashPassword(input.Password); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		user.ForgotPasswordCodeEncrypted = nil
+		user.ForgotPasswordCodeIssuedAt = sql.NullTime{}
+		if err = database.UpdateUser(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedUserAuthentication, map[string]interface{}{
+			"userId":       user.Id,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandleAPIUserDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if user.Subject.String() == getLoggedInSubject(r) {
+			httpHelper.JsonError(w, r, badRequest("You cannot delete your own user."))
+			return
+		}
+
+		if err = database.DeleteUser(nil, user.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditDeletedUser, map[string]interface{}{
+			"userId":       user.Id,
+			"email":        user.Email,
+			"loggedInUser": getLoggedInSubject(r),
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandleAPIUserGroupsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input UpdateGroupIdsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		groups := []models.Group{}
+		if len(input.GroupIds) > 0 {
+			if groups, err = database.GetGroupsByIds(nil, input.GroupIds); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+		}
+
+		for _, groupId := range input.GroupIds {
+			if !slices.ContainsFunc(groups, func(g models.Group) bool { return g.Id == groupId }) {
+				httpHelper.JsonError(w, r, badRequest("Group not found."))
+				return
+			}
+		}
+
+		userGroups, err := database.GetUserGroupsByUserId(nil, user.Id)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		var added, removed []int64
+		existing := make([]int64, 0, len(userGroups))
+		for _, userGroup := range userGroups {
+			existing = append(existing, userGroup.GroupId)
+			if !slices.Contains(input.GroupIds, userGroup.GroupId) {
+				if err = database.DeleteUserGroup(tx, userGroup.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+				removed = append(removed, userGroup.GroupId)
+			}
+		}
+
+		for _, group := range groups {
+			if !slices.Contains(existing, group.Id) {
+				if err = database.CreateUserGroup(tx, &models.UserGroup{UserId: user.Id, GroupId: group.Id}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+				added = append(added, group.Id)
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		for _, groupId := range added {
+			auditLogger.Log(constants.AuditUserAddedToGroup, map[string]interface{}{
+				"userId":       user.Id,
+				"groupId":      groupId,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		for _, groupId := range removed {
+			auditLogger.Log(constants.AuditUserRemovedFromGroup, map[string]interface{}{
+				"userId":       user.Id,
+				"groupId":      groupId,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		resp := make([]GroupResponse, 0, len(groups))
+		for idx := range groups {
+			resp = append(resp, newGroupResponse(&groups[idx]))
+		}
+
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleAPIUserPermissionsPut(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := getUserFromUrlParam(r, database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input UpdatePermissionIdsRequest
+		if err = decodeJsonBody(r, &input); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permissions, err := getPermissionsByIds(database, input.PermissionIds)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		userPermissions, err := database.GetUserPermissionsByUserId(nil, user.Id)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		var added, removed []int64
+		existing := make([]int64, 0, len(userPermissions))
+		for _, userPermission := range userPermissions {
+			existing = append(existing, userPermission.PermissionId)
+			if !slices.Contains(input.PermissionIds, userPermission.PermissionId) {
+				if err = database.DeleteUserPermission(tx, userPermission.Id); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+				removed = append(removed, userPermission.PermissionId)
+			}
+		}
+
+		for _, permission := range permissions {
+			if !slices.Contains(existing, permission.Id) {
+				if err = database.CreateUserPermission(tx, &models.UserPermission{UserId: user.Id, PermissionId: permission.Id}); err != nil {
+					httpHelper.JsonError(w, r, err)
+					return
+				}
+				added = append(added, permission.Id)
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		for _, permissionId := range added {
+			auditLogger.Log(constants.AuditAddedUserPermission, map[string]interface{}{
+				"userId":       user.Id,
+				"permissionId": permissionId,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		for _, permissionId := range removed {
+			auditLogger.Log(constants.AuditDeletedUserPermission, map[string]interface{}{
+				"userId":       user.Id,
+				"permissionId": permissionId,
+				"loggedInUser": getLoggedInSubject(r),
+			})
+		}
+
+		httpHelper.EncodeJson(w, r, newPermissionResponses(permissions))
+	}
+}
+
+func getUserFromUrlParam(r *http.Request, database database.Database) (*models.User, error) {
+	userId, err := getIdFromUrlParam(r, "userId")
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := database.GetUserById(nil, userId)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, notFound("User not found.")
+	}
+
+	return user, nil
+}
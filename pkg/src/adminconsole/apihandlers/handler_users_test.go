@@ -0,0 +1,180 @@
+package apihandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/user"
+	mocksUser "github.com/pchchv/aas/pkg/src/user/mocks"
+	"github.com/pchchv/aas/pkg/src/validators"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleAPIUserPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	userCreator := mocksUser.NewUserCreator(t)
+	emailValidator := validatorsMocks.NewEmailValidator(t)
+	profileValidator := validatorsMocks.NewProfileValidator(t)
+	passwordValidator := validatorsMocks.NewPasswordValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	emailValidator.On("ValidateEmail", "jane@example.com").Return(nil)
+	database.On("GetUserByEmail", mock.Anything, "jane@example.com").Return(nil, nil)
+	profileValidator.On("ValidateName", mock.Anything, mock.Anything).Return(nil)
+	passwordValidator.On("ValidatePassword", mock.Anything, "Secr3t!pass").Return(nil)
+	userCreator.On("CreateUser", mock.MatchedBy(func(input *user.CreateUserInput) bool {
+		return input.Email == "jane@example.com" && input.GivenName == "Jane" && input.EmailVerified &&
+			hashutil.VerifyPasswordHash(input.PasswordHash, "Secr3t!pass")
+	})).Return(&models.User{Id: 9, Email: "jane@example.com", Subject: uuid.New()}, nil)
+	auditLogger.On("Log", constants.AuditCreatedUser, mock.MatchedBy(func(details map[string]interface{}) bool {
+		return details["userId"] == int64(9) && details["loggedInUser"] == "automation-client"
+	})).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp UserResponse) bool {
+		return resp.Id == 9 && resp.Email == "jane@example.com"
+	})).Return()
+
+	handler := HandleAPIUserPost(httpHelper, database, userCreator, emailValidator, profileValidator, passwordValidator, auditLogger)
+	body := `{"email":" Jane@Example.com ","emailVerified":true,"givenName":"Jane","password":"Secr3t!pass"}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/users", body, nil))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	profileValidator.AssertNumberOfCalls(t, "ValidateName", 3)
+}
+
+func TestHandleAPIUserPost_WeakPassword(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	userCreator := mocksUser.NewUserCreator(t)
+	emailValidator := validatorsMocks.NewEmailValidator(t)
+	profileValidator := validatorsMocks.NewProfileValidator(t)
+	passwordValidator := validatorsMocks.NewPasswordValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	emailValidator.On("ValidateEmail", "jane@example.com").Return(nil)
+	database.On("GetUserByEmail", mock.Anything, "jane@example.com").Return(nil, nil)
+	profileValidator.On("ValidateName", mock.Anything, mock.Anything).Return(nil)
+	passwordValidator.On("ValidatePassword", mock.Anything, "123").
+		Return(customerrors.NewErrorDetail("", "The minimum length for the password is 6 characters"))
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIUserPost(httpHelper, database, userCreator, emailValidator, profileValidator, passwordValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/users", `{"email":"jane@example.com","password":"123"}`, nil))
+
+	userCreator.AssertNotCalled(t, "CreateUser", mock.Anything)
+}
+
+func TestHandleAPIUserPost_EmailAlreadyRegistered(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	userCreator := mocksUser.NewUserCreator(t)
+	emailValidator := validatorsMocks.NewEmailValidator(t)
+	profileValidator := validatorsMocks.NewProfileValidator(t)
+	passwordValidator := validatorsMocks.NewPasswordValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	emailValidator.On("ValidateEmail", "jane@example.com").Return(nil)
+	database.On("GetUserByEmail", mock.Anything, "jane@example.com").Return(&models.User{Id: 1}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIUserPost(httpHelper, database, userCreator, emailValidator, profileValidator, passwordValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("POST", "/api/v1/users", `{"email":"jane@example.com"}`, nil))
+
+	userCreator.AssertNotCalled(t, "CreateUser", mock.Anything)
+}
+
+func TestHandleAPIUserPut_DisablesUser(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	profileValidator := validatorsMocks.NewProfileValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	subject := uuid.New()
+	database.On("GetUserById", mock.Anything, int64(9)).Return(&models.User{Id: 9, Enabled: true, Subject: subject}, nil)
+	profileValidator.On("ValidateProfile", mock.MatchedBy(func(input *validators.ValidateProfileInput) bool {
+		return input.Subject == subject.String() && input.GivenName == "Jane" && input.DateOfBirth == "1990-05-01"
+	})).Return(nil)
+	database.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+		return !u.Enabled && u.GivenName == "Jane" && u.GetDateOfBirthFormatted() == "1990-05-01"
+	})).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedUserDetails, mock.Anything).Return()
+	auditLogger.On("Log", constants.AuditUpdatedUserProfile, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("apihandlers.UserResponse")).Return()
+
+	handler := HandleAPIUserPut(httpHelper, database, profileValidator, auditLogger)
+	body := `{"enabled":false,"givenName":"Jane","dateOfBirth":"1990-05-01"}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/users/9", body, map[string]string{"userId": "9"}))
+}
+
+func TestHandleAPIUserGroupsPut(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetUserById", mock.Anything, int64(9)).Return(&models.User{Id: 9}, nil)
+	database.On("GetGroupsByIds", mock.Anything, []int64{2, 3}).Return([]models.Group{{Id: 2}, {Id: 3}}, nil)
+	database.On("GetUserGroupsByUserId", mock.Anything, int64(9)).Return([]models.UserGroup{
+		{Id: 100, UserId: 9, GroupId: 1},
+		{Id: 101, UserId: 9, GroupId: 2},
+	}, nil)
+	database.On("BeginTransaction").Return(nil, nil)
+	database.On("RollbackTransaction", mock.Anything).Return(nil)
+	database.On("DeleteUserGroup", mock.Anything, int64(100)).Return(nil)
+	database.On("CreateUserGroup", mock.Anything, &models.UserGroup{UserId: 9, GroupId: 3}).Return(nil)
+	database.On("CommitTransaction", mock.Anything).Return(nil)
+	auditLogger.On("Log", constants.AuditUserAddedToGroup, mock.MatchedBy(func(details map[string]interface{}) bool {
+		return details["groupId"] == int64(3)
+	})).Return()
+	auditLogger.On("Log", constants.AuditUserRemovedFromGroup, mock.MatchedBy(func(details map[string]interface{}) bool {
+		return details["groupId"] == int64(1)
+	})).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	handler := HandleAPIUserGroupsPut(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/users/9/groups", `{"groupIds":[2,3]}`, map[string]string{"userId": "9"}))
+}
+
+func TestHandleAPIUserGroupsPut_UnknownGroup(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetUserById", mock.Anything, int64(9)).Return(&models.User{Id: 9}, nil)
+	database.On("GetGroupsByIds", mock.Anything, []int64{2, 99}).Return([]models.Group{{Id: 2}}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIUserGroupsPut(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("PUT", "/api/v1/users/9/groups", `{"groupIds":[2,99]}`, map[string]string{"userId": "9"}))
+
+	database.AssertNotCalled(t, "BeginTransaction")
+}
+
+func TestHandleAPIUserGet_InvalidId(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithStatus(http.StatusBadRequest)).Return()
+
+	handler := HandleAPIUserGet(httpHelper, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newAPIRequest("GET", "/api/v1/users/abc", "", map[string]string{"userId": "abc"}))
+
+	database.AssertNotCalled(t, "GetUserById", mock.Anything, mock.Anything)
+}
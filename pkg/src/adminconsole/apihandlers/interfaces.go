@@ -0,0 +1,47 @@
+package apihandlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/user"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+type HttpHelper interface {
+	JsonError(w http.ResponseWriter, r *http.Request, err error)
+	EncodeJson(w http.ResponseWriter, r *http.Request, data interface{})
+}
+
+type AuditLogger interface {
+	Log(auditEvent string, details map[string]interface{})
+}
+
+type IdentifierValidator interface {
+	ValidateIdentifier(identifier string, enforceMinLength bool) error
+}
+
+type EmailValidator interface {
+	ValidateEmail(emailAddress string) error
+	ValidateEmailUpdate(input *validators.ValidateEmailInput) error
+}
+
+type PasswordValidator interface {
+	ValidatePassword(ctx context.Context, password string) error
+}
+
+type ProfileValidator interface {
+	ValidateName(name string, nameField string) error
+	ValidateProfile(input *validators.ValidateProfileInput) error
+}
+
+type UserCreator interface {
+	CreateUser(input *user.CreateUserInput) (*models.User, error)
+}
+
+type KeyRotator interface {
+	Rotate(algorithm string) (bool, error)
+	ReplaceNextKey(algorithm string) (bool, error)
+	RevokeKey(keyPair *models.KeyPair, algorithm string) (bool, error)
+}
@@ -0,0 +1,14 @@
+package apihandlers
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/config"
+)
+
+func TestMain(m *testing.M) {
+	config.Init("AdminConsole")
+	code := m.Run()
+	os.Exit(code)
+}
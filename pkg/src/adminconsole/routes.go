@@ -0,0 +1,110 @@
+package adminconsole
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pchchv/aas/pkg/src/adminconsole/apihandlers"
+	"github.com/pchchv/aas/pkg/src/audit"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/helpers"
+	"github.com/pchchv/aas/pkg/src/keyrotation"
+	"github.com/pchchv/aas/pkg/src/middleware"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/user"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+func (s *Server) initRoutes() {
+	tokenParser := oauth.NewTokenParser(s.database)
+	auditLogger := audit.NewAuditLogger()
+	userCreator := user.NewUserCreator(s.database)
+	keyRotator := keyrotation.NewKeyRotator(s.database)
+	identifierValidator := validators.NewIdentifierValidator(s.database)
+	emailValidator := validators.NewEmailValidator(s.database)
+	passwordValidator := validators.NewPasswordValidator()
+	profileValidator := validators.NewProfileValidator(s.database)
+
+	// the API only renders JSON, so no templates are needed
+	httpHelper := helpers.NewHttpHelper(nil, s.database)
+	authHelper := helpers.NewAuthHelper(nil)
+
+	jwtMiddleware := middleware.NewMiddlewareJwt(nil, tokenParser, s.database, authHelper, &http.Client{})
+	manageScope := constants.AdminConsoleResourceIdentifier + ":" + constants.ManageAdminConsolePermissionIdentifier
+
+	s.router.Get("/health", apihandlers.HandleHealthCheckGet(httpHelper, s.database))
+
+	s.router.Route("/api/v1", func(r chi.Router) {
+		r.Use(jwtMiddleware.JwtAuthorizationHeaderToContext())
+		r.Use(jwtMiddleware.RequiresBearerScope([]string{manageScope}))
+
+		r.Route("/clients", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPIClientsGet(httpHelper, s.database))
+			r.Post("/", apihandlers.HandleAPIClientPost(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Get("/{clientId}", apihandlers.HandleAPIClientGet(httpHelper, s.database))
+			r.Put("/{clientId}", apihandlers.HandleAPIClientPut(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Delete("/{clientId}", apihandlers.HandleAPIClientDelete(httpHelper, s.database, auditLogger))
+			r.Put("/{clientId}/redirect-uris", apihandlers.HandleAPIClientRedirectURIsPut(httpHelper, s.database, auditLogger))
+			r.Put("/{clientId}/post-logout-redirect-uris", apihandlers.HandleAPIClientPostLogoutRedirectURIsPut(httpHelper, s.database, auditLogger))
+			r.Put("/{clientId}/web-origins", apihandlers.HandleAPIClientWebOriginsPut(httpHelper, s.database, auditLogger))
+			r.Put("/{clientId}/permissions", apihandlers.HandleAPIClientPermissionsPut(httpHelper, s.database, auditLogger))
+		})
+
+		r.Route("/resources", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPIResourcesGet(httpHelper, s.database))
+			r.Post("/", apihandlers.HandleAPIResourcePost(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Get("/{resourceId}", apihandlers.HandleAPIResourceGet(httpHelper, s.database))
+			r.Put("/{resourceId}", apihandlers.HandleAPIResourcePut(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Delete("/{resourceId}", apihandlers.HandleAPIResourceDelete(httpHelper, s.database, auditLogger))
+			r.Get("/{resourceId}/permissions", apihandlers.HandleAPIResourcePermissionsGet(httpHelper, s.database))
+		})
+
+		r.Route("/permissions", func(r chi.Router) {
+			r.Post("/", apihandlers.HandleAPIPermissionPost(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Get("/{permissionId}", apihandlers.HandleAPIPermissionGet(httpHelper, s.database))
+			r.Put("/{permissionId}", apihandlers.HandleAPIPermissionPut(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Delete("/{permissionId}", apihandlers.HandleAPIPermissionDelete(httpHelper, s.database, auditLogger))
+		})
+
+		r.Route("/users", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPIUsersGet(httpHelper, s.database))
+			r.Post("/", apihandlers.HandleAPIUserPost(httpHelper, s.database, userCreator, emailValidator, profileValidator, passwordValidator, auditLogger))
+			r.Get("/{userId}", apihandlers.HandleAPIUserGet(httpHelper, s.database))
+			r.Put("/{userId}", apihandlers.HandleAPIUserPut(httpHelper, s.database, profileValidator, auditLogger))
+			r.Delete("/{userId}", apihandlers.HandleAPIUserDelete(httpHelper, s.database, auditLogger))
+			r.Put("/{userId}/email", apihandlers.HandleAPIUserEmailPut(httpHelper, s.database, emailValidator, auditLogger))
+			r.Put("/{userId}/password", apihandlers.HandleAPIUserPasswordPut(httpHelper, s.database, passwordValidator, auditLogger))
+			r.Put("/{userId}/groups", apihandlers.HandleAPIUserGroupsPut(httpHelper, s.database, auditLogger))
+			r.Put("/{userId}/permissions", apihandlers.HandleAPIUserPermissionsPut(httpHelper, s.database, auditLogger))
+		})
+
+		r.Route("/groups", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPIGroupsGet(httpHelper, s.database))
+			r.Post("/", apihandlers.HandleAPIGroupPost(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Get("/{groupId}", apihandlers.HandleAPIGroupGet(httpHelper, s.database))
+			r.Put("/{groupId}", apihandlers.HandleAPIGroupPut(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Delete("/{groupId}", apihandlers.HandleAPIGroupDelete(httpHelper, s.database, auditLogger))
+			r.Get("/{groupId}/members", apihandlers.HandleAPIGroupMembersGet(httpHelper, s.database))
+			r.Put("/{groupId}/permissions", apihandlers.HandleAPIGroupPermissionsPut(httpHelper, s.database, auditLogger))
+		})
+
+		r.Route("/settings", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPISettingsGet(httpHelper))
+			r.Put("/general", apihandlers.HandleAPISettingsGeneralPut(httpHelper, s.database, identifierValidator, auditLogger))
+			r.Put("/sessions", apihandlers.HandleAPISettingsSessionsPut(httpHelper, s.database, auditLogger))
+			r.Put("/tokens", apihandlers.HandleAPISettingsTokensPut(httpHelper, s.database, keyRotator, auditLogger))
+		})
+
+		r.Route("/initial-access-tokens", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPIInitialAccessTokensGet(httpHelper, s.database))
+			r.Post("/", apihandlers.HandleAPIInitialAccessTokenPost(httpHelper, s.database, auditLogger))
+			r.Delete("/{initialAccessTokenId}", apihandlers.HandleAPIInitialAccessTokenDelete(httpHelper, s.database, auditLogger))
+		})
+
+		r.Route("/keys", func(r chi.Router) {
+			r.Get("/", apihandlers.HandleAPIKeysGet(httpHelper, s.database))
+			r.Post("/rotate", apihandlers.HandleAPIKeysRotatePost(httpHelper, s.database, keyRotator, auditLogger))
+			r.Delete("/{keyId}", apihandlers.HandleAPIKeyDelete(httpHelper, s.database, keyRotator, auditLogger))
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package adminconsole
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/middleware"
+)
+
+type Server struct {
+	router   *chi.Mux
+	database database.Database
+}
+
+func NewServer(router *chi.Mux, database database.Database) *Server {
+	return &Server{
+		router:   router,
+		database: database,
+	}
+}
+
+func (s *Server) Start() {
+	s.initMiddleware()
+	s.initRoutes()
+
+	cfg := config.Get()
+	if len(strings.TrimSpace(cfg.CertFile)) > 0 && len(strings.TrimSpace(cfg.KeyFile)) > 0 {
+		go func() {
+			addr := fmt.Sprintf("%v:%v", cfg.ListenHostHttps, cfg.ListenPortHttps)
+			slog.Info("starting https server on " + addr)
+			if err := http.ListenAndServeTLS(addr, cfg.CertFile, cfg.KeyFile, s.router); err != nil {
+				slog.Error(err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
+	addr := fmt.Sprintf("%v:%v", cfg.ListenHostHttp, cfg.ListenPortHttp)
+	slog.Info("starting http server on " + addr)
+	if err := http.ListenAndServe(addr, s.router); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func (s *Server) initMiddleware() {
+	slog.Info("initializing middleware")
+
+	s.router.Use(chimiddleware.RequestID)
+	if config.Get().TrustProxyHeaders {
+		slog.Info("trusting proxy headers")
+		s.router.Use(chimiddleware.RealIP)
+	}
+
+	s.router.Use(chimiddleware.Recoverer)
+	if config.Get().LogHttpRequests {
+		slog.Info("http request logging enabled")
+		s.router.Use(chimiddleware.Logger)
+	}
+
+	// the JSON API is authenticated with bearer tokens only,
+	// so it does not need the CSRF and cookie middlewares of the auth server
+	s.router.Use(middleware.MiddlewareSettings(s.database))
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+func HandleAuthCompletedGet(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	userSessionManager UserSessionManager,
+	sessionStore sessions.Store,
+	database database.Database,
+	permissionChecker PermissionChecker,
+	cibaPingNotifier CIBAPingNotifier,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateAuthenticationCompleted)
+		if !ok {
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		user, err := database.GetUserById(nil, authContext.UserId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		} else if user == nil || !user.Enabled {
+			renderErrorPage(w, r, httpHelper, "The user account is not available.")
+			return
+		}
+
+		sess, err := sessionStore.Get(r, constants.SessionName)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		// a fresh authentication always starts a new session,
+		// otherwise the existing one is bumped
+		sessionIdentifier, _ := sess.Values[constants.SessionKeySessionIdentifier].(string)
+		if len(authContext.AuthMethods) > 0 || len(sessionIdentifier) == 0 {
+			if len(sessionIdentifier) > 0 {
+				// step-up on top of an existing session keeps the methods used before
+				existingSession, err := database.GetUserSessionBySessionIdentifier(nil, sessionIdentifier)
+				if err != nil {
+					httpHelper.InternalServerError(w, r, err)
+					return
+				}
+
+				passwordUsed := slices.Contains(strings.Fields(authContext.AuthMethods), enums.AuthMethodPassword.String())
+				if existingSession != nil && existingSession.UserId == user.Id && !passwordUsed {
+					for _, method := range strings.Fields(existingSession.AuthMethods) {
+						authContext.AddAuthMethod(method)
+					}
+				}
+			}
+
+			userSession, err := userSessionManager.StartNewUserSession(w, r, user.Id, client.Id,
+				authContext.AuthMethods, authContext.AcrLevel)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditStartedNewUserSesson, map[string]interface{}{
+				"userId":    user.Id,
+				"clientId":  client.Id,
+				"sessionId": userSession.Id,
+			})
+		} else {
+			userSession, err := userSessionManager.BumpUserSession(r, sessionIdentifier, client.Id)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditBumpedUserSession, map[string]interface{}{
+				"userId":   user.Id,
+				"clientId": client.Id,
+			})
+
+			authContext.AuthMethods = userSession.AuthMethods
+		}
+
+		// an essential acr claim the authentication does not satisfy makes it fail (OpenID Connect Core, section 5.5.1.1)
+		if !authContext.ApplyEssentialAcrClaim() {
+			denyAuthorization(w, r, httpHelper, database, cibaPingNotifier, authContext, "access_denied",
+				"The authentication does not satisfy the essential acr claim requested by the client.")
+			return
+		}
+
+		// only scopes the user is entitled to survive
+		scope, err := permissionChecker.FilterOutScopesWhereUserIsNotAuthorized(authContext.Scope, user)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+		authContext.Scope = scope
+
+		if len(strings.TrimSpace(authContext.Scope)) == 0 {
+			denyAuthorization(w, r, httpHelper, database, cibaPingNotifier, authContext, "access_denied",
+				"The user is not authorized to access any of the requested scopes.")
+			return
+		}
+
+		// claims requested individually are released with the consent to the scope granting them
+		requestedClaims := authContext.GetClaimsRequest().GetUserClaimNames()
+		requiresConsent := client.ConsentRequired
+		if !requiresConsent && authContext.HasScope(oidc.OfflineAccessScope) {
+			requiresConsent = true
+		}
+
+		if authContext.IsDeviceFlow() || authContext.IsCIBAFlow() || authContext.HasPrompt(oidc.PromptConsent) {
+			// the request did not start in this browser, or the client asked for it,
+			// so the user always confirms it
+			requiresConsent = true
+		} else if requiresConsent {
+			consent, err := database.GetConsentByUserIdAndClientId(nil, user.Id, client.Id)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			if consent != nil {
+				coversAll := true
+				for _, s := range strings.Split(authContext.Scope, " ") {
+					if !consent.HasScope(s) {
+						coversAll = false
+						break
+					}
+				}
+
+				for _, c := range requestedClaims {
+					if !consent.HasScope(oidc.GetClaimScope(c)) {
+						coversAll = false
+						break
+					}
+				}
+
+				if coversAll {
+					requiresConsent = false
+					authContext.ConsentedScope = authContext.Scope
+					authContext.ConsentedClaims = strings.Join(requestedClaims, " ")
+				}
+			}
+		} else {
+			authContext.ConsentedScope = authContext.Scope
+			authContext.ConsentedClaims = strings.Join(requestedClaims, " ")
+		}
+
+		if requiresConsent && requireInteraction(w, r, httpHelper, database, cibaPingNotifier, authContext, "consent_required",
+			"The user must consent to the requested scopes and prompt=none does not allow to display the consent page.") {
+			return
+		}
+
+		if requiresConsent {
+			authContext.AuthState = oauth.AuthStateRequiresConsent
+		} else {
+			authContext.AuthState = oauth.AuthStateReadyToIssueCode
+		}
+
+		if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if requiresConsent {
+			http.Redirect(w, r, "/auth/consent", http.StatusFound)
+		} else {
+			http.Redirect(w, r, "/auth/issue", http.StatusFound)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleAuthLevel1Get(httpHelper HttpHelper, authHelper AuthHelper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper,
+			oauth.AuthStateRequiresLevel1, oauth.AuthStateLevel1ExistingSession)
+		if !ok {
+			return
+		}
+
+		if authContext.AuthState == oauth.AuthStateLevel1ExistingSession {
+			http.Redirect(w, r, "/auth/level1completed", http.StatusFound)
+			return
+		}
+
+		authContext.AuthState = oauth.AuthStateLevel1Password
+		if err := authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/pwd", http.StatusFound)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+func HandleAuthLevel1CompletedGet(httpHelper HttpHelper, authHelper AuthHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper,
+			oauth.AuthStateLevel1PasswordCompleted, oauth.AuthStateLevel1ExistingSession)
+		if !ok {
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		targetAcrLevel := authContext.GetTargetAcrLevel(client.DefaultAcrLevel)
+		if targetAcrLevel == enums.AcrLevel1 {
+			authContext.AuthState = oauth.AuthStateAuthenticationCompleted
+			if authContext.AcrLevel == "" {
+				authContext.AcrLevel = targetAcrLevel.String()
+			}
+		} else {
+			authContext.AuthState = oauth.AuthStateRequiresLevel2
+		}
+
+		if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if authContext.AuthState == oauth.AuthStateAuthenticationCompleted {
+			http.Redirect(w, r, "/auth/completed", http.StatusFound)
+		} else {
+			http.Redirect(w, r, "/auth/level2", http.StatusFound)
+		}
+	}
+}
+
+func HandleAuthLevel2Get(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	sessionStore sessions.Store,
+	database database.Database,
+	cibaPingNotifier CIBAPingNotifier,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateRequiresLevel2)
+		if !ok {
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		user, err := database.GetUserById(nil, authContext.UserId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		} else if user == nil || !user.Enabled {
+			renderErrorPage(w, r, httpHelper, "The user account is not available.")
+			return
+		}
+
+		targetAcrLevel := authContext.GetTargetAcrLevel(client.DefaultAcrLevel)
+
+		// an existing session may already satisfy the level 2 requirement
+		sess, err := sessionStore.Get(r, constants.SessionName)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		// prompt=login asks for a complete reauthentication
+		if sessionIdentifier, ok := sess.Values[constants.SessionKeySessionIdentifier].(string); ok && !authContext.HasPrompt(oidc.PromptLogin) {
+			userSession, err := database.GetUserSessionBySessionIdentifier(nil, sessionIdentifier)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			if userSession != nil && userSession.UserId == user.Id && !userSession.Level2AuthConfigHasChanged {
+				sessionAcrLevel, err := enums.AcrLevelFromString(userSession.AcrLevel)
+				if err == nil && (sessionAcrLevel == enums.AcrLevel2Mandatory ||
+					(sessionAcrLevel == enums.AcrLevel2Optional && targetAcrLevel == enums.AcrLevel2Optional)) {
+					authContext.AcrLevel = sessionAcrLevel.String()
+					authContext.AuthState = oauth.AuthStateAuthenticationCompleted
+					if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+						httpHelper.InternalServerError(w, r, err)
+						return
+					}
+					http.Redirect(w, r, "/auth/completed", http.StatusFound)
+					return
+				}
+			}
+		}
+
+		if targetAcrLevel == enums.AcrLevel2Optional && !user.OTPEnabled {
+			// the user did not opt in to OTP, level 1 is enough
+			authContext.AcrLevel = targetAcrLevel.String()
+			authContext.AuthState = oauth.AuthStateAuthenticationCompleted
+			if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+			http.Redirect(w, r, "/auth/completed", http.StatusFound)
+			return
+		}
+
+		if requireInteraction(w, r, httpHelper, database, cibaPingNotifier, authContext, "interaction_required",
+			"The user must complete the second level of authentication and prompt=none does not allow to display it.") {
+			return
+		}
+
+		authContext.AcrLevel = targetAcrLevel.String()
+		authContext.AuthState = oauth.AuthStateLevel2OTP
+		if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/otp", http.StatusFound)
+	}
+}
+
+func HandleAuthLevel2CompletedGet(httpHelper HttpHelper, authHelper AuthHelper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateLevel2OTPCompleted)
+		if !ok {
+			return
+		}
+
+		authContext.AuthState = oauth.AuthStateAuthenticationCompleted
+		if err := authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/completed", http.StatusFound)
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pquerna/otp/totp"
+)
+
+func HandleAuthOtpGet(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	sessionStore sessions.Store,
+	database database.Database,
+	otpSecretGenerator OtpSecretGenerator,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateLevel2OTP)
+		if !ok {
+			return
+		}
+
+		user, err := database.GetUserById(nil, authContext.UserId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		} else if user == nil {
+			renderErrorPage(w, r, httpHelper, "The user account is not available.")
+			return
+		}
+
+		bind := map[string]interface{}{
+			"error":     nil,
+			"csrfField": csrf.TemplateField(r),
+		}
+
+		if !user.OTPEnabled {
+			// the user must enroll before a code can be verified
+			sess, err := sessionStore.Get(r, constants.SessionName)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+			base64Image, secretKey, err := otpSecretGenerator.GenerateOTPSecret(user.Email, settings.AppName)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			sess.Values[constants.SessionKeyOTPImage] = base64Image
+			sess.Values[constants.SessionKeyOTPSecret] = secretKey
+			if err = sessionStore.Save(r, w, sess); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			bind["showOtpEnrollment"] = true
+			bind["otpImage"] = base64Image
+			bind["otpSecret"] = secretKey
+		}
+
+		if err = httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_otp.html", bind); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+		}
+	}
+}
+
+func HandleAuthOtpPost(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	sessionStore sessions.Store,
+	database database.Database,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateLevel2OTP)
+		if !ok {
+			return
+		}
+
+		user, err := database.GetUserById(nil, authContext.UserId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		} else if user == nil {
+			renderErrorPage(w, r, httpHelper, "The user account is not available.")
+			return
+		}
+
+		sess, err := sessionStore.Get(r, constants.SessionName)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		otpSecret := user.OTPSecret
+		enrolling := !user.OTPEnabled
+		if enrolling {
+			secret, ok := sess.Values[constants.SessionKeyOTPSecret].(string)
+			if !ok || len(secret) == 0 {
+				renderErrorPage(w, r, httpHelper, "The OTP enrollment was not found or has expired. Please start over.")
+				return
+			}
+			otpSecret = secret
+		}
+
+		otpCode := strings.TrimSpace(r.FormValue("otp"))
+		if len(otpCode) == 0 || !totp.Validate(otpCode, otpSecret) {
+			auditLogger.Log(constants.AuditAuthFailedOtp, map[string]interface{}{
+				"userId": user.Id,
+			})
+
+			bind := map[string]interface{}{
+				"error":     "Incorrect OTP code.",
+				"csrfField": csrf.TemplateField(r),
+			}
+			if enrolling {
+				bind["showOtpEnrollment"] = true
+				bind["otpImage"] = sess.Values[constants.SessionKeyOTPImage]
+				bind["otpSecret"] = otpSecret
+			}
+
+			if err = httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_otp.html", bind); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+			}
+			return
+		}
+
+		if enrolling {
+			user.OTPSecret = otpSecret
+			user.OTPEnabled = true
+			if err = database.UpdateUser(nil, user); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			delete(sess.Values, constants.SessionKeyOTPImage)
+			delete(sess.Values, constants.SessionKeyOTPSecret)
+			if err = sessionStore.Save(r, w, sess); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditEnabledOTP, map[string]interface{}{
+				"userId": user.Id,
+			})
+		}
+
+		auditLogger.Log(constants.AuditAuthSuccessOtp, map[string]interface{}{
+			"userId": user.Id,
+		})
+
+		authContext.AddAuthMethod(enums.AuthMethodOTP.String())
+		authContext.AuthState = oauth.AuthStateLevel2OTPCompleted
+		if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/level2completed", http.StatusFound)
+	}
+}
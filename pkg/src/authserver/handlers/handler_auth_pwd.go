@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleAuthPwdGet(httpHelper HttpHelper, authHelper AuthHelper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateLevel1Password)
+		if !ok {
+			return
+		}
+
+		bind := map[string]interface{}{
+			"error":     nil,
+			"email":     authContext.LoginHint,
+			"csrfField": csrf.TemplateField(r),
+		}
+
+		if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_pwd.html", bind); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+		}
+	}
+}
+
+func HandleAuthPwdPost(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	database database.Database,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateLevel1Password)
+		if !ok {
+			return
+		}
+
+		email := strings.TrimSpace(r.FormValue("email"))
+		password := r.FormValue("password")
+		renderError := func(message string) {
+			bind := map[string]interface{}{
+				"error":     message,
+				"email":     email,
+				"csrfField": csrf.TemplateField(r),
+			}
+
+			if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_pwd.html", bind); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+			}
+		}
+
+		if len(email) == 0 {
+			renderError("Email is required.")
+			return
+		}
+
+		if len(password) == 0 {
+			renderError("Password is required.")
+			return
+		}
+
+		user, err := database.GetUserByEmail(nil, email)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if user == nil || !hashutil.VerifyPasswordHash(user.PasswordHash, password) {
+			auditDetails := map[string]interface{}{
+				"email": email,
+			}
+			if user != nil {
+				auditDetails["userId"] = user.Id
+			}
+			auditLogger.Log(constants.AuditAuthFailedPwd, auditDetails)
+			renderError("Authentication failed.")
+			return
+		}
+
+		if !user.Enabled {
+			auditLogger.Log(constants.AuditUserDisabled, map[string]interface{}{
+				"userId": user.Id,
+			})
+			renderError("Your account is disabled.")
+			return
+		}
+
+		// the client asked for a specific user with an id_token_hint
+		if !authContext.IsExpectedUser(user.Id) {
+			renderError("Please sign in with the account requested by the application.")
+			return
+		}
+
+		auditLogger.Log(constants.AuditAuthSuccessPwd, map[string]interface{}{
+			"userId": user.Id,
+		})
+
+		authContext.UserId = user.Id
+		authContext.AddAuthMethod(enums.AuthMethodPassword.String())
+		authContext.AuthState = oauth.AuthStateLevel1PasswordCompleted
+		if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/level1completed", http.StatusFound)
+	}
+}
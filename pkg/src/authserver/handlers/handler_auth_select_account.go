@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/user"
+)
+
+func HandleAuthSelectAccountGet(httpHelper HttpHelper, authHelper AuthHelper, userSessionManager UserSessionManager,
+	sessionStore sessions.Store, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateRequiresAccountSelection)
+		if !ok {
+			return
+		}
+
+		sess, err := sessionStore.Get(r, constants.SessionName)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		userSessions, err := getSelectableUserSessions(r, sess, userSessionManager, database, authContext)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		accounts := []map[string]interface{}{}
+		for i := range userSessions {
+			if err = database.UserSessionLoadUser(nil, &userSessions[i]); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			} else if userSessions[i].User.Enabled {
+				accounts = append(accounts, map[string]interface{}{
+					"userId": userSessions[i].UserId,
+					"email":  userSessions[i].User.Email,
+				})
+			}
+		}
+
+		bind := map[string]interface{}{
+			"accounts":  accounts,
+			"csrfField": csrf.TemplateField(r),
+		}
+
+		if err = httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_select_account.html", bind); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+		}
+	}
+}
+
+// HandleAuthSelectAccountPost continues with the account of one of the sessions of the browser,
+// which becomes its active session, or lets the user sign in with another account
+func HandleAuthSelectAccountPost(httpHelper HttpHelper, authHelper AuthHelper, userSessionManager UserSessionManager,
+	sessionStore sessions.Store, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateRequiresAccountSelection)
+		if !ok {
+			return
+		}
+
+		if r.FormValue("btnOtherAccount") == "true" {
+			authContext.UserId = 0
+			authContext.AcrLevel = ""
+			authContext.AuthState = oauth.AuthStateRequiresLevel1
+		} else {
+			sess, err := sessionStore.Get(r, constants.SessionName)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			userSessions, err := getSelectableUserSessions(r, sess, userSessionManager, database, authContext)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			// the account is picked by its user id, so session identifiers never appear in the page
+			userId, _ := strconv.ParseInt(r.FormValue("userId"), 10, 64)
+			idx := slices.IndexFunc(userSessions, func(userSession models.UserSession) bool {
+				return userSession.UserId == userId
+			})
+			if idx < 0 {
+				renderErrorPage(w, r, httpHelper, "The selected account is not available. Please start over.")
+				return
+			}
+			userSession := userSessions[idx]
+
+			client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			} else if client == nil {
+				renderErrorPage(w, r, httpHelper, "The client is not available.")
+				return
+			}
+
+			authContext.UserId = userSession.UserId
+			authContext.AuthState = oauth.AuthStateLevel1ExistingSession
+			if err = authContext.SetAcrLevel(authContext.GetTargetAcrLevel(client.DefaultAcrLevel), &userSession); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			activeSessionIdentifier, _ := sess.Values[constants.SessionKeySessionIdentifier].(string)
+			otherSessionIdentifiers, _ := sess.Values[constants.SessionKeyOtherSessionIdentifiers].(string)
+			sess.Values[constants.SessionKeyOtherSessionIdentifiers] = user.UpdateOtherSessionIdentifiers(otherSessionIdentifiers,
+				activeSessionIdentifier, userSession.SessionIdentifier)
+			sess.Values[constants.SessionKeySessionIdentifier] = userSession.SessionIdentifier
+			if err = sessionStore.Save(r, w, sess); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+		}
+
+		if err := authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/level1", http.StatusFound)
+	}
+}
+
+// getSelectableUserSessions returns the valid sessions of the browser that match the hints of the request,
+// the active session first. A user signed in more than once is listed once.
+func getSelectableUserSessions(r *http.Request, sess *sessions.Session, userSessionManager UserSessionManager,
+	database database.Database, authContext *oauth.AuthContext) ([]models.UserSession, error) {
+	sessionIdentifier, _ := sess.Values[constants.SessionKeySessionIdentifier].(string)
+	otherSessionIdentifiers, _ := sess.Values[constants.SessionKeyOtherSessionIdentifiers].(string)
+	requestedMaxAge := authContext.ParseRequestedMaxAge()
+
+	userSessions := []models.UserSession{}
+	for _, identifier := range append([]string{sessionIdentifier}, strings.Fields(otherSessionIdentifiers)...) {
+		if len(identifier) == 0 {
+			continue
+		}
+
+		userSession, err := database.GetUserSessionBySessionIdentifier(nil, identifier)
+		if err != nil {
+			return nil, err
+		} else if !userSessionManager.HasValidUserSession(r.Context(), userSession, requestedMaxAge) ||
+			slices.ContainsFunc(userSessions, func(us models.UserSession) bool { return us.UserId == userSession.UserId }) {
+			continue
+		}
+
+		isHintedUser, err := isHintedUser(database, authContext, userSession.UserId)
+		if err != nil {
+			return nil, err
+		} else if isHintedUser {
+			userSessions = append(userSessions, *userSession)
+		}
+	}
+
+	return userSessions, nil
+}
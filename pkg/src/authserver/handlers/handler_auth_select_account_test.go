@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	storeMocks "github.com/pchchv/aas/pkg/src/sqlstore/mocks"
+	mocksUser "github.com/pchchv/aas/pkg/src/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSelectAccountSessions(sessionStore *storeMocks.Store, database *mocks.Database,
+	userSessionManager *mocksUser.UserSessionManager) *sessions.Session {
+	sess := sessions.NewSession(sessionStore, constants.SessionName)
+	sess.Values[constants.SessionKeySessionIdentifier] = "session-1"
+	sess.Values[constants.SessionKeyOtherSessionIdentifiers] = "session-2 session-3"
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sess, nil)
+
+	userSessions := map[string]*models.UserSession{
+		"session-1": {Id: 1, SessionIdentifier: "session-1", UserId: 7, AcrLevel: enums.AcrLevel1.String()},
+		"session-2": {Id: 2, SessionIdentifier: "session-2", UserId: 8, AcrLevel: enums.AcrLevel2Mandatory.String()},
+		"session-3": {Id: 3, SessionIdentifier: "session-3", UserId: 9, AcrLevel: enums.AcrLevel1.String()},
+	}
+	for identifier, userSession := range userSessions {
+		database.On("GetUserSessionBySessionIdentifier", mock.Anything, identifier).Return(userSession, nil)
+		// the session of user 9 has expired
+		userSessionManager.On("HasValidUserSession", mock.Anything, userSession, (*int)(nil)).Return(userSession.UserId != 9)
+	}
+
+	return sess
+}
+
+func TestHandleAuthSelectAccountGet(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+
+	authHelper.On("GetAuthContext", mock.Anything).Return(&oauth.AuthContext{
+		ClientId:  "test-client",
+		AuthState: oauth.AuthStateRequiresAccountSelection,
+	}, nil)
+	setupSelectAccountSessions(sessionStore, database, userSessionManager)
+	database.On("UserSessionLoadUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		userSession := args.Get(1).(*models.UserSession)
+		userSession.User = models.User{Id: userSession.UserId, Email: fmt.Sprintf("user%d@example.com", userSession.UserId), Enabled: true}
+	}).Return(nil)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_select_account.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			accounts := data["accounts"].([]map[string]interface{})
+			return len(accounts) == 2 && accounts[0]["email"] == "user7@example.com" && accounts[1]["email"] == "user8@example.com"
+		})).Return(nil)
+
+	handler := HandleAuthSelectAccountGet(httpHelper, authHelper, userSessionManager, sessionStore, database)
+	req := httptest.NewRequest("GET", "/auth/selectaccount", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestHandleAuthSelectAccountPost_OtherSession(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+
+	authHelper.On("GetAuthContext", mock.Anything).Return(&oauth.AuthContext{
+		ClientId:  "test-client",
+		AuthState: oauth.AuthStateRequiresAccountSelection,
+	}, nil)
+	sess := setupSelectAccountSessions(sessionStore, database, userSessionManager)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1, DefaultAcrLevel: enums.AcrLevel1}, nil)
+	sessionStore.On("Save", mock.Anything, mock.Anything, sess).Return(nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.AuthState == oauth.AuthStateLevel1ExistingSession && ac.UserId == 8 &&
+			ac.AcrLevel == enums.AcrLevel2Mandatory.String()
+	})).Return(nil)
+
+	handler := HandleAuthSelectAccountPost(httpHelper, authHelper, userSessionManager, sessionStore, database)
+	req := httptest.NewRequest("POST", "/auth/selectaccount", strings.NewReader(url.Values{"userId": {"8"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+	// the selected session becomes the active session of the browser
+	assert.Equal(t, "session-2", sess.Values[constants.SessionKeySessionIdentifier])
+	assert.Equal(t, "session-1 session-3", sess.Values[constants.SessionKeyOtherSessionIdentifiers])
+}
+
+func TestHandleAuthSelectAccountPost_ExpiredSession(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+
+	authHelper.On("GetAuthContext", mock.Anything).Return(&oauth.AuthContext{
+		ClientId:  "test-client",
+		AuthState: oauth.AuthStateRequiresAccountSelection,
+	}, nil)
+	setupSelectAccountSessions(sessionStore, database, userSessionManager)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_error.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["error"] == "The selected account is not available. Please start over."
+		})).Return(nil)
+
+	handler := HandleAuthSelectAccountPost(httpHelper, authHelper, userSessionManager, sessionStore, database)
+	req := httptest.NewRequest("POST", "/auth/selectaccount", strings.NewReader(url.Values{"userId": {"9"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	authHelper.AssertNotCalled(t, "SaveAuthContext", mock.Anything, mock.Anything, mock.Anything)
+	sessionStore.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+const (
+	// requestObjectURIPrefix references a request object stored in the request objects directory
+	requestObjectURIPrefix   = "urn:goiabada:request_object:"
+	maxRequestObjectFileSize = 64 * 1024
+)
+
+// requestObjectPathSegmentRegex matches the client identifiers and request object names
+// that can be used as a file name, without leaving the request objects directory
+var requestObjectPathSegmentRegex = regexp.MustCompile(`^[a-zA-Z0-9_-][a-zA-Z0-9._-]{0,127}$`)
+
+func HandleAuthorizeGet(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	userSessionManager UserSessionManager,
+	sessionStore sessions.Store,
+	database database.Database,
+	authorizeValidator AuthorizeValidator,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		getParam := func(key string) string {
+			return httpHelper.GetFromUrlQueryOrFormPost(r, key)
+		}
+
+		// parameters of a pushed authorization request are taken only
+		// from the stored request, never from the query (RFC 9126, section 4).
+		// A stored request object is used as if it was passed by value (RFC 9101, section 5.2).
+		requestURI := getParam("request_uri")
+		pushed := false
+		if strings.HasPrefix(requestURI, requestObjectURIPrefix) {
+			if len(getParam("request")) > 0 {
+				renderErrorPage(w, r, httpHelper, "The request and request_uri parameters cannot be used together.")
+				return
+			}
+
+			requestObject, err := getStoredRequestObject(config.GetAuthServer().RequestObjectsDir, requestURI, getParam("client_id"))
+			if err != nil {
+				if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+					renderErrorPage(w, r, httpHelper, errorDetail.GetDescription())
+				} else {
+					httpHelper.InternalServerError(w, r, err)
+				}
+				return
+			}
+
+			getQueryParam := getParam
+			getParam = func(key string) string {
+				if key == "request" {
+					return requestObject
+				}
+				return getQueryParam(key)
+			}
+		} else if len(requestURI) > 0 {
+			parameters, err := getPushedAuthorizationRequest(database, requestURI, getParam("client_id"))
+			if err != nil {
+				if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+					renderErrorPage(w, r, httpHelper, errorDetail.GetDescription())
+				} else {
+					httpHelper.InternalServerError(w, r, err)
+				}
+				return
+			}
+			getParam = parameters.Get
+			pushed = true
+		}
+
+		// the parameters of a request object are only trusted once its signature is verified (RFC 9101, section 6)
+		requestObject := getParam("request")
+		if len(requestObject) > 0 {
+			result, err := authorizeValidator.ValidateRequestObject(r.Context(), &validators.ValidateRequestObjectInput{
+				ClientId:      getParam("client_id"),
+				RequestObject: requestObject,
+			})
+			if err != nil {
+				if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+					renderErrorPage(w, r, httpHelper, errorDetail.GetDescription())
+				} else {
+					httpHelper.InternalServerError(w, r, err)
+				}
+				return
+			}
+			getParam = getRequestObjectParam(result, getParam)
+		}
+
+		authContext := oauth.AuthContext{
+			ClientId:                      getParam("client_id"),
+			RedirectURI:                   getParam("redirect_uri"),
+			ResponseType:                  getParam("response_type"),
+			CodeChallengeMethod:           getParam("code_challenge_method"),
+			CodeChallenge:                 getParam("code_challenge"),
+			ResponseMode:                  getParam("response_mode"),
+			MaxAge:                        getParam("max_age"),
+			Prompt:                        getParam("prompt"),
+			LoginHint:                     strings.TrimSpace(getParam("login_hint")),
+			AcrValuesFromAuthorizeRequest: getParam("acr_values"),
+			State:                         getParam("state"),
+			Nonce:                         getParam("nonce"),
+			Resource:                      getParam("resource"),
+			Claims:                        getParam("claims"),
+			UserAgent:                     r.UserAgent(),
+			IpAddress:                     getRemoteIpAddress(r),
+			AuthState:                     oauth.AuthStateInitial,
+		}
+		authContext.SetScope(getParam("scope"))
+		idTokenHint := getParam("id_token_hint")
+
+		// when the client or the redirect URI can't be trusted,
+		// the error is displayed to the user instead of redirecting
+		err := authorizeValidator.ValidateClientAndRedirectURI(&validators.ValidateClientAndRedirectURIInput{
+			ClientId:    authContext.ClientId,
+			RedirectURI: authContext.RedirectURI,
+		})
+		if err != nil {
+			if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+				renderErrorPage(w, r, httpHelper, errorDetail.GetDescription())
+			} else {
+				httpHelper.InternalServerError(w, r, err)
+			}
+			return
+		}
+
+		err = authorizeValidator.ValidateRequest(&validators.ValidateRequestInput{
+			ResponseType:        authContext.ResponseType,
+			CodeChallengeMethod: authContext.CodeChallengeMethod,
+			CodeChallenge:       authContext.CodeChallenge,
+			ResponseMode:        authContext.ResponseMode,
+			Prompt:              authContext.Prompt,
+		})
+		if err == nil {
+			err = authorizeValidator.ValidateScopes(authContext.Scope)
+		}
+		if err == nil {
+			err = validators.ValidateResourceIndicator(authContext.Scope, authContext.Resource)
+		}
+		if err == nil {
+			err = validators.ValidateClaimsRequest(authContext.Scope, authContext.Claims)
+		}
+		if err == nil && len(idTokenHint) > 0 {
+			var hintedUser *models.User
+			hintedUser, err = authorizeValidator.ValidateIdTokenHint(&validators.ValidateIdTokenHintInput{
+				ClientId:    authContext.ClientId,
+				IdTokenHint: idTokenHint,
+			})
+			if err == nil {
+				authContext.HintedUserId = hintedUser.Id
+				authContext.LoginHint = hintedUser.Email
+			}
+		}
+
+		if err != nil {
+			if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+				redirToClientWithError(w, r, httpHelper, database, errorDetail.GetCode(), errorDetail.GetDescription(),
+					authContext.ClientId, authContext.ResponseMode, authContext.RedirectURI, authContext.State)
+			} else {
+				httpHelper.InternalServerError(w, r, err)
+			}
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if client.PARRequired && len(requestURI) == 0 {
+			redirToClientWithError(w, r, httpHelper, database, "invalid_request",
+				"The client requires pushed authorization requests. Please use the request_uri parameter.",
+				authContext.ClientId, authContext.ResponseMode, authContext.RedirectURI, authContext.State)
+			return
+		}
+
+		// the request object of a pushed authorization request was verified by the PAR endpoint,
+		// which rejects the requests without one from these clients
+		if client.RequireSignedRequestObject && len(requestObject) == 0 && !pushed {
+			redirToClientWithError(w, r, httpHelper, database, "invalid_request",
+				"The client requires signed request objects. Please use the request parameter.",
+				authContext.ClientId, authContext.ResponseMode, authContext.RedirectURI, authContext.State)
+			return
+		}
+
+		startAuthentication(w, r, httpHelper, authHelper, userSessionManager, sessionStore, database, client, &authContext)
+	}
+}
+
+// getRequestObjectParam returns a parameter getter preferring the parameters of a verified
+// request object over the others (OpenID Connect Core, section 6.1). Clients requiring
+// signed request objects can't pass authorization parameters outside of it.
+func getRequestObjectParam(requestObject *validators.ValidateRequestObjectResult, getParam func(string) string) func(string) string {
+	return func(key string) string {
+		if requestObject.Parameters.Has(key) {
+			return requestObject.Parameters.Get(key)
+		} else if requestObject.Client.RequireSignedRequestObject {
+			return ""
+		}
+		return getParam(key)
+	}
+}
+
+// getStoredRequestObject reads the request object referenced by requestURI from the subdirectory
+// of the client in the request objects directory, so a client can only reference its own
+// request objects. The request object is verified afterwards like one passed by value.
+func getStoredRequestObject(requestObjectsDir string, requestURI string, clientId string) (string, error) {
+	invalidRequestURI := customerrors.NewErrorDetail("invalid_request_uri",
+		"The request_uri parameter is invalid or the request object does not exist.")
+	if len(requestObjectsDir) == 0 {
+		return "", customerrors.NewErrorDetail("request_uri_not_supported",
+			"The request_uri parameter only supports pushed authorization requests.")
+	}
+
+	name := strings.TrimPrefix(requestURI, requestObjectURIPrefix)
+	if !requestObjectPathSegmentRegex.MatchString(clientId) || !requestObjectPathSegmentRegex.MatchString(name) {
+		return "", invalidRequestURI
+	}
+
+	file, err := os.Open(filepath.Join(requestObjectsDir, clientId, name+".jwt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", invalidRequestURI
+		}
+		return "", err
+	}
+	defer file.Close()
+
+	requestObject, err := io.ReadAll(io.LimitReader(file, maxRequestObjectFileSize))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(requestObject)), nil
+}
+
+func getRemoteIpAddress(r *http.Request) string {
+	ipWithoutPort, _, _ := net.SplitHostPort(r.RemoteAddr)
+	if len(ipWithoutPort) == 0 {
+		ipWithoutPort = r.RemoteAddr
+	}
+
+	return ipWithoutPort
+}
@@ -0,0 +1,643 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	storeMocks "github.com/pchchv/aas/pkg/src/sqlstore/mocks"
+	mocksUser "github.com/pchchv/aas/pkg/src/user/mocks"
+	"github.com/pchchv/aas/pkg/src/validators"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAuthorizeParams(httpHelper *helpersMocks.HttpHelper, params map[string]string) {
+	for _, key := range []string{"request_uri", "client_id", "redirect_uri", "response_type", "code_challenge_method",
+		"code_challenge", "response_mode", "max_age", "prompt", "login_hint", "id_token_hint", "acr_values", "state", "nonce", "scope",
+		"resource", "request", "claims"} {
+		httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, key).Return(params[key])
+	}
+}
+
+func TestHandleAuthorizeGet_InvalidClient(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{"client_id": "unknown"})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).
+		Return(customerrors.NewErrorDetail("", "Invalid client_id parameter. The client does not exist."))
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_error.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["error"] == "Invalid client_id parameter. The client does not exist."
+		})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize?client_id=unknown", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	httpHelper.AssertExpectations(t)
+	authorizeValidator.AssertNotCalled(t, "ValidateRequest", mock.Anything)
+}
+
+func TestHandleAuthorizeGet_InvalidRequestRedirectsToClient(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "token",
+		"state":         "abc",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).
+		Return(customerrors.NewErrorDetail("invalid_request", "Ensure response_type is set to 'code' as it's the only supported value."))
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", location.Host)
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+	assert.Equal(t, "abc", location.Query().Get("state"))
+	authorizeValidator.AssertNotCalled(t, "ValidateScopes", mock.Anything)
+}
+
+func TestHandleAuthorizeGet_ResourceNotInScope(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid resource1:read",
+		"resource":      "resource2",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid resource1:read").Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_target", location.Query().Get("error"))
+	database.AssertNotCalled(t, "GetClientByClientIdentifier", mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_InvalidClaimsParameter(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"claims":        `{"id_token":{"acr":{"essential":true,"value":"urn:other:acr"}}}`,
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+	assert.Equal(t, "None of the values of the essential acr claim is supported.", location.Query().Get("error_description"))
+	database.AssertNotCalled(t, "GetClientByClientIdentifier", mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_NoSessionRequiresLevel1(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid  openid email",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid email").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1}, nil)
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.AuthState == oauth.AuthStateRequiresLevel1 && ac.Scope == "openid email" && ac.ClientId == "test-client"
+	})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+	userSessionManager.AssertNotCalled(t, "HasValidUserSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_PushedAuthorizationRequest(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	requestURI := "urn:ietf:params:oauth:request_uri:abc"
+	requestURIHash, err := hashutil.HashString(requestURI)
+	assert.NoError(t, err)
+
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "request_uri").Return(requestURI)
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "client_id").Return("test-client")
+	database.On("GetPushedAuthorizationRequestByRequestURIHash", mock.Anything, requestURIHash).Return(&models.PushedAuthorizationRequest{
+		Id:       5,
+		ClientId: 1,
+		Parameters: url.Values{
+			"client_id":     {"test-client"},
+			"redirect_uri":  {"https://example.com/callback"},
+			"response_type": {"code"},
+			"scope":         {"openid"},
+			"state":         {"pushed-state"},
+		}.Encode(),
+		ExpiresAt: sql.NullTime{Time: time.Now().UTC().Add(time.Minute), Valid: true},
+	}, nil)
+	database.On("DeletePushedAuthorizationRequest", mock.Anything, int64(5)).Return(nil)
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1, PARRequired: true}, nil)
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.AuthState == oauth.AuthStateRequiresLevel1 && ac.State == "pushed-state" &&
+			ac.RedirectURI == "https://example.com/callback"
+	})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+}
+
+func TestHandleAuthorizeGet_ExpiredPushedAuthorizationRequest(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "request_uri").Return("urn:ietf:params:oauth:request_uri:abc")
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "client_id").Return("test-client")
+	database.On("GetPushedAuthorizationRequestByRequestURIHash", mock.Anything, mock.Anything).Return(&models.PushedAuthorizationRequest{
+		Id:         5,
+		ClientId:   1,
+		Parameters: url.Values{"client_id": {"test-client"}}.Encode(),
+		ExpiresAt:  sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true},
+	}, nil)
+	database.On("DeletePushedAuthorizationRequest", mock.Anything, int64(5)).Return(nil)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_error.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["error"] == "The request_uri parameter is invalid or has expired."
+		})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	authorizeValidator.AssertNotCalled(t, "ValidateClientAndRedirectURI", mock.Anything)
+}
+
+func TestHandleAuthorizeGet_PARRequired(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"state":         "abc",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1, PARRequired: true}, nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+	assert.Equal(t, "abc", location.Query().Get("state"))
+	authHelper.AssertNotCalled(t, "SaveAuthContext", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_RequestObject(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"response_type": "code",
+		"scope":         "openid",
+		"state":         "query-state",
+		"nonce":         "query-nonce",
+		"request":       "signed-request-object",
+	})
+	// parameters of the request object are never read from the query
+	for _, call := range httpHelper.ExpectedCalls {
+		call.Maybe()
+	}
+	client := &models.Client{Id: 1, ClientIdentifier: "test-client"}
+	authorizeValidator.On("ValidateRequestObject", mock.Anything, &validators.ValidateRequestObjectInput{
+		ClientId:      "test-client",
+		RequestObject: "signed-request-object",
+	}).Return(&validators.ValidateRequestObjectResult{
+		Client: client,
+		Parameters: url.Values{
+			"client_id":    {"test-client"},
+			"redirect_uri": {"https://example.com/callback"},
+			"state":        {"signed-state"},
+		},
+	}, nil)
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		// the request object takes precedence, the query fills in what it doesn't contain
+		return ac.State == "signed-state" && ac.Nonce == "query-nonce" &&
+			ac.RedirectURI == "https://example.com/callback"
+	})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+}
+
+func TestGetRequestObjectParam_RequireSignedRequestObject(t *testing.T) {
+	client := &models.Client{Id: 1, ClientIdentifier: "test-client", RequireSignedRequestObject: true}
+	getParam := getRequestObjectParam(&validators.ValidateRequestObjectResult{
+		Client:     client,
+		Parameters: url.Values{"client_id": {"test-client"}, "state": {"signed-state"}},
+	}, url.Values{"state": {"query-state"}, "nonce": {"query-nonce"}}.Get)
+
+	assert.Equal(t, "signed-state", getParam("state"))
+	assert.Equal(t, "", getParam("nonce"))
+}
+
+func TestGetStoredRequestObject(t *testing.T) {
+	requestObjectsDir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(requestObjectsDir, "test-client"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(requestObjectsDir, "test-client", "login.jwt"), []byte("signed-request-object\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(requestObjectsDir, "secret.jwt"), []byte("other-request-object"), 0o600))
+
+	tests := []struct {
+		name              string
+		requestObjectsDir string
+		requestURI        string
+		clientId          string
+		expected          string
+		expectedCode      string
+	}{
+		{
+			name:              "Stored request object",
+			requestObjectsDir: requestObjectsDir,
+			requestURI:        "urn:goiabada:request_object:login",
+			clientId:          "test-client",
+			expected:          "signed-request-object",
+		},
+		{
+			name:         "Request objects directory not configured",
+			requestURI:   "urn:goiabada:request_object:login",
+			clientId:     "test-client",
+			expectedCode: "request_uri_not_supported",
+		},
+		{
+			name:              "Request object does not exist",
+			requestObjectsDir: requestObjectsDir,
+			requestURI:        "urn:goiabada:request_object:logout",
+			clientId:          "test-client",
+			expectedCode:      "invalid_request_uri",
+		},
+		{
+			name:              "Request object of another client",
+			requestObjectsDir: requestObjectsDir,
+			requestURI:        "urn:goiabada:request_object:login",
+			clientId:          "other-client",
+			expectedCode:      "invalid_request_uri",
+		},
+		{
+			name:              "Path traversal in the name",
+			requestObjectsDir: requestObjectsDir,
+			requestURI:        "urn:goiabada:request_object:../secret",
+			clientId:          "test-client",
+			expectedCode:      "invalid_request_uri",
+		},
+		{
+			name:              "Path traversal in the client id",
+			requestObjectsDir: requestObjectsDir,
+			requestURI:        "urn:goiabada:request_object:secret",
+			clientId:          "..",
+			expectedCode:      "invalid_request_uri",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestObject, err := getStoredRequestObject(tt.requestObjectsDir, tt.requestURI, tt.clientId)
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, requestObject)
+			} else {
+				errDetail, ok := err.(*customerrors.ErrorDetail)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, errDetail.GetCode())
+			}
+		})
+	}
+}
+
+func TestHandleAuthorizeGet_RequestAndStoredRequestObject(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "request_uri").Return("urn:goiabada:request_object:login")
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "request").Return("signed-request-object")
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_error.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["error"] == "The request and request_uri parameters cannot be used together."
+		})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	authorizeValidator.AssertNotCalled(t, "ValidateRequestObject", mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_RequestObjectRequired(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"state":         "abc",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1, RequireSignedRequestObject: true}, nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_request", location.Query().Get("error"))
+	authHelper.AssertNotCalled(t, "SaveAuthContext", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_PromptNoneLoginRequired(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"state":         "abc",
+		"prompt":        "none",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.MatchedBy(func(input *validators.ValidateRequestInput) bool {
+		return input.Prompt == "none"
+	})).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1}, nil)
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "login_required", location.Query().Get("error"))
+	assert.Equal(t, "abc", location.Query().Get("state"))
+	authHelper.AssertNotCalled(t, "SaveAuthContext", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_PromptLoginIgnoresSession(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"prompt":        "login",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1}, nil)
+	sess := sessions.NewSession(sessionStore, constants.SessionName)
+	sess.Values[constants.SessionKeySessionIdentifier] = "session-id"
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sess, nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.AuthState == oauth.AuthStateRequiresLevel1 && ac.UserId == 0
+	})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+	database.AssertNotCalled(t, "GetUserSessionBySessionIdentifier", mock.Anything, mock.Anything)
+}
+
+func TestHandleAuthorizeGet_PromptSelectAccount(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"prompt":        "select_account",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1}, nil)
+	sess := sessions.NewSession(sessionStore, constants.SessionName)
+	sess.Values[constants.SessionKeySessionIdentifier] = "session-id"
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sess, nil)
+	userSession := &models.UserSession{Id: 1, UserId: 7, AcrLevel: enums.AcrLevel1.String()}
+	database.On("GetUserSessionBySessionIdentifier", mock.Anything, "session-id").Return(userSession, nil)
+	userSessionManager.On("HasValidUserSession", mock.Anything, userSession, (*int)(nil)).Return(true)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.AuthState == oauth.AuthStateRequiresAccountSelection && ac.UserId == 0
+	})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/selectaccount", rr.Header().Get("Location"))
+}
+
+func TestHandleAuthorizeGet_IdTokenHintForAnotherUser(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	setupAuthorizeParams(httpHelper, map[string]string{
+		"client_id":     "test-client",
+		"redirect_uri":  "https://example.com/callback",
+		"response_type": "code",
+		"scope":         "openid",
+		"id_token_hint": "id-token",
+	})
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateRequest", mock.Anything).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	authorizeValidator.On("ValidateIdTokenHint", &validators.ValidateIdTokenHintInput{
+		ClientId:    "test-client",
+		IdTokenHint: "id-token",
+	}).Return(&models.User{Id: 3, Email: "hinted@example.com"}, nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1}, nil)
+	sess := sessions.NewSession(sessionStore, constants.SessionName)
+	sess.Values[constants.SessionKeySessionIdentifier] = "session-id"
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sess, nil)
+	userSession := &models.UserSession{Id: 1, UserId: 7}
+	database.On("GetUserSessionBySessionIdentifier", mock.Anything, "session-id").Return(userSession, nil)
+	userSessionManager.On("HasValidUserSession", mock.Anything, userSession, (*int)(nil)).Return(true)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		// the session of another user is not reused
+		return ac.AuthState == oauth.AuthStateRequiresLevel1 && ac.UserId == 0 &&
+			ac.HintedUserId == 3 && ac.LoginHint == "hinted@example.com"
+	})).Return(nil)
+
+	handler := HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator)
+	req := httptest.NewRequest("GET", "/auth/authorize", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pchchv/aas/pkg/src/communication"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/stringutil"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+const (
+	cibaRequestExpirationInSeconds    = 300
+	cibaRequestMaxExpirationInSeconds = 1800
+	cibaRequestIntervalInSeconds      = 5
+	maxBindingMessageLength           = 64
+	maxClientNotificationTokenLength  = 1024
+)
+
+func HandleBackChannelAuthenticationPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	authorizeValidator AuthorizeValidator,
+	authenticationDeviceNotifier communication.AuthenticationDeviceNotifier,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		// only confidential clients can make backchannel authentication requests (OpenID Connect CIBA, section 7.1)
+		client, err := authenticateClient(r, database, false)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if !client.CIBAEnabled {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"The client associated with the provided client_id does not support backchannel authentication.",
+				http.StatusBadRequest))
+			return
+		}
+
+		scope := strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(r.PostFormValue("scope"), " "))
+		if !slices.Contains(strings.Fields(scope), "openid") {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_scope",
+				"The openid scope is required for backchannel authentication requests.", http.StatusBadRequest))
+			return
+		} else if err = authorizeValidator.ValidateScopes(scope); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		user, err := getBackChannelAuthenticationUser(r, database, authorizeValidator, client)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		bindingMessage := strings.TrimSpace(r.PostFormValue("binding_message"))
+		if utf8.RuneCountInString(bindingMessage) > maxBindingMessageLength {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_binding_message",
+				"The binding_message must not be longer than "+strconv.Itoa(maxBindingMessageLength)+" characters.",
+				http.StatusBadRequest))
+			return
+		}
+
+		expiresIn := cibaRequestExpirationInSeconds
+		if requestedExpiry := r.PostFormValue("requested_expiry"); len(requestedExpiry) > 0 {
+			if expiresIn, err = strconv.Atoi(requestedExpiry); err != nil || expiresIn <= 0 || expiresIn > cibaRequestMaxExpirationInSeconds {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+					"The requested_expiry must be a number of seconds between 1 and "+strconv.Itoa(cibaRequestMaxExpirationInSeconds)+".",
+					http.StatusBadRequest))
+				return
+			}
+		}
+
+		authReqId := strings.ReplaceAll(uuid.New().String(), "-", "") + stringutil.GenerateSecurityRandomString(64)
+		authReqIdHash, err := hashutil.HashString(authReqId)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		verificationCode := stringutil.GenerateSecurityRandomString(64)
+		verificationCodeHash, err := hashutil.HashString(verificationCode)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		cibaRequest := &models.CIBARequest{
+			AuthReqIdHash:          authReqIdHash,
+			VerificationCodeHash:   verificationCodeHash,
+			ClientId:               client.Id,
+			UserId:                 user.Id,
+			Scope:                  scope,
+			AcrValues:              getBackChannelAuthenticationAcrValues(r.PostFormValue("acr_values")),
+			BindingMessage:         bindingMessage,
+			TokenDeliveryMode:      constants.CIBATokenDeliveryModePoll,
+			Status:                 enums.CIBARequestStatusPending.String(),
+			ExpiresAt:              sql.NullTime{Time: time.Now().UTC().Add(time.Duration(expiresIn) * time.Second), Valid: true},
+			PollingIntervalSeconds: cibaRequestIntervalInSeconds,
+		}
+
+		if client.UsesCIBAPingMode() {
+			if err = setCIBAPingParameters(r, cibaRequest, authReqId); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+		}
+
+		if err = database.CreateCIBARequest(nil, cibaRequest); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		clientName := client.Description
+		if len(clientName) == 0 {
+			clientName = client.ClientIdentifier
+		}
+
+		if err = authenticationDeviceNotifier.NotifyAuthenticationDevice(r.Context(), &communication.AuthenticationDeviceNotification{
+			Email:           user.Email,
+			Name:            user.GetFullName(),
+			ClientName:      clientName,
+			BindingMessage:  bindingMessage,
+			VerificationURI: config.Get().BaseURL + "/ciba?code=" + url.QueryEscape(verificationCode),
+		}); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedCIBARequest, map[string]interface{}{
+			"clientId":      client.Id,
+			"userId":        user.Id,
+			"cibaRequestId": cibaRequest.Id,
+		})
+
+		httpHelper.EncodeJson(w, r, oauth.BackChannelAuthenticationResponse{
+			AuthReqId: authReqId,
+			ExpiresIn: expiresIn,
+			Interval:  cibaRequestIntervalInSeconds,
+		})
+	}
+}
+
+// getBackChannelAuthenticationUser identifies the user with exactly one of the hints of the
+// request, the login_hint being the email address of the user (OpenID Connect CIBA, section 7.1)
+func getBackChannelAuthenticationUser(r *http.Request, database database.Database,
+	authorizeValidator AuthorizeValidator, client *models.Client) (*models.User, error) {
+	loginHint := strings.TrimSpace(r.PostFormValue("login_hint"))
+	idTokenHint := strings.TrimSpace(r.PostFormValue("id_token_hint"))
+	if len(r.PostFormValue("login_hint_token")) > 0 {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The login_hint_token parameter is not supported. Please use login_hint or id_token_hint.", http.StatusBadRequest)
+	} else if (len(loginHint) > 0) == (len(idTokenHint) > 0) {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"Exactly one of the login_hint or id_token_hint parameters is required.", http.StatusBadRequest)
+	}
+
+	unknownUser := customerrors.NewErrorDetailWithHttpStatusCode("unknown_user_id",
+		"The hint does not identify a valid user.", http.StatusBadRequest)
+
+	if len(idTokenHint) > 0 {
+		user, err := authorizeValidator.ValidateIdTokenHint(&validators.ValidateIdTokenHintInput{
+			ClientId:    client.ClientIdentifier,
+			IdTokenHint: idTokenHint,
+		})
+		var errorDetail *customerrors.ErrorDetail
+		if errors.As(err, &errorDetail) && errorDetail.GetCode() == "login_required" {
+			return nil, unknownUser
+		} else if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	user, err := database.GetUserByEmail(nil, loginHint)
+	if err != nil {
+		return nil, err
+	} else if user == nil || !user.Enabled {
+		return nil, unknownUser
+	}
+
+	return user, nil
+}
+
+// getBackChannelAuthenticationAcrValues keeps the supported values of the acr_values parameter
+func getBackChannelAuthenticationAcrValues(acrValues string) string {
+	supported := []string{}
+	for _, acrValue := range strings.Fields(acrValues) {
+		if _, err := enums.AcrLevelFromString(acrValue); err == nil {
+			supported = append(supported, acrValue)
+		}
+	}
+
+	return strings.Join(supported, " ")
+}
+
+// setCIBAPingParameters keeps what is needed to ping the client once the user has authenticated.
+// The auth_req_id and client_notification_token are stored encrypted, as the client must receive them back.
+func setCIBAPingParameters(r *http.Request, cibaRequest *models.CIBARequest, authReqId string) error {
+	clientNotificationToken := r.PostFormValue("client_notification_token")
+	if len(clientNotificationToken) == 0 {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The client_notification_token parameter is required for clients using the ping mode.", http.StatusBadRequest)
+	} else if len(clientNotificationToken) > maxClientNotificationTokenLength {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The client_notification_token must not be longer than "+strconv.Itoa(maxClientNotificationTokenLength)+" characters.",
+			http.StatusBadRequest)
+	}
+
+	settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+	authReqIdEncrypted, err := encryption.EncryptText(authReqId, settings.AESEncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	clientNotificationTokenEncrypted, err := encryption.EncryptText(clientNotificationToken, settings.AESEncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	cibaRequest.TokenDeliveryMode = constants.CIBATokenDeliveryModePing
+	cibaRequest.AuthReqIdEncrypted = authReqIdEncrypted
+	cibaRequest.ClientNotificationTokenEncrypted = clientNotificationTokenEncrypted
+	return nil
+}
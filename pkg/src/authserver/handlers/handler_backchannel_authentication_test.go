@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/communication"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBackChannelAuthenticationRequest(form url.Values) *http.Request {
+	form.Set("client_id", "call-center")
+	form.Set("client_secret", "secret")
+	req := httptest.NewRequest("POST", "/auth/bc-authorize", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{AESEncryptionKey: testAESEncryptionKey})
+	return req.WithContext(ctx)
+}
+
+func newBackChannelAuthenticationClient(t *testing.T) *models.Client {
+	clientSecretEncrypted, err := encryption.EncryptText("secret", testAESEncryptionKey)
+	assert.NoError(t, err)
+	return &models.Client{
+		Id:                    1,
+		ClientIdentifier:      "call-center",
+		Description:           "Call center",
+		ClientSecretEncrypted: clientSecretEncrypted,
+		Enabled:               true,
+		CIBAEnabled:           true,
+	}
+}
+
+func expectBackChannelAuthenticationError(httpHelper *helpersMocks.HttpHelper, code string) {
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == code
+	})).Return()
+}
+
+func TestHandleBackChannelAuthenticationPost_CIBADisabled(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+	notifier := communication.NewInMemoryAuthenticationDeviceNotifier()
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	client := newBackChannelAuthenticationClient(t)
+	client.CIBAEnabled = false
+	database.On("GetClientByClientIdentifier", mock.Anything, "call-center").Return(client, nil)
+	expectBackChannelAuthenticationError(httpHelper, "unauthorized_client")
+
+	handler := HandleBackChannelAuthenticationPost(httpHelper, database, authorizeValidator, notifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newBackChannelAuthenticationRequest(url.Values{"scope": {"openid"}, "login_hint": {"jane@example.com"}}))
+
+	database.AssertNotCalled(t, "CreateCIBARequest", mock.Anything, mock.Anything)
+	assert.Empty(t, notifier.GetNotifications())
+}
+
+func TestHandleBackChannelAuthenticationPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+	notifier := communication.NewInMemoryAuthenticationDeviceNotifier()
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	var created *models.CIBARequest
+	database.On("GetClientByClientIdentifier", mock.Anything, "call-center").Return(newBackChannelAuthenticationClient(t), nil)
+	authorizeValidator.On("ValidateScopes", "openid profile").Return(nil)
+	database.On("GetUserByEmail", mock.Anything, "jane@example.com").Return(&models.User{Id: 7, Email: "jane@example.com", GivenName: "Jane", Enabled: true}, nil)
+	database.On("CreateCIBARequest", mock.Anything, mock.MatchedBy(func(cibaRequest *models.CIBARequest) bool {
+		return cibaRequest.ClientId == 1 && cibaRequest.UserId == 7 && cibaRequest.Scope == "openid profile" &&
+			cibaRequest.AcrValues == "urn:goiabada:level2_optional" && cibaRequest.BindingMessage == "A1B2" &&
+			cibaRequest.TokenDeliveryMode == "poll" && cibaRequest.Status == "pending" &&
+			cibaRequest.PollingIntervalSeconds == 5 && cibaRequest.ExpiresAt.Valid && len(cibaRequest.AuthReqIdEncrypted) == 0
+	})).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.CIBARequest)
+	}).Return(nil)
+	auditLogger.On("Log", constants.AuditCreatedCIBARequest, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp oauth.BackChannelAuthenticationResponse) bool {
+		authReqIdHash, err := hashutil.HashString(resp.AuthReqId)
+		return err == nil && authReqIdHash == created.AuthReqIdHash && resp.ExpiresIn == 120 && resp.Interval == 5
+	})).Return()
+
+	handler := HandleBackChannelAuthenticationPost(httpHelper, database, authorizeValidator, notifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newBackChannelAuthenticationRequest(url.Values{
+		"scope":            {"openid profile"},
+		"login_hint":       {"jane@example.com"},
+		"binding_message":  {"A1B2"},
+		"acr_values":       {"urn:goiabada:level2_optional unknown"},
+		"requested_expiry": {"120"},
+	}))
+
+	notifications := notifier.GetNotifications()
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, "jane@example.com", notifications[0].Email)
+		assert.Equal(t, "Call center", notifications[0].ClientName)
+		assert.Equal(t, "A1B2", notifications[0].BindingMessage)
+
+		verificationURI, err := url.Parse(notifications[0].VerificationURI)
+		assert.NoError(t, err)
+		assert.Equal(t, "/ciba", verificationURI.Path)
+		verificationCodeHash, err := hashutil.HashString(verificationURI.Query().Get("code"))
+		assert.NoError(t, err)
+		assert.Equal(t, created.VerificationCodeHash, verificationCodeHash)
+	}
+}
+
+func TestHandleBackChannelAuthenticationPost_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		form         url.Values
+		pingMode     bool
+		expectedCode string
+	}{
+		{
+			name:         "Missing openid scope",
+			form:         url.Values{"scope": {"profile"}, "login_hint": {"jane@example.com"}},
+			expectedCode: "invalid_scope",
+		},
+		{
+			name:         "Missing hint",
+			form:         url.Values{"scope": {"openid"}},
+			expectedCode: "invalid_request",
+		},
+		{
+			name:         "Unknown user",
+			form:         url.Values{"scope": {"openid"}, "login_hint": {"unknown@example.com"}},
+			expectedCode: "unknown_user_id",
+		},
+		{
+			name:         "Binding message too long",
+			form:         url.Values{"scope": {"openid"}, "login_hint": {"jane@example.com"}, "binding_message": {strings.Repeat("a", 65)}},
+			expectedCode: "invalid_binding_message",
+		},
+		{
+			name:         "Ping mode without client notification token",
+			form:         url.Values{"scope": {"openid"}, "login_hint": {"jane@example.com"}},
+			pingMode:     true,
+			expectedCode: "invalid_request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpHelper := helpersMocks.NewHttpHelper(t)
+			database := mocks.NewDatabase(t)
+			authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+			notifier := communication.NewInMemoryAuthenticationDeviceNotifier()
+			auditLogger := auditMocks.NewAuditLogger(t)
+
+			client := newBackChannelAuthenticationClient(t)
+			if tt.pingMode {
+				client.BackChannelTokenDeliveryMode = "ping"
+				client.BackChannelClientNotificationEndpoint = "https://call-center.example.com/cb"
+			}
+			database.On("GetClientByClientIdentifier", mock.Anything, "call-center").Return(client, nil)
+			authorizeValidator.On("ValidateScopes", mock.Anything).Return(nil).Maybe()
+			database.On("GetUserByEmail", mock.Anything, "jane@example.com").Return(&models.User{Id: 7, Email: "jane@example.com", Enabled: true}, nil).Maybe()
+			database.On("GetUserByEmail", mock.Anything, "unknown@example.com").Return(nil, nil).Maybe()
+			expectBackChannelAuthenticationError(httpHelper, tt.expectedCode)
+
+			handler := HandleBackChannelAuthenticationPost(httpHelper, database, authorizeValidator, notifier, auditLogger)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newBackChannelAuthenticationRequest(tt.form))
+
+			database.AssertNotCalled(t, "CreateCIBARequest", mock.Anything, mock.Anything)
+			assert.Empty(t, notifier.GetNotifications())
+		})
+	}
+}
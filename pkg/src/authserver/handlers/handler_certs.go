@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pkg/errors"
+)
+
+func HandleCertsGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := database.GetAllSigningKeys(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		result := oauth.Jwks{
+			Keys: []oauth.Jwk{},
+		}
+		for _, key := range keys {
+			var jwk oauth.Jwk
+			if err = json.Unmarshal(key.PublicKeyJWK, &jwk); err != nil {
+				httpHelper.JsonError(w, r, errors.Wrap(err, "unable to unmarshal public key JWK"))
+				return
+			}
+			result.Keys = append(result.Keys, jwk)
+		}
+
+		httpHelper.EncodeJson(w, r, result)
+	}
+}
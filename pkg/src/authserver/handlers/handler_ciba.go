@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pkg/errors"
+)
+
+// HandleCIBAGet shows the backchannel authentication request to the user following the link they received.
+// The authentication only starts when the user confirms, so that links opened by mail scanners have no effect.
+func HandleCIBAGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		verificationCode := r.URL.Query().Get("code")
+		cibaRequest, ok := getCIBARequestByVerificationCode(w, r, httpHelper, database, verificationCode)
+		if !ok {
+			return
+		}
+
+		bind := map[string]interface{}{
+			"code":              verificationCode,
+			"clientIdentifier":  cibaRequest.Client.ClientIdentifier,
+			"clientDescription": cibaRequest.Client.Description,
+			"bindingMessage":    cibaRequest.BindingMessage,
+			"csrfField":         csrf.TemplateField(r),
+		}
+
+		if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/ciba.html", bind); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+		}
+	}
+}
+
+func HandleCIBAPost(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	userSessionManager UserSessionManager,
+	sessionStore sessions.Store,
+	database database.Database,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cibaRequest, ok := getCIBARequestByVerificationCode(w, r, httpHelper, database, r.FormValue("code"))
+		if !ok {
+			return
+		}
+
+		if err := database.CIBARequestLoadUser(nil, cibaRequest); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		// only the user the client asked for can complete the request
+		authContext := oauth.AuthContext{
+			ClientId:                      cibaRequest.Client.ClientIdentifier,
+			CIBARequestId:                 cibaRequest.Id,
+			HintedUserId:                  cibaRequest.UserId,
+			LoginHint:                     cibaRequest.User.Email,
+			AcrValuesFromAuthorizeRequest: cibaRequest.AcrValues,
+			UserAgent:                     r.UserAgent(),
+			IpAddress:                     getRemoteIpAddress(r),
+			AuthState:                     oauth.AuthStateInitial,
+		}
+		authContext.SetScope(cibaRequest.Scope)
+
+		startAuthentication(w, r, httpHelper, authHelper, userSessionManager, sessionStore, database, &cibaRequest.Client, &authContext)
+	}
+}
+
+// getCIBARequestByVerificationCode renders an error page when the link
+// is invalid or the request was already handled or has expired
+func getCIBARequestByVerificationCode(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper,
+	database database.Database, verificationCode string) (*models.CIBARequest, bool) {
+	const invalidRequestMessage = "The sign-in request is invalid or has expired. Please ask the application to send a new one."
+	if len(verificationCode) == 0 {
+		renderErrorPage(w, r, httpHelper, invalidRequestMessage)
+		return nil, false
+	}
+
+	verificationCodeHash, err := hashutil.HashString(verificationCode)
+	if err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return nil, false
+	}
+
+	cibaRequest, err := database.GetCIBARequestByVerificationCodeHash(nil, verificationCodeHash)
+	if err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return nil, false
+	} else if cibaRequest == nil || cibaRequest.IsExpired() || cibaRequest.Status != enums.CIBARequestStatusPending.String() {
+		renderErrorPage(w, r, httpHelper, invalidRequestMessage)
+		return nil, false
+	}
+
+	if err = database.CIBARequestLoadClient(nil, cibaRequest); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return nil, false
+	} else if !cibaRequest.Client.Enabled || !cibaRequest.Client.CIBAEnabled {
+		renderErrorPage(w, r, httpHelper, "The application is not available.")
+		return nil, false
+	}
+
+	return cibaRequest, true
+}
+
+// completeCIBARequest saves the outcome of the backchannel authentication. Clients using the ping mode
+// are told they can fetch it from the token endpoint (OpenID Connect CIBA, section 10.2).
+func completeCIBARequest(r *http.Request, database database.Database, cibaPingNotifier CIBAPingNotifier, cibaRequest *models.CIBARequest) error {
+	if err := database.UpdateCIBARequest(nil, cibaRequest); err != nil {
+		return err
+	}
+
+	if cibaRequest.TokenDeliveryMode != constants.CIBATokenDeliveryModePing {
+		return nil
+	}
+
+	if err := database.CIBARequestLoadClient(nil, cibaRequest); err != nil {
+		return err
+	}
+
+	settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+	authReqId, err := encryption.DecryptText(cibaRequest.AuthReqIdEncrypted, settings.AESEncryptionKey)
+	if err != nil {
+		return errors.Wrap(err, "unable to decrypt the auth_req_id")
+	}
+
+	clientNotificationToken, err := encryption.DecryptText(cibaRequest.ClientNotificationTokenEncrypted, settings.AESEncryptionKey)
+	if err != nil {
+		return errors.Wrap(err, "unable to decrypt the client notification token")
+	}
+
+	cibaPingNotifier.NotifyCIBAPing(oauth.CIBAPingNotification{
+		ClientIdentifier:           cibaRequest.Client.ClientIdentifier,
+		ClientNotificationEndpoint: cibaRequest.Client.BackChannelClientNotificationEndpoint,
+		ClientNotificationToken:    clientNotificationToken,
+		AuthReqId:                  authReqId,
+	})
+	return nil
+}
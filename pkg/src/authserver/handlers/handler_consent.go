@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+type consentScope struct {
+	Scope       string
+	Description string
+}
+
+// consentClaim is a claim of the user requested individually with the claims parameter
+type consentClaim struct {
+	Claim     string
+	Essential bool
+}
+
+func HandleConsentGet(httpHelper HttpHelper, authHelper AuthHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateRequiresConsent)
+		if !ok {
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		scopes, err := buildConsentScopes(database, authContext.Scope)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		claimsRequest := authContext.GetClaimsRequest()
+		claims := []consentClaim{}
+		for _, c := range claimsRequest.GetUserClaimNames() {
+			claims = append(claims, consentClaim{
+				Claim:     c,
+				Essential: claimsRequest.IsEssentialClaim(c),
+			})
+		}
+
+		bind := map[string]interface{}{
+			"clientIdentifier":  client.ClientIdentifier,
+			"clientDescription": client.Description,
+			"scopes":            scopes,
+			"claims":            claims,
+			"csrfField":         csrf.TemplateField(r),
+		}
+
+		if err = httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/consent.html", bind); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+		}
+	}
+}
+
+func HandleConsentPost(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	database database.Database,
+	cibaPingNotifier CIBAPingNotifier,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateRequiresConsent)
+		if !ok {
+			return
+		}
+
+		if r.FormValue("btnCancel") == "true" {
+			if err := authHelper.ClearAuthContext(w, r); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			denyAuthorization(w, r, httpHelper, database, cibaPingNotifier, authContext, "access_denied", "The user did not provide consent.")
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, authContext.ClientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		requestedScopes := strings.Split(authContext.Scope, " ")
+		consentedScopes := []string{}
+		for i, s := range requestedScopes {
+			if r.FormValue("consent"+strconv.Itoa(i)) == "on" {
+				consentedScopes = append(consentedScopes, s)
+			}
+		}
+
+		if len(consentedScopes) == 0 {
+			if err = authHelper.ClearAuthContext(w, r); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			denyAuthorization(w, r, httpHelper, database, cibaPingNotifier, authContext, "access_denied", "The user did not provide consent.")
+			return
+		}
+
+		consent, err := database.GetConsentByUserIdAndClientId(nil, authContext.UserId, client.Id)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		utcNow := time.Now().UTC()
+		if consent == nil {
+			consent = &models.UserConsent{
+				UserId:    authContext.UserId,
+				ClientId:  client.Id,
+				Scope:     strings.Join(consentedScopes, " "),
+				GrantedAt: sql.NullTime{Time: utcNow, Valid: true},
+			}
+			err = database.CreateUserConsent(nil, consent)
+		} else {
+			consent.Scope = strings.Join(consentedScopes, " ")
+			consent.GrantedAt = sql.NullTime{Time: utcNow, Valid: true}
+			err = database.UpdateUserConsent(nil, consent)
+		}
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditSavedConsent, map[string]interface{}{
+			"userId":   authContext.UserId,
+			"clientId": client.Id,
+		})
+
+		consentedClaims := []string{}
+		for i, c := range authContext.GetClaimsRequest().GetUserClaimNames() {
+			if r.FormValue("claim"+strconv.Itoa(i)) == "on" {
+				consentedClaims = append(consentedClaims, c)
+			}
+		}
+
+		authContext.ConsentedScope = strings.Join(consentedScopes, " ")
+		authContext.ConsentedClaims = strings.Join(consentedClaims, " ")
+		authContext.AuthState = oauth.AuthStateReadyToIssueCode
+		if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		http.Redirect(w, r, "/auth/issue", http.StatusFound)
+	}
+}
+
+func buildConsentScopes(database database.Database, scope string) ([]consentScope, error) {
+	scopes := []consentScope{}
+	for _, s := range strings.Split(scope, " ") {
+		if oidc.IsIdTokenScope(s) || oidc.IsOfflineAccessScope(s) {
+			scopes = append(scopes, consentScope{
+				Scope:       s,
+				Description: oidc.GetIdTokenScopeDescription(s),
+			})
+			continue
+		}
+
+		description := s
+		parts := strings.Split(s, ":")
+		if len(parts) == 2 {
+			resource, err := database.GetResourceByResourceIdentifier(nil, parts[0])
+			if err != nil {
+				return nil, err
+			}
+
+			if resource != nil {
+				permissions, err := database.GetPermissionsByResourceId(nil, resource.Id)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, permission := range permissions {
+					if permission.PermissionIdentifier == parts[1] && len(permission.Description) > 0 {
+						description = permission.Description
+						break
+					}
+				}
+			}
+		}
+
+		scopes = append(scopes, consentScope{
+			Scope:       s,
+			Description: description,
+		})
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleDeviceGet(httpHelper HttpHelper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		renderDevicePage(w, r, httpHelper, normalizeUserCode(r.URL.Query().Get("user_code")), "")
+	}
+}
+
+func HandleDevicePost(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	userSessionManager UserSessionManager,
+	sessionStore sessions.Store,
+	database database.Database,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCode := normalizeUserCode(r.FormValue("user_code"))
+		if len(userCode) == 0 {
+			renderDevicePage(w, r, httpHelper, userCode, "Please enter the code displayed on your device.")
+			return
+		}
+
+		deviceCode, err := database.GetDeviceCodeByUserCode(nil, userCode)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		} else if deviceCode == nil || deviceCode.IsExpired() || deviceCode.Status != enums.DeviceCodeStatusPending.String() {
+			renderDevicePage(w, r, httpHelper, userCode, "The code is invalid or has expired. Please check the code displayed on your device.")
+			return
+		}
+
+		if err = database.DeviceCodeLoadClient(nil, deviceCode); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		} else if !deviceCode.Client.Enabled || !deviceCode.Client.DeviceCodeEnabled {
+			renderDevicePage(w, r, httpHelper, userCode, "The application is not available.")
+			return
+		}
+
+		authContext := oauth.AuthContext{
+			ClientId:     deviceCode.Client.ClientIdentifier,
+			DeviceCodeId: deviceCode.Id,
+			UserAgent:    r.UserAgent(),
+			IpAddress:    getRemoteIpAddress(r),
+			AuthState:    oauth.AuthStateInitial,
+		}
+		authContext.SetScope(deviceCode.Scope)
+
+		startAuthentication(w, r, httpHelper, authHelper, userSessionManager, sessionStore, database, &deviceCode.Client, &authContext)
+	}
+}
+
+func renderDevicePage(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, userCode string, errorMessage string) {
+	bind := map[string]interface{}{
+		"userCode":  userCode,
+		"error":     errorMessage,
+		"csrfField": csrf.TemplateField(r),
+	}
+
+	if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/device.html", bind); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+	}
+}
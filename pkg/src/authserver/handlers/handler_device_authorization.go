@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/stringutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	deviceCodeExpirationInSeconds = 600
+	deviceCodeIntervalInSeconds   = 5
+	// vowels and look-alike characters are left out (RFC 8628, section 6.1)
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+	// a new user code is generated when it is already taken by another device code
+	maxUserCodeAttempts = 5
+)
+
+func HandleDeviceAuthorizationPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	authorizeValidator AuthorizeValidator,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		client, err := authenticateClient(r, database, true)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if !client.DeviceCodeEnabled {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"The client associated with the provided client_id does not support device authorization flow.",
+				http.StatusBadRequest))
+			return
+		}
+
+		scope := strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(r.PostFormValue("scope"), " "))
+		if err = authorizeValidator.ValidateScopes(scope); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		deviceCode := strings.ReplaceAll(uuid.New().String(), "-", "") + stringutil.GenerateSecurityRandomString(96)
+		deviceCodeHash, err := hashutil.HashString(deviceCode)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		userCode, err := generateUniqueUserCode(database)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		deviceCodeEntity := &models.DeviceCode{
+			DeviceCodeHash:         deviceCodeHash,
+			UserCode:               userCode,
+			ClientId:               client.Id,
+			Scope:                  scope,
+			Status:                 enums.DeviceCodeStatusPending.String(),
+			ExpiresAt:              sql.NullTime{Time: time.Now().UTC().Add(deviceCodeExpirationInSeconds * time.Second), Valid: true},
+			PollingIntervalSeconds: deviceCodeIntervalInSeconds,
+		}
+		if err = database.CreateDeviceCode(nil, deviceCodeEntity); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedDeviceCode, map[string]interface{}{
+			"clientId":     client.Id,
+			"deviceCodeId": deviceCodeEntity.Id,
+		})
+
+		verificationURI := config.Get().BaseURL + "/device"
+		httpHelper.EncodeJson(w, r, oauth.DeviceAuthorizationResponse{
+			DeviceCode:              deviceCode,
+			UserCode:                userCode,
+			VerificationURI:         verificationURI,
+			VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(userCode),
+			ExpiresIn:               deviceCodeExpirationInSeconds,
+			Interval:                deviceCodeIntervalInSeconds,
+		})
+	}
+}
+
+// generateUniqueUserCode returns a user code that no other device code uses,
+// the unique index on the user codes still rejects a concurrent duplicate
+func generateUniqueUserCode(database database.Database) (string, error) {
+	for range maxUserCodeAttempts {
+		userCode, err := generateUserCode()
+		if err != nil {
+			return "", err
+		}
+
+		existingDeviceCode, err := database.GetDeviceCodeByUserCode(nil, userCode)
+		if err != nil {
+			return "", err
+		} else if existingDeviceCode == nil {
+			return userCode, nil
+		}
+	}
+
+	return "", errors.New("unable to generate a unique user code")
+}
+
+// generateUserCode returns a random user code formatted as XXXX-XXXX,
+// every character is picked uniformly from the charset
+func generateUserCode() (string, error) {
+	charsetLength := big.NewInt(int64(len(userCodeCharset)))
+
+	var sb strings.Builder
+	for i := range userCodeLength {
+		if i == userCodeLength/2 {
+			sb.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, charsetLength)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(userCodeCharset[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// normalizeUserCode uppercases the user code and restores the dash,
+// so users can type it without separators or in lower case
+func normalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	userCode = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(userCodeCharset, r) {
+			return r
+		}
+		return -1
+	}, userCode)
+
+	if len(userCode) != userCodeLength {
+		return userCode
+	}
+
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
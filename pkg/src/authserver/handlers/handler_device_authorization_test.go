@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newDeviceAuthorizationRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/auth/device_authorization", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{AESEncryptionKey: testAESEncryptionKey})
+	return req.WithContext(ctx)
+}
+
+func TestHandleDeviceAuthorizationPost_DeviceFlowDisabled(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "tv-app").Return(&models.Client{Id: 1, ClientIdentifier: "tv-app", Enabled: true, IsPublic: true}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == "unauthorized_client"
+	})).Return()
+
+	handler := HandleDeviceAuthorizationPost(httpHelper, database, authorizeValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newDeviceAuthorizationRequest(url.Values{"client_id": {"tv-app"}, "scope": {"openid"}}))
+
+	database.AssertNotCalled(t, "CreateDeviceCode", mock.Anything, mock.Anything)
+}
+
+func TestHandleDeviceAuthorizationPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	var created *models.DeviceCode
+	database.On("GetClientByClientIdentifier", mock.Anything, "tv-app").Return(&models.Client{Id: 1, ClientIdentifier: "tv-app", Enabled: true, IsPublic: true, DeviceCodeEnabled: true}, nil)
+	authorizeValidator.On("ValidateScopes", "openid profile").Return(nil)
+	database.On("GetDeviceCodeByUserCode", mock.Anything, mock.Anything).Return(nil, nil)
+	database.On("CreateDeviceCode", mock.Anything, mock.MatchedBy(func(deviceCode *models.DeviceCode) bool {
+		return deviceCode.ClientId == 1 && deviceCode.Scope == "openid profile" && deviceCode.Status == "pending" &&
+			deviceCode.PollingIntervalSeconds == 5 && deviceCode.ExpiresAt.Valid
+	})).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.DeviceCode)
+	}).Return(nil)
+	auditLogger.On("Log", constants.AuditCreatedDeviceCode, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp oauth.DeviceAuthorizationResponse) bool {
+		deviceCodeHash, err := hashutil.HashString(resp.DeviceCode)
+		return err == nil && deviceCodeHash == created.DeviceCodeHash && resp.UserCode == created.UserCode &&
+			regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`).MatchString(resp.UserCode) &&
+			strings.HasSuffix(resp.VerificationURI, "/device") &&
+			resp.VerificationURIComplete == resp.VerificationURI+"?user_code="+resp.UserCode &&
+			resp.ExpiresIn == 600 && resp.Interval == 5
+	})).Return()
+
+	handler := HandleDeviceAuthorizationPost(httpHelper, database, authorizeValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newDeviceAuthorizationRequest(url.Values{"client_id": {"tv-app"}, "scope": {" openid  profile"}}))
+
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+}
+
+func TestGenerateUniqueUserCode_RetriesOnCollision(t *testing.T) {
+	database := mocks.NewDatabase(t)
+
+	var takenUserCode string
+	database.On("GetDeviceCodeByUserCode", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		takenUserCode = args.Get(1).(string)
+	}).Return(&models.DeviceCode{Id: 1}, nil).Once()
+	database.On("GetDeviceCodeByUserCode", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+	userCode, err := generateUniqueUserCode(database)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, takenUserCode, userCode)
+	database.AssertNumberOfCalls(t, "GetDeviceCodeByUserCode", 2)
+}
+
+func TestGenerateUniqueUserCode_AllTaken(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	database.On("GetDeviceCodeByUserCode", mock.Anything, mock.Anything).Return(&models.DeviceCode{Id: 1}, nil)
+
+	_, err := generateUniqueUserCode(database)
+
+	assert.EqualError(t, err, "unable to generate a unique user code")
+	database.AssertNumberOfCalls(t, "GetDeviceCodeByUserCode", maxUserCodeAttempts)
+}
+
+func TestNormalizeUserCode(t *testing.T) {
+	assert.Equal(t, "BCDF-GHJK", normalizeUserCode("bcdfghjk"))
+	assert.Equal(t, "BCDF-GHJK", normalizeUserCode(" bcdf-ghjk "))
+	assert.Equal(t, "BCD", normalizeUserCode("bcd"))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	storeMocks "github.com/pchchv/aas/pkg/src/sqlstore/mocks"
+	mocksUser "github.com/pchchv/aas/pkg/src/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newDeviceRequest(userCode string) *http.Request {
+	req := httptest.NewRequest("POST", "/device", strings.NewReader(url.Values{"user_code": {userCode}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestHandleDevicePost_ExpiredUserCode(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+
+	database.On("GetDeviceCodeByUserCode", mock.Anything, "BCDF-GHJK").Return(&models.DeviceCode{
+		Id:        1,
+		Status:    "pending",
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+	}, nil)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/device.html", mock.MatchedBy(func(bind map[string]interface{}) bool {
+		return bind["userCode"] == "BCDF-GHJK" && bind["error"] != ""
+	})).Return(nil)
+
+	handler := HandleDevicePost(httpHelper, authHelper, userSessionManager, sessionStore, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newDeviceRequest("bcdfghjk"))
+
+	authHelper.AssertNotCalled(t, "SaveAuthContext", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleDevicePost_StartsAuthentication(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+
+	deviceCode := &models.DeviceCode{
+		Id:        4,
+		ClientId:  1,
+		Scope:     "openid profile",
+		Status:    "pending",
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+	}
+	database.On("GetDeviceCodeByUserCode", mock.Anything, "BCDF-GHJK").Return(deviceCode, nil)
+	database.On("DeviceCodeLoadClient", mock.Anything, deviceCode).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.DeviceCode).Client = models.Client{Id: 1, ClientIdentifier: "tv-app", Enabled: true, DeviceCodeEnabled: true}
+	}).Return(nil)
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.IsDeviceFlow() && ac.DeviceCodeId == 4 && ac.ClientId == "tv-app" &&
+			ac.Scope == "openid profile" && ac.AuthState == oauth.AuthStateRequiresLevel1
+	})).Return(nil)
+
+	handler := HandleDevicePost(httpHelper, authHelper, userSessionManager, sessionStore, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newDeviceRequest("BCDF-GHJK"))
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+	"github.com/pchchv/aas/pkg/src/validators"
+	"github.com/pkg/errors"
+)
+
+func renderErrorPage(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, errorMessage string) {
+	bind := map[string]interface{}{
+		"title": "Unable to authorize",
+		"error": errorMessage,
+	}
+
+	if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_error.html", bind); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+	}
+}
+
+func redirToClientWithError(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, database database.Database,
+	code string, description string, clientId string, responseMode string, redirectURI string, state string) {
+	values := url.Values{}
+	values.Set("error", code)
+	values.Set("error_description", description)
+	if len(strings.TrimSpace(state)) > 0 {
+		values.Set("state", state)
+	}
+
+	respondToClient(w, r, httpHelper, database, clientId, responseMode, redirectURI, values)
+}
+
+func redirToClientWithCode(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, database database.Database,
+	code string, clientId string, responseMode string, redirectURI string, state string) {
+	values := url.Values{}
+	values.Set("code", code)
+	if len(strings.TrimSpace(state)) > 0 {
+		values.Set("state", state)
+	}
+
+	respondToClient(w, r, httpHelper, database, clientId, responseMode, redirectURI, values)
+}
+
+// respondToClient delivers the authorization response to the client using the requested
+// response mode (query, fragment or form_post). With the JWT response modes the parameters
+// are signed into a single response parameter, so the client can detect tampering (JARM),
+// and then encrypted when the client registered an authorization response encryption.
+func respondToClient(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, database database.Database,
+	clientId string, responseMode string, redirectURI string, values url.Values) {
+	if oauth.IsJwtResponseMode(responseMode) {
+		keyPair, err := database.GetCurrentSigningKey(nil)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		values.Set("iss", settings.Issuer)
+		response, err := oauth.GenerateAuthorizationResponseJwt(keyPair, settings.Issuer, clientId, values)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		client, err := database.GetClientByClientIdentifier(nil, clientId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		// the signed response is nested in a JWE (JARM, section 2.3)
+		if client != nil && client.EncryptsAuthorizationResponse() {
+			if response, err = oauth.EncryptForClient(settings, client, client.AuthorizationEncryptedResponseAlg,
+				client.AuthorizationEncryptedResponseEnc, response); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+		}
+
+		values = url.Values{"response": {response}}
+		responseMode = oauth.GetJwtResponseModeTransport(responseMode)
+	}
+
+	switch responseMode {
+	case "form_post":
+		params := map[string]string{}
+		for key := range values {
+			params[key] = values.Get(key)
+		}
+
+		bind := map[string]interface{}{
+			"redirectURI": redirectURI,
+			"params":      params,
+		}
+		if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/form_post.html", bind); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+		}
+	case "fragment":
+		http.Redirect(w, r, redirectURI+"#"+values.Encode(), http.StatusFound)
+	default:
+		separator := "?"
+		if strings.Contains(redirectURI, "?") {
+			separator = "&"
+		}
+		http.Redirect(w, r, redirectURI+separator+values.Encode(), http.StatusFound)
+	}
+}
+
+// getAuthContextOrRenderError loads the auth context from the session
+// and checks it is in one of the expected states
+func getAuthContextOrRenderError(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper,
+	authHelper AuthHelper, expectedStates ...string) (*oauth.AuthContext, bool) {
+	authContext, err := authHelper.GetAuthContext(r)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNoAuthContext) {
+			renderErrorPage(w, r, httpHelper, "The authentication request was not found or has expired. Please start over.")
+		} else {
+			httpHelper.InternalServerError(w, r, err)
+		}
+		return nil, false
+	}
+
+	for _, state := range expectedStates {
+		if authContext.AuthState == state {
+			return authContext, true
+		}
+	}
+
+	renderErrorPage(w, r, httpHelper, "The authentication request is in an unexpected state. Please start over.")
+	return nil, false
+}
+
+// startAuthentication saves the auth context and sends the user to the first level of
+// authentication, where a still valid user session can be reused instead of asking for the password.
+// With prompt=login the session is never reused, with prompt=select_account the user picks between
+// the accounts of all the sessions of the browser and another one, and with prompt=none there is no user interaction at all.
+func startAuthentication(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, authHelper AuthHelper,
+	userSessionManager UserSessionManager, sessionStore sessions.Store, database database.Database,
+	client *models.Client, authContext *oauth.AuthContext) {
+	sess, err := sessionStore.Get(r, constants.SessionName)
+	if err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return
+	}
+
+	authContext.AuthState = oauth.AuthStateRequiresLevel1
+	if authContext.HasPrompt(oidc.PromptSelectAccount) && !authContext.HasPrompt(oidc.PromptLogin) {
+		userSessions, err := getSelectableUserSessions(r, sess, userSessionManager, database, authContext)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if len(userSessions) > 0 {
+			authContext.AuthState = oauth.AuthStateRequiresAccountSelection
+		}
+	} else if sess.Values[constants.SessionKeySessionIdentifier] != nil && !authContext.HasPrompt(oidc.PromptLogin) {
+		sessionIdentifier := sess.Values[constants.SessionKeySessionIdentifier].(string)
+		userSession, err := database.GetUserSessionBySessionIdentifier(nil, sessionIdentifier)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		requestedMaxAge := authContext.ParseRequestedMaxAge()
+		if userSessionManager.HasValidUserSession(r.Context(), userSession, requestedMaxAge) {
+			isHintedUser, err := isHintedUser(database, authContext, userSession.UserId)
+			if err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			if isHintedUser {
+				authContext.UserId = userSession.UserId
+				authContext.AuthState = oauth.AuthStateLevel1ExistingSession
+				if err = authContext.SetAcrLevel(authContext.GetTargetAcrLevel(client.DefaultAcrLevel), userSession); err != nil {
+					httpHelper.InternalServerError(w, r, err)
+					return
+				}
+			}
+		}
+	}
+
+	if authContext.HasPrompt(oidc.PromptNone) && authContext.AuthState == oauth.AuthStateRequiresLevel1 {
+		redirToClientWithError(w, r, httpHelper, database, "login_required",
+			"The user is not authenticated and prompt=none does not allow to display a login page.",
+			authContext.ClientId, authContext.ResponseMode, authContext.RedirectURI, authContext.State)
+		return
+	}
+
+	if err = authHelper.SaveAuthContext(w, r, authContext); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return
+	}
+
+	if authContext.AuthState == oauth.AuthStateRequiresAccountSelection {
+		http.Redirect(w, r, "/auth/selectaccount", http.StatusFound)
+		return
+	}
+
+	http.Redirect(w, r, "/auth/level1", http.StatusFound)
+}
+
+// isHintedUser tells if the user of a session matches the id_token_hint and login_hint
+// of the request. The login_hint is compared with the email address of the user.
+func isHintedUser(database database.Database, authContext *oauth.AuthContext, userId int64) (bool, error) {
+	if !authContext.IsExpectedUser(userId) {
+		return false, nil
+	} else if len(authContext.LoginHint) == 0 || authContext.HintedUserId > 0 {
+		return true, nil
+	}
+
+	user, err := database.GetUserById(nil, userId)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && strings.EqualFold(user.Email, authContext.LoginHint), nil
+}
+
+// requireInteraction ends a request with prompt=none that needs the user to interact,
+// it returns false when the authentication can go on
+func requireInteraction(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, database database.Database,
+	cibaPingNotifier CIBAPingNotifier, authContext *oauth.AuthContext, code string, description string) bool {
+	if !authContext.HasPrompt(oidc.PromptNone) {
+		return false
+	}
+
+	denyAuthorization(w, r, httpHelper, database, cibaPingNotifier, authContext, code, description)
+	return true
+}
+
+// denyAuthorization ends the authorization with an error. Device and CIBA flows have no redirect URI:
+// the request is marked as denied so the polling client receives access_denied instead.
+func denyAuthorization(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, database database.Database,
+	cibaPingNotifier CIBAPingNotifier, authContext *oauth.AuthContext, code string, description string) {
+	if authContext.IsCIBAFlow() {
+		cibaRequest, err := database.GetCIBARequestById(nil, authContext.CIBARequestId)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if cibaRequest != nil && cibaRequest.Status == enums.CIBARequestStatusPending.String() {
+			cibaRequest.Status = enums.CIBARequestStatusDenied.String()
+			if err = completeCIBARequest(r, database, cibaPingNotifier, cibaRequest); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+		}
+
+		renderDeviceCompletedPage(w, r, httpHelper, "Access denied", description)
+		return
+	}
+
+	if !authContext.IsDeviceFlow() {
+		redirToClientWithError(w, r, httpHelper, database, code, description,
+			authContext.ClientId, authContext.ResponseMode, authContext.RedirectURI, authContext.State)
+		return
+	}
+
+	deviceCode, err := database.GetDeviceCodeById(nil, authContext.DeviceCodeId)
+	if err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return
+	}
+
+	if deviceCode != nil && deviceCode.Status == enums.DeviceCodeStatusPending.String() {
+		deviceCode.Status = enums.DeviceCodeStatusDenied.String()
+		if err = database.UpdateDeviceCode(nil, deviceCode); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+	}
+
+	renderDeviceCompletedPage(w, r, httpHelper, "Access denied", description)
+}
+
+func renderDeviceCompletedPage(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, title string, message string) {
+	bind := map[string]interface{}{
+		"title":   title,
+		"message": message,
+	}
+
+	if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/device_completed.html", bind); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+	}
+}
+
+// getClientCredentials reads the client credentials from the basic auth
+// header (client_secret_basic) or from the form post (client_secret_post)
+func getClientCredentials(r *http.Request) (clientId string, clientSecret string) {
+	if clientId, clientSecret, ok := r.BasicAuth(); ok {
+		if unescaped, err := url.QueryUnescape(clientId); err == nil {
+			clientId = unescaped
+		}
+
+		if unescaped, err := url.QueryUnescape(clientSecret); err == nil {
+			clientSecret = unescaped
+		}
+
+		return clientId, clientSecret
+	}
+
+	return r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+}
+
+// authenticateClient authenticates a confidential client with its secret, a client assertion or its TLS certificate.
+// Public clients, when allowed, are only identified by their client_id.
+func authenticateClient(r *http.Request, database database.Database, allowPublicClient bool) (*models.Client, error) {
+	clientId, clientSecret := getClientCredentials(r)
+	clientAssertionType, clientAssertion := r.PostFormValue("client_assertion_type"), r.PostFormValue("client_assertion")
+	if len(clientAssertionType) > 0 || len(clientAssertion) > 0 {
+		if len(clientSecret) > 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Only one client authentication method can be used.", http.StatusBadRequest)
+		}
+
+		return validators.NewClientAssertionValidator(database).ValidateClientAssertion(r.Context(), &validators.ValidateClientAssertionInput{
+			ClientId:            clientId,
+			ClientAssertionType: clientAssertionType,
+			ClientAssertion:     clientAssertion,
+		})
+	}
+
+	if len(clientId) == 0 {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+			"Client authentication is required.", http.StatusUnauthorized)
+	}
+
+	client, err := database.GetClientByClientIdentifier(nil, clientId)
+	if err != nil {
+		return nil, err
+	} else if client == nil || !client.Enabled {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+			"Client authentication failed.", http.StatusUnauthorized)
+	}
+
+	if client.IsPublic {
+		if !allowPublicClient || len(clientSecret) > 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+				"Client authentication failed.", http.StatusUnauthorized)
+		}
+		return client, nil
+	}
+
+	if client.UsesTLSClientAuth() {
+		if len(clientSecret) > 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Only one client authentication method can be used.", http.StatusBadRequest)
+		}
+
+		if err = validators.NewClientCertificateValidator().ValidateClientCertificate(client, oauth.GetClientCertificates(r)); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+
+	if len(clientSecret) == 0 || client.UsesClientAssertion() {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+			"Client authentication is required.", http.StatusUnauthorized)
+	}
+
+	settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+	clientSecretDecrypted, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+	if err != nil {
+		return nil, err
+	} else if clientSecretDecrypted != clientSecret {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+			"Client authentication failed.", http.StatusUnauthorized)
+	}
+
+	return client, nil
+}
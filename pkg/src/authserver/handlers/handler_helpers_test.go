@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirToClientWithCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseMode string
+		redirectURI  string
+		expected     string
+	}{
+		{"query", "query", "https://example.com/cb", "https://example.com/cb?code=abc&state=xyz"},
+		{"query with existing params", "", "https://example.com/cb?a=1", "https://example.com/cb?a=1&code=abc&state=xyz"},
+		{"fragment", "fragment", "https://example.com/cb", "https://example.com/cb#code=abc&state=xyz"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			httpHelper := helpersMocks.NewHttpHelper(t)
+			rr := httptest.NewRecorder()
+			redirToClientWithCode(rr, httptest.NewRequest("GET", "/auth/issue", nil), httpHelper, nil,
+				"abc", "test-client", tc.responseMode, tc.redirectURI, "xyz")
+
+			assert.Equal(t, http.StatusFound, rr.Code)
+			assert.Equal(t, tc.expected, rr.Header().Get("Location"))
+		})
+	}
+}
+
+func TestRedirToClientWithError_FormPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/form_post.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			params := data["params"].(map[string]string)
+			_, hasState := params["state"]
+			return data["redirectURI"] == "https://example.com/cb" &&
+				params["error"] == "access_denied" && !hasState
+		})).Return(nil)
+
+	rr := httptest.NewRecorder()
+	redirToClientWithError(rr, httptest.NewRequest("GET", "/auth/consent", nil), httpHelper, nil,
+		"access_denied", "The user did not provide consent.", "test-client", "form_post", "https://example.com/cb", "")
+
+	httpHelper.AssertExpectations(t)
+	assert.Empty(t, rr.Header().Get("Location"))
+}
+
+func TestRedirToClientWithCode_JwtResponseMode(t *testing.T) {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		responseMode string
+		prefix       string
+	}{
+		{"jwt", "jwt", "https://example.com/cb?response="},
+		{"query.jwt", "query.jwt", "https://example.com/cb?response="},
+		{"fragment.jwt", "fragment.jwt", "https://example.com/cb#response="},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			httpHelper := helpersMocks.NewHttpHelper(t)
+			database := mocks.NewDatabase(t)
+			database.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+				KeyIdentifier: keyPair.KeyIdentifier,
+				PrivateKeyPEM: keyPair.PrivateKeyPEM,
+			}, nil)
+			database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{ClientIdentifier: "test-client"}, nil)
+
+			req := httptest.NewRequest("GET", "/auth/issue", nil)
+			req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{Issuer: "https://auth.example.com"}))
+			rr := httptest.NewRecorder()
+			redirToClientWithCode(rr, req, httpHelper, database, "abc", "test-client", tc.responseMode, "https://example.com/cb", "xyz")
+
+			assert.Equal(t, http.StatusFound, rr.Code)
+			location := rr.Header().Get("Location")
+			require.True(t, strings.HasPrefix(location, tc.prefix))
+
+			claims := jwt.MapClaims{}
+			_, err := jwt.ParseWithClaims(strings.TrimPrefix(location, tc.prefix), claims, func(token *jwt.Token) (interface{}, error) {
+				return privateKey.(*ecdsa.PrivateKey).Public(), nil
+			}, jwt.WithIssuer("https://auth.example.com"), jwt.WithAudience("test-client"), jwt.WithExpirationRequired())
+			require.NoError(t, err)
+			assert.Equal(t, "abc", claims["code"])
+			assert.Equal(t, "xyz", claims["state"])
+		})
+	}
+}
+
+func TestRedirToClientWithCode_EncryptedJwtResponseMode(t *testing.T) {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+
+	settings := &models.Settings{Issuer: "https://auth.example.com", AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef")}
+	clientSecretEncrypted, err := encryption.EncryptText("client-secret", settings.AESEncryptionKey)
+	require.NoError(t, err)
+
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	database.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+		KeyIdentifier: keyPair.KeyIdentifier,
+		PrivateKeyPEM: keyPair.PrivateKeyPEM,
+	}, nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{
+		ClientIdentifier:                  "test-client",
+		ClientSecretEncrypted:             clientSecretEncrypted,
+		AuthorizationEncryptedResponseAlg: encryption.JWEAlgorithmDir,
+		AuthorizationEncryptedResponseEnc: encryption.JWEEncryptionA128CBCHS256,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/auth/issue", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeySettings, settings))
+	rr := httptest.NewRecorder()
+	redirToClientWithCode(rr, req, httpHelper, database, "abc", "test-client", "query.jwt", "https://example.com/cb", "xyz")
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location, err := url.Parse(rr.Header().Get("Location"))
+	require.NoError(t, err)
+
+	key, err := encryption.DeriveClientSecretKey("client-secret", encryption.JWEAlgorithmDir, encryption.JWEEncryptionA128CBCHS256)
+	require.NoError(t, err)
+	signedJwt, header, err := encryption.DecryptJWE(location.Query().Get("response"), key)
+	require.NoError(t, err)
+	assert.Equal(t, "JWT", header.ContentType)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(string(signedJwt), claims, func(token *jwt.Token) (interface{}, error) {
+		return privateKey.(*ecdsa.PrivateKey).Public(), nil
+	}, jwt.WithIssuer("https://auth.example.com"), jwt.WithAudience("test-client"), jwt.WithExpirationRequired())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", claims["code"])
+}
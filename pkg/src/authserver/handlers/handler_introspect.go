@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleIntrospectPost(httpHelper HttpHelper, database database.Database, tokenParser TokenParser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		client, err := authenticateClient(r, database, false)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tokenStr := r.PostFormValue("token")
+		if len(tokenStr) == 0 {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Missing required token parameter.", http.StatusBadRequest))
+			return
+		}
+
+		// the token type is taken from the typ claim, so token_type_hint is not needed;
+		// expiration is checked below against the claim or the database record
+		token, err := tokenParser.DecodeAndValidateTokenString(tokenStr, nil, false)
+		if err != nil {
+			httpHelper.EncodeJson(w, r, oauth.IntrospectionResponse{Active: false})
+			return
+		}
+
+		var resp *oauth.IntrospectionResponse
+		switch token.GetStringClaim("typ") {
+		case enums.TokenTypeBearer.String():
+			resp, err = introspectAccessToken(database, client, token)
+		case "Refresh", "Offline":
+			resp, err = introspectRefreshToken(database, client, token)
+		}
+
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if resp == nil {
+			resp = &oauth.IntrospectionResponse{Active: false}
+		}
+
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+// introspectAccessToken returns nil if the access token is expired, its user
+// is no longer available or the client is not allowed to see it
+func introspectAccessToken(database database.Database, client *models.Client, token *oauth.Jwt) (*oauth.IntrospectionResponse, error) {
+	if !token.GetTimeClaim("exp").After(time.Now().UTC()) {
+		return nil, nil
+	}
+
+	clientId := token.GetStringClaim("client_id")
+	audience := token.GetAudience()
+	if clientId != client.ClientIdentifier {
+		// a resource server can only see tokens issued for the resources it has access to
+		if err := database.ClientLoadPermissions(nil, client); err != nil {
+			return nil, err
+		}
+
+		if err := database.PermissionsLoadResources(nil, client.Permissions); err != nil {
+			return nil, err
+		}
+
+		permitted := false
+		for _, permission := range client.Permissions {
+			if slices.Contains(audience, permission.Resource.ResourceIdentifier) {
+				permitted = true
+				break
+			}
+		}
+
+		if !permitted {
+			return nil, nil
+		}
+	}
+
+	if active, err := isAccessTokenUserActive(database, client, token); err != nil || !active {
+		return nil, err
+	}
+
+	// DPoP-bound tokens must be presented with a proof of their key
+	tokenType := enums.TokenTypeBearer.String()
+	jkt := token.GetConfirmationClaim(oauth.ConfirmationMethodJkt)
+	if len(jkt) > 0 {
+		tokenType = enums.TokenTypeDPoP.String()
+	}
+
+	return &oauth.IntrospectionResponse{
+		Active:       true,
+		Scope:        token.GetStringClaim("scope"),
+		ClientId:     clientId,
+		Subject:      token.GetStringClaim("sub"),
+		Audience:     audience,
+		Issuer:       token.GetStringClaim("iss"),
+		TokenType:    tokenType,
+		Jti:          token.GetStringClaim("jti"),
+		ExpiresAt:    token.GetTimeClaim("exp").Unix(),
+		IssuedAt:     token.GetTimeClaim("iat").Unix(),
+		Confirmation: getConfirmation(token.GetConfirmationClaim(oauth.ConfirmationMethodX5tS256), jkt),
+	}, nil
+}
+
+// isAccessTokenUserActive resolves the sub claim of an access token issued to a user,
+// which is pairwise when the client of the token belongs to a sector, and checks the user is enabled.
+// The sub claim of a client credentials token is the client itself.
+func isAccessTokenUserActive(database database.Database, client *models.Client, token *oauth.Jwt) (bool, error) {
+	clientId := token.GetStringClaim("client_id")
+	subject := token.GetStringClaim("sub")
+	if subject == clientId {
+		return true, nil
+	}
+
+	tokenClient := client
+	if clientId != client.ClientIdentifier {
+		var err error
+		if tokenClient, err = database.GetClientByClientIdentifier(nil, clientId); err != nil {
+			return false, err
+		}
+	}
+
+	user, err := oauth.ResolveSubject(database, tokenClient, subject)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && user.Enabled, nil
+}
+
+// introspectRefreshToken returns nil if the refresh token is unknown, revoked,
+// expired or was not issued to the client
+func introspectRefreshToken(database database.Database, client *models.Client, token *oauth.Jwt) (*oauth.IntrospectionResponse, error) {
+	refreshToken, err := database.GetRefreshTokenByJti(nil, token.GetStringClaim("jti"))
+	if err != nil {
+		return nil, err
+	} else if refreshToken == nil || refreshToken.Revoked {
+		return nil, nil
+	} else if !refreshToken.ExpiresAt.Valid || !refreshToken.ExpiresAt.Time.After(time.Now().UTC()) {
+		return nil, nil
+	}
+
+	if err = database.RefreshTokenLoadCode(nil, refreshToken); err != nil {
+		return nil, err
+	} else if refreshToken.Code.ClientId != client.Id {
+		return nil, nil
+	}
+
+	return &oauth.IntrospectionResponse{
+		Active:       true,
+		Scope:        refreshToken.Scope,
+		ClientId:     client.ClientIdentifier,
+		Subject:      token.GetStringClaim("sub"),
+		Audience:     token.GetAudience(),
+		Issuer:       token.GetStringClaim("iss"),
+		TokenType:    refreshToken.RefreshTokenType,
+		Jti:          refreshToken.RefreshTokenJti,
+		ExpiresAt:    refreshToken.ExpiresAt.Time.Unix(),
+		IssuedAt:     refreshToken.IssuedAt.Time.Unix(),
+		Confirmation: getConfirmation(token.GetConfirmationClaim(oauth.ConfirmationMethodX5tS256), refreshToken.DPoPJkt),
+	}, nil
+}
+
+// getConfirmation returns the cnf member of the introspection response, so resource servers
+// can check the certificate and the DPoP key the token is bound to (RFC 9449, section 6.2)
+func getConfirmation(certificateThumbprint string, jkt string) map[string]string {
+	cnf := make(map[string]string)
+	if len(certificateThumbprint) > 0 {
+		cnf[oauth.ConfirmationMethodX5tS256] = certificateThumbprint
+	}
+	if len(jkt) > 0 {
+		cnf[oauth.ConfirmationMethodJkt] = jkt
+	}
+
+	if len(cnf) == 0 {
+		return nil
+	}
+	return cnf
+}
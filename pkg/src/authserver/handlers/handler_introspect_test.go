@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	mocksOAuth "github.com/pchchv/aas/pkg/src/oauth/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testAESEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newIntrospectRequest(t *testing.T, form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/auth/introspect", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("resource-server", "secret")
+	ctx := context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{AESEncryptionKey: testAESEncryptionKey})
+	return req.WithContext(ctx)
+}
+
+func newIntrospectClient(t *testing.T, id int64, clientIdentifier string) *models.Client {
+	clientSecretEncrypted, err := encryption.EncryptText("secret", testAESEncryptionKey)
+	assert.NoError(t, err)
+	return &models.Client{
+		Id:                    id,
+		ClientIdentifier:      clientIdentifier,
+		ClientSecretEncrypted: clientSecretEncrypted,
+		Enabled:               true,
+	}
+}
+
+func TestHandleIntrospectPost_InvalidClientSecret(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+
+	client := newIntrospectClient(t, 1, "resource-server")
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(client, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == "invalid_client"
+	})).Return()
+
+	req := newIntrospectRequest(t, url.Values{"token": {"some-token"}})
+	req.SetBasicAuth("resource-server", "wrong-secret")
+	handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	tokenParser.AssertNotCalled(t, "DecodeAndValidateTokenString", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleIntrospectPost_InvalidToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+	tokenParser.On("DecodeAndValidateTokenString", "garbage", mock.Anything, false).Return(nil, errors.New("token is malformed"))
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, oauth.IntrospectionResponse{Active: false}).Return()
+
+	handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIntrospectRequest(t, url.Values{"token": {"garbage"}}))
+
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+}
+
+func TestHandleIntrospectPost_AccessTokenForPermittedAudience(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	token := &oauth.Jwt{Claims: map[string]interface{}{
+		"typ":       "Bearer",
+		"client_id": "web-app",
+		"sub":       "user-subject",
+		"aud":       []interface{}{"orders", "billing"},
+		"scope":     "orders:read",
+		"jti":       "jti-1",
+		"exp":       float64(exp),
+	}}
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+	tokenParser.On("DecodeAndValidateTokenString", "access-token", mock.Anything, false).Return(token, nil)
+	database.On("ClientLoadPermissions", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		client := args.Get(1).(*models.Client)
+		client.Permissions = []models.Permission{{Id: 1, ResourceId: 5}}
+	}).Return(nil)
+	database.On("PermissionsLoadResources", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		permissions := args.Get(1).([]models.Permission)
+		permissions[0].Resource = models.Resource{Id: 5, ResourceIdentifier: "orders"}
+	}).Return(nil)
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(&models.Client{Id: 2, ClientIdentifier: "web-app"}, nil)
+	database.On("GetUserBySubject", mock.Anything, "user-subject").Return(&models.User{Id: 3, Enabled: true}, nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp *oauth.IntrospectionResponse) bool {
+		return resp.Active && resp.ClientId == "web-app" && resp.Subject == "user-subject" &&
+			resp.Scope == "orders:read" && resp.TokenType == "Bearer" && resp.ExpiresAt == exp &&
+			assert.ObjectsAreEqual([]string{"orders", "billing"}, resp.Audience)
+	})).Return()
+
+	handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIntrospectRequest(t, url.Values{"token": {"access-token"}}))
+}
+
+func TestHandleIntrospectPost_AccessTokenForOtherAudience(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+
+	token := &oauth.Jwt{Claims: map[string]interface{}{
+		"typ":       "Bearer",
+		"client_id": "web-app",
+		"aud":       "billing",
+		"exp":       float64(time.Now().Add(time.Hour).Unix()),
+	}}
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+	tokenParser.On("DecodeAndValidateTokenString", "access-token", mock.Anything, false).Return(token, nil)
+	database.On("ClientLoadPermissions", mock.Anything, mock.Anything).Return(nil)
+	database.On("PermissionsLoadResources", mock.Anything, mock.Anything).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, &oauth.IntrospectionResponse{Active: false}).Return()
+
+	handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIntrospectRequest(t, url.Values{"token": {"access-token"}}))
+}
+
+func TestHandleIntrospectPost_AccessTokenWithPairwiseSubject(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   *models.User
+		active bool
+	}{
+		{name: "Enabled user", user: &models.User{Id: 3, Enabled: true}, active: true},
+		{name: "Disabled user", user: &models.User{Id: 3, Enabled: false}, active: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpHelper := helpersMocks.NewHttpHelper(t)
+			database := mocks.NewDatabase(t)
+			tokenParser := mocksOAuth.NewTokenParser(t)
+
+			token := &oauth.Jwt{Claims: map[string]interface{}{
+				"typ":       "Bearer",
+				"client_id": "resource-server",
+				"sub":       "pairwise-subject",
+				"aud":       "orders",
+				"exp":       float64(time.Now().Add(time.Hour).Unix()),
+			}}
+
+			client := newIntrospectClient(t, 1, "resource-server")
+			client.SubjectType = constants.SubjectTypePairwise
+			client.SectorIdentifier = "rs.example.com"
+			database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(client, nil)
+			tokenParser.On("DecodeAndValidateTokenString", "access-token", mock.Anything, false).Return(token, nil)
+			database.On("GetPairwiseSubjectBySectorIdentifierAndSubject", mock.Anything, "rs.example.com", "pairwise-subject").
+				Return(&models.PairwiseSubject{UserId: 3, SectorIdentifier: "rs.example.com", Subject: "pairwise-subject"}, nil)
+			database.On("GetUserById", mock.Anything, int64(3)).Return(tt.user, nil)
+			httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp *oauth.IntrospectionResponse) bool {
+				return resp.Active == tt.active && (!tt.active || resp.Subject == "pairwise-subject")
+			})).Return()
+
+			handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newIntrospectRequest(t, url.Values{"token": {"access-token"}}))
+		})
+	}
+}
+
+func TestHandleIntrospectPost_ExpiredAccessToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+
+	token := &oauth.Jwt{Claims: map[string]interface{}{
+		"typ":       "Bearer",
+		"client_id": "resource-server",
+		"exp":       float64(time.Now().Add(-time.Minute).Unix()),
+	}}
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+	tokenParser.On("DecodeAndValidateTokenString", "access-token", mock.Anything, false).Return(token, nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, &oauth.IntrospectionResponse{Active: false}).Return()
+
+	handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIntrospectRequest(t, url.Values{"token": {"access-token"}}))
+}
+
+func TestHandleIntrospectPost_RefreshToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		refreshToken *models.RefreshToken
+		codeClientId int64
+		active       bool
+	}{
+		{
+			name:         "active",
+			refreshToken: &models.RefreshToken{RefreshTokenJti: "jti-1", RefreshTokenType: "Offline", Scope: "openid offline_access", ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}},
+			codeClientId: 1,
+			active:       true,
+		},
+		{
+			name:         "revoked",
+			refreshToken: &models.RefreshToken{RefreshTokenJti: "jti-1", Revoked: true, ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}},
+		},
+		{
+			name:         "expired",
+			refreshToken: &models.RefreshToken{RefreshTokenJti: "jti-1", ExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}},
+		},
+		{
+			name:         "issued to another client",
+			refreshToken: &models.RefreshToken{RefreshTokenJti: "jti-1", ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}},
+			codeClientId: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpHelper := helpersMocks.NewHttpHelper(t)
+			database := mocks.NewDatabase(t)
+			tokenParser := mocksOAuth.NewTokenParser(t)
+
+			token := &oauth.Jwt{Claims: map[string]interface{}{"typ": "Offline", "jti": "jti-1", "sub": "user-subject"}}
+			database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+			tokenParser.On("DecodeAndValidateTokenString", "refresh-token", mock.Anything, false).Return(token, nil)
+			database.On("GetRefreshTokenByJti", mock.Anything, "jti-1").Return(tt.refreshToken, nil)
+			if tt.codeClientId != 0 {
+				database.On("RefreshTokenLoadCode", mock.Anything, tt.refreshToken).Run(func(args mock.Arguments) {
+					args.Get(1).(*models.RefreshToken).Code = models.Code{ClientId: tt.codeClientId}
+				}).Return(nil)
+			}
+
+			httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp *oauth.IntrospectionResponse) bool {
+				if !tt.active {
+					return !resp.Active && resp.Scope == ""
+				}
+				return resp.Active && resp.ClientId == "resource-server" && resp.TokenType == "Offline" &&
+					resp.Scope == "openid offline_access" && resp.Subject == "user-subject"
+			})).Return()
+
+			handler := HandleIntrospectPost(httpHelper, database, tokenParser)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newIntrospectRequest(t, url.Values{"token": {"refresh-token"}, "token_type_hint": {"refresh_token"}}))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleIssueGet(
+	httpHelper HttpHelper,
+	authHelper AuthHelper,
+	sessionStore sessions.Store,
+	database database.Database,
+	codeIssuer CodeIssuer,
+	cibaPingNotifier CIBAPingNotifier,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authContext, ok := getAuthContextOrRenderError(w, r, httpHelper, authHelper, oauth.AuthStateReadyToIssueCode)
+		if !ok {
+			return
+		}
+
+		var deviceCode *models.DeviceCode
+		if authContext.IsDeviceFlow() {
+			if deviceCode, ok = getPendingDeviceCode(w, r, httpHelper, database, authContext.DeviceCodeId); !ok {
+				return
+			}
+		}
+
+		var cibaRequest *models.CIBARequest
+		if authContext.IsCIBAFlow() {
+			if cibaRequest, ok = getPendingCIBARequest(w, r, httpHelper, database, authContext.CIBARequestId); !ok {
+				return
+			}
+		}
+
+		sess, err := sessionStore.Get(r, constants.SessionName)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		sessionIdentifier, _ := sess.Values[constants.SessionKeySessionIdentifier].(string)
+		code, err := codeIssuer.CreateAuthCode(&oauth.CreateCodeInput{
+			AuthContext:       *authContext,
+			SessionIdentifier: sessionIdentifier,
+		})
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditCreatedAuthCode, map[string]interface{}{
+			"userId":   authContext.UserId,
+			"clientId": code.ClientId,
+			"codeId":   code.Id,
+		})
+
+		if err = authHelper.ClearAuthContext(w, r); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if deviceCode != nil {
+			// the device picks up the code on its next poll of the token endpoint
+			deviceCode.Status = enums.DeviceCodeStatusApproved.String()
+			deviceCode.CodeId = sql.NullInt64{Int64: code.Id, Valid: true}
+			if err = database.UpdateDeviceCode(nil, deviceCode); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			renderDeviceCompletedPage(w, r, httpHelper, "Device connected", "You can now return to your device.")
+			return
+		}
+
+		if cibaRequest != nil {
+			// the client polls the token endpoint, or fetches the tokens once pinged
+			cibaRequest.Status = enums.CIBARequestStatusApproved.String()
+			cibaRequest.CodeId = sql.NullInt64{Int64: code.Id, Valid: true}
+			if err = completeCIBARequest(r, database, cibaPingNotifier, cibaRequest); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			renderDeviceCompletedPage(w, r, httpHelper, "Request approved", "You can now close this page.")
+			return
+		}
+
+		redirToClientWithCode(w, r, httpHelper, database, code.Code,
+			authContext.ClientId, code.ResponseMode, code.RedirectURI, code.State)
+	}
+}
+
+// getPendingDeviceCode renders an error page when the device code was already
+// handled or has expired while the user was authenticating
+func getPendingDeviceCode(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper,
+	database database.Database, deviceCodeId int64) (*models.DeviceCode, bool) {
+	deviceCode, err := database.GetDeviceCodeById(nil, deviceCodeId)
+	if err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return nil, false
+	} else if deviceCode == nil || deviceCode.IsExpired() || deviceCode.Status != enums.DeviceCodeStatusPending.String() {
+		renderErrorPage(w, r, httpHelper, "The device code is no longer valid. Please start over on your device.")
+		return nil, false
+	}
+
+	return deviceCode, true
+}
+
+// getPendingCIBARequest renders an error page when the backchannel authentication
+// request was already handled or has expired while the user was authenticating
+func getPendingCIBARequest(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper,
+	database database.Database, cibaRequestId int64) (*models.CIBARequest, bool) {
+	cibaRequest, err := database.GetCIBARequestById(nil, cibaRequestId)
+	if err != nil {
+		httpHelper.InternalServerError(w, r, err)
+		return nil, false
+	} else if cibaRequest == nil || cibaRequest.IsExpired() || cibaRequest.Status != enums.CIBARequestStatusPending.String() {
+		renderErrorPage(w, r, httpHelper, "The sign-in request is no longer valid. Please ask the application to send a new one.")
+		return nil, false
+	}
+
+	return cibaRequest, true
+}
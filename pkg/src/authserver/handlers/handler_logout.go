@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+type logoutRequest struct {
+	ClientId              string
+	PostLogoutRedirectURI string
+	State                 string
+	IdToken               *oauth.Jwt
+	// HintedUserId is the user the sub claim of the id_token_hint resolves to
+	HintedUserId int64
+}
+
+// HandleLogoutGet handles RP-initiated logout (OpenID Connect RP-Initiated Logout 1.0).
+// Signing out ends every session of the browser. They are ended right away when the browser
+// only has the session of the user of the id_token_hint, otherwise the user is asked to confirm.
+func HandleLogoutGet(
+	httpHelper HttpHelper,
+	sessionStore sessions.Store,
+	database database.Database,
+	tokenParser TokenParser,
+	logoutNotifier LogoutNotifier,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logoutReq, err := getLogoutRequest(database, tokenParser, r.URL.Query())
+		if err != nil {
+			if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+				renderLogoutErrorPage(w, r, httpHelper, errorDetail.GetDescription())
+			} else {
+				httpHelper.InternalServerError(w, r, err)
+			}
+			return
+		}
+
+		userSessions, err := getBrowserUserSessions(r, sessionStore, database)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		if len(userSessions) > 0 {
+			if len(userSessions) > 1 || !userSessions[0].active || logoutReq.HintedUserId == 0 ||
+				logoutReq.HintedUserId != userSessions[0].UserId {
+				emails := []string{}
+				for _, userSession := range userSessions {
+					emails = append(emails, userSession.User.Email)
+				}
+
+				bind := map[string]interface{}{
+					"emails":                emails,
+					"clientId":              logoutReq.ClientId,
+					"postLogoutRedirectURI": logoutReq.PostLogoutRedirectURI,
+					"state":                 logoutReq.State,
+					"csrfField":             csrf.TemplateField(r),
+				}
+
+				if err = httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/logout.html", bind); err != nil {
+					httpHelper.InternalServerError(w, r, err)
+				}
+				return
+			}
+		}
+
+		logout(w, r, httpHelper, sessionStore, database, logoutNotifier, auditLogger, logoutReq, userSessions)
+	}
+}
+
+// HandleLogoutPost ends the sessions of the browser once the user confirmed the logout
+func HandleLogoutPost(
+	httpHelper HttpHelper,
+	sessionStore sessions.Store,
+	database database.Database,
+	tokenParser TokenParser,
+	logoutNotifier LogoutNotifier,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		// the confirmation form carries no id_token_hint, the redirect URI is validated again
+		values := url.Values{
+			"client_id":                {r.PostForm.Get("client_id")},
+			"post_logout_redirect_uri": {r.PostForm.Get("post_logout_redirect_uri")},
+			"state":                    {r.PostForm.Get("state")},
+		}
+		logoutReq, err := getLogoutRequest(database, tokenParser, values)
+		if err != nil {
+			if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+				renderLogoutErrorPage(w, r, httpHelper, errorDetail.GetDescription())
+			} else {
+				httpHelper.InternalServerError(w, r, err)
+			}
+			return
+		}
+
+		userSessions, err := getBrowserUserSessions(r, sessionStore, database)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		logout(w, r, httpHelper, sessionStore, database, logoutNotifier, auditLogger, logoutReq, userSessions)
+	}
+}
+
+// getLogoutRequest validates the logout parameters. A post_logout_redirect_uri is only
+// accepted when it is registered for the client, which is identified by the
+// client_id parameter or the audience of the id_token_hint.
+func getLogoutRequest(database database.Database, tokenParser TokenParser, values url.Values) (*logoutRequest, error) {
+	logoutReq := &logoutRequest{
+		ClientId:              strings.TrimSpace(values.Get("client_id")),
+		PostLogoutRedirectURI: strings.TrimSpace(values.Get("post_logout_redirect_uri")),
+		State:                 values.Get("state"),
+	}
+
+	// the id token is usually expired by the time the user logs out
+	if idTokenHint := values.Get("id_token_hint"); len(idTokenHint) > 0 {
+		idToken, err := tokenParser.DecodeAndValidateTokenString(idTokenHint, nil, false)
+		if err != nil || idToken == nil || idToken.GetStringClaim("typ") != enums.TokenTypeId.String() {
+			return nil, customerrors.NewErrorDetail("", "The id_token_hint parameter is invalid.")
+		}
+
+		audience := idToken.GetAudience()
+		if len(logoutReq.ClientId) == 0 && len(audience) > 0 {
+			logoutReq.ClientId = audience[0]
+		} else if !slices.Contains(audience, logoutReq.ClientId) {
+			return nil, customerrors.NewErrorDetail("", "The id_token_hint was not issued to the client_id parameter.")
+		}
+		logoutReq.IdToken = idToken
+	}
+
+	if len(logoutReq.PostLogoutRedirectURI) > 0 && len(logoutReq.ClientId) == 0 {
+		return nil, customerrors.NewErrorDetail("", "The client_id or id_token_hint parameter is required when a post_logout_redirect_uri is given.")
+	} else if logoutReq.IdToken == nil && len(logoutReq.PostLogoutRedirectURI) == 0 {
+		return logoutReq, nil
+	}
+
+	client, err := database.GetClientByClientIdentifier(nil, logoutReq.ClientId)
+	if err != nil {
+		return nil, err
+	} else if client == nil || !client.Enabled {
+		return nil, customerrors.NewErrorDetail("", "Invalid client_id parameter. The client does not exist or is disabled.")
+	}
+
+	if logoutReq.IdToken != nil {
+		// the sub claim is pairwise when the client belongs to a sector
+		user, err := oauth.ResolveSubject(database, client, logoutReq.IdToken.GetStringClaim("sub"))
+		if err != nil {
+			return nil, err
+		} else if user != nil {
+			logoutReq.HintedUserId = user.Id
+		}
+	}
+
+	if len(logoutReq.PostLogoutRedirectURI) == 0 {
+		return logoutReq, nil
+	}
+
+	if err = database.ClientLoadPostLogoutRedirectURIs(nil, client); err != nil {
+		return nil, err
+	}
+
+	for _, postLogoutRedirectURI := range client.PostLogoutRedirectURIs {
+		if postLogoutRedirectURI.URI == logoutReq.PostLogoutRedirectURI {
+			return logoutReq, nil
+		}
+	}
+
+	return nil, customerrors.NewErrorDetail("", "Invalid post_logout_redirect_uri parameter. The URI is not registered for the client.")
+}
+
+// browserUserSession is a user session of the browser, with its user and clients loaded
+type browserUserSession struct {
+	*models.UserSession
+	// active is set for the session the browser currently uses, the others are remembered
+	// to switch accounts (see SessionKeyOtherSessionIdentifiers)
+	active bool
+}
+
+// getBrowserUserSessions returns the user sessions of the browser, the active session first.
+// The sessions that no longer exist are skipped.
+func getBrowserUserSessions(r *http.Request, sessionStore sessions.Store, database database.Database) ([]browserUserSession, error) {
+	sess, err := sessionStore.Get(r, constants.SessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIdentifier, _ := sess.Values[constants.SessionKeySessionIdentifier].(string)
+	otherSessionIdentifiers, _ := sess.Values[constants.SessionKeyOtherSessionIdentifiers].(string)
+
+	userSessions := []browserUserSession{}
+	for _, identifier := range append([]string{sessionIdentifier}, strings.Fields(otherSessionIdentifiers)...) {
+		if len(identifier) == 0 {
+			continue
+		}
+
+		userSession, err := database.GetUserSessionBySessionIdentifier(nil, identifier)
+		if err != nil {
+			return nil, err
+		} else if userSession == nil {
+			continue
+		}
+
+		if err = database.UserSessionLoadUser(nil, userSession); err != nil {
+			return nil, err
+		}
+
+		if err = database.UserSessionLoadClients(nil, userSession); err != nil {
+			return nil, err
+		}
+
+		if err = database.UserSessionClientsLoadClients(nil, userSession.Clients); err != nil {
+			return nil, err
+		}
+
+		userSessions = append(userSessions, browserUserSession{
+			UserSession: userSession,
+			active:      identifier == sessionIdentifier,
+		})
+	}
+
+	return userSessions, nil
+}
+
+// logout ends the user sessions of the browser, revoking their refresh tokens, and notifies
+// the clients that took part in them: through the back-channel with a logout token, and
+// through the front-channel with iframes rendered on the page shown to the user
+func logout(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, sessionStore sessions.Store, database database.Database,
+	logoutNotifier LogoutNotifier, auditLogger AuditLogger, logoutReq *logoutRequest, userSessions []browserUserSession) {
+	settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+	frontChannelLogoutURIs := []string{}
+	if len(userSessions) > 0 {
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		for _, userSession := range userSessions {
+			if err = database.RevokeRefreshTokensBySessionIdentifier(tx, userSession.SessionIdentifier); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+
+			if err = database.DeleteUserSession(tx, userSession.Id); err != nil {
+				httpHelper.InternalServerError(w, r, err)
+				return
+			}
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		sess, err := sessionStore.Get(r, constants.SessionName)
+		if err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		delete(sess.Values, constants.SessionKeySessionIdentifier)
+		delete(sess.Values, constants.SessionKeyOtherSessionIdentifiers)
+		if err = sessionStore.Save(r, w, sess); err != nil {
+			httpHelper.InternalServerError(w, r, err)
+			return
+		}
+
+		var keyPair *models.KeyPair
+		notifications := []oauth.BackChannelLogoutNotification{}
+		for _, userSession := range userSessions {
+			auditLogger.Log(constants.AuditLogout, map[string]interface{}{
+				"userId":            userSession.UserId,
+				"sessionIdentifier": userSession.SessionIdentifier,
+				"clientId":          logoutReq.ClientId,
+			})
+
+			for _, sessionClient := range userSession.Clients {
+				client := sessionClient.Client
+				if len(client.FrontChannelLogoutURI) > 0 {
+					frontChannelLogoutURIs = append(frontChannelLogoutURIs,
+						getFrontChannelLogoutURI(client.FrontChannelLogoutURI, settings.Issuer, userSession.SessionIdentifier))
+				}
+
+				if len(client.BackChannelLogoutURI) == 0 {
+					continue
+				}
+
+				if keyPair == nil {
+					if keyPair, err = database.GetCurrentSigningKey(nil); err != nil {
+						httpHelper.InternalServerError(w, r, err)
+						return
+					}
+				}
+
+				subject, err := oauth.GetSubject(database, settings, &client, &userSession.User)
+				if err != nil {
+					httpHelper.InternalServerError(w, r, err)
+					return
+				}
+
+				logoutToken, err := oauth.GenerateLogoutToken(keyPair, settings.Issuer, client.ClientIdentifier,
+					subject, userSession.SessionIdentifier)
+				if err != nil {
+					httpHelper.InternalServerError(w, r, err)
+					return
+				}
+
+				notifications = append(notifications, oauth.BackChannelLogoutNotification{
+					ClientIdentifier: client.ClientIdentifier,
+					LogoutURI:        client.BackChannelLogoutURI,
+					LogoutToken:      logoutToken,
+				})
+			}
+		}
+
+		if len(notifications) > 0 {
+			logoutNotifier.NotifyBackChannelLogout(notifications)
+		}
+	}
+
+	redirectURI := ""
+	if len(logoutReq.PostLogoutRedirectURI) > 0 {
+		redirectURI = logoutReq.PostLogoutRedirectURI
+		if len(logoutReq.State) > 0 {
+			separator := "?"
+			if strings.Contains(redirectURI, "?") {
+				separator = "&"
+			}
+			redirectURI += separator + url.Values{"state": {logoutReq.State}}.Encode()
+		}
+	}
+
+	if len(frontChannelLogoutURIs) == 0 && len(redirectURI) > 0 {
+		http.Redirect(w, r, redirectURI, http.StatusFound)
+		return
+	}
+
+	// the page redirects once the front-channel logout iframes are loaded
+	bind := map[string]interface{}{
+		"frontChannelLogoutURIs": frontChannelLogoutURIs,
+		"redirectURI":            redirectURI,
+	}
+
+	if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/logout_completed.html", bind); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+	}
+}
+
+// getFrontChannelLogoutURI adds the iss and sid parameters, so the client
+// can tell which session ended (Front-Channel Logout, section 2)
+func getFrontChannelLogoutURI(frontChannelLogoutURI string, issuer string, sessionIdentifier string) string {
+	separator := "?"
+	if strings.Contains(frontChannelLogoutURI, "?") {
+		separator = "&"
+	}
+
+	return frontChannelLogoutURI + separator + url.Values{
+		"iss": {issuer},
+		"sid": {sessionIdentifier},
+	}.Encode()
+}
+
+func renderLogoutErrorPage(w http.ResponseWriter, r *http.Request, httpHelper HttpHelper, errorMessage string) {
+	bind := map[string]interface{}{
+		"title": "Unable to sign out",
+		"error": errorMessage,
+	}
+
+	if err := httpHelper.RenderTemplate(w, r, "/layouts/no_menu_layout.html", "/auth_error.html", bind); err != nil {
+		httpHelper.InternalServerError(w, r, err)
+	}
+}
@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/sessions"
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	mocksOAuth "github.com/pchchv/aas/pkg/src/oauth/mocks"
+	storeMocks "github.com/pchchv/aas/pkg/src/sqlstore/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var logoutUserSubject = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+func newLogoutRequest(method string, target string, body string) *http.Request {
+	var req *http.Request
+	if len(body) > 0 {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{Issuer: "https://auth.example.com"}))
+}
+
+func newLogoutIdToken(subject string) *oauth.Jwt {
+	return &oauth.Jwt{Claims: jwt.MapClaims{
+		"typ": enums.TokenTypeId.String(),
+		"aud": "test-client",
+		"sub": subject,
+	}}
+}
+
+func setupLogoutClient(database *mocks.Database) {
+	database.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1, ClientIdentifier: "test-client", Enabled: true}, nil)
+	database.On("ClientLoadPostLogoutRedirectURIs", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).PostLogoutRedirectURIs = []models.PostLogoutRedirectURI{{Id: 1, URI: "https://app.example.com/signed-out"}}
+	}).Return(nil)
+}
+
+// setupLogoutUserSession registers a browser session of the user, who signed in to a client
+// with a front-channel logout URI and a client with a back-channel logout URI
+func setupLogoutUserSession(sessionStore *storeMocks.Store, database *mocks.Database) (*sessions.Session, *models.UserSession) {
+	sess := sessions.NewSession(sessionStore, constants.SessionName)
+	sess.Values[constants.SessionKeySessionIdentifier] = "session-id"
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sess, nil)
+
+	userSession := &models.UserSession{Id: 4, UserId: 7, SessionIdentifier: "session-id"}
+	database.On("GetUserSessionBySessionIdentifier", mock.Anything, "session-id").Return(userSession, nil)
+	database.On("UserSessionLoadUser", mock.Anything, userSession).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.UserSession).User = models.User{Id: 7, Subject: logoutUserSubject, Email: "user@example.com"}
+	}).Return(nil)
+	database.On("UserSessionLoadClients", mock.Anything, userSession).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.UserSession).Clients = []models.UserSessionClient{{ClientId: 1}, {ClientId: 2}}
+	}).Return(nil)
+	database.On("UserSessionClientsLoadClients", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sessionClients := args.Get(1).([]models.UserSessionClient)
+		if len(sessionClients) == 0 {
+			return
+		}
+		sessionClients[0].Client = models.Client{Id: 1, ClientIdentifier: "test-client", FrontChannelLogoutURI: "https://app.example.com/frontchannel?tenant=1"}
+		sessionClients[1].Client = models.Client{Id: 2, ClientIdentifier: "other-client", BackChannelLogoutURI: "https://other.example.com/backchannel",
+			SubjectType: constants.SubjectTypePairwise, SectorIdentifier: "other.example.com"}
+	}).Return(nil)
+
+	return sess, userSession
+}
+
+func TestHandleLogoutGet_UnregisteredPostLogoutRedirectURI(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	setupLogoutClient(database)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_error.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["error"] == "Invalid post_logout_redirect_uri parameter. The URI is not registered for the client."
+		})).Return(nil)
+
+	handler := HandleLogoutGet(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("GET", "/auth/logout?client_id=test-client&post_logout_redirect_uri=https://evil.example.com", ""))
+
+	sessionStore.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestHandleLogoutGet_IdTokenHintForAnotherClient(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	tokenParser.On("DecodeAndValidateTokenString", "id-token", mock.Anything, false).Return(newLogoutIdToken(logoutUserSubject.String()), nil)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/auth_error.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["error"] == "The id_token_hint was not issued to the client_id parameter."
+		})).Return(nil)
+
+	handler := HandleLogoutGet(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("GET", "/auth/logout?client_id=other-client&id_token_hint=id-token", ""))
+}
+
+func TestHandleLogoutGet_WithoutIdTokenHintAsksForConfirmation(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	setupLogoutClient(database)
+	setupLogoutUserSession(sessionStore, database)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/logout.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return assert.ObjectsAreEqual([]string{"user@example.com"}, data["emails"]) && data["clientId"] == "test-client" &&
+				data["postLogoutRedirectURI"] == "https://app.example.com/signed-out" && data["state"] == "xyz"
+		})).Return(nil)
+
+	handler := HandleLogoutGet(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("GET", "/auth/logout?client_id=test-client&post_logout_redirect_uri=https://app.example.com/signed-out&state=xyz", ""))
+
+	database.AssertNotCalled(t, "DeleteUserSession", mock.Anything, mock.Anything)
+}
+
+// setupLogoutOtherUserSession registers a session of another user the browser remembers,
+// next to a session that no longer exists
+func setupLogoutOtherUserSession(sess *sessions.Session, database *mocks.Database) *models.UserSession {
+	sess.Values[constants.SessionKeyOtherSessionIdentifiers] = "other-session-id deleted-session-id"
+	otherUserSession := &models.UserSession{Id: 5, UserId: 8, SessionIdentifier: "other-session-id"}
+	database.On("GetUserSessionBySessionIdentifier", mock.Anything, "other-session-id").Return(otherUserSession, nil)
+	database.On("GetUserSessionBySessionIdentifier", mock.Anything, "deleted-session-id").Return(nil, nil)
+	database.On("UserSessionLoadUser", mock.Anything, otherUserSession).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.UserSession).User = models.User{Id: 8, Email: "other@example.com"}
+	}).Return(nil)
+	database.On("UserSessionLoadClients", mock.Anything, otherUserSession).Return(nil)
+
+	return otherUserSession
+}
+
+func TestHandleLogoutGet_IdTokenHintWithOtherSessionsAsksForConfirmation(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	// the other account is signed out as well, so the user has to confirm
+	tokenParser.On("DecodeAndValidateTokenString", "id-token", mock.Anything, false).Return(newLogoutIdToken(logoutUserSubject.String()), nil)
+	setupLogoutClient(database)
+	database.On("GetUserBySubject", mock.Anything, logoutUserSubject.String()).Return(&models.User{Id: 7, Subject: logoutUserSubject, Enabled: true}, nil)
+	sess, _ := setupLogoutUserSession(sessionStore, database)
+	setupLogoutOtherUserSession(sess, database)
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/logout.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return assert.ObjectsAreEqual([]string{"user@example.com", "other@example.com"}, data["emails"])
+		})).Return(nil)
+
+	handler := HandleLogoutGet(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("GET", "/auth/logout?id_token_hint=id-token&post_logout_redirect_uri=https://app.example.com/signed-out", ""))
+
+	database.AssertNotCalled(t, "DeleteUserSession", mock.Anything, mock.Anything)
+}
+
+func TestHandleLogoutGet_IdTokenHint(t *testing.T) {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	tokenParser.On("DecodeAndValidateTokenString", "id-token", mock.Anything, false).Return(newLogoutIdToken(logoutUserSubject.String()), nil)
+	setupLogoutClient(database)
+	database.On("GetUserBySubject", mock.Anything, logoutUserSubject.String()).Return(&models.User{Id: 7, Subject: logoutUserSubject, Enabled: true}, nil)
+	sess, userSession := setupLogoutUserSession(sessionStore, database)
+	database.On("GetPairwiseSubjectByUserIdAndSectorIdentifier", mock.Anything, int64(7), "other.example.com").
+		Return(&models.PairwiseSubject{UserId: 7, SectorIdentifier: "other.example.com", Subject: "other-pairwise-subject"}, nil)
+	database.On("BeginTransaction").Return(nil, nil)
+	database.On("RollbackTransaction", mock.Anything).Return(nil)
+	database.On("RevokeRefreshTokensBySessionIdentifier", mock.Anything, "session-id").Return(nil)
+	database.On("DeleteUserSession", mock.Anything, userSession.Id).Return(nil)
+	database.On("CommitTransaction", mock.Anything).Return(nil)
+	database.On("GetCurrentSigningKey", mock.Anything).Return(keyPair, nil)
+	sessionStore.On("Save", mock.Anything, mock.Anything, sess).Return(nil)
+	auditLogger.On("Log", constants.AuditLogout, map[string]interface{}{
+		"userId":            int64(7),
+		"sessionIdentifier": "session-id",
+		"clientId":          "test-client",
+	}).Return()
+	logoutNotifier.On("NotifyBackChannelLogout", mock.MatchedBy(func(notifications []oauth.BackChannelLogoutNotification) bool {
+		if len(notifications) != 1 || notifications[0].ClientIdentifier != "other-client" ||
+			notifications[0].LogoutURI != "https://other.example.com/backchannel" {
+			return false
+		}
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(notifications[0].LogoutToken, claims, func(token *jwt.Token) (interface{}, error) {
+			return privateKey.(*ecdsa.PrivateKey).Public(), nil
+		}, jwt.WithIssuer("https://auth.example.com"), jwt.WithAudience("other-client"), jwt.WithExpirationRequired())
+		return err == nil && claims["sub"] == "other-pairwise-subject" && claims["sid"] == "session-id"
+	})).Return()
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/logout_completed.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			frontChannelLogoutURI := "https://app.example.com/frontchannel?tenant=1&" +
+				url.Values{"iss": {"https://auth.example.com"}, "sid": {"session-id"}}.Encode()
+			return assert.ObjectsAreEqual([]string{frontChannelLogoutURI}, data["frontChannelLogoutURIs"]) &&
+				data["redirectURI"] == "https://app.example.com/signed-out?state=xyz"
+		})).Return(nil)
+
+	handler := HandleLogoutGet(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("GET", "/auth/logout?id_token_hint=id-token&post_logout_redirect_uri=https://app.example.com/signed-out&state=xyz", ""))
+
+	assert.NotContains(t, sess.Values, constants.SessionKeySessionIdentifier)
+}
+
+func TestHandleLogoutGet_WithoutSessionRedirects(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	setupLogoutClient(database)
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+
+	handler := HandleLogoutGet(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("GET", "/auth/logout?client_id=test-client&post_logout_redirect_uri=https://app.example.com/signed-out&state=xyz", ""))
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "https://app.example.com/signed-out?state=xyz", rr.Header().Get("Location"))
+}
+
+func TestHandleLogoutPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	sess := sessions.NewSession(sessionStore, constants.SessionName)
+	sess.Values[constants.SessionKeySessionIdentifier] = "session-id"
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sess, nil)
+	sessionStore.On("Save", mock.Anything, mock.Anything, sess).Return(nil)
+	userSession := &models.UserSession{Id: 4, UserId: 7, SessionIdentifier: "session-id"}
+	database.On("GetUserSessionBySessionIdentifier", mock.Anything, "session-id").Return(userSession, nil)
+	database.On("UserSessionLoadUser", mock.Anything, userSession).Return(nil)
+	database.On("UserSessionLoadClients", mock.Anything, userSession).Return(nil)
+	database.On("UserSessionClientsLoadClients", mock.Anything, mock.Anything).Return(nil)
+	database.On("BeginTransaction").Return(nil, nil)
+	database.On("RollbackTransaction", mock.Anything).Return(nil)
+	database.On("RevokeRefreshTokensBySessionIdentifier", mock.Anything, "session-id").Return(nil)
+	database.On("DeleteUserSession", mock.Anything, userSession.Id).Return(nil)
+	database.On("CommitTransaction", mock.Anything).Return(nil)
+	auditLogger.On("Log", constants.AuditLogout, mock.Anything).Return()
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/logout_completed.html",
+		mock.MatchedBy(func(data map[string]interface{}) bool {
+			return data["redirectURI"] == ""
+		})).Return(nil)
+
+	handler := HandleLogoutPost(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("POST", "/auth/logout", "client_id=&post_logout_redirect_uri=&state="))
+
+	logoutNotifier.AssertNotCalled(t, "NotifyBackChannelLogout", mock.Anything)
+}
+
+func TestHandleLogoutPost_EndsEverySessionOfTheBrowser(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	sessionStore := storeMocks.NewStore(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	logoutNotifier := mocksOAuth.NewLogoutNotifier(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	sess, userSession := setupLogoutUserSession(sessionStore, database)
+	otherUserSession := setupLogoutOtherUserSession(sess, database)
+	database.On("GetPairwiseSubjectByUserIdAndSectorIdentifier", mock.Anything, int64(7), "other.example.com").
+		Return(&models.PairwiseSubject{UserId: 7, SectorIdentifier: "other.example.com", Subject: "other-pairwise-subject"}, nil)
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	database.On("GetCurrentSigningKey", mock.Anything).Return(keyPair, nil)
+	database.On("BeginTransaction").Return(nil, nil)
+	database.On("RollbackTransaction", mock.Anything).Return(nil)
+	database.On("RevokeRefreshTokensBySessionIdentifier", mock.Anything, "session-id").Return(nil).Once()
+	database.On("DeleteUserSession", mock.Anything, userSession.Id).Return(nil).Once()
+	database.On("RevokeRefreshTokensBySessionIdentifier", mock.Anything, "other-session-id").Return(nil).Once()
+	database.On("DeleteUserSession", mock.Anything, otherUserSession.Id).Return(nil).Once()
+	database.On("CommitTransaction", mock.Anything).Return(nil)
+	sessionStore.On("Save", mock.Anything, mock.Anything, sess).Return(nil)
+	auditLogger.On("Log", constants.AuditLogout, mock.MatchedBy(func(details map[string]interface{}) bool {
+		return details["sessionIdentifier"] == "session-id"
+	})).Return().Once()
+	auditLogger.On("Log", constants.AuditLogout, mock.MatchedBy(func(details map[string]interface{}) bool {
+		return details["sessionIdentifier"] == "other-session-id"
+	})).Return().Once()
+	logoutNotifier.On("NotifyBackChannelLogout", mock.Anything).Return()
+	httpHelper.On("RenderTemplate", mock.Anything, mock.Anything, "/layouts/no_menu_layout.html", "/logout_completed.html", mock.Anything).Return(nil)
+
+	handler := HandleLogoutPost(httpHelper, sessionStore, database, tokenParser, logoutNotifier, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newLogoutRequest("POST", "/auth/logout", "client_id=&post_logout_redirect_uri=&state="))
+
+	database.AssertExpectations(t)
+	auditLogger.AssertExpectations(t)
+	// no session is left to switch to
+	assert.NotContains(t, sess.Values, constants.SessionKeySessionIdentifier)
+	assert.NotContains(t, sess.Values, constants.SessionKeyOtherSessionIdentifiers)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/stringutil"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+// nonAuthorizationParameters are the parameters of a pushed authorization request that are not stored
+var nonAuthorizationParameters = []string{"client_secret", "client_assertion", "client_assertion_type", "request"}
+
+const (
+	requestURIPrefix                       = "urn:ietf:params:oauth:request_uri:"
+	pushedAuthorizationExpirationInSeconds = 60
+)
+
+func HandlePushedAuthorizationRequestPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	authorizeValidator AuthorizeValidator,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		client, err := authenticateClient(r, database, true)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		// a pushed request can't reference another one (RFC 9126, section 2.1)
+		if len(r.PostFormValue("request_uri")) > 0 {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"The request_uri parameter is not allowed in a pushed authorization request.", http.StatusBadRequest))
+			return
+		}
+
+		// the authenticated client takes precedence over a client_id in the body
+		if clientId := r.PostFormValue("client_id"); len(clientId) > 0 && clientId != client.ClientIdentifier {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"The client_id parameter does not match the authenticated client.", http.StatusBadRequest))
+			return
+		}
+
+		getParam := r.PostFormValue
+		var requestObjectParameters url.Values
+		if requestObject := r.PostFormValue("request"); len(requestObject) > 0 {
+			result, err := authorizeValidator.ValidateRequestObject(r.Context(), &validators.ValidateRequestObjectInput{
+				ClientId:      client.ClientIdentifier,
+				RequestObject: requestObject,
+			})
+			if err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+			getParam = getRequestObjectParam(result, getParam)
+			requestObjectParameters = result.Parameters
+		} else if client.RequireSignedRequestObject {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"The client requires signed request objects. Please use the request parameter.", http.StatusBadRequest))
+			return
+		}
+
+		err = authorizeValidator.ValidateClientAndRedirectURI(&validators.ValidateClientAndRedirectURIInput{
+			ClientId:    client.ClientIdentifier,
+			RedirectURI: getParam("redirect_uri"),
+		})
+		if err != nil {
+			if errorDetail, ok := err.(*customerrors.ErrorDetail); ok {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+					errorDetail.GetDescription(), http.StatusBadRequest))
+			} else {
+				httpHelper.JsonError(w, r, err)
+			}
+			return
+		}
+
+		err = authorizeValidator.ValidateRequest(&validators.ValidateRequestInput{
+			ResponseType:        getParam("response_type"),
+			CodeChallengeMethod: getParam("code_challenge_method"),
+			CodeChallenge:       getParam("code_challenge"),
+			ResponseMode:        getParam("response_mode"),
+			Prompt:              getParam("prompt"),
+		})
+		if err == nil {
+			err = authorizeValidator.ValidateScopes(getParam("scope"))
+		}
+		if err == nil {
+			err = validators.ValidateResourceIndicator(getParam("scope"), getParam("resource"))
+		}
+		if err == nil {
+			err = validators.ValidateClaimsRequest(getParam("scope"), getParam("claims"))
+		}
+
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		// client credentials are not authorization parameters. A request object is stored as its verified
+		// parameters, which take precedence like in getRequestObjectParam: its jti is already recorded,
+		// so it could not be verified again when the request_uri is used.
+		parameters := url.Values{}
+		if requestObjectParameters == nil || !client.RequireSignedRequestObject {
+			for key, values := range r.PostForm {
+				if !slices.Contains(nonAuthorizationParameters, key) {
+					parameters[key] = values
+				}
+			}
+		}
+		for key, values := range requestObjectParameters {
+			parameters[key] = values
+		}
+		parameters.Set("client_id", client.ClientIdentifier)
+
+		requestURI := requestURIPrefix + strings.ReplaceAll(uuid.New().String(), "-", "") + stringutil.GenerateSecurityRandomString(32)
+		requestURIHash, err := hashutil.HashString(requestURI)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		pushedAuthorizationRequest := &models.PushedAuthorizationRequest{
+			RequestURIHash: requestURIHash,
+			ClientId:       client.Id,
+			Parameters:     parameters.Encode(),
+			ExpiresAt:      sql.NullTime{Time: time.Now().UTC().Add(pushedAuthorizationExpirationInSeconds * time.Second), Valid: true},
+		}
+		if err = database.CreatePushedAuthorizationRequest(nil, pushedAuthorizationRequest); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		httpHelper.EncodeJson(w, r, oauth.PushedAuthorizationResponse{
+			RequestURI: requestURI,
+			ExpiresIn:  pushedAuthorizationExpirationInSeconds,
+		})
+	}
+}
+
+// getPushedAuthorizationRequest consumes the pushed authorization request referenced by requestURI,
+// returning its parameters. Request URIs are single use (RFC 9126, section 4).
+func getPushedAuthorizationRequest(database database.Database, requestURI string, clientId string) (url.Values, error) {
+	invalidRequestURI := customerrors.NewErrorDetail("invalid_request_uri",
+		"The request_uri parameter is invalid or has expired.")
+	if !strings.HasPrefix(requestURI, requestURIPrefix) {
+		return nil, invalidRequestURI
+	}
+
+	requestURIHash, err := hashutil.HashString(requestURI)
+	if err != nil {
+		return nil, err
+	}
+
+	pushedAuthorizationRequest, err := database.GetPushedAuthorizationRequestByRequestURIHash(nil, requestURIHash)
+	if err != nil {
+		return nil, err
+	} else if pushedAuthorizationRequest == nil {
+		return nil, invalidRequestURI
+	}
+
+	if err = database.DeletePushedAuthorizationRequest(nil, pushedAuthorizationRequest.Id); err != nil {
+		return nil, err
+	} else if pushedAuthorizationRequest.IsExpired() {
+		return nil, invalidRequestURI
+	}
+
+	parameters, err := url.ParseQuery(pushedAuthorizationRequest.Parameters)
+	if err != nil {
+		return nil, err
+	} else if parameters.Get("client_id") != clientId {
+		return nil, customerrors.NewErrorDetail("invalid_request_uri",
+			"The client_id parameter does not match the pushed authorization request.")
+	}
+
+	return parameters, nil
+}
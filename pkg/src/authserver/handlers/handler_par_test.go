@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/sessions"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	storeMocks "github.com/pchchv/aas/pkg/src/sqlstore/mocks"
+	mocksUser "github.com/pchchv/aas/pkg/src/user/mocks"
+	"github.com/pchchv/aas/pkg/src/validators"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPushedAuthorizationRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/auth/par", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{AESEncryptionKey: testAESEncryptionKey})
+	return req.WithContext(ctx)
+}
+
+func newPushedAuthorizationClient(t *testing.T) *models.Client {
+	clientSecretEncrypted, err := encryption.EncryptText("secret", testAESEncryptionKey)
+	assert.NoError(t, err)
+	return &models.Client{
+		Id:                       1,
+		ClientIdentifier:         "web-app",
+		ClientSecretEncrypted:    clientSecretEncrypted,
+		Enabled:                  true,
+		AuthorizationCodeEnabled: true,
+	}
+}
+
+func TestHandlePushedAuthorizationRequestPost(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	var created *models.PushedAuthorizationRequest
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(newPushedAuthorizationClient(t), nil)
+	authorizeValidator.On("ValidateClientAndRedirectURI", &validators.ValidateClientAndRedirectURIInput{
+		ClientId:    "web-app",
+		RedirectURI: "https://app.example.com/callback",
+	}).Return(nil)
+	authorizeValidator.On("ValidateRequest", &validators.ValidateRequestInput{
+		ResponseType:        "code",
+		CodeChallengeMethod: "S256",
+		CodeChallenge:       "challenge",
+	}).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("CreatePushedAuthorizationRequest", mock.Anything, mock.MatchedBy(func(par *models.PushedAuthorizationRequest) bool {
+		return par.ClientId == 1 && par.ExpiresAt.Valid
+	})).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.PushedAuthorizationRequest)
+	}).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp oauth.PushedAuthorizationResponse) bool {
+		requestURIHash, err := hashutil.HashString(resp.RequestURI)
+		return err == nil && requestURIHash == created.RequestURIHash &&
+			strings.HasPrefix(resp.RequestURI, "urn:ietf:params:oauth:request_uri:") && resp.ExpiresIn == 60
+	})).Return()
+
+	handler := HandlePushedAuthorizationRequestPost(httpHelper, database, authorizeValidator)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newPushedAuthorizationRequest(url.Values{
+		"client_id":             {"web-app"},
+		"client_secret":         {"secret"},
+		"redirect_uri":          {"https://app.example.com/callback"},
+		"response_type":         {"code"},
+		"code_challenge_method": {"S256"},
+		"code_challenge":        {"challenge"},
+		"scope":                 {"openid"},
+		"state":                 {"xyz"},
+	}))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+	parameters, err := url.ParseQuery(created.Parameters)
+	assert.NoError(t, err)
+	assert.Equal(t, "web-app", parameters.Get("client_id"))
+	assert.Equal(t, "xyz", parameters.Get("state"))
+	assert.False(t, parameters.Has("client_secret"))
+}
+
+func TestHandlePushedAuthorizationRequestPost_RequestURINotAllowed(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(newPushedAuthorizationClient(t), nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_request")).Return()
+
+	handler := HandlePushedAuthorizationRequestPost(httpHelper, database, authorizeValidator)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newPushedAuthorizationRequest(url.Values{
+		"client_id":     {"web-app"},
+		"client_secret": {"secret"},
+		"request_uri":   {"urn:ietf:params:oauth:request_uri:abc"},
+	}))
+
+	authorizeValidator.AssertNotCalled(t, "ValidateClientAndRedirectURI", mock.Anything)
+	database.AssertNotCalled(t, "CreatePushedAuthorizationRequest", mock.Anything, mock.Anything)
+}
+
+func TestHandlePushedAuthorizationRequestPost_InvalidRedirectURI(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(newPushedAuthorizationClient(t), nil)
+	authorizeValidator.On("ValidateClientAndRedirectURI", mock.Anything).
+		Return(customerrors.NewErrorDetail("", "Invalid redirect_uri parameter. The client does not have this redirect URI registered."))
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		errorDetail, ok := err.(*customerrors.ErrorDetail)
+		return ok && errorDetail.GetCode() == "invalid_request" && errorDetail.GetHttpStatusCode() == http.StatusBadRequest
+	})).Return()
+
+	handler := HandlePushedAuthorizationRequestPost(httpHelper, database, authorizeValidator)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newPushedAuthorizationRequest(url.Values{
+		"client_id":     {"web-app"},
+		"client_secret": {"secret"},
+		"redirect_uri":  {"https://evil.example.com/callback"},
+	}))
+
+	authorizeValidator.AssertNotCalled(t, "ValidateRequest", mock.Anything)
+	database.AssertNotCalled(t, "CreatePushedAuthorizationRequest", mock.Anything, mock.Anything)
+}
+
+func TestHandlePushedAuthorizationRequestPost_RequestObjectRequired(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	client := newPushedAuthorizationClient(t)
+	client.RequireSignedRequestObject = true
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(client, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_request")).Return()
+
+	handler := HandlePushedAuthorizationRequestPost(httpHelper, database, authorizeValidator)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newPushedAuthorizationRequest(url.Values{
+		"client_id":     {"web-app"},
+		"client_secret": {"secret"},
+		"redirect_uri":  {"https://app.example.com/callback"},
+		"response_type": {"code"},
+		"scope":         {"openid"},
+	}))
+
+	authorizeValidator.AssertNotCalled(t, "ValidateClientAndRedirectURI", mock.Anything)
+	database.AssertNotCalled(t, "CreatePushedAuthorizationRequest", mock.Anything, mock.Anything)
+}
+
+func TestHandlePushedAuthorizationRequestPost_RequestObject(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validatorsMocks.NewAuthorizeValidator(t)
+
+	client := newPushedAuthorizationClient(t)
+	var created *models.PushedAuthorizationRequest
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(client, nil)
+	authorizeValidator.On("ValidateRequestObject", mock.Anything, &validators.ValidateRequestObjectInput{
+		ClientId:      "web-app",
+		RequestObject: "signed-request-object",
+	}).Return(&validators.ValidateRequestObjectResult{
+		Client: client,
+		Parameters: url.Values{
+			"client_id":             {"web-app"},
+			"redirect_uri":          {"https://app.example.com/callback"},
+			"response_type":         {"code"},
+			"code_challenge_method": {"S256"},
+			"code_challenge":        {"challenge"},
+			"scope":                 {"openid"},
+		},
+	}, nil)
+	authorizeValidator.On("ValidateClientAndRedirectURI", &validators.ValidateClientAndRedirectURIInput{
+		ClientId:    "web-app",
+		RedirectURI: "https://app.example.com/callback",
+	}).Return(nil)
+	authorizeValidator.On("ValidateRequest", &validators.ValidateRequestInput{
+		ResponseType:        "code",
+		CodeChallengeMethod: "S256",
+		CodeChallenge:       "challenge",
+	}).Return(nil)
+	authorizeValidator.On("ValidateScopes", "openid").Return(nil)
+	database.On("CreatePushedAuthorizationRequest", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.PushedAuthorizationRequest)
+	}).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	handler := HandlePushedAuthorizationRequestPost(httpHelper, database, authorizeValidator)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newPushedAuthorizationRequest(url.Values{
+		"client_id":     {"web-app"},
+		"client_secret": {"secret"},
+		"request":       {"signed-request-object"},
+	}))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	parameters, err := url.ParseQuery(created.Parameters)
+	assert.NoError(t, err)
+	assert.False(t, parameters.Has("request"))
+	assert.Equal(t, "https://app.example.com/callback", parameters.Get("redirect_uri"))
+	assert.Equal(t, "challenge", parameters.Get("code_challenge"))
+}
+
+func TestPushedAuthorizationRequest_SignedRequestObjectThroughAuthorize(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	authorizeValidator := validators.NewAuthorizeValidator(database)
+	settings := &models.Settings{AESEncryptionKey: testAESEncryptionKey, Issuer: "https://auth.example.com"}
+
+	client := newPushedAuthorizationClient(t)
+	client.PARRequired = true
+	client.RequireSignedRequestObject = true
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(client, nil)
+	database.On("ClientLoadRedirectURIs", mock.Anything, client).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).RedirectURIs = []models.RedirectURI{{URI: "https://app.example.com/callback"}}
+	}).Return(nil)
+
+	// the jti of the request object is recorded by the real validator and can only be used once
+	requestObjectJtis := []*models.RequestObjectJti{}
+	database.On("GetRequestObjectJti", mock.Anything, client.Id, mock.Anything).Return(
+		func(tx *sql.Tx, clientId int64, jtiHash string) (*models.RequestObjectJti, error) {
+			for _, requestObjectJti := range requestObjectJtis {
+				if requestObjectJti.JtiHash == jtiHash {
+					return requestObjectJti, nil
+				}
+			}
+			return nil, nil
+		})
+	database.On("CreateRequestObjectJti", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		requestObjectJtis = append(requestObjectJtis, args.Get(1).(*models.RequestObjectJti))
+	}).Return(nil)
+
+	var pushed *models.PushedAuthorizationRequest
+	database.On("CreatePushedAuthorizationRequest", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		pushed = args.Get(1).(*models.PushedAuthorizationRequest)
+		pushed.Id = 5
+	}).Return(nil)
+	database.On("GetPushedAuthorizationRequestByRequestURIHash", mock.Anything, mock.Anything).Return(
+		func(tx *sql.Tx, requestURIHash string) (*models.PushedAuthorizationRequest, error) {
+			if pushed != nil && pushed.RequestURIHash == requestURIHash {
+				return pushed, nil
+			}
+			return nil, nil
+		})
+	database.On("DeletePushedAuthorizationRequest", mock.Anything, int64(5)).Return(nil)
+
+	requestObject, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":                   "web-app",
+		"aud":                   settings.Issuer,
+		"exp":                   time.Now().Add(time.Minute).Unix(),
+		"jti":                   "request-object-1",
+		"client_id":             "web-app",
+		"redirect_uri":          "https://app.example.com/callback",
+		"response_type":         "code",
+		"code_challenge_method": "S256",
+		"code_challenge":        strings.Repeat("c", 43),
+		"scope":                 "openid",
+		"state":                 "signed-state",
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	parHttpHelper := helpersMocks.NewHttpHelper(t)
+	var requestURI string
+	parHttpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		requestURI = args.Get(2).(oauth.PushedAuthorizationResponse).RequestURI
+	}).Return()
+
+	parReq := httptest.NewRequest("POST", "/auth/par", strings.NewReader(url.Values{
+		"client_id":     {"web-app"},
+		"client_secret": {"secret"},
+		"request":       {requestObject},
+	}.Encode()))
+	parReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	parReq = parReq.WithContext(context.WithValue(parReq.Context(), constants.ContextKeySettings, settings))
+	rr := httptest.NewRecorder()
+	HandlePushedAuthorizationRequestPost(parHttpHelper, database, authorizeValidator).ServeHTTP(rr, parReq)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.NotEmpty(t, requestURI)
+
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	authHelper := helpersMocks.NewAuthHelper(t)
+	userSessionManager := mocksUser.NewUserSessionManager(t)
+	sessionStore := storeMocks.NewStore(t)
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "request_uri").Return(requestURI)
+	httpHelper.On("GetFromUrlQueryOrFormPost", mock.Anything, "client_id").Return("web-app")
+	sessionStore.On("Get", mock.Anything, constants.SessionName).Return(sessions.NewSession(sessionStore, constants.SessionName), nil)
+	authHelper.On("SaveAuthContext", mock.Anything, mock.Anything, mock.MatchedBy(func(ac *oauth.AuthContext) bool {
+		return ac.AuthState == oauth.AuthStateRequiresLevel1 && ac.State == "signed-state" &&
+			ac.RedirectURI == "https://app.example.com/callback"
+	})).Return(nil)
+
+	authorizeReq := httptest.NewRequest("GET", "/auth/authorize", nil)
+	authorizeReq = authorizeReq.WithContext(context.WithValue(authorizeReq.Context(), constants.ContextKeySettings, settings))
+	rr = httptest.NewRecorder()
+	HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, sessionStore, database, authorizeValidator).ServeHTTP(rr, authorizeReq)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/auth/level1", rr.Header().Get("Location"))
+	assert.Len(t, requestObjectJtis, 1)
+}
@@ -0,0 +1,643 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+	"github.com/pchchv/aas/pkg/src/stringutil"
+)
+
+// clientUpdateRequest is the body of a client update request (RFC 7592, section 2.2),
+// which carries the client_id and optionally the client_secret next to the metadata
+type clientUpdateRequest struct {
+	ClientId     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	oauth.ClientMetadata
+}
+
+func HandleRegisterPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	clientRegistrationValidator ClientRegistrationValidator,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		if !settings.DynamicClientRegistrationEnabled {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("access_denied",
+				"Dynamic client registration is disabled.", http.StatusForbidden))
+			return
+		}
+
+		initialAccessToken, err := getInitialAccessToken(r, database)
+		if err != nil {
+			setInvalidTokenHeader(w)
+			httpHelper.JsonError(w, r, err)
+			return
+		} else if initialAccessToken == nil && !settings.DynamicClientRegistrationOpen {
+			setInvalidTokenHeader(w)
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_token",
+				"An initial access token is required to register a client.", http.StatusUnauthorized))
+			return
+		}
+
+		var metadata oauth.ClientMetadata
+		if err = json.NewDecoder(r.Body).Decode(&metadata); err != nil {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client_metadata",
+				"The request body is not valid JSON.", http.StatusBadRequest))
+			return
+		}
+
+		if err = clientRegistrationValidator.ValidateClientMetadata(&metadata, initialAccessToken != nil); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		permissions, err := getPermissionsFromScope(database, metadata.Scope)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		registrationAccessToken := stringutil.GenerateSecurityRandomString(64)
+		registrationAccessTokenHash, err := hashutil.HashString(registrationAccessToken)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		client := &models.Client{
+			ClientIdentifier:                        "dcr-" + strings.ReplaceAll(uuid.New().String(), "-", ""),
+			Enabled:                                 true,
+			ConsentRequired:                         true,
+			DefaultAcrLevel:                         enums.AcrLevel2Optional,
+			IncludeOpenIDConnectClaimsInAccessToken: enums.ThreeStateSettingDefault.String(),
+			RegistrationAccessTokenHash:             registrationAccessTokenHash,
+		}
+		clientSecret, err := applyClientMetadata(client, &metadata, settings)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		if err = database.CreateClient(tx, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = createClientRelations(tx, database, client, &metadata, permissions); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		details := map[string]interface{}{
+			"clientId":         client.Id,
+			"clientIdentifier": client.ClientIdentifier,
+		}
+		if initialAccessToken != nil {
+			details["initialAccessTokenId"] = initialAccessToken.Id
+		}
+		auditLogger.Log(constants.AuditRegisteredClient, details)
+
+		resp := newClientRegistrationResponse(client)
+		resp.ClientSecret = clientSecret
+		resp.RegistrationAccessToken = registrationAccessToken
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleRegisterClientGet(httpHelper HttpHelper, database database.Database) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		client, err := getRegisteredClient(r, database)
+		if err != nil {
+			setInvalidTokenHeader(w)
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = loadClientRelations(database, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		httpHelper.EncodeJson(w, r, newClientRegistrationResponse(client))
+	}
+}
+
+func HandleRegisterClientPut(
+	httpHelper HttpHelper,
+	database database.Database,
+	clientRegistrationValidator ClientRegistrationValidator,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		client, err := getRegisteredClient(r, database)
+		if err != nil {
+			setInvalidTokenHeader(w)
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		var input clientUpdateRequest
+		if err = json.NewDecoder(r.Body).Decode(&input); err != nil {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client_metadata",
+				"The request body is not valid JSON.", http.StatusBadRequest))
+			return
+		}
+
+		if input.ClientId != client.ClientIdentifier {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"The client_id in the request body does not match the client being updated.", http.StatusBadRequest))
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		if len(input.ClientSecret) > 0 && len(client.ClientSecretEncrypted) > 0 {
+			clientSecret, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+			if err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			} else if clientSecret != input.ClientSecret {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+					"The client_secret in the request body does not match the client being updated.", http.StatusBadRequest))
+				return
+			}
+		}
+
+		if err = loadClientRelations(database, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		metadata := input.ClientMetadata
+		if err = clientRegistrationValidator.ValidateClientMetadata(&metadata, true); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		// resource scopes are kept only if the client already holds them,
+		// updates never grant permissions the registration did not have
+		currentScopes := strings.Fields(getClientMetadata(client).Scope)
+		for _, scope := range strings.Fields(metadata.Scope) {
+			if strings.Contains(scope, ":") && !slices.Contains(currentScopes, scope) {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client_metadata",
+					"The scope '"+scope+"' cannot be added when updating a client.", http.StatusBadRequest))
+				return
+			}
+		}
+
+		permissions, err := getPermissionsFromScope(database, metadata.Scope)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		clientSecret, err := applyClientMetadata(client, &metadata, settings)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tx, err := database.BeginTransaction()
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+		defer database.RollbackTransaction(tx) //nolint:errcheck
+
+		if err = database.UpdateClient(tx, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = deleteClientRelations(tx, database, client); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = createClientRelations(tx, database, client, &metadata, permissions); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.CommitTransaction(tx); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditUpdatedRegisteredClient, map[string]interface{}{
+			"clientId": client.Id,
+		})
+
+		resp := newClientRegistrationResponse(client)
+		resp.ClientSecret = clientSecret
+		httpHelper.EncodeJson(w, r, resp)
+	}
+}
+
+func HandleRegisterClientDelete(httpHelper HttpHelper, database database.Database, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := getRegisteredClient(r, database)
+		if err != nil {
+			setInvalidTokenHeader(w)
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.DeleteClient(nil, client.Id); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		auditLogger.Log(constants.AuditDeletedRegisteredClient, map[string]interface{}{
+			"clientId":         client.Id,
+			"clientIdentifier": client.ClientIdentifier,
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getBearerToken returns the token from the Authorization header, if any
+func getBearerToken(r *http.Request) string {
+	const bearerSchema = "Bearer "
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, bearerSchema) {
+		return ""
+	}
+	return strings.TrimSpace(authHeader[len(bearerSchema):])
+}
+
+func setInvalidTokenHeader(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+}
+
+// getInitialAccessToken returns nil if the request carries no bearer token
+// and an error if the token is unknown or expired
+func getInitialAccessToken(r *http.Request, database database.Database) (*models.InitialAccessToken, error) {
+	token := getBearerToken(r)
+	if len(token) == 0 {
+		return nil, nil
+	}
+
+	tokenHash, err := hashutil.HashString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	initialAccessToken, err := database.GetInitialAccessTokenByTokenHash(nil, tokenHash)
+	if err != nil {
+		return nil, err
+	} else if initialAccessToken == nil || initialAccessToken.IsExpired() {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_token",
+			"The initial access token is invalid or expired.", http.StatusUnauthorized)
+	}
+
+	return initialAccessToken, nil
+}
+
+// getRegisteredClient loads the client from the URL and checks the registration access token.
+// Unknown clients get the same error as a wrong token, so client identifiers cannot be probed.
+func getRegisteredClient(r *http.Request, database database.Database) (*models.Client, error) {
+	invalidToken := customerrors.NewErrorDetailWithHttpStatusCode("invalid_token",
+		"The registration access token is invalid.", http.StatusUnauthorized)
+
+	settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+	if !settings.DynamicClientRegistrationEnabled {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("access_denied",
+			"Dynamic client registration is disabled.", http.StatusForbidden)
+	}
+
+	token := getBearerToken(r)
+	if len(token) == 0 {
+		return nil, invalidToken
+	}
+
+	client, err := database.GetClientByClientIdentifier(nil, chi.URLParam(r, "clientId"))
+	if err != nil {
+		return nil, err
+	} else if client == nil || !client.IsDynamicallyRegistered() ||
+		!hashutil.VerifyStringHash(client.RegistrationAccessTokenHash, token) {
+		return nil, invalidToken
+	}
+
+	return client, nil
+}
+
+// applyClientMetadata copies the validated metadata to the client.
+// It returns the client secret when a new one was generated.
+func applyClientMetadata(client *models.Client, metadata *oauth.ClientMetadata, settings *models.Settings) (string, error) {
+	client.Description = metadata.ClientName
+	client.AuthorizationCodeEnabled = slices.Contains(metadata.GrantTypes, "authorization_code")
+	client.ClientCredentialsEnabled = slices.Contains(metadata.GrantTypes, "client_credentials")
+	client.DeviceCodeEnabled = slices.Contains(metadata.GrantTypes, constants.DeviceCodeGrantType)
+	client.CIBAEnabled = slices.Contains(metadata.GrantTypes, constants.CIBAGrantType)
+	client.BackChannelTokenDeliveryMode = metadata.BackChannelTokenDeliveryMode
+	client.BackChannelClientNotificationEndpoint = metadata.BackChannelClientNotificationEndpoint
+	client.PARRequired = metadata.RequirePushedAuthorizationRequests
+	client.RequireSignedRequestObject = metadata.RequireSignedRequestObject
+	client.JWKS = metadata.Jwks
+	client.JWKSURI = metadata.JwksURI
+	client.TLSClientAuthSubjectDN = metadata.TLSClientAuthSubjectDN
+	client.FrontChannelLogoutURI = metadata.FrontChannelLogoutURI
+	client.BackChannelLogoutURI = metadata.BackChannelLogoutURI
+	client.SubjectType = metadata.SubjectType
+	client.SectorIdentifierURI = metadata.SectorIdentifierURI
+	client.SectorIdentifier = metadata.SectorIdentifier
+	client.IdTokenEncryptedResponseAlg = metadata.IdTokenEncryptedResponseAlg
+	client.IdTokenEncryptedResponseEnc = metadata.IdTokenEncryptedResponseEnc
+	client.UserInfoEncryptedResponseAlg = metadata.UserInfoEncryptedResponseAlg
+	client.UserInfoEncryptedResponseEnc = metadata.UserInfoEncryptedResponseEnc
+	client.AuthorizationEncryptedResponseAlg = metadata.AuthorizationEncryptedResponseAlg
+	client.AuthorizationEncryptedResponseEnc = metadata.AuthorizationEncryptedResponseEnc
+
+	client.TokenEndpointAuthMethod = ""
+	switch metadata.TokenEndpointAuthMethod {
+	case constants.TokenEndpointAuthMethodPrivateKeyJwt, constants.TokenEndpointAuthMethodClientSecretJwt,
+		constants.TokenEndpointAuthMethodTLSClientAuth, constants.TokenEndpointAuthMethodSelfSignedTLS:
+		client.TokenEndpointAuthMethod = metadata.TokenEndpointAuthMethod
+	}
+
+	if metadata.TokenEndpointAuthMethod == "none" {
+		client.IsPublic = true
+		client.ClientSecretEncrypted = nil
+		return "", nil
+	}
+
+	client.IsPublic = false
+	if client.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodPrivateKeyJwt || client.UsesTLSClientAuth() {
+		// the client authenticates with its own keys or certificate and has no secret
+		client.ClientSecretEncrypted = nil
+		return "", nil
+	} else if len(client.ClientSecretEncrypted) > 0 {
+		return "", nil
+	}
+
+	clientSecret := stringutil.GenerateSecurityRandomString(60)
+	clientSecretEncrypted, err := encryption.EncryptText(clientSecret, settings.AESEncryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	client.ClientSecretEncrypted = clientSecretEncrypted
+	return clientSecret, nil
+}
+
+// getPermissionsFromScope returns the permissions for the resource scopes,
+// which were already validated against the database
+func getPermissionsFromScope(database database.Database, scope string) ([]models.Permission, error) {
+	permissions := []models.Permission{}
+	for _, scopeStr := range strings.Fields(scope) {
+		if oidc.IsIdTokenScope(scopeStr) || oidc.IsOfflineAccessScope(scopeStr) {
+			continue
+		}
+
+		parts := strings.Split(scopeStr, ":")
+		resource, err := database.GetResourceByResourceIdentifier(nil, parts[0])
+		if err != nil {
+			return nil, err
+		} else if resource == nil {
+			continue
+		}
+
+		resourcePermissions, err := database.GetPermissionsByResourceId(nil, resource.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, permission := range resourcePermissions {
+			if permission.PermissionIdentifier == parts[1] {
+				permission.Resource = *resource
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return permissions, nil
+}
+
+// createClientRelations creates the redirect URIs, post logout redirect URIs,
+// web origins and permissions from the metadata, setting them on the client
+func createClientRelations(tx *sql.Tx, database database.Database, client *models.Client, metadata *oauth.ClientMetadata, permissions []models.Permission) error {
+	client.RedirectURIs = make([]models.RedirectURI, 0, len(metadata.RedirectURIs))
+	for _, uri := range metadata.RedirectURIs {
+		redirectURI := models.RedirectURI{ClientId: client.Id, URI: uri}
+		if err := database.CreateRedirectURI(tx, &redirectURI); err != nil {
+			return err
+		}
+		client.RedirectURIs = append(client.RedirectURIs, redirectURI)
+	}
+
+	client.PostLogoutRedirectURIs = make([]models.PostLogoutRedirectURI, 0, len(metadata.PostLogoutRedirectURIs))
+	for _, uri := range metadata.PostLogoutRedirectURIs {
+		postLogoutRedirectURI := models.PostLogoutRedirectURI{ClientId: client.Id, URI: uri}
+		if err := database.CreatePostLogoutRedirectURI(tx, &postLogoutRedirectURI); err != nil {
+			return err
+		}
+		client.PostLogoutRedirectURIs = append(client.PostLogoutRedirectURIs, postLogoutRedirectURI)
+	}
+
+	client.WebOrigins = make([]models.WebOrigin, 0, len(metadata.WebOrigins))
+	for _, origin := range metadata.WebOrigins {
+		webOrigin := models.WebOrigin{ClientId: client.Id, Origin: origin}
+		if err := database.CreateWebOrigin(tx, &webOrigin); err != nil {
+			return err
+		}
+		client.WebOrigins = append(client.WebOrigins, webOrigin)
+	}
+
+	for _, permission := range permissions {
+		if err := database.CreateClientPermission(tx, &models.ClientPermission{ClientId: client.Id, PermissionId: permission.Id}); err != nil {
+			return err
+		}
+	}
+	client.Permissions = permissions
+
+	return nil
+}
+
+func deleteClientRelations(tx *sql.Tx, database database.Database, client *models.Client) error {
+	for _, redirectURI := range client.RedirectURIs {
+		if err := database.DeleteRedirectURI(tx, redirectURI.Id); err != nil {
+			return err
+		}
+	}
+
+	for _, postLogoutRedirectURI := range client.PostLogoutRedirectURIs {
+		if err := database.DeletePostLogoutRedirectURI(tx, postLogoutRedirectURI.Id); err != nil {
+			return err
+		}
+	}
+
+	for _, webOrigin := range client.WebOrigins {
+		if err := database.DeleteWebOrigin(tx, webOrigin.Id); err != nil {
+			return err
+		}
+	}
+
+	clientPermissions, err := database.GetClientPermissionsByClientId(tx, client.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, clientPermission := range clientPermissions {
+		if err = database.DeleteClientPermission(tx, clientPermission.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadClientRelations loads the redirect URIs, post logout redirect URIs, web origins and permissions of the client
+func loadClientRelations(database database.Database, client *models.Client) error {
+	if err := database.ClientLoadRedirectURIs(nil, client); err != nil {
+		return err
+	}
+
+	if err := database.ClientLoadPostLogoutRedirectURIs(nil, client); err != nil {
+		return err
+	}
+
+	if err := database.ClientLoadWebOrigins(nil, client); err != nil {
+		return err
+	}
+
+	if err := database.ClientLoadPermissions(nil, client); err != nil {
+		return err
+	}
+
+	return database.PermissionsLoadResources(nil, client.Permissions)
+}
+
+// getClientMetadata rebuilds the registered metadata from the client and its loaded relations
+func getClientMetadata(client *models.Client) *oauth.ClientMetadata {
+	metadata := &oauth.ClientMetadata{
+		ClientName:                            client.Description,
+		TokenEndpointAuthMethod:               "client_secret_basic",
+		RequirePushedAuthorizationRequests:    client.PARRequired,
+		RequireSignedRequestObject:            client.RequireSignedRequestObject,
+		FrontChannelLogoutURI:                 client.FrontChannelLogoutURI,
+		BackChannelLogoutURI:                  client.BackChannelLogoutURI,
+		SubjectType:                           client.SubjectType,
+		SectorIdentifierURI:                   client.SectorIdentifierURI,
+		IdTokenEncryptedResponseAlg:           client.IdTokenEncryptedResponseAlg,
+		IdTokenEncryptedResponseEnc:           client.IdTokenEncryptedResponseEnc,
+		UserInfoEncryptedResponseAlg:          client.UserInfoEncryptedResponseAlg,
+		UserInfoEncryptedResponseEnc:          client.UserInfoEncryptedResponseEnc,
+		AuthorizationEncryptedResponseAlg:     client.AuthorizationEncryptedResponseAlg,
+		AuthorizationEncryptedResponseEnc:     client.AuthorizationEncryptedResponseEnc,
+		BackChannelTokenDeliveryMode:          client.BackChannelTokenDeliveryMode,
+		BackChannelClientNotificationEndpoint: client.BackChannelClientNotificationEndpoint,
+		// the keys are only registered for authentication or the encryption of the responses
+		Jwks:    client.JWKS,
+		JwksURI: client.JWKSURI,
+	}
+	if client.IsPublic {
+		metadata.TokenEndpointAuthMethod = "none"
+	} else if client.UsesClientAssertion() || client.UsesTLSClientAuth() {
+		metadata.TokenEndpointAuthMethod = client.TokenEndpointAuthMethod
+		metadata.TLSClientAuthSubjectDN = client.TLSClientAuthSubjectDN
+	}
+
+	if client.AuthorizationCodeEnabled {
+		metadata.GrantTypes = append(metadata.GrantTypes, "authorization_code")
+		metadata.ResponseTypes = []string{"code"}
+	}
+
+	if client.ClientCredentialsEnabled {
+		metadata.GrantTypes = append(metadata.GrantTypes, "client_credentials")
+	}
+
+	if client.DeviceCodeEnabled {
+		metadata.GrantTypes = append(metadata.GrantTypes, constants.DeviceCodeGrantType)
+	}
+
+	if client.CIBAEnabled {
+		metadata.GrantTypes = append(metadata.GrantTypes, constants.CIBAGrantType)
+	}
+
+	// refresh tokens are issued for all the user flows
+	if client.AuthorizationCodeEnabled || client.DeviceCodeEnabled || client.CIBAEnabled {
+		metadata.GrantTypes = append(metadata.GrantTypes, "refresh_token")
+	}
+
+	for _, redirectURI := range client.RedirectURIs {
+		metadata.RedirectURIs = append(metadata.RedirectURIs, redirectURI.URI)
+	}
+
+	for _, postLogoutRedirectURI := range client.PostLogoutRedirectURIs {
+		metadata.PostLogoutRedirectURIs = append(metadata.PostLogoutRedirectURIs, postLogoutRedirectURI.URI)
+	}
+
+	for _, webOrigin := range client.WebOrigins {
+		metadata.WebOrigins = append(metadata.WebOrigins, webOrigin.Origin)
+	}
+
+	// only resource scopes are stored, as client permissions
+	scopes := make([]string, 0, len(client.Permissions))
+	for _, permission := range client.Permissions {
+		scopes = append(scopes, permission.Resource.ResourceIdentifier+":"+permission.PermissionIdentifier)
+	}
+	metadata.Scope = strings.Join(scopes, " ")
+
+	return metadata
+}
+
+func newClientRegistrationResponse(client *models.Client) *oauth.ClientRegistrationResponse {
+	resp := &oauth.ClientRegistrationResponse{
+		ClientId:              client.ClientIdentifier,
+		RegistrationClientURI: config.Get().BaseURL + "/auth/register/" + client.ClientIdentifier,
+		ClientMetadata:        *getClientMetadata(client),
+	}
+
+	if client.CreatedAt.Valid {
+		resp.ClientIdIssuedAt = client.CreatedAt.Time.Unix()
+	}
+
+	if len(client.ClientSecretEncrypted) > 0 {
+		// client secrets never expire
+		var clientSecretExpiresAt int64
+		resp.ClientSecretExpiresAt = &clientSecretExpiresAt
+	}
+
+	return resp
+}
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRegisterRequest(method string, target string, body string, settings *models.Settings, clientId string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	if len(clientId) > 0 {
+		rctx.URLParams.Add("clientId", clientId)
+	}
+
+	settings.AESEncryptionKey = testAESEncryptionKey
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, constants.ContextKeySettings, settings)
+	return req.WithContext(ctx)
+}
+
+func isErrorWithCode(code string) interface{} {
+	return mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == code
+	})
+}
+
+func newRegisteredClient(t *testing.T, registrationAccessToken string) *models.Client {
+	tokenHash, err := hashutil.HashString(registrationAccessToken)
+	assert.NoError(t, err)
+	return &models.Client{
+		Id:                          5,
+		ClientIdentifier:            "dcr-app",
+		Enabled:                     true,
+		IsPublic:                    true,
+		AuthorizationCodeEnabled:    true,
+		RegistrationAccessTokenHash: tokenHash,
+	}
+}
+
+func TestHandleRegisterPost_Disabled(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("access_denied")).Return()
+
+	handler := HandleRegisterPost(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRegisterRequest("POST", "/auth/register", `{}`, &models.Settings{}, ""))
+
+	database.AssertNotCalled(t, "CreateClient", mock.Anything, mock.Anything)
+}
+
+func TestHandleRegisterPost_InitialAccessTokenRequired(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_token")).Return()
+
+	handler := HandleRegisterPost(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRegisterRequest("POST", "/auth/register", `{}`, &models.Settings{DynamicClientRegistrationEnabled: true}, ""))
+
+	assert.Contains(t, rr.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+	validator.AssertNotCalled(t, "ValidateClientMetadata", mock.Anything, mock.Anything)
+}
+
+func TestHandleRegisterPost_ExpiredInitialAccessToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	tokenHash, _ := hashutil.HashString("initial-token")
+	database.On("GetInitialAccessTokenByTokenHash", mock.Anything, tokenHash).Return(&models.InitialAccessToken{
+		Id:        3,
+		TokenHash: tokenHash,
+		ExpiresAt: sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true},
+	}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_token")).Return()
+
+	req := newRegisterRequest("POST", "/auth/register", `{}`, &models.Settings{DynamicClientRegistrationEnabled: true, DynamicClientRegistrationOpen: true}, "")
+	req.Header.Set("Authorization", "Bearer initial-token")
+	handler := HandleRegisterPost(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	validator.AssertNotCalled(t, "ValidateClientMetadata", mock.Anything, mock.Anything)
+}
+
+func TestHandleRegisterPost_OpenRegistration(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	tx := &sql.Tx{}
+	validator.On("ValidateClientMetadata", mock.Anything, false).Run(func(args mock.Arguments) {
+		metadata := args.Get(0).(*oauth.ClientMetadata)
+		metadata.GrantTypes = []string{"authorization_code"}
+		metadata.ResponseTypes = []string{"code"}
+	}).Return(nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("CreateClient", tx, mock.MatchedBy(func(c *models.Client) bool {
+		return strings.HasPrefix(c.ClientIdentifier, "dcr-") && c.Enabled && c.ConsentRequired && c.IsPublic &&
+			c.AuthorizationCodeEnabled && !c.ClientCredentialsEnabled && c.Description == "My App" &&
+			len(c.ClientSecretEncrypted) == 0 && c.IsDynamicallyRegistered()
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).Id = 5
+	}).Return(nil)
+	database.On("CreateRedirectURI", tx, &models.RedirectURI{ClientId: 5, URI: "https://app.example.com/callback"}).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+	auditLogger.On("Log", constants.AuditRegisteredClient, mock.Anything).Return()
+
+	var resp *oauth.ClientRegistrationResponse
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.AnythingOfType("*oauth.ClientRegistrationResponse")).Run(func(args mock.Arguments) {
+		resp = args.Get(2).(*oauth.ClientRegistrationResponse)
+	}).Return()
+
+	body := `{"client_name":"My App","redirect_uris":["https://app.example.com/callback"],"token_endpoint_auth_method":"none"}`
+	handler := HandleRegisterPost(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRegisterRequest("POST", "/auth/register", body, &models.Settings{DynamicClientRegistrationEnabled: true, DynamicClientRegistrationOpen: true}, ""))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	if assert.NotNil(t, resp) {
+		assert.Empty(t, resp.ClientSecret)
+		assert.Nil(t, resp.ClientSecretExpiresAt)
+		assert.NotEmpty(t, resp.RegistrationAccessToken)
+		assert.True(t, strings.HasSuffix(resp.RegistrationClientURI, "/auth/register/"+resp.ClientId))
+		assert.Equal(t, "none", resp.TokenEndpointAuthMethod)
+		assert.Equal(t, []string{"authorization_code", "refresh_token"}, resp.GrantTypes)
+		assert.Equal(t, []string{"https://app.example.com/callback"}, resp.RedirectURIs)
+	}
+}
+
+func TestHandleRegisterPost_WithInitialAccessToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	tx := &sql.Tx{}
+	tokenHash, _ := hashutil.HashString("initial-token")
+	database.On("GetInitialAccessTokenByTokenHash", mock.Anything, tokenHash).Return(&models.InitialAccessToken{
+		Id:        3,
+		TokenHash: tokenHash,
+		ExpiresAt: sql.NullTime{Time: time.Now().UTC().Add(time.Hour), Valid: true},
+	}, nil)
+	validator.On("ValidateClientMetadata", mock.Anything, true).Run(func(args mock.Arguments) {
+		metadata := args.Get(0).(*oauth.ClientMetadata)
+		metadata.TokenEndpointAuthMethod = "client_secret_basic"
+	}).Return(nil)
+	database.On("GetResourceByResourceIdentifier", mock.Anything, "orders").Return(&models.Resource{Id: 2, ResourceIdentifier: "orders"}, nil)
+	database.On("GetPermissionsByResourceId", mock.Anything, int64(2)).Return([]models.Permission{
+		{Id: 8, PermissionIdentifier: "read", ResourceId: 2},
+		{Id: 9, PermissionIdentifier: "write", ResourceId: 2},
+	}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("CreateClient", tx, mock.MatchedBy(func(c *models.Client) bool {
+		return c.ClientCredentialsEnabled && !c.IsPublic && len(c.ClientSecretEncrypted) > 0
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).Id = 5
+	}).Return(nil)
+	database.On("CreateClientPermission", tx, &models.ClientPermission{ClientId: 5, PermissionId: 8}).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+	auditLogger.On("Log", constants.AuditRegisteredClient, mock.MatchedBy(func(details map[string]interface{}) bool {
+		return details["initialAccessTokenId"] == int64(3)
+	})).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp *oauth.ClientRegistrationResponse) bool {
+		return len(resp.ClientSecret) > 0 && resp.ClientSecretExpiresAt != nil && *resp.ClientSecretExpiresAt == 0 &&
+			resp.Scope == "orders:read" && assert.ObjectsAreEqual([]string{"client_credentials"}, resp.GrantTypes)
+	})).Return()
+
+	req := newRegisterRequest("POST", "/auth/register", `{"grant_types":["client_credentials"],"scope":"orders:read"}`, &models.Settings{DynamicClientRegistrationEnabled: true}, "")
+	req.Header.Set("Authorization", "Bearer initial-token")
+	handler := HandleRegisterPost(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestHandleRegisterClientGet_InvalidToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(newRegisteredClient(t, "registration-token"), nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_token")).Return()
+
+	req := newRegisterRequest("GET", "/auth/register/dcr-app", "", &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	handler := HandleRegisterClientGet(httpHelper, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	database.AssertNotCalled(t, "ClientLoadRedirectURIs", mock.Anything, mock.Anything)
+}
+
+func TestHandleRegisterClientGet_AdminCreatedClient(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+
+	// clients created through the admin API have no registration access token
+	client := newRegisteredClient(t, "registration-token")
+	client.RegistrationAccessTokenHash = ""
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(client, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_token")).Return()
+
+	req := newRegisterRequest("GET", "/auth/register/dcr-app", "", &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer registration-token")
+	handler := HandleRegisterClientGet(httpHelper, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+}
+
+func TestHandleRegisterClientGet(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(newRegisteredClient(t, "registration-token"), nil)
+	database.On("ClientLoadRedirectURIs", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).RedirectURIs = []models.RedirectURI{{Id: 1, URI: "https://app.example.com/callback"}}
+	}).Return(nil)
+	database.On("ClientLoadPostLogoutRedirectURIs", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).PostLogoutRedirectURIs = []models.PostLogoutRedirectURI{{Id: 1, URI: "https://app.example.com/signed-out"}}
+	}).Return(nil)
+	database.On("ClientLoadWebOrigins", mock.Anything, mock.Anything).Return(nil)
+	database.On("ClientLoadPermissions", mock.Anything, mock.Anything).Return(nil)
+	database.On("PermissionsLoadResources", mock.Anything, mock.Anything).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp *oauth.ClientRegistrationResponse) bool {
+		return resp.ClientId == "dcr-app" && resp.RegistrationAccessToken == "" && resp.TokenEndpointAuthMethod == "none" &&
+			assert.ObjectsAreEqual([]string{"https://app.example.com/callback"}, resp.RedirectURIs) &&
+			assert.ObjectsAreEqual([]string{"https://app.example.com/signed-out"}, resp.PostLogoutRedirectURIs)
+	})).Return()
+
+	req := newRegisterRequest("GET", "/auth/register/dcr-app", "", &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer registration-token")
+	handler := HandleRegisterClientGet(httpHelper, database)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestHandleRegisterClientPut_CannotAddResourceScope(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(newRegisteredClient(t, "registration-token"), nil)
+	database.On("ClientLoadRedirectURIs", mock.Anything, mock.Anything).Return(nil)
+	database.On("ClientLoadPostLogoutRedirectURIs", mock.Anything, mock.Anything).Return(nil)
+	database.On("ClientLoadWebOrigins", mock.Anything, mock.Anything).Return(nil)
+	database.On("ClientLoadPermissions", mock.Anything, mock.Anything).Return(nil)
+	database.On("PermissionsLoadResources", mock.Anything, mock.Anything).Return(nil)
+	validator.On("ValidateClientMetadata", mock.Anything, true).Return(nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_client_metadata")).Return()
+
+	body := `{"client_id":"dcr-app","redirect_uris":["https://app.example.com/callback"],"scope":"openid orders:read"}`
+	req := newRegisterRequest("PUT", "/auth/register/dcr-app", body, &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer registration-token")
+	handler := HandleRegisterClientPut(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	database.AssertNotCalled(t, "UpdateClient", mock.Anything, mock.Anything)
+}
+
+func TestHandleRegisterClientPut_ClientIdMismatch(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(newRegisteredClient(t, "registration-token"), nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, isErrorWithCode("invalid_request")).Return()
+
+	req := newRegisterRequest("PUT", "/auth/register/dcr-app", `{"client_id":"other-app"}`, &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer registration-token")
+	handler := HandleRegisterClientPut(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	validator.AssertNotCalled(t, "ValidateClientMetadata", mock.Anything, mock.Anything)
+}
+
+func TestHandleRegisterClientPut(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	validator := validatorsMocks.NewClientRegistrationValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	tx := &sql.Tx{}
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(newRegisteredClient(t, "registration-token"), nil)
+	database.On("ClientLoadRedirectURIs", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Client).RedirectURIs = []models.RedirectURI{{Id: 1, URI: "https://old.example.com/callback"}}
+	}).Return(nil)
+	database.On("ClientLoadPostLogoutRedirectURIs", mock.Anything, mock.Anything).Return(nil)
+	database.On("ClientLoadWebOrigins", mock.Anything, mock.Anything).Return(nil)
+	database.On("ClientLoadPermissions", mock.Anything, mock.Anything).Return(nil)
+	database.On("PermissionsLoadResources", mock.Anything, mock.Anything).Return(nil)
+	validator.On("ValidateClientMetadata", mock.Anything, true).Run(func(args mock.Arguments) {
+		metadata := args.Get(0).(*oauth.ClientMetadata)
+		metadata.GrantTypes = []string{"authorization_code"}
+		metadata.TokenEndpointAuthMethod = "client_secret_basic"
+	}).Return(nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("UpdateClient", tx, mock.MatchedBy(func(c *models.Client) bool {
+		return !c.IsPublic && len(c.ClientSecretEncrypted) > 0 && c.Description == "Renamed"
+	})).Return(nil)
+	database.On("DeleteRedirectURI", tx, int64(1)).Return(nil)
+	database.On("GetClientPermissionsByClientId", tx, int64(5)).Return([]models.ClientPermission{}, nil)
+	database.On("CreateRedirectURI", tx, &models.RedirectURI{ClientId: 5, URI: "https://new.example.com/callback"}).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+	auditLogger.On("Log", constants.AuditUpdatedRegisteredClient, map[string]interface{}{"clientId": int64(5)}).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(resp *oauth.ClientRegistrationResponse) bool {
+		// switching from a public to a confidential client issues a new secret
+		return len(resp.ClientSecret) > 0 && resp.TokenEndpointAuthMethod == "client_secret_basic" &&
+			assert.ObjectsAreEqual([]string{"https://new.example.com/callback"}, resp.RedirectURIs)
+	})).Return()
+
+	body := `{"client_id":"dcr-app","client_name":"Renamed","redirect_uris":["https://new.example.com/callback"]}`
+	req := newRegisterRequest("PUT", "/auth/register/dcr-app", body, &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer registration-token")
+	handler := HandleRegisterClientPut(httpHelper, database, validator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestHandleRegisterClientDelete(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "dcr-app").Return(newRegisteredClient(t, "registration-token"), nil)
+	database.On("DeleteClient", mock.Anything, int64(5)).Return(nil)
+	auditLogger.On("Log", constants.AuditDeletedRegisteredClient, mock.Anything).Return()
+
+	req := newRegisterRequest("DELETE", "/auth/register/dcr-app", "", &models.Settings{DynamicClientRegistrationEnabled: true}, "dcr-app")
+	req.Header.Set("Authorization", "Bearer registration-token")
+	handler := HandleRegisterClientDelete(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
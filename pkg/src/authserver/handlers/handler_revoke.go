@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleRevokePost(httpHelper HttpHelper, database database.Database, tokenParser TokenParser, auditLogger AuditLogger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := authenticateClient(r, database, true)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		tokenStr := r.PostFormValue("token")
+		if len(tokenStr) == 0 {
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Missing required token parameter.", http.StatusBadRequest))
+			return
+		}
+
+		// invalid, expired or already revoked tokens need no further action (RFC 7009, section 2.2).
+		// token_type_hint is only advisory, the actual type is taken from the typ claim
+		token, err := tokenParser.DecodeAndValidateTokenString(tokenStr, nil, false)
+		if err != nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		switch token.GetStringClaim("typ") {
+		case enums.TokenTypeBearer.String():
+			err = revokeAccessToken(database, client, token, auditLogger)
+		case "Refresh", "Offline":
+			err = revokeRefreshToken(database, client, token, auditLogger)
+		default:
+			err = customerrors.NewErrorDetailWithHttpStatusCode("unsupported_token_type",
+				"Only access tokens and refresh tokens can be revoked.", http.StatusBadRequest)
+		}
+
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// revokeAccessToken adds the access token to the deny list until it expires
+func revokeAccessToken(database database.Database, client *models.Client, token *oauth.Jwt, auditLogger AuditLogger) error {
+	if token.GetStringClaim("client_id") != client.ClientIdentifier {
+		return customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+			"The token was not issued to this client.", http.StatusBadRequest)
+	}
+
+	expiresAt := token.GetTimeClaim("exp").UTC()
+	if !expiresAt.After(time.Now().UTC()) {
+		return nil
+	}
+
+	jti := token.GetStringClaim("jti")
+	if err := database.CreateRevokedAccessToken(nil, &models.RevokedAccessToken{
+		Jti:       jti,
+		ExpiresAt: sql.NullTime{Time: expiresAt, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	auditLogger.Log(constants.AuditRevokedAccessToken, map[string]interface{}{
+		"clientId": client.Id,
+		"jti":      jti,
+	})
+
+	return nil
+}
+
+// revokeRefreshToken revokes the refresh token along with every token
+// of its chain (all tokens sharing the same first refresh token)
+func revokeRefreshToken(database database.Database, client *models.Client, token *oauth.Jwt, auditLogger AuditLogger) error {
+	refreshToken, err := database.GetRefreshTokenByJti(nil, token.GetStringClaim("jti"))
+	if err != nil {
+		return err
+	} else if refreshToken == nil {
+		return nil
+	}
+
+	if err = database.RefreshTokenLoadCode(nil, refreshToken); err != nil {
+		return err
+	} else if refreshToken.Code.ClientId != client.Id {
+		return customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+			"The token was not issued to this client.", http.StatusBadRequest)
+	}
+
+	if err = database.RevokeRefreshTokensByFirstRefreshTokenJti(nil, refreshToken.FirstRefreshTokenJti); err != nil {
+		return err
+	}
+
+	auditLogger.Log(constants.AuditRevokedRefreshToken, map[string]interface{}{
+		"clientId":             client.Id,
+		"userId":               refreshToken.Code.UserId,
+		"firstRefreshTokenJti": refreshToken.FirstRefreshTokenJti,
+	})
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	mocksOAuth "github.com/pchchv/aas/pkg/src/oauth/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRevokeRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/auth/revoke", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{AESEncryptionKey: testAESEncryptionKey})
+	return req.WithContext(ctx)
+}
+
+func TestHandleRevokePost_InvalidTokenIsIgnored(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "spa").Return(&models.Client{Id: 1, ClientIdentifier: "spa", Enabled: true, IsPublic: true}, nil)
+	tokenParser.On("DecodeAndValidateTokenString", "garbage", mock.Anything, false).Return(nil, errors.New("token is malformed"))
+
+	handler := HandleRevokePost(httpHelper, database, tokenParser, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRevokeRequest(url.Values{"client_id": {"spa"}, "token": {"garbage"}}))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	database.AssertNotCalled(t, "CreateRevokedAccessToken", mock.Anything, mock.Anything)
+}
+
+func TestHandleRevokePost_PublicClientWithSecret(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "spa").Return(&models.Client{Id: 1, ClientIdentifier: "spa", Enabled: true, IsPublic: true}, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == "invalid_client"
+	})).Return()
+
+	handler := HandleRevokePost(httpHelper, database, tokenParser, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRevokeRequest(url.Values{"client_id": {"spa"}, "client_secret": {"secret"}, "token": {"token"}}))
+
+	tokenParser.AssertNotCalled(t, "DecodeAndValidateTokenString", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleRevokePost_AccessToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	token := &oauth.Jwt{Claims: map[string]interface{}{
+		"typ":       "Bearer",
+		"client_id": "resource-server",
+		"jti":       "access-jti",
+		"exp":       float64(exp),
+	}}
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+	tokenParser.On("DecodeAndValidateTokenString", "access-token", mock.Anything, false).Return(token, nil)
+	database.On("CreateRevokedAccessToken", mock.Anything, mock.MatchedBy(func(revokedAccessToken *models.RevokedAccessToken) bool {
+		return revokedAccessToken.Jti == "access-jti" && revokedAccessToken.ExpiresAt.Time.Unix() == exp
+	})).Return(nil)
+	auditLogger.On("Log", constants.AuditRevokedAccessToken, mock.Anything).Return()
+
+	handler := HandleRevokePost(httpHelper, database, tokenParser, auditLogger)
+	rr := httptest.NewRecorder()
+	req := newRevokeRequest(url.Values{"token": {"access-token"}, "token_type_hint": {"access_token"}})
+	req.SetBasicAuth("resource-server", "secret")
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestHandleRevokePost_AccessTokenOfAnotherClient(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	token := &oauth.Jwt{Claims: map[string]interface{}{
+		"typ":       "Bearer",
+		"client_id": "web-app",
+		"jti":       "access-jti",
+		"exp":       float64(time.Now().Add(time.Hour).Unix()),
+	}}
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "resource-server").Return(newIntrospectClient(t, 1, "resource-server"), nil)
+	tokenParser.On("DecodeAndValidateTokenString", "access-token", mock.Anything, false).Return(token, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == "unauthorized_client"
+	})).Return()
+
+	handler := HandleRevokePost(httpHelper, database, tokenParser, auditLogger)
+	rr := httptest.NewRecorder()
+	req := newRevokeRequest(url.Values{"token": {"access-token"}})
+	req.SetBasicAuth("resource-server", "secret")
+	handler.ServeHTTP(rr, req)
+
+	database.AssertNotCalled(t, "CreateRevokedAccessToken", mock.Anything, mock.Anything)
+}
+
+func TestHandleRevokePost_RefreshTokenRevokesChain(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenParser := mocksOAuth.NewTokenParser(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	token := &oauth.Jwt{Claims: map[string]interface{}{"typ": "Refresh", "jti": "third-jti"}}
+	refreshToken := &models.RefreshToken{
+		Id:                      3,
+		CodeId:                  7,
+		RefreshTokenJti:         "third-jti",
+		PreviousRefreshTokenJti: "second-jti",
+		FirstRefreshTokenJti:    "first-jti",
+		ExpiresAt:               sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	database.On("GetClientByClientIdentifier", mock.Anything, "spa").Return(&models.Client{Id: 1, ClientIdentifier: "spa", Enabled: true, IsPublic: true}, nil)
+	tokenParser.On("DecodeAndValidateTokenString", "refresh-token", mock.Anything, false).Return(token, nil)
+	database.On("GetRefreshTokenByJti", mock.Anything, "third-jti").Return(refreshToken, nil)
+	database.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.RefreshToken).Code = models.Code{Id: 7, ClientId: 1, UserId: 9}
+	}).Return(nil)
+	database.On("RevokeRefreshTokensByFirstRefreshTokenJti", mock.Anything, "first-jti").Return(nil)
+	auditLogger.On("Log", constants.AuditRevokedRefreshToken, mock.Anything).Return()
+
+	handler := HandleRevokePost(httpHelper, database, tokenParser, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRevokeRequest(url.Values{"client_id": {"spa"}, "token": {"refresh-token"}, "token_type_hint": {"refresh_token"}}))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+func HandleTokenPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	tokenIssuer TokenIssuer,
+	tokenValidator TokenValidator,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// tokens must never be cached by intermediaries
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		clientId, clientSecret := getClientCredentials(r)
+		input := validators.ValidateTokenRequestInput{
+			GrantType:           r.PostFormValue("grant_type"),
+			Code:                r.PostFormValue("code"),
+			RedirectURI:         r.PostFormValue("redirect_uri"),
+			CodeVerifier:        r.PostFormValue("code_verifier"),
+			ClientId:            clientId,
+			ClientSecret:        clientSecret,
+			ClientAssertionType: r.PostFormValue("client_assertion_type"),
+			ClientAssertion:     r.PostFormValue("client_assertion"),
+			ClientCertificates:  oauth.GetClientCertificates(r),
+			DPoPProofs:          r.Header.Values(constants.DPoPHeaderName),
+			Scope:               r.PostFormValue("scope"),
+			Resources:           r.PostForm["resource"],
+			RefreshToken:        r.PostFormValue("refresh_token"),
+			DeviceCode:          r.PostFormValue("device_code"),
+			AuthReqId:           r.PostFormValue("auth_req_id"),
+			SubjectToken:        r.PostFormValue("subject_token"),
+			SubjectTokenType:    r.PostFormValue("subject_token_type"),
+			ActorToken:          r.PostFormValue("actor_token"),
+			ActorTokenType:      r.PostFormValue("actor_token_type"),
+			RequestedTokenType:  r.PostFormValue("requested_token_type"),
+			Audience:            r.PostForm["audience"],
+		}
+
+		if len(input.DPoPProofs) > 0 {
+			// a fresh nonce for the next DPoP proof, also needed to retry after a use_dpop_nonce error
+			settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+			w.Header().Set(constants.DPoPNonceHeaderName, oauth.NewDPoPNonce(settings.AESEncryptionKey))
+		}
+
+		validateResult, err := tokenValidator.ValidateTokenRequest(r.Context(), &input)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		ctx := r.Context()
+		if len(input.ClientCertificates) > 0 {
+			// tokens issued over mutual TLS are bound to the client certificate (RFC 8705, section 3)
+			ctx = context.WithValue(ctx, constants.ContextKeyClientCertificateThumbprint,
+				oauth.CertificateThumbprint(input.ClientCertificates[0]))
+		}
+
+		if len(validateResult.DPoPKeyThumbprint) > 0 {
+			// tokens issued with a DPoP proof are bound to its key (RFC 9449, section 5)
+			ctx = context.WithValue(ctx, constants.ContextKeyDPoPKeyThumbprint, validateResult.DPoPKeyThumbprint)
+		}
+
+		var tokenResponse *oauth.TokenResponse
+		switch input.GrantType {
+		case "authorization_code":
+			// a code can only be exchanged once
+			if err = markCodeAsUsed(database, validateResult.CodeEntity, "Code is invalid."); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			if tokenResponse, err = tokenIssuer.GenerateTokenResponseForAuthCode(ctx, validateResult.CodeEntity); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditTokenIssuedAuthorizationCodeResponse, map[string]interface{}{
+				"codeId":   validateResult.CodeEntity.Id,
+				"clientId": validateResult.CodeEntity.ClientId,
+				"userId":   validateResult.CodeEntity.UserId,
+			})
+		case constants.DeviceCodeGrantType:
+			// both the device code and its authorization code can only be exchanged once. Only one
+			// of concurrent polls moves the device code from approved to used.
+			if updated, err := database.UpdateDeviceCodeStatus(nil, validateResult.DeviceCode.Id,
+				enums.DeviceCodeStatusApproved.String(), enums.DeviceCodeStatusUsed.String()); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			} else if !updated {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+					"The device code has already been used.", http.StatusBadRequest))
+				return
+			}
+			validateResult.DeviceCode.Status = enums.DeviceCodeStatusUsed.String()
+
+			if err = markCodeAsUsed(database, validateResult.CodeEntity, "The device code is invalid."); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			if tokenResponse, err = tokenIssuer.GenerateTokenResponseForAuthCode(ctx, validateResult.CodeEntity); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditTokenIssuedDeviceCodeResponse, map[string]interface{}{
+				"codeId":       validateResult.CodeEntity.Id,
+				"deviceCodeId": validateResult.DeviceCode.Id,
+				"clientId":     validateResult.CodeEntity.ClientId,
+				"userId":       validateResult.CodeEntity.UserId,
+			})
+		case constants.CIBAGrantType:
+			// both the auth_req_id and its authorization code can only be exchanged once. Only one
+			// of concurrent polls moves the CIBA request from approved to used.
+			if updated, err := database.UpdateCIBARequestStatus(nil, validateResult.CIBARequest.Id,
+				enums.CIBARequestStatusApproved.String(), enums.CIBARequestStatusUsed.String()); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			} else if !updated {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+					"The auth_req_id has already been used.", http.StatusBadRequest))
+				return
+			}
+			validateResult.CIBARequest.Status = enums.CIBARequestStatusUsed.String()
+
+			if err = markCodeAsUsed(database, validateResult.CodeEntity, "The auth_req_id is invalid."); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			if tokenResponse, err = tokenIssuer.GenerateTokenResponseForAuthCode(ctx, validateResult.CodeEntity); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditTokenIssuedCIBAResponse, map[string]interface{}{
+				"codeId":        validateResult.CodeEntity.Id,
+				"cibaRequestId": validateResult.CIBARequest.Id,
+				"clientId":      validateResult.CodeEntity.ClientId,
+				"userId":        validateResult.CodeEntity.UserId,
+			})
+		case "client_credentials":
+			if tokenResponse, err = tokenIssuer.GenerateTokenResponseForClientCred(ctx, validateResult.Client, validateResult.Scope); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditTokenIssuedClientCredentialsResponse, map[string]interface{}{
+				"clientId": validateResult.Client.Id,
+			})
+		case constants.TokenExchangeGrantType:
+			if tokenResponse, err = tokenIssuer.GenerateTokenResponseForTokenExchange(ctx, &oauth.GenerateTokenForTokenExchangeInput{
+				Client:  validateResult.Client,
+				Subject: validateResult.Subject,
+				Scope:   validateResult.Scope,
+				Actor:   validateResult.Actor,
+			}); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			// the current actor is the outermost act claim, there is none when the client impersonates the subject
+			actor, _ := validateResult.Actor["sub"].(string)
+			auditLogger.Log(constants.AuditTokenIssuedTokenExchangeResponse, map[string]interface{}{
+				"clientId":      validateResult.Client.Id,
+				"subject":       validateResult.Subject,
+				"actor":         actor,
+				"impersonation": validateResult.Actor == nil,
+			})
+		case "refresh_token":
+			// refresh tokens are single use, presenting this one again is a reuse. Only one of
+			// concurrent refreshes supersedes it, so the chain can't split into two valid branches.
+			if superseded, err := database.SupersedeRefreshToken(nil, validateResult.RefreshToken.Id); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			} else if !superseded {
+				httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+					"The refresh token has already been used. Please use the refresh token issued in its place.", http.StatusBadRequest))
+				return
+			}
+
+			if tokenResponse, err = tokenIssuer.GenerateTokenResponseForRefresh(ctx, &oauth.GenerateTokenForRefreshInput{
+				Code:             validateResult.CodeEntity,
+				RefreshToken:     validateResult.RefreshToken,
+				RefreshTokenInfo: validateResult.RefreshTokenInfo,
+				ScopeRequested:   input.Scope,
+				Resource:         validateResult.Resource,
+			}); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+
+			auditLogger.Log(constants.AuditTokenIssuedRefreshTokenResponse, map[string]interface{}{
+				"codeId":   validateResult.CodeEntity.Id,
+				"clientId": validateResult.CodeEntity.ClientId,
+				"userId":   validateResult.CodeEntity.UserId,
+			})
+		}
+
+		httpHelper.EncodeJson(w, r, tokenResponse)
+	}
+}
+
+// markCodeAsUsed marks the code as used. Only one of concurrent exchanges of the
+// same code gets to mark it, the others fail with the description.
+func markCodeAsUsed(database database.Database, code *models.Code, description string) error {
+	if used, err := database.MarkCodeAsUsed(nil, code.Id); err != nil {
+		return err
+	} else if !used {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", description, http.StatusBadRequest)
+	}
+
+	code.Used = true
+	return nil
+}
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	mocksOAuth "github.com/pchchv/aas/pkg/src/oauth/mocks"
+	"github.com/pchchv/aas/pkg/src/validators"
+	validatorsMocks "github.com/pchchv/aas/pkg/src/validators/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTokenRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", "/auth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{})
+	return req.WithContext(ctx)
+}
+
+func TestHandleTokenPost_ValidationError(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	validationErr := customerrors.NewErrorDetailWithHttpStatusCode("unsupported_grant_type", "Unsupported grant_type.", http.StatusBadRequest)
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(nil, validationErr)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, validationErr).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{"grant_type": {"password"}}))
+
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	httpHelper.AssertExpectations(t)
+	tokenIssuer.AssertNotCalled(t, "GenerateTokenResponseForAuthCode", mock.Anything, mock.Anything)
+}
+
+func TestHandleTokenPost_AuthorizationCodeWithBasicAuth(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	code := &models.Code{Id: 5, ClientId: 2, UserId: 3}
+	tokenResponse := &oauth.TokenResponse{AccessToken: "access", TokenType: "Bearer"}
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.MatchedBy(func(input *validators.ValidateTokenRequestInput) bool {
+		return input.ClientId == "my client" && input.ClientSecret == "s3cr3t" && input.Code == "abc"
+	})).Return(&validators.ValidateTokenRequestResult{CodeEntity: code}, nil)
+	database.On("MarkCodeAsUsed", mock.Anything, int64(5)).Return(true, nil)
+	tokenIssuer.On("GenerateTokenResponseForAuthCode", mock.Anything, code).Return(tokenResponse, nil)
+	auditLogger.On("Log", constants.AuditTokenIssuedAuthorizationCodeResponse, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, tokenResponse).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	req := newTokenRequest(url.Values{"grant_type": {"authorization_code"}, "code": {"abc"}})
+	req.SetBasicAuth(url.QueryEscape("my client"), "s3cr3t")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	httpHelper.AssertExpectations(t)
+	database.AssertExpectations(t)
+	auditLogger.AssertExpectations(t)
+}
+
+func TestHandleTokenPost_AuthorizationCodeAlreadyUsed(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	// a concurrent exchange of the same code marked it as used first
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(&validators.ValidateTokenRequestResult{
+		CodeEntity: &models.Code{Id: 5, ClientId: 2, UserId: 3},
+	}, nil)
+	database.On("MarkCodeAsUsed", mock.Anything, int64(5)).Return(false, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == "invalid_grant"
+	})).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{"grant_type": {"authorization_code"}, "code": {"abc"}}))
+
+	httpHelper.AssertExpectations(t)
+	tokenIssuer.AssertNotCalled(t, "GenerateTokenResponseForAuthCode", mock.Anything, mock.Anything)
+}
+
+func TestHandleTokenPost_RefreshTokenRevokesPrevious(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	code := &models.Code{Id: 5, ClientId: 2, UserId: 3}
+	refreshToken := &models.RefreshToken{Id: 9}
+	tokenResponse := &oauth.TokenResponse{AccessToken: "access", RefreshToken: "refresh"}
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(&validators.ValidateTokenRequestResult{
+		CodeEntity:   code,
+		RefreshToken: refreshToken,
+	}, nil)
+	tokenIssuer.On("GenerateTokenResponseForRefresh", mock.Anything, mock.MatchedBy(func(input *oauth.GenerateTokenForRefreshInput) bool {
+		return input.ScopeRequested == "openid" && input.RefreshToken == refreshToken
+	})).Return(tokenResponse, nil)
+	database.On("SupersedeRefreshToken", mock.Anything, int64(9)).Return(true, nil)
+	auditLogger.On("Log", constants.AuditTokenIssuedRefreshTokenResponse, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, tokenResponse).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"x"}, "scope": {"openid"}}))
+
+	database.AssertExpectations(t)
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleTokenPost_RefreshTokenAlreadySuperseded(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	// a concurrent refresh with the same token superseded it first
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(&validators.ValidateTokenRequestResult{
+		CodeEntity:   &models.Code{Id: 5, ClientId: 2, UserId: 3},
+		RefreshToken: &models.RefreshToken{Id: 9},
+	}, nil)
+	database.On("SupersedeRefreshToken", mock.Anything, int64(9)).Return(false, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetCode() == "invalid_grant"
+	})).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"x"}}))
+
+	httpHelper.AssertExpectations(t)
+	tokenIssuer.AssertNotCalled(t, "GenerateTokenResponseForRefresh", mock.Anything, mock.Anything)
+}
+
+func TestHandleTokenPost_DeviceCode(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	code := &models.Code{Id: 5, ClientId: 2, UserId: 3}
+	deviceCode := &models.DeviceCode{Id: 8, ClientId: 2, Status: "approved"}
+	tokenResponse := &oauth.TokenResponse{AccessToken: "access", TokenType: "Bearer"}
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.MatchedBy(func(input *validators.ValidateTokenRequestInput) bool {
+		return input.GrantType == constants.DeviceCodeGrantType && input.DeviceCode == "device-code"
+	})).Return(&validators.ValidateTokenRequestResult{CodeEntity: code, DeviceCode: deviceCode}, nil)
+	database.On("MarkCodeAsUsed", mock.Anything, int64(5)).Return(true, nil)
+	database.On("UpdateDeviceCodeStatus", mock.Anything, int64(8), "approved", "used").Return(true, nil)
+	tokenIssuer.On("GenerateTokenResponseForAuthCode", mock.Anything, code).Return(tokenResponse, nil)
+	auditLogger.On("Log", constants.AuditTokenIssuedDeviceCodeResponse, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, tokenResponse).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{
+		"grant_type":  {constants.DeviceCodeGrantType},
+		"device_code": {"device-code"},
+		"client_id":   {"tv-app"},
+	}))
+
+	database.AssertExpectations(t)
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleTokenPost_DeviceCodeAlreadyUsed(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	// a concurrent poll with the same device code exchanged it first
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(&validators.ValidateTokenRequestResult{
+		CodeEntity: &models.Code{Id: 5, ClientId: 2, UserId: 3},
+		DeviceCode: &models.DeviceCode{Id: 8, ClientId: 2, Status: "approved"},
+	}, nil)
+	database.On("UpdateDeviceCodeStatus", mock.Anything, int64(8), "approved", "used").Return(false, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetDescription() == "The device code has already been used."
+	})).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{
+		"grant_type":  {constants.DeviceCodeGrantType},
+		"device_code": {"device-code"},
+		"client_id":   {"tv-app"},
+	}))
+
+	httpHelper.AssertExpectations(t)
+	database.AssertNotCalled(t, "MarkCodeAsUsed", mock.Anything, mock.Anything)
+	tokenIssuer.AssertNotCalled(t, "GenerateTokenResponseForAuthCode", mock.Anything, mock.Anything)
+}
+
+func TestHandleTokenPost_CIBA(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	code := &models.Code{Id: 5, ClientId: 2, UserId: 3}
+	cibaRequest := &models.CIBARequest{Id: 7, ClientId: 2, Status: "approved"}
+	tokenResponse := &oauth.TokenResponse{AccessToken: "access", TokenType: "Bearer"}
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(&validators.ValidateTokenRequestResult{
+		CodeEntity:  code,
+		CIBARequest: cibaRequest,
+	}, nil)
+	database.On("UpdateCIBARequestStatus", mock.Anything, int64(7), "approved", "used").Return(true, nil)
+	database.On("MarkCodeAsUsed", mock.Anything, int64(5)).Return(true, nil)
+	tokenIssuer.On("GenerateTokenResponseForAuthCode", mock.Anything, code).Return(tokenResponse, nil)
+	auditLogger.On("Log", constants.AuditTokenIssuedCIBAResponse, mock.Anything).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, tokenResponse).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{"grant_type": {constants.CIBAGrantType}, "auth_req_id": {"auth-req-id"}}))
+
+	database.AssertExpectations(t)
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleTokenPost_CIBAAlreadyUsed(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	// a concurrent poll with the same auth_req_id exchanged it first
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.Anything).Return(&validators.ValidateTokenRequestResult{
+		CodeEntity:  &models.Code{Id: 5, ClientId: 2, UserId: 3},
+		CIBARequest: &models.CIBARequest{Id: 7, ClientId: 2, Status: "approved"},
+	}, nil)
+	database.On("UpdateCIBARequestStatus", mock.Anything, int64(7), "approved", "used").Return(false, nil)
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		var errorDetail *customerrors.ErrorDetail
+		return errors.As(err, &errorDetail) && errorDetail.GetDescription() == "The auth_req_id has already been used."
+	})).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{"grant_type": {constants.CIBAGrantType}, "auth_req_id": {"auth-req-id"}}))
+
+	httpHelper.AssertExpectations(t)
+	database.AssertNotCalled(t, "MarkCodeAsUsed", mock.Anything, mock.Anything)
+	tokenIssuer.AssertNotCalled(t, "GenerateTokenResponseForAuthCode", mock.Anything, mock.Anything)
+}
+
+func TestHandleTokenPost_TokenExchange(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	tokenIssuer := mocksOAuth.NewTokenIssuer(t)
+	tokenValidator := validatorsMocks.NewTokenValidator(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	client := &models.Client{Id: 2, ClientIdentifier: "gateway"}
+	actor := map[string]interface{}{"sub": "support-agent"}
+	tokenResponse := &oauth.TokenResponse{AccessToken: "access", IssuedTokenType: constants.TokenTypeAccessToken}
+	tokenValidator.On("ValidateTokenRequest", mock.Anything, mock.MatchedBy(func(input *validators.ValidateTokenRequestInput) bool {
+		return input.SubjectToken == "subject" && input.ActorToken == "actor" &&
+			len(input.Audience) == 2 && input.Audience[1] == "resource2"
+	})).Return(&validators.ValidateTokenRequestResult{
+		Client:  client,
+		Scope:   "resource1:read",
+		Subject: "user-subject",
+		Actor:   actor,
+	}, nil)
+	tokenIssuer.On("GenerateTokenResponseForTokenExchange", mock.Anything, &oauth.GenerateTokenForTokenExchangeInput{
+		Client:  client,
+		Subject: "user-subject",
+		Scope:   "resource1:read",
+		Actor:   actor,
+	}).Return(tokenResponse, nil)
+	auditLogger.On("Log", constants.AuditTokenIssuedTokenExchangeResponse, map[string]interface{}{
+		"clientId":      int64(2),
+		"subject":       "user-subject",
+		"actor":         "support-agent",
+		"impersonation": false,
+	}).Return()
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, tokenResponse).Return()
+
+	handler := HandleTokenPost(httpHelper, database, tokenIssuer, tokenValidator, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newTokenRequest(url.Values{
+		"grant_type":         {constants.TokenExchangeGrantType},
+		"subject_token":      {"subject"},
+		"subject_token_type": {constants.TokenTypeAccessToken},
+		"actor_token":        {"actor"},
+		"actor_token_type":   {constants.TokenTypeAccessToken},
+		"audience":           {"resource1", "resource2"},
+	}))
+
+	httpHelper.AssertExpectations(t)
+	auditLogger.AssertExpectations(t)
+}
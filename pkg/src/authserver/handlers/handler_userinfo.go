@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+)
+
+func HandleUserInfoGetPost(
+	httpHelper HttpHelper,
+	database database.Database,
+	auditLogger AuditLogger,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jwtToken, ok := r.Context().Value(constants.ContextKeyBearerToken).(oauth.Jwt)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="The access token is missing or invalid."`)
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_token",
+				"The access token is missing or invalid.", http.StatusUnauthorized))
+			return
+		}
+
+		userinfoScope := constants.AuthServerResourceIdentifier + ":" + constants.UserinfoPermissionIdentifier
+		if !jwtToken.HasScope(userinfoScope) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%v"`, userinfoScope))
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("insufficient_scope",
+				"This endpoint requires the '"+userinfoScope+"' scope.", http.StatusForbidden))
+			return
+		}
+
+		// the sub claim is pairwise when the client of the access token belongs to a sector
+		var client *models.Client
+		var err error
+		if clientId := jwtToken.GetStringClaim("client_id"); len(clientId) > 0 {
+			if client, err = database.GetClientByClientIdentifier(nil, clientId); err != nil {
+				httpHelper.JsonError(w, r, err)
+				return
+			}
+		}
+
+		sub := jwtToken.GetStringClaim("sub")
+		user, err := oauth.ResolveSubject(database, client, sub)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if user == nil || !user.Enabled {
+			if user != nil {
+				auditLogger.Log(constants.AuditUserDisabled, map[string]interface{}{
+					"userId": user.Id,
+				})
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="The user is not available."`)
+			httpHelper.JsonError(w, r, customerrors.NewErrorDetailWithHttpStatusCode("invalid_token",
+				"The user is not available.", http.StatusUnauthorized))
+			return
+		}
+
+		if err = database.UserLoadGroups(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.GroupsLoadAttributes(nil, user.Groups); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if err = database.UserLoadAttributes(nil, user); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		scope := jwtToken.GetStringClaim("scope")
+		claims := buildUserInfoClaims(user, sub, strings.Split(scope, " "))
+		oauth.AddRequestedClaims(claims, user, jwtToken.GetUserInfoClaimsRequest())
+		if client == nil || !client.EncryptsUserInfo() {
+			httpHelper.EncodeJson(w, r, claims)
+			return
+		}
+
+		// the claims are signed, then encrypted for the client (OpenID Connect Core, section 5.3.2)
+		keyPair, err := database.GetCurrentSigningKey(nil)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		userInfoJwt, err := oauth.GenerateUserInfoJwt(keyPair, settings.Issuer, client.ClientIdentifier, claims)
+		if err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		if userInfoJwt, err = oauth.EncryptForClient(settings, client, client.UserInfoEncryptedResponseAlg,
+			client.UserInfoEncryptedResponseEnc, userInfoJwt); err != nil {
+			httpHelper.JsonError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwt")
+		if _, err = w.Write([]byte(userInfoJwt)); err != nil {
+			httpHelper.JsonError(w, r, err)
+		}
+	}
+}
+
+// buildUserInfoClaims returns the claims of the user granted by the scopes. The sub claim is the one of
+// the access token, so it matches the ID token issued to the client (OpenID Connect Core, section 5.3.2).
+func buildUserInfoClaims(user *models.User, subject string, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": subject,
+	}
+
+	addIfNotEmpty := func(name string, value string) {
+		if len(strings.TrimSpace(value)) > 0 {
+			claims[name] = value
+		}
+	}
+
+	if slices.Contains(scopes, "profile") {
+		addIfNotEmpty("name", user.GetFullName())
+		addIfNotEmpty("given_name", user.GivenName)
+		addIfNotEmpty("middle_name", user.MiddleName)
+		addIfNotEmpty("family_name", user.FamilyName)
+		addIfNotEmpty("nickname", user.Nickname)
+		addIfNotEmpty("preferred_username", user.Username)
+		claims["profile"] = fmt.Sprintf("%v/account/profile", config.Get().BaseURL)
+		addIfNotEmpty("website", user.Website)
+		addIfNotEmpty("gender", user.Gender)
+		if user.BirthDate.Valid {
+			claims["birthdate"] = user.BirthDate.Time.Format("2006-01-02")
+		}
+		addIfNotEmpty("zoneinfo", user.ZoneInfo)
+		addIfNotEmpty("locale", user.Locale)
+		claims["updated_at"] = user.UpdatedAt.Time.UTC().Unix()
+	}
+
+	if slices.Contains(scopes, "email") {
+		addIfNotEmpty("email", user.Email)
+		claims["email_verified"] = user.EmailVerified
+	}
+
+	if slices.Contains(scopes, "address") && user.HasAddress() {
+		claims["address"] = user.GetAddressClaim()
+	}
+
+	if slices.Contains(scopes, "phone") {
+		addIfNotEmpty("phone_number", user.PhoneNumber)
+		claims["phone_number_verified"] = user.PhoneNumberVerified
+	}
+
+	if slices.Contains(scopes, "groups") {
+		groups := []string{}
+		for _, group := range user.Groups {
+			if group.IncludeInIdToken {
+				groups = append(groups, group.GroupIdentifier)
+			}
+		}
+
+		if len(groups) > 0 {
+			claims["groups"] = groups
+		}
+	}
+
+	if slices.Contains(scopes, "attributes") {
+		attributes := map[string]string{}
+		for _, attribute := range user.Attributes {
+			if attribute.IncludeInIdToken {
+				attributes[attribute.Key] = attribute.Value
+			}
+		}
+
+		for _, group := range user.Groups {
+			for _, attribute := range group.Attributes {
+				if attribute.IncludeInIdToken {
+					attributes[attribute.Key] = attribute.Value
+				}
+			}
+		}
+
+		if len(attributes) > 0 {
+			claims["attributes"] = attributes
+		}
+	}
+
+	return claims
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	auditMocks "github.com/pchchv/aas/pkg/src/audit/mocks"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleUserInfoGetPost_MissingToken(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		errorDetail, ok := err.(*customerrors.ErrorDetail)
+		return ok && errorDetail.GetHttpStatusCode() == http.StatusUnauthorized
+	})).Return()
+
+	handler := HandleUserInfoGetPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/userinfo", nil))
+
+	assert.Contains(t, rr.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleUserInfoGetPost_InsufficientScope(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	httpHelper.On("JsonError", mock.Anything, mock.Anything, mock.MatchedBy(func(err error) bool {
+		errorDetail, ok := err.(*customerrors.ErrorDetail)
+		return ok && errorDetail.GetCode() == "insufficient_scope"
+	})).Return()
+
+	token := oauth.Jwt{Claims: map[string]interface{}{"scope": "openid"}}
+	req := httptest.NewRequest("GET", "/userinfo", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeyBearerToken, token))
+
+	handler := HandleUserInfoGetPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+	database.AssertNotCalled(t, "GetUserBySubject", mock.Anything, mock.Anything)
+}
+
+func TestHandleUserInfoGetPost_ClaimsFollowScopes(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	subject := uuid.New()
+	user := &models.User{
+		Id:          1,
+		Enabled:     true,
+		Subject:     subject,
+		Email:       "user@example.com",
+		GivenName:   "John",
+		PhoneNumber: "+1 555",
+	}
+	database.On("GetUserBySubject", mock.Anything, subject.String()).Return(user, nil)
+	database.On("UserLoadGroups", mock.Anything, user).Return(nil)
+	database.On("GroupsLoadAttributes", mock.Anything, mock.Anything).Return(nil)
+	database.On("UserLoadAttributes", mock.Anything, user).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(claims map[string]interface{}) bool {
+		_, hasPhone := claims["phone_number"]
+		_, hasGivenName := claims["given_name"]
+		return claims["sub"] == subject.String() && claims["email"] == "user@example.com" && !hasPhone && !hasGivenName
+	})).Return()
+
+	token := oauth.Jwt{Claims: map[string]interface{}{
+		"sub":   subject.String(),
+		"scope": "openid email authserver:userinfo",
+	}}
+	req := httptest.NewRequest("GET", "/userinfo", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeyBearerToken, token))
+
+	handler := HandleUserInfoGetPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleUserInfoGetPost_RequestedClaims(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	subject := uuid.New()
+	user := &models.User{
+		Id:          1,
+		Enabled:     true,
+		Subject:     subject,
+		Email:       "user@example.com",
+		GivenName:   "John",
+		PhoneNumber: "+1 555",
+	}
+	database.On("GetUserBySubject", mock.Anything, subject.String()).Return(user, nil)
+	database.On("UserLoadGroups", mock.Anything, user).Return(nil)
+	database.On("GroupsLoadAttributes", mock.Anything, mock.Anything).Return(nil)
+	database.On("UserLoadAttributes", mock.Anything, user).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(claims map[string]interface{}) bool {
+		_, hasPhone := claims["phone_number"]
+		_, hasEmail := claims["email"]
+		return claims["sub"] == subject.String() && claims["given_name"] == "John" && !hasPhone && !hasEmail
+	})).Return()
+
+	token := oauth.Jwt{Claims: map[string]interface{}{
+		"sub":   subject.String(),
+		"scope": "openid authserver:userinfo",
+		"userinfo_claims": map[string]interface{}{
+			"given_name":   map[string]interface{}{"essential": true},
+			"phone_number": map[string]interface{}{"value": "+1 999"},
+		},
+	}}
+	req := httptest.NewRequest("GET", "/userinfo", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeyBearerToken, token))
+
+	handler := HandleUserInfoGetPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleUserInfoGetPost_PairwiseSubject(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	user := &models.User{Id: 1, Enabled: true, Subject: uuid.New()}
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(&models.Client{
+		ClientIdentifier: "web-app",
+		SubjectType:      constants.SubjectTypePairwise,
+		SectorIdentifier: "app.example.com",
+	}, nil)
+	database.On("GetPairwiseSubjectBySectorIdentifierAndSubject", mock.Anything, "app.example.com", "pairwise-subject").
+		Return(&models.PairwiseSubject{UserId: 1, SectorIdentifier: "app.example.com", Subject: "pairwise-subject"}, nil)
+	database.On("GetUserById", mock.Anything, int64(1)).Return(user, nil)
+	database.On("UserLoadGroups", mock.Anything, user).Return(nil)
+	database.On("GroupsLoadAttributes", mock.Anything, mock.Anything).Return(nil)
+	database.On("UserLoadAttributes", mock.Anything, user).Return(nil)
+	httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.MatchedBy(func(claims map[string]interface{}) bool {
+		return claims["sub"] == "pairwise-subject"
+	})).Return()
+
+	token := oauth.Jwt{Claims: map[string]interface{}{
+		"sub":       "pairwise-subject",
+		"client_id": "web-app",
+		"scope":     "openid authserver:userinfo",
+	}}
+	req := httptest.NewRequest("GET", "/userinfo", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeyBearerToken, token))
+
+	handler := HandleUserInfoGetPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	httpHelper.AssertExpectations(t)
+}
+
+func TestHandleUserInfoGetPost_EncryptedResponse(t *testing.T) {
+	httpHelper := helpersMocks.NewHttpHelper(t)
+	database := mocks.NewDatabase(t)
+	auditLogger := auditMocks.NewAuditLogger(t)
+
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+
+	settings := &models.Settings{
+		Issuer:           "https://auth.example.com",
+		AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+	}
+	clientSecretEncrypted, err := encryption.EncryptText("client-secret", settings.AESEncryptionKey)
+	require.NoError(t, err)
+
+	subject := uuid.New()
+	user := &models.User{Id: 1, Enabled: true, Subject: subject, Email: "user@example.com"}
+	database.On("GetClientByClientIdentifier", mock.Anything, "web-app").Return(&models.Client{
+		ClientIdentifier:             "web-app",
+		ClientSecretEncrypted:        clientSecretEncrypted,
+		UserInfoEncryptedResponseAlg: encryption.JWEAlgorithmA256KW,
+		UserInfoEncryptedResponseEnc: encryption.JWEEncryptionA128CBCHS256,
+	}, nil)
+	database.On("GetUserBySubject", mock.Anything, subject.String()).Return(user, nil)
+	database.On("UserLoadGroups", mock.Anything, user).Return(nil)
+	database.On("GroupsLoadAttributes", mock.Anything, mock.Anything).Return(nil)
+	database.On("UserLoadAttributes", mock.Anything, user).Return(nil)
+	database.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+		KeyIdentifier: keyPair.KeyIdentifier,
+		PrivateKeyPEM: keyPair.PrivateKeyPEM,
+	}, nil)
+
+	token := oauth.Jwt{Claims: map[string]interface{}{
+		"sub":       subject.String(),
+		"client_id": "web-app",
+		"scope":     "openid email authserver:userinfo",
+	}}
+	req := httptest.NewRequest("GET", "/userinfo", nil)
+	ctx := context.WithValue(req.Context(), constants.ContextKeyBearerToken, token)
+	req = req.WithContext(context.WithValue(ctx, constants.ContextKeySettings, settings))
+
+	handler := HandleUserInfoGetPost(httpHelper, database, auditLogger)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/jwt", rr.Header().Get("Content-Type"))
+
+	key, err := encryption.DeriveClientSecretKey("client-secret", encryption.JWEAlgorithmA256KW, encryption.JWEEncryptionA128CBCHS256)
+	require.NoError(t, err)
+	userInfoJwt, _, err := encryption.DecryptJWE(rr.Body.String(), key)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(string(userInfoJwt), claims, func(token *jwt.Token) (interface{}, error) {
+		return privateKey.(*ecdsa.PrivateKey).Public(), nil
+	}, jwt.WithIssuer("https://auth.example.com"), jwt.WithAudience("web-app"))
+	require.NoError(t, err)
+	assert.Equal(t, subject.String(), claims["sub"])
+	assert.Equal(t, "user@example.com", claims["email"])
+}
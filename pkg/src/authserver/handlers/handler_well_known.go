@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+func HandleWellKnownOIDCConfigGet(httpHelper HttpHelper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		settings := r.Context().Value(constants.ContextKeySettings).(*models.Settings)
+		baseURL := config.Get().BaseURL
+		wellKnownConfig := oidc.WellKnownConfig{
+			Issuer:                             settings.Issuer,
+			AuthorizationEndpoint:              baseURL + "/auth/authorize",
+			TokenEndpoint:                      baseURL + "/auth/token",
+			UserInfoEndpoint:                   baseURL + "/userinfo",
+			EndSessionEndpoint:                 baseURL + "/auth/logout",
+			IntrospectionEndpoint:              baseURL + "/auth/introspect",
+			RevocationEndpoint:                 baseURL + "/auth/revoke",
+			DeviceAuthorizationEndpoint:        baseURL + "/auth/device_authorization",
+			PushedAuthorizationRequestEndpoint: baseURL + "/auth/par",
+			BackChannelAuthenticationEndpoint:  baseURL + "/auth/bc-authorize",
+			JWKsURI:                            baseURL + "/certs",
+			GrantTypesSupported: []string{
+				"authorization_code", "refresh_token", "client_credentials", constants.DeviceCodeGrantType,
+				constants.TokenExchangeGrantType, constants.CIBAGrantType,
+			},
+			ResponseTypesSupported: []string{"code"},
+			ResponseModesSupported: append([]string{"query", "fragment", "form_post"}, oauth.JwtResponseModes...),
+			ACRValuesSupported: []string{
+				enums.AcrLevel1.String(),
+				enums.AcrLevel2Optional.String(),
+				enums.AcrLevel2Mandatory.String(),
+			},
+			SubjectTypesSupported:                []string{constants.SubjectTypePublic, constants.SubjectTypePairwise},
+			IdTokenSigningAlgValuesSupported:     keyutil.SupportedAlgorithms,
+			IdTokenEncryptionAlgValuesSupported:  encryption.JWEAlgorithms,
+			IdTokenEncryptionEncValuesSupported:  encryption.JWEEncryptionMethods,
+			UserInfoEncryptionAlgValuesSupported: encryption.JWEAlgorithms,
+			UserInfoEncryptionEncValuesSupported: encryption.JWEEncryptionMethods,
+			ScopesSupported: []string{
+				"openid", "profile", "email", "address", "phone", "groups", "attributes", oidc.OfflineAccessScope,
+			},
+			ClaimsSupported: []string{
+				"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "acr", "amr", "sid",
+				"name", "given_name", "middle_name", "family_name", "nickname", "preferred_username",
+				"profile", "website", "gender", "birthdate", "zoneinfo", "locale", "updated_at",
+				"email", "email_verified", "address", "phone_number", "phone_number_verified",
+				"groups", "attributes",
+			},
+			ClaimsParameterSupported: true,
+			TokenEndpointAuthMethodsSupported: []string{
+				"client_secret_post", "client_secret_basic",
+				constants.TokenEndpointAuthMethodPrivateKeyJwt, constants.TokenEndpointAuthMethodClientSecretJwt,
+			},
+			TokenEndpointAuthSigningAlgValuesSupported: validators.ClientAssertionSigningAlgorithms(),
+			CodeChallengeMethodsSupported:              []string{"S256"},
+			DPoPSigningAlgValuesSupported:              validators.DPoPSigningAlgorithms(),
+			IntrospectionEndpointAuthMethodsSupported: []string{
+				"client_secret_post", "client_secret_basic",
+				constants.TokenEndpointAuthMethodPrivateKeyJwt, constants.TokenEndpointAuthMethodClientSecretJwt,
+			},
+			RevocationEndpointAuthMethodsSupported: []string{
+				"client_secret_post", "client_secret_basic",
+				constants.TokenEndpointAuthMethodPrivateKeyJwt, constants.TokenEndpointAuthMethodClientSecretJwt, "none",
+			},
+			// request_uri values reference pushed authorization requests or, when it is configured,
+			// the local request objects directory. Client hosted URLs are never fetched.
+			RequestParameterSupported:                 true,
+			RequestURIParameterSupported:              len(config.GetAuthServer().RequestObjectsDir) > 0,
+			RequestObjectSigningAlgValuesSupported:    validators.RequestObjectSigningAlgorithms(),
+			AuthorizationSigningAlgValuesSupported:    keyutil.SupportedAlgorithms,
+			AuthorizationEncryptionAlgValuesSupported: encryption.JWEAlgorithms,
+			AuthorizationEncryptionEncValuesSupported: encryption.JWEEncryptionMethods,
+			// logout notifications always carry the iss and sid of the session
+			FrontChannelLogoutSupported:        true,
+			FrontChannelLogoutSessionSupported: true,
+			BackChannelLogoutSupported:         true,
+			BackChannelLogoutSessionSupported:  true,
+			BackChannelTokenDeliveryModesSupported: []string{
+				constants.CIBATokenDeliveryModePoll, constants.CIBATokenDeliveryModePing,
+			},
+			BackChannelUserCodeParameterSupported: false,
+		}
+
+		// mutual-TLS client authentication is only possible when the server terminates TLS itself,
+		// on a separate listener so browsers are never asked for a certificate
+		cfg := config.Get()
+		if cfg.MTLSEnabled() {
+			mtlsMethods := []string{constants.TokenEndpointAuthMethodSelfSignedTLS}
+			if len(strings.TrimSpace(cfg.ClientCAFile)) > 0 {
+				mtlsMethods = append(mtlsMethods, constants.TokenEndpointAuthMethodTLSClientAuth)
+			}
+
+			wellKnownConfig.TokenEndpointAuthMethodsSupported = append(wellKnownConfig.TokenEndpointAuthMethodsSupported, mtlsMethods...)
+			wellKnownConfig.IntrospectionEndpointAuthMethodsSupported = append(wellKnownConfig.IntrospectionEndpointAuthMethodsSupported, mtlsMethods...)
+			wellKnownConfig.RevocationEndpointAuthMethodsSupported = append(wellKnownConfig.RevocationEndpointAuthMethodsSupported, mtlsMethods...)
+			wellKnownConfig.TLSClientCertificateBoundAccessTokens = true
+			wellKnownConfig.MTLSEndpointAliases = &oidc.MTLSEndpointAliases{
+				TokenEndpoint:                      cfg.MTLSBaseURL + "/auth/token",
+				IntrospectionEndpoint:              cfg.MTLSBaseURL + "/auth/introspect",
+				RevocationEndpoint:                 cfg.MTLSBaseURL + "/auth/revoke",
+				PushedAuthorizationRequestEndpoint: cfg.MTLSBaseURL + "/auth/par",
+				BackChannelAuthenticationEndpoint:  cfg.MTLSBaseURL + "/auth/bc-authorize",
+				DeviceAuthorizationEndpoint:        cfg.MTLSBaseURL + "/auth/device_authorization",
+				UserInfoEndpoint:                   cfg.MTLSBaseURL + "/userinfo",
+			}
+		}
+
+		if settings.DynamicClientRegistrationEnabled {
+			wellKnownConfig.RegistrationEndpoint = baseURL + "/auth/register"
+		}
+
+		httpHelper.EncodeJson(w, r, wellKnownConfig)
+	}
+}
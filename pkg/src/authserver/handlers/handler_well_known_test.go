@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	helpersMocks "github.com/pchchv/aas/pkg/src/helpers/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleWellKnownOIDCConfigGet_MTLS(t *testing.T) {
+	tests := []struct {
+		name        string
+		mtlsBaseURL string
+		expectMTLS  bool
+	}{
+		{name: "Without the mutual-TLS listener", mtlsBaseURL: "", expectMTLS: false},
+		{name: "With the mutual-TLS listener", mtlsBaseURL: "https://mtls.example.com", expectMTLS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Get()
+			originalCfg := *cfg
+			t.Cleanup(func() { *cfg = originalCfg })
+			cfg.BaseURL = "https://auth.example.com"
+			cfg.CertFile = "cert.pem"
+			cfg.KeyFile = "key.pem"
+			cfg.MTLSBaseURL = tt.mtlsBaseURL
+
+			var wellKnownConfig oidc.WellKnownConfig
+			httpHelper := helpersMocks.NewHttpHelper(t)
+			httpHelper.On("EncodeJson", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				wellKnownConfig = args.Get(2).(oidc.WellKnownConfig)
+			}).Return()
+
+			req := httptest.NewRequest("GET", "/.well-known/openid-configuration", nil)
+			req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeySettings, &models.Settings{Issuer: "https://auth.example.com"}))
+			HandleWellKnownOIDCConfigGet(httpHelper).ServeHTTP(httptest.NewRecorder(), req)
+
+			// the regular endpoints never ask for a client certificate
+			assert.Equal(t, "https://auth.example.com/auth/token", wellKnownConfig.TokenEndpoint)
+			assert.Equal(t, tt.expectMTLS, wellKnownConfig.TLSClientCertificateBoundAccessTokens)
+			assert.Equal(t, tt.expectMTLS, slices.Contains(wellKnownConfig.TokenEndpointAuthMethodsSupported, constants.TokenEndpointAuthMethodSelfSignedTLS))
+			if tt.expectMTLS {
+				assert.Equal(t, "https://mtls.example.com/auth/token", wellKnownConfig.MTLSEndpointAliases.TokenEndpoint)
+				assert.Equal(t, "https://mtls.example.com/auth/par", wellKnownConfig.MTLSEndpointAliases.PushedAuthorizationRequestEndpoint)
+			} else {
+				assert.Nil(t, wellKnownConfig.MTLSEndpointAliases)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+type HttpHelper interface {
+	InternalServerError(w http.ResponseWriter, r *http.Request, err error)
+	RenderTemplate(w http.ResponseWriter, r *http.Request, layoutName string, templateName string, data map[string]interface{}) error
+	RenderTemplateToBuffer(r *http.Request, layoutName string, templateName string, data map[string]interface{}) (*bytes.Buffer, error)
+	JsonError(w http.ResponseWriter, r *http.Request, err error)
+	EncodeJson(w http.ResponseWriter, r *http.Request, data interface{})
+	GetFromUrlQueryOrFormPost(r *http.Request, key string) string
+}
+
+type AuthHelper interface {
+	GetAuthContext(r *http.Request) (*oauth.AuthContext, error)
+	SaveAuthContext(w http.ResponseWriter, r *http.Request, authContext *oauth.AuthContext) error
+	ClearAuthContext(w http.ResponseWriter, r *http.Request) error
+	GetLoggedInSubject(r *http.Request) string
+	RedirToAuthorize(w http.ResponseWriter, r *http.Request, clientIdentifier string, scope string, redirectBack string) error
+	IsAuthorizedToAccessResource(jwtInfo oauth.JwtInfo, scopesAnyOf []string) bool
+	IsAuthenticated(jwtInfo oauth.JwtInfo) bool
+}
+
+type UserSessionManager interface {
+	HasValidUserSession(ctx context.Context, userSession *models.UserSession, requestedMaxAgeInSeconds *int) bool
+	StartNewUserSession(w http.ResponseWriter, r *http.Request, userId int64, clientId int64, authMethods string, acrLevel string) (*models.UserSession, error)
+	BumpUserSession(r *http.Request, sessionIdentifier string, clientId int64) (*models.UserSession, error)
+}
+
+type AuthorizeValidator interface {
+	ValidateScopes(scope string) error
+	ValidateRequest(input *validators.ValidateRequestInput) error
+	ValidateClientAndRedirectURI(input *validators.ValidateClientAndRedirectURIInput) error
+	ValidateRequestObject(ctx context.Context, input *validators.ValidateRequestObjectInput) (*validators.ValidateRequestObjectResult, error)
+	ValidateIdTokenHint(input *validators.ValidateIdTokenHintInput) (*models.User, error)
+}
+
+type TokenValidator interface {
+	ValidateTokenRequest(ctx context.Context, input *validators.ValidateTokenRequestInput) (*validators.ValidateTokenRequestResult, error)
+}
+
+type TokenParser interface {
+	DecodeAndValidateTokenString(token string, pubKey crypto.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error)
+}
+
+type CodeIssuer interface {
+	CreateAuthCode(input *oauth.CreateCodeInput) (*models.Code, error)
+}
+
+type TokenIssuer interface {
+	GenerateTokenResponseForAuthCode(ctx context.Context, code *models.Code) (*oauth.TokenResponse, error)
+	GenerateTokenResponseForClientCred(ctx context.Context, client *models.Client, scope string) (*oauth.TokenResponse, error)
+	GenerateTokenResponseForRefresh(ctx context.Context, input *oauth.GenerateTokenForRefreshInput) (*oauth.TokenResponse, error)
+	GenerateTokenResponseForTokenExchange(ctx context.Context, input *oauth.GenerateTokenForTokenExchangeInput) (*oauth.TokenResponse, error)
+}
+
+type LogoutNotifier interface {
+	NotifyBackChannelLogout(notifications []oauth.BackChannelLogoutNotification)
+}
+
+type CIBAPingNotifier interface {
+	NotifyCIBAPing(notification oauth.CIBAPingNotification)
+}
+
+type PermissionChecker interface {
+	UserHasScopePermission(userId int64, scope string) (bool, error)
+	FilterOutScopesWhereUserIsNotAuthorized(scope string, user *models.User) (string, error)
+}
+
+type AuditLogger interface {
+	Log(auditEvent string, details map[string]interface{})
+}
+
+type OtpSecretGenerator interface {
+	GenerateOTPSecret(email string, appName string) (string, string, error)
+}
+
+type ClientRegistrationValidator interface {
+	ValidateClientMetadata(metadata *oauth.ClientMetadata, allowResourceScopes bool) error
+}
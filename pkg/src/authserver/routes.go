@@ -0,0 +1,85 @@
+package authserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pchchv/aas/pkg/src/audit"
+	"github.com/pchchv/aas/pkg/src/authserver/handlers"
+	"github.com/pchchv/aas/pkg/src/communication"
+	"github.com/pchchv/aas/pkg/src/helpers"
+	"github.com/pchchv/aas/pkg/src/middleware"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/otp"
+	"github.com/pchchv/aas/pkg/src/user"
+	"github.com/pchchv/aas/pkg/src/validators"
+)
+
+func (s *Server) initRoutes() {
+	tokenParser := oauth.NewTokenParser(s.database)
+	tokenIssuer := oauth.NewTokenIssuer(s.database, tokenParser)
+	codeIssuer := oauth.NewCodeIssuer(s.database)
+	permissionChecker := user.NewPermissionChecker(s.database)
+	userSessionManager := user.NewUserSessionManager(codeIssuer, s.sessionStore, s.database)
+	auditLogger := audit.NewAuditLogger()
+	authorizeValidator := validators.NewAuthorizeValidator(s.database)
+	clientRegistrationValidator := validators.NewClientRegistrationValidator(s.database)
+	tokenValidator := validators.NewTokenValidator(s.database, tokenParser, permissionChecker, auditLogger)
+	otpSecretGenerator := otp.NewOTPSecretGenerator()
+	logoutNotifier := oauth.NewLogoutNotifier(&http.Client{Timeout: 10 * time.Second})
+	cibaPingNotifier := oauth.NewCIBAPingNotifier(&http.Client{Timeout: 10 * time.Second})
+	authenticationDeviceNotifier := communication.NewEmailAuthenticationDeviceNotifier(communication.NewEmailSender())
+
+	httpHelper := helpers.NewHttpHelper(s.templateFS, s.database)
+	authHelper := helpers.NewAuthHelper(s.sessionStore)
+
+	jwtMiddleware := middleware.NewMiddlewareJwt(s.sessionStore, tokenParser, s.database, authHelper, &http.Client{})
+	rateLimiter := middleware.NewRateLimiterMiddleware(authHelper)
+
+	s.router.Handle("/static/*", http.StripPrefix("/static/", http.FileServer(http.FS(s.staticFS))))
+	s.router.Get("/health", handlers.HandleHealthCheckGet(httpHelper, s.database))
+	s.router.Get("/.well-known/openid-configuration", handlers.HandleWellKnownOIDCConfigGet(httpHelper))
+	s.router.Get("/certs", handlers.HandleCertsGet(httpHelper, s.database))
+
+	s.router.Route("/auth", func(r chi.Router) {
+		r.Get("/authorize", handlers.HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database, authorizeValidator))
+		r.Post("/authorize", handlers.HandleAuthorizeGet(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database, authorizeValidator))
+		r.Get("/level1", handlers.HandleAuthLevel1Get(httpHelper, authHelper))
+		r.Get("/selectaccount", handlers.HandleAuthSelectAccountGet(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database))
+		r.Post("/selectaccount", handlers.HandleAuthSelectAccountPost(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database))
+		r.Get("/pwd", handlers.HandleAuthPwdGet(httpHelper, authHelper))
+		r.With(rateLimiter.LimitPwd).Post("/pwd", handlers.HandleAuthPwdPost(httpHelper, authHelper, s.database, auditLogger))
+		r.Get("/level1completed", handlers.HandleAuthLevel1CompletedGet(httpHelper, authHelper, s.database))
+		r.Get("/level2", handlers.HandleAuthLevel2Get(httpHelper, authHelper, s.sessionStore, s.database, cibaPingNotifier))
+		r.Get("/otp", handlers.HandleAuthOtpGet(httpHelper, authHelper, s.sessionStore, s.database, otpSecretGenerator))
+		r.With(rateLimiter.LimitOtp).Post("/otp", handlers.HandleAuthOtpPost(httpHelper, authHelper, s.sessionStore, s.database, auditLogger))
+		r.Get("/level2completed", handlers.HandleAuthLevel2CompletedGet(httpHelper, authHelper))
+		r.Get("/completed", handlers.HandleAuthCompletedGet(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database, permissionChecker, cibaPingNotifier, auditLogger))
+		r.Get("/consent", handlers.HandleConsentGet(httpHelper, authHelper, s.database))
+		r.Post("/consent", handlers.HandleConsentPost(httpHelper, authHelper, s.database, cibaPingNotifier, auditLogger))
+		r.Get("/issue", handlers.HandleIssueGet(httpHelper, authHelper, s.sessionStore, s.database, codeIssuer, cibaPingNotifier, auditLogger))
+		r.Post("/token", handlers.HandleTokenPost(httpHelper, s.database, tokenIssuer, tokenValidator, auditLogger))
+		r.Post("/introspect", handlers.HandleIntrospectPost(httpHelper, s.database, tokenParser))
+		r.Get("/logout", handlers.HandleLogoutGet(httpHelper, s.sessionStore, s.database, tokenParser, logoutNotifier, auditLogger))
+		r.Post("/logout", handlers.HandleLogoutPost(httpHelper, s.sessionStore, s.database, tokenParser, logoutNotifier, auditLogger))
+		r.Post("/revoke", handlers.HandleRevokePost(httpHelper, s.database, tokenParser, auditLogger))
+		r.Post("/par", handlers.HandlePushedAuthorizationRequestPost(httpHelper, s.database, authorizeValidator))
+		r.Post("/device_authorization", handlers.HandleDeviceAuthorizationPost(httpHelper, s.database, authorizeValidator, auditLogger))
+		r.Post("/bc-authorize", handlers.HandleBackChannelAuthenticationPost(httpHelper, s.database, authorizeValidator, authenticationDeviceNotifier, auditLogger))
+		r.With(rateLimiter.LimitRegister).Post("/register", handlers.HandleRegisterPost(httpHelper, s.database, clientRegistrationValidator, auditLogger))
+		r.Get("/register/{clientId}", handlers.HandleRegisterClientGet(httpHelper, s.database))
+		r.Put("/register/{clientId}", handlers.HandleRegisterClientPut(httpHelper, s.database, clientRegistrationValidator, auditLogger))
+		r.Delete("/register/{clientId}", handlers.HandleRegisterClientDelete(httpHelper, s.database, auditLogger))
+	})
+
+	s.router.Get("/device", handlers.HandleDeviceGet(httpHelper))
+	s.router.With(rateLimiter.LimitDevice).Post("/device", handlers.HandleDevicePost(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database))
+	s.router.Get("/ciba", handlers.HandleCIBAGet(httpHelper, s.database))
+	s.router.Post("/ciba", handlers.HandleCIBAPost(httpHelper, authHelper, userSessionManager, s.sessionStore, s.database))
+
+	s.router.With(jwtMiddleware.JwtAuthorizationHeaderToContext()).Route("/userinfo", func(r chi.Router) {
+		r.Get("/", handlers.HandleUserInfoGetPost(httpHelper, s.database, auditLogger))
+		r.Post("/", handlers.HandleUserInfoGetPost(httpHelper, s.database, auditLogger))
+	})
+}
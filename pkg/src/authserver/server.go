@@ -0,0 +1,235 @@
+package authserver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/gorilla/sessions"
+	"github.com/pchchv/aas/pkg/src/audit"
+	"github.com/pchchv/aas/pkg/src/authserver/web"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/keyrotation"
+	"github.com/pchchv/aas/pkg/src/middleware"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+// mtlsEndpointPaths are the endpoints clients call with their TLS certificate,
+// to authenticate or to use certificate-bound tokens
+var mtlsEndpointPaths = []string{
+	"/auth/token", "/auth/introspect", "/auth/revoke", "/auth/par",
+	"/auth/bc-authorize", "/auth/device_authorization", "/userinfo",
+}
+
+type Server struct {
+	router       *chi.Mux
+	database     database.Database
+	sessionStore sessions.Store
+	templateFS   fs.FS
+	staticFS     fs.FS
+}
+
+func NewServer(router *chi.Mux, database database.Database, sessionStore sessions.Store) *Server {
+	return &Server{
+		router:       router,
+		database:     database,
+		sessionStore: sessionStore,
+		templateFS:   loadFS(config.Get().TemplateDir, web.TemplateFS),
+		staticFS:     loadFS(config.Get().StaticDir, web.StaticFS),
+	}
+}
+
+func (s *Server) Start(settings *models.Settings) {
+	s.initMiddleware(settings)
+	s.initRoutes()
+
+	go s.runCleanup()
+	go s.runKeyRotation()
+
+	cfg := config.Get()
+	if len(strings.TrimSpace(cfg.CertFile)) > 0 && len(strings.TrimSpace(cfg.KeyFile)) > 0 {
+		go func() {
+			addr := fmt.Sprintf("%v:%v", cfg.ListenHostHttps, cfg.ListenPortHttps)
+			slog.Info("starting https server on " + addr)
+			if err := http.ListenAndServeTLS(addr, cfg.CertFile, cfg.KeyFile, s.router); err != nil {
+				slog.Error(err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
+	if cfg.MTLSEnabled() {
+		go func() {
+			addr := fmt.Sprintf("%v:%v", cfg.ListenHostMTLS, cfg.ListenPortMTLS)
+			slog.Info("starting mutual-TLS server on " + addr)
+			server := &http.Server{
+				Addr:    addr,
+				Handler: mtlsHandler(s.router),
+				// client certificates are verified per client (RFC 8705), so the
+				// handshake requests them without requiring a trusted chain
+				TLSConfig: &tls.Config{ClientAuth: tls.RequestClientCert},
+			}
+			if err := server.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile); err != nil {
+				slog.Error(err.Error())
+				os.Exit(1)
+			}
+		}()
+	}
+
+	addr := fmt.Sprintf("%v:%v", cfg.ListenHostHttp, cfg.ListenPortHttp)
+	slog.Info("starting http server on " + addr)
+	if err := http.ListenAndServe(addr, s.router); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func (s *Server) initMiddleware(settings *models.Settings) {
+	slog.Info("initializing middleware")
+
+	s.router.Use(chimiddleware.RequestID)
+	if config.Get().TrustProxyHeaders {
+		slog.Info("trusting proxy headers")
+		s.router.Use(chimiddleware.RealIP)
+	}
+
+	s.router.Use(chimiddleware.Recoverer)
+	if config.Get().LogHttpRequests {
+		slog.Info("http request logging enabled")
+		s.router.Use(chimiddleware.Logger)
+	}
+
+	s.router.Use(middleware.MiddlewareSkipCsrf())
+	s.router.Use(middleware.MiddlewareCsrf(settings))
+	s.router.Use(middleware.MiddlewareSettings(s.database))
+	s.router.Use(middleware.MiddlewareCookieReset(s.sessionStore))
+	s.router.Use(middleware.MiddlewareCors(s.database))
+}
+
+// runCleanup periodically removes expired codes, device codes, refresh tokens, revoked access tokens,
+// initial access tokens, pushed authorization requests, client assertion jtis, DPoP proof jtis and user sessions
+func (s *Server) runCleanup() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		// settings may have changed since the server started
+		settings, err := s.database.GetSettingsById(nil, 1)
+		if err != nil {
+			slog.Error(fmt.Sprintf("unable to load settings: %+v", err))
+			continue
+		}
+
+		if err := s.database.DeleteUsedCodesWithoutRefreshTokens(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete used codes: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredDeviceCodes(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired device codes: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredCIBARequests(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired CIBA requests: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredOrRevokedRefreshTokens(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired or revoked refresh tokens: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredOpaqueAccessTokens(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired opaque access tokens: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredRevokedAccessTokens(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired revoked access tokens: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredInitialAccessTokens(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired initial access tokens: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredPushedAuthorizationRequests(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired pushed authorization requests: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredClientAssertionJtis(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired client assertion jtis: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredRequestObjectJtis(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired request object jtis: %+v", err))
+		}
+
+		if err := s.database.DeleteExpiredDPoPProofJtis(nil); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired dpop proof jtis: %+v", err))
+		}
+
+		idleTimeout := time.Duration(settings.UserSessionIdleTimeoutInSeconds) * time.Second
+		if err := s.database.DeleteIdleSessions(nil, idleTimeout); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete idle user sessions: %+v", err))
+		}
+
+		maxLifetime := time.Duration(settings.UserSessionMaxLifetimeInSeconds) * time.Second
+		if err := s.database.DeleteExpiredSessions(nil, maxLifetime); err != nil {
+			slog.Error(fmt.Sprintf("unable to delete expired user sessions: %+v", err))
+		}
+	}
+}
+
+// runKeyRotation periodically retires expired previous signing keys and rotates
+// the keys when the configured interval has elapsed. Every server instance runs it,
+// the key rotator makes sure only one of them rotates the keys.
+func (s *Server) runKeyRotation() {
+	keyRotator := keyrotation.NewKeyRotator(s.database)
+	auditLogger := audit.NewAuditLogger()
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		settings, err := s.database.GetSettingsById(nil, 1)
+		if err != nil {
+			slog.Error(fmt.Sprintf("unable to load settings: %+v", err))
+			continue
+		}
+
+		rotated, err := keyRotator.RunScheduledRotation(settings)
+		if err != nil {
+			slog.Error(fmt.Sprintf("unable to rotate signing keys: %+v", err))
+		} else if rotated {
+			auditLogger.Log(constants.AuditRotatedKeys, map[string]interface{}{
+				"scheduled": true,
+			})
+		}
+	}
+}
+
+// mtlsHandler only serves the endpoints advertised in the mtls_endpoint_aliases, so
+// browsers never reach the listener that asks for a client certificate (RFC 8705, section 5)
+func mtlsHandler(router http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(mtlsEndpointPaths, r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		router.ServeHTTP(w, r)
+	})
+}
+
+func loadFS(dir string, embedded func() fs.FS) fs.FS {
+	if len(strings.TrimSpace(dir)) == 0 {
+		return embedded()
+	}
+
+	slog.Info("using files from " + dir)
+	return os.DirFS(dir)
+}
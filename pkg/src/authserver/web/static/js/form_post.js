@@ -0,0 +1 @@
+document.getElementById("formPost").submit();
@@ -0,0 +1,18 @@
+(function () {
+    var redirectURI = document.getElementById("logoutCompleted").dataset.redirectUri;
+    if (!redirectURI) {
+        return;
+    }
+
+    // the load event waits for the front-channel logout iframes,
+    // the timeout covers clients that never finish loading
+    var redirected = false;
+    var redirect = function () {
+        if (!redirected) {
+            redirected = true;
+            window.location.href = redirectURI;
+        }
+    };
+    window.addEventListener("load", redirect);
+    setTimeout(redirect, 5000);
+})();
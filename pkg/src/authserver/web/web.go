@@ -0,0 +1,30 @@
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed template
+var templateFS embed.FS
+
+//go:embed static
+var staticFS embed.FS
+
+// TemplateFS returns the embedded templates rooted at the template directory
+func TemplateFS() fs.FS {
+	sub, err := fs.Sub(templateFS, "template")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// StaticFS returns the embedded static files rooted at the static directory
+func StaticFS() fs.FS {
+	sub, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,87 @@
+package communication
+
+import (
+	"context"
+	"html"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type AuthenticationDeviceNotification struct {
+	Email           string
+	Name            string
+	ClientName      string
+	BindingMessage  string
+	VerificationURI string
+}
+
+// AuthenticationDeviceNotifier reaches the user on their authentication device when a client
+// starts a backchannel authentication for them (OpenID Connect CIBA, section 7.1)
+type AuthenticationDeviceNotifier interface {
+	NotifyAuthenticationDevice(ctx context.Context, notification *AuthenticationDeviceNotification) error
+}
+
+// EmailAuthenticationDeviceNotifier sends the user an email with the link to authenticate
+type EmailAuthenticationDeviceNotifier struct {
+	emailSender *EmailSender
+}
+
+func NewEmailAuthenticationDeviceNotifier(emailSender *EmailSender) *EmailAuthenticationDeviceNotifier {
+	return &EmailAuthenticationDeviceNotifier{
+		emailSender: emailSender,
+	}
+}
+
+func (n *EmailAuthenticationDeviceNotifier) NotifyAuthenticationDevice(ctx context.Context, notification *AuthenticationDeviceNotification) error {
+	var sb strings.Builder
+	if len(notification.Name) > 0 {
+		sb.WriteString("<p>Hello " + html.EscapeString(notification.Name) + ",</p>")
+	}
+	sb.WriteString("<p>" + html.EscapeString(notification.ClientName) + " is asking you to sign in.</p>")
+	if len(notification.BindingMessage) > 0 {
+		// the binding message lets the user check the request comes from the device they are using
+		sb.WriteString("<p>Make sure the following message is displayed on that device: <strong>" +
+			html.EscapeString(notification.BindingMessage) + "</strong></p>")
+	}
+	sb.WriteString(`<p><a href="` + html.EscapeString(notification.VerificationURI) + `">Review the request</a></p>`)
+	sb.WriteString("<p>If you did not expect this request, you can ignore this email.</p>")
+
+	if err := n.emailSender.SendEmail(ctx, &SendEmailInput{
+		To:       notification.Email,
+		Subject:  "Sign-in request from " + notification.ClientName,
+		HtmlBody: sb.String(),
+	}); err != nil {
+		return errors.Wrap(err, "unable to send the authentication request email")
+	}
+
+	return nil
+}
+
+// InMemoryAuthenticationDeviceNotifier keeps the notifications instead of delivering them,
+// for tests and for environments where the users can't be reached
+type InMemoryAuthenticationDeviceNotifier struct {
+	mu            sync.Mutex
+	notifications []AuthenticationDeviceNotification
+}
+
+func NewInMemoryAuthenticationDeviceNotifier() *InMemoryAuthenticationDeviceNotifier {
+	return &InMemoryAuthenticationDeviceNotifier{}
+}
+
+func (n *InMemoryAuthenticationDeviceNotifier) NotifyAuthenticationDevice(ctx context.Context, notification *AuthenticationDeviceNotification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.notifications = append(n.notifications, *notification)
+	return nil
+}
+
+// GetNotifications returns a copy of the notifications received so far
+func (n *InMemoryAuthenticationDeviceNotifier) GetNotifications() []AuthenticationDeviceNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]AuthenticationDeviceNotification(nil), n.notifications...)
+}
@@ -0,0 +1,28 @@
+package communication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryAuthenticationDeviceNotifier(t *testing.T) {
+	notifier := NewInMemoryAuthenticationDeviceNotifier()
+	assert.Empty(t, notifier.GetNotifications())
+
+	notification := &AuthenticationDeviceNotification{
+		Email:           "user@example.com",
+		ClientName:      "call-center",
+		BindingMessage:  "W4SCT",
+		VerificationURI: "https://auth.example.com/ciba?code=abc",
+	}
+	assert.NoError(t, notifier.NotifyAuthenticationDevice(context.Background(), notification))
+
+	notifications := notifier.GetNotifications()
+	assert.Equal(t, []AuthenticationDeviceNotification{*notification}, notifications)
+
+	// the returned slice is a copy
+	notifications[0].Email = "other@example.com"
+	assert.Equal(t, "user@example.com", notifier.GetNotifications()[0].Email)
+}
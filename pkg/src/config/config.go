@@ -19,16 +19,21 @@ type ServerConfig struct {
 	LogSQL             bool
 	KeyFile            string
 	CertFile           string
+	ClientCAFile       string
+	MTLSBaseURL        string
 	InternalBaseURL    string
 	ListenHostHttps    string
 	ListenPortHttps    int
 	ListenHostHttp     string
 	ListenPortHttp     int
+	ListenHostMTLS     string
+	ListenPortMTLS     int
 	TrustProxyHeaders  bool
 	SetCookieSecure    bool
 	LogHttpRequests    bool
 	StaticDir          string
 	TemplateDir        string
+	RequestObjectsDir  string
 	AuditLogsInConsole bool
 }
 
@@ -85,6 +90,12 @@ func GetDatabase() *DatabaseConfig {
 	return &cfg.Database
 }
 
+// MTLSEnabled reports whether the server runs the mutual-TLS listener,
+// which needs the certificate files and the public URL of the listener
+func (c *ServerConfig) MTLSEnabled() bool {
+	return len(c.MTLSBaseURL) > 0 && len(strings.TrimSpace(c.CertFile)) > 0 && len(strings.TrimSpace(c.KeyFile)) > 0
+}
+
 // setActiveServer sets the active server configuration.
 func setActiveServer(server string) {
 	switch server {
@@ -106,15 +117,20 @@ func load() {
 			ListenPortHttps:    getEnvAsInt("GOIABADA_AUTHSERVER_LISTEN_PORT_HTTPS", 9443),
 			ListenHostHttp:     getEnv("GOIABADA_AUTHSERVER_LISTEN_HOST_HTTP", "0.0.0.0"),
 			ListenPortHttp:     getEnvAsInt("GOIABADA_AUTHSERVER_LISTEN_PORT_HTTP", 9090),
+			ListenHostMTLS:     getEnv("GOIABADA_AUTHSERVER_LISTEN_HOST_MTLS", "0.0.0.0"),
+			ListenPortMTLS:     getEnvAsInt("GOIABADA_AUTHSERVER_LISTEN_PORT_MTLS", 9445),
+			MTLSBaseURL:        getEnv("GOIABADA_AUTHSERVER_MTLS_BASEURL", ""),
 			TrustProxyHeaders:  getEnvAsBool("GOIABADA_AUTHSERVER_TRUST_PROXY_HEADERS"),
 			SetCookieSecure:    getEnvAsBool("GOIABADA_AUTHSERVER_SET_COOKIE_SECURE"),
 			LogHttpRequests:    getEnvAsBool("GOIABADA_AUTHSERVER_LOG_HTTP_REQUESTS"),
 			CertFile:           getEnv("GOIABADA_AUTHSERVER_CERTFILE", ""),
 			KeyFile:            getEnv("GOIABADA_AUTHSERVER_KEYFILE", ""),
+			ClientCAFile:       getEnv("GOIABADA_AUTHSERVER_CLIENT_CA_FILE", ""),
 			LogSQL:             getEnvAsBool("GOIABADA_AUTHSERVER_LOG_SQL"),
 			AuditLogsInConsole: getEnvAsBool("GOIABADA_AUTHSERVER_AUDIT_LOGS_IN_CONSOLE"),
 			StaticDir:          getEnv("GOIABADA_AUTHSERVER_STATICDIR", ""),
 			TemplateDir:        getEnv("GOIABADA_AUTHSERVER_TEMPLATEDIR", ""),
+			RequestObjectsDir:  getEnv("GOIABADA_AUTHSERVER_REQUEST_OBJECTS_DIR", ""),
 		},
 		AdminConsole: ServerConfig{
 			BaseURL:            getEnv("GOIABADA_ADMINCONSOLE_BASEURL", "http://localhost:9091"),
@@ -154,15 +170,20 @@ func load() {
 	flag.IntVar(&cfg.AuthServer.ListenPortHttps, "authserver-listen-port-https", cfg.AuthServer.ListenPortHttps, "Auth server https port")
 	flag.StringVar(&cfg.AuthServer.ListenHostHttp, "authserver-listen-host-http", cfg.AuthServer.ListenHostHttp, "Auth server http host")
 	flag.IntVar(&cfg.AuthServer.ListenPortHttp, "authserver-listen-port-http", cfg.AuthServer.ListenPortHttp, "Auth server http port")
+	flag.StringVar(&cfg.AuthServer.ListenHostMTLS, "authserver-listen-host-mtls", cfg.AuthServer.ListenHostMTLS, "Auth server mutual-TLS host")
+	flag.IntVar(&cfg.AuthServer.ListenPortMTLS, "authserver-listen-port-mtls", cfg.AuthServer.ListenPortMTLS, "Auth server mutual-TLS port")
+	flag.StringVar(&cfg.AuthServer.MTLSBaseURL, "authserver-mtls-baseurl", cfg.AuthServer.MTLSBaseURL, "Base URL of the auth server mutual-TLS listener, which requests client certificates (enables mutual-TLS when set with the certificate and key files)")
 	flag.BoolVar(&cfg.AuthServer.TrustProxyHeaders, "authserver-trust-proxy-headers", cfg.AuthServer.TrustProxyHeaders, "Trust HTTP headers from reverse proxy in Auth server? (True-Client-IP, X-Real-IP or the X-Forwarded-For headers)")
 	flag.BoolVar(&cfg.AuthServer.SetCookieSecure, "authserver-set-cookie-secure", cfg.AuthServer.SetCookieSecure, "Set secure flag on cookies for auth server")
 	flag.BoolVar(&cfg.AuthServer.LogHttpRequests, "authserver-log-http-requests", cfg.AuthServer.LogHttpRequests, "Log HTTP requests for auth server")
 	flag.StringVar(&cfg.AuthServer.CertFile, "authserver-certfile", cfg.AuthServer.CertFile, "Certificate file for HTTPS (auth server)")
 	flag.StringVar(&cfg.AuthServer.KeyFile, "authserver-keyfile", cfg.AuthServer.KeyFile, "Key file for HTTPS (auth server)")
+	flag.StringVar(&cfg.AuthServer.ClientCAFile, "authserver-client-ca-file", cfg.AuthServer.ClientCAFile, "CA certificates trusted for tls_client_auth client certificates (auth server)")
 	flag.BoolVar(&cfg.AuthServer.LogSQL, "authserver-log-sql", cfg.AuthServer.LogSQL, "Log SQL queries for auth server")
 	flag.BoolVar(&cfg.AuthServer.AuditLogsInConsole, "authserver-audit-logs-in-console", cfg.AuthServer.AuditLogsInConsole, "Enable audit logs in console output for auth server")
 	flag.StringVar(&cfg.AuthServer.StaticDir, "authserver-staticdir", cfg.AuthServer.StaticDir, "Static files directory for auth server")
 	flag.StringVar(&cfg.AuthServer.TemplateDir, "authserver-templatedir", cfg.AuthServer.TemplateDir, "Template files directory for auth server")
+	flag.StringVar(&cfg.AuthServer.RequestObjectsDir, "authserver-request-objects-dir", cfg.AuthServer.RequestObjectsDir, "Directory of the request objects referenced by request_uri, one subdirectory per client (auth server)")
 
 	// Admin console
 	flag.StringVar(&cfg.AdminConsole.BaseURL, "adminconsole-baseurl", cfg.AdminConsole.BaseURL, "Goiabada admin console base URL")
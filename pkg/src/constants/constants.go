@@ -1,6 +1,8 @@
 package constants
 
 const (
+	AccessTokenFormatJwt                      = "jwt"
+	AccessTokenFormatOpaque                   = "opaque"
 	AdminConsoleClientIdentifier              = "admin-console-client"
 	AdminConsoleResourceIdentifier            = "adminconsole"
 	AuditActivatedAccount                     = "activated_account"
@@ -16,8 +18,11 @@ const (
 	AuditBumpedUserSession                    = "bumped_user_session"
 	AuditChangedPassword                      = "changed_password"
 	AuditCreatedAuthCode                      = "created_auth_code"
+	AuditCreatedCIBARequest                   = "created_ciba_request"
 	AuditCreatedClient                        = "created_client"
+	AuditCreatedDeviceCode                    = "created_device_code"
 	AuditCreatedGroup                         = "created_group"
+	AuditCreatedInitialAccessToken            = "created_initial_access_token"
 	AuditCreatedPreRegistration               = "created_pre_registration"
 	AuditCreatedResource                      = "created_resource"
 	AuditCreatedUser                          = "created_user"
@@ -26,6 +31,8 @@ const (
 	AuditDeletedClient                        = "deleted_client"
 	AuditDeletedGroup                         = "deleted_group"
 	AuditDeletedGroupPermission               = "deleted_group_permission"
+	AuditDeletedInitialAccessToken            = "deleted_initial_access_token"
+	AuditDeletedRegisteredClient              = "deleted_registered_client"
 	AuditDeletedResource                      = "deleted_resource"
 	AuditDeletedUser                          = "deleted_user"
 	AuditDeletedUserConsent                   = "deleted_user_consent"
@@ -36,15 +43,22 @@ const (
 	AuditEnabledOTP                           = "enabled_otp"
 	AuditFailedEmailVerificationCode          = "failed_email_verification_code"
 	AuditLogout                               = "logout"
+	AuditRefreshTokenReuseDetected            = "refresh_token_reuse_detected"
+	AuditRegisteredClient                     = "registered_client"
+	AuditRevokedAccessToken                   = "revoked_access_token"
 	AuditRevokedKey                           = "revoked_key"
+	AuditRevokedRefreshToken                  = "revoked_refresh_token"
 	AuditRotatedKeys                          = "rotated_keys"
 	AuditSavedConsent                         = "saved_consent"
 	AuditSentEmailVerificationMessage         = "sent_email_verification_message"
 	AuditSentPhoneVerificationMessage         = "sent_phone_verification_message"
 	AuditStartedNewUserSesson                 = "started_new_user_session"
 	AuditTokenIssuedAuthorizationCodeResponse = "token_issued_authorization_code_response"
+	AuditTokenIssuedCIBAResponse              = "token_issued_ciba_response"
 	AuditTokenIssuedClientCredentialsResponse = "token_issued_client_credentials_response"
+	AuditTokenIssuedDeviceCodeResponse        = "token_issued_device_code_response"
 	AuditTokenIssuedRefreshTokenResponse      = "token_issued_refresh_token_response"
+	AuditTokenIssuedTokenExchangeResponse     = "token_issued_token_exchange_response"
 	AuditUserAddedToGroup                     = "user_added_to_group"
 	AuditUserDisabled                         = "user_disabled"
 	AuditUserRemovedFromGroup                 = "user_removed_from_group"
@@ -56,7 +70,9 @@ const (
 	AuditUpdatedGeneralSettings               = "updated_general_settings"
 	AuditUpdatedGroup                         = "updated_group"
 	AuditUpdatedGroupAttribute                = "updated_group_attribute"
+	AuditUpdatedPostLogoutRedirectURIs        = "updated_post_logout_redirect_uris"
 	AuditUpdatedRedirectURIs                  = "updated_redirect_uris"
+	AuditUpdatedRegisteredClient              = "updated_registered_client"
 	AuditUpdatedResourcePermissions           = "updated_resource_permissions"
 	AuditUpdatedResource                      = "updated_resource"
 	AuditUpdatedSessionsSettings              = "updated_sessions_settings"
@@ -75,7 +91,23 @@ const (
 	AuditVerifiedEmail                        = "verified_email"
 	AuditVerifiedPhone                        = "verified_phone"
 	AuthServerResourceIdentifier              = "authserver"
+	CIBAGrantType                             = "urn:openid:params:grant-type:ciba"
+	CIBATokenDeliveryModePing                 = "ping"
+	CIBATokenDeliveryModePoll                 = "poll"
+	ClientAssertionTypeJwtBearer              = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	DeviceCodeGrantType                       = "urn:ietf:params:oauth:grant-type:device_code"
+	DPoPHeaderName                            = "DPoP"
+	DPoPNonceHeaderName                       = "DPoP-Nonce"
+	DPoPProofType                             = "dpop+jwt"
 	ManageAccountPermissionIdentifier         = "manage-account"
 	ManageAdminConsolePermissionIdentifier    = "manage"
+	SubjectTypePairwise                       = "pairwise"
+	SubjectTypePublic                         = "public"
+	TokenEndpointAuthMethodClientSecretJwt    = "client_secret_jwt"
+	TokenEndpointAuthMethodPrivateKeyJwt      = "private_key_jwt"
+	TokenEndpointAuthMethodSelfSignedTLS      = "self_signed_tls_client_auth"
+	TokenEndpointAuthMethodTLSClientAuth      = "tls_client_auth"
+	TokenExchangeGrantType                    = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken                      = "urn:ietf:params:oauth:token-type:access_token"
 	UserinfoPermissionIdentifier              = "userinfo"
 )
@@ -6,3 +6,5 @@ const ContextKeyJwtInfo ctxKey = "JwtInfo"
 const ContextKeySettings ctxKey = "Settings"
 const ContextKeyBearerToken ctxKey = "BearerToken"
 const ContextKeySessionIdentifier ctxKey = "SessionIdentifier"
+const ContextKeyClientCertificateThumbprint ctxKey = "ClientCertificateThumbprint"
+const ContextKeyDPoPKeyThumbprint ctxKey = "DPoPKeyThumbprint"
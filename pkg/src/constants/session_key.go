@@ -2,6 +2,7 @@ package constants
 
 const SessionName string = "AAS"
 const SessionKeySessionIdentifier string = "SessionIdentifier"
+const SessionKeyOtherSessionIdentifiers string = "OtherSessionIdentifiers"
 const SessionKeyOTPImage string = "OTPImage"
 const SessionKeyOTPSecret string = "OTPSecret"
 const SessionKeyAuthContext string = "AuthContext"
@@ -8,6 +8,12 @@ import (
 
 var ErrNoAuthContext = NewErrorDetail("no_auth_context", "no auth context in session")
 
+var ErrTokenRevoked = NewErrorDetail("token_revoked", "the token has been revoked")
+
+var ErrUnknownSigningKey = NewErrorDetail("unknown_signing_key", "the token was not signed with any of the published keys")
+
+var ErrUnknownOpaqueToken = NewErrorDetail("unknown_token", "the token does not reference any issued access token")
+
 type ErrorDetail struct {
 	details map[string]string
 }
@@ -0,0 +1,167 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	if cibaRequest.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	if cibaRequest.UserId == 0 {
+		return errors.WithStack(errors.New("user id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := cibaRequest.CreatedAt
+	originalUpdatedAt := cibaRequest.UpdatedAt
+	cibaRequest.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	cibaRequest.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	cibaRequestStruct := sqlbuilder.NewStruct(new(models.CIBARequest)).For(d.Flavor)
+	insertBuilder := cibaRequestStruct.WithoutTag("pk").InsertInto("ciba_requests", cibaRequest)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		cibaRequest.CreatedAt = originalCreatedAt
+		cibaRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert cibaRequest")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		cibaRequest.CreatedAt = originalCreatedAt
+		cibaRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	cibaRequest.Id = id
+	return nil
+}
+
+func (d *CommonDB) UpdateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	if cibaRequest.Id == 0 {
+		return errors.WithStack(errors.New("can't update cibaRequest with id 0"))
+	}
+
+	originalUpdatedAt := cibaRequest.UpdatedAt
+	cibaRequest.UpdatedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	cibaRequestStruct := sqlbuilder.NewStruct(new(models.CIBARequest)).For(d.Flavor)
+	updateBuilder := cibaRequestStruct.WithoutTag("pk").WithoutTag("dont-update").Update("ciba_requests", cibaRequest)
+	updateBuilder.Where(updateBuilder.Equal("id", cibaRequest.Id))
+	sql, args := updateBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		cibaRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to update cibaRequest")
+	}
+
+	return nil
+}
+
+// UpdateCIBARequestStatus moves the CIBA request to toStatus only if it is still in fromStatus,
+// reporting whether it did. Concurrent callers can use it to agree on a single winner.
+func (d *CommonDB) UpdateCIBARequestStatus(tx *sql.Tx, cibaRequestId int64, fromStatus string, toStatus string) (bool, error) {
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("ciba_requests")
+	updateBuilder.Set(
+		updateBuilder.Assign("status", toStatus),
+		updateBuilder.Assign("updated_at", time.Now().UTC()),
+	)
+	updateBuilder.Where(
+		updateBuilder.Equal("id", cibaRequestId),
+		updateBuilder.Equal("status", fromStatus),
+	)
+	sql, args := updateBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to update cibaRequest status")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (d *CommonDB) getCIBARequestCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, cibaRequestStruct *sqlbuilder.Struct) (*models.CIBARequest, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var cibaRequest models.CIBARequest
+	if rows.Next() {
+		addr := cibaRequestStruct.Addr(&cibaRequest)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan cibaRequest")
+		}
+		return &cibaRequest, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetCIBARequestById(tx *sql.Tx, cibaRequestId int64) (*models.CIBARequest, error) {
+	cibaRequestStruct := sqlbuilder.NewStruct(new(models.CIBARequest)).For(d.Flavor)
+	selectBuilder := cibaRequestStruct.SelectFrom("ciba_requests")
+	selectBuilder.Where(selectBuilder.Equal("id", cibaRequestId))
+	return d.getCIBARequestCommon(tx, selectBuilder, cibaRequestStruct)
+}
+
+func (d *CommonDB) GetCIBARequestByAuthReqIdHash(tx *sql.Tx, authReqIdHash string) (*models.CIBARequest, error) {
+	cibaRequestStruct := sqlbuilder.NewStruct(new(models.CIBARequest)).For(d.Flavor)
+	selectBuilder := cibaRequestStruct.SelectFrom("ciba_requests")
+	selectBuilder.Where(selectBuilder.Equal("auth_req_id_hash", authReqIdHash))
+	return d.getCIBARequestCommon(tx, selectBuilder, cibaRequestStruct)
+}
+
+func (d *CommonDB) GetCIBARequestByVerificationCodeHash(tx *sql.Tx, verificationCodeHash string) (*models.CIBARequest, error) {
+	cibaRequestStruct := sqlbuilder.NewStruct(new(models.CIBARequest)).For(d.Flavor)
+	selectBuilder := cibaRequestStruct.SelectFrom("ciba_requests")
+	selectBuilder.Where(selectBuilder.Equal("verification_code_hash", verificationCodeHash))
+	return d.getCIBARequestCommon(tx, selectBuilder, cibaRequestStruct)
+}
+
+func (d *CommonDB) CIBARequestLoadClient(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	if cibaRequest != nil {
+		if client, err := d.GetClientById(tx, cibaRequest.ClientId); err != nil {
+			return errors.Wrap(err, "unable to load client")
+		} else if client != nil {
+			cibaRequest.Client = *client
+		}
+	}
+
+	return nil
+}
+
+func (d *CommonDB) CIBARequestLoadUser(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	if cibaRequest != nil {
+		if user, err := d.GetUserById(tx, cibaRequest.UserId); err != nil {
+			return errors.Wrap(err, "unable to load user")
+		} else if user != nil {
+			cibaRequest.User = *user
+		}
+	}
+
+	return nil
+}
+
+func (d *CommonDB) DeleteExpiredCIBARequests(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("ciba_requests")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired CIBA requests")
+	}
+
+	return nil
+}
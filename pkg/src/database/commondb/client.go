@@ -172,6 +172,16 @@ func (d *CommonDB) ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) (er
 	return nil
 }
 
+func (d *CommonDB) ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) (err error) {
+	if client != nil {
+		if client.PostLogoutRedirectURIs, err = d.GetPostLogoutRedirectURIsByClientId(tx, client.Id); err != nil {
+			return errors.Wrap(err, "unable to get post logout redirect URIs")
+		}
+	}
+
+	return nil
+}
+
 func (d *CommonDB) getClientCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, clientStruct *sqlbuilder.Struct) (*models.Client, error) {
 	sql, args := selectBuilder.Build()
 	rows, err := d.QuerySql(tx, sql, args...)
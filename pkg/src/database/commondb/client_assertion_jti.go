@@ -0,0 +1,82 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateClientAssertionJti(tx *sql.Tx, clientAssertionJti *models.ClientAssertionJti) error {
+	if clientAssertionJti.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := clientAssertionJti.CreatedAt
+	originalUpdatedAt := clientAssertionJti.UpdatedAt
+	clientAssertionJti.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	clientAssertionJti.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	clientAssertionJtiStruct := sqlbuilder.NewStruct(new(models.ClientAssertionJti)).For(d.Flavor)
+	insertBuilder := clientAssertionJtiStruct.WithoutTag("pk").InsertInto("client_assertion_jtis", clientAssertionJti)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		clientAssertionJti.CreatedAt = originalCreatedAt
+		clientAssertionJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert clientAssertionJti")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		clientAssertionJti.CreatedAt = originalCreatedAt
+		clientAssertionJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	clientAssertionJti.Id = id
+	return nil
+}
+
+func (d *CommonDB) getClientAssertionJtiCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, clientAssertionJtiStruct *sqlbuilder.Struct) (*models.ClientAssertionJti, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var clientAssertionJti models.ClientAssertionJti
+	if rows.Next() {
+		addr := clientAssertionJtiStruct.Addr(&clientAssertionJti)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan clientAssertionJti")
+		}
+		return &clientAssertionJti, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetClientAssertionJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.ClientAssertionJti, error) {
+	clientAssertionJtiStruct := sqlbuilder.NewStruct(new(models.ClientAssertionJti)).For(d.Flavor)
+	selectBuilder := clientAssertionJtiStruct.SelectFrom("client_assertion_jtis")
+	selectBuilder.Where(
+		selectBuilder.Equal("client_id", clientId),
+		selectBuilder.Equal("jti_hash", jtiHash),
+	)
+	return d.getClientAssertionJtiCommon(tx, selectBuilder, clientAssertionJtiStruct)
+}
+
+func (d *CommonDB) DeleteExpiredClientAssertionJtis(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("client_assertion_jtis")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired client assertion jtis")
+	}
+
+	return nil
+}
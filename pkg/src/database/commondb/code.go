@@ -78,6 +78,33 @@ func (d *CommonDB) UpdateCode(tx *sql.Tx, code *models.Code) error {
 	return nil
 }
 
+// MarkCodeAsUsed marks the code as used only if it was not used yet, reporting whether it did.
+// Concurrent exchanges of the same code can use it to agree on a single winner.
+func (d *CommonDB) MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error) {
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("codes")
+	updateBuilder.Set(
+		updateBuilder.Assign("used", true),
+		updateBuilder.Assign("updated_at", time.Now().UTC()),
+	)
+	updateBuilder.Where(
+		updateBuilder.Equal("id", codeId),
+		updateBuilder.Equal("used", false),
+	)
+	sql, args := updateBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to mark code as used")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
 func (d *CommonDB) getCodeCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, codeStruct *sqlbuilder.Struct) (*models.Code, error) {
 	sql, args := selectBuilder.Build()
 	rows, err := d.QuerySql(tx, sql, args...)
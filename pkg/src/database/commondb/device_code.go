@@ -0,0 +1,151 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	if deviceCode.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := deviceCode.CreatedAt
+	originalUpdatedAt := deviceCode.UpdatedAt
+	deviceCode.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	deviceCode.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	deviceCodeStruct := sqlbuilder.NewStruct(new(models.DeviceCode)).For(d.Flavor)
+	insertBuilder := deviceCodeStruct.WithoutTag("pk").InsertInto("device_codes", deviceCode)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		deviceCode.CreatedAt = originalCreatedAt
+		deviceCode.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert deviceCode")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		deviceCode.CreatedAt = originalCreatedAt
+		deviceCode.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	deviceCode.Id = id
+	return nil
+}
+
+func (d *CommonDB) UpdateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	if deviceCode.Id == 0 {
+		return errors.WithStack(errors.New("can't update deviceCode with id 0"))
+	}
+
+	originalUpdatedAt := deviceCode.UpdatedAt
+	deviceCode.UpdatedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	deviceCodeStruct := sqlbuilder.NewStruct(new(models.DeviceCode)).For(d.Flavor)
+	updateBuilder := deviceCodeStruct.WithoutTag("pk").WithoutTag("dont-update").Update("device_codes", deviceCode)
+	updateBuilder.Where(updateBuilder.Equal("id", deviceCode.Id))
+	sql, args := updateBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		deviceCode.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to update deviceCode")
+	}
+
+	return nil
+}
+
+// UpdateDeviceCodeStatus moves the device code to toStatus only if it is still in fromStatus,
+// reporting whether it did. Concurrent callers can use it to agree on a single winner.
+func (d *CommonDB) UpdateDeviceCodeStatus(tx *sql.Tx, deviceCodeId int64, fromStatus string, toStatus string) (bool, error) {
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("device_codes")
+	updateBuilder.Set(
+		updateBuilder.Assign("status", toStatus),
+		updateBuilder.Assign("updated_at", time.Now().UTC()),
+	)
+	updateBuilder.Where(
+		updateBuilder.Equal("id", deviceCodeId),
+		updateBuilder.Equal("status", fromStatus),
+	)
+	sql, args := updateBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to update deviceCode status")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (d *CommonDB) getDeviceCodeCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, deviceCodeStruct *sqlbuilder.Struct) (*models.DeviceCode, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var deviceCode models.DeviceCode
+	if rows.Next() {
+		addr := deviceCodeStruct.Addr(&deviceCode)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan deviceCode")
+		}
+		return &deviceCode, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetDeviceCodeById(tx *sql.Tx, deviceCodeId int64) (*models.DeviceCode, error) {
+	deviceCodeStruct := sqlbuilder.NewStruct(new(models.DeviceCode)).For(d.Flavor)
+	selectBuilder := deviceCodeStruct.SelectFrom("device_codes")
+	selectBuilder.Where(selectBuilder.Equal("id", deviceCodeId))
+	return d.getDeviceCodeCommon(tx, selectBuilder, deviceCodeStruct)
+}
+
+func (d *CommonDB) GetDeviceCodeByDeviceCodeHash(tx *sql.Tx, deviceCodeHash string) (*models.DeviceCode, error) {
+	deviceCodeStruct := sqlbuilder.NewStruct(new(models.DeviceCode)).For(d.Flavor)
+	selectBuilder := deviceCodeStruct.SelectFrom("device_codes")
+	selectBuilder.Where(selectBuilder.Equal("device_code_hash", deviceCodeHash))
+	return d.getDeviceCodeCommon(tx, selectBuilder, deviceCodeStruct)
+}
+
+func (d *CommonDB) GetDeviceCodeByUserCode(tx *sql.Tx, userCode string) (*models.DeviceCode, error) {
+	deviceCodeStruct := sqlbuilder.NewStruct(new(models.DeviceCode)).For(d.Flavor)
+	selectBuilder := deviceCodeStruct.SelectFrom("device_codes")
+	selectBuilder.Where(selectBuilder.Equal("user_code", userCode))
+	return d.getDeviceCodeCommon(tx, selectBuilder, deviceCodeStruct)
+}
+
+func (d *CommonDB) DeviceCodeLoadClient(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	if deviceCode != nil {
+		if client, err := d.GetClientById(tx, deviceCode.ClientId); err != nil {
+			return errors.Wrap(err, "unable to load client")
+		} else if client != nil {
+			deviceCode.Client = *client
+		}
+	}
+
+	return nil
+}
+
+func (d *CommonDB) DeleteExpiredDeviceCodes(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("device_codes")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired device codes")
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateDPoPProofJti(tx *sql.Tx, dpopProofJti *models.DPoPProofJti) error {
+	if len(dpopProofJti.JtiHash) == 0 {
+		return errors.WithStack(errors.New("jti hash must not be empty"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := dpopProofJti.CreatedAt
+	originalUpdatedAt := dpopProofJti.UpdatedAt
+	dpopProofJti.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	dpopProofJti.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	dpopProofJtiStruct := sqlbuilder.NewStruct(new(models.DPoPProofJti)).For(d.Flavor)
+	insertBuilder := dpopProofJtiStruct.WithoutTag("pk").InsertInto("dpop_proof_jtis", dpopProofJti)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		dpopProofJti.CreatedAt = originalCreatedAt
+		dpopProofJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert dpopProofJti")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		dpopProofJti.CreatedAt = originalCreatedAt
+		dpopProofJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	dpopProofJti.Id = id
+	return nil
+}
+
+func (d *CommonDB) getDPoPProofJtiCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, dpopProofJtiStruct *sqlbuilder.Struct) (*models.DPoPProofJti, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var dpopProofJti models.DPoPProofJti
+	if rows.Next() {
+		addr := dpopProofJtiStruct.Addr(&dpopProofJti)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan dpopProofJti")
+		}
+		return &dpopProofJti, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetDPoPProofJti(tx *sql.Tx, jtiHash string) (*models.DPoPProofJti, error) {
+	dpopProofJtiStruct := sqlbuilder.NewStruct(new(models.DPoPProofJti)).For(d.Flavor)
+	selectBuilder := dpopProofJtiStruct.SelectFrom("dpop_proof_jtis")
+	selectBuilder.Where(selectBuilder.Equal("jti_hash", jtiHash))
+	return d.getDPoPProofJtiCommon(tx, selectBuilder, dpopProofJtiStruct)
+}
+
+func (d *CommonDB) DeleteExpiredDPoPProofJtis(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("dpop_proof_jtis")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired dpop proof jtis")
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := initialAccessToken.CreatedAt
+	originalUpdatedAt := initialAccessToken.UpdatedAt
+	initialAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	initialAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(d.Flavor)
+	insertBuilder := initialAccessTokenStruct.WithoutTag("pk").InsertInto("initial_access_tokens", initialAccessToken)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		initialAccessToken.CreatedAt = originalCreatedAt
+		initialAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert initialAccessToken")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		initialAccessToken.CreatedAt = originalCreatedAt
+		initialAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	initialAccessToken.Id = id
+	return nil
+}
+
+func (d *CommonDB) getInitialAccessTokenCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, initialAccessTokenStruct *sqlbuilder.Struct) (*models.InitialAccessToken, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var initialAccessToken models.InitialAccessToken
+	if rows.Next() {
+		addr := initialAccessTokenStruct.Addr(&initialAccessToken)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan initialAccessToken")
+		}
+		return &initialAccessToken, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error) {
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(d.Flavor)
+	selectBuilder := initialAccessTokenStruct.SelectFrom("initial_access_tokens")
+	selectBuilder.Where(selectBuilder.Equal("id", initialAccessTokenId))
+	return d.getInitialAccessTokenCommon(tx, selectBuilder, initialAccessTokenStruct)
+}
+
+func (d *CommonDB) GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error) {
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(d.Flavor)
+	selectBuilder := initialAccessTokenStruct.SelectFrom("initial_access_tokens")
+	selectBuilder.Where(selectBuilder.Equal("token_hash", tokenHash))
+	return d.getInitialAccessTokenCommon(tx, selectBuilder, initialAccessTokenStruct)
+}
+
+func (d *CommonDB) GetAllInitialAccessTokens(tx *sql.Tx) (initialAccessTokens []models.InitialAccessToken, err error) {
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(d.Flavor)
+	selectBuilder := initialAccessTokenStruct.SelectFrom("initial_access_tokens")
+	selectBuilder.OrderBy("id")
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var initialAccessToken models.InitialAccessToken
+		addr := initialAccessTokenStruct.Addr(&initialAccessToken)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan initialAccessToken")
+		}
+
+		initialAccessTokens = append(initialAccessTokens, initialAccessToken)
+	}
+
+	return initialAccessTokens, nil
+}
+
+func (d *CommonDB) DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error {
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(d.Flavor)
+	deleteBuilder := initialAccessTokenStruct.DeleteFrom("initial_access_tokens")
+	deleteBuilder.Where(deleteBuilder.Equal("id", initialAccessTokenId))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete initialAccessToken")
+	}
+
+	return nil
+}
+
+func (d *CommonDB) DeleteExpiredInitialAccessTokens(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("initial_access_tokens")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired initial access tokens")
+	}
+
+	return nil
+}
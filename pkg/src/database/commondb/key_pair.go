@@ -56,6 +56,33 @@ func (d *CommonDB) UpdateKeyPair(tx *sql.Tx, keyPair *models.KeyPair) error {
 	return nil
 }
 
+// UpdateKeyPairState moves the key pair to toState only if it is still in fromState,
+// reporting whether it did. Concurrent callers can use it to agree on a single winner.
+func (d *CommonDB) UpdateKeyPairState(tx *sql.Tx, keyPairId int64, fromState string, toState string) (bool, error) {
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("key_pairs")
+	updateBuilder.Set(
+		updateBuilder.Assign("state", toState),
+		updateBuilder.Assign("updated_at", time.Now().UTC()),
+	)
+	updateBuilder.Where(
+		updateBuilder.Equal("id", keyPairId),
+		updateBuilder.Equal("state", fromState),
+	)
+	sql, args := updateBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to update keyPair state")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
 func (d *CommonDB) GetAllSigningKeys(tx *sql.Tx) (keyPairs []models.KeyPair, err error) {
 	keyPairStruct := sqlbuilder.NewStruct(new(models.KeyPair)).For(d.Flavor)
 	selectBuilder := keyPairStruct.SelectFrom("key_pairs")
@@ -105,6 +132,29 @@ func (d *CommonDB) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
 	return nil
 }
 
+// DeleteKeyPairWithState deletes the key pair only if it is still in state, reporting whether it did.
+// Like UpdateKeyPairState, it lets concurrent callers agree on a single winner.
+func (d *CommonDB) DeleteKeyPairWithState(tx *sql.Tx, keyPairId int64, state string) (bool, error) {
+	keyPairStruct := sqlbuilder.NewStruct(new(models.KeyPair)).For(d.Flavor)
+	deleteBuilder := keyPairStruct.DeleteFrom("key_pairs")
+	deleteBuilder.Where(
+		deleteBuilder.Equal("id", keyPairId),
+		deleteBuilder.Equal("state", state),
+	)
+	sql, args := deleteBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to delete keyPair")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
 func (d *CommonDB) getKeyPairCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, keyPairStruct *sqlbuilder.Struct) (*models.KeyPair, error) {
 	sql, args := selectBuilder.Build()
 	rows, err := d.QuerySql(tx, sql, args...)
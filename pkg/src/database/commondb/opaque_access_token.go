@@ -0,0 +1,71 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateOpaqueAccessToken(tx *sql.Tx, opaqueAccessToken *models.OpaqueAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := opaqueAccessToken.CreatedAt
+	originalUpdatedAt := opaqueAccessToken.UpdatedAt
+	opaqueAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	opaqueAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	opaqueAccessTokenStruct := sqlbuilder.NewStruct(new(models.OpaqueAccessToken)).For(d.Flavor)
+	insertBuilder := opaqueAccessTokenStruct.WithoutTag("pk").InsertInto("opaque_access_tokens", opaqueAccessToken)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		opaqueAccessToken.CreatedAt = originalCreatedAt
+		opaqueAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert opaqueAccessToken")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		opaqueAccessToken.CreatedAt = originalCreatedAt
+		opaqueAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	opaqueAccessToken.Id = id
+	return nil
+}
+
+func (d *CommonDB) GetOpaqueAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.OpaqueAccessToken, error) {
+	opaqueAccessTokenStruct := sqlbuilder.NewStruct(new(models.OpaqueAccessToken)).For(d.Flavor)
+	selectBuilder := opaqueAccessTokenStruct.SelectFrom("opaque_access_tokens")
+	selectBuilder.Where(selectBuilder.Equal("token_hash", tokenHash))
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var opaqueAccessToken models.OpaqueAccessToken
+	if rows.Next() {
+		addr := opaqueAccessTokenStruct.Addr(&opaqueAccessToken)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan opaqueAccessToken")
+		}
+		return &opaqueAccessToken, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) DeleteExpiredOpaqueAccessTokens(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("opaque_access_tokens")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired opaque access tokens")
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreatePairwiseSubject(tx *sql.Tx, pairwiseSubject *models.PairwiseSubject) error {
+	if pairwiseSubject.UserId == 0 {
+		return errors.WithStack(errors.New("user id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := pairwiseSubject.CreatedAt
+	pairwiseSubject.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	pairwiseSubjectStruct := sqlbuilder.NewStruct(new(models.PairwiseSubject)).For(d.Flavor)
+	insertBuilder := pairwiseSubjectStruct.WithoutTag("pk").InsertInto("pairwise_subjects", pairwiseSubject)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		pairwiseSubject.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to insert pairwiseSubject")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		pairwiseSubject.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	pairwiseSubject.Id = id
+	return nil
+}
+
+func (d *CommonDB) getPairwiseSubjectCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, pairwiseSubjectStruct *sqlbuilder.Struct) (*models.PairwiseSubject, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var pairwiseSubject models.PairwiseSubject
+	if rows.Next() {
+		addr := pairwiseSubjectStruct.Addr(&pairwiseSubject)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan pairwiseSubject")
+		}
+		return &pairwiseSubject, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetPairwiseSubjectByUserIdAndSectorIdentifier(tx *sql.Tx, userId int64, sectorIdentifier string) (*models.PairwiseSubject, error) {
+	pairwiseSubjectStruct := sqlbuilder.NewStruct(new(models.PairwiseSubject)).For(d.Flavor)
+	selectBuilder := pairwiseSubjectStruct.SelectFrom("pairwise_subjects")
+	selectBuilder.Where(
+		selectBuilder.Equal("user_id", userId),
+		selectBuilder.Equal("sector_identifier", sectorIdentifier),
+	)
+	return d.getPairwiseSubjectCommon(tx, selectBuilder, pairwiseSubjectStruct)
+}
+
+func (d *CommonDB) GetPairwiseSubjectBySectorIdentifierAndSubject(tx *sql.Tx, sectorIdentifier string, subject string) (*models.PairwiseSubject, error) {
+	pairwiseSubjectStruct := sqlbuilder.NewStruct(new(models.PairwiseSubject)).For(d.Flavor)
+	selectBuilder := pairwiseSubjectStruct.SelectFrom("pairwise_subjects")
+	selectBuilder.Where(
+		selectBuilder.Equal("sector_identifier", sectorIdentifier),
+		selectBuilder.Equal("subject", subject),
+	)
+	return d.getPairwiseSubjectCommon(tx, selectBuilder, pairwiseSubjectStruct)
+}
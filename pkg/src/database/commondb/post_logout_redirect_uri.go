@@ -0,0 +1,101 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreatePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURI *models.PostLogoutRedirectURI) error {
+	if postLogoutRedirectURI.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := postLogoutRedirectURI.CreatedAt
+	postLogoutRedirectURI.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	postLogoutRedirectURIStruct := sqlbuilder.NewStruct(new(models.PostLogoutRedirectURI)).For(d.Flavor)
+	insertBuilder := postLogoutRedirectURIStruct.WithoutTag("pk").InsertInto("post_logout_redirect_uris", postLogoutRedirectURI)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		postLogoutRedirectURI.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to insert postLogoutRedirectURI")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		postLogoutRedirectURI.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	postLogoutRedirectURI.Id = id
+	return nil
+}
+
+func (d *CommonDB) GetPostLogoutRedirectURIsByClientId(tx *sql.Tx, clientId int64) (postLogoutRedirectURIs []models.PostLogoutRedirectURI, err error) {
+	postLogoutRedirectURIStruct := sqlbuilder.NewStruct(new(models.PostLogoutRedirectURI)).For(d.Flavor)
+	selectBuilder := postLogoutRedirectURIStruct.SelectFrom("post_logout_redirect_uris")
+	selectBuilder.Where(selectBuilder.Equal("client_id", clientId))
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postLogoutRedirectURI models.PostLogoutRedirectURI
+		addr := postLogoutRedirectURIStruct.Addr(&postLogoutRedirectURI)
+		err = rows.Scan(addr...)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to scan postLogoutRedirectURI")
+		}
+		postLogoutRedirectURIs = append(postLogoutRedirectURIs, postLogoutRedirectURI)
+	}
+
+	return
+}
+
+func (d *CommonDB) GetPostLogoutRedirectURIById(tx *sql.Tx, postLogoutRedirectURIId int64) (*models.PostLogoutRedirectURI, error) {
+	postLogoutRedirectURIStruct := sqlbuilder.NewStruct(new(models.PostLogoutRedirectURI)).For(d.Flavor)
+	selectBuilder := postLogoutRedirectURIStruct.SelectFrom("post_logout_redirect_uris")
+	selectBuilder.Where(selectBuilder.Equal("id", postLogoutRedirectURIId))
+	return d.getPostLogoutRedirectURICommon(tx, selectBuilder, postLogoutRedirectURIStruct)
+}
+
+func (d *CommonDB) DeletePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURIId int64) error {
+	clientStruct := sqlbuilder.NewStruct(new(models.PostLogoutRedirectURI)).For(d.Flavor)
+	deleteBuilder := clientStruct.DeleteFrom("post_logout_redirect_uris")
+	deleteBuilder.Where(deleteBuilder.Equal("id", postLogoutRedirectURIId))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete postLogoutRedirectURI")
+	}
+
+	return nil
+}
+
+func (d *CommonDB) getPostLogoutRedirectURICommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, postLogoutRedirectURIStruct *sqlbuilder.Struct) (*models.PostLogoutRedirectURI, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var postLogoutRedirectURI models.PostLogoutRedirectURI
+	if rows.Next() {
+		addr := postLogoutRedirectURIStruct.Addr(&postLogoutRedirectURI)
+		err = rows.Scan(addr...)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to scan postLogoutRedirectURI")
+		}
+		return &postLogoutRedirectURI, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,91 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreatePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequest *models.PushedAuthorizationRequest) error {
+	if pushedAuthorizationRequest.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := pushedAuthorizationRequest.CreatedAt
+	originalUpdatedAt := pushedAuthorizationRequest.UpdatedAt
+	pushedAuthorizationRequest.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	pushedAuthorizationRequest.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	pushedAuthorizationRequestStruct := sqlbuilder.NewStruct(new(models.PushedAuthorizationRequest)).For(d.Flavor)
+	insertBuilder := pushedAuthorizationRequestStruct.WithoutTag("pk").InsertInto("pushed_authorization_requests", pushedAuthorizationRequest)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		pushedAuthorizationRequest.CreatedAt = originalCreatedAt
+		pushedAuthorizationRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert pushedAuthorizationRequest")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		pushedAuthorizationRequest.CreatedAt = originalCreatedAt
+		pushedAuthorizationRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	pushedAuthorizationRequest.Id = id
+	return nil
+}
+
+func (d *CommonDB) getPushedAuthorizationRequestCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, pushedAuthorizationRequestStruct *sqlbuilder.Struct) (*models.PushedAuthorizationRequest, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var pushedAuthorizationRequest models.PushedAuthorizationRequest
+	if rows.Next() {
+		addr := pushedAuthorizationRequestStruct.Addr(&pushedAuthorizationRequest)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan pushedAuthorizationRequest")
+		}
+		return &pushedAuthorizationRequest, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetPushedAuthorizationRequestByRequestURIHash(tx *sql.Tx, requestURIHash string) (*models.PushedAuthorizationRequest, error) {
+	pushedAuthorizationRequestStruct := sqlbuilder.NewStruct(new(models.PushedAuthorizationRequest)).For(d.Flavor)
+	selectBuilder := pushedAuthorizationRequestStruct.SelectFrom("pushed_authorization_requests")
+	selectBuilder.Where(selectBuilder.Equal("request_uri_hash", requestURIHash))
+	return d.getPushedAuthorizationRequestCommon(tx, selectBuilder, pushedAuthorizationRequestStruct)
+}
+
+func (d *CommonDB) DeletePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequestId int64) error {
+	pushedAuthorizationRequestStruct := sqlbuilder.NewStruct(new(models.PushedAuthorizationRequest)).For(d.Flavor)
+	deleteBuilder := pushedAuthorizationRequestStruct.DeleteFrom("pushed_authorization_requests")
+	deleteBuilder.Where(deleteBuilder.Equal("id", pushedAuthorizationRequestId))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete pushedAuthorizationRequest")
+	}
+
+	return nil
+}
+
+func (d *CommonDB) DeleteExpiredPushedAuthorizationRequests(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("pushed_authorization_requests")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired pushed authorization requests")
+	}
+
+	return nil
+}
@@ -69,6 +69,75 @@ func (d *CommonDB) UpdateRefreshToken(tx *sql.Tx, refreshToken *models.RefreshTo
 	return nil
 }
 
+// SupersedeRefreshToken revokes the refresh token as it is exchanged for a new one, only if it is
+// still valid, reporting whether it did. Of concurrent refreshes with the same token, only one rotates it.
+func (d *CommonDB) SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error) {
+	now := time.Now().UTC()
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("refresh_tokens")
+	updateBuilder.Set(
+		updateBuilder.Assign("revoked", true),
+		updateBuilder.Assign("superseded_at", now),
+		updateBuilder.Assign("updated_at", now),
+	)
+	updateBuilder.Where(
+		updateBuilder.Equal("id", refreshTokenId),
+		updateBuilder.Equal("revoked", false),
+	)
+	sql, args := updateBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to supersede refreshToken")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RevokeRefreshTokensByFirstRefreshTokenJti revokes every refresh token of a chain,
+// i.e. all tokens obtained by refreshing the same original refresh token.
+// The superseded tokens are no longer accepted during the reuse grace period.
+func (d *CommonDB) RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error {
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("refresh_tokens")
+	updateBuilder.Set(
+		updateBuilder.Assign("revoked", true),
+		updateBuilder.Assign("superseded_at", nil),
+		updateBuilder.Assign("updated_at", time.Now().UTC()),
+	)
+	updateBuilder.Where(updateBuilder.Equal("first_refresh_token_jti", firstRefreshTokenJti))
+	sql, args := updateBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to revoke refresh tokens")
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokensBySessionIdentifier revokes the refresh tokens bound to a user session,
+// offline refresh tokens are not bound to a session and remain valid.
+// The superseded tokens are no longer accepted during the reuse grace period.
+func (d *CommonDB) RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error {
+	updateBuilder := d.Flavor.NewUpdateBuilder()
+	updateBuilder.Update("refresh_tokens")
+	updateBuilder.Set(
+		updateBuilder.Assign("revoked", true),
+		updateBuilder.Assign("superseded_at", nil),
+		updateBuilder.Assign("updated_at", time.Now().UTC()),
+	)
+	updateBuilder.Where(updateBuilder.Equal("session_identifier", sessionIdentifier))
+	sql, args := updateBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to revoke refresh tokens")
+	}
+
+	return nil
+}
+
 func (d *CommonDB) DeleteRefreshToken(tx *sql.Tx, refreshTokenId int64) error {
 	userConsentStruct := sqlbuilder.NewStruct(new(models.RefreshToken)).For(d.Flavor)
 	deleteBuilder := userConsentStruct.DeleteFrom("refresh_tokens")
@@ -81,6 +150,8 @@ func (d *CommonDB) DeleteRefreshToken(tx *sql.Tx, refreshTokenId int64) error {
 	return nil
 }
 
+// DeleteExpiredOrRevokedRefreshTokens keeps the superseded refresh tokens until they expire,
+// so that presenting them again can still be detected as a reuse
 func (d *CommonDB) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 	deleteBuilder := d.Flavor.NewDeleteBuilder()
 	deleteBuilder.DeleteFrom("refresh_tokens")
@@ -89,7 +160,10 @@ func (d *CommonDB) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 		deleteBuilder.Or(
 			deleteBuilder.LessThan("expires_at", now),
 			deleteBuilder.LessThan("max_lifetime", now),
-			deleteBuilder.Equal("revoked", true),
+			deleteBuilder.And(
+				deleteBuilder.Equal("revoked", true),
+				deleteBuilder.IsNull("superseded_at"),
+			),
 		),
 	)
 
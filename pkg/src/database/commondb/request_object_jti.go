@@ -0,0 +1,82 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateRequestObjectJti(tx *sql.Tx, requestObjectJti *models.RequestObjectJti) error {
+	if requestObjectJti.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := requestObjectJti.CreatedAt
+	originalUpdatedAt := requestObjectJti.UpdatedAt
+	requestObjectJti.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	requestObjectJti.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	requestObjectJtiStruct := sqlbuilder.NewStruct(new(models.RequestObjectJti)).For(d.Flavor)
+	insertBuilder := requestObjectJtiStruct.WithoutTag("pk").InsertInto("request_object_jtis", requestObjectJti)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		requestObjectJti.CreatedAt = originalCreatedAt
+		requestObjectJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert requestObjectJti")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		requestObjectJti.CreatedAt = originalCreatedAt
+		requestObjectJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	requestObjectJti.Id = id
+	return nil
+}
+
+func (d *CommonDB) getRequestObjectJtiCommon(tx *sql.Tx, selectBuilder *sqlbuilder.SelectBuilder, requestObjectJtiStruct *sqlbuilder.Struct) (*models.RequestObjectJti, error) {
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var requestObjectJti models.RequestObjectJti
+	if rows.Next() {
+		addr := requestObjectJtiStruct.Addr(&requestObjectJti)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan requestObjectJti")
+		}
+		return &requestObjectJti, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) GetRequestObjectJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.RequestObjectJti, error) {
+	requestObjectJtiStruct := sqlbuilder.NewStruct(new(models.RequestObjectJti)).For(d.Flavor)
+	selectBuilder := requestObjectJtiStruct.SelectFrom("request_object_jtis")
+	selectBuilder.Where(
+		selectBuilder.Equal("client_id", clientId),
+		selectBuilder.Equal("jti_hash", jtiHash),
+	)
+	return d.getRequestObjectJtiCommon(tx, selectBuilder, requestObjectJtiStruct)
+}
+
+func (d *CommonDB) DeleteExpiredRequestObjectJtis(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("request_object_jtis")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired request object jtis")
+	}
+
+	return nil
+}
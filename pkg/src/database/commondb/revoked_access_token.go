@@ -0,0 +1,71 @@
+package commondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *CommonDB) CreateRevokedAccessToken(tx *sql.Tx, revokedAccessToken *models.RevokedAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := revokedAccessToken.CreatedAt
+	originalUpdatedAt := revokedAccessToken.UpdatedAt
+	revokedAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	revokedAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	revokedAccessTokenStruct := sqlbuilder.NewStruct(new(models.RevokedAccessToken)).For(d.Flavor)
+	insertBuilder := revokedAccessTokenStruct.WithoutTag("pk").InsertInto("revoked_access_tokens", revokedAccessToken)
+	sql, args := insertBuilder.Build()
+	result, err := d.ExecSql(tx, sql, args...)
+	if err != nil {
+		revokedAccessToken.CreatedAt = originalCreatedAt
+		revokedAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert revokedAccessToken")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		revokedAccessToken.CreatedAt = originalCreatedAt
+		revokedAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to get last insert id")
+	}
+
+	revokedAccessToken.Id = id
+	return nil
+}
+
+func (d *CommonDB) GetRevokedAccessTokenByJti(tx *sql.Tx, jti string) (*models.RevokedAccessToken, error) {
+	revokedAccessTokenStruct := sqlbuilder.NewStruct(new(models.RevokedAccessToken)).For(d.Flavor)
+	selectBuilder := revokedAccessTokenStruct.SelectFrom("revoked_access_tokens")
+	selectBuilder.Where(selectBuilder.Equal("jti", jti))
+	sql, args := selectBuilder.Build()
+	rows, err := d.QuerySql(tx, sql, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to query database")
+	}
+	defer rows.Close()
+
+	var revokedAccessToken models.RevokedAccessToken
+	if rows.Next() {
+		addr := revokedAccessTokenStruct.Addr(&revokedAccessToken)
+		if err = rows.Scan(addr...); err != nil {
+			return nil, errors.Wrap(err, "unable to scan revokedAccessToken")
+		}
+		return &revokedAccessToken, nil
+	}
+	return nil, nil
+}
+
+func (d *CommonDB) DeleteExpiredRevokedAccessTokens(tx *sql.Tx) error {
+	deleteBuilder := d.Flavor.NewDeleteBuilder()
+	deleteBuilder.DeleteFrom("revoked_access_tokens")
+	deleteBuilder.Where(deleteBuilder.LessThan("expires_at", time.Now().UTC()))
+	sql, args := deleteBuilder.Build()
+	if _, err := d.ExecSql(tx, sql, args...); err != nil {
+		return errors.Wrap(err, "unable to delete expired revoked access tokens")
+	}
+
+	return nil
+}
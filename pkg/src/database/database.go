@@ -30,6 +30,7 @@ type Database interface {
 	GetAllClients(tx *sql.Tx) ([]models.Client, error)
 	DeleteClient(tx *sql.Tx, clientId int64) error
 	ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) error
+	ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) error
 	ClientLoadWebOrigins(tx *sql.Tx, client *models.Client) error
 	ClientLoadPermissions(tx *sql.Tx, client *models.Client) error
 	CreateUser(tx *sql.Tx, user *models.User) error
@@ -49,6 +50,7 @@ type Database interface {
 	UserLoadAttributes(tx *sql.Tx, user *models.User) error
 	CreateCode(tx *sql.Tx, code *models.Code) error
 	UpdateCode(tx *sql.Tx, code *models.Code) error
+	MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error)
 	GetCodeById(tx *sql.Tx, codeId int64) (*models.Code, error)
 	GetCodeByCodeHash(tx *sql.Tx, codeHash string, used bool) (*models.Code, error)
 	DeleteCode(tx *sql.Tx, codeId int64) error
@@ -71,14 +73,20 @@ type Database interface {
 	PermissionsLoadResources(tx *sql.Tx, permissions []models.Permission) error
 	CreateKeyPair(tx *sql.Tx, keyPair *models.KeyPair) error
 	UpdateKeyPair(tx *sql.Tx, keyPair *models.KeyPair) error
+	UpdateKeyPairState(tx *sql.Tx, keyPairId int64, fromState string, toState string) (bool, error)
 	GetKeyPairById(tx *sql.Tx, keyPairId int64) (*models.KeyPair, error)
 	GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error)
 	GetCurrentSigningKey(tx *sql.Tx) (*models.KeyPair, error)
 	DeleteKeyPair(tx *sql.Tx, keyPairId int64) error
+	DeleteKeyPairWithState(tx *sql.Tx, keyPairId int64, state string) (bool, error)
 	CreateRedirectURI(tx *sql.Tx, redirectURI *models.RedirectURI) error
 	GetRedirectURIById(tx *sql.Tx, redirectURIId int64) (*models.RedirectURI, error)
 	GetRedirectURIsByClientId(tx *sql.Tx, clientId int64) ([]models.RedirectURI, error)
 	DeleteRedirectURI(tx *sql.Tx, redirectURIId int64) error
+	CreatePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURI *models.PostLogoutRedirectURI) error
+	GetPostLogoutRedirectURIById(tx *sql.Tx, postLogoutRedirectURIId int64) (*models.PostLogoutRedirectURI, error)
+	GetPostLogoutRedirectURIsByClientId(tx *sql.Tx, clientId int64) ([]models.PostLogoutRedirectURI, error)
+	DeletePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURIId int64) error
 	CreateWebOrigin(tx *sql.Tx, webOrigin *models.WebOrigin) error
 	GetWebOriginById(tx *sql.Tx, webOriginId int64) (*models.WebOrigin, error)
 	GetAllWebOrigins(tx *sql.Tx) ([]models.WebOrigin, error)
@@ -172,6 +180,54 @@ type Database interface {
 	DeleteRefreshToken(tx *sql.Tx, refreshTokenId int64) error
 	RefreshTokenLoadCode(tx *sql.Tx, refreshToken *models.RefreshToken) error
 	DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error
+	SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error)
+	RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error
+	RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error
+	CreateRevokedAccessToken(tx *sql.Tx, revokedAccessToken *models.RevokedAccessToken) error
+	GetRevokedAccessTokenByJti(tx *sql.Tx, jti string) (*models.RevokedAccessToken, error)
+	DeleteExpiredRevokedAccessTokens(tx *sql.Tx) error
+	CreateOpaqueAccessToken(tx *sql.Tx, opaqueAccessToken *models.OpaqueAccessToken) error
+	GetOpaqueAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.OpaqueAccessToken, error)
+	DeleteExpiredOpaqueAccessTokens(tx *sql.Tx) error
+	CreateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error
+	UpdateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error
+	UpdateDeviceCodeStatus(tx *sql.Tx, deviceCodeId int64, fromStatus string, toStatus string) (bool, error)
+	GetDeviceCodeById(tx *sql.Tx, deviceCodeId int64) (*models.DeviceCode, error)
+	GetDeviceCodeByDeviceCodeHash(tx *sql.Tx, deviceCodeHash string) (*models.DeviceCode, error)
+	GetDeviceCodeByUserCode(tx *sql.Tx, userCode string) (*models.DeviceCode, error)
+	DeviceCodeLoadClient(tx *sql.Tx, deviceCode *models.DeviceCode) error
+	DeleteExpiredDeviceCodes(tx *sql.Tx) error
+	CreateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error
+	UpdateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error
+	UpdateCIBARequestStatus(tx *sql.Tx, cibaRequestId int64, fromStatus string, toStatus string) (bool, error)
+	GetCIBARequestById(tx *sql.Tx, cibaRequestId int64) (*models.CIBARequest, error)
+	GetCIBARequestByAuthReqIdHash(tx *sql.Tx, authReqIdHash string) (*models.CIBARequest, error)
+	GetCIBARequestByVerificationCodeHash(tx *sql.Tx, verificationCodeHash string) (*models.CIBARequest, error)
+	CIBARequestLoadClient(tx *sql.Tx, cibaRequest *models.CIBARequest) error
+	CIBARequestLoadUser(tx *sql.Tx, cibaRequest *models.CIBARequest) error
+	DeleteExpiredCIBARequests(tx *sql.Tx) error
+	CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error
+	GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error)
+	GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error)
+	GetAllInitialAccessTokens(tx *sql.Tx) ([]models.InitialAccessToken, error)
+	DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error
+	DeleteExpiredInitialAccessTokens(tx *sql.Tx) error
+	CreatePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequest *models.PushedAuthorizationRequest) error
+	GetPushedAuthorizationRequestByRequestURIHash(tx *sql.Tx, requestURIHash string) (*models.PushedAuthorizationRequest, error)
+	DeletePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequestId int64) error
+	DeleteExpiredPushedAuthorizationRequests(tx *sql.Tx) error
+	CreateClientAssertionJti(tx *sql.Tx, clientAssertionJti *models.ClientAssertionJti) error
+	GetClientAssertionJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.ClientAssertionJti, error)
+	DeleteExpiredClientAssertionJtis(tx *sql.Tx) error
+	CreateRequestObjectJti(tx *sql.Tx, requestObjectJti *models.RequestObjectJti) error
+	GetRequestObjectJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.RequestObjectJti, error)
+	DeleteExpiredRequestObjectJtis(tx *sql.Tx) error
+	CreateDPoPProofJti(tx *sql.Tx, dpopProofJti *models.DPoPProofJti) error
+	GetDPoPProofJti(tx *sql.Tx, jtiHash string) (*models.DPoPProofJti, error)
+	DeleteExpiredDPoPProofJtis(tx *sql.Tx) error
+	CreatePairwiseSubject(tx *sql.Tx, pairwiseSubject *models.PairwiseSubject) error
+	GetPairwiseSubjectByUserIdAndSectorIdentifier(tx *sql.Tx, userId int64, sectorIdentifier string) (*models.PairwiseSubject, error)
+	GetPairwiseSubjectBySectorIdentifierAndSubject(tx *sql.Tx, sectorIdentifier string, subject string) (*models.PairwiseSubject, error)
 	CreateUserSessionClient(tx *sql.Tx, userSessionClient *models.UserSessionClient) error
 	UpdateUserSessionClient(tx *sql.Tx, userSessionClient *models.UserSessionClient) error
 	GetUserSessionClientById(tx *sql.Tx, userSessionClientId int64) (*models.UserSessionClient, error)
@@ -1,8 +1,6 @@
 package database
 
 import (
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"log/slog"
 
@@ -13,10 +11,9 @@ import (
 	"github.com/pchchv/aas/pkg/src/encryption"
 	"github.com/pchchv/aas/pkg/src/enums"
 	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/keyutil"
 	"github.com/pchchv/aas/pkg/src/models"
-	"github.com/pchchv/aas/pkg/src/rsautil"
 	"github.com/pchchv/aas/pkg/src/stringutil"
-	"github.com/pkg/errors"
 )
 
 type DatabaseSeeder struct {
@@ -44,6 +41,7 @@ func (ds *DatabaseSeeder) Seed() (err error) {
 		ClientCredentialsEnabled:                false,
 		ClientSecretEncrypted:                   clientSecretEncrypted,
 		IncludeOpenIDConnectClaimsInAccessToken: enums.ThreeStateSettingDefault.String(),
+		SubjectType:                             constants.SubjectTypePublic,
 	}
 
 	if err = ds.DB.CreateClient(nil, client1); err != nil {
@@ -174,39 +172,12 @@ func (ds *DatabaseSeeder) Seed() (err error) {
 	slog.Info(fmt.Sprintf("user '%v' granted permission '%v'", user.Email, permission3.PermissionIdentifier))
 
 	// key pair (current)
-	privateKey, err := rsautil.GeneratePrivateKey(4096)
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmRS256)
 	if err != nil {
-		return errors.Wrap(err, "unable to generate a private key")
+		return err
 	}
 
-	privateKeyPEM := rsautil.EncodePrivateKeyToPEM(privateKey)
-	publicKeyASN1_DER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
-	if err != nil {
-		return errors.Wrap(err, "unable to marshal public key to PKIX")
-	}
-
-	publicKeyPEM := pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "RSA PUBLIC KEY",
-			Bytes: publicKeyASN1_DER,
-		},
-	)
-	kid := uuid.New().String()
-	publicKeyJWK, err := rsautil.MarshalRSAPublicKeyToJWK(&privateKey.PublicKey, kid)
-	if err != nil {
-		return
-	}
-
-	keyPair := &models.KeyPair{
-		State:             enums.KeyStateCurrent.String(),
-		KeyIdentifier:     kid,
-		Type:              "RSA",
-		Algorithm:         "RS256",
-		PrivateKeyPEM:     privateKeyPEM,
-		PublicKeyPEM:      publicKeyPEM,
-		PublicKeyASN1_DER: publicKeyASN1_DER,
-		PublicKeyJWK:      publicKeyJWK,
-	}
+	keyPair.State = enums.KeyStateCurrent.String()
 	if err = ds.DB.CreateKeyPair(nil, keyPair); err != nil {
 		return
 	}
@@ -214,38 +185,11 @@ func (ds *DatabaseSeeder) Seed() (err error) {
 	slog.Info(fmt.Sprintf("key pair '%v' (current) created", keyPair.KeyIdentifier))
 
 	// key pair (next)
-	privateKey, err = rsautil.GeneratePrivateKey(4096)
-	if err != nil {
-		return errors.Wrap(err, "unable to generate a private key")
-	}
-
-	privateKeyPEM = rsautil.EncodePrivateKeyToPEM(privateKey)
-	publicKeyASN1_DER, err = x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
-	if err != nil {
-		return errors.Wrap(err, "unable to marshal public key to PKIX")
+	if keyPair, err = keyutil.GenerateKeyPair(keyutil.AlgorithmRS256); err != nil {
+		return err
 	}
 
-	publicKeyPEM = pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "RSA PUBLIC KEY",
-			Bytes: publicKeyASN1_DER,
-		},
-	)
-	kid = uuid.New().String()
-	if publicKeyJWK, err = rsautil.MarshalRSAPublicKeyToJWK(&privateKey.PublicKey, kid); err != nil {
-		return
-	}
-
-	keyPair = &models.KeyPair{
-		State:             enums.KeyStateNext.String(),
-		KeyIdentifier:     kid,
-		Type:              "RSA",
-		Algorithm:         "RS256",
-		PrivateKeyPEM:     privateKeyPEM,
-		PublicKeyPEM:      publicKeyPEM,
-		PublicKeyASN1_DER: publicKeyASN1_DER,
-		PublicKeyJWK:      publicKeyJWK,
-	}
+	keyPair.State = enums.KeyStateNext.String()
 	if err = ds.DB.CreateKeyPair(nil, keyPair); err != nil {
 		return
 	}
@@ -264,16 +208,19 @@ func (ds *DatabaseSeeder) Seed() (err error) {
 		UITheme:                 "",
 		SelfRegistrationEnabled: true,
 		SelfRegistrationRequiresEmailVerification: false,
-		PasswordPolicy:                          enums.PasswordPolicyLow,
-		SessionAuthenticationKey:                securecookie.GenerateRandomKey(64),
-		SessionEncryptionKey:                    securecookie.GenerateRandomKey(32),
-		AESEncryptionKey:                        encryptionKey,
-		TokenExpirationInSeconds:                300,      // 5 minutes
-		RefreshTokenOfflineIdleTimeoutInSeconds: 2592000,  // 30 days
-		RefreshTokenOfflineMaxLifetimeInSeconds: 31536000, // 1 year
-		UserSessionIdleTimeoutInSeconds:         7200,     // 2 hours
-		UserSessionMaxLifetimeInSeconds:         86400,    // 24 hours
-		IncludeOpenIDConnectClaimsInAccessToken: false,
+		DynamicClientRegistrationEnabled:          false,
+		DynamicClientRegistrationOpen:             false,
+		SigningKeyAlgorithm:                       keyutil.AlgorithmRS256,
+		PasswordPolicy:                            enums.PasswordPolicyLow,
+		SessionAuthenticationKey:                  securecookie.GenerateRandomKey(64),
+		SessionEncryptionKey:                      securecookie.GenerateRandomKey(32),
+		AESEncryptionKey:                          encryptionKey,
+		TokenExpirationInSeconds:                  300,      // 5 minutes
+		RefreshTokenOfflineIdleTimeoutInSeconds:   2592000,  // 30 days
+		RefreshTokenOfflineMaxLifetimeInSeconds:   31536000, // 1 year
+		UserSessionIdleTimeoutInSeconds:           7200,     // 2 hours
+		UserSessionMaxLifetimeInSeconds:           86400,    // 24 hours
+		IncludeOpenIDConnectClaimsInAccessToken:   false,
 	}
 	if err = ds.DB.CreateSettings(nil, settings); err != nil {
 		return
@@ -46,6 +46,42 @@ func (_m *Database) BeginTransaction() (*sql.Tx, error) {
 	return r0, r1
 }
 
+// CIBARequestLoadClient provides a mock function with given fields: tx, cibaRequest
+func (_m *Database) CIBARequestLoadClient(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	ret := _m.Called(tx, cibaRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CIBARequestLoadClient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.CIBARequest) error); ok {
+		r0 = rf(tx, cibaRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CIBARequestLoadUser provides a mock function with given fields: tx, cibaRequest
+func (_m *Database) CIBARequestLoadUser(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	ret := _m.Called(tx, cibaRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CIBARequestLoadUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.CIBARequest) error); ok {
+		r0 = rf(tx, cibaRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ClientLoadPermissions provides a mock function with given fields: tx, client
 func (_m *Database) ClientLoadPermissions(tx *sql.Tx, client *models.Client) error {
 	ret := _m.Called(tx, client)
@@ -64,6 +100,24 @@ func (_m *Database) ClientLoadPermissions(tx *sql.Tx, client *models.Client) err
 	return r0
 }
 
+// ClientLoadPostLogoutRedirectURIs provides a mock function with given fields: tx, client
+func (_m *Database) ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) error {
+	ret := _m.Called(tx, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClientLoadPostLogoutRedirectURIs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.Client) error); ok {
+		r0 = rf(tx, client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ClientLoadRedirectURIs provides a mock function with given fields: tx, client
 func (_m *Database) ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) error {
 	ret := _m.Called(tx, client)
@@ -182,6 +236,24 @@ func (_m *Database) CountGroupMembers(tx *sql.Tx, groupId int64) (int, error) {
 	return r0, r1
 }
 
+// CreateCIBARequest provides a mock function with given fields: tx, cibaRequest
+func (_m *Database) CreateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	ret := _m.Called(tx, cibaRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCIBARequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.CIBARequest) error); ok {
+		r0 = rf(tx, cibaRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateClient provides a mock function with given fields: tx, client
 func (_m *Database) CreateClient(tx *sql.Tx, client *models.Client) error {
 	ret := _m.Called(tx, client)
@@ -200,6 +272,24 @@ func (_m *Database) CreateClient(tx *sql.Tx, client *models.Client) error {
 	return r0
 }
 
+// CreateClientAssertionJti provides a mock function with given fields: tx, clientAssertionJti
+func (_m *Database) CreateClientAssertionJti(tx *sql.Tx, clientAssertionJti *models.ClientAssertionJti) error {
+	ret := _m.Called(tx, clientAssertionJti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateClientAssertionJti")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.ClientAssertionJti) error); ok {
+		r0 = rf(tx, clientAssertionJti)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateClientPermission provides a mock function with given fields: tx, clientPermission
 func (_m *Database) CreateClientPermission(tx *sql.Tx, clientPermission *models.ClientPermission) error {
 	ret := _m.Called(tx, clientPermission)
@@ -236,6 +326,42 @@ func (_m *Database) CreateCode(tx *sql.Tx, code *models.Code) error {
 	return r0
 }
 
+// CreateDPoPProofJti provides a mock function with given fields: tx, dpopProofJti
+func (_m *Database) CreateDPoPProofJti(tx *sql.Tx, dpopProofJti *models.DPoPProofJti) error {
+	ret := _m.Called(tx, dpopProofJti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDPoPProofJti")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.DPoPProofJti) error); ok {
+		r0 = rf(tx, dpopProofJti)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateDeviceCode provides a mock function with given fields: tx, deviceCode
+func (_m *Database) CreateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	ret := _m.Called(tx, deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDeviceCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.DeviceCode) error); ok {
+		r0 = rf(tx, deviceCode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateGroup provides a mock function with given fields: tx, group
 func (_m *Database) CreateGroup(tx *sql.Tx, group *models.Group) error {
 	ret := _m.Called(tx, group)
@@ -308,6 +434,24 @@ func (_m *Database) CreateHttpSession(tx *sql.Tx, httpSession *models.HttpSessio
 	return r0
 }
 
+// CreateInitialAccessToken provides a mock function with given fields: tx, initialAccessToken
+func (_m *Database) CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error {
+	ret := _m.Called(tx, initialAccessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInitialAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.InitialAccessToken) error); ok {
+		r0 = rf(tx, initialAccessToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateKeyPair provides a mock function with given fields: tx, keyPair
 func (_m *Database) CreateKeyPair(tx *sql.Tx, keyPair *models.KeyPair) error {
 	ret := _m.Called(tx, keyPair)
@@ -326,6 +470,42 @@ func (_m *Database) CreateKeyPair(tx *sql.Tx, keyPair *models.KeyPair) error {
 	return r0
 }
 
+// CreateOpaqueAccessToken provides a mock function with given fields: tx, opaqueAccessToken
+func (_m *Database) CreateOpaqueAccessToken(tx *sql.Tx, opaqueAccessToken *models.OpaqueAccessToken) error {
+	ret := _m.Called(tx, opaqueAccessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOpaqueAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.OpaqueAccessToken) error); ok {
+		r0 = rf(tx, opaqueAccessToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePairwiseSubject provides a mock function with given fields: tx, pairwiseSubject
+func (_m *Database) CreatePairwiseSubject(tx *sql.Tx, pairwiseSubject *models.PairwiseSubject) error {
+	ret := _m.Called(tx, pairwiseSubject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePairwiseSubject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.PairwiseSubject) error); ok {
+		r0 = rf(tx, pairwiseSubject)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreatePermission provides a mock function with given fields: tx, permission
 func (_m *Database) CreatePermission(tx *sql.Tx, permission *models.Permission) error {
 	ret := _m.Called(tx, permission)
@@ -344,6 +524,24 @@ func (_m *Database) CreatePermission(tx *sql.Tx, permission *models.Permission)
 	return r0
 }
 
+// CreatePostLogoutRedirectURI provides a mock function with given fields: tx, postLogoutRedirectURI
+func (_m *Database) CreatePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURI *models.PostLogoutRedirectURI) error {
+	ret := _m.Called(tx, postLogoutRedirectURI)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePostLogoutRedirectURI")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.PostLogoutRedirectURI) error); ok {
+		r0 = rf(tx, postLogoutRedirectURI)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreatePreRegistration provides a mock function with given fields: tx, preRegistration
 func (_m *Database) CreatePreRegistration(tx *sql.Tx, preRegistration *models.PreRegistration) error {
 	ret := _m.Called(tx, preRegistration)
@@ -362,6 +560,24 @@ func (_m *Database) CreatePreRegistration(tx *sql.Tx, preRegistration *models.Pr
 	return r0
 }
 
+// CreatePushedAuthorizationRequest provides a mock function with given fields: tx, pushedAuthorizationRequest
+func (_m *Database) CreatePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequest *models.PushedAuthorizationRequest) error {
+	ret := _m.Called(tx, pushedAuthorizationRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePushedAuthorizationRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.PushedAuthorizationRequest) error); ok {
+		r0 = rf(tx, pushedAuthorizationRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateRedirectURI provides a mock function with given fields: tx, redirectURI
 func (_m *Database) CreateRedirectURI(tx *sql.Tx, redirectURI *models.RedirectURI) error {
 	ret := _m.Called(tx, redirectURI)
@@ -398,6 +614,24 @@ func (_m *Database) CreateRefreshToken(tx *sql.Tx, refreshToken *models.RefreshT
 	return r0
 }
 
+// CreateRequestObjectJti provides a mock function with given fields: tx, requestObjectJti
+func (_m *Database) CreateRequestObjectJti(tx *sql.Tx, requestObjectJti *models.RequestObjectJti) error {
+	ret := _m.Called(tx, requestObjectJti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRequestObjectJti")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.RequestObjectJti) error); ok {
+		r0 = rf(tx, requestObjectJti)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateResource provides a mock function with given fields: tx, resource
 func (_m *Database) CreateResource(tx *sql.Tx, resource *models.Resource) error {
 	ret := _m.Called(tx, resource)
@@ -416,6 +650,24 @@ func (_m *Database) CreateResource(tx *sql.Tx, resource *models.Resource) error
 	return r0
 }
 
+// CreateRevokedAccessToken provides a mock function with given fields: tx, revokedAccessToken
+func (_m *Database) CreateRevokedAccessToken(tx *sql.Tx, revokedAccessToken *models.RevokedAccessToken) error {
+	ret := _m.Called(tx, revokedAccessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRevokedAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.RevokedAccessToken) error); ok {
+		r0 = rf(tx, revokedAccessToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateSettings provides a mock function with given fields: tx, settings
 func (_m *Database) CreateSettings(tx *sql.Tx, settings *models.Settings) error {
 	ret := _m.Called(tx, settings)
@@ -650,12 +902,12 @@ func (_m *Database) DeleteCode(tx *sql.Tx, codeId int64) error {
 	return r0
 }
 
-// DeleteExpiredOrRevokedRefreshTokens provides a mock function with given fields: tx
-func (_m *Database) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
+// DeleteExpiredCIBARequests provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredCIBARequests(tx *sql.Tx) error {
 	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteExpiredOrRevokedRefreshTokens")
+		panic("no return value specified for DeleteExpiredCIBARequests")
 	}
 
 	var r0 error
@@ -668,17 +920,17 @@ func (_m *Database) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 	return r0
 }
 
-// DeleteExpiredSessions provides a mock function with given fields: tx, maxLifetime
-func (_m *Database) DeleteExpiredSessions(tx *sql.Tx, maxLifetime time.Duration) error {
-	ret := _m.Called(tx, maxLifetime)
+// DeleteExpiredClientAssertionJtis provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredClientAssertionJtis(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteExpiredSessions")
+		panic("no return value specified for DeleteExpiredClientAssertionJtis")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, time.Duration) error); ok {
-		r0 = rf(tx, maxLifetime)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -686,17 +938,17 @@ func (_m *Database) DeleteExpiredSessions(tx *sql.Tx, maxLifetime time.Duration)
 	return r0
 }
 
-// DeleteGroup provides a mock function with given fields: tx, groupId
-func (_m *Database) DeleteGroup(tx *sql.Tx, groupId int64) error {
-	ret := _m.Called(tx, groupId)
+// DeleteExpiredDPoPProofJtis provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredDPoPProofJtis(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteGroup")
+		panic("no return value specified for DeleteExpiredDPoPProofJtis")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
-		r0 = rf(tx, groupId)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -704,17 +956,17 @@ func (_m *Database) DeleteGroup(tx *sql.Tx, groupId int64) error {
 	return r0
 }
 
-// DeleteGroupAttribute provides a mock function with given fields: tx, groupAttributeId
-func (_m *Database) DeleteGroupAttribute(tx *sql.Tx, groupAttributeId int64) error {
-	ret := _m.Called(tx, groupAttributeId)
+// DeleteExpiredDeviceCodes provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredDeviceCodes(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteGroupAttribute")
+		panic("no return value specified for DeleteExpiredDeviceCodes")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
-		r0 = rf(tx, groupAttributeId)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -722,17 +974,17 @@ func (_m *Database) DeleteGroupAttribute(tx *sql.Tx, groupAttributeId int64) err
 	return r0
 }
 
-// DeleteGroupPermission provides a mock function with given fields: tx, groupPermissionId
-func (_m *Database) DeleteGroupPermission(tx *sql.Tx, groupPermissionId int64) error {
-	ret := _m.Called(tx, groupPermissionId)
+// DeleteExpiredInitialAccessTokens provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredInitialAccessTokens(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteGroupPermission")
+		panic("no return value specified for DeleteExpiredInitialAccessTokens")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
-		r0 = rf(tx, groupPermissionId)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -740,17 +992,17 @@ func (_m *Database) DeleteGroupPermission(tx *sql.Tx, groupPermissionId int64) e
 	return r0
 }
 
-// DeleteHttpSession provides a mock function with given fields: tx, httpSessionId
-func (_m *Database) DeleteHttpSession(tx *sql.Tx, httpSessionId int64) error {
-	ret := _m.Called(tx, httpSessionId)
+// DeleteExpiredOpaqueAccessTokens provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredOpaqueAccessTokens(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteHttpSession")
+		panic("no return value specified for DeleteExpiredOpaqueAccessTokens")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
-		r0 = rf(tx, httpSessionId)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -758,12 +1010,12 @@ func (_m *Database) DeleteHttpSession(tx *sql.Tx, httpSessionId int64) error {
 	return r0
 }
 
-// DeleteHttpSessionExpired provides a mock function with given fields: tx
-func (_m *Database) DeleteHttpSessionExpired(tx *sql.Tx) error {
+// DeleteExpiredOrRevokedRefreshTokens provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteHttpSessionExpired")
+		panic("no return value specified for DeleteExpiredOrRevokedRefreshTokens")
 	}
 
 	var r0 error
@@ -776,17 +1028,17 @@ func (_m *Database) DeleteHttpSessionExpired(tx *sql.Tx) error {
 	return r0
 }
 
-// DeleteIdleSessions provides a mock function with given fields: tx, idleTimeout
-func (_m *Database) DeleteIdleSessions(tx *sql.Tx, idleTimeout time.Duration) error {
-	ret := _m.Called(tx, idleTimeout)
+// DeleteExpiredPushedAuthorizationRequests provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredPushedAuthorizationRequests(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteIdleSessions")
+		panic("no return value specified for DeleteExpiredPushedAuthorizationRequests")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, time.Duration) error); ok {
-		r0 = rf(tx, idleTimeout)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -794,17 +1046,17 @@ func (_m *Database) DeleteIdleSessions(tx *sql.Tx, idleTimeout time.Duration) er
 	return r0
 }
 
-// DeleteKeyPair provides a mock function with given fields: tx, keyPairId
-func (_m *Database) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
-	ret := _m.Called(tx, keyPairId)
+// DeleteExpiredRequestObjectJtis provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredRequestObjectJtis(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteKeyPair")
+		panic("no return value specified for DeleteExpiredRequestObjectJtis")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
-		r0 = rf(tx, keyPairId)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -812,17 +1064,17 @@ func (_m *Database) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
 	return r0
 }
 
-// DeletePermission provides a mock function with given fields: tx, permissionId
-func (_m *Database) DeletePermission(tx *sql.Tx, permissionId int64) error {
-	ret := _m.Called(tx, permissionId)
+// DeleteExpiredRevokedAccessTokens provides a mock function with given fields: tx
+func (_m *Database) DeleteExpiredRevokedAccessTokens(tx *sql.Tx) error {
+	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeletePermission")
+		panic("no return value specified for DeleteExpiredRevokedAccessTokens")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
-		r0 = rf(tx, permissionId)
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -830,10 +1082,236 @@ func (_m *Database) DeletePermission(tx *sql.Tx, permissionId int64) error {
 	return r0
 }
 
-// DeletePreRegistration provides a mock function with given fields: tx, preRegistrationId
-func (_m *Database) DeletePreRegistration(tx *sql.Tx, preRegistrationId int64) error {
-	ret := _m.Called(tx, preRegistrationId)
-
+// DeleteExpiredSessions provides a mock function with given fields: tx, maxLifetime
+func (_m *Database) DeleteExpiredSessions(tx *sql.Tx, maxLifetime time.Duration) error {
+	ret := _m.Called(tx, maxLifetime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, time.Duration) error); ok {
+		r0 = rf(tx, maxLifetime)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteGroup provides a mock function with given fields: tx, groupId
+func (_m *Database) DeleteGroup(tx *sql.Tx, groupId int64) error {
+	ret := _m.Called(tx, groupId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, groupId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteGroupAttribute provides a mock function with given fields: tx, groupAttributeId
+func (_m *Database) DeleteGroupAttribute(tx *sql.Tx, groupAttributeId int64) error {
+	ret := _m.Called(tx, groupAttributeId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroupAttribute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, groupAttributeId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteGroupPermission provides a mock function with given fields: tx, groupPermissionId
+func (_m *Database) DeleteGroupPermission(tx *sql.Tx, groupPermissionId int64) error {
+	ret := _m.Called(tx, groupPermissionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroupPermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, groupPermissionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteHttpSession provides a mock function with given fields: tx, httpSessionId
+func (_m *Database) DeleteHttpSession(tx *sql.Tx, httpSessionId int64) error {
+	ret := _m.Called(tx, httpSessionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHttpSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, httpSessionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteHttpSessionExpired provides a mock function with given fields: tx
+func (_m *Database) DeleteHttpSessionExpired(tx *sql.Tx) error {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHttpSessionExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) error); ok {
+		r0 = rf(tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteIdleSessions provides a mock function with given fields: tx, idleTimeout
+func (_m *Database) DeleteIdleSessions(tx *sql.Tx, idleTimeout time.Duration) error {
+	ret := _m.Called(tx, idleTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIdleSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, time.Duration) error); ok {
+		r0 = rf(tx, idleTimeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteInitialAccessToken provides a mock function with given fields: tx, initialAccessTokenId
+func (_m *Database) DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error {
+	ret := _m.Called(tx, initialAccessTokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteInitialAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, initialAccessTokenId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteKeyPair provides a mock function with given fields: tx, keyPairId
+func (_m *Database) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
+	ret := _m.Called(tx, keyPairId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKeyPair")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, keyPairId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteKeyPairWithState provides a mock function with given fields: tx, keyPairId, state
+func (_m *Database) DeleteKeyPairWithState(tx *sql.Tx, keyPairId int64, state string) (bool, error) {
+	ret := _m.Called(tx, keyPairId, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKeyPairWithState")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) (bool, error)); ok {
+		return rf(tx, keyPairId, state)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) bool); ok {
+		r0 = rf(tx, keyPairId, state)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string) error); ok {
+		r1 = rf(tx, keyPairId, state)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePermission provides a mock function with given fields: tx, permissionId
+func (_m *Database) DeletePermission(tx *sql.Tx, permissionId int64) error {
+	ret := _m.Called(tx, permissionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePermission")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, permissionId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePostLogoutRedirectURI provides a mock function with given fields: tx, postLogoutRedirectURIId
+func (_m *Database) DeletePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURIId int64) error {
+	ret := _m.Called(tx, postLogoutRedirectURIId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePostLogoutRedirectURI")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, postLogoutRedirectURIId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePreRegistration provides a mock function with given fields: tx, preRegistrationId
+func (_m *Database) DeletePreRegistration(tx *sql.Tx, preRegistrationId int64) error {
+	ret := _m.Called(tx, preRegistrationId)
+
 	if len(ret) == 0 {
 		panic("no return value specified for DeletePreRegistration")
 	}
@@ -848,6 +1326,24 @@ func (_m *Database) DeletePreRegistration(tx *sql.Tx, preRegistrationId int64) e
 	return r0
 }
 
+// DeletePushedAuthorizationRequest provides a mock function with given fields: tx, pushedAuthorizationRequestId
+func (_m *Database) DeletePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequestId int64) error {
+	ret := _m.Called(tx, pushedAuthorizationRequestId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePushedAuthorizationRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) error); ok {
+		r0 = rf(tx, pushedAuthorizationRequestId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteRedirectURI provides a mock function with given fields: tx, redirectURIId
 func (_m *Database) DeleteRedirectURI(tx *sql.Tx, redirectURIId int64) error {
 	ret := _m.Called(tx, redirectURIId)
@@ -1064,6 +1560,24 @@ func (_m *Database) DeleteWebOrigin(tx *sql.Tx, webOriginId int64) error {
 	return r0
 }
 
+// DeviceCodeLoadClient provides a mock function with given fields: tx, deviceCode
+func (_m *Database) DeviceCodeLoadClient(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	ret := _m.Called(tx, deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeviceCodeLoadClient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.DeviceCode) error); ok {
+		r0 = rf(tx, deviceCode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAllClients provides a mock function with given fields: tx
 func (_m *Database) GetAllClients(tx *sql.Tx) ([]models.Client, error) {
 	ret := _m.Called(tx)
@@ -1074,14 +1588,171 @@ func (_m *Database) GetAllClients(tx *sql.Tx) ([]models.Client, error) {
 
 	var r0 []models.Client
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.Client, error)); ok {
+	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.Client, error)); ok {
+		return rf(tx)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.Client); ok {
+		r0 = rf(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Client)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
+		r1 = rf(tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllGroups provides a mock function with given fields: tx
+func (_m *Database) GetAllGroups(tx *sql.Tx) ([]models.Group, error) {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllGroups")
+	}
+
+	var r0 []models.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.Group, error)); ok {
+		return rf(tx)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.Group); ok {
+		r0 = rf(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
+		r1 = rf(tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllGroupsPaginated provides a mock function with given fields: tx, page, pageSize
+func (_m *Database) GetAllGroupsPaginated(tx *sql.Tx, page int, pageSize int) ([]models.Group, int, error) {
+	ret := _m.Called(tx, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllGroupsPaginated")
+	}
+
+	var r0 []models.Group
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int, int) ([]models.Group, int, error)); ok {
+		return rf(tx, page, pageSize)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int, int) []models.Group); ok {
+		r0 = rf(tx, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int, int) int); ok {
+		r1 = rf(tx, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*sql.Tx, int, int) error); ok {
+		r2 = rf(tx, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAllInitialAccessTokens provides a mock function with given fields: tx
+func (_m *Database) GetAllInitialAccessTokens(tx *sql.Tx) ([]models.InitialAccessToken, error) {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllInitialAccessTokens")
+	}
+
+	var r0 []models.InitialAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.InitialAccessToken, error)); ok {
+		return rf(tx)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.InitialAccessToken); ok {
+		r0 = rf(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.InitialAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
+		r1 = rf(tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllResources provides a mock function with given fields: tx
+func (_m *Database) GetAllResources(tx *sql.Tx) ([]models.Resource, error) {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllResources")
+	}
+
+	var r0 []models.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.Resource, error)); ok {
+		return rf(tx)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.Resource); ok {
+		r0 = rf(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
+		r1 = rf(tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllSigningKeys provides a mock function with given fields: tx
+func (_m *Database) GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error) {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllSigningKeys")
+	}
+
+	var r0 []models.KeyPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.KeyPair, error)); ok {
 		return rf(tx)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.Client); ok {
+	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.KeyPair); ok {
 		r0 = rf(tx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Client)
+			r0 = ret.Get(0).([]models.KeyPair)
 		}
 	}
 
@@ -1094,24 +1765,24 @@ func (_m *Database) GetAllClients(tx *sql.Tx) ([]models.Client, error) {
 	return r0, r1
 }
 
-// GetAllGroups provides a mock function with given fields: tx
-func (_m *Database) GetAllGroups(tx *sql.Tx) ([]models.Group, error) {
+// GetAllWebOrigins provides a mock function with given fields: tx
+func (_m *Database) GetAllWebOrigins(tx *sql.Tx) ([]models.WebOrigin, error) {
 	ret := _m.Called(tx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllGroups")
+		panic("no return value specified for GetAllWebOrigins")
 	}
 
-	var r0 []models.Group
+	var r0 []models.WebOrigin
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.Group, error)); ok {
+	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.WebOrigin, error)); ok {
 		return rf(tx)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.Group); ok {
+	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.WebOrigin); ok {
 		r0 = rf(tx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Group)
+			r0 = ret.Get(0).([]models.WebOrigin)
 		}
 	}
 
@@ -1124,66 +1795,59 @@ func (_m *Database) GetAllGroups(tx *sql.Tx) ([]models.Group, error) {
 	return r0, r1
 }
 
-// GetAllGroupsPaginated provides a mock function with given fields: tx, page, pageSize
-func (_m *Database) GetAllGroupsPaginated(tx *sql.Tx, page int, pageSize int) ([]models.Group, int, error) {
-	ret := _m.Called(tx, page, pageSize)
+// GetCIBARequestByAuthReqIdHash provides a mock function with given fields: tx, authReqIdHash
+func (_m *Database) GetCIBARequestByAuthReqIdHash(tx *sql.Tx, authReqIdHash string) (*models.CIBARequest, error) {
+	ret := _m.Called(tx, authReqIdHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllGroupsPaginated")
+		panic("no return value specified for GetCIBARequestByAuthReqIdHash")
 	}
 
-	var r0 []models.Group
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int, int) ([]models.Group, int, error)); ok {
-		return rf(tx, page, pageSize)
+	var r0 *models.CIBARequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.CIBARequest, error)); ok {
+		return rf(tx, authReqIdHash)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx, int, int) []models.Group); ok {
-		r0 = rf(tx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.CIBARequest); ok {
+		r0 = rf(tx, authReqIdHash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Group)
+			r0 = ret.Get(0).(*models.CIBARequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx, int, int) int); ok {
-		r1 = rf(tx, page, pageSize)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-
-	if rf, ok := ret.Get(2).(func(*sql.Tx, int, int) error); ok {
-		r2 = rf(tx, page, pageSize)
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, authReqIdHash)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetAllResources provides a mock function with given fields: tx
-func (_m *Database) GetAllResources(tx *sql.Tx) ([]models.Resource, error) {
-	ret := _m.Called(tx)
+// GetCIBARequestById provides a mock function with given fields: tx, cibaRequestId
+func (_m *Database) GetCIBARequestById(tx *sql.Tx, cibaRequestId int64) (*models.CIBARequest, error) {
+	ret := _m.Called(tx, cibaRequestId)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllResources")
+		panic("no return value specified for GetCIBARequestById")
 	}
 
-	var r0 []models.Resource
+	var r0 *models.CIBARequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.Resource, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) (*models.CIBARequest, error)); ok {
+		return rf(tx, cibaRequestId)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.Resource); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) *models.CIBARequest); ok {
+		r0 = rf(tx, cibaRequestId)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Resource)
+			r0 = ret.Get(0).(*models.CIBARequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, cibaRequestId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1191,29 +1855,29 @@ func (_m *Database) GetAllResources(tx *sql.Tx) ([]models.Resource, error) {
 	return r0, r1
 }
 
-// GetAllSigningKeys provides a mock function with given fields: tx
-func (_m *Database) GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error) {
-	ret := _m.Called(tx)
+// GetCIBARequestByVerificationCodeHash provides a mock function with given fields: tx, verificationCodeHash
+func (_m *Database) GetCIBARequestByVerificationCodeHash(tx *sql.Tx, verificationCodeHash string) (*models.CIBARequest, error) {
+	ret := _m.Called(tx, verificationCodeHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllSigningKeys")
+		panic("no return value specified for GetCIBARequestByVerificationCodeHash")
 	}
 
-	var r0 []models.KeyPair
+	var r0 *models.CIBARequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.KeyPair, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.CIBARequest, error)); ok {
+		return rf(tx, verificationCodeHash)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.KeyPair); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.CIBARequest); ok {
+		r0 = rf(tx, verificationCodeHash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.KeyPair)
+			r0 = ret.Get(0).(*models.CIBARequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, verificationCodeHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1221,29 +1885,29 @@ func (_m *Database) GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error) {
 	return r0, r1
 }
 
-// GetAllWebOrigins provides a mock function with given fields: tx
-func (_m *Database) GetAllWebOrigins(tx *sql.Tx) ([]models.WebOrigin, error) {
-	ret := _m.Called(tx)
+// GetClientAssertionJti provides a mock function with given fields: tx, clientId, jtiHash
+func (_m *Database) GetClientAssertionJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.ClientAssertionJti, error) {
+	ret := _m.Called(tx, clientId, jtiHash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllWebOrigins")
+		panic("no return value specified for GetClientAssertionJti")
 	}
 
-	var r0 []models.WebOrigin
+	var r0 *models.ClientAssertionJti
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) ([]models.WebOrigin, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) (*models.ClientAssertionJti, error)); ok {
+		return rf(tx, clientId, jtiHash)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) []models.WebOrigin); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) *models.ClientAssertionJti); ok {
+		r0 = rf(tx, clientId, jtiHash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.WebOrigin)
+			r0 = ret.Get(0).(*models.ClientAssertionJti)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string) error); ok {
+		r1 = rf(tx, clientId, jtiHash)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1581,6 +2245,126 @@ func (_m *Database) GetCurrentSigningKey(tx *sql.Tx) (*models.KeyPair, error) {
 	return r0, r1
 }
 
+// GetDPoPProofJti provides a mock function with given fields: tx, jtiHash
+func (_m *Database) GetDPoPProofJti(tx *sql.Tx, jtiHash string) (*models.DPoPProofJti, error) {
+	ret := _m.Called(tx, jtiHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDPoPProofJti")
+	}
+
+	var r0 *models.DPoPProofJti
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.DPoPProofJti, error)); ok {
+		return rf(tx, jtiHash)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.DPoPProofJti); ok {
+		r0 = rf(tx, jtiHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DPoPProofJti)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, jtiHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeviceCodeByDeviceCodeHash provides a mock function with given fields: tx, deviceCodeHash
+func (_m *Database) GetDeviceCodeByDeviceCodeHash(tx *sql.Tx, deviceCodeHash string) (*models.DeviceCode, error) {
+	ret := _m.Called(tx, deviceCodeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeviceCodeByDeviceCodeHash")
+	}
+
+	var r0 *models.DeviceCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.DeviceCode, error)); ok {
+		return rf(tx, deviceCodeHash)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.DeviceCode); ok {
+		r0 = rf(tx, deviceCodeHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeviceCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, deviceCodeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeviceCodeById provides a mock function with given fields: tx, deviceCodeId
+func (_m *Database) GetDeviceCodeById(tx *sql.Tx, deviceCodeId int64) (*models.DeviceCode, error) {
+	ret := _m.Called(tx, deviceCodeId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeviceCodeById")
+	}
+
+	var r0 *models.DeviceCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) (*models.DeviceCode, error)); ok {
+		return rf(tx, deviceCodeId)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) *models.DeviceCode); ok {
+		r0 = rf(tx, deviceCodeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeviceCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, deviceCodeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeviceCodeByUserCode provides a mock function with given fields: tx, userCode
+func (_m *Database) GetDeviceCodeByUserCode(tx *sql.Tx, userCode string) (*models.DeviceCode, error) {
+	ret := _m.Called(tx, userCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeviceCodeByUserCode")
+	}
+
+	var r0 *models.DeviceCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.DeviceCode, error)); ok {
+		return rf(tx, userCode)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.DeviceCode); ok {
+		r0 = rf(tx, userCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeviceCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, userCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGroupAttributeById provides a mock function with given fields: tx, groupAttributeId
 func (_m *Database) GetGroupAttributeById(tx *sql.Tx, groupAttributeId int64) (*models.GroupAttribute, error) {
 	ret := _m.Called(tx, groupAttributeId)
@@ -1948,6 +2732,66 @@ func (_m *Database) GetHttpSessionById(tx *sql.Tx, httpSessionId int64) (*models
 	return r0, r1
 }
 
+// GetInitialAccessTokenById provides a mock function with given fields: tx, initialAccessTokenId
+func (_m *Database) GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error) {
+	ret := _m.Called(tx, initialAccessTokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInitialAccessTokenById")
+	}
+
+	var r0 *models.InitialAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) (*models.InitialAccessToken, error)); ok {
+		return rf(tx, initialAccessTokenId)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) *models.InitialAccessToken); ok {
+		r0 = rf(tx, initialAccessTokenId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InitialAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, initialAccessTokenId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInitialAccessTokenByTokenHash provides a mock function with given fields: tx, tokenHash
+func (_m *Database) GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error) {
+	ret := _m.Called(tx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInitialAccessTokenByTokenHash")
+	}
+
+	var r0 *models.InitialAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.InitialAccessToken, error)); ok {
+		return rf(tx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.InitialAccessToken); ok {
+		r0 = rf(tx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InitialAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetKeyPairById provides a mock function with given fields: tx, keyPairId
 func (_m *Database) GetKeyPairById(tx *sql.Tx, keyPairId int64) (*models.KeyPair, error) {
 	ret := _m.Called(tx, keyPairId)
@@ -1995,12 +2839,102 @@ func (_m *Database) GetLastUserWithOTPState(tx *sql.Tx, otpEnabledState bool) (*
 		r0 = rf(tx, otpEnabledState)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, bool) error); ok {
+		r1 = rf(tx, otpEnabledState)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpaqueAccessTokenByTokenHash provides a mock function with given fields: tx, tokenHash
+func (_m *Database) GetOpaqueAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.OpaqueAccessToken, error) {
+	ret := _m.Called(tx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpaqueAccessTokenByTokenHash")
+	}
+
+	var r0 *models.OpaqueAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.OpaqueAccessToken, error)); ok {
+		return rf(tx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.OpaqueAccessToken); ok {
+		r0 = rf(tx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OpaqueAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPairwiseSubjectBySectorIdentifierAndSubject provides a mock function with given fields: tx, sectorIdentifier, subject
+func (_m *Database) GetPairwiseSubjectBySectorIdentifierAndSubject(tx *sql.Tx, sectorIdentifier string, subject string) (*models.PairwiseSubject, error) {
+	ret := _m.Called(tx, sectorIdentifier, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPairwiseSubjectBySectorIdentifierAndSubject")
+	}
+
+	var r0 *models.PairwiseSubject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string, string) (*models.PairwiseSubject, error)); ok {
+		return rf(tx, sectorIdentifier, subject)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string, string) *models.PairwiseSubject); ok {
+		r0 = rf(tx, sectorIdentifier, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PairwiseSubject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string, string) error); ok {
+		r1 = rf(tx, sectorIdentifier, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPairwiseSubjectByUserIdAndSectorIdentifier provides a mock function with given fields: tx, userId, sectorIdentifier
+func (_m *Database) GetPairwiseSubjectByUserIdAndSectorIdentifier(tx *sql.Tx, userId int64, sectorIdentifier string) (*models.PairwiseSubject, error) {
+	ret := _m.Called(tx, userId, sectorIdentifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPairwiseSubjectByUserIdAndSectorIdentifier")
+	}
+
+	var r0 *models.PairwiseSubject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) (*models.PairwiseSubject, error)); ok {
+		return rf(tx, userId, sectorIdentifier)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) *models.PairwiseSubject); ok {
+		r0 = rf(tx, userId, sectorIdentifier)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PairwiseSubject)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx, bool) error); ok {
-		r1 = rf(tx, otpEnabledState)
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string) error); ok {
+		r1 = rf(tx, userId, sectorIdentifier)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2098,6 +3032,66 @@ func (_m *Database) GetPermissionsByResourceId(tx *sql.Tx, resourceId int64) ([]
 	return r0, r1
 }
 
+// GetPostLogoutRedirectURIById provides a mock function with given fields: tx, postLogoutRedirectURIId
+func (_m *Database) GetPostLogoutRedirectURIById(tx *sql.Tx, postLogoutRedirectURIId int64) (*models.PostLogoutRedirectURI, error) {
+	ret := _m.Called(tx, postLogoutRedirectURIId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostLogoutRedirectURIById")
+	}
+
+	var r0 *models.PostLogoutRedirectURI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) (*models.PostLogoutRedirectURI, error)); ok {
+		return rf(tx, postLogoutRedirectURIId)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) *models.PostLogoutRedirectURI); ok {
+		r0 = rf(tx, postLogoutRedirectURIId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PostLogoutRedirectURI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, postLogoutRedirectURIId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPostLogoutRedirectURIsByClientId provides a mock function with given fields: tx, clientId
+func (_m *Database) GetPostLogoutRedirectURIsByClientId(tx *sql.Tx, clientId int64) ([]models.PostLogoutRedirectURI, error) {
+	ret := _m.Called(tx, clientId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostLogoutRedirectURIsByClientId")
+	}
+
+	var r0 []models.PostLogoutRedirectURI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) ([]models.PostLogoutRedirectURI, error)); ok {
+		return rf(tx, clientId)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) []models.PostLogoutRedirectURI); ok {
+		r0 = rf(tx, clientId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PostLogoutRedirectURI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, clientId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPreRegistrationByEmail provides a mock function with given fields: tx, email
 func (_m *Database) GetPreRegistrationByEmail(tx *sql.Tx, email string) (*models.PreRegistration, error) {
 	ret := _m.Called(tx, email)
@@ -2158,6 +3152,36 @@ func (_m *Database) GetPreRegistrationById(tx *sql.Tx, preRegistrationId int64)
 	return r0, r1
 }
 
+// GetPushedAuthorizationRequestByRequestURIHash provides a mock function with given fields: tx, requestURIHash
+func (_m *Database) GetPushedAuthorizationRequestByRequestURIHash(tx *sql.Tx, requestURIHash string) (*models.PushedAuthorizationRequest, error) {
+	ret := _m.Called(tx, requestURIHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPushedAuthorizationRequestByRequestURIHash")
+	}
+
+	var r0 *models.PushedAuthorizationRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.PushedAuthorizationRequest, error)); ok {
+		return rf(tx, requestURIHash)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.PushedAuthorizationRequest); ok {
+		r0 = rf(tx, requestURIHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PushedAuthorizationRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, requestURIHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRedirectURIById provides a mock function with given fields: tx, redirectURIId
 func (_m *Database) GetRedirectURIById(tx *sql.Tx, redirectURIId int64) (*models.RedirectURI, error) {
 	ret := _m.Called(tx, redirectURIId)
@@ -2278,6 +3302,36 @@ func (_m *Database) GetRefreshTokenByJti(tx *sql.Tx, jti string) (*models.Refres
 	return r0, r1
 }
 
+// GetRequestObjectJti provides a mock function with given fields: tx, clientId, jtiHash
+func (_m *Database) GetRequestObjectJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.RequestObjectJti, error) {
+	ret := _m.Called(tx, clientId, jtiHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequestObjectJti")
+	}
+
+	var r0 *models.RequestObjectJti
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) (*models.RequestObjectJti, error)); ok {
+		return rf(tx, clientId, jtiHash)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string) *models.RequestObjectJti); ok {
+		r0 = rf(tx, clientId, jtiHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RequestObjectJti)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string) error); ok {
+		r1 = rf(tx, clientId, jtiHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetResourceById provides a mock function with given fields: tx, resourceId
 func (_m *Database) GetResourceById(tx *sql.Tx, resourceId int64) (*models.Resource, error) {
 	ret := _m.Called(tx, resourceId)
@@ -2368,6 +3422,36 @@ func (_m *Database) GetResourcesByIds(tx *sql.Tx, resourceIds []int64) ([]models
 	return r0, r1
 }
 
+// GetRevokedAccessTokenByJti provides a mock function with given fields: tx, jti
+func (_m *Database) GetRevokedAccessTokenByJti(tx *sql.Tx, jti string) (*models.RevokedAccessToken, error) {
+	ret := _m.Called(tx, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevokedAccessTokenByJti")
+	}
+
+	var r0 *models.RevokedAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) (*models.RevokedAccessToken, error)); ok {
+		return rf(tx, jti)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) *models.RevokedAccessToken); ok {
+		r0 = rf(tx, jti)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RevokedAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, string) error); ok {
+		r1 = rf(tx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSettingsById provides a mock function with given fields: tx, settingsId
 func (_m *Database) GetSettingsById(tx *sql.Tx, settingsId int64) (*models.Settings, error) {
 	ret := _m.Called(tx, settingsId)
@@ -3304,6 +4388,34 @@ func (_m *Database) IsEmpty() (bool, error) {
 	return r0, r1
 }
 
+// MarkCodeAsUsed provides a mock function with given fields: tx, codeId
+func (_m *Database) MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error) {
+	ret := _m.Called(tx, codeId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkCodeAsUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) (bool, error)); ok {
+		return rf(tx, codeId)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) bool); ok {
+		r0 = rf(tx, codeId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, codeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Migrate provides a mock function with given fields:
 func (_m *Database) Migrate() error {
 	ret := _m.Called()
@@ -3358,6 +4470,42 @@ func (_m *Database) RefreshTokenLoadCode(tx *sql.Tx, refreshToken *models.Refres
 	return r0
 }
 
+// RevokeRefreshTokensByFirstRefreshTokenJti provides a mock function with given fields: tx, firstRefreshTokenJti
+func (_m *Database) RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error {
+	ret := _m.Called(tx, firstRefreshTokenJti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokensByFirstRefreshTokenJti")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) error); ok {
+		r0 = rf(tx, firstRefreshTokenJti)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshTokensBySessionIdentifier provides a mock function with given fields: tx, sessionIdentifier
+func (_m *Database) RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error {
+	ret := _m.Called(tx, sessionIdentifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokensBySessionIdentifier")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, string) error); ok {
+		r0 = rf(tx, sessionIdentifier)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RollbackTransaction provides a mock function with given fields: tx
 func (_m *Database) RollbackTransaction(tx *sql.Tx) error {
 	ret := _m.Called(tx)
@@ -3413,6 +4561,80 @@ func (_m *Database) SearchUsersPaginated(tx *sql.Tx, query string, page int, pag
 	return r0, r1, r2
 }
 
+// SupersedeRefreshToken provides a mock function with given fields: tx, refreshTokenId
+func (_m *Database) SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error) {
+	ret := _m.Called(tx, refreshTokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SupersedeRefreshToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) (bool, error)); ok {
+		return rf(tx, refreshTokenId)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64) bool); ok {
+		r0 = rf(tx, refreshTokenId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64) error); ok {
+		r1 = rf(tx, refreshTokenId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCIBARequest provides a mock function with given fields: tx, cibaRequest
+func (_m *Database) UpdateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	ret := _m.Called(tx, cibaRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCIBARequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.CIBARequest) error); ok {
+		r0 = rf(tx, cibaRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCIBARequestStatus provides a mock function with given fields: tx, cibaRequestId, fromStatus, toStatus
+func (_m *Database) UpdateCIBARequestStatus(tx *sql.Tx, cibaRequestId int64, fromStatus string, toStatus string) (bool, error) {
+	ret := _m.Called(tx, cibaRequestId, fromStatus, toStatus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCIBARequestStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string, string) (bool, error)); ok {
+		return rf(tx, cibaRequestId, fromStatus, toStatus)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string, string) bool); ok {
+		r0 = rf(tx, cibaRequestId, fromStatus, toStatus)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string, string) error); ok {
+		r1 = rf(tx, cibaRequestId, fromStatus, toStatus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateClient provides a mock function with given fields: tx, client
 func (_m *Database) UpdateClient(tx *sql.Tx, client *models.Client) error {
 	ret := _m.Called(tx, client)
@@ -3467,6 +4689,52 @@ func (_m *Database) UpdateCode(tx *sql.Tx, code *models.Code) error {
 	return r0
 }
 
+// UpdateDeviceCode provides a mock function with given fields: tx, deviceCode
+func (_m *Database) UpdateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	ret := _m.Called(tx, deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeviceCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, *models.DeviceCode) error); ok {
+		r0 = rf(tx, deviceCode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDeviceCodeStatus provides a mock function with given fields: tx, deviceCodeId, fromStatus, toStatus
+func (_m *Database) UpdateDeviceCodeStatus(tx *sql.Tx, deviceCodeId int64, fromStatus string, toStatus string) (bool, error) {
+	ret := _m.Called(tx, deviceCodeId, fromStatus, toStatus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeviceCodeStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string, string) (bool, error)); ok {
+		return rf(tx, deviceCodeId, fromStatus, toStatus)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string, string) bool); ok {
+		r0 = rf(tx, deviceCodeId, fromStatus, toStatus)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string, string) error); ok {
+		r1 = rf(tx, deviceCodeId, fromStatus, toStatus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateGroup provides a mock function with given fields: tx, group
 func (_m *Database) UpdateGroup(tx *sql.Tx, group *models.Group) error {
 	ret := _m.Called(tx, group)
@@ -3557,6 +4825,34 @@ func (_m *Database) UpdateKeyPair(tx *sql.Tx, keyPair *models.KeyPair) error {
 	return r0
 }
 
+// UpdateKeyPairState provides a mock function with given fields: tx, keyPairId, fromState, toState
+func (_m *Database) UpdateKeyPairState(tx *sql.Tx, keyPairId int64, fromState string, toState string) (bool, error) {
+	ret := _m.Called(tx, keyPairId, fromState, toState)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateKeyPairState")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string, string) (bool, error)); ok {
+		return rf(tx, keyPairId, fromState, toState)
+	}
+	if rf, ok := ret.Get(0).(func(*sql.Tx, int64, string, string) bool); ok {
+		r0 = rf(tx, keyPairId, fromState, toState)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*sql.Tx, int64, string, string) error); ok {
+		r1 = rf(tx, keyPairId, fromState, toState)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdatePermission provides a mock function with given fields: tx, permission
 func (_m *Database) UpdatePermission(tx *sql.Tx, permission *models.Permission) error {
 	ret := _m.Called(tx, permission)
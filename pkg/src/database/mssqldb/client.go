@@ -68,6 +68,10 @@ func (d *MsSQLDB) ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) erro
 	return d.CommonDB.ClientLoadRedirectURIs(tx, client)
 }
 
+func (d *MsSQLDB) ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) error {
+	return d.CommonDB.ClientLoadPostLogoutRedirectURIs(tx, client)
+}
+
 func (d *MsSQLDB) ClientLoadWebOrigins(tx *sql.Tx, client *models.Client) error {
 	return d.CommonDB.ClientLoadWebOrigins(tx, client)
 }
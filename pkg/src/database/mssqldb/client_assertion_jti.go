@@ -0,0 +1,53 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreateClientAssertionJti(tx *sql.Tx, clientAssertionJti *models.ClientAssertionJti) error {
+	now := time.Now().UTC()
+	originalCreatedAt := clientAssertionJti.CreatedAt
+	originalUpdatedAt := clientAssertionJti.UpdatedAt
+	clientAssertionJti.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	clientAssertionJti.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	clientAssertionJtiStruct := sqlbuilder.NewStruct(new(models.ClientAssertionJti)).For(sqlbuilder.SQLServer)
+	insertBuilder := clientAssertionJtiStruct.WithoutTag("pk").InsertInto("client_assertion_jtis", clientAssertionJti)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		clientAssertionJti.CreatedAt = originalCreatedAt
+		clientAssertionJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert clientAssertionJti")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&clientAssertionJti.Id); err != nil {
+			clientAssertionJti.CreatedAt = originalCreatedAt
+			clientAssertionJti.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan clientAssertionJti id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) GetClientAssertionJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.ClientAssertionJti, error) {
+	return d.CommonDB.GetClientAssertionJti(tx, clientId, jtiHash)
+}
+
+func (d *MsSQLDB) DeleteExpiredClientAssertionJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredClientAssertionJtis(tx)
+}
@@ -56,6 +56,10 @@ func (d *MsSQLDB) UpdateCode(tx *sql.Tx, code *models.Code) error {
 	return d.CommonDB.UpdateCode(tx, code)
 }
 
+func (d *MsSQLDB) MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error) {
+	return d.CommonDB.MarkCodeAsUsed(tx, codeId)
+}
+
 func (d *MsSQLDB) GetCodeById(tx *sql.Tx, codeId int64) (*models.Code, error) {
 	return d.CommonDB.GetCodeById(tx, codeId)
 }
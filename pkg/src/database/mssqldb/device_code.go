@@ -0,0 +1,77 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	if deviceCode.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := deviceCode.CreatedAt
+	originalUpdatedAt := deviceCode.UpdatedAt
+	deviceCode.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	deviceCode.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	deviceCodeStruct := sqlbuilder.NewStruct(new(models.DeviceCode)).For(sqlbuilder.SQLServer)
+	insertBuilder := deviceCodeStruct.WithoutTag("pk").InsertInto("device_codes", deviceCode)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		deviceCode.CreatedAt = originalCreatedAt
+		deviceCode.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert deviceCode")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&deviceCode.Id); err != nil {
+			deviceCode.CreatedAt = originalCreatedAt
+			deviceCode.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan deviceCode id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) UpdateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	return d.CommonDB.UpdateDeviceCode(tx, deviceCode)
+}
+
+func (d *MsSQLDB) UpdateDeviceCodeStatus(tx *sql.Tx, deviceCodeId int64, fromStatus string, toStatus string) (bool, error) {
+	return d.CommonDB.UpdateDeviceCodeStatus(tx, deviceCodeId, fromStatus, toStatus)
+}
+
+func (d *MsSQLDB) GetDeviceCodeById(tx *sql.Tx, deviceCodeId int64) (*models.DeviceCode, error) {
+	return d.CommonDB.GetDeviceCodeById(tx, deviceCodeId)
+}
+
+func (d *MsSQLDB) GetDeviceCodeByDeviceCodeHash(tx *sql.Tx, deviceCodeHash string) (*models.DeviceCode, error) {
+	return d.CommonDB.GetDeviceCodeByDeviceCodeHash(tx, deviceCodeHash)
+}
+
+func (d *MsSQLDB) GetDeviceCodeByUserCode(tx *sql.Tx, userCode string) (*models.DeviceCode, error) {
+	return d.CommonDB.GetDeviceCodeByUserCode(tx, userCode)
+}
+
+func (d *MsSQLDB) DeviceCodeLoadClient(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	return d.CommonDB.DeviceCodeLoadClient(tx, deviceCode)
+}
+
+func (d *MsSQLDB) DeleteExpiredDeviceCodes(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredDeviceCodes(tx)
+}
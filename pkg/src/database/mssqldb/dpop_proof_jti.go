@@ -0,0 +1,53 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreateDPoPProofJti(tx *sql.Tx, dpopProofJti *models.DPoPProofJti) error {
+	now := time.Now().UTC()
+	originalCreatedAt := dpopProofJti.CreatedAt
+	originalUpdatedAt := dpopProofJti.UpdatedAt
+	dpopProofJti.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	dpopProofJti.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	dpopProofJtiStruct := sqlbuilder.NewStruct(new(models.DPoPProofJti)).For(sqlbuilder.SQLServer)
+	insertBuilder := dpopProofJtiStruct.WithoutTag("pk").InsertInto("dpop_proof_jtis", dpopProofJti)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		dpopProofJti.CreatedAt = originalCreatedAt
+		dpopProofJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert dpopProofJti")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&dpopProofJti.Id); err != nil {
+			dpopProofJti.CreatedAt = originalCreatedAt
+			dpopProofJti.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan dpopProofJti id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) GetDPoPProofJti(tx *sql.Tx, jtiHash string) (*models.DPoPProofJti, error) {
+	return d.CommonDB.GetDPoPProofJti(tx, jtiHash)
+}
+
+func (d *MsSQLDB) DeleteExpiredDPoPProofJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredDPoPProofJtis(tx)
+}
@@ -0,0 +1,65 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := initialAccessToken.CreatedAt
+	originalUpdatedAt := initialAccessToken.UpdatedAt
+	initialAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	initialAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(sqlbuilder.SQLServer)
+	insertBuilder := initialAccessTokenStruct.WithoutTag("pk").InsertInto("initial_access_tokens", initialAccessToken)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		initialAccessToken.CreatedAt = originalCreatedAt
+		initialAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert initialAccessToken")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&initialAccessToken.Id); err != nil {
+			initialAccessToken.CreatedAt = originalCreatedAt
+			initialAccessToken.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan initialAccessToken id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenById(tx, initialAccessTokenId)
+}
+
+func (d *MsSQLDB) GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *MsSQLDB) GetAllInitialAccessTokens(tx *sql.Tx) ([]models.InitialAccessToken, error) {
+	return d.CommonDB.GetAllInitialAccessTokens(tx)
+}
+
+func (d *MsSQLDB) DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error {
+	return d.CommonDB.DeleteInitialAccessToken(tx, initialAccessTokenId)
+}
+
+func (d *MsSQLDB) DeleteExpiredInitialAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredInitialAccessTokens(tx)
+}
@@ -52,6 +52,10 @@ func (d *MsSQLDB) GetKeyPairById(tx *sql.Tx, keyPairId int64) (*models.KeyPair,
 	return d.CommonDB.GetKeyPairById(tx, keyPairId)
 }
 
+func (d *MsSQLDB) UpdateKeyPairState(tx *sql.Tx, keyPairId int64, fromState string, toState string) (bool, error) {
+	return d.CommonDB.UpdateKeyPairState(tx, keyPairId, fromState, toState)
+}
+
 func (d *MsSQLDB) GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error) {
 	return d.CommonDB.GetAllSigningKeys(tx)
 }
@@ -63,3 +67,7 @@ func (d *MsSQLDB) GetCurrentSigningKey(tx *sql.Tx) (*models.KeyPair, error) {
 func (d *MsSQLDB) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
 	return d.CommonDB.DeleteKeyPair(tx, keyPairId)
 }
+
+func (d *MsSQLDB) DeleteKeyPairWithState(tx *sql.Tx, keyPairId int64, state string) (bool, error) {
+	return d.CommonDB.DeleteKeyPairWithState(tx, keyPairId, state)
+}
@@ -0,0 +1,4 @@
+-- 000003_revoked_access_tokens.down.sql
+
+DROP INDEX IF EXISTS [idx_refresh_tokens_first_refresh_token_jti] ON [dbo].[refresh_tokens];
+DROP TABLE IF EXISTS [dbo].[revoked_access_tokens];
@@ -0,0 +1,12 @@
+-- 000003_revoked_access_tokens.up.sql
+
+CREATE TABLE [dbo].[revoked_access_tokens] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [jti] NVARCHAR(64) NOT NULL,
+    [expires_at] datetime2(6)
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_revoked_access_tokens_jti] ON [dbo].[revoked_access_tokens] ([jti]);
+CREATE NONCLUSTERED INDEX [idx_refresh_tokens_first_refresh_token_jti] ON [dbo].[refresh_tokens] ([first_refresh_token_jti]);
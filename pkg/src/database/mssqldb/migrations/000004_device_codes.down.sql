@@ -0,0 +1,5 @@
+-- 000004_device_codes.down.sql
+
+DROP TABLE IF EXISTS [dbo].[device_codes];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_device_code_enabled];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [device_code_enabled];
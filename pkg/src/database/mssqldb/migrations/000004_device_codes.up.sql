@@ -0,0 +1,24 @@
+-- 000004_device_codes.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [device_code_enabled] BIT NOT NULL
+    CONSTRAINT [df_clients_device_code_enabled] DEFAULT 0;
+
+CREATE TABLE [dbo].[device_codes] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [device_code_hash] NVARCHAR(64) NOT NULL,
+    [user_code] NVARCHAR(16) NOT NULL,
+    [client_id] BIGINT NOT NULL,
+    [scope] NVARCHAR(512) NOT NULL,
+    [status] NVARCHAR(16) NOT NULL,
+    [expires_at] datetime2(6),
+    [polling_interval_seconds] INT NOT NULL,
+    [last_polled_at] datetime2(6),
+    [code_id] BIGINT,
+    CONSTRAINT [fk_device_codes_client] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_device_codes_device_code_hash] ON [dbo].[device_codes] ([device_code_hash]);
+CREATE UNIQUE NONCLUSTERED INDEX [idx_device_codes_user_code] ON [dbo].[device_codes] ([user_code]);
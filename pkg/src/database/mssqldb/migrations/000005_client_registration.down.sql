@@ -0,0 +1,9 @@
+-- 000005_client_registration.down.sql
+
+DROP TABLE IF EXISTS [dbo].[initial_access_tokens];
+ALTER TABLE [dbo].[settings] DROP CONSTRAINT IF EXISTS [df_settings_dynamic_client_registration_open];
+ALTER TABLE [dbo].[settings] DROP COLUMN IF EXISTS [dynamic_client_registration_open];
+ALTER TABLE [dbo].[settings] DROP CONSTRAINT IF EXISTS [df_settings_dynamic_client_registration_enabled];
+ALTER TABLE [dbo].[settings] DROP COLUMN IF EXISTS [dynamic_client_registration_enabled];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_registration_access_token_hash];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [registration_access_token_hash];
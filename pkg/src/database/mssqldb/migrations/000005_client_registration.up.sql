@@ -0,0 +1,21 @@
+-- 000005_client_registration.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [registration_access_token_hash] NVARCHAR(64) NOT NULL
+    CONSTRAINT [df_clients_registration_access_token_hash] DEFAULT '';
+
+ALTER TABLE [dbo].[settings] ADD [dynamic_client_registration_enabled] BIT NOT NULL
+    CONSTRAINT [df_settings_dynamic_client_registration_enabled] DEFAULT 0;
+
+ALTER TABLE [dbo].[settings] ADD [dynamic_client_registration_open] BIT NOT NULL
+    CONSTRAINT [df_settings_dynamic_client_registration_open] DEFAULT 0;
+
+CREATE TABLE [dbo].[initial_access_tokens] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [token_hash] NVARCHAR(64) NOT NULL,
+    [description] NVARCHAR(128) NOT NULL,
+    [expires_at] datetime2(6)
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_initial_access_tokens_token_hash] ON [dbo].[initial_access_tokens] ([token_hash]);
@@ -0,0 +1,5 @@
+-- 000006_pushed_authorization_requests.down.sql
+
+DROP TABLE IF EXISTS [dbo].[pushed_authorization_requests];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_par_required];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [par_required];
@@ -0,0 +1,18 @@
+-- 000006_pushed_authorization_requests.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [par_required] BIT NOT NULL
+    CONSTRAINT [df_clients_par_required] DEFAULT 0;
+
+CREATE TABLE [dbo].[pushed_authorization_requests] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [request_uri_hash] NVARCHAR(64) NOT NULL,
+    [client_id] BIGINT NOT NULL,
+    [parameters] NVARCHAR(MAX) NOT NULL,
+    [expires_at] datetime2(6),
+    CONSTRAINT [fk_pushed_authorization_requests_client] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_pushed_authorization_requests_request_uri_hash] ON [dbo].[pushed_authorization_requests] ([request_uri_hash]);
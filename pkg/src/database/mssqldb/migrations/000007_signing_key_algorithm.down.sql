@@ -0,0 +1,4 @@
+-- 000007_signing_key_algorithm.down.sql
+
+ALTER TABLE [dbo].[settings] DROP CONSTRAINT IF EXISTS [df_settings_signing_key_algorithm];
+ALTER TABLE [dbo].[settings] DROP COLUMN IF EXISTS [signing_key_algorithm];
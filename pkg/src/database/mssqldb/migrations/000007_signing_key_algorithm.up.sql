@@ -0,0 +1,4 @@
+-- 000007_signing_key_algorithm.up.sql
+
+ALTER TABLE [dbo].[settings] ADD [signing_key_algorithm] NVARCHAR(16) NOT NULL
+    CONSTRAINT [df_settings_signing_key_algorithm] DEFAULT 'RS256';
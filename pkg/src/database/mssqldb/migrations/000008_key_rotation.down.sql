@@ -0,0 +1,4 @@
+-- 000008_key_rotation.down.sql
+
+ALTER TABLE [dbo].[settings] DROP CONSTRAINT IF EXISTS [df_settings_key_rotation_interval_in_seconds];
+ALTER TABLE [dbo].[settings] DROP COLUMN IF EXISTS [key_rotation_interval_in_seconds];
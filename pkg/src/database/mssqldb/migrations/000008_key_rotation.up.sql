@@ -0,0 +1,4 @@
+-- 000008_key_rotation.up.sql
+
+ALTER TABLE [dbo].[settings] ADD [key_rotation_interval_in_seconds] INT NOT NULL
+    CONSTRAINT [df_settings_key_rotation_interval_in_seconds] DEFAULT 0;
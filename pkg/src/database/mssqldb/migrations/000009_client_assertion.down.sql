@@ -0,0 +1,8 @@
+-- 000009_client_assertion.down.sql
+
+DROP TABLE IF EXISTS [dbo].[client_assertion_jtis];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_jwks_uri];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [jwks_uri];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [jwks];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_token_endpoint_auth_method];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [token_endpoint_auth_method];
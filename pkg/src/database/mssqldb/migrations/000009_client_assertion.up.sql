@@ -0,0 +1,22 @@
+-- 000009_client_assertion.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [token_endpoint_auth_method] NVARCHAR(40) NOT NULL
+    CONSTRAINT [df_clients_token_endpoint_auth_method] DEFAULT '';
+
+ALTER TABLE [dbo].[clients] ADD [jwks] VARBINARY(MAX);
+
+ALTER TABLE [dbo].[clients] ADD [jwks_uri] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_jwks_uri] DEFAULT '';
+
+CREATE TABLE [dbo].[client_assertion_jtis] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [client_id] BIGINT NOT NULL,
+    [jti_hash] NVARCHAR(64) NOT NULL,
+    [expires_at] datetime2(6),
+    CONSTRAINT [fk_client_assertion_jtis_client] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_client_assertion_jtis_client_id_jti_hash] ON [dbo].[client_assertion_jtis] ([client_id], [jti_hash]);
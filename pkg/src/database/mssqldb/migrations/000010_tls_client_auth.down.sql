@@ -0,0 +1,4 @@
+-- 000010_tls_client_auth.down.sql
+
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_tls_client_auth_subject_dn];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [tls_client_auth_subject_dn];
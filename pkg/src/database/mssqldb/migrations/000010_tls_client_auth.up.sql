@@ -0,0 +1,4 @@
+-- 000010_tls_client_auth.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [tls_client_auth_subject_dn] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_tls_client_auth_subject_dn] DEFAULT '';
@@ -0,0 +1,5 @@
+-- 000011_dpop.down.sql
+
+DROP TABLE IF EXISTS [dbo].[dpop_proof_jtis];
+ALTER TABLE [dbo].[refresh_tokens] DROP CONSTRAINT IF EXISTS [df_refresh_tokens_dpop_jkt];
+ALTER TABLE [dbo].[refresh_tokens] DROP COLUMN IF EXISTS [dpop_jkt];
@@ -0,0 +1,14 @@
+-- 000011_dpop.up.sql
+
+ALTER TABLE [dbo].[refresh_tokens] ADD [dpop_jkt] NVARCHAR(64) NOT NULL
+    CONSTRAINT [df_refresh_tokens_dpop_jkt] DEFAULT '';
+
+CREATE TABLE [dbo].[dpop_proof_jtis] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [jti_hash] NVARCHAR(64) NOT NULL,
+    [expires_at] datetime2(6)
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_dpop_proof_jtis_jti_hash] ON [dbo].[dpop_proof_jtis] ([jti_hash]);
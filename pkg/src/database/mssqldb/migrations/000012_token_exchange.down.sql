@@ -0,0 +1,6 @@
+-- 000012_token_exchange.down.sql
+
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_token_exchange_impersonation_enabled];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [token_exchange_impersonation_enabled];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_token_exchange_enabled];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [token_exchange_enabled];
@@ -0,0 +1,6 @@
+-- 000012_token_exchange.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [token_exchange_enabled] BIT NOT NULL
+    CONSTRAINT [df_clients_token_exchange_enabled] DEFAULT 0;
+ALTER TABLE [dbo].[clients] ADD [token_exchange_impersonation_enabled] BIT NOT NULL
+    CONSTRAINT [df_clients_token_exchange_impersonation_enabled] DEFAULT 0;
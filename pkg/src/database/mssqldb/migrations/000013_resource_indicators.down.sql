@@ -0,0 +1,4 @@
+-- 000013_resource_indicators.down.sql
+
+ALTER TABLE [dbo].[codes] DROP CONSTRAINT IF EXISTS [df_codes_resource];
+ALTER TABLE [dbo].[codes] DROP COLUMN IF EXISTS [resource];
@@ -0,0 +1,4 @@
+-- 000013_resource_indicators.up.sql
+
+ALTER TABLE [dbo].[codes] ADD [resource] NVARCHAR(40) NOT NULL
+    CONSTRAINT [df_codes_resource] DEFAULT '';
@@ -0,0 +1,4 @@
+-- 000014_request_objects.down.sql
+
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_require_signed_request_object];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [require_signed_request_object];
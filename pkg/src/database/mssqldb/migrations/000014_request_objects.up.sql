@@ -0,0 +1,4 @@
+-- 000014_request_objects.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [require_signed_request_object] BIT NOT NULL
+    CONSTRAINT [df_clients_require_signed_request_object] DEFAULT 0;
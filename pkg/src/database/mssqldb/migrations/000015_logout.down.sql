@@ -0,0 +1,7 @@
+-- 000015_logout.down.sql
+
+DROP TABLE IF EXISTS [dbo].[post_logout_redirect_uris];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_backchannel_logout_uri];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [backchannel_logout_uri];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_frontchannel_logout_uri];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [frontchannel_logout_uri];
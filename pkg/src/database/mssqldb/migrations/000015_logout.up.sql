@@ -0,0 +1,15 @@
+-- 000015_logout.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [frontchannel_logout_uri] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_frontchannel_logout_uri] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [backchannel_logout_uri] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_backchannel_logout_uri] DEFAULT '';
+
+CREATE TABLE [dbo].[post_logout_redirect_uris] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [uri] NVARCHAR(256) NOT NULL,
+    [client_id] BIGINT NOT NULL,
+    CONSTRAINT [fk_clients_post_logout_redirect_uris] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE
+);
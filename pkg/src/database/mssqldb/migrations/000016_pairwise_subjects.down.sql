@@ -0,0 +1,9 @@
+-- 000016_pairwise_subjects.down.sql
+
+DROP TABLE IF EXISTS [dbo].[pairwise_subjects];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_sector_identifier];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [sector_identifier];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_sector_identifier_uri];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [sector_identifier_uri];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_subject_type];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [subject_type];
@@ -0,0 +1,21 @@
+-- 000016_pairwise_subjects.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [subject_type] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_subject_type] DEFAULT 'public';
+ALTER TABLE [dbo].[clients] ADD [sector_identifier_uri] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_sector_identifier_uri] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [sector_identifier] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_sector_identifier] DEFAULT '';
+
+CREATE TABLE [dbo].[pairwise_subjects] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [user_id] BIGINT NOT NULL,
+    [sector_identifier] NVARCHAR(256) NOT NULL,
+    [subject] NVARCHAR(64) NOT NULL,
+    CONSTRAINT [fk_pairwise_subjects_user] FOREIGN KEY ([user_id])
+        REFERENCES [dbo].[users] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_pairwise_subjects_user_id_sector_identifier] ON [dbo].[pairwise_subjects] ([user_id], [sector_identifier]);
+CREATE UNIQUE NONCLUSTERED INDEX [idx_pairwise_subjects_sector_identifier_subject] ON [dbo].[pairwise_subjects] ([sector_identifier], [subject]);
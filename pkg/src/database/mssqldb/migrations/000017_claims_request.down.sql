@@ -0,0 +1,4 @@
+-- 000017_claims_request.down.sql
+
+ALTER TABLE [dbo].[codes] DROP CONSTRAINT IF EXISTS [df_codes_claims];
+ALTER TABLE [dbo].[codes] DROP COLUMN IF EXISTS [claims];
@@ -0,0 +1,4 @@
+-- 000017_claims_request.up.sql
+
+ALTER TABLE [dbo].[codes] ADD [claims] NVARCHAR(MAX) NOT NULL
+    CONSTRAINT [df_codes_claims] DEFAULT '';
@@ -0,0 +1,10 @@
+-- 000018_encrypted_responses.down.sql
+
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_userinfo_encrypted_response_enc];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [userinfo_encrypted_response_enc];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_userinfo_encrypted_response_alg];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [userinfo_encrypted_response_alg];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_id_token_encrypted_response_enc];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [id_token_encrypted_response_enc];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_id_token_encrypted_response_alg];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [id_token_encrypted_response_alg];
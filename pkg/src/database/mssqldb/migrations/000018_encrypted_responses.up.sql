@@ -0,0 +1,10 @@
+-- 000018_encrypted_responses.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [id_token_encrypted_response_alg] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_id_token_encrypted_response_alg] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [id_token_encrypted_response_enc] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_id_token_encrypted_response_enc] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [userinfo_encrypted_response_alg] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_userinfo_encrypted_response_alg] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [userinfo_encrypted_response_enc] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_userinfo_encrypted_response_enc] DEFAULT '';
@@ -0,0 +1,9 @@
+-- 000019_ciba.down.sql
+
+DROP TABLE IF EXISTS [dbo].[ciba_requests];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_backchannel_client_notification_endpoint];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [backchannel_client_notification_endpoint];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_backchannel_token_delivery_mode];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [backchannel_token_delivery_mode];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_ciba_enabled];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [ciba_enabled];
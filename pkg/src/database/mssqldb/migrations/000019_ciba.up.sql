@@ -0,0 +1,36 @@
+-- 000019_ciba.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [ciba_enabled] BIT NOT NULL
+    CONSTRAINT [df_clients_ciba_enabled] DEFAULT 0;
+ALTER TABLE [dbo].[clients] ADD [backchannel_token_delivery_mode] NVARCHAR(10) NOT NULL
+    CONSTRAINT [df_clients_backchannel_token_delivery_mode] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [backchannel_client_notification_endpoint] NVARCHAR(256) NOT NULL
+    CONSTRAINT [df_clients_backchannel_client_notification_endpoint] DEFAULT '';
+
+CREATE TABLE [dbo].[ciba_requests] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [auth_req_id_hash] NVARCHAR(64) NOT NULL,
+    [auth_req_id_encrypted] VARBINARY(MAX),
+    [verification_code_hash] NVARCHAR(64) NOT NULL,
+    [client_id] BIGINT NOT NULL,
+    [user_id] BIGINT NOT NULL,
+    [scope] NVARCHAR(512) NOT NULL,
+    [acr_values] NVARCHAR(128) NOT NULL,
+    [binding_message] NVARCHAR(128) NOT NULL,
+    [token_delivery_mode] NVARCHAR(10) NOT NULL,
+    [client_notification_token_encrypted] VARBINARY(MAX),
+    [status] NVARCHAR(16) NOT NULL,
+    [expires_at] datetime2(6),
+    [polling_interval_seconds] INT NOT NULL,
+    [last_polled_at] datetime2(6),
+    [code_id] BIGINT,
+    CONSTRAINT [fk_ciba_requests_client] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE,
+    CONSTRAINT [fk_ciba_requests_user] FOREIGN KEY ([user_id])
+        REFERENCES [dbo].[users] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_ciba_requests_auth_req_id_hash] ON [dbo].[ciba_requests] ([auth_req_id_hash]);
+CREATE UNIQUE NONCLUSTERED INDEX [idx_ciba_requests_verification_code_hash] ON [dbo].[ciba_requests] ([verification_code_hash]);
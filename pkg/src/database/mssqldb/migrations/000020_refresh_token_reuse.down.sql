@@ -0,0 +1,6 @@
+-- 000020_refresh_token_reuse.down.sql
+
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [refresh_token_reuse_grace_period_in_seconds];
+ALTER TABLE [dbo].[settings] DROP CONSTRAINT IF EXISTS [df_settings_refresh_token_reuse_grace_period_in_seconds];
+ALTER TABLE [dbo].[settings] DROP COLUMN IF EXISTS [refresh_token_reuse_grace_period_in_seconds];
+ALTER TABLE [dbo].[refresh_tokens] DROP COLUMN IF EXISTS [superseded_at];
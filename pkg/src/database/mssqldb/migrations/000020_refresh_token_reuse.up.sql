@@ -0,0 +1,6 @@
+-- 000020_refresh_token_reuse.up.sql
+
+ALTER TABLE [dbo].[refresh_tokens] ADD [superseded_at] datetime2(6);
+ALTER TABLE [dbo].[settings] ADD [refresh_token_reuse_grace_period_in_seconds] INT NOT NULL
+    CONSTRAINT [df_settings_refresh_token_reuse_grace_period_in_seconds] DEFAULT 0;
+ALTER TABLE [dbo].[clients] ADD [refresh_token_reuse_grace_period_in_seconds] INT NULL;
@@ -0,0 +1,5 @@
+-- 000021_opaque_access_tokens.down.sql
+
+DROP TABLE IF EXISTS [dbo].[opaque_access_tokens];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_access_token_format];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [access_token_format];
@@ -0,0 +1,20 @@
+-- 000021_opaque_access_tokens.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [access_token_format] NVARCHAR(10) NOT NULL
+    CONSTRAINT [df_clients_access_token_format] DEFAULT '';
+
+CREATE TABLE [dbo].[opaque_access_tokens] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [token_hash] NVARCHAR(64) NOT NULL,
+    [jti] NVARCHAR(64) NOT NULL,
+    [client_id] BIGINT NOT NULL,
+    [claims] NVARCHAR(MAX) NOT NULL,
+    [expires_at] datetime2(6),
+    CONSTRAINT [fk_opaque_access_tokens_client] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_opaque_access_tokens_token_hash] ON [dbo].[opaque_access_tokens] ([token_hash]);
+CREATE NONCLUSTERED INDEX [idx_opaque_access_tokens_expires_at] ON [dbo].[opaque_access_tokens] ([expires_at]);
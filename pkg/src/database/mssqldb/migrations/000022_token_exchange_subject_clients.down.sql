@@ -0,0 +1,4 @@
+-- 000022_token_exchange_subject_clients.down.sql
+
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_token_exchange_subject_clients];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [token_exchange_subject_clients];
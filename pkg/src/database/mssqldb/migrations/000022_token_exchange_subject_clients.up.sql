@@ -0,0 +1,4 @@
+-- 000022_token_exchange_subject_clients.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [token_exchange_subject_clients] NVARCHAR(1024) NOT NULL
+    CONSTRAINT [df_clients_token_exchange_subject_clients] DEFAULT '';
@@ -0,0 +1,6 @@
+-- 000023_authorization_encrypted_responses.down.sql
+
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_authorization_encrypted_response_enc];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [authorization_encrypted_response_enc];
+ALTER TABLE [dbo].[clients] DROP CONSTRAINT IF EXISTS [df_clients_authorization_encrypted_response_alg];
+ALTER TABLE [dbo].[clients] DROP COLUMN IF EXISTS [authorization_encrypted_response_alg];
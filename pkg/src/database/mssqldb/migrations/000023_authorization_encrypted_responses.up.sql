@@ -0,0 +1,6 @@
+-- 000023_authorization_encrypted_responses.up.sql
+
+ALTER TABLE [dbo].[clients] ADD [authorization_encrypted_response_alg] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_authorization_encrypted_response_alg] DEFAULT '';
+ALTER TABLE [dbo].[clients] ADD [authorization_encrypted_response_enc] NVARCHAR(20) NOT NULL
+    CONSTRAINT [df_clients_authorization_encrypted_response_enc] DEFAULT '';
@@ -0,0 +1,14 @@
+-- 000024_request_object_jtis.up.sql
+
+CREATE TABLE [dbo].[request_object_jtis] (
+    [id] BIGINT IDENTITY(1,1) PRIMARY KEY,
+    [created_at] datetime2(6),
+    [updated_at] datetime2(6),
+    [client_id] BIGINT NOT NULL,
+    [jti_hash] NVARCHAR(64) NOT NULL,
+    [expires_at] datetime2(6),
+    CONSTRAINT [fk_request_object_jtis_client] FOREIGN KEY ([client_id])
+        REFERENCES [dbo].[clients] ([id]) ON DELETE CASCADE
+);
+
+CREATE UNIQUE NONCLUSTERED INDEX [idx_request_object_jtis_client_id_jti_hash] ON [dbo].[request_object_jtis] ([client_id], [jti_hash]);
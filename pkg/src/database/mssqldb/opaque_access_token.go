@@ -0,0 +1,53 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreateOpaqueAccessToken(tx *sql.Tx, opaqueAccessToken *models.OpaqueAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := opaqueAccessToken.CreatedAt
+	originalUpdatedAt := opaqueAccessToken.UpdatedAt
+	opaqueAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	opaqueAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	opaqueAccessTokenStruct := sqlbuilder.NewStruct(new(models.OpaqueAccessToken)).For(sqlbuilder.SQLServer)
+	insertBuilder := opaqueAccessTokenStruct.WithoutTag("pk").InsertInto("opaque_access_tokens", opaqueAccessToken)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		opaqueAccessToken.CreatedAt = originalCreatedAt
+		opaqueAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert opaqueAccessToken")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&opaqueAccessToken.Id); err != nil {
+			opaqueAccessToken.CreatedAt = originalCreatedAt
+			opaqueAccessToken.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan opaqueAccessToken id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) GetOpaqueAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.OpaqueAccessToken, error) {
+	return d.CommonDB.GetOpaqueAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *MsSQLDB) DeleteExpiredOpaqueAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredOpaqueAccessTokens(tx)
+}
@@ -0,0 +1,57 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreatePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURI *models.PostLogoutRedirectURI) error {
+	if postLogoutRedirectURI.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := postLogoutRedirectURI.CreatedAt
+	postLogoutRedirectURI.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	postLogoutRedirectURIStruct := sqlbuilder.NewStruct(new(models.PostLogoutRedirectURI)).For(sqlbuilder.SQLServer)
+	insertBuilder := postLogoutRedirectURIStruct.WithoutTag("pk").InsertInto("post_logout_redirect_uris", postLogoutRedirectURI)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		postLogoutRedirectURI.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to insert postLogoutRedirectURI")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&postLogoutRedirectURI.Id); err != nil {
+			postLogoutRedirectURI.CreatedAt = originalCreatedAt
+			return errors.Wrap(err, "unable to scan postLogoutRedirectURI id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) GetPostLogoutRedirectURIById(tx *sql.Tx, postLogoutRedirectURIId int64) (*models.PostLogoutRedirectURI, error) {
+	return d.CommonDB.GetPostLogoutRedirectURIById(tx, postLogoutRedirectURIId)
+}
+
+func (d *MsSQLDB) GetPostLogoutRedirectURIsByClientId(tx *sql.Tx, clientId int64) ([]models.PostLogoutRedirectURI, error) {
+	return d.CommonDB.GetPostLogoutRedirectURIsByClientId(tx, clientId)
+}
+
+func (d *MsSQLDB) DeletePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURIId int64) error {
+	return d.CommonDB.DeletePostLogoutRedirectURI(tx, postLogoutRedirectURIId)
+}
@@ -0,0 +1,57 @@
+package mssqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *MsSQLDB) CreatePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequest *models.PushedAuthorizationRequest) error {
+	now := time.Now().UTC()
+	originalCreatedAt := pushedAuthorizationRequest.CreatedAt
+	originalUpdatedAt := pushedAuthorizationRequest.UpdatedAt
+	pushedAuthorizationRequest.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	pushedAuthorizationRequest.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	pushedAuthorizationRequestStruct := sqlbuilder.NewStruct(new(models.PushedAuthorizationRequest)).For(sqlbuilder.SQLServer)
+	insertBuilder := pushedAuthorizationRequestStruct.WithoutTag("pk").InsertInto("pushed_authorization_requests", pushedAuthorizationRequest)
+	sql, args := insertBuilder.Build()
+	parts := strings.SplitN(sql, "VALUES", 2)
+	if len(parts) != 2 {
+		return errors.New("unexpected SQL format from sqlbuilder")
+	}
+
+	sql = parts[0] + "OUTPUT INSERTED.id VALUES" + parts[1]
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		pushedAuthorizationRequest.CreatedAt = originalCreatedAt
+		pushedAuthorizationRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert pushedAuthorizationRequest")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&pushedAuthorizationRequest.Id); err != nil {
+			pushedAuthorizationRequest.CreatedAt = originalCreatedAt
+			pushedAuthorizationRequest.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan pushedAuthorizationRequest id")
+		}
+	}
+
+	return nil
+}
+
+func (d *MsSQLDB) GetPushedAuthorizationRequestByRequestURIHash(tx *sql.Tx, requestURIHash string) (*models.PushedAuthorizationRequest, error) {
+	return d.CommonDB.GetPushedAuthorizationRequestByRequestURIHash(tx, requestURIHash)
+}
+
+func (d *MsSQLDB) DeletePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequestId int64) error {
+	return d.CommonDB.DeletePushedAuthorizationRequest(tx, pushedAuthorizationRequestId)
+}
+
+func (d *MsSQLDB) DeleteExpiredPushedAuthorizationRequests(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredPushedAuthorizationRequests(tx)
+}
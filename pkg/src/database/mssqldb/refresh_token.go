@@ -67,3 +67,15 @@ func (d *MsSQLDB) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 func (d *MsSQLDB) RefreshTokenLoadCode(tx *sql.Tx, refreshToken *models.RefreshToken) error {
 	return d.CommonDB.RefreshTokenLoadCode(tx, refreshToken)
 }
+
+func (d *MsSQLDB) SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error) {
+	return d.CommonDB.SupersedeRefreshToken(tx, refreshTokenId)
+}
+
+func (d *MsSQLDB) RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error {
+	return d.CommonDB.RevokeRefreshTokensByFirstRefreshTokenJti(tx, firstRefreshTokenJti)
+}
+
+func (d *MsSQLDB) RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error {
+	return d.CommonDB.RevokeRefreshTokensBySessionIdentifier(tx, sessionIdentifier)
+}
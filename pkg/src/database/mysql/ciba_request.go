@@ -0,0 +1,43 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CreateCIBARequest(tx, cibaRequest)
+}
+
+func (d *MySQLDB) UpdateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.UpdateCIBARequest(tx, cibaRequest)
+}
+
+func (d *MySQLDB) UpdateCIBARequestStatus(tx *sql.Tx, cibaRequestId int64, fromStatus string, toStatus string) (bool, error) {
+	return d.CommonDB.UpdateCIBARequestStatus(tx, cibaRequestId, fromStatus, toStatus)
+}
+
+func (d *MySQLDB) GetCIBARequestById(tx *sql.Tx, cibaRequestId int64) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestById(tx, cibaRequestId)
+}
+
+func (d *MySQLDB) GetCIBARequestByAuthReqIdHash(tx *sql.Tx, authReqIdHash string) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestByAuthReqIdHash(tx, authReqIdHash)
+}
+
+func (d *MySQLDB) GetCIBARequestByVerificationCodeHash(tx *sql.Tx, verificationCodeHash string) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestByVerificationCodeHash(tx, verificationCodeHash)
+}
+
+func (d *MySQLDB) CIBARequestLoadClient(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CIBARequestLoadClient(tx, cibaRequest)
+}
+
+func (d *MySQLDB) CIBARequestLoadUser(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CIBARequestLoadUser(tx, cibaRequest)
+}
+
+func (d *MySQLDB) DeleteExpiredCIBARequests(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredCIBARequests(tx)
+}
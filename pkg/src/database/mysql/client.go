@@ -38,6 +38,10 @@ func (d *MySQLDB) ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) erro
 	return d.CommonDB.ClientLoadRedirectURIs(tx, client)
 }
 
+func (d *MySQLDB) ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) error {
+	return d.CommonDB.ClientLoadPostLogoutRedirectURIs(tx, client)
+}
+
 func (d *MySQLDB) ClientLoadWebOrigins(tx *sql.Tx, client *models.Client) error {
 	return d.CommonDB.ClientLoadWebOrigins(tx, client)
 }
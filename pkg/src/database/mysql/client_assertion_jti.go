@@ -0,0 +1,19 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateClientAssertionJti(tx *sql.Tx, clientAssertionJti *models.ClientAssertionJti) error {
+	return d.CommonDB.CreateClientAssertionJti(tx, clientAssertionJti)
+}
+
+func (d *MySQLDB) GetClientAssertionJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.ClientAssertionJti, error) {
+	return d.CommonDB.GetClientAssertionJti(tx, clientId, jtiHash)
+}
+
+func (d *MySQLDB) DeleteExpiredClientAssertionJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredClientAssertionJtis(tx)
+}
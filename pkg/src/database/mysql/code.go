@@ -14,6 +14,10 @@ func (d *MySQLDB) UpdateCode(tx *sql.Tx, code *models.Code) error {
 	return d.CommonDB.UpdateCode(tx, code)
 }
 
+func (d *MySQLDB) MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error) {
+	return d.CommonDB.MarkCodeAsUsed(tx, codeId)
+}
+
 func (d *MySQLDB) GetCodeById(tx *sql.Tx, codeId int64) (*models.Code, error) {
 	return d.CommonDB.GetCodeById(tx, codeId)
 }
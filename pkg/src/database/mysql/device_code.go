@@ -0,0 +1,39 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	return d.CommonDB.CreateDeviceCode(tx, deviceCode)
+}
+
+func (d *MySQLDB) UpdateDeviceCode(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	return d.CommonDB.UpdateDeviceCode(tx, deviceCode)
+}
+
+func (d *MySQLDB) UpdateDeviceCodeStatus(tx *sql.Tx, deviceCodeId int64, fromStatus string, toStatus string) (bool, error) {
+	return d.CommonDB.UpdateDeviceCodeStatus(tx, deviceCodeId, fromStatus, toStatus)
+}
+
+func (d *MySQLDB) GetDeviceCodeById(tx *sql.Tx, deviceCodeId int64) (*models.DeviceCode, error) {
+	return d.CommonDB.GetDeviceCodeById(tx, deviceCodeId)
+}
+
+func (d *MySQLDB) GetDeviceCodeByDeviceCodeHash(tx *sql.Tx, deviceCodeHash string) (*models.DeviceCode, error) {
+	return d.CommonDB.GetDeviceCodeByDeviceCodeHash(tx, deviceCodeHash)
+}
+
+func (d *MySQLDB) GetDeviceCodeByUserCode(tx *sql.Tx, userCode string) (*models.DeviceCode, error) {
+	return d.CommonDB.GetDeviceCodeByUserCode(tx, userCode)
+}
+
+func (d *MySQLDB) DeviceCodeLoadClient(tx *sql.Tx, deviceCode *models.DeviceCode) error {
+	return d.CommonDB.DeviceCodeLoadClient(tx, deviceCode)
+}
+
+func (d *MySQLDB) DeleteExpiredDeviceCodes(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredDeviceCodes(tx)
+}
@@ -0,0 +1,19 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateDPoPProofJti(tx *sql.Tx, dpopProofJti *models.DPoPProofJti) error {
+	return d.CommonDB.CreateDPoPProofJti(tx, dpopProofJti)
+}
+
+func (d *MySQLDB) GetDPoPProofJti(tx *sql.Tx, jtiHash string) (*models.DPoPProofJti, error) {
+	return d.CommonDB.GetDPoPProofJti(tx, jtiHash)
+}
+
+func (d *MySQLDB) DeleteExpiredDPoPProofJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredDPoPProofJtis(tx)
+}
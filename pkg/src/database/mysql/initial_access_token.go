@@ -0,0 +1,31 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error {
+	return d.CommonDB.CreateInitialAccessToken(tx, initialAccessToken)
+}
+
+func (d *MySQLDB) GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenById(tx, initialAccessTokenId)
+}
+
+func (d *MySQLDB) GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *MySQLDB) GetAllInitialAccessTokens(tx *sql.Tx) ([]models.InitialAccessToken, error) {
+	return d.CommonDB.GetAllInitialAccessTokens(tx)
+}
+
+func (d *MySQLDB) DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error {
+	return d.CommonDB.DeleteInitialAccessToken(tx, initialAccessTokenId)
+}
+
+func (d *MySQLDB) DeleteExpiredInitialAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredInitialAccessTokens(tx)
+}
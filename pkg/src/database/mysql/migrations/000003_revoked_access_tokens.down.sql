@@ -0,0 +1,6 @@
+-- 000003_revoked_access_tokens.down.sql
+
+ALTER TABLE `refresh_tokens`
+DROP INDEX `idx_refresh_tokens_first_refresh_token_jti`;
+
+DROP TABLE IF EXISTS `revoked_access_tokens`;
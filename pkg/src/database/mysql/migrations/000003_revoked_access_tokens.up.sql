@@ -0,0 +1,14 @@
+-- 000003_revoked_access_tokens.up.sql
+
+CREATE TABLE `revoked_access_tokens` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `jti` varchar(64) NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_revoked_access_tokens_jti` (`jti`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE `refresh_tokens`
+ADD KEY `idx_refresh_tokens_first_refresh_token_jti` (`first_refresh_token_jti`);
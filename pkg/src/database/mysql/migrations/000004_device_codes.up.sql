@@ -0,0 +1,24 @@
+-- 000004_device_codes.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `device_code_enabled` tinyint(1) NOT NULL DEFAULT 0;
+
+CREATE TABLE `device_codes` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `device_code_hash` varchar(64) NOT NULL,
+  `user_code` varchar(16) NOT NULL,
+  `client_id` bigint unsigned NOT NULL,
+  `scope` varchar(512) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  `polling_interval_seconds` int NOT NULL,
+  `last_polled_at` datetime(6) DEFAULT NULL,
+  `code_id` bigint unsigned DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_device_codes_device_code_hash` (`device_code_hash`),
+  UNIQUE KEY `idx_device_codes_user_code` (`user_code`),
+  KEY `fk_device_codes_client` (`client_id`),
+  CONSTRAINT `fk_device_codes_client` FOREIGN KEY (`client_id`) REFERENCES `clients` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
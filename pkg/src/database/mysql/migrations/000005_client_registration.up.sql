@@ -0,0 +1,19 @@
+-- 000005_client_registration.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `registration_access_token_hash` varchar(64) NOT NULL DEFAULT '';
+
+ALTER TABLE `settings`
+ADD COLUMN `dynamic_client_registration_enabled` tinyint(1) NOT NULL DEFAULT 0,
+ADD COLUMN `dynamic_client_registration_open` tinyint(1) NOT NULL DEFAULT 0;
+
+CREATE TABLE `initial_access_tokens` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `description` varchar(128) NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_initial_access_tokens_token_hash` (`token_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
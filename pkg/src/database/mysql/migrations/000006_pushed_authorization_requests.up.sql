@@ -0,0 +1,18 @@
+-- 000006_pushed_authorization_requests.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `par_required` tinyint(1) NOT NULL DEFAULT 0;
+
+CREATE TABLE `pushed_authorization_requests` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `request_uri_hash` varchar(64) NOT NULL,
+  `client_id` bigint unsigned NOT NULL,
+  `parameters` text NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_pushed_authorization_requests_request_uri_hash` (`request_uri_hash`),
+  KEY `fk_pushed_authorization_requests_client` (`client_id`),
+  CONSTRAINT `fk_pushed_authorization_requests_client` FOREIGN KEY (`client_id`) REFERENCES `clients` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,4 @@
+-- 000007_signing_key_algorithm.down.sql
+
+ALTER TABLE `settings`
+DROP COLUMN `signing_key_algorithm`;
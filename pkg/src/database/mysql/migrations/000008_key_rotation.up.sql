@@ -0,0 +1,4 @@
+-- 000008_key_rotation.up.sql
+
+ALTER TABLE `settings`
+ADD COLUMN `key_rotation_interval_in_seconds` int NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- 000009_client_assertion.down.sql
+
+DROP TABLE IF EXISTS `client_assertion_jtis`;
+
+ALTER TABLE `clients`
+DROP COLUMN `jwks_uri`,
+DROP COLUMN `jwks`,
+DROP COLUMN `token_endpoint_auth_method`;
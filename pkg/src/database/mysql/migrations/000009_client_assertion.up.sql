@@ -0,0 +1,18 @@
+-- 000009_client_assertion.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `token_endpoint_auth_method` varchar(40) NOT NULL DEFAULT '',
+ADD COLUMN `jwks` longblob,
+ADD COLUMN `jwks_uri` varchar(256) NOT NULL DEFAULT '';
+
+CREATE TABLE `client_assertion_jtis` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `client_id` bigint unsigned NOT NULL,
+  `jti_hash` varchar(64) NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_client_assertion_jtis_client_id_jti_hash` (`client_id`, `jti_hash`),
+  CONSTRAINT `fk_client_assertion_jtis_client` FOREIGN KEY (`client_id`) REFERENCES `clients` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,4 @@
+-- 000010_tls_client_auth.down.sql
+
+ALTER TABLE `clients`
+DROP COLUMN `tls_client_auth_subject_dn`;
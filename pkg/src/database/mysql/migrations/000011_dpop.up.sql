@@ -0,0 +1,14 @@
+-- 000011_dpop.up.sql
+
+ALTER TABLE `refresh_tokens`
+ADD COLUMN `dpop_jkt` varchar(64) NOT NULL DEFAULT '';
+
+CREATE TABLE `dpop_proof_jtis` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `jti_hash` varchar(64) NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_dpop_proof_jtis_jti_hash` (`jti_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
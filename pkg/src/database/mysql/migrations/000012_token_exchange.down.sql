@@ -0,0 +1,5 @@
+-- 000012_token_exchange.down.sql
+
+ALTER TABLE `clients`
+DROP COLUMN `token_exchange_impersonation_enabled`,
+DROP COLUMN `token_exchange_enabled`;
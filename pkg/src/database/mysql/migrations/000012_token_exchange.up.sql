@@ -0,0 +1,5 @@
+-- 000012_token_exchange.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `token_exchange_enabled` tinyint(1) NOT NULL DEFAULT 0,
+ADD COLUMN `token_exchange_impersonation_enabled` tinyint(1) NOT NULL DEFAULT 0;
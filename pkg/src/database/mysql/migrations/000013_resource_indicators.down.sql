@@ -0,0 +1,4 @@
+-- 000013_resource_indicators.down.sql
+
+ALTER TABLE `codes`
+DROP COLUMN `resource`;
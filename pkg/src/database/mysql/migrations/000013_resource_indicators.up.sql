@@ -0,0 +1,4 @@
+-- 000013_resource_indicators.up.sql
+
+ALTER TABLE `codes`
+ADD COLUMN `resource` varchar(40) NOT NULL DEFAULT '';
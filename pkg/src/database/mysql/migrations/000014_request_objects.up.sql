@@ -0,0 +1,4 @@
+-- 000014_request_objects.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `require_signed_request_object` tinyint(1) NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- 000016_pairwise_subjects.down.sql
+
+DROP TABLE IF EXISTS `pairwise_subjects`;
+
+ALTER TABLE `clients`
+DROP COLUMN `sector_identifier`,
+DROP COLUMN `sector_identifier_uri`,
+DROP COLUMN `subject_type`;
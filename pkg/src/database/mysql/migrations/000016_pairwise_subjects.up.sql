@@ -0,0 +1,18 @@
+-- 000016_pairwise_subjects.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `subject_type` varchar(20) NOT NULL DEFAULT 'public',
+ADD COLUMN `sector_identifier_uri` varchar(256) NOT NULL DEFAULT '',
+ADD COLUMN `sector_identifier` varchar(256) NOT NULL DEFAULT '';
+
+CREATE TABLE `pairwise_subjects` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `sector_identifier` varchar(256) NOT NULL,
+  `subject` varchar(64) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_pairwise_subjects_user_id_sector_identifier` (`user_id`, `sector_identifier`),
+  UNIQUE KEY `idx_pairwise_subjects_sector_identifier_subject` (`sector_identifier`, `subject`),
+  CONSTRAINT `fk_pairwise_subjects_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
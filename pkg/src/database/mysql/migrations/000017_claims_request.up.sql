@@ -0,0 +1,4 @@
+-- 000017_claims_request.up.sql
+
+ALTER TABLE `codes`
+ADD COLUMN `claims` text NOT NULL;
@@ -0,0 +1,7 @@
+-- 000018_encrypted_responses.down.sql
+
+ALTER TABLE `clients`
+DROP COLUMN `userinfo_encrypted_response_enc`,
+DROP COLUMN `userinfo_encrypted_response_alg`,
+DROP COLUMN `id_token_encrypted_response_enc`,
+DROP COLUMN `id_token_encrypted_response_alg`;
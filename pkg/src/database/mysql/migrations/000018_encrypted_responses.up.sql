@@ -0,0 +1,7 @@
+-- 000018_encrypted_responses.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `id_token_encrypted_response_alg` varchar(20) NOT NULL DEFAULT '',
+ADD COLUMN `id_token_encrypted_response_enc` varchar(20) NOT NULL DEFAULT '',
+ADD COLUMN `userinfo_encrypted_response_alg` varchar(20) NOT NULL DEFAULT '',
+ADD COLUMN `userinfo_encrypted_response_enc` varchar(20) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- 000019_ciba.down.sql
+
+DROP TABLE IF EXISTS `ciba_requests`;
+
+ALTER TABLE `clients`
+DROP COLUMN `backchannel_client_notification_endpoint`,
+DROP COLUMN `backchannel_token_delivery_mode`,
+DROP COLUMN `ciba_enabled`;
@@ -0,0 +1,34 @@
+-- 000019_ciba.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `ciba_enabled` tinyint(1) NOT NULL DEFAULT 0,
+ADD COLUMN `backchannel_token_delivery_mode` varchar(10) NOT NULL DEFAULT '',
+ADD COLUMN `backchannel_client_notification_endpoint` varchar(256) NOT NULL DEFAULT '';
+
+CREATE TABLE `ciba_requests` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `auth_req_id_hash` varchar(64) NOT NULL,
+  `auth_req_id_encrypted` longblob,
+  `verification_code_hash` varchar(64) NOT NULL,
+  `client_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `scope` varchar(512) NOT NULL,
+  `acr_values` varchar(128) NOT NULL,
+  `binding_message` varchar(128) NOT NULL,
+  `token_delivery_mode` varchar(10) NOT NULL,
+  `client_notification_token_encrypted` longblob,
+  `status` varchar(16) NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  `polling_interval_seconds` int NOT NULL,
+  `last_polled_at` datetime(6) DEFAULT NULL,
+  `code_id` bigint unsigned DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_ciba_requests_auth_req_id_hash` (`auth_req_id_hash`),
+  UNIQUE KEY `idx_ciba_requests_verification_code_hash` (`verification_code_hash`),
+  KEY `fk_ciba_requests_client` (`client_id`),
+  KEY `fk_ciba_requests_user` (`user_id`),
+  CONSTRAINT `fk_ciba_requests_client` FOREIGN KEY (`client_id`) REFERENCES `clients` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_ciba_requests_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,10 @@
+-- 000020_refresh_token_reuse.down.sql
+
+ALTER TABLE `clients`
+DROP COLUMN `refresh_token_reuse_grace_period_in_seconds`;
+
+ALTER TABLE `settings`
+DROP COLUMN `refresh_token_reuse_grace_period_in_seconds`;
+
+ALTER TABLE `refresh_tokens`
+DROP COLUMN `superseded_at`;
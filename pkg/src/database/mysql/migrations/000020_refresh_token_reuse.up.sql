@@ -0,0 +1,10 @@
+-- 000020_refresh_token_reuse.up.sql
+
+ALTER TABLE `refresh_tokens`
+ADD COLUMN `superseded_at` datetime(6) DEFAULT NULL;
+
+ALTER TABLE `settings`
+ADD COLUMN `refresh_token_reuse_grace_period_in_seconds` int NOT NULL DEFAULT 0;
+
+ALTER TABLE `clients`
+ADD COLUMN `refresh_token_reuse_grace_period_in_seconds` int DEFAULT NULL;
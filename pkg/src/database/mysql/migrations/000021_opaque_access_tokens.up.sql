@@ -0,0 +1,20 @@
+-- 000021_opaque_access_tokens.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `access_token_format` varchar(10) NOT NULL DEFAULT '';
+
+CREATE TABLE `opaque_access_tokens` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(6) DEFAULT NULL,
+  `updated_at` datetime(6) DEFAULT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `jti` varchar(64) NOT NULL,
+  `client_id` bigint unsigned NOT NULL,
+  `claims` mediumtext NOT NULL,
+  `expires_at` datetime(6) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_opaque_access_tokens_token_hash` (`token_hash`),
+  KEY `idx_opaque_access_tokens_expires_at` (`expires_at`),
+  KEY `fk_opaque_access_tokens_client` (`client_id`),
+  CONSTRAINT `fk_opaque_access_tokens_client` FOREIGN KEY (`client_id`) REFERENCES `clients` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,4 @@
+-- 000022_token_exchange_subject_clients.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `token_exchange_subject_clients` varchar(1024) NOT NULL DEFAULT '';
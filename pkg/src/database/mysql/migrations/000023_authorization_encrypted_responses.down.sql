@@ -0,0 +1,5 @@
+-- 000023_authorization_encrypted_responses.down.sql
+
+ALTER TABLE `clients`
+DROP COLUMN `authorization_encrypted_response_enc`,
+DROP COLUMN `authorization_encrypted_response_alg`;
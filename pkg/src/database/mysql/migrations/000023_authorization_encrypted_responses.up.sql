@@ -0,0 +1,5 @@
+-- 000023_authorization_encrypted_responses.up.sql
+
+ALTER TABLE `clients`
+ADD COLUMN `authorization_encrypted_response_alg` varchar(20) NOT NULL DEFAULT '',
+ADD COLUMN `authorization_encrypted_response_enc` varchar(20) NOT NULL DEFAULT '';
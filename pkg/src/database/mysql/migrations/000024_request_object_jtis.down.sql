@@ -0,0 +1,3 @@
+-- 000024_request_object_jtis.down.sql
+
+DROP TABLE IF EXISTS `request_object_jtis`;
@@ -0,0 +1,19 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateOpaqueAccessToken(tx *sql.Tx, opaqueAccessToken *models.OpaqueAccessToken) error {
+	return d.CommonDB.CreateOpaqueAccessToken(tx, opaqueAccessToken)
+}
+
+func (d *MySQLDB) GetOpaqueAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.OpaqueAccessToken, error) {
+	return d.CommonDB.GetOpaqueAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *MySQLDB) DeleteExpiredOpaqueAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredOpaqueAccessTokens(tx)
+}
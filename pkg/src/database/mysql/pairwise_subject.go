@@ -0,0 +1,19 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreatePairwiseSubject(tx *sql.Tx, pairwiseSubject *models.PairwiseSubject) error {
+	return d.CommonDB.CreatePairwiseSubject(tx, pairwiseSubject)
+}
+
+func (d *MySQLDB) GetPairwiseSubjectByUserIdAndSectorIdentifier(tx *sql.Tx, userId int64, sectorIdentifier string) (*models.PairwiseSubject, error) {
+	return d.CommonDB.GetPairwiseSubjectByUserIdAndSectorIdentifier(tx, userId, sectorIdentifier)
+}
+
+func (d *MySQLDB) GetPairwiseSubjectBySectorIdentifierAndSubject(tx *sql.Tx, sectorIdentifier string, subject string) (*models.PairwiseSubject, error) {
+	return d.CommonDB.GetPairwiseSubjectBySectorIdentifierAndSubject(tx, sectorIdentifier, subject)
+}
@@ -0,0 +1,23 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreatePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURI *models.PostLogoutRedirectURI) error {
+	return d.CommonDB.CreatePostLogoutRedirectURI(tx, postLogoutRedirectURI)
+}
+
+func (d *MySQLDB) GetPostLogoutRedirectURIById(tx *sql.Tx, postLogoutRedirectURIId int64) (*models.PostLogoutRedirectURI, error) {
+	return d.CommonDB.GetPostLogoutRedirectURIById(tx, postLogoutRedirectURIId)
+}
+
+func (d *MySQLDB) GetPostLogoutRedirectURIsByClientId(tx *sql.Tx, clientId int64) ([]models.PostLogoutRedirectURI, error) {
+	return d.CommonDB.GetPostLogoutRedirectURIsByClientId(tx, clientId)
+}
+
+func (d *MySQLDB) DeletePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURIId int64) error {
+	return d.CommonDB.DeletePostLogoutRedirectURI(tx, postLogoutRedirectURIId)
+}
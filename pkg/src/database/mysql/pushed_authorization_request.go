@@ -0,0 +1,23 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreatePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequest *models.PushedAuthorizationRequest) error {
+	return d.CommonDB.CreatePushedAuthorizationRequest(tx, pushedAuthorizationRequest)
+}
+
+func (d *MySQLDB) GetPushedAuthorizationRequestByRequestURIHash(tx *sql.Tx, requestURIHash string) (*models.PushedAuthorizationRequest, error) {
+	return d.CommonDB.GetPushedAuthorizationRequestByRequestURIHash(tx, requestURIHash)
+}
+
+func (d *MySQLDB) DeletePushedAuthorizationRequest(tx *sql.Tx, pushedAuthorizationRequestId int64) error {
+	return d.CommonDB.DeletePushedAuthorizationRequest(tx, pushedAuthorizationRequestId)
+}
+
+func (d *MySQLDB) DeleteExpiredPushedAuthorizationRequests(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredPushedAuthorizationRequests(tx)
+}
@@ -33,3 +33,15 @@ func (d *MySQLDB) DeleteRefreshToken(tx *sql.Tx, refreshTokenId int64) error {
 func (d *MySQLDB) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 	return d.CommonDB.DeleteExpiredOrRevokedRefreshTokens(tx)
 }
+
+func (d *MySQLDB) SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error) {
+	return d.CommonDB.SupersedeRefreshToken(tx, refreshTokenId)
+}
+
+func (d *MySQLDB) RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error {
+	return d.CommonDB.RevokeRefreshTokensByFirstRefreshTokenJti(tx, firstRefreshTokenJti)
+}
+
+func (d *MySQLDB) RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error {
+	return d.CommonDB.RevokeRefreshTokensBySessionIdentifier(tx, sessionIdentifier)
+}
@@ -0,0 +1,19 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateRequestObjectJti(tx *sql.Tx, requestObjectJti *models.RequestObjectJti) error {
+	return d.CommonDB.CreateRequestObjectJti(tx, requestObjectJti)
+}
+
+func (d *MySQLDB) GetRequestObjectJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.RequestObjectJti, error) {
+	return d.CommonDB.GetRequestObjectJti(tx, clientId, jtiHash)
+}
+
+func (d *MySQLDB) DeleteExpiredRequestObjectJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredRequestObjectJtis(tx)
+}
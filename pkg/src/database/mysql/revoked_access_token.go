@@ -0,0 +1,19 @@
+package mysqldb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *MySQLDB) CreateRevokedAccessToken(tx *sql.Tx, revokedAccessToken *models.RevokedAccessToken) error {
+	return d.CommonDB.CreateRevokedAccessToken(tx, revokedAccessToken)
+}
+
+func (d *MySQLDB) GetRevokedAccessTokenByJti(tx *sql.Tx, jti string) (*models.RevokedAccessToken, error) {
+	return d.CommonDB.GetRevokedAccessTokenByJti(tx, jti)
+}
+
+func (d *MySQLDB) DeleteExpiredRevokedAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredRevokedAccessTokens(tx)
+}
@@ -0,0 +1,79 @@
+package postgresdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *PostgresDB) CreateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	if cibaRequest.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	if cibaRequest.UserId == 0 {
+		return errors.WithStack(errors.New("user id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := cibaRequest.CreatedAt
+	originalUpdatedAt := cibaRequest.UpdatedAt
+	cibaRequest.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	cibaRequest.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	cibaRequestStruct := sqlbuilder.NewStruct(new(models.CIBARequest)).For(sqlbuilder.PostgreSQL)
+	insertBuilder := cibaRequestStruct.WithoutTag("pk").InsertInto("ciba_requests", cibaRequest)
+	sql, args := insertBuilder.Build()
+	sql += " RETURNING id"
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		cibaRequest.CreatedAt = originalCreatedAt
+		cibaRequest.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert cibaRequest")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&cibaRequest.Id); err != nil {
+			cibaRequest.CreatedAt = originalCreatedAt
+			cibaRequest.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan cibaRequest id")
+		}
+	}
+
+	return nil
+}
+
+func (d *PostgresDB) UpdateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.UpdateCIBARequest(tx, cibaRequest)
+}
+
+func (d *PostgresDB) UpdateCIBARequestStatus(tx *sql.Tx, cibaRequestId int64, fromStatus string, toStatus string) (bool, error) {
+	return d.CommonDB.UpdateCIBARequestStatus(tx, cibaRequestId, fromStatus, toStatus)
+}
+
+func (d *PostgresDB) GetCIBARequestById(tx *sql.Tx, cibaRequestId int64) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestById(tx, cibaRequestId)
+}
+
+func (d *PostgresDB) GetCIBARequestByAuthReqIdHash(tx *sql.Tx, authReqIdHash string) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestByAuthReqIdHash(tx, authReqIdHash)
+}
+
+func (d *PostgresDB) GetCIBARequestByVerificationCodeHash(tx *sql.Tx, verificationCodeHash string) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestByVerificationCodeHash(tx, verificationCodeHash)
+}
+
+func (d *PostgresDB) CIBARequestLoadClient(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CIBARequestLoadClient(tx, cibaRequest)
+}
+
+func (d *PostgresDB) CIBARequestLoadUser(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CIBARequestLoadUser(tx, cibaRequest)
+}
+
+func (d *PostgresDB) DeleteExpiredCIBARequests(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredCIBARequests(tx)
+}
@@ -66,6 +66,10 @@ func (d *PostgresDB) ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) e
 	return d.CommonDB.ClientLoadRedirectURIs(tx, client)
 }
 
+func (d *PostgresDB) ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) error {
+	return d.CommonDB.ClientLoadPostLogoutRedirectURIs(tx, client)
+}
+
 func (d *PostgresDB) ClientLoadWebOrigins(tx *sql.Tx, client *models.Client) error {
 	return d.CommonDB.ClientLoadWebOrigins(tx, client)
 }
@@ -50,6 +50,10 @@ func (d *PostgresDB) UpdateCode(tx *sql.Tx, code *models.Code) error {
 	return d.CommonDB.UpdateCode(tx, code)
 }
 
+func (d *PostgresDB) MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error) {
+	return d.CommonDB.MarkCodeAsUsed(tx, codeId)
+}
+
 func (d *PostgresDB) GetCodeById(tx *sql.Tx, codeId int64) (*models.Code, error) {
 	return d.CommonDB.GetCodeById(tx, codeId)
 }
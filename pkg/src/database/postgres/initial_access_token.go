@@ -0,0 +1,59 @@
+package postgresdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *PostgresDB) CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := initialAccessToken.CreatedAt
+	originalUpdatedAt := initialAccessToken.UpdatedAt
+	initialAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	initialAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	initialAccessTokenStruct := sqlbuilder.NewStruct(new(models.InitialAccessToken)).For(sqlbuilder.PostgreSQL)
+	insertBuilder := initialAccessTokenStruct.WithoutTag("pk").InsertInto("initial_access_tokens", initialAccessToken)
+	sql, args := insertBuilder.Build()
+	sql += " RETURNING id"
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		initialAccessToken.CreatedAt = originalCreatedAt
+		initialAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert initialAccessToken")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&initialAccessToken.Id); err != nil {
+			initialAccessToken.CreatedAt = originalCreatedAt
+			initialAccessToken.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan initialAccessToken id")
+		}
+	}
+
+	return nil
+}
+
+func (d *PostgresDB) GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenById(tx, initialAccessTokenId)
+}
+
+func (d *PostgresDB) GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *PostgresDB) GetAllInitialAccessTokens(tx *sql.Tx) ([]models.InitialAccessToken, error) {
+	return d.CommonDB.GetAllInitialAccessTokens(tx)
+}
+
+func (d *PostgresDB) DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error {
+	return d.CommonDB.DeleteInitialAccessToken(tx, initialAccessTokenId)
+}
+
+func (d *PostgresDB) DeleteExpiredInitialAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredInitialAccessTokens(tx)
+}
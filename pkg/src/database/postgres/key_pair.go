@@ -46,6 +46,10 @@ func (d *PostgresDB) GetKeyPairById(tx *sql.Tx, keyPairId int64) (*models.KeyPai
 	return d.CommonDB.GetKeyPairById(tx, keyPairId)
 }
 
+func (d *PostgresDB) UpdateKeyPairState(tx *sql.Tx, keyPairId int64, fromState string, toState string) (bool, error) {
+	return d.CommonDB.UpdateKeyPairState(tx, keyPairId, fromState, toState)
+}
+
 func (d *PostgresDB) GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error) {
 	return d.CommonDB.GetAllSigningKeys(tx)
 }
@@ -57,3 +61,7 @@ func (d *PostgresDB) GetCurrentSigningKey(tx *sql.Tx) (*models.KeyPair, error) {
 func (d *PostgresDB) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
 	return d.CommonDB.DeleteKeyPair(tx, keyPairId)
 }
+
+func (d *PostgresDB) DeleteKeyPairWithState(tx *sql.Tx, keyPairId int64, state string) (bool, error) {
+	return d.CommonDB.DeleteKeyPairWithState(tx, keyPairId, state)
+}
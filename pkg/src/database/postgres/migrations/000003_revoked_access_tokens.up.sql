@@ -0,0 +1,12 @@
+-- 000003_revoked_access_tokens.up.sql
+
+CREATE TABLE revoked_access_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  jti VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP(6)
+);
+
+CREATE UNIQUE INDEX idx_revoked_access_tokens_jti ON revoked_access_tokens(jti);
+CREATE INDEX idx_refresh_tokens_first_refresh_token_jti ON refresh_tokens(first_refresh_token_jti);
@@ -0,0 +1,4 @@
+-- 000004_device_codes.down.sql
+
+DROP TABLE IF EXISTS device_codes;
+ALTER TABLE clients DROP COLUMN IF EXISTS device_code_enabled;
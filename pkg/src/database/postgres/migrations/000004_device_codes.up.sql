@@ -0,0 +1,22 @@
+-- 000004_device_codes.up.sql
+
+ALTER TABLE clients ADD COLUMN device_code_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE device_codes (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  device_code_hash VARCHAR(64) NOT NULL,
+  user_code VARCHAR(16) NOT NULL,
+  client_id BIGINT NOT NULL,
+  scope VARCHAR(512) NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  expires_at TIMESTAMP(6),
+  polling_interval_seconds INTEGER NOT NULL,
+  last_polled_at TIMESTAMP(6),
+  code_id BIGINT,
+  CONSTRAINT fk_device_codes_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_device_codes_device_code_hash ON device_codes(device_code_hash);
+CREATE UNIQUE INDEX idx_device_codes_user_code ON device_codes(user_code);
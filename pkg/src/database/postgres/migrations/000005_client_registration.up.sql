@@ -0,0 +1,16 @@
+-- 000005_client_registration.up.sql
+
+ALTER TABLE clients ADD COLUMN registration_access_token_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN dynamic_client_registration_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE settings ADD COLUMN dynamic_client_registration_open BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE initial_access_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  token_hash VARCHAR(64) NOT NULL,
+  description VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMP(6)
+);
+
+CREATE UNIQUE INDEX idx_initial_access_tokens_token_hash ON initial_access_tokens(token_hash);
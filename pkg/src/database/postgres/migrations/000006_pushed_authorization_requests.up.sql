@@ -0,0 +1,16 @@
+-- 000006_pushed_authorization_requests.up.sql
+
+ALTER TABLE clients ADD COLUMN par_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE pushed_authorization_requests (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  request_uri_hash VARCHAR(64) NOT NULL,
+  client_id BIGINT NOT NULL,
+  parameters TEXT NOT NULL,
+  expires_at TIMESTAMP(6),
+  CONSTRAINT fk_pushed_authorization_requests_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_pushed_authorization_requests_request_uri_hash ON pushed_authorization_requests(request_uri_hash);
@@ -0,0 +1,3 @@
+-- 000008_key_rotation.up.sql
+
+ALTER TABLE settings ADD COLUMN key_rotation_interval_in_seconds INTEGER NOT NULL DEFAULT 0;
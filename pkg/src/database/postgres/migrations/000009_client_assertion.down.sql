@@ -0,0 +1,6 @@
+-- 000009_client_assertion.down.sql
+
+DROP TABLE IF EXISTS client_assertion_jtis;
+ALTER TABLE clients DROP COLUMN IF EXISTS jwks_uri;
+ALTER TABLE clients DROP COLUMN IF EXISTS jwks;
+ALTER TABLE clients DROP COLUMN IF EXISTS token_endpoint_auth_method;
@@ -0,0 +1,17 @@
+-- 000009_client_assertion.up.sql
+
+ALTER TABLE clients ADD COLUMN token_endpoint_auth_method VARCHAR(40) NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN jwks BYTEA;
+ALTER TABLE clients ADD COLUMN jwks_uri VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE TABLE client_assertion_jtis (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  client_id BIGINT NOT NULL,
+  jti_hash VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP(6),
+  CONSTRAINT fk_client_assertion_jtis_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_client_assertion_jtis_client_id_jti_hash ON client_assertion_jtis(client_id, jti_hash);
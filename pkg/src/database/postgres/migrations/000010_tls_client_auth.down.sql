@@ -0,0 +1,3 @@
+-- 000010_tls_client_auth.down.sql
+
+ALTER TABLE clients DROP COLUMN IF EXISTS tls_client_auth_subject_dn;
@@ -0,0 +1,4 @@
+-- 000011_dpop.down.sql
+
+DROP TABLE IF EXISTS dpop_proof_jtis;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS dpop_jkt;
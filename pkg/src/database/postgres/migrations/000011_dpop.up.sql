@@ -0,0 +1,13 @@
+-- 000011_dpop.up.sql
+
+ALTER TABLE refresh_tokens ADD COLUMN dpop_jkt VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE TABLE dpop_proof_jtis (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  jti_hash VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP(6)
+);
+
+CREATE UNIQUE INDEX idx_dpop_proof_jtis_jti_hash ON dpop_proof_jtis(jti_hash);
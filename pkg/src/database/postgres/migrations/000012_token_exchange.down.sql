@@ -0,0 +1,4 @@
+-- 000012_token_exchange.down.sql
+
+ALTER TABLE clients DROP COLUMN IF EXISTS token_exchange_impersonation_enabled;
+ALTER TABLE clients DROP COLUMN IF EXISTS token_exchange_enabled;
@@ -0,0 +1,4 @@
+-- 000012_token_exchange.up.sql
+
+ALTER TABLE clients ADD COLUMN token_exchange_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE clients ADD COLUMN token_exchange_impersonation_enabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- 000013_resource_indicators.down.sql
+
+ALTER TABLE codes DROP COLUMN IF EXISTS resource;
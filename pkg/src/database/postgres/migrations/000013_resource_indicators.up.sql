@@ -0,0 +1,3 @@
+-- 000013_resource_indicators.up.sql
+
+ALTER TABLE codes ADD COLUMN resource VARCHAR(40) NOT NULL DEFAULT '';
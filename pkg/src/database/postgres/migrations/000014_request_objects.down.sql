@@ -0,0 +1,3 @@
+-- 000014_request_objects.down.sql
+
+ALTER TABLE clients DROP COLUMN IF EXISTS require_signed_request_object;
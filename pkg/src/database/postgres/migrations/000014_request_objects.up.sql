@@ -0,0 +1,3 @@
+-- 000014_request_objects.up.sql
+
+ALTER TABLE clients ADD COLUMN require_signed_request_object BOOLEAN NOT NULL DEFAULT FALSE;
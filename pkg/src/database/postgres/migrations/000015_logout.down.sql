@@ -0,0 +1,5 @@
+-- 000015_logout.down.sql
+
+DROP TABLE IF EXISTS post_logout_redirect_uris;
+ALTER TABLE clients DROP COLUMN IF EXISTS backchannel_logout_uri;
+ALTER TABLE clients DROP COLUMN IF EXISTS frontchannel_logout_uri;
@@ -0,0 +1,12 @@
+-- 000015_logout.up.sql
+
+ALTER TABLE clients ADD COLUMN frontchannel_logout_uri VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN backchannel_logout_uri VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE TABLE post_logout_redirect_uris (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  uri VARCHAR(256) NOT NULL,
+  client_id BIGINT NOT NULL,
+  CONSTRAINT fk_clients_post_logout_redirect_uris FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- 000016_pairwise_subjects.up.sql
+
+ALTER TABLE clients ADD COLUMN subject_type VARCHAR(20) NOT NULL DEFAULT 'public';
+ALTER TABLE clients ADD COLUMN sector_identifier_uri VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN sector_identifier VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE TABLE pairwise_subjects (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  user_id BIGINT NOT NULL,
+  sector_identifier VARCHAR(256) NOT NULL,
+  subject VARCHAR(64) NOT NULL,
+  CONSTRAINT fk_pairwise_subjects_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_pairwise_subjects_user_id_sector_identifier ON pairwise_subjects(user_id, sector_identifier);
+CREATE UNIQUE INDEX idx_pairwise_subjects_sector_identifier_subject ON pairwise_subjects(sector_identifier, subject);
@@ -0,0 +1,3 @@
+-- 000017_claims_request.up.sql
+
+ALTER TABLE codes ADD COLUMN claims TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,31 @@
+-- 000019_ciba.up.sql
+
+ALTER TABLE clients ADD COLUMN ciba_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE clients ADD COLUMN backchannel_token_delivery_mode VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN backchannel_client_notification_endpoint VARCHAR(256) NOT NULL DEFAULT '';
+
+CREATE TABLE ciba_requests (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  auth_req_id_hash VARCHAR(64) NOT NULL,
+  auth_req_id_encrypted BYTEA,
+  verification_code_hash VARCHAR(64) NOT NULL,
+  client_id BIGINT NOT NULL,
+  user_id BIGINT NOT NULL,
+  scope VARCHAR(512) NOT NULL,
+  acr_values VARCHAR(128) NOT NULL,
+  binding_message VARCHAR(128) NOT NULL,
+  token_delivery_mode VARCHAR(10) NOT NULL,
+  client_notification_token_encrypted BYTEA,
+  status VARCHAR(16) NOT NULL,
+  expires_at TIMESTAMP(6),
+  polling_interval_seconds INTEGER NOT NULL,
+  last_polled_at TIMESTAMP(6),
+  code_id BIGINT,
+  CONSTRAINT fk_ciba_requests_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE,
+  CONSTRAINT fk_ciba_requests_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_ciba_requests_auth_req_id_hash ON ciba_requests(auth_req_id_hash);
+CREATE UNIQUE INDEX idx_ciba_requests_verification_code_hash ON ciba_requests(verification_code_hash);
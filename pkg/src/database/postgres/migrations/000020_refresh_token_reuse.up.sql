@@ -0,0 +1,5 @@
+-- 000020_refresh_token_reuse.up.sql
+
+ALTER TABLE refresh_tokens ADD COLUMN superseded_at TIMESTAMP(6);
+ALTER TABLE settings ADD COLUMN refresh_token_reuse_grace_period_in_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE clients ADD COLUMN refresh_token_reuse_grace_period_in_seconds INTEGER;
@@ -0,0 +1,4 @@
+-- 000021_opaque_access_tokens.down.sql
+
+DROP TABLE IF EXISTS opaque_access_tokens;
+ALTER TABLE clients DROP COLUMN IF EXISTS access_token_format;
@@ -0,0 +1,18 @@
+-- 000021_opaque_access_tokens.up.sql
+
+ALTER TABLE clients ADD COLUMN access_token_format VARCHAR(10) NOT NULL DEFAULT '';
+
+CREATE TABLE opaque_access_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  token_hash VARCHAR(64) NOT NULL,
+  jti VARCHAR(64) NOT NULL,
+  client_id BIGINT NOT NULL,
+  claims TEXT NOT NULL,
+  expires_at TIMESTAMP(6),
+  CONSTRAINT fk_opaque_access_tokens_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_opaque_access_tokens_token_hash ON opaque_access_tokens(token_hash);
+CREATE INDEX idx_opaque_access_tokens_expires_at ON opaque_access_tokens(expires_at);
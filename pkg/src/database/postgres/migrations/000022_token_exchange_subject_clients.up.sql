@@ -0,0 +1,3 @@
+-- 000022_token_exchange_subject_clients.up.sql
+
+ALTER TABLE clients ADD COLUMN token_exchange_subject_clients VARCHAR(1024) NOT NULL DEFAULT '';
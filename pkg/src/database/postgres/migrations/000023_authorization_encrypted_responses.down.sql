@@ -0,0 +1,4 @@
+-- 000023_authorization_encrypted_responses.down.sql
+
+ALTER TABLE clients DROP COLUMN IF EXISTS authorization_encrypted_response_enc;
+ALTER TABLE clients DROP COLUMN IF EXISTS authorization_encrypted_response_alg;
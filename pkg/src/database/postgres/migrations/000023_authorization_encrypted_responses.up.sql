@@ -0,0 +1,4 @@
+-- 000023_authorization_encrypted_responses.up.sql
+
+ALTER TABLE clients ADD COLUMN authorization_encrypted_response_alg VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN authorization_encrypted_response_enc VARCHAR(20) NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+-- 000024_request_object_jtis.up.sql
+
+CREATE TABLE request_object_jtis (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP(6),
+  updated_at TIMESTAMP(6),
+  client_id BIGINT NOT NULL,
+  jti_hash VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP(6),
+  CONSTRAINT fk_request_object_jtis_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_request_object_jtis_client_id_jti_hash ON request_object_jtis(client_id, jti_hash);
@@ -0,0 +1,47 @@
+package postgresdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *PostgresDB) CreatePairwiseSubject(tx *sql.Tx, pairwiseSubject *models.PairwiseSubject) error {
+	if pairwiseSubject.UserId == 0 {
+		return errors.WithStack(errors.New("user id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := pairwiseSubject.CreatedAt
+	pairwiseSubject.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	pairwiseSubjectStruct := sqlbuilder.NewStruct(new(models.PairwiseSubject)).For(sqlbuilder.PostgreSQL)
+	insertBuilder := pairwiseSubjectStruct.WithoutTag("pk").InsertInto("pairwise_subjects", pairwiseSubject)
+	sql, args := insertBuilder.Build()
+	sql += " RETURNING id"
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		pairwiseSubject.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to insert pairwiseSubject")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&pairwiseSubject.Id); err != nil {
+			pairwiseSubject.CreatedAt = originalCreatedAt
+			return errors.Wrap(err, "unable to scan pairwiseSubject id")
+		}
+	}
+
+	return nil
+}
+
+func (d *PostgresDB) GetPairwiseSubjectByUserIdAndSectorIdentifier(tx *sql.Tx, userId int64, sectorIdentifier string) (*models.PairwiseSubject, error) {
+	return d.CommonDB.GetPairwiseSubjectByUserIdAndSectorIdentifier(tx, userId, sectorIdentifier)
+}
+
+func (d *PostgresDB) GetPairwiseSubjectBySectorIdentifierAndSubject(tx *sql.Tx, sectorIdentifier string, subject string) (*models.PairwiseSubject, error) {
+	return d.CommonDB.GetPairwiseSubjectBySectorIdentifierAndSubject(tx, sectorIdentifier, subject)
+}
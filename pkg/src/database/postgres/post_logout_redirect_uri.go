@@ -0,0 +1,51 @@
+package postgresdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *PostgresDB) CreatePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURI *models.PostLogoutRedirectURI) error {
+	if postLogoutRedirectURI.ClientId == 0 {
+		return errors.WithStack(errors.New("client id must be greater than 0"))
+	}
+
+	now := time.Now().UTC()
+	originalCreatedAt := postLogoutRedirectURI.CreatedAt
+	postLogoutRedirectURI.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	postLogoutRedirectURIStruct := sqlbuilder.NewStruct(new(models.PostLogoutRedirectURI)).For(sqlbuilder.PostgreSQL)
+	insertBuilder := postLogoutRedirectURIStruct.WithoutTag("pk").InsertInto("post_logout_redirect_uris", postLogoutRedirectURI)
+	sql, args := insertBuilder.Build()
+	sql += " RETURNING id"
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		postLogoutRedirectURI.CreatedAt = originalCreatedAt
+		return errors.Wrap(err, "unable to insert postLogoutRedirectURI")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&postLogoutRedirectURI.Id); err != nil {
+			postLogoutRedirectURI.CreatedAt = originalCreatedAt
+			return errors.Wrap(err, "unable to scan postLogoutRedirectURI id")
+		}
+	}
+
+	return nil
+}
+
+func (d *PostgresDB) GetPostLogoutRedirectURIById(tx *sql.Tx, postLogoutRedirectURIId int64) (*models.PostLogoutRedirectURI, error) {
+	return d.CommonDB.GetPostLogoutRedirectURIById(tx, postLogoutRedirectURIId)
+}
+
+func (d *PostgresDB) GetPostLogoutRedirectURIsByClientId(tx *sql.Tx, clientId int64) ([]models.PostLogoutRedirectURI, error) {
+	return d.CommonDB.GetPostLogoutRedirectURIsByClientId(tx, clientId)
+}
+
+func (d *PostgresDB) DeletePostLogoutRedirectURI(tx *sql.Tx, postLogoutRedirectURIId int64) error {
+	return d.CommonDB.DeletePostLogoutRedirectURI(tx, postLogoutRedirectURIId)
+}
@@ -61,3 +61,15 @@ func (d *PostgresDB) RefreshTokenLoadCode(tx *sql.Tx, refreshToken *models.Refre
 func (d *PostgresDB) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 	return d.CommonDB.DeleteExpiredOrRevokedRefreshTokens(tx)
 }
+
+func (d *PostgresDB) SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error) {
+	return d.CommonDB.SupersedeRefreshToken(tx, refreshTokenId)
+}
+
+func (d *PostgresDB) RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error {
+	return d.CommonDB.RevokeRefreshTokensByFirstRefreshTokenJti(tx, firstRefreshTokenJti)
+}
+
+func (d *PostgresDB) RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error {
+	return d.CommonDB.RevokeRefreshTokensBySessionIdentifier(tx, sessionIdentifier)
+}
@@ -0,0 +1,47 @@
+package postgresdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *PostgresDB) CreateRequestObjectJti(tx *sql.Tx, requestObjectJti *models.RequestObjectJti) error {
+	now := time.Now().UTC()
+	originalCreatedAt := requestObjectJti.CreatedAt
+	originalUpdatedAt := requestObjectJti.UpdatedAt
+	requestObjectJti.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	requestObjectJti.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	requestObjectJtiStruct := sqlbuilder.NewStruct(new(models.RequestObjectJti)).For(sqlbuilder.PostgreSQL)
+	insertBuilder := requestObjectJtiStruct.WithoutTag("pk").InsertInto("request_object_jtis", requestObjectJti)
+	sql, args := insertBuilder.Build()
+	sql += " RETURNING id"
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		requestObjectJti.CreatedAt = originalCreatedAt
+		requestObjectJti.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert requestObjectJti")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&requestObjectJti.Id); err != nil {
+			requestObjectJti.CreatedAt = originalCreatedAt
+			requestObjectJti.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan requestObjectJti id")
+		}
+	}
+
+	return nil
+}
+
+func (d *PostgresDB) GetRequestObjectJti(tx *sql.Tx, clientId int64, jtiHash string) (*models.RequestObjectJti, error) {
+	return d.CommonDB.GetRequestObjectJti(tx, clientId, jtiHash)
+}
+
+func (d *PostgresDB) DeleteExpiredRequestObjectJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredRequestObjectJtis(tx)
+}
@@ -0,0 +1,47 @@
+package postgresdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+func (d *PostgresDB) CreateRevokedAccessToken(tx *sql.Tx, revokedAccessToken *models.RevokedAccessToken) error {
+	now := time.Now().UTC()
+	originalCreatedAt := revokedAccessToken.CreatedAt
+	originalUpdatedAt := revokedAccessToken.UpdatedAt
+	revokedAccessToken.CreatedAt = sql.NullTime{Time: now, Valid: true}
+	revokedAccessToken.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	revokedAccessTokenStruct := sqlbuilder.NewStruct(new(models.RevokedAccessToken)).For(sqlbuilder.PostgreSQL)
+	insertBuilder := revokedAccessTokenStruct.WithoutTag("pk").InsertInto("revoked_access_tokens", revokedAccessToken)
+	sql, args := insertBuilder.Build()
+	sql += " RETURNING id"
+	rows, err := d.CommonDB.QuerySql(tx, sql, args...)
+	if err != nil {
+		revokedAccessToken.CreatedAt = originalCreatedAt
+		revokedAccessToken.UpdatedAt = originalUpdatedAt
+		return errors.Wrap(err, "unable to insert revokedAccessToken")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&revokedAccessToken.Id); err != nil {
+			revokedAccessToken.CreatedAt = originalCreatedAt
+			revokedAccessToken.UpdatedAt = originalUpdatedAt
+			return errors.Wrap(err, "unable to scan revokedAccessToken id")
+		}
+	}
+
+	return nil
+}
+
+func (d *PostgresDB) GetRevokedAccessTokenByJti(tx *sql.Tx, jti string) (*models.RevokedAccessToken, error) {
+	return d.CommonDB.GetRevokedAccessTokenByJti(tx, jti)
+}
+
+func (d *PostgresDB) DeleteExpiredRevokedAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredRevokedAccessTokens(tx)
+}
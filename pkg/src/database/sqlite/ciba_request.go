@@ -0,0 +1,43 @@
+package sqlitedb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *SQLiteDB) CreateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CreateCIBARequest(tx, cibaRequest)
+}
+
+func (d *SQLiteDB) UpdateCIBARequest(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.UpdateCIBARequest(tx, cibaRequest)
+}
+
+func (d *SQLiteDB) UpdateCIBARequestStatus(tx *sql.Tx, cibaRequestId int64, fromStatus string, toStatus string) (bool, error) {
+	return d.CommonDB.UpdateCIBARequestStatus(tx, cibaRequestId, fromStatus, toStatus)
+}
+
+func (d *SQLiteDB) GetCIBARequestById(tx *sql.Tx, cibaRequestId int64) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestById(tx, cibaRequestId)
+}
+
+func (d *SQLiteDB) GetCIBARequestByAuthReqIdHash(tx *sql.Tx, authReqIdHash string) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestByAuthReqIdHash(tx, authReqIdHash)
+}
+
+func (d *SQLiteDB) GetCIBARequestByVerificationCodeHash(tx *sql.Tx, verificationCodeHash string) (*models.CIBARequest, error) {
+	return d.CommonDB.GetCIBARequestByVerificationCodeHash(tx, verificationCodeHash)
+}
+
+func (d *SQLiteDB) CIBARequestLoadClient(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CIBARequestLoadClient(tx, cibaRequest)
+}
+
+func (d *SQLiteDB) CIBARequestLoadUser(tx *sql.Tx, cibaRequest *models.CIBARequest) error {
+	return d.CommonDB.CIBARequestLoadUser(tx, cibaRequest)
+}
+
+func (d *SQLiteDB) DeleteExpiredCIBARequests(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredCIBARequests(tx)
+}
@@ -0,0 +1,42 @@
+package sqlitedb
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCIBARequestStatus(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	cibaRequest := &models.CIBARequest{
+		AuthReqIdHash:        "auth-req-id-hash",
+		VerificationCodeHash: "verification-code-hash",
+		ClientId:             1,
+		UserId:               1,
+		Status:               enums.CIBARequestStatusPending.String(),
+	}
+	require.NoError(t, db.CreateCIBARequest(nil, cibaRequest))
+
+	// only an approved request can be exchanged
+	updated, err := db.UpdateCIBARequestStatus(nil, cibaRequest.Id,
+		enums.CIBARequestStatusApproved.String(), enums.CIBARequestStatusUsed.String())
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	updated, err = db.UpdateCIBARequestStatus(nil, cibaRequest.Id,
+		enums.CIBARequestStatusPending.String(), enums.CIBARequestStatusApproved.String())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = db.UpdateCIBARequestStatus(nil, cibaRequest.Id,
+		enums.CIBARequestStatusApproved.String(), enums.CIBARequestStatusUsed.String())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	cibaRequest, err = db.GetCIBARequestById(nil, cibaRequest.Id)
+	require.NoError(t, err)
+	assert.Equal(t, enums.CIBARequestStatusUsed.String(), cibaRequest.Status)
+}
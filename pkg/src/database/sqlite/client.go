@@ -38,6 +38,10 @@ func (d *SQLiteDB) ClientLoadRedirectURIs(tx *sql.Tx, client *models.Client) err
 	return d.CommonDB.ClientLoadRedirectURIs(tx, client)
 }
 
+func (d *SQLiteDB) ClientLoadPostLogoutRedirectURIs(tx *sql.Tx, client *models.Client) error {
+	return d.CommonDB.ClientLoadPostLogoutRedirectURIs(tx, client)
+}
+
 func (d *SQLiteDB) ClientLoadWebOrigins(tx *sql.Tx, client *models.Client) error {
 	return d.CommonDB.ClientLoadWebOrigins(tx, client)
 }
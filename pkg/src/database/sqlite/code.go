@@ -14,6 +14,10 @@ func (d *SQLiteDB) UpdateCode(tx *sql.Tx, code *models.Code) error {
 	return d.CommonDB.UpdateCode(tx, code)
 }
 
+func (d *SQLiteDB) MarkCodeAsUsed(tx *sql.Tx, codeId int64) (bool, error) {
+	return d.CommonDB.MarkCodeAsUsed(tx, codeId)
+}
+
 func (d *SQLiteDB) GetCodeById(tx *sql.Tx, codeId int64) (*models.Code, error) {
 	return d.CommonDB.GetCodeById(tx, codeId)
 }
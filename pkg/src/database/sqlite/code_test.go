@@ -0,0 +1,34 @@
+package sqlitedb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkCodeAsUsed(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	code := &models.Code{
+		CodeHash:        "code-hash",
+		ClientId:        1,
+		UserId:          1,
+		AuthenticatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, db.CreateCode(nil, code))
+
+	used, err := db.MarkCodeAsUsed(nil, code.Id)
+	require.NoError(t, err)
+	assert.True(t, used)
+
+	// a code can only be marked as used once
+	used, err = db.MarkCodeAsUsed(nil, code.Id)
+	require.NoError(t, err)
+	assert.False(t, used)
+
+	code, err = db.GetCodeById(nil, code.Id)
+	require.NoError(t, err)
+	assert.True(t, code.Used)
+}
@@ -87,7 +87,8 @@ func NewSQLiteDB() (*SQLiteDB, error) {
 			return nil, errors.Wrapf(err, "unable to check %s status", check.name)
 		}
 
-		if value.(string) != check.expected.(string) {
+		// the driver returns integers for numeric pragmas and strings otherwise
+		if fmt.Sprint(value) != fmt.Sprint(check.expected) {
 			return nil, errors.Errorf("%s is not set correctly. Expected %v, got %v", check.name, check.expected, value)
 		}
 	}
@@ -0,0 +1,36 @@
+package sqlitedb
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateDeviceCodeStatus(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	deviceCode := &models.DeviceCode{
+		DeviceCodeHash: "device-code-hash",
+		UserCode:       "BCDFGHJK",
+		ClientId:       1,
+		Status:         enums.DeviceCodeStatusApproved.String(),
+	}
+	require.NoError(t, db.CreateDeviceCode(nil, deviceCode))
+
+	updated, err := db.UpdateDeviceCodeStatus(nil, deviceCode.Id,
+		enums.DeviceCodeStatusApproved.String(), enums.DeviceCodeStatusUsed.String())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	// the device code is no longer approved, so it can't be exchanged again
+	updated, err = db.UpdateDeviceCodeStatus(nil, deviceCode.Id,
+		enums.DeviceCodeStatusApproved.String(), enums.DeviceCodeStatusUsed.String())
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	deviceCode, err = db.GetDeviceCodeById(nil, deviceCode.Id)
+	require.NoError(t, err)
+	assert.Equal(t, enums.DeviceCodeStatusUsed.String(), deviceCode.Status)
+}
@@ -0,0 +1,19 @@
+package sqlitedb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *SQLiteDB) CreateDPoPProofJti(tx *sql.Tx, dpopProofJti *models.DPoPProofJti) error {
+	return d.CommonDB.CreateDPoPProofJti(tx, dpopProofJti)
+}
+
+func (d *SQLiteDB) GetDPoPProofJti(tx *sql.Tx, jtiHash string) (*models.DPoPProofJti, error) {
+	return d.CommonDB.GetDPoPProofJti(tx, jtiHash)
+}
+
+func (d *SQLiteDB) DeleteExpiredDPoPProofJtis(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredDPoPProofJtis(tx)
+}
@@ -0,0 +1,31 @@
+package sqlitedb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *SQLiteDB) CreateInitialAccessToken(tx *sql.Tx, initialAccessToken *models.InitialAccessToken) error {
+	return d.CommonDB.CreateInitialAccessToken(tx, initialAccessToken)
+}
+
+func (d *SQLiteDB) GetInitialAccessTokenById(tx *sql.Tx, initialAccessTokenId int64) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenById(tx, initialAccessTokenId)
+}
+
+func (d *SQLiteDB) GetInitialAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.InitialAccessToken, error) {
+	return d.CommonDB.GetInitialAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *SQLiteDB) GetAllInitialAccessTokens(tx *sql.Tx) ([]models.InitialAccessToken, error) {
+	return d.CommonDB.GetAllInitialAccessTokens(tx)
+}
+
+func (d *SQLiteDB) DeleteInitialAccessToken(tx *sql.Tx, initialAccessTokenId int64) error {
+	return d.CommonDB.DeleteInitialAccessToken(tx, initialAccessTokenId)
+}
+
+func (d *SQLiteDB) DeleteExpiredInitialAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredInitialAccessTokens(tx)
+}
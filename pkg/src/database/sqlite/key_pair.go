@@ -18,6 +18,10 @@ func (d *SQLiteDB) GetKeyPairById(tx *sql.Tx, keyPairId int64) (*models.KeyPair,
 	return d.CommonDB.GetKeyPairById(tx, keyPairId)
 }
 
+func (d *SQLiteDB) UpdateKeyPairState(tx *sql.Tx, keyPairId int64, fromState string, toState string) (bool, error) {
+	return d.CommonDB.UpdateKeyPairState(tx, keyPairId, fromState, toState)
+}
+
 func (d *SQLiteDB) GetAllSigningKeys(tx *sql.Tx) ([]models.KeyPair, error) {
 	return d.CommonDB.GetAllSigningKeys(tx)
 }
@@ -29,3 +33,7 @@ func (d *SQLiteDB) GetCurrentSigningKey(tx *sql.Tx) (*models.KeyPair, error) {
 func (d *SQLiteDB) DeleteKeyPair(tx *sql.Tx, keyPairId int64) error {
 	return d.CommonDB.DeleteKeyPair(tx, keyPairId)
 }
+
+func (d *SQLiteDB) DeleteKeyPairWithState(tx *sql.Tx, keyPairId int64, state string) (bool, error) {
+	return d.CommonDB.DeleteKeyPairWithState(tx, keyPairId, state)
+}
@@ -0,0 +1,4 @@
+-- 000003_revoked_access_tokens.down.sql
+
+DROP INDEX IF EXISTS idx_refresh_tokens_first_refresh_token_jti;
+DROP TABLE IF EXISTS revoked_access_tokens;
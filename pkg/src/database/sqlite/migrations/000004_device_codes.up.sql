@@ -0,0 +1,22 @@
+-- 000004_device_codes.up.sql
+
+ALTER TABLE clients ADD COLUMN device_code_enabled numeric NOT NULL DEFAULT 0;
+
+CREATE TABLE device_codes (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  device_code_hash TEXT NOT NULL,
+  user_code TEXT NOT NULL,
+  client_id INTEGER NOT NULL,
+  scope TEXT NOT NULL,
+  `status` TEXT NOT NULL,
+  expires_at DATETIME,
+  polling_interval_seconds INTEGER NOT NULL,
+  last_polled_at DATETIME,
+  code_id INTEGER,
+  CONSTRAINT fk_device_codes_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX `idx_device_codes_device_code_hash` ON `device_codes`(`device_code_hash`);
+CREATE UNIQUE INDEX `idx_device_codes_user_code` ON `device_codes`(`user_code`);
@@ -0,0 +1,6 @@
+-- 000005_client_registration.down.sql
+
+DROP TABLE IF EXISTS initial_access_tokens;
+ALTER TABLE settings DROP COLUMN dynamic_client_registration_open;
+ALTER TABLE settings DROP COLUMN dynamic_client_registration_enabled;
+ALTER TABLE clients DROP COLUMN registration_access_token_hash;
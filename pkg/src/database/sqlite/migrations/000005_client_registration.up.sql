@@ -0,0 +1,16 @@
+-- 000005_client_registration.up.sql
+
+ALTER TABLE clients ADD COLUMN registration_access_token_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE settings ADD COLUMN dynamic_client_registration_enabled numeric NOT NULL DEFAULT 0;
+ALTER TABLE settings ADD COLUMN dynamic_client_registration_open numeric NOT NULL DEFAULT 0;
+
+CREATE TABLE initial_access_tokens (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  token_hash TEXT NOT NULL,
+  description TEXT NOT NULL,
+  expires_at DATETIME
+);
+
+CREATE UNIQUE INDEX `idx_initial_access_tokens_token_hash` ON `initial_access_tokens`(`token_hash`);
@@ -0,0 +1,4 @@
+-- 000006_pushed_authorization_requests.down.sql
+
+DROP TABLE IF EXISTS pushed_authorization_requests;
+ALTER TABLE clients DROP COLUMN par_required;
@@ -0,0 +1,16 @@
+-- 000006_pushed_authorization_requests.up.sql
+
+ALTER TABLE clients ADD COLUMN par_required numeric NOT NULL DEFAULT 0;
+
+CREATE TABLE pushed_authorization_requests (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  request_uri_hash TEXT NOT NULL,
+  client_id INTEGER NOT NULL,
+  parameters TEXT NOT NULL,
+  expires_at DATETIME,
+  CONSTRAINT fk_pushed_authorization_requests_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX `idx_pushed_authorization_requests_request_uri_hash` ON `pushed_authorization_requests`(`request_uri_hash`);
@@ -0,0 +1,3 @@
+-- 000007_signing_key_algorithm.up.sql
+
+ALTER TABLE settings ADD COLUMN signing_key_algorithm TEXT NOT NULL DEFAULT 'RS256';
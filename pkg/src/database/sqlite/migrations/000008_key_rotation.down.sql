@@ -0,0 +1,3 @@
+-- 000008_key_rotation.down.sql
+
+ALTER TABLE settings DROP COLUMN key_rotation_interval_in_seconds;
@@ -0,0 +1,17 @@
+-- 000009_client_assertion.up.sql
+
+ALTER TABLE clients ADD COLUMN token_endpoint_auth_method TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN jwks BLOB;
+ALTER TABLE clients ADD COLUMN jwks_uri TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE client_assertion_jtis (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  client_id INTEGER NOT NULL,
+  jti_hash TEXT NOT NULL,
+  expires_at DATETIME,
+  CONSTRAINT fk_client_assertion_jtis_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX `idx_client_assertion_jtis_client_id_jti_hash` ON `client_assertion_jtis`(`client_id`, `jti_hash`);
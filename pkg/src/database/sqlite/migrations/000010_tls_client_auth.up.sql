@@ -0,0 +1,3 @@
+-- 000010_tls_client_auth.up.sql
+
+ALTER TABLE clients ADD COLUMN tls_client_auth_subject_dn TEXT NOT NULL DEFAULT '';
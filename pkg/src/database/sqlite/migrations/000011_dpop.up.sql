@@ -0,0 +1,13 @@
+-- 000011_dpop.up.sql
+
+ALTER TABLE refresh_tokens ADD COLUMN dpop_jkt TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE dpop_proof_jtis (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  jti_hash TEXT NOT NULL,
+  expires_at DATETIME
+);
+
+CREATE UNIQUE INDEX `idx_dpop_proof_jtis_jti_hash` ON `dpop_proof_jtis`(`jti_hash`);
@@ -0,0 +1,4 @@
+-- 000012_token_exchange.up.sql
+
+ALTER TABLE clients ADD COLUMN token_exchange_enabled numeric NOT NULL DEFAULT 0;
+ALTER TABLE clients ADD COLUMN token_exchange_impersonation_enabled numeric NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- 000016_pairwise_subjects.down.sql
+
+DROP TABLE IF EXISTS pairwise_subjects;
+ALTER TABLE clients DROP COLUMN sector_identifier;
+ALTER TABLE clients DROP COLUMN sector_identifier_uri;
+ALTER TABLE clients DROP COLUMN subject_type;
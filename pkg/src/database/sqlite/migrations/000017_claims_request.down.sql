@@ -0,0 +1,3 @@
+-- 000017_claims_request.down.sql
+
+ALTER TABLE codes DROP COLUMN claims;
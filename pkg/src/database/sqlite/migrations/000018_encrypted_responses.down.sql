@@ -0,0 +1,6 @@
+-- 000018_encrypted_responses.down.sql
+
+ALTER TABLE clients DROP COLUMN userinfo_encrypted_response_enc;
+ALTER TABLE clients DROP COLUMN userinfo_encrypted_response_alg;
+ALTER TABLE clients DROP COLUMN id_token_encrypted_response_enc;
+ALTER TABLE clients DROP COLUMN id_token_encrypted_response_alg;
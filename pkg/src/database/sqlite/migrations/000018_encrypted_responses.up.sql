@@ -0,0 +1,6 @@
+-- 000018_encrypted_responses.up.sql
+
+ALTER TABLE clients ADD COLUMN id_token_encrypted_response_alg TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN id_token_encrypted_response_enc TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN userinfo_encrypted_response_alg TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN userinfo_encrypted_response_enc TEXT NOT NULL DEFAULT '';
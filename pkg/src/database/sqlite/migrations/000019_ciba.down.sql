@@ -0,0 +1,6 @@
+-- 000019_ciba.down.sql
+
+DROP TABLE IF EXISTS ciba_requests;
+ALTER TABLE clients DROP COLUMN backchannel_client_notification_endpoint;
+ALTER TABLE clients DROP COLUMN backchannel_token_delivery_mode;
+ALTER TABLE clients DROP COLUMN ciba_enabled;
@@ -0,0 +1,31 @@
+-- 000019_ciba.up.sql
+
+ALTER TABLE clients ADD COLUMN ciba_enabled numeric NOT NULL DEFAULT 0;
+ALTER TABLE clients ADD COLUMN backchannel_token_delivery_mode TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN backchannel_client_notification_endpoint TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE ciba_requests (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  auth_req_id_hash TEXT NOT NULL,
+  auth_req_id_encrypted BLOB,
+  verification_code_hash TEXT NOT NULL,
+  client_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  scope TEXT NOT NULL,
+  acr_values TEXT NOT NULL,
+  binding_message TEXT NOT NULL,
+  token_delivery_mode TEXT NOT NULL,
+  client_notification_token_encrypted BLOB,
+  `status` TEXT NOT NULL,
+  expires_at DATETIME,
+  polling_interval_seconds INTEGER NOT NULL,
+  last_polled_at DATETIME,
+  code_id INTEGER,
+  CONSTRAINT fk_ciba_requests_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE,
+  CONSTRAINT fk_ciba_requests_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX `idx_ciba_requests_auth_req_id_hash` ON `ciba_requests`(`auth_req_id_hash`);
+CREATE UNIQUE INDEX `idx_ciba_requests_verification_code_hash` ON `ciba_requests`(`verification_code_hash`);
@@ -0,0 +1,18 @@
+-- 000021_opaque_access_tokens.up.sql
+
+ALTER TABLE clients ADD COLUMN access_token_format TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE opaque_access_tokens (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  token_hash TEXT NOT NULL,
+  jti TEXT NOT NULL,
+  client_id INTEGER NOT NULL,
+  claims TEXT NOT NULL,
+  expires_at DATETIME,
+  CONSTRAINT fk_opaque_access_tokens_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX `idx_opaque_access_tokens_token_hash` ON `opaque_access_tokens`(`token_hash`);
+CREATE INDEX `idx_opaque_access_tokens_expires_at` ON `opaque_access_tokens`(`expires_at`);
@@ -0,0 +1,3 @@
+-- 000022_token_exchange_subject_clients.down.sql
+
+ALTER TABLE clients DROP COLUMN token_exchange_subject_clients;
@@ -0,0 +1,4 @@
+-- 000023_authorization_encrypted_responses.up.sql
+
+ALTER TABLE clients ADD COLUMN authorization_encrypted_response_alg TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN authorization_encrypted_response_enc TEXT NOT NULL DEFAULT '';
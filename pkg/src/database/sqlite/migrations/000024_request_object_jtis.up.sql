@@ -0,0 +1,13 @@
+-- 000024_request_object_jtis.up.sql
+
+CREATE TABLE request_object_jtis (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  client_id INTEGER NOT NULL,
+  jti_hash TEXT NOT NULL,
+  expires_at DATETIME,
+  CONSTRAINT fk_request_object_jtis_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX `idx_request_object_jtis_client_id_jti_hash` ON `request_object_jtis`(`client_id`, `jti_hash`);
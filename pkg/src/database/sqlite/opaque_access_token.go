@@ -0,0 +1,19 @@
+package sqlitedb
+
+import (
+	"database/sql"
+
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+func (d *SQLiteDB) CreateOpaqueAccessToken(tx *sql.Tx, opaqueAccessToken *models.OpaqueAccessToken) error {
+	return d.CommonDB.CreateOpaqueAccessToken(tx, opaqueAccessToken)
+}
+
+func (d *SQLiteDB) GetOpaqueAccessTokenByTokenHash(tx *sql.Tx, tokenHash string) (*models.OpaqueAccessToken, error) {
+	return d.CommonDB.GetOpaqueAccessTokenByTokenHash(tx, tokenHash)
+}
+
+func (d *SQLiteDB) DeleteExpiredOpaqueAccessTokens(tx *sql.Tx) error {
+	return d.CommonDB.DeleteExpiredOpaqueAccessTokens(tx)
+}
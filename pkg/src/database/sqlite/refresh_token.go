@@ -32,3 +32,15 @@ func (d *SQLiteDB) DeleteExpiredOrRevokedRefreshTokens(tx *sql.Tx) error {
 func (d *SQLiteDB) RefreshTokenLoadCode(tx *sql.Tx, refreshToken *models.RefreshToken) error {
 	return d.CommonDB.RefreshTokenLoadCode(tx, refreshToken)
 }
+
+func (d *SQLiteDB) SupersedeRefreshToken(tx *sql.Tx, refreshTokenId int64) (bool, error) {
+	return d.CommonDB.SupersedeRefreshToken(tx, refreshTokenId)
+}
+
+func (d *SQLiteDB) RevokeRefreshTokensByFirstRefreshTokenJti(tx *sql.Tx, firstRefreshTokenJti string) error {
+	return d.CommonDB.RevokeRefreshTokensByFirstRefreshTokenJti(tx, firstRefreshTokenJti)
+}
+
+func (d *SQLiteDB) RevokeRefreshTokensBySessionIdentifier(tx *sql.Tx, sessionIdentifier string) error {
+	return d.CommonDB.RevokeRefreshTokensBySessionIdentifier(tx, sessionIdentifier)
+}
@@ -0,0 +1,85 @@
+package sqlitedb
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/pchchv/aas/pkg/src/database/commondb"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteDB(t *testing.T) *SQLiteDB {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	sqliteDb := &SQLiteDB{
+		DB:       db,
+		CommonDB: commondb.NewCommonDB(db, sqlbuilder.SQLite),
+	}
+	require.NoError(t, sqliteDb.Migrate())
+
+	return sqliteDb
+}
+
+func TestDeleteExpiredOrRevokedRefreshTokens_KeepsSupersededTokens(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	now := time.Now().UTC()
+	createRefreshToken := func(jti string, expiresAt time.Time) *models.RefreshToken {
+		refreshToken := &models.RefreshToken{
+			CodeId:               1,
+			RefreshTokenJti:      jti,
+			FirstRefreshTokenJti: "first",
+			RefreshTokenType:     "Offline",
+			ExpiresAt:            sql.NullTime{Time: expiresAt, Valid: true},
+		}
+		require.NoError(t, db.CreateRefreshToken(nil, refreshToken))
+		return refreshToken
+	}
+
+	active := createRefreshToken("active", now.Add(time.Hour))
+	superseded := createRefreshToken("superseded", now.Add(time.Hour))
+	revoked := createRefreshToken("revoked", now.Add(time.Hour))
+	expiredSuperseded := createRefreshToken("expired-superseded", now.Add(-time.Minute))
+
+	for _, refreshToken := range []*models.RefreshToken{superseded, expiredSuperseded} {
+		ok, err := db.SupersedeRefreshToken(nil, refreshToken.Id)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	// a token can only be superseded once
+	ok, err := db.SupersedeRefreshToken(nil, superseded.Id)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	revoked.Revoked = true
+	require.NoError(t, db.UpdateRefreshToken(nil, revoked))
+
+	require.NoError(t, db.DeleteExpiredOrRevokedRefreshTokens(nil))
+
+	for jti, kept := range map[string]bool{
+		active.RefreshTokenJti:            true,
+		superseded.RefreshTokenJti:        true,
+		revoked.RefreshTokenJti:           false,
+		expiredSuperseded.RefreshTokenJti: false,
+	} {
+		refreshToken, err := db.GetRefreshTokenByJti(nil, jti)
+		require.NoError(t, err)
+		assert.Equal(t, kept, refreshToken != nil, jti)
+	}
+
+	// once the chain is revoked, the superseded tokens no longer need to be kept
+	require.NoError(t, db.RevokeRefreshTokensByFirstRefreshTokenJti(nil, "first"))
+	require.NoError(t, db.DeleteExpiredOrRevokedRefreshTokens(nil))
+
+	refreshToken, err := db.GetRefreshTokenByJti(nil, superseded.RefreshTokenJti)
+	require.NoError(t, err)
+	assert.Nil(t, refreshToken)
+}
@@ -0,0 +1,408 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// key management algorithms of JSON Web Encryption (RFC 7518, section 4.1)
+const (
+	JWEAlgorithmRSAOAEP256 = "RSA-OAEP-256"
+	JWEAlgorithmA256KW     = "A256KW"
+	JWEAlgorithmDir        = "dir"
+)
+
+// content encryption algorithms of JSON Web Encryption (RFC 7518, section 5.1)
+const (
+	JWEEncryptionA128CBCHS256 = "A128CBC-HS256"
+	JWEEncryptionA256CBCHS512 = "A256CBC-HS512"
+	JWEEncryptionA128GCM      = "A128GCM"
+	JWEEncryptionA256GCM      = "A256GCM"
+)
+
+// JWEAlgorithms lists the supported key management algorithms
+var JWEAlgorithms = []string{JWEAlgorithmRSAOAEP256, JWEAlgorithmA256KW, JWEAlgorithmDir}
+
+// JWEEncryptionMethods lists the supported content encryption algorithms
+var JWEEncryptionMethods = []string{JWEEncryptionA128CBCHS256, JWEEncryptionA256CBCHS512, JWEEncryptionA128GCM, JWEEncryptionA256GCM}
+
+// gcmNonceSize is the size of the initialization vector of AES GCM (RFC 7518, section 5.3)
+const gcmNonceSize = 12
+
+// keyWrapDefaultIV is the initial value of the AES key wrap (RFC 3394, section 2.2.3.1)
+var keyWrapDefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// JWEHeader is the JOSE header of a JWE, cty is JWT for a nested JWT (RFC 7519, section 5.2)
+type JWEHeader struct {
+	Algorithm     string `json:"alg"`
+	Encryption    string `json:"enc"`
+	KeyIdentifier string `json:"kid,omitempty"`
+	ContentType   string `json:"cty,omitempty"`
+}
+
+// EncryptJWE encrypts the plaintext into a JWE in compact serialization (RFC 7516, section 7.1).
+// The key is an *rsa.PublicKey for RSA-OAEP-256, the key encryption key for A256KW
+// and the content encryption key for dir.
+func EncryptJWE(plaintext []byte, header *JWEHeader, key interface{}) (string, error) {
+	cekSize, err := getContentEncryptionKeySize(header.Encryption)
+	if err != nil {
+		return "", err
+	}
+
+	var cek, encryptedKey []byte
+	switch header.Algorithm {
+	case JWEAlgorithmRSAOAEP256:
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return "", errors.WithStack(errors.New("RSA-OAEP-256 requires an RSA public key"))
+		}
+
+		if cek, err = randomBytes(cekSize); err != nil {
+			return "", err
+		}
+
+		if encryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, cek, nil); err != nil {
+			return "", errors.Wrap(err, "unable to encrypt the content encryption key")
+		}
+	case JWEAlgorithmA256KW:
+		kek, ok := key.([]byte)
+		if !ok || len(kek) != 32 {
+			return "", errors.WithStack(errors.New("A256KW requires a 256-bit key"))
+		}
+
+		if cek, err = randomBytes(cekSize); err != nil {
+			return "", err
+		}
+
+		if encryptedKey, err = wrapKey(kek, cek); err != nil {
+			return "", err
+		}
+	case JWEAlgorithmDir:
+		var ok bool
+		if cek, ok = key.([]byte); !ok || len(cek) != cekSize {
+			return "", errors.WithStack(errors.Errorf("dir with %v requires a %v-bit key", header.Encryption, cekSize*8))
+		}
+	default:
+		return "", errors.WithStack(errors.New("unsupported JWE algorithm " + header.Algorithm))
+	}
+
+	headerJson, err := json.Marshal(header)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to serialize the JWE header")
+	}
+
+	protectedHeader := base64.RawURLEncoding.EncodeToString(headerJson)
+	iv, ciphertext, tag, err := encryptContent(header.Encryption, cek, plaintext, []byte(protectedHeader))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		protectedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// DecryptJWE decrypts a JWE in compact serialization. The key is an *rsa.PrivateKey for RSA-OAEP-256,
+// the key encryption key for A256KW and the content encryption key for dir.
+func DecryptJWE(jwe string, key interface{}) ([]byte, *JWEHeader, error) {
+	parts := strings.Split(jwe, ".")
+	if len(parts) != 5 {
+		return nil, nil, errors.WithStack(errors.New("the JWE must have 5 parts"))
+	}
+
+	decoded := make([][]byte, 5)
+	for i, part := range parts {
+		var err error
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(part); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid JWE encoding")
+		}
+	}
+
+	var header JWEHeader
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid JWE header")
+	}
+
+	cekSize, err := getContentEncryptionKeySize(header.Encryption)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cek []byte
+	switch header.Algorithm {
+	case JWEAlgorithmRSAOAEP256:
+		privateKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, nil, errors.WithStack(errors.New("RSA-OAEP-256 requires an RSA private key"))
+		}
+
+		if cek, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, decoded[1], nil); err != nil {
+			return nil, nil, errors.Wrap(err, "unable to decrypt the content encryption key")
+		}
+	case JWEAlgorithmA256KW:
+		kek, ok := key.([]byte)
+		if !ok || len(kek) != 32 {
+			return nil, nil, errors.WithStack(errors.New("A256KW requires a 256-bit key"))
+		}
+
+		if cek, err = unwrapKey(kek, decoded[1]); err != nil {
+			return nil, nil, err
+		}
+	case JWEAlgorithmDir:
+		var ok bool
+		if cek, ok = key.([]byte); !ok || len(decoded[1]) > 0 {
+			return nil, nil, errors.WithStack(errors.New("dir requires a symmetric key and no encrypted key"))
+		}
+	default:
+		return nil, nil, errors.WithStack(errors.New("unsupported JWE algorithm " + header.Algorithm))
+	}
+
+	if len(cek) != cekSize {
+		return nil, nil, errors.WithStack(errors.New("invalid content encryption key size"))
+	}
+
+	plaintext, err := decryptContent(header.Encryption, cek, decoded[2], decoded[3], decoded[4], []byte(parts[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+	return plaintext, &header, nil
+}
+
+// DeriveClientSecretKey derives the symmetric key of the algorithm from the client secret: the left-most
+// bits of its SHA-256 hash, or of its SHA-512 hash for keys over 256 bits (OpenID Connect Core, section 10.2)
+func DeriveClientSecretKey(clientSecret string, alg string, enc string) ([]byte, error) {
+	keySize := 32
+	if alg == JWEAlgorithmDir {
+		var err error
+		if keySize, err = getContentEncryptionKeySize(enc); err != nil {
+			return nil, err
+		}
+	}
+
+	if keySize > sha256.Size {
+		hash := sha512.Sum512([]byte(clientSecret))
+		return hash[:keySize], nil
+	}
+
+	hash := sha256.Sum256([]byte(clientSecret))
+	return hash[:keySize], nil
+}
+
+func getContentEncryptionKeySize(enc string) (int, error) {
+	switch enc {
+	case JWEEncryptionA128GCM:
+		return 16, nil
+	case JWEEncryptionA256GCM, JWEEncryptionA128CBCHS256:
+		return 32, nil
+	case JWEEncryptionA256CBCHS512:
+		return 64, nil
+	}
+
+	return 0, errors.WithStack(errors.New("unsupported JWE encryption " + enc))
+}
+
+// encryptContent encrypts the plaintext with a random initialization vector
+func encryptContent(enc string, cek []byte, plaintext []byte, aad []byte) (iv []byte, ciphertext []byte, tag []byte, err error) {
+	ivSize := aes.BlockSize
+	if enc == JWEEncryptionA128GCM || enc == JWEEncryptionA256GCM {
+		ivSize = gcmNonceSize
+	}
+
+	if iv, err = randomBytes(ivSize); err != nil {
+		return nil, nil, nil, err
+	}
+
+	ciphertext, tag, err = encryptContentWithIV(enc, cek, iv, plaintext, aad)
+	return iv, ciphertext, tag, err
+}
+
+// encryptContentWithIV encrypts the plaintext and authenticates it with the additional data,
+// which is the encoded protected header (RFC 7516, section 5.1)
+func encryptContentWithIV(enc string, cek []byte, iv []byte, plaintext []byte, aad []byte) (ciphertext []byte, tag []byte, err error) {
+	switch enc {
+	case JWEEncryptionA128GCM, JWEEncryptionA256GCM:
+		gcm, err := newGCM(cek)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sealed := gcm.Seal(nil, iv, plaintext, aad)
+		tagStart := len(sealed) - gcm.Overhead()
+		return sealed[:tagStart], sealed[tagStart:], nil
+	case JWEEncryptionA128CBCHS256, JWEEncryptionA256CBCHS512:
+		macKey, encKey, hashFunc := splitCBCHMACKey(enc, cek)
+		block, err := aes.NewCipher(encKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// PKCS #7 padding (RFC 7518, section 5.2.2.1)
+		padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+		padded := append(append([]byte{}, plaintext...), make([]byte, padding)...)
+		for i := len(plaintext); i < len(padded); i++ {
+			padded[i] = byte(padding)
+		}
+
+		ciphertext = make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+		return ciphertext, cbcHMACTag(hashFunc, macKey, aad, iv, ciphertext), nil
+	}
+
+	return nil, nil, errors.WithStack(errors.New("unsupported JWE encryption " + enc))
+}
+
+func decryptContent(enc string, cek []byte, iv []byte, ciphertext []byte, tag []byte, aad []byte) ([]byte, error) {
+	switch enc {
+	case JWEEncryptionA128GCM, JWEEncryptionA256GCM:
+		gcm, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		} else if len(iv) != gcm.NonceSize() {
+			return nil, errors.WithStack(errors.New("invalid JWE initialization vector"))
+		}
+
+		plaintext, err := gcm.Open(nil, iv, append(append([]byte{}, ciphertext...), tag...), aad)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to decrypt the JWE")
+		}
+		return plaintext, nil
+	case JWEEncryptionA128CBCHS256, JWEEncryptionA256CBCHS512:
+		macKey, encKey, hashFunc := splitCBCHMACKey(enc, cek)
+		if subtle.ConstantTimeCompare(tag, cbcHMACTag(hashFunc, macKey, aad, iv, ciphertext)) != 1 {
+			return nil, errors.WithStack(errors.New("invalid JWE authentication tag"))
+		}
+
+		block, err := aes.NewCipher(encKey)
+		if err != nil {
+			return nil, err
+		} else if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, errors.WithStack(errors.New("invalid JWE ciphertext"))
+		}
+
+		plaintext := make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+		padding := int(plaintext[len(plaintext)-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, errors.WithStack(errors.New("invalid JWE padding"))
+		}
+		return plaintext[:len(plaintext)-padding], nil
+	}
+
+	return nil, errors.WithStack(errors.New("unsupported JWE encryption " + enc))
+}
+
+// splitCBCHMACKey splits the content encryption key into the MAC key and the encryption key (RFC 7518, section 5.2.2.1)
+func splitCBCHMACKey(enc string, cek []byte) ([]byte, []byte, func() hash.Hash) {
+	hashFunc := sha256.New
+	if enc == JWEEncryptionA256CBCHS512 {
+		hashFunc = sha512.New
+	}
+	return cek[:len(cek)/2], cek[len(cek)/2:], hashFunc
+}
+
+// cbcHMACTag is the first half of the HMAC of the additional data, the initialization vector,
+// the ciphertext and the bit length of the additional data (RFC 7518, section 5.2.2.1)
+func cbcHMACTag(hashFunc func() hash.Hash, macKey []byte, aad []byte, iv []byte, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(hashFunc, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+	return mac.Sum(nil)[:len(macKey)]
+}
+
+// wrapKey wraps the key with the AES key wrap algorithm (RFC 3394, section 2.2.1)
+func wrapKey(kek []byte, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(key) / 8
+	a := append([]byte{}, keyWrapDefaultIV...)
+	r := append([]byte{}, key...)
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 0; i < n; i++ {
+			copy(b, a)
+			copy(b[8:], r[i*8:(i+1)*8])
+			block.Encrypt(b, b)
+
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:8])^t)
+			copy(r[i*8:], b[8:])
+		}
+	}
+
+	return append(a, r...), nil
+}
+
+// unwrapKey unwraps a key wrapped with the AES key wrap algorithm (RFC 3394, section 2.2.2)
+func unwrapKey(kek []byte, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, errors.WithStack(errors.New("invalid wrapped key"))
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	a := append([]byte{}, wrapped[:8]...)
+	r := append([]byte{}, wrapped[8:]...)
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(b, binary.BigEndian.Uint64(a)^t)
+			copy(b[8:], r[i*8:(i+1)*8])
+			block.Decrypt(b, b)
+
+			copy(a, b[:8])
+			copy(r[i*8:], b[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, keyWrapDefaultIV) != 1 {
+		return nil, errors.WithStack(errors.New("unable to unwrap the key"))
+	}
+	return r, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func randomBytes(size int) ([]byte, error) {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -6,31 +6,60 @@ const (
 	TokenTypeId TokenType = iota
 	TokenTypeBearer
 	TokenTypeRefresh
+	TokenTypeDPoP
+)
 
+const (
 	AcrLevel1          AcrLevel = "urn:goiabada:level1"
 	AcrLevel2Optional  AcrLevel = "urn:goiabada:level2_optional"
 	AcrLevel2Mandatory AcrLevel = "urn:goiabada:level2_mandatory"
+)
 
+const (
 	PasswordPolicyNone   PasswordPolicy = iota // at least 1 char
 	PasswordPolicyLow                          // at least 6 chars
 	PasswordPolicyMedium                       // at least 8 chars. Must contain: 1 uppercase, 1 lowercase and 1 number
 	PasswordPolicyHigh                         // at least 10 chars. Must contain: 1 uppercase, 1 lowercase, 1 number and 1 special character/symbol
+)
 
+const (
 	KeyStateCurrent KeyState = iota
 	KeyStatePrevious
 	KeyStateNext
+)
 
+const (
 	ThreeStateSettingOn ThreeStateSetting = iota
 	ThreeStateSettingOff
 	ThreeStateSettingDefault
+)
 
+const (
 	AuthMethodPassword AuthMethod = iota
 	AuthMethodOTP
+)
 
+const (
 	SMTPEncryptionNone SMTPEncryption = iota
 	SMTPEncryptionSSLTLS
 	SMTPEncryptionSTARTTLS
+)
 
+const (
+	DeviceCodeStatusPending DeviceCodeStatus = iota
+	DeviceCodeStatusApproved
+	DeviceCodeStatusDenied
+	DeviceCodeStatusUsed
+)
+
+const (
+	CIBARequestStatusPending CIBARequestStatus = iota
+	CIBARequestStatusApproved
+	CIBARequestStatusDenied
+	CIBARequestStatusUsed
+)
+
+const (
 	GenderFemale Gender = iota
 	GenderMale
 	GenderOther
@@ -39,7 +68,7 @@ const (
 type TokenType int
 
 func (tt TokenType) String() string {
-	return []string{"ID", "Bearer", "Refresh"}[tt]
+	return []string{"ID", "Bearer", "Refresh", "DPoP"}[tt]
 }
 
 type AcrLevel string
@@ -78,6 +107,18 @@ func (se SMTPEncryption) String() string {
 	return []string{"none", "ssltls", "starttls"}[se]
 }
 
+type DeviceCodeStatus int
+
+func (dcs DeviceCodeStatus) String() string {
+	return []string{"pending", "approved", "denied", "used"}[dcs]
+}
+
+type CIBARequestStatus int
+
+func (crs CIBARequestStatus) String() string {
+	return []string{"pending", "approved", "denied", "used"}[crs]
+}
+
 type Gender int
 
 func (g Gender) String() string {
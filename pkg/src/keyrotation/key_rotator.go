@@ -0,0 +1,238 @@
+package keyrotation
+
+import (
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+// KeyRotator moves the signing keys through their states. The next key is
+// published in the JWKS ahead of time, promoted to current on rotation and then
+// kept as previous until every token it signed has expired.
+type KeyRotator struct {
+	database database.Database
+}
+
+func NewKeyRotator(database database.Database) *KeyRotator {
+	return &KeyRotator{
+		database: database,
+	}
+}
+
+// Rotate promotes the next key to current, demotes the current key to previous and
+// publishes a new next key using the algorithm. Older previous keys stay published
+// until RunScheduledRotation retires them, as tokens they signed can still be valid.
+// It returns false when another server instance rotated the keys first.
+func (kr *KeyRotator) Rotate(algorithm string) (bool, error) {
+	keyPairs, err := kr.database.GetAllSigningKeys(nil)
+	if err != nil {
+		return false, err
+	}
+
+	currentKeyPair := findKeyPair(keyPairs, enums.KeyStateCurrent)
+	nextKeyPair := findKeyPair(keyPairs, enums.KeyStateNext)
+	if currentKeyPair == nil || nextKeyPair == nil {
+		return false, errors.WithStack(errors.New("unable to rotate the signing keys: a current and a next key are required"))
+	}
+
+	newNextKeyPair, err := keyutil.GenerateKeyPair(algorithm)
+	if err != nil {
+		return false, err
+	}
+	newNextKeyPair.State = enums.KeyStateNext.String()
+
+	tx, err := kr.database.BeginTransaction()
+	if err != nil {
+		return false, err
+	}
+	defer kr.database.RollbackTransaction(tx) //nolint:errcheck
+
+	// both state changes are conditional, so when instances race only one of them
+	// gets to move the keys and the others roll back
+	updated, err := kr.database.UpdateKeyPairState(tx, currentKeyPair.Id, enums.KeyStateCurrent.String(), enums.KeyStatePrevious.String())
+	if err != nil || !updated {
+		return false, err
+	}
+
+	updated, err = kr.database.UpdateKeyPairState(tx, nextKeyPair.Id, enums.KeyStateNext.String(), enums.KeyStateCurrent.String())
+	if err != nil || !updated {
+		return false, err
+	}
+
+	if err = kr.database.CreateKeyPair(tx, newNextKeyPair); err != nil {
+		return false, err
+	}
+
+	if err = kr.database.CommitTransaction(tx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RunScheduledRotation retires the previous keys that can no longer have valid tokens
+// and rotates the keys once the current key has been in use for the rotation interval.
+// Several previous keys can be kept at once, so the rotation interval can be shorter
+// than the lifetime of the tokens: the retention only decides when a key is deleted.
+func (kr *KeyRotator) RunScheduledRotation(settings *models.Settings) (bool, error) {
+	keyPairs, err := kr.database.GetAllSigningKeys(nil)
+	if err != nil {
+		return false, err
+	}
+
+	maxTokenLifetime, err := kr.getMaxTokenLifetime(settings)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC()
+	for _, keyPair := range keyPairs {
+		if keyPair.State != enums.KeyStatePrevious.String() ||
+			now.Before(getStateChangedAt(&keyPair).Add(maxTokenLifetime)) {
+			continue
+		}
+
+		if err = kr.database.DeleteKeyPair(nil, keyPair.Id); err != nil {
+			return false, err
+		}
+	}
+
+	if settings.KeyRotationIntervalInSeconds <= 0 {
+		return false, nil
+	}
+
+	currentKeyPair := findKeyPair(keyPairs, enums.KeyStateCurrent)
+	if currentKeyPair == nil {
+		return false, nil
+	}
+
+	rotationInterval := time.Duration(settings.KeyRotationIntervalInSeconds) * time.Second
+	if now.Before(getStateChangedAt(currentKeyPair).Add(rotationInterval)) {
+		return false, nil
+	}
+
+	return kr.Rotate(settings.SigningKeyAlgorithm)
+}
+
+// ReplaceNextKey swaps the next signing key for one using the algorithm.
+// The current key keeps signing until the next key is promoted, so tokens
+// already issued and clients that cached the JWKS are not affected.
+// It returns false when the next key was changed by another request first.
+func (kr *KeyRotator) ReplaceNextKey(algorithm string) (bool, error) {
+	keyPairs, err := kr.database.GetAllSigningKeys(nil)
+	if err != nil {
+		return false, err
+	}
+
+	nextKeyPair := findKeyPair(keyPairs, enums.KeyStateNext)
+	if nextKeyPair == nil {
+		return false, errors.WithStack(errors.New("unable to replace the next signing key: a next key is required"))
+	}
+
+	return kr.replaceNextKey(nextKeyPair, algorithm)
+}
+
+// RevokeKey removes a previous or next key from the JWKS, so tokens signed with it
+// stop being accepted. A revoked next key is replaced with a new one using the algorithm.
+// The current key cannot be revoked, it has to be rotated out first.
+// It returns false when the key changed state or was removed by another request first.
+func (kr *KeyRotator) RevokeKey(keyPair *models.KeyPair, algorithm string) (bool, error) {
+	switch keyPair.State {
+	case enums.KeyStatePrevious.String():
+		return kr.database.DeleteKeyPairWithState(nil, keyPair.Id, enums.KeyStatePrevious.String())
+	case enums.KeyStateNext.String():
+		return kr.replaceNextKey(keyPair, algorithm)
+	default:
+		return false, errors.WithStack(errors.New("unable to revoke the current signing key"))
+	}
+}
+
+func (kr *KeyRotator) replaceNextKey(nextKeyPair *models.KeyPair, algorithm string) (bool, error) {
+	newNextKeyPair, err := keyutil.GenerateKeyPair(algorithm)
+	if err != nil {
+		return false, err
+	}
+	newNextKeyPair.State = enums.KeyStateNext.String()
+
+	tx, err := kr.database.BeginTransaction()
+	if err != nil {
+		return false, err
+	}
+	defer kr.database.RollbackTransaction(tx) //nolint:errcheck
+
+	// the delete is conditional, so when the next key was promoted or replaced
+	// in the meantime no second next key is created
+	deleted, err := kr.database.DeleteKeyPairWithState(tx, nextKeyPair.Id, enums.KeyStateNext.String())
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	if err = kr.database.CreateKeyPair(tx, newNextKeyPair); err != nil {
+		return false, err
+	}
+
+	if err = kr.database.CommitTransaction(tx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getMaxTokenLifetime returns the longest time a token signed now can remain valid,
+// taking into account the token lifetimes overridden by clients. A refresh token is
+// signed again every time it is used, so only its expiration counts and not the
+// max lifetime of the offline session. Its signature is verified before it is looked up,
+// so the key has to be kept for as long as the refresh token can be used.
+func (kr *KeyRotator) getMaxTokenLifetime(settings *models.Settings) (time.Duration, error) {
+	clients, err := kr.database.GetAllClients(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	maxLifetimeInSeconds := max(
+		settings.TokenExpirationInSeconds,
+		min(settings.RefreshTokenOfflineIdleTimeoutInSeconds, settings.RefreshTokenOfflineMaxLifetimeInSeconds),
+		min(settings.UserSessionIdleTimeoutInSeconds, settings.UserSessionMaxLifetimeInSeconds),
+	)
+	for _, client := range clients {
+		offlineIdleTimeoutInSeconds := settings.RefreshTokenOfflineIdleTimeoutInSeconds
+		if client.RefreshTokenOfflineIdleTimeoutInSeconds > 0 {
+			offlineIdleTimeoutInSeconds = client.RefreshTokenOfflineIdleTimeoutInSeconds
+		}
+
+		offlineMaxLifetimeInSeconds := settings.RefreshTokenOfflineMaxLifetimeInSeconds
+		if client.RefreshTokenOfflineMaxLifetimeInSeconds > 0 {
+			offlineMaxLifetimeInSeconds = client.RefreshTokenOfflineMaxLifetimeInSeconds
+		}
+
+		maxLifetimeInSeconds = max(
+			maxLifetimeInSeconds,
+			client.TokenExpirationInSeconds,
+			min(offlineIdleTimeoutInSeconds, offlineMaxLifetimeInSeconds),
+		)
+	}
+
+	return time.Duration(maxLifetimeInSeconds) * time.Second, nil
+}
+
+func findKeyPair(keyPairs []models.KeyPair, state enums.KeyState) *models.KeyPair {
+	for idx := range keyPairs {
+		if keyPairs[idx].State == state.String() {
+			return &keyPairs[idx]
+		}
+	}
+	return nil
+}
+
+// getStateChangedAt returns when the key pair entered its current state.
+// Rotation updates the key pairs, so updated_at tracks the last state change.
+func getStateChangedAt(keyPair *models.KeyPair) time.Time {
+	if keyPair.UpdatedAt.Valid {
+		return keyPair.UpdatedAt.Time
+	}
+	return keyPair.CreatedAt.Time
+}
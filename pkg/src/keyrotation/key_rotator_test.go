@@ -0,0 +1,327 @@
+package keyrotation
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newKeyPair(id int64, state enums.KeyState, stateChangedAt time.Time) models.KeyPair {
+	return models.KeyPair{
+		Id:        id,
+		State:     state.String(),
+		CreatedAt: sql.NullTime{Time: stateChangedAt, Valid: true},
+		UpdatedAt: sql.NullTime{Time: stateChangedAt, Valid: true},
+	}
+}
+
+func TestRotate(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(1, enums.KeyStatePrevious, time.Now()),
+		newKeyPair(2, enums.KeyStateCurrent, time.Now()),
+		newKeyPair(3, enums.KeyStateNext, time.Now()),
+	}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("UpdateKeyPairState", tx, int64(2), enums.KeyStateCurrent.String(), enums.KeyStatePrevious.String()).Return(true, nil)
+	database.On("UpdateKeyPairState", tx, int64(3), enums.KeyStateNext.String(), enums.KeyStateCurrent.String()).Return(true, nil)
+	database.On("CreateKeyPair", tx, mock.MatchedBy(func(keyPair *models.KeyPair) bool {
+		return keyPair.State == enums.KeyStateNext.String() && keyPair.Algorithm == keyutil.AlgorithmES256
+	})).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	rotated, err := NewKeyRotator(database).Rotate(keyutil.AlgorithmES256)
+	assert.NoError(t, err)
+	assert.True(t, rotated)
+	// the older previous key can still verify tokens, the scheduled rotation retires it
+	database.AssertNotCalled(t, "DeleteKeyPair", mock.Anything, mock.Anything)
+}
+
+func TestRotate_AlreadyRotatedByAnotherInstance(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(2, enums.KeyStateCurrent, time.Now()),
+		newKeyPair(3, enums.KeyStateNext, time.Now()),
+	}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("UpdateKeyPairState", tx, int64(2), enums.KeyStateCurrent.String(), enums.KeyStatePrevious.String()).Return(false, nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	rotated, err := NewKeyRotator(database).Rotate(keyutil.AlgorithmRS256)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	database.AssertNotCalled(t, "CreateKeyPair", mock.Anything, mock.Anything)
+	database.AssertNotCalled(t, "CommitTransaction", mock.Anything)
+}
+
+func TestRotate_MissingNextKey(t *testing.T) {
+	database := mocks.NewDatabase(t)
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(2, enums.KeyStateCurrent, time.Now()),
+	}, nil)
+
+	rotated, err := NewKeyRotator(database).Rotate(keyutil.AlgorithmRS256)
+	assert.Error(t, err)
+	assert.False(t, rotated)
+	database.AssertNotCalled(t, "BeginTransaction")
+}
+
+func TestRunScheduledRotation_RetiresExpiredPreviousKey(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	settings := &models.Settings{
+		TokenExpirationInSeconds:                300,
+		RefreshTokenOfflineMaxLifetimeInSeconds: 3600,
+		UserSessionMaxLifetimeInSeconds:         7200,
+	}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(1, enums.KeyStatePrevious, time.Now().Add(-3*time.Hour)),
+		newKeyPair(2, enums.KeyStateCurrent, time.Now().Add(-3*time.Hour)),
+		newKeyPair(3, enums.KeyStateNext, time.Now().Add(-3*time.Hour)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{}, nil)
+	database.On("DeleteKeyPair", mock.Anything, int64(1)).Return(nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+}
+
+func TestRunScheduledRotation_RetiresOnlyExpiredPreviousKeys(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	settings := &models.Settings{
+		TokenExpirationInSeconds: 3600,
+	}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(1, enums.KeyStatePrevious, time.Now().Add(-2*time.Hour)),
+		newKeyPair(2, enums.KeyStatePrevious, time.Now().Add(-time.Minute)),
+		newKeyPair(3, enums.KeyStateCurrent, time.Now().Add(-time.Minute)),
+		newKeyPair(4, enums.KeyStateNext, time.Now().Add(-time.Minute)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{}, nil)
+	database.On("DeleteKeyPair", mock.Anything, int64(1)).Return(nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	database.AssertNotCalled(t, "DeleteKeyPair", mock.Anything, int64(2))
+}
+
+func TestRunScheduledRotation_IntervalShorterThanOfflineIdleTimeout(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+	settings := &models.Settings{
+		TokenExpirationInSeconds:                300,
+		RefreshTokenOfflineIdleTimeoutInSeconds: 2592000,
+		RefreshTokenOfflineMaxLifetimeInSeconds: 31536000,
+		KeyRotationIntervalInSeconds:            86400,
+		SigningKeyAlgorithm:                     keyutil.AlgorithmES256,
+	}
+
+	// the previous key can still verify offline refresh tokens, it is kept
+	// without holding back the rotation of the current key
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(1, enums.KeyStatePrevious, time.Now().Add(-25*time.Hour)),
+		newKeyPair(2, enums.KeyStateCurrent, time.Now().Add(-25*time.Hour)),
+		newKeyPair(3, enums.KeyStateNext, time.Now().Add(-25*time.Hour)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("UpdateKeyPairState", tx, int64(2), enums.KeyStateCurrent.String(), enums.KeyStatePrevious.String()).Return(true, nil)
+	database.On("UpdateKeyPairState", tx, int64(3), enums.KeyStateNext.String(), enums.KeyStateCurrent.String()).Return(true, nil)
+	database.On("CreateKeyPair", tx, mock.Anything).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.True(t, rotated)
+	database.AssertNotCalled(t, "DeleteKeyPair", mock.Anything, mock.Anything)
+}
+
+func TestRunScheduledRotation_KeepsPreviousKeyUsedByClientTokens(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	settings := &models.Settings{
+		TokenExpirationInSeconds: 300,
+	}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(1, enums.KeyStatePrevious, time.Now().Add(-2*time.Hour)),
+		newKeyPair(2, enums.KeyStateCurrent, time.Now().Add(-2*time.Hour)),
+		newKeyPair(3, enums.KeyStateNext, time.Now().Add(-2*time.Hour)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{
+		{Id: 1, RefreshTokenOfflineIdleTimeoutInSeconds: 86400, RefreshTokenOfflineMaxLifetimeInSeconds: 86400},
+	}, nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	database.AssertNotCalled(t, "DeleteKeyPair", mock.Anything, mock.Anything)
+}
+
+func TestRunScheduledRotation_IgnoresOfflineMaxLifetime(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	settings := &models.Settings{
+		TokenExpirationInSeconds:                300,
+		RefreshTokenOfflineIdleTimeoutInSeconds: 3600,
+		RefreshTokenOfflineMaxLifetimeInSeconds: 31536000,
+		UserSessionIdleTimeoutInSeconds:         3600,
+		UserSessionMaxLifetimeInSeconds:         86400,
+	}
+
+	// refresh tokens are signed again when used, so they expire with the idle timeout
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(1, enums.KeyStatePrevious, time.Now().Add(-2*time.Hour)),
+		newKeyPair(2, enums.KeyStateCurrent, time.Now().Add(-2*time.Hour)),
+		newKeyPair(3, enums.KeyStateNext, time.Now().Add(-2*time.Hour)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{
+		{Id: 1, RefreshTokenOfflineMaxLifetimeInSeconds: 63072000},
+	}, nil)
+	database.On("DeleteKeyPair", mock.Anything, int64(1)).Return(nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+}
+
+func TestRunScheduledRotation_RotatesWhenDue(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+	settings := &models.Settings{
+		TokenExpirationInSeconds:     300,
+		KeyRotationIntervalInSeconds: 86400,
+		SigningKeyAlgorithm:          keyutil.AlgorithmEdDSA,
+	}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(2, enums.KeyStateCurrent, time.Now().Add(-25*time.Hour)),
+		newKeyPair(3, enums.KeyStateNext, time.Now().Add(-25*time.Hour)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("UpdateKeyPairState", tx, int64(2), enums.KeyStateCurrent.String(), enums.KeyStatePrevious.String()).Return(true, nil)
+	database.On("UpdateKeyPairState", tx, int64(3), enums.KeyStateNext.String(), enums.KeyStateCurrent.String()).Return(true, nil)
+	database.On("CreateKeyPair", tx, mock.MatchedBy(func(keyPair *models.KeyPair) bool {
+		return keyPair.Algorithm == keyutil.AlgorithmEdDSA
+	})).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.True(t, rotated)
+}
+
+func TestRunScheduledRotation_NotDue(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	settings := &models.Settings{
+		TokenExpirationInSeconds:     300,
+		KeyRotationIntervalInSeconds: 86400,
+	}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(2, enums.KeyStateCurrent, time.Now().Add(-time.Hour)),
+		newKeyPair(3, enums.KeyStateNext, time.Now().Add(-time.Hour)),
+	}, nil)
+	database.On("GetAllClients", mock.Anything).Return([]models.Client{}, nil)
+
+	rotated, err := NewKeyRotator(database).RunScheduledRotation(settings)
+	assert.NoError(t, err)
+	assert.False(t, rotated)
+	database.AssertNotCalled(t, "BeginTransaction")
+}
+
+func TestReplaceNextKey(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(2, enums.KeyStateCurrent, time.Now()),
+		newKeyPair(3, enums.KeyStateNext, time.Now()),
+	}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("DeleteKeyPairWithState", tx, int64(3), enums.KeyStateNext.String()).Return(true, nil)
+	database.On("CreateKeyPair", tx, mock.MatchedBy(func(keyPair *models.KeyPair) bool {
+		return keyPair.State == enums.KeyStateNext.String() && keyPair.Algorithm == keyutil.AlgorithmES256
+	})).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	replaced, err := NewKeyRotator(database).ReplaceNextKey(keyutil.AlgorithmES256)
+	assert.NoError(t, err)
+	assert.True(t, replaced)
+}
+
+func TestReplaceNextKey_ChangedByAnotherRequest(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+
+	database.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		newKeyPair(2, enums.KeyStateCurrent, time.Now()),
+		newKeyPair(3, enums.KeyStateNext, time.Now()),
+	}, nil)
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("DeleteKeyPairWithState", tx, int64(3), enums.KeyStateNext.String()).Return(false, nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	replaced, err := NewKeyRotator(database).ReplaceNextKey(keyutil.AlgorithmES256)
+	assert.NoError(t, err)
+	assert.False(t, replaced)
+	database.AssertNotCalled(t, "CreateKeyPair", mock.Anything, mock.Anything)
+	database.AssertNotCalled(t, "CommitTransaction", mock.Anything)
+}
+
+func TestRevokeKey_NextKeyIsReplaced(t *testing.T) {
+	database := mocks.NewDatabase(t)
+	tx := &sql.Tx{}
+
+	database.On("BeginTransaction").Return(tx, nil)
+	database.On("DeleteKeyPairWithState", tx, int64(3), enums.KeyStateNext.String()).Return(true, nil)
+	database.On("CreateKeyPair", tx, mock.MatchedBy(func(keyPair *models.KeyPair) bool {
+		return keyPair.State == enums.KeyStateNext.String()
+	})).Return(nil)
+	database.On("CommitTransaction", tx).Return(nil)
+	database.On("RollbackTransaction", tx).Return(nil)
+
+	nextKeyPair := newKeyPair(3, enums.KeyStateNext, time.Now())
+	revoked, err := NewKeyRotator(database).RevokeKey(&nextKeyPair, keyutil.AlgorithmRS256)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRevokeKey_PreviousKeyAlreadyRetired(t *testing.T) {
+	database := mocks.NewDatabase(t)
+
+	database.On("DeleteKeyPairWithState", mock.Anything, int64(1), enums.KeyStatePrevious.String()).Return(false, nil)
+
+	previousKeyPair := newKeyPair(1, enums.KeyStatePrevious, time.Now())
+	revoked, err := NewKeyRotator(database).RevokeKey(&previousKeyPair, keyutil.AlgorithmRS256)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestRevokeKey_CurrentKey(t *testing.T) {
+	database := mocks.NewDatabase(t)
+
+	currentKeyPair := newKeyPair(2, enums.KeyStateCurrent, time.Now())
+	revoked, err := NewKeyRotator(database).RevokeKey(&currentKeyPair, keyutil.AlgorithmRS256)
+	assert.Error(t, err)
+	assert.False(t, revoked)
+	database.AssertNotCalled(t, "DeleteKeyPairWithState", mock.Anything, mock.Anything, mock.Anything)
+}
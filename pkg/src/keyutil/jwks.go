@@ -0,0 +1,234 @@
+package keyutil
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// PublicJWK is a verification key read from a JSON Web Key Set
+type PublicJWK struct {
+	KeyIdentifier string
+	Algorithm     string
+	Key           crypto.PublicKey
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	D   string `json:"d"`
+}
+
+// ParseJWKS returns the signature verification keys of a JSON Web Key Set (RFC 7517, section 5).
+// Encryption keys and key types that can't verify signatures are skipped.
+func ParseJWKS(jwks []byte) ([]PublicJWK, error) {
+	return parseJWKS(jwks, "sig")
+}
+
+// ParseEncryptionJWKS returns the encryption keys of a JSON Web Key Set,
+// keys without a use member serve both purposes (RFC 7517, section 4.2)
+func ParseEncryptionJWKS(jwks []byte) ([]PublicJWK, error) {
+	return parseJWKS(jwks, "enc")
+}
+
+// SelectRSAEncryptionKey returns the first RSA key usable with the key management algorithm,
+// the key has no alg member or names that algorithm, nil when there is none
+func SelectRSAEncryptionKey(keys []PublicJWK, algorithm string) *PublicJWK {
+	for i, key := range keys {
+		if _, ok := key.Key.(*rsa.PublicKey); !ok {
+			continue
+		} else if len(key.Algorithm) == 0 || key.Algorithm == algorithm {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+func parseJWKS(jwks []byte, use string) ([]PublicJWK, error) {
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(jwks, &keySet); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the JWKS")
+	}
+
+	keys := make([]PublicJWK, 0, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if len(jwk.Use) > 0 && jwk.Use != use {
+			continue
+		}
+
+		key, err := parseJWK(&jwk)
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			continue
+		}
+
+		keys = append(keys, PublicJWK{
+			KeyIdentifier: jwk.Kid,
+			Algorithm:     jwk.Alg,
+			Key:           key,
+		})
+	}
+
+	return keys, nil
+}
+
+// ParsePublicJWK parses a single public JSON Web Key and returns the key with its
+// RFC 7638 thumbprint. Keys holding private key parameters are rejected.
+func ParsePublicJWK(publicJWK []byte) (crypto.PublicKey, string, error) {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(publicJWK, &jwk); err != nil {
+		return nil, "", errors.Wrap(err, "unable to parse the JWK")
+	} else if len(jwk.D) > 0 {
+		return nil, "", errors.WithStack(errors.New("the JWK must not contain a private key"))
+	}
+
+	key, err := parseJWK(&jwk)
+	if err != nil {
+		return nil, "", err
+	} else if key == nil {
+		return nil, "", errors.WithStack(errors.New("unsupported key type in the JWK: " + jwk.Kty))
+	}
+
+	thumbprint, err := jwkThumbprint(&jwk)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, thumbprint, nil
+}
+
+// jwkThumbprint returns the base64url-encoded SHA-256 hash of the required members
+// of the key, serialized in lexicographic order (RFC 7638, section 3)
+func jwkThumbprint(jwk *jsonWebKey) (string, error) {
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	serialized, err := json.Marshal(members)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to serialize the JWK")
+	}
+
+	hash := sha256.Sum256(serialized)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+func parseJWK(jwk *jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeJWKParameter(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeJWKParameter(jwk.E)
+		if err != nil {
+			return nil, err
+		} else if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.WithStack(errors.New("invalid RSA key in the JWKS"))
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		default:
+			return nil, errors.WithStack(errors.New("unsupported elliptic curve in the JWKS: " + jwk.Crv))
+		}
+
+		x, err := decodeJWKParameter(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeJWKParameter(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		// the coordinates are padded to the size of the curve (RFC 7518, section 6.2.1.2)
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.WithStack(errors.New("invalid EC key in the JWKS"))
+		}
+
+		// crypto/ecdh checks that the point is on the curve
+		point := append([]byte{4}, append(x, y...)...)
+		if _, err = ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, errors.Wrap(err, "invalid EC key in the JWKS")
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, errors.WithStack(errors.New("unsupported curve in the JWKS: " + jwk.Crv))
+		}
+
+		x, err := decodeJWKParameter(jwk.X)
+		if err != nil {
+			return nil, err
+		} else if len(x) != ed25519.PublicKeySize {
+			return nil, errors.WithStack(errors.New("invalid Ed25519 key in the JWKS"))
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, nil
+}
+
+func decodeJWKParameter(value string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base64url value in the JWKS")
+	}
+	return decoded, nil
+}
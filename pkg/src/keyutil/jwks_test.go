@@ -0,0 +1,131 @@
+package keyutil
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJWKS(t *testing.T) {
+	for _, algorithm := range SupportedAlgorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			keyPair, err := GenerateKeyPair(algorithm)
+			require.NoError(t, err)
+
+			jwks := []byte(`{"keys":[` + string(keyPair.PublicKeyJWK) + `]}`)
+			publicJWKs, err := ParseJWKS(jwks)
+			require.NoError(t, err)
+			require.Len(t, publicJWKs, 1)
+			assert.Equal(t, keyPair.KeyIdentifier, publicJWKs[0].KeyIdentifier)
+			assert.Equal(t, algorithm, publicJWKs[0].Algorithm)
+
+			publicKey, err := ParsePublicKeyFromPEM(keyPair.PublicKeyPEM)
+			require.NoError(t, err)
+			assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(publicJWKs[0].Key))
+		})
+	}
+}
+
+func TestParseJWKS_SkipsNonSignatureKeys(t *testing.T) {
+	jwks := []byte(`{"keys":[
+		{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"},
+		{"kty":"oct","k":"c2VjcmV0"}
+	]}`)
+
+	publicJWKs, err := ParseJWKS(jwks)
+	assert.NoError(t, err)
+	assert.Empty(t, publicJWKs)
+}
+
+func TestParseEncryptionJWKS(t *testing.T) {
+	jwks := []byte(`{"keys":[
+		{"kty":"RSA","kid":"enc","use":"enc","alg":"RSA-OAEP-256","n":"AQAB","e":"AQAB"},
+		{"kty":"RSA","kid":"sig","use":"sig","n":"AQAB","e":"AQAB"},
+		{"kty":"RSA","kid":"any","n":"AQAB","e":"AQAB"}
+	]}`)
+
+	publicJWKs, err := ParseEncryptionJWKS(jwks)
+	require.NoError(t, err)
+	require.Len(t, publicJWKs, 2)
+	assert.Equal(t, "enc", publicJWKs[0].KeyIdentifier)
+	assert.Equal(t, "RSA-OAEP-256", publicJWKs[0].Algorithm)
+	assert.Equal(t, "any", publicJWKs[1].KeyIdentifier)
+}
+
+func TestSelectRSAEncryptionKey(t *testing.T) {
+	keyPair, err := GenerateKeyPair(AlgorithmES256)
+	require.NoError(t, err)
+	ecKey, err := ParsePublicKeyFromPEM(keyPair.PublicKeyPEM)
+	require.NoError(t, err)
+
+	keys := []PublicJWK{
+		{KeyIdentifier: "ec", Key: ecKey},
+		{KeyIdentifier: "rsa1_5", Algorithm: "RSA1_5", Key: &rsa.PublicKey{}},
+		{KeyIdentifier: "oaep", Algorithm: "RSA-OAEP-256", Key: &rsa.PublicKey{}},
+	}
+
+	key := SelectRSAEncryptionKey(keys, "RSA-OAEP-256")
+	require.NotNil(t, key)
+	assert.Equal(t, "oaep", key.KeyIdentifier)
+	assert.Nil(t, SelectRSAEncryptionKey(keys[:2], "RSA-OAEP-256"))
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		jwks string
+	}{
+		{"Not JSON", `keys`},
+		{"Invalid base64url", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"not base64!"}]}`},
+		{"Unsupported curve", `{"keys":[{"kty":"EC","crv":"P-521","x":"AA","y":"AA"}]}`},
+		{"Short EC coordinates", `{"keys":[{"kty":"EC","crv":"P-256","x":"AQAB","y":"AQAB"}]}`},
+		{"Short Ed25519 key", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"AQAB"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publicJWKs, err := ParseJWKS([]byte(tt.jwks))
+			assert.Error(t, err)
+			assert.Nil(t, publicJWKs)
+		})
+	}
+}
+
+func TestParsePublicJWK_Thumbprint(t *testing.T) {
+	// example key and thumbprint from RFC 7638, section 3.1
+	jwk := []byte(`{
+		"kty": "RSA",
+		"n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		"e": "AQAB",
+		"alg": "RS256",
+		"kid": "2011-04-29"
+	}`)
+
+	publicKey, thumbprint, err := ParsePublicJWK(jwk)
+	require.NoError(t, err)
+	assert.NotNil(t, publicKey)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+}
+
+func TestParsePublicJWK_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		jwk  string
+	}{
+		{"Not JSON", `jwk`},
+		{"Private key", `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"}`},
+		{"Symmetric key", `{"kty":"oct","k":"c2VjcmV0"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publicKey, thumbprint, err := ParsePublicJWK([]byte(tt.jwk))
+			assert.Error(t, err)
+			assert.Nil(t, publicKey)
+			assert.Empty(t, thumbprint)
+		})
+	}
+}
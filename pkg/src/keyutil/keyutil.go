@@ -0,0 +1,242 @@
+package keyutil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/rsautil"
+	"github.com/pkg/errors"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmES384 = "ES384"
+	AlgorithmEdDSA = "EdDSA"
+	rsaKeyBitSize  = 4096
+)
+
+// SupportedAlgorithms lists the JWS algorithms that signing keys can be generated for
+var SupportedAlgorithms = []string{AlgorithmRS256, AlgorithmES256, AlgorithmES384, AlgorithmEdDSA}
+
+// GenerateKeyPair creates a signing key pair for the algorithm, with a new key identifier.
+// The state of the key pair is left for the caller to set.
+func GenerateKeyPair(algorithm string) (*models.KeyPair, error) {
+	var (
+		privateKey crypto.Signer
+		keyType    string
+		err        error
+	)
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsautil.GeneratePrivateKey(rsaKeyBitSize)
+		keyType = "RSA"
+	case AlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		keyType = "EC"
+	case AlgorithmES384:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		keyType = "EC"
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		keyType = "OKP"
+	default:
+		return nil, errors.WithStack(errors.New("unsupported signing key algorithm " + algorithm))
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate a private key")
+	}
+
+	privateKeyPEM, err := EncodePrivateKeyToPEM(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeyASN1_DER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal public key to PKIX")
+	}
+
+	// RSA public keys have always been stored with this block type
+	publicKeyBlockType := "PUBLIC KEY"
+	if keyType == "RSA" {
+		publicKeyBlockType = "RSA PUBLIC KEY"
+	}
+
+	kid := uuid.New().String()
+	publicKeyJWK, err := MarshalPublicKeyToJWK(privateKey.Public(), kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.KeyPair{
+		KeyIdentifier:     kid,
+		Type:              keyType,
+		Algorithm:         algorithm,
+		PrivateKeyPEM:     privateKeyPEM,
+		PublicKeyPEM:      pem.EncodeToMemory(&pem.Block{Type: publicKeyBlockType, Bytes: publicKeyASN1_DER}),
+		PublicKeyASN1_DER: publicKeyASN1_DER,
+		PublicKeyJWK:      publicKeyJWK,
+	}, nil
+}
+
+func EncodePrivateKeyToPEM(privateKey crypto.PrivateKey) ([]byte, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return rsautil.EncodePrivateKeyToPEM(key), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to marshal EC private key")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	case ed25519.PrivateKey:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to marshal Ed25519 private key")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	}
+
+	return nil, errors.WithStack(errors.New("unsupported private key type"))
+}
+
+// ParsePrivateKeyFromPEM parses a private key in PKCS #1, SEC 1 or PKCS #8 form
+func ParsePrivateKeyFromPEM(privateKeyPEM []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.WithStack(errors.New("unable to decode private key PEM"))
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, errors.Wrap(err, "unable to parse RSA private key")
+		} else {
+			return key, nil
+		}
+	case "EC PRIVATE KEY":
+		if key, err := x509.ParseECPrivateKey(block.Bytes); err != nil {
+			return nil, errors.Wrap(err, "unable to parse EC private key")
+		} else {
+			return key, nil
+		}
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse private key")
+	}
+
+	return key, nil
+}
+
+func ParsePublicKeyFromPEM(publicKeyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.WithStack(errors.New("unable to decode public key PEM"))
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse public key")
+	}
+
+	return key, nil
+}
+
+// GetSigningMethod returns the JWS signing method matching a private or public key
+func GetSigningMethod(key interface{}) (jwt.SigningMethod, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		return getECSigningMethod(key.Curve)
+	case *ecdsa.PublicKey:
+		return getECSigningMethod(key.Curve)
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, errors.WithStack(errors.New("unsupported key type"))
+}
+
+func getECSigningMethod(curve elliptic.Curve) (jwt.SigningMethod, error) {
+	switch curve {
+	case elliptic.P256():
+		return jwt.SigningMethodES256, nil
+	case elliptic.P384():
+		return jwt.SigningMethodES384, nil
+	}
+
+	return nil, errors.WithStack(errors.New("unsupported elliptic curve " + curve.Params().Name))
+}
+
+func MarshalPublicKeyToJWK(publicKey crypto.PublicKey, kid string) (publicKeyJWK []byte, err error) {
+	var jwk interface{}
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsautil.MarshalRSAPublicKeyToJWK(key, kid)
+	case *ecdsa.PublicKey:
+		method, err := getECSigningMethod(key.Curve)
+		if err != nil {
+			return nil, err
+		}
+
+		// coordinates are padded to the size of the curve (RFC 7518, section 6.2.1.2)
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk = struct {
+			Alg string `json:"alg"`
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{
+			Alg: method.Alg(),
+			Kid: kid,
+			Kty: "EC",
+			Use: "sig",
+			Crv: key.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+		}
+	case ed25519.PublicKey:
+		jwk = struct {
+			Alg string `json:"alg"`
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+		}{
+			Alg: AlgorithmEdDSA,
+			Kid: kid,
+			Kty: "OKP",
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}
+	default:
+		return nil, errors.WithStack(errors.New("unsupported public key type"))
+	}
+
+	if publicKeyJWK, err = json.MarshalIndent(jwk, "", "  "); err != nil {
+		return nil, errors.Wrap(err, "unable to marshal public key to JSON")
+	}
+
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/go-chi/cors"
+	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/database"
 )
 
@@ -15,7 +16,7 @@ func MiddlewareCors(database database.Database) func(next http.Handler) http.Han
 			if r.URL.Path == "/.well-known/openid-configuration" || r.URL.Path == "/certs" {
 				// always allow the discovery URL
 				return true
-			} else if r.URL.Path == "/auth/token" || r.URL.Path == "/auth/logout" || r.URL.Path == "/userinfo" {
+			} else if r.URL.Path == "/auth/token" || r.URL.Path == "/auth/revoke" || r.URL.Path == "/auth/logout" || r.URL.Path == "/userinfo" {
 				// allow when the web origin of the request matches a web origin in the database
 				webOrigins, err := database.GetAllWebOrigins(nil)
 				if err != nil {
@@ -30,7 +31,9 @@ func MiddlewareCors(database database.Database) func(next http.Handler) http.Han
 			}
 			return false
 		},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", constants.DPoPHeaderName},
 		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		// browser clients read the nonce for their next DPoP proof
+		ExposedHeaders: []string{constants.DPoPNonceHeaderName},
 	})
 }
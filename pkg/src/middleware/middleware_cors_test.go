@@ -44,6 +44,17 @@ func TestMiddlewareCors(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name:          "Allow CORS for auth/revoke with valid origin",
+			path:          "/auth/revoke",
+			origin:        "http://allowed.com",
+			expectedAllow: true,
+			setupMock: func(db *mocks.Database) {
+				db.On("GetAllWebOrigins", mock.Anything).Return([]models.WebOrigin{
+					{Origin: "http://allowed.com"},
+				}, nil)
+			},
+		},
 		{
 			name:          "Allow CORS for auth/logout with valid origin",
 			path:          "/auth/logout",
@@ -16,6 +16,14 @@ func MiddlewareSkipCsrf() func(next http.Handler) http.Handler {
 			if strings.HasPrefix(r.URL.Path, "/static") ||
 				strings.HasPrefix(r.URL.Path, "/userinfo") ||
 				strings.HasPrefix(r.URL.Path, "/auth/token") ||
+				strings.HasPrefix(r.URL.Path, "/auth/introspect") ||
+				strings.HasPrefix(r.URL.Path, "/auth/revoke") ||
+				strings.HasPrefix(r.URL.Path, "/auth/device_authorization") ||
+				strings.HasPrefix(r.URL.Path, "/auth/bc-authorize") ||
+				strings.HasPrefix(r.URL.Path, "/auth/register") ||
+				strings.HasPrefix(r.URL.Path, "/auth/par") ||
+				// authorization requests can be form posted by the clients (OpenID Connect Core, section 3.1.2.1)
+				r.URL.Path == "/auth/authorize" ||
 				strings.HasPrefix(r.URL.Path, "/auth/callback") {
 				skip = true
 			}
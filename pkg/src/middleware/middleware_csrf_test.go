@@ -20,6 +20,14 @@ func TestMiddlewareSkipCsrf(t *testing.T) {
 		{"Static path", "/static/file.css", true},
 		{"Userinfo path", "/userinfo", true},
 		{"Token path", "/auth/token", true},
+		{"Introspect path", "/auth/introspect", true},
+		{"Revoke path", "/auth/revoke", true},
+		{"Device authorization path", "/auth/device_authorization", true},
+		{"Backchannel authentication path", "/auth/bc-authorize", true},
+		{"Register path", "/auth/register", true},
+		{"PAR path", "/auth/par", true},
+		{"Authorize path", "/auth/authorize", true},
+		{"Authorize subpath", "/auth/authorize/other", false},
 		{"Callback path", "/auth/callback", true},
 		{"Other path", "/other", false},
 	}
@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto"
 	"encoding/json"
 	"errors"
 	"io"
@@ -19,6 +19,7 @@ import (
 	"github.com/pchchv/aas/pkg/src/encryption"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/validators"
 )
 
 type HTTPClient interface {
@@ -26,16 +27,17 @@ type HTTPClient interface {
 }
 
 type MiddlewareJwt struct {
-	sessionStore sessions.Store
-	tokenParser  tokenParser
-	database     database.Database
-	authHelper   authHelper
-	httpClient   HTTPClient
+	sessionStore  sessions.Store
+	tokenParser   tokenParser
+	database      database.Database
+	authHelper    authHelper
+	httpClient    HTTPClient
+	dpopValidator *validators.DPoPValidator
 }
 
 type tokenParser interface {
 	DecodeAndValidateTokenResponse(tokenResponse *oauth.TokenResponse) (*oauth.JwtInfo, error)
-	DecodeAndValidateTokenString(token string, pubKey *rsa.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error)
+	DecodeAndValidateTokenString(token string, pubKey crypto.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error)
 }
 
 type authHelper interface {
@@ -46,27 +48,44 @@ type authHelper interface {
 
 func NewMiddlewareJwt(sessionStore sessions.Store, tokenParser tokenParser, database database.Database, authHelper authHelper, httpClient HTTPClient) *MiddlewareJwt {
 	return &MiddlewareJwt{
-		sessionStore: sessionStore,
-		tokenParser:  tokenParser,
-		database:     database,
-		authHelper:   authHelper,
-		httpClient:   httpClient,
+		sessionStore:  sessionStore,
+		tokenParser:   tokenParser,
+		database:      database,
+		authHelper:    authHelper,
+		httpClient:    httpClient,
+		dpopValidator: validators.NewDPoPValidator(database),
 	}
 }
 
 // JwtAuthorizationHeaderToContext is a middleware that extracts the JWT token from the Authorization header and stores it in the context.
+// DPoP-bound tokens must be sent with the DPoP scheme and a proof signed by the key they are bound to (RFC 9449, section 7).
 func (m *MiddlewareJwt) JwtAuthorizationHeaderToContext() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 			const BEARER_SCHEMA = "Bearer "
+			const DPOP_SCHEMA = "DPoP "
 			authHeader := r.Header.Get("Authorization")
 			if strings.HasPrefix(authHeader, BEARER_SCHEMA) && len(authHeader) >= len(BEARER_SCHEMA) {
 				tokenStr := authHeader[len(BEARER_SCHEMA):]
 				token, err := m.tokenParser.DecodeAndValidateTokenString(tokenStr, nil, true)
-				if err == nil {
+				if err == nil && m.isTokenBindingValid(r, token, "") {
 					ctx = context.WithValue(ctx, constants.ContextKeyBearerToken, *token)
 				}
+			} else if strings.HasPrefix(authHeader, DPOP_SCHEMA) && len(authHeader) >= len(DPOP_SCHEMA) {
+				tokenStr := authHeader[len(DPOP_SCHEMA):]
+				token, err := m.tokenParser.DecodeAndValidateTokenString(tokenStr, nil, true)
+				if err == nil {
+					jkt, err := m.dpopValidator.ValidateDPoPProof(ctx, &validators.ValidateDPoPProofInput{
+						Proofs:      r.Header.Values(constants.DPoPHeaderName),
+						HttpMethod:  r.Method,
+						HttpURL:     config.Get().BaseURL + r.URL.Path,
+						AccessToken: tokenStr,
+					})
+					if err == nil && m.isTokenBindingValid(r, token, jkt) {
+						ctx = context.WithValue(ctx, constants.ContextKeyBearerToken, *token)
+					}
+				}
 			}
 
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -74,6 +93,12 @@ func (m *MiddlewareJwt) JwtAuthorizationHeaderToContext() func(http.Handler) htt
 	}
 }
 
+// isTokenBindingValid checks that a certificate-bound token is used with the same client certificate
+// (RFC 8705, section 3) and that a DPoP-bound token is used with a proof of its key
+func (m *MiddlewareJwt) isTokenBindingValid(r *http.Request, token *oauth.Jwt, jkt string) bool {
+	return oauth.IsCertificateBindingValid(token, oauth.GetClientCertificates(r)) && oauth.IsDPoPBindingValid(token, jkt)
+}
+
 // JwtSessionHandler is a middleware that checks if the user has a valid JWT session.
 // It will also refresh the token if needed.
 func (m *MiddlewareJwt) JwtSessionHandler() func(http.Handler) http.Handler {
@@ -177,6 +202,30 @@ func (m *MiddlewareJwt) RequiresScope(scopesAnyOf []string) func(http.Handler) h
 	}
 }
 
+// RequiresBearerScope is a middleware that checks if the bearer token in the context has the required scope.
+// Unlike RequiresScope, it never redirects and responds with a JSON error instead, which makes it suitable for APIs.
+func (m *MiddlewareJwt) RequiresBearerScope(scopesAnyOf []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			jwtToken, ok := r.Context().Value(constants.ContextKeyBearerToken).(oauth.Jwt)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="The access token is missing or invalid."`)
+				writeJsonError(w, http.StatusUnauthorized, "invalid_token", "The access token is missing or invalid.")
+				return
+			}
+
+			if isAuthorized := m.authHelper.IsAuthorizedToAccessResource(oauth.JwtInfo{AccessToken: &jwtToken}, scopesAnyOf); !isAuthorized {
+				scope := strings.Join(scopesAnyOf, " ")
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				writeJsonError(w, http.StatusForbidden, "insufficient_scope", "The access token does not have the required scope ("+scope+").")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func (m *MiddlewareJwt) refreshToken(w http.ResponseWriter, r *http.Request, tokenResponse *oauth.TokenResponse) (bool, error) {
 	if tokenResponse.RefreshToken == "" {
 		return false, nil
@@ -281,3 +330,12 @@ func (m *MiddlewareJwt) buildScopeString(customScopes []string) string {
 
 	return strings.Join(allScopes, " ")
 }
+
+func writeJsonError(w http.ResponseWriter, statusCode int, code string, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{ //nolint:errcheck
+		"error":             code,
+		"error_description": description,
+	})
+}
@@ -88,6 +88,67 @@ func TestJwtAuthorizationHeaderToContext_InvalidBearerToken(t *testing.T) {
 	mockTokenParser.AssertExpectations(t)
 }
 
+func TestJwtAuthorizationHeaderToContext_DPoPBoundTokenWithBearerScheme(t *testing.T) {
+	mockTokenParser := new(oauthMocks.TokenParser)
+	mockDatabase := new(dataMocks.Database)
+	mockAuthHelper := new(helpersMocks.AuthHelper)
+	middleware := NewMiddlewareJwt(nil, mockTokenParser, mockDatabase, mockAuthHelper, nil)
+	boundToken := &oauth.Jwt{
+		TokenBase64: "boundtoken",
+		Claims: map[string]interface{}{
+			"sub": "user",
+			"cnf": map[string]interface{}{oauth.ConfirmationMethodJkt: "jkt"},
+		},
+	}
+	mockTokenParser.On("DecodeAndValidateTokenString", "boundtoken", mock.Anything, true).
+		Return(boundToken, nil)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer boundtoken")
+	rr := httptest.NewRecorder()
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Context().Value(constants.ContextKeyBearerToken)
+		assert.Nil(t, token)
+	})
+
+	handler := middleware.JwtAuthorizationHeaderToContext()(nextHandler)
+	handler.ServeHTTP(rr, req)
+
+	mockTokenParser.AssertExpectations(t)
+}
+
+func TestJwtAuthorizationHeaderToContext_DPoPTokenWithoutProof(t *testing.T) {
+	mockTokenParser := new(oauthMocks.TokenParser)
+	mockDatabase := new(dataMocks.Database)
+	mockAuthHelper := new(helpersMocks.AuthHelper)
+	middleware := NewMiddlewareJwt(nil, mockTokenParser, mockDatabase, mockAuthHelper, nil)
+	boundToken := &oauth.Jwt{
+		TokenBase64: "boundtoken",
+		Claims: map[string]interface{}{
+			"sub": "user",
+			"cnf": map[string]interface{}{oauth.ConfirmationMethodJkt: "jkt"},
+		},
+	}
+	mockTokenParser.On("DecodeAndValidateTokenString", "boundtoken", mock.Anything, true).
+		Return(boundToken, nil)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "DPoP boundtoken")
+	rr := httptest.NewRecorder()
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Context().Value(constants.ContextKeyBearerToken)
+		assert.Nil(t, token)
+	})
+
+	handler := middleware.JwtAuthorizationHeaderToContext()(nextHandler)
+	handler.ServeHTTP(rr, req)
+
+	mockTokenParser.AssertExpectations(t)
+	mockDatabase.AssertNotCalled(t, "CreateDPoPProofJti", mock.Anything, mock.Anything)
+}
+
 func TestJwtAuthorizationHeaderToContext_NoBearerToken(t *testing.T) {
 	mockTokenParser := new(oauthMocks.TokenParser)
 	mockDatabase := new(dataMocks.Database)
@@ -829,3 +890,78 @@ func TestRequiresScope_RedirectError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	mockAuthHelper.AssertExpectations(t)
 }
+
+func TestRequiresBearerScope_Authorized(t *testing.T) {
+	mockTokenParser := new(oauthMocks.TokenParser)
+	mockDatabase := new(dataMocks.Database)
+	mockAuthHelper := new(helpersMocks.AuthHelper)
+	middleware := NewMiddlewareJwt(nil, mockTokenParser, mockDatabase, mockAuthHelper, nil)
+	token := oauth.Jwt{
+		TokenBase64: "validtoken",
+		Claims: map[string]interface{}{
+			"scope": "required:scope",
+		},
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeyBearerToken, token))
+	rr := httptest.NewRecorder()
+
+	mockAuthHelper.On("IsAuthorizedToAccessResource", oauth.JwtInfo{AccessToken: &token}, []string{"required:scope"}).Return(true)
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+
+	handler := middleware.RequiresBearerScope([]string{"required:scope"})(next)
+	handler.ServeHTTP(rr, req)
+
+	assert.True(t, nextCalled, "Next handler should have been called")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockAuthHelper.AssertExpectations(t)
+}
+
+func TestRequiresBearerScope_InsufficientScope(t *testing.T) {
+	mockTokenParser := new(oauthMocks.TokenParser)
+	mockDatabase := new(dataMocks.Database)
+	mockAuthHelper := new(helpersMocks.AuthHelper)
+	middleware := NewMiddlewareJwt(nil, mockTokenParser, mockDatabase, mockAuthHelper, nil)
+	token := oauth.Jwt{TokenBase64: "validtoken"}
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), constants.ContextKeyBearerToken, token))
+	rr := httptest.NewRecorder()
+
+	mockAuthHelper.On("IsAuthorizedToAccessResource", oauth.JwtInfo{AccessToken: &token}, []string{"required:scope"}).Return(false)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Next handler should not have been called")
+	})
+
+	handler := middleware.RequiresBearerScope([]string{"required:scope"})(next)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "insufficient_scope")
+	assert.Contains(t, rr.Body.String(), `"error":"insufficient_scope"`)
+	mockAuthHelper.AssertExpectations(t)
+}
+
+func TestRequiresBearerScope_NoBearerToken(t *testing.T) {
+	mockTokenParser := new(oauthMocks.TokenParser)
+	mockDatabase := new(dataMocks.Database)
+	mockAuthHelper := new(helpersMocks.AuthHelper)
+	middleware := NewMiddlewareJwt(nil, mockTokenParser, mockDatabase, mockAuthHelper, nil)
+	req := httptest.NewRequest("GET", "/", nil)
+	rr := httptest.NewRecorder()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Next handler should not have been called")
+	})
+
+	handler := middleware.RequiresBearerScope([]string{"required:scope"})(next)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "invalid_token")
+	mockAuthHelper.AssertNotCalled(t, "IsAuthorizedToAccessResource", mock.Anything, mock.Anything)
+}
@@ -20,6 +20,8 @@ type RateLimiterMiddleware struct {
 	otpLimiter      *httprate.RateLimiter
 	activateLimiter *httprate.RateLimiter
 	resetPwdLimiter *httprate.RateLimiter
+	deviceLimiter   *httprate.RateLimiter
+	registerLimiter *httprate.RateLimiter
 }
 
 func NewRateLimiterMiddleware(authHelper AuthHelper) *RateLimiterMiddleware {
@@ -29,6 +31,8 @@ func NewRateLimiterMiddleware(authHelper AuthHelper) *RateLimiterMiddleware {
 		otpLimiter:      httprate.NewRateLimiter(10, 1*time.Minute),
 		activateLimiter: httprate.NewRateLimiter(5, 5*time.Minute),
 		resetPwdLimiter: httprate.NewRateLimiter(5, 5*time.Minute),
+		deviceLimiter:   httprate.NewRateLimiter(10, 5*time.Minute),
+		registerLimiter: httprate.NewRateLimiter(10, 1*time.Hour),
 	}
 }
 
@@ -86,3 +90,39 @@ func (m *RateLimiterMiddleware) LimitResetPwd(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func (m *RateLimiterMiddleware) LimitDevice(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// user codes are short, so guesses are limited per client IP
+		ip, err := httprate.KeyByIP(r)
+		if err != nil {
+			slog.Error("Rate limiter - unable to get client IP", "error", err)
+			return
+		}
+
+		if m.deviceLimiter.RespondOnLimit(w, r, ip) {
+			slog.Error("Rate limiter - limit reached (device)", "ip", ip)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *RateLimiterMiddleware) LimitRegister(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// open registration needs no credentials, so clients are limited per IP
+		ip, err := httprate.KeyByIP(r)
+		if err != nil {
+			slog.Error("Rate limiter - unable to get client IP", "error", err)
+			return
+		}
+
+		if m.registerLimiter.RespondOnLimit(w, r, ip) {
+			slog.Error("Rate limiter - limit reached (register)", "ip", ip)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
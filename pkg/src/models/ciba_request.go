@@ -0,0 +1,35 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CIBARequest is a backchannel authentication request of a client, waiting for the
+// user to authenticate on their own device (OpenID Connect CIBA, section 7)
+type CIBARequest struct {
+	Id                               int64         `db:"id" fieldtag:"pk"`
+	CreatedAt                        sql.NullTime  `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt                        sql.NullTime  `db:"updated_at"`
+	AuthReqIdHash                    string        `db:"auth_req_id_hash"`
+	AuthReqIdEncrypted               []byte        `db:"auth_req_id_encrypted"`
+	VerificationCodeHash             string        `db:"verification_code_hash"`
+	ClientId                         int64         `db:"client_id"`
+	Client                           Client        `db:"-"`
+	UserId                           int64         `db:"user_id"`
+	User                             User          `db:"-"`
+	Scope                            string        `db:"scope"`
+	AcrValues                        string        `db:"acr_values"`
+	BindingMessage                   string        `db:"binding_message"`
+	TokenDeliveryMode                string        `db:"token_delivery_mode"`
+	ClientNotificationTokenEncrypted []byte        `db:"client_notification_token_encrypted"`
+	Status                           string        `db:"status"`
+	ExpiresAt                        sql.NullTime  `db:"expires_at"`
+	PollingIntervalSeconds           int           `db:"polling_interval_seconds"`
+	LastPolledAt                     sql.NullTime  `db:"last_polled_at"`
+	CodeId                           sql.NullInt64 `db:"code_id"`
+}
+
+func (cr *CIBARequest) IsExpired() bool {
+	return !cr.ExpiresAt.Valid || time.Now().UTC().After(cr.ExpiresAt.Time)
+}
@@ -2,31 +2,123 @@ package models
 
 import (
 	"database/sql"
+	"slices"
+	"strings"
 
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/enums"
 )
 
 type Client struct {
-	Id                                      int64          `db:"id" fieldtag:"pk"`
-	CreatedAt                               sql.NullTime   `db:"created_at" fieldtag:"dont-update"`
-	UpdatedAt                               sql.NullTime   `db:"updated_at"`
-	ClientIdentifier                        string         `db:"client_identifier"`
-	ClientSecretEncrypted                   []byte         `db:"client_secret_encrypted"`
-	Description                             string         `db:"description"`
-	Enabled                                 bool           `db:"enabled"`
-	ConsentRequired                         bool           `db:"consent_required"`
-	IsPublic                                bool           `db:"is_public"`
-	AuthorizationCodeEnabled                bool           `db:"authorization_code_enabled"`
-	ClientCredentialsEnabled                bool           `db:"client_credentials_enabled"`
-	TokenExpirationInSeconds                int            `db:"token_expiration_in_seconds"`
-	RefreshTokenOfflineIdleTimeoutInSeconds int            `db:"refresh_token_offline_idle_timeout_in_seconds"`
-	RefreshTokenOfflineMaxLifetimeInSeconds int            `db:"refresh_token_offline_max_lifetime_in_seconds"`
-	IncludeOpenIDConnectClaimsInAccessToken string         `db:"include_open_id_connect_claims_in_access_token"`
-	DefaultAcrLevel                         enums.AcrLevel `db:"default_acr_level"`
-	Permissions                             []Permission   `db:"-"`
-	RedirectURIs                            []RedirectURI  `db:"-"`
-	WebOrigins                              []WebOrigin    `db:"-"`
+	Id                                      int64                   `db:"id" fieldtag:"pk"`
+	CreatedAt                               sql.NullTime            `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt                               sql.NullTime            `db:"updated_at"`
+	ClientIdentifier                        string                  `db:"client_identifier"`
+	ClientSecretEncrypted                   []byte                  `db:"client_secret_encrypted"`
+	Description                             string                  `db:"description"`
+	Enabled                                 bool                    `db:"enabled"`
+	ConsentRequired                         bool                    `db:"consent_required"`
+	IsPublic                                bool                    `db:"is_public"`
+	AuthorizationCodeEnabled                bool                    `db:"authorization_code_enabled"`
+	ClientCredentialsEnabled                bool                    `db:"client_credentials_enabled"`
+	DeviceCodeEnabled                       bool                    `db:"device_code_enabled"`
+	TokenExchangeEnabled                    bool                    `db:"token_exchange_enabled"`
+	TokenExchangeImpersonationEnabled       bool                    `db:"token_exchange_impersonation_enabled"`
+	TokenExchangeSubjectClients             string                  `db:"token_exchange_subject_clients"`
+	TokenExpirationInSeconds                int                     `db:"token_expiration_in_seconds"`
+	RefreshTokenOfflineIdleTimeoutInSeconds int                     `db:"refresh_token_offline_idle_timeout_in_seconds"`
+	RefreshTokenOfflineMaxLifetimeInSeconds int                     `db:"refresh_token_offline_max_lifetime_in_seconds"`
+	RefreshTokenReuseGracePeriodInSeconds   sql.NullInt32           `db:"refresh_token_reuse_grace_period_in_seconds"`
+	IncludeOpenIDConnectClaimsInAccessToken string                  `db:"include_open_id_connect_claims_in_access_token"`
+	DefaultAcrLevel                         enums.AcrLevel          `db:"default_acr_level"`
+	RegistrationAccessTokenHash             string                  `db:"registration_access_token_hash"`
+	PARRequired                             bool                    `db:"par_required"`
+	RequireSignedRequestObject              bool                    `db:"require_signed_request_object"`
+	TokenEndpointAuthMethod                 string                  `db:"token_endpoint_auth_method"`
+	JWKS                                    []byte                  `db:"jwks"`
+	JWKSURI                                 string                  `db:"jwks_uri"`
+	TLSClientAuthSubjectDN                  string                  `db:"tls_client_auth_subject_dn"`
+	FrontChannelLogoutURI                   string                  `db:"frontchannel_logout_uri"`
+	BackChannelLogoutURI                    string                  `db:"backchannel_logout_uri"`
+	SubjectType                             string                  `db:"subject_type"`
+	SectorIdentifierURI                     string                  `db:"sector_identifier_uri"`
+	SectorIdentifier                        string                  `db:"sector_identifier"`
+	IdTokenEncryptedResponseAlg             string                  `db:"id_token_encrypted_response_alg"`
+	IdTokenEncryptedResponseEnc             string                  `db:"id_token_encrypted_response_enc"`
+	UserInfoEncryptedResponseAlg            string                  `db:"userinfo_encrypted_response_alg"`
+	UserInfoEncryptedResponseEnc            string                  `db:"userinfo_encrypted_response_enc"`
+	AuthorizationEncryptedResponseAlg       string                  `db:"authorization_encrypted_response_alg"`
+	AuthorizationEncryptedResponseEnc       string                  `db:"authorization_encrypted_response_enc"`
+	CIBAEnabled                             bool                    `db:"ciba_enabled"`
+	BackChannelTokenDeliveryMode            string                  `db:"backchannel_token_delivery_mode"`
+	BackChannelClientNotificationEndpoint   string                  `db:"backchannel_client_notification_endpoint"`
+	AccessTokenFormat                       string                  `db:"access_token_format"`
+	Permissions                             []Permission            `db:"-"`
+	RedirectURIs                            []RedirectURI           `db:"-"`
+	PostLogoutRedirectURIs                  []PostLogoutRedirectURI `db:"-"`
+	WebOrigins                              []WebOrigin             `db:"-"`
+}
+
+// IsDynamicallyRegistered reports whether the client was created through
+// the dynamic client registration endpoint and can manage its own configuration
+func (c *Client) IsDynamicallyRegistered() bool {
+	return len(c.RegistrationAccessTokenHash) > 0
+}
+
+// UsesClientAssertion reports whether the client authenticates with a signed
+// JWT (private_key_jwt or client_secret_jwt) instead of sending its secret
+func (c *Client) UsesClientAssertion() bool {
+	return c.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodPrivateKeyJwt ||
+		c.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodClientSecretJwt
+}
+
+// UsesTLSClientAuth reports whether the client authenticates with the certificate
+// it presents in the TLS handshake (tls_client_auth or self_signed_tls_client_auth)
+func (c *Client) UsesTLSClientAuth() bool {
+	return c.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodTLSClientAuth ||
+		c.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodSelfSignedTLS
+}
+
+// UsesPairwiseSubject reports whether the users are identified to the client by
+// a subject derived for its sector, instead of the subject shared by all clients
+func (c *Client) UsesPairwiseSubject() bool {
+	return c.SubjectType == constants.SubjectTypePairwise
+}
+
+// EncryptsIdToken reports whether the ID tokens issued to the client are
+// encrypted for it, nested in a JWE after being signed
+func (c *Client) EncryptsIdToken() bool {
+	return len(c.IdTokenEncryptedResponseAlg) > 0
+}
+
+// EncryptsUserInfo reports whether the userinfo responses of the client are
+// returned as a signed and encrypted JWT instead of a JSON object
+func (c *Client) EncryptsUserInfo() bool {
+	return len(c.UserInfoEncryptedResponseAlg) > 0
+}
+
+// EncryptsAuthorizationResponse reports whether the JWT authorization responses
+// of the client are nested in a JWE after being signed (JARM, section 2.3)
+func (c *Client) EncryptsAuthorizationResponse() bool {
+	return len(c.AuthorizationEncryptedResponseAlg) > 0
+}
+
+// CanExchangeTokensOf reports whether the client was allowed to exchange
+// the access tokens issued to the other client
+func (c *Client) CanExchangeTokensOf(clientIdentifier string) bool {
+	return slices.Contains(strings.Fields(c.TokenExchangeSubjectClients), clientIdentifier)
+}
+
+// UsesCIBAPingMode reports whether the client is notified at its client notification
+// endpoint once the user completes a backchannel authentication, instead of only polling
+func (c *Client) UsesCIBAPingMode() bool {
+	return c.BackChannelTokenDeliveryMode == constants.CIBATokenDeliveryModePing
+}
+
+// UsesOpaqueAccessTokens reports whether the access tokens of the client are opaque references
+// to claims kept by the server, instead of JWTs the client can read
+func (c *Client) UsesOpaqueAccessTokens() bool {
+	return c.AccessTokenFormat == constants.AccessTokenFormatOpaque
 }
 
 func (c *Client) IsSystemLevelClient() bool {
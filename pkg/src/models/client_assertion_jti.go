@@ -0,0 +1,14 @@
+package models
+
+import "database/sql"
+
+// ClientAssertionJti records the jti of a client assertion that was already used,
+// until the assertion expires, so it can't be replayed (RFC 7523, section 3)
+type ClientAssertionJti struct {
+	Id        int64        `db:"id" fieldtag:"pk"`
+	CreatedAt sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+	ClientId  int64        `db:"client_id"`
+	JtiHash   string       `db:"jti_hash"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
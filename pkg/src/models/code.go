@@ -14,6 +14,8 @@ type Code struct {
 	ClientId            int64        `db:"client_id"`
 	Client              Client       `db:"-"`
 	Scope               string       `db:"scope"`
+	Resource            string       `db:"resource"`
+	Claims              string       `db:"claims"`
 	State               string       `db:"state"`
 	Nonce               string       `db:"nonce"`
 	RedirectURI         string       `db:"redirect_uri"`
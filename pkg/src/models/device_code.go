@@ -0,0 +1,26 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type DeviceCode struct {
+	Id                     int64         `db:"id" fieldtag:"pk"`
+	CreatedAt              sql.NullTime  `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt              sql.NullTime  `db:"updated_at"`
+	DeviceCodeHash         string        `db:"device_code_hash"`
+	UserCode               string        `db:"user_code"`
+	ClientId               int64         `db:"client_id"`
+	Client                 Client        `db:"-"`
+	Scope                  string        `db:"scope"`
+	Status                 string        `db:"status"`
+	ExpiresAt              sql.NullTime  `db:"expires_at"`
+	PollingIntervalSeconds int           `db:"polling_interval_seconds"`
+	LastPolledAt           sql.NullTime  `db:"last_polled_at"`
+	CodeId                 sql.NullInt64 `db:"code_id"`
+}
+
+func (dc *DeviceCode) IsExpired() bool {
+	return !dc.ExpiresAt.Valid || time.Now().UTC().After(dc.ExpiresAt.Time)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "database/sql"
+
+// DPoPProofJti records the jti of a DPoP proof that was already used,
+// until the proof is too old to be accepted, so it can't be replayed (RFC 9449, section 11.1)
+type DPoPProofJti struct {
+	Id        int64        `db:"id" fieldtag:"pk"`
+	CreatedAt sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+	JtiHash   string       `db:"jti_hash"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type InitialAccessToken struct {
+	Id          int64        `db:"id" fieldtag:"pk"`
+	CreatedAt   sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt   sql.NullTime `db:"updated_at"`
+	TokenHash   string       `db:"token_hash"`
+	Description string       `db:"description"`
+	ExpiresAt   sql.NullTime `db:"expires_at"`
+}
+
+func (iat *InitialAccessToken) IsExpired() bool {
+	return !iat.ExpiresAt.Valid || time.Now().UTC().After(iat.ExpiresAt.Time)
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// OpaqueAccessToken keeps the claims of an access token issued as an opaque reference,
+// the token itself is only stored hashed
+type OpaqueAccessToken struct {
+	Id        int64        `db:"id" fieldtag:"pk"`
+	CreatedAt sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+	TokenHash string       `db:"token_hash"`
+	Jti       string       `db:"jti"`
+	ClientId  int64        `db:"client_id"`
+	Claims    string       `db:"claims"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
+
+func (oat *OpaqueAccessToken) IsExpired() bool {
+	return !oat.ExpiresAt.Valid || time.Now().UTC().After(oat.ExpiresAt.Time)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "database/sql"
+
+// PairwiseSubject records the subject a user has for the clients of a sector,
+// so the sub claim of the tokens issued to those clients can be resolved back to the user
+type PairwiseSubject struct {
+	Id               int64        `db:"id" fieldtag:"pk"`
+	CreatedAt        sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UserId           int64        `db:"user_id"`
+	SectorIdentifier string       `db:"sector_identifier"`
+	Subject          string       `db:"subject"`
+}
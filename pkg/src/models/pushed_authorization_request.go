@@ -0,0 +1,21 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PushedAuthorizationRequest struct {
+	Id             int64        `db:"id" fieldtag:"pk"`
+	CreatedAt      sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt      sql.NullTime `db:"updated_at"`
+	RequestURIHash string       `db:"request_uri_hash"`
+	ClientId       int64        `db:"client_id"`
+	Client         Client       `db:"-"`
+	Parameters     string       `db:"parameters"`
+	ExpiresAt      sql.NullTime `db:"expires_at"`
+}
+
+func (par *PushedAuthorizationRequest) IsExpired() bool {
+	return !par.ExpiresAt.Valid || time.Now().UTC().After(par.ExpiresAt.Time)
+}
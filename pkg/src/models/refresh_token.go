@@ -18,4 +18,12 @@ type RefreshToken struct {
 	Revoked                 bool         `db:"revoked"`
 	FirstRefreshTokenJti    string       `db:"first_refresh_token_jti"`
 	PreviousRefreshTokenJti string       `db:"previous_refresh_token_jti"`
+	DPoPJkt                 string       `db:"dpop_jkt"`
+	SupersededAt            sql.NullTime `db:"superseded_at"`
+}
+
+// IsSuperseded reports whether the token was exchanged for a new refresh token,
+// as opposed to being revoked, so that presenting it again reveals a reuse
+func (rt *RefreshToken) IsSuperseded() bool {
+	return rt.SupersededAt.Valid
 }
@@ -0,0 +1,14 @@
+package models
+
+import "database/sql"
+
+// RequestObjectJti records the jti of a request object that was already used,
+// until the request object expires, so it can't be replayed (RFC 9101, section 10.8)
+type RequestObjectJti struct {
+	Id        int64        `db:"id" fieldtag:"pk"`
+	CreatedAt sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+	ClientId  int64        `db:"client_id"`
+	JtiHash   string       `db:"jti_hash"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "database/sql"
+
+type RevokedAccessToken struct {
+	Id        int64        `db:"id" fieldtag:"pk"`
+	CreatedAt sql.NullTime `db:"created_at" fieldtag:"dont-update"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+	Jti       string       `db:"jti"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
@@ -16,12 +16,17 @@ type Settings struct {
 	PasswordPolicy                            enums.PasswordPolicy `db:"password_policy"`
 	SelfRegistrationEnabled                   bool                 `db:"self_registration_enabled"`
 	SelfRegistrationRequiresEmailVerification bool                 `db:"self_registration_requires_email_verification"`
+	DynamicClientRegistrationEnabled          bool                 `db:"dynamic_client_registration_enabled"`
+	DynamicClientRegistrationOpen             bool                 `db:"dynamic_client_registration_open"`
 	TokenExpirationInSeconds                  int                  `db:"token_expiration_in_seconds"`
 	RefreshTokenOfflineIdleTimeoutInSeconds   int                  `db:"refresh_token_offline_idle_timeout_in_seconds"`
 	RefreshTokenOfflineMaxLifetimeInSeconds   int                  `db:"refresh_token_offline_max_lifetime_in_seconds"`
+	RefreshTokenReuseGracePeriodInSeconds     int                  `db:"refresh_token_reuse_grace_period_in_seconds"`
 	UserSessionIdleTimeoutInSeconds           int                  `db:"user_session_idle_timeout_in_seconds"`
 	UserSessionMaxLifetimeInSeconds           int                  `db:"user_session_max_lifetime_in_seconds"`
 	IncludeOpenIDConnectClaimsInAccessToken   bool                 `db:"include_open_id_connect_claims_in_access_token"`
+	SigningKeyAlgorithm                       string               `db:"signing_key_algorithm"`
+	KeyRotationIntervalInSeconds              int                  `db:"key_rotation_interval_in_seconds"`
 	SessionAuthenticationKey                  []byte               `db:"session_authentication_key"`
 	SessionEncryptionKey                      []byte               `db:"session_encryption_key"`
 	AESEncryptionKey                          []byte               `db:"aes_encryption_key"`
@@ -8,20 +8,22 @@ import (
 
 	"github.com/pchchv/aas/pkg/src/enums"
 	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oidc"
 )
 
 var (
-	AuthStateInitial                 = "initial"
-	AuthStateRequiresLevel1          = "requires_level_1"
-	AuthStateRequiresLevel2          = "requires_level_2"
-	AuthStateLevel1Password          = "level1_password"
-	AuthStateLevel1PasswordCompleted = "level1_password_completed"
-	AuthStateLevel1ExistingSession   = "level1_existing_session"
-	AuthStateLevel2OTP               = "level2_otp"
-	AuthStateLevel2OTPCompleted      = "level2_otp_completed"
-	AuthStateAuthenticationCompleted = "authentication_completed"
-	AuthStateRequiresConsent         = "requires_consent"
-	AuthStateReadyToIssueCode        = "ready_to_issue_code"
+	AuthStateInitial                  = "initial"
+	AuthStateRequiresLevel1           = "requires_level_1"
+	AuthStateRequiresLevel2           = "requires_level_2"
+	AuthStateLevel1Password           = "level1_password"
+	AuthStateLevel1PasswordCompleted  = "level1_password_completed"
+	AuthStateLevel1ExistingSession    = "level1_existing_session"
+	AuthStateRequiresAccountSelection = "requires_account_selection"
+	AuthStateLevel2OTP                = "level2_otp"
+	AuthStateLevel2OTPCompleted       = "level2_otp_completed"
+	AuthStateAuthenticationCompleted  = "authentication_completed"
+	AuthStateRequiresConsent          = "requires_consent"
+	AuthStateReadyToIssueCode         = "ready_to_issue_code"
 )
 
 type AuthContext struct {
@@ -32,8 +34,14 @@ type AuthContext struct {
 	CodeChallenge                 string
 	ResponseMode                  string
 	Scope                         string
+	Resource                      string
 	ConsentedScope                string
+	Claims                        string
+	ConsentedClaims               string
 	MaxAge                        string
+	Prompt                        string
+	LoginHint                     string
+	HintedUserId                  int64
 	AcrValuesFromAuthorizeRequest string
 	State                         string
 	Nonce                         string
@@ -43,6 +51,20 @@ type AuthContext struct {
 	AuthMethods                   string
 	AuthState                     string
 	UserId                        int64
+	DeviceCodeId                  int64
+	CIBARequestId                 int64
+}
+
+// IsDeviceFlow reports whether the authentication was started
+// from the device verification page rather than the authorize endpoint
+func (ac *AuthContext) IsDeviceFlow() bool {
+	return ac.DeviceCodeId > 0
+}
+
+// IsCIBAFlow reports whether the authentication was started from the link
+// sent to the user for a backchannel authentication request of a client
+func (ac *AuthContext) IsCIBAFlow() bool {
+	return ac.CIBARequestId > 0
 }
 
 func (ac *AuthContext) HasScope(scope string) bool {
@@ -52,6 +74,17 @@ func (ac *AuthContext) HasScope(scope string) bool {
 	return slices.Contains(strings.Split(ac.Scope, " "), scope)
 }
 
+// HasPrompt tells if the prompt parameter of the authorize request contains the value
+func (ac *AuthContext) HasPrompt(prompt string) bool {
+	return slices.Contains(strings.Fields(ac.Prompt), prompt)
+}
+
+// IsExpectedUser tells if the user can complete the request, when the client
+// identified the user with an id_token_hint no other user can sign in
+func (ac *AuthContext) IsExpectedUser(userId int64) bool {
+	return ac.HintedUserId == 0 || ac.HintedUserId == userId
+}
+
 func (ac *AuthContext) SetScope(scope string) {
 	scopeArr := []string{}
 	// remove duplicated spaces
@@ -104,9 +137,57 @@ func (ac *AuthContext) GetTargetAcrLevel(defaultAcrLevelFromClient enums.AcrLeve
 	if len(acrValuesFromAuthorizeRequest) > 0 {
 		return acrValuesFromAuthorizeRequest[0]
 	}
+
+	// the acr claim of the claims parameter is used when there is no acr_values parameter
+	for _, v := range ac.GetClaimsRequest().GetAcrValues() {
+		if acr, err := enums.AcrLevelFromString(v); err == nil {
+			return acr
+		}
+	}
 	return defaultAcrLevelFromClient
 }
 
+// GetClaimsRequest returns the claims parameter of the authorize request,
+// nil when there is none. The parameter is validated by the authorize endpoint.
+func (ac *AuthContext) GetClaimsRequest() *oidc.ClaimsRequest {
+	claimsRequest, err := oidc.ParseClaimsRequest(ac.Claims)
+	if err != nil {
+		return nil
+	}
+	return claimsRequest
+}
+
+// GetGrantedClaims returns the claims parameter without the claims of the user that were not consented
+func (ac *AuthContext) GetGrantedClaims() string {
+	return ac.GetClaimsRequest().Filter(strings.Fields(ac.ConsentedClaims)).String()
+}
+
+// ApplyEssentialAcrClaim makes the acr level of the authentication one of the values of an essential
+// acr claim (OpenID Connect Core, section 5.5.1.1). A stronger session satisfies a weaker requested level,
+// which is then the acr level. It returns false when the authentication satisfies none of the values.
+func (ac *AuthContext) ApplyEssentialAcrClaim() bool {
+	claimsRequest := ac.GetClaimsRequest()
+	if !claimsRequest.HasEssentialAcr() {
+		return true
+	}
+
+	acrValues := claimsRequest.GetAcrValues()
+	if slices.Contains(acrValues, ac.AcrLevel) {
+		return true
+	}
+
+	acrLevels := []string{enums.AcrLevel1.String(), enums.AcrLevel2Optional.String(), enums.AcrLevel2Mandatory.String()}
+	achieved := slices.Index(acrLevels, ac.AcrLevel)
+	for _, acrValue := range acrValues {
+		if requested := slices.Index(acrLevels, acrValue); requested >= 0 && requested <= achieved {
+			ac.AcrLevel = acrValue
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ac *AuthContext) ParseRequestedMaxAge() (requestedMaxAge *int) {
 	if len(ac.MaxAge) > 0 {
 		if i, err := strconv.Atoi(ac.MaxAge); err == nil {
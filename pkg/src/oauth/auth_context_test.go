@@ -0,0 +1,65 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTargetAcrLevel_ClaimsParameter(t *testing.T) {
+	authContext := AuthContext{Claims: `{"id_token":{"acr":{"values":["urn:other:acr","urn:goiabada:level2_mandatory"]}}}`}
+	assert.Equal(t, enums.AcrLevel2Mandatory, authContext.GetTargetAcrLevel(enums.AcrLevel1))
+
+	// the acr_values parameter takes precedence
+	authContext.AcrValuesFromAuthorizeRequest = "urn:goiabada:level2_optional"
+	assert.Equal(t, enums.AcrLevel2Optional, authContext.GetTargetAcrLevel(enums.AcrLevel1))
+}
+
+func TestApplyEssentialAcrClaim(t *testing.T) {
+	tests := []struct {
+		name        string
+		claims      string
+		acrLevel    string
+		satisfied   bool
+		expectedAcr string
+	}{
+		{name: "No claims parameter", acrLevel: "urn:goiabada:level1", satisfied: true, expectedAcr: "urn:goiabada:level1"},
+		{
+			name:        "Voluntary acr",
+			claims:      `{"id_token":{"acr":{"value":"urn:goiabada:level2_mandatory"}}}`,
+			acrLevel:    "urn:goiabada:level1",
+			satisfied:   true,
+			expectedAcr: "urn:goiabada:level1",
+		},
+		{
+			name:        "Requested level",
+			claims:      `{"id_token":{"acr":{"essential":true,"value":"urn:goiabada:level2_optional"}}}`,
+			acrLevel:    "urn:goiabada:level2_optional",
+			satisfied:   true,
+			expectedAcr: "urn:goiabada:level2_optional",
+		},
+		{
+			name:        "Stronger session",
+			claims:      `{"id_token":{"acr":{"essential":true,"values":["urn:goiabada:level2_optional","urn:goiabada:level1"]}}}`,
+			acrLevel:    "urn:goiabada:level2_mandatory",
+			satisfied:   true,
+			expectedAcr: "urn:goiabada:level2_optional",
+		},
+		{
+			name:        "Weaker authentication",
+			claims:      `{"id_token":{"acr":{"essential":true,"value":"urn:goiabada:level2_mandatory"}}}`,
+			acrLevel:    "urn:goiabada:level1",
+			satisfied:   false,
+			expectedAcr: "urn:goiabada:level1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authContext := AuthContext{Claims: tt.claims, AcrLevel: tt.acrLevel}
+			assert.Equal(t, tt.satisfied, authContext.ApplyEssentialAcrClaim())
+			assert.Equal(t, tt.expectedAcr, authContext.AcrLevel)
+		})
+	}
+}
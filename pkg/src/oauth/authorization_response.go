@@ -0,0 +1,52 @@
+package oauth
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+// authorizationResponseExpirationInSeconds keeps the response JWT short-lived,
+// it is consumed by the client right after the redirect (JARM, section 2.1)
+const authorizationResponseExpirationInSeconds = 300
+
+// JwtResponseModes are the JWT Secured Authorization Response Modes (JARM, section 2.3)
+var JwtResponseModes = []string{"jwt", "query.jwt", "fragment.jwt", "form_post.jwt"}
+
+// IsJwtResponseMode tells if the authorization response is delivered in a signed JWT
+func IsJwtResponseMode(responseMode string) bool {
+	return slices.Contains(JwtResponseModes, responseMode)
+}
+
+// GetJwtResponseModeTransport returns the response mode the response JWT is delivered with,
+// jwt uses the default of the code response type, which is query
+func GetJwtResponseModeTransport(responseMode string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(responseMode, "jwt"), ".")
+}
+
+// GenerateAuthorizationResponseJwt signs the parameters of an authorization response
+// for the client, adding the iss, aud and exp claims (JARM, section 2.1).
+// The clients registering an encryption get it nested with EncryptForClient.
+func GenerateAuthorizationResponseJwt(keyPair *models.KeyPair, issuer string, clientId string, parameters url.Values) (string, error) {
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to parse private key from PEM")
+	}
+
+	claims := jwt.MapClaims{
+		"iss": issuer,
+		"aud": clientId,
+		"exp": time.Now().UTC().Add(authorizationResponseExpirationInSeconds * time.Second).Unix(),
+	}
+	for key := range parameters {
+		claims[key] = parameters.Get(key)
+	}
+
+	return signToken(claims, privKey, keyPair.KeyIdentifier)
+}
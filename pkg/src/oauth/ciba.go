@@ -0,0 +1,93 @@
+package oauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BackChannelAuthenticationResponse is the successful authentication request acknowledgement (OpenID Connect CIBA, section 7.3).
+type BackChannelAuthenticationResponse struct {
+	AuthReqId string `json:"auth_req_id"`
+	ExpiresIn int    `json:"expires_in"`
+	Interval  int    `json:"interval,omitempty"`
+}
+
+type CIBAPingNotification struct {
+	ClientIdentifier           string
+	ClientNotificationEndpoint string
+	ClientNotificationToken    string
+	AuthReqId                  string
+}
+
+// CIBAPingNotifier tells the clients using the ping mode that
+// the result of a backchannel authentication can be fetched
+type CIBAPingNotifier struct {
+	httpClient  *http.Client
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+func NewCIBAPingNotifier(httpClient *http.Client) *CIBAPingNotifier {
+	return &CIBAPingNotifier{
+		httpClient:  httpClient,
+		maxAttempts: 3,
+		retryDelay:  2 * time.Second,
+	}
+}
+
+// NotifyCIBAPing delivers the notification in the background,
+// the client can still poll the token endpoint if it is never delivered
+func (n *CIBAPingNotifier) NotifyCIBAPing(notification CIBAPingNotification) {
+	go func() {
+		if err := n.deliver(notification); err != nil {
+			slog.Warn(fmt.Sprintf("unable to deliver the CIBA ping notification to client %v: %v",
+				notification.ClientIdentifier, err))
+		}
+	}()
+}
+
+// deliver posts the auth_req_id with the client notification token as bearer token (OpenID Connect CIBA, section 10.2),
+// retrying when the client can't be reached or fails with a server error
+func (n *CIBAPingNotifier) deliver(notification CIBAPingNotification) (err error) {
+	body, err := json.Marshal(map[string]string{"auth_req_id": notification.AuthReqId})
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal the ping notification")
+	}
+
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * n.retryDelay)
+		}
+
+		var req *http.Request
+		if req, err = http.NewRequest(http.MethodPost, notification.ClientNotificationEndpoint, bytes.NewReader(body)); err != nil {
+			return errors.Wrap(err, "unable to create the ping notification request")
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+notification.ClientNotificationToken)
+
+		var resp *http.Response
+		resp, err = n.httpClient.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+
+		err = errors.Errorf("the client notification endpoint responded with status %v", resp.StatusCode)
+		if resp.StatusCode < 500 {
+			return err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCIBAPingNotifierDeliver(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCodes      []int
+		expectedAttempts int32
+		expectError      bool
+	}{
+		{name: "Delivered", statusCodes: []int{http.StatusNoContent}, expectedAttempts: 1},
+		{name: "Retried after a server error", statusCodes: []int{http.StatusBadGateway, http.StatusOK}, expectedAttempts: 2},
+		{name: "Rejected notification is not retried", statusCodes: []int{http.StatusUnauthorized}, expectedAttempts: 1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer notification-token", r.Header.Get("Authorization"))
+
+				var body map[string]string
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, map[string]string{"auth_req_id": "auth-req-id"}, body)
+				w.WriteHeader(tt.statusCodes[attempt-1])
+			}))
+			defer server.Close()
+
+			notifier := NewCIBAPingNotifier(server.Client())
+			notifier.retryDelay = time.Millisecond
+
+			err := notifier.deliver(CIBAPingNotification{
+				ClientIdentifier:           "test-client",
+				ClientNotificationEndpoint: server.URL,
+				ClientNotificationToken:    "notification-token",
+				AuthReqId:                  "auth-req-id",
+			})
+			assert.Equal(t, tt.expectError, err != nil)
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
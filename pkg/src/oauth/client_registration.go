@@ -0,0 +1,58 @@
+package oauth
+
+import "encoding/json"
+
+// ClientMetadata holds the client metadata accepted by the
+// dynamic client registration endpoint (RFC 7591, section 2).
+// web_origins is an extension used for CORS on the token endpoint and
+// require_pushed_authorization_requests is defined in RFC 9126, section 6.
+// jwks and jwks_uri register the keys of private_key_jwt and self_signed_tls_client_auth
+// clients, tls_client_auth_subject_dn is defined in RFC 8705, section 2.1.2 and
+// require_signed_request_object in RFC 9101, section 10.5. The logout metadata is defined
+// by OpenID Connect RP-Initiated, Front-Channel and Back-Channel Logout, subject_type and
+// sector_identifier_uri by OpenID Connect Dynamic Client Registration, section 2, as well as
+// the encryption of the ID token and userinfo responses. jwks and jwks_uri also register the
+// RSA-OAEP-256 keys those responses are encrypted with. backchannel_token_delivery_mode and
+// backchannel_client_notification_endpoint are defined in OpenID Connect CIBA, section 4,
+// the encryption of the authorization responses in JARM, section 3.
+type ClientMetadata struct {
+	RedirectURIs                          []string        `json:"redirect_uris,omitempty"`
+	PostLogoutRedirectURIs                []string        `json:"post_logout_redirect_uris,omitempty"`
+	FrontChannelLogoutURI                 string          `json:"frontchannel_logout_uri,omitempty"`
+	BackChannelLogoutURI                  string          `json:"backchannel_logout_uri,omitempty"`
+	SubjectType                           string          `json:"subject_type,omitempty"`
+	SectorIdentifierURI                   string          `json:"sector_identifier_uri,omitempty"`
+	IdTokenEncryptedResponseAlg           string          `json:"id_token_encrypted_response_alg,omitempty"`
+	IdTokenEncryptedResponseEnc           string          `json:"id_token_encrypted_response_enc,omitempty"`
+	UserInfoEncryptedResponseAlg          string          `json:"userinfo_encrypted_response_alg,omitempty"`
+	UserInfoEncryptedResponseEnc          string          `json:"userinfo_encrypted_response_enc,omitempty"`
+	AuthorizationEncryptedResponseAlg     string          `json:"authorization_encrypted_response_alg,omitempty"`
+	AuthorizationEncryptedResponseEnc     string          `json:"authorization_encrypted_response_enc,omitempty"`
+	TokenEndpointAuthMethod               string          `json:"token_endpoint_auth_method,omitempty"`
+	Jwks                                  json.RawMessage `json:"jwks,omitempty"`
+	JwksURI                               string          `json:"jwks_uri,omitempty"`
+	TLSClientAuthSubjectDN                string          `json:"tls_client_auth_subject_dn,omitempty"`
+	GrantTypes                            []string        `json:"grant_types,omitempty"`
+	ResponseTypes                         []string        `json:"response_types,omitempty"`
+	ClientName                            string          `json:"client_name,omitempty"`
+	Scope                                 string          `json:"scope,omitempty"`
+	WebOrigins                            []string        `json:"web_origins,omitempty"`
+	RequirePushedAuthorizationRequests    bool            `json:"require_pushed_authorization_requests,omitempty"`
+	RequireSignedRequestObject            bool            `json:"require_signed_request_object,omitempty"`
+	BackChannelTokenDeliveryMode          string          `json:"backchannel_token_delivery_mode,omitempty"`
+	BackChannelClientNotificationEndpoint string          `json:"backchannel_client_notification_endpoint,omitempty"`
+	// SectorIdentifier is resolved from the metadata when it is validated
+	SectorIdentifier string `json:"-"`
+}
+
+// ClientRegistrationResponse is the client information response
+// (RFC 7591, section 3.2.1 and RFC 7592, section 3).
+type ClientRegistrationResponse struct {
+	ClientId                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientIdIssuedAt        int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri"`
+	ClientMetadata
+}
@@ -63,6 +63,8 @@ func (ci *CodeIssuer) CreateAuthCode(input *CreateCodeInput) (*models.Code, erro
 		CodeChallengeMethod: input.CodeChallengeMethod,
 		RedirectURI:         input.RedirectURI,
 		Scope:               scope,
+		Resource:            input.Resource,
+		Claims:              input.GetGrantedClaims(),
 		State:               input.State,
 		Nonce:               input.Nonce,
 		UserAgent:           input.UserAgent,
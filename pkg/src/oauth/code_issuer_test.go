@@ -66,6 +66,26 @@ func TestCreateAuthCode(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestCreateAuthCode_GrantedClaims(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	codeIssuer := NewCodeIssuer(mockDB)
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{Id: 1}, nil)
+	mockDB.On("CreateCode", mock.Anything, mock.AnythingOfType("*models.Code")).Return(nil)
+	input := &CreateCodeInput{
+		AuthContext: AuthContext{
+			ClientId:        "test-client",
+			Scope:           "openid",
+			Claims:          `{"userinfo":{"birthdate":null,"email":{"essential":true}},"id_token":{"acr":{"essential":true,"value":"urn:goiabada:level1"},"birthdate":null}}`,
+			ConsentedClaims: "email",
+		},
+	}
+
+	code, err := codeIssuer.CreateAuthCode(input)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"userinfo":{"email":{"essential":true}},"id_token":{"acr":{"essential":true,"value":"urn:goiabada:level1"}}}`, code.Claims)
+}
+
 func TestCreateAuthCode_DefaultResponseMode(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	codeIssuer := NewCodeIssuer(mockDB)
@@ -0,0 +1,47 @@
+package oauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"time"
+)
+
+// DPoPNonceLifetime is how long a nonce issued by the server is accepted in DPoP proofs
+const DPoPNonceLifetime = 5 * time.Minute
+
+// NewDPoPNonce returns a nonce for DPoP proofs (RFC 9449, section 8).
+// Nonces are not stored, they hold their issue time and a MAC over it.
+func NewDPoPNonce(key []byte) string {
+	return newDPoPNonce(key, time.Now().UTC())
+}
+
+// IsDPoPNonceValid reports whether the nonce was issued by the server
+// with the key and has not expired
+func IsDPoPNonceValid(key []byte, nonce string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil || len(decoded) != 8+sha256.Size {
+		return false
+	}
+
+	issuedAt := time.Unix(int64(binary.BigEndian.Uint64(decoded[:8])), 0)
+	if !hmac.Equal(decoded[8:], dpopNonceMAC(key, decoded[:8])) {
+		return false
+	}
+
+	age := time.Since(issuedAt)
+	return age >= -time.Minute && age <= DPoPNonceLifetime
+}
+
+func newDPoPNonce(key []byte, issuedAt time.Time) string {
+	timestamp := binary.BigEndian.AppendUint64(nil, uint64(issuedAt.Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(timestamp, dpopNonceMAC(key, timestamp)...))
+}
+
+func dpopNonceMAC(key []byte, timestamp []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("dpop-nonce:"))
+	mac.Write(timestamp)
+	return mac.Sum(nil)
+}
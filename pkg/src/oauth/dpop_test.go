@@ -0,0 +1,26 @@
+package oauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDPoPNonce(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	nonce := NewDPoPNonce(key)
+
+	assert.True(t, IsDPoPNonceValid(key, nonce))
+	assert.False(t, IsDPoPNonceValid([]byte("another key"), nonce))
+	assert.False(t, IsDPoPNonceValid(key, nonce[:len(nonce)-2]+"AA"))
+	assert.False(t, IsDPoPNonceValid(key, ""))
+	assert.False(t, IsDPoPNonceValid(key, "not a nonce!"))
+}
+
+func TestDPoPNonce_Expired(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	assert.True(t, IsDPoPNonceValid(key, newDPoPNonce(key, time.Now().Add(-DPoPNonceLifetime+time.Minute))))
+	assert.False(t, IsDPoPNonceValid(key, newDPoPNonce(key, time.Now().Add(-DPoPNonceLifetime-time.Minute))))
+	assert.False(t, IsDPoPNonceValid(key, newDPoPNonce(key, time.Now().Add(10*time.Minute))))
+}
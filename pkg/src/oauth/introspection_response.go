@@ -0,0 +1,18 @@
+package oauth
+
+// IntrospectionResponse is the token introspection response (RFC 7662).
+// Inactive tokens carry only the active member.
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientId  string   `json:"client_id,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Jti       string   `json:"jti,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	// Confirmation holds the key or certificate binding of the token (RFC 7800, RFC 8705 section 3.2)
+	Confirmation map[string]string `json:"cnf,omitempty"`
+}
@@ -1,14 +1,20 @@
 package oauth
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/pchchv/aas/pkg/src/enums"
 	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/oidc"
 )
 
+// userInfoClaimsClaim is the claim of the access token holding the claims
+// requested individually from the userinfo endpoint with the claims parameter
+const userInfoClaimsClaim = "userinfo_claims"
+
 type Jwt struct {
 	TokenBase64 string
 	Claims      jwt.MapClaims
@@ -34,6 +40,35 @@ func (jwt Jwt) GetStringClaim(claimName string) string {
 	return ""
 }
 
+// GetConfirmationClaim returns a member of the cnf claim (RFC 7800), such as x5t#S256
+func (jwt Jwt) GetConfirmationClaim(member string) string {
+	if cnf, ok := jwt.Claims["cnf"].(map[string]interface{}); ok {
+		if value, ok := cnf[member].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetUserInfoClaimsRequest returns the claims requested individually from the userinfo endpoint
+// when the access token was issued, nil when there are none
+func (jwt Jwt) GetUserInfoClaimsRequest() map[string]*oidc.ClaimRequest {
+	if jwt.Claims[userInfoClaimsClaim] == nil {
+		return nil
+	}
+
+	claimsJson, err := json.Marshal(jwt.Claims[userInfoClaimsClaim])
+	if err != nil {
+		return nil
+	}
+
+	var requestedClaims map[string]*oidc.ClaimRequest
+	if err = json.Unmarshal(claimsJson, &requestedClaims); err != nil {
+		return nil
+	}
+	return requestedClaims
+}
+
 func (jwt Jwt) GetBoolClaim(claimName string) *bool {
 	if jwt.Claims[claimName] != nil {
 		if b, ok := jwt.Claims[claimName].(bool); ok {
@@ -0,0 +1,110 @@
+package oauth
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+// BackChannelLogoutEvent is the member of the events claim
+// that identifies a logout token (Back-Channel Logout, section 2.4)
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// logoutTokenExpirationInSeconds keeps the logout token short-lived,
+// it is only meant to be delivered right after the logout
+const logoutTokenExpirationInSeconds = 120
+
+type BackChannelLogoutNotification struct {
+	ClientIdentifier string
+	LogoutURI        string
+	LogoutToken      string
+}
+
+// LogoutNotifier delivers logout tokens to the back-channel logout URIs of the clients
+type LogoutNotifier struct {
+	httpClient  *http.Client
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+func NewLogoutNotifier(httpClient *http.Client) *LogoutNotifier {
+	return &LogoutNotifier{
+		httpClient:  httpClient,
+		maxAttempts: 3,
+		retryDelay:  2 * time.Second,
+	}
+}
+
+// GenerateLogoutToken signs a logout token for the client, identifying the
+// user and the session that ended (Back-Channel Logout, section 2.4)
+func GenerateLogoutToken(keyPair *models.KeyPair, issuer string, clientId string, subject string, sessionIdentifier string) (string, error) {
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to parse private key from PEM")
+	}
+
+	now := time.Now().UTC()
+	claims := jwt.MapClaims{
+		"iss":    issuer,
+		"sub":    subject,
+		"aud":    clientId,
+		"iat":    now.Unix(),
+		"exp":    now.Add(logoutTokenExpirationInSeconds * time.Second).Unix(),
+		"jti":    uuid.New().String(),
+		"sid":    sessionIdentifier,
+		"events": map[string]interface{}{BackChannelLogoutEvent: map[string]interface{}{}},
+	}
+
+	return signToken(claims, privKey, keyPair.KeyIdentifier)
+}
+
+// NotifyBackChannelLogout delivers the logout tokens in the background,
+// so that slow or unavailable clients do not hold up the logout of the user
+func (n *LogoutNotifier) NotifyBackChannelLogout(notifications []BackChannelLogoutNotification) {
+	for _, notification := range notifications {
+		go func(notification BackChannelLogoutNotification) {
+			if err := n.deliver(notification); err != nil {
+				slog.Warn(fmt.Sprintf("unable to deliver the back-channel logout to client %v: %v",
+					notification.ClientIdentifier, err))
+			}
+		}(notification)
+	}
+}
+
+// deliver posts the logout token, retrying when the client can't be reached or fails with a server error.
+// A client error means the logout token was rejected and is not retried (Back-Channel Logout, section 2.8).
+func (n *LogoutNotifier) deliver(notification BackChannelLogoutNotification) (err error) {
+	body := url.Values{"logout_token": {notification.LogoutToken}}.Encode()
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * n.retryDelay)
+		}
+
+		var resp *http.Response
+		resp, err = n.httpClient.Post(notification.LogoutURI, "application/x-www-form-urlencoded", strings.NewReader(body))
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+
+		err = errors.Errorf("the back-channel logout URI responded with status %v", resp.StatusCode)
+		if resp.StatusCode < 500 {
+			return err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateLogoutToken(t *testing.T) {
+	keyPair := &models.KeyPair{
+		KeyIdentifier: "test-key",
+		PrivateKeyPEM: getTestPrivateKey(t),
+	}
+
+	logoutToken, err := GenerateLogoutToken(keyPair, "https://test-issuer.com", "test-client", "user-subject", "session-123")
+	require.NoError(t, err)
+
+	claims := verifyAndDecodeToken(t, logoutToken, getTestPublicKey(t))
+	assert.Equal(t, "https://test-issuer.com", claims["iss"])
+	assert.Equal(t, "test-client", claims["aud"])
+	assert.Equal(t, "user-subject", claims["sub"])
+	assert.Equal(t, "session-123", claims["sid"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.NotContains(t, claims, "nonce")
+	assert.Equal(t, map[string]interface{}{BackChannelLogoutEvent: map[string]interface{}{}}, claims["events"])
+}
+
+func TestLogoutNotifierDeliver(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCodes      []int
+		expectedAttempts int32
+		expectError      bool
+	}{
+		{name: "Delivered", statusCodes: []int{http.StatusOK}, expectedAttempts: 1},
+		{name: "Retried after a server error", statusCodes: []int{http.StatusServiceUnavailable, http.StatusNoContent}, expectedAttempts: 2},
+		{name: "Rejected logout token is not retried", statusCodes: []int{http.StatusBadRequest}, expectedAttempts: 1, expectError: true},
+		{
+			name:             "Gives up after the maximum number of attempts",
+			statusCodes:      []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedAttempts: 3,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+				assert.Equal(t, "logout-token", r.FormValue("logout_token"))
+				w.WriteHeader(tt.statusCodes[attempt-1])
+			}))
+			defer server.Close()
+
+			notifier := NewLogoutNotifier(server.Client())
+			notifier.retryDelay = time.Millisecond
+
+			err := notifier.deliver(BackChannelLogoutNotification{
+				ClientIdentifier: "test-client",
+				LogoutURI:        server.URL,
+				LogoutToken:      "logout-token",
+			})
+			assert.Equal(t, tt.expectError, err != nil)
+			assert.Equal(t, tt.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
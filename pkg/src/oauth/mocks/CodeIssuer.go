@@ -4,7 +4,7 @@ package mocks_oauth
 
 import (
 	"github.com/stretchr/testify/mock"
-	
+
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/pchchv/aas/pkg/src/oauth"
 )
@@ -56,4 +56,4 @@ func NewCodeIssuer(t interface {
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
 	return mock
-}
\ No newline at end of file
+}
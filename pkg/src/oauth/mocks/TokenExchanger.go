@@ -3,8 +3,8 @@
 package mocks_oauth
 
 import (
-	"github.com/stretchr/testify/mock"
 	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/stretchr/testify/mock"
 )
 
 // TokenExchanger is an autogenerated mock type for the TokenExchanger type
@@ -54,4 +54,4 @@ func NewTokenExchanger(t interface {
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
 	return mock
-}
\ No newline at end of file
+}
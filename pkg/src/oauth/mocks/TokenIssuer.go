@@ -5,9 +5,9 @@ package mocks_oauth
 import (
 	"context"
 
-	"github.com/stretchr/testify/mock"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/stretchr/testify/mock"
 )
 
 // TokenIssuer is an autogenerated mock type for the TokenIssuer type
@@ -105,6 +105,36 @@ func (_m *TokenIssuer) GenerateTokenResponseForRefresh(ctx context.Context, inpu
 	return r0, r1
 }
 
+// GenerateTokenResponseForTokenExchange provides a mock function with given fields: ctx, input
+func (_m *TokenIssuer) GenerateTokenResponseForTokenExchange(ctx context.Context, input *oauth.GenerateTokenForTokenExchangeInput) (*oauth.TokenResponse, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateTokenResponseForTokenExchange")
+	}
+
+	var r0 *oauth.TokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *oauth.GenerateTokenForTokenExchangeInput) (*oauth.TokenResponse, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *oauth.GenerateTokenForTokenExchangeInput) *oauth.TokenResponse); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oauth.TokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *oauth.GenerateTokenForTokenExchangeInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTokenIssuer creates a new instance of TokenIssuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTokenIssuer(t interface {
@@ -117,4 +147,4 @@ func NewTokenIssuer(t interface {
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
 	return mock
-}
\ No newline at end of file
+}
@@ -3,11 +3,10 @@
 package mocks_oauth
 
 import (
-	"crypto/rsa"
-	
-	"github.com/stretchr/testify/mock"
-	"github.com/pchchv/aas/pkg/src/oauth"
+	"crypto"
 
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/stretchr/testify/mock"
 )
 
 // TokenParser is an autogenerated mock type for the TokenParser type
@@ -46,7 +45,7 @@ func (_m *TokenParser) DecodeAndValidateTokenResponse(tokenResponse *oauth.Token
 }
 
 // DecodeAndValidateTokenString provides a mock function with given fields: token, pubKey, withExpirationCheck
-func (_m *TokenParser) DecodeAndValidateTokenString(token string, pubKey *rsa.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error) {
+func (_m *TokenParser) DecodeAndValidateTokenString(token string, pubKey crypto.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error) {
 	ret := _m.Called(token, pubKey, withExpirationCheck)
 
 	if len(ret) == 0 {
@@ -55,10 +54,10 @@ func (_m *TokenParser) DecodeAndValidateTokenString(token string, pubKey *rsa.Pu
 
 	var r0 *oauth.Jwt
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, *rsa.PublicKey, bool) (*oauth.Jwt, error)); ok {
+	if rf, ok := ret.Get(0).(func(string, crypto.PublicKey, bool) (*oauth.Jwt, error)); ok {
 		return rf(token, pubKey, withExpirationCheck)
 	}
-	if rf, ok := ret.Get(0).(func(string, *rsa.PublicKey, bool) *oauth.Jwt); ok {
+	if rf, ok := ret.Get(0).(func(string, crypto.PublicKey, bool) *oauth.Jwt); ok {
 		r0 = rf(token, pubKey, withExpirationCheck)
 	} else {
 		if ret.Get(0) != nil {
@@ -66,7 +65,7 @@ func (_m *TokenParser) DecodeAndValidateTokenString(token string, pubKey *rsa.Pu
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, *rsa.PublicKey, bool) error); ok {
+	if rf, ok := ret.Get(1).(func(string, crypto.PublicKey, bool) error); ok {
 		r1 = rf(token, pubKey, withExpirationCheck)
 	} else {
 		r1 = ret.Error(1)
@@ -87,4 +86,4 @@ func NewTokenParser(t interface {
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
 	return mock
-}
\ No newline at end of file
+}
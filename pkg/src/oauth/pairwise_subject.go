@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+// GetSubject returns the sub claim identifying the user to the client. Clients with the pairwise
+// subject type get a subject derived for their sector (OpenID Connect Core, section 8.1),
+// which is recorded so tokens carrying it can be resolved back to the user.
+func GetSubject(database database.Database, settings *models.Settings, client *models.Client, user *models.User) (string, error) {
+	if !client.UsesPairwiseSubject() {
+		return user.Subject.String(), nil
+	}
+
+	pairwiseSubject, err := database.GetPairwiseSubjectByUserIdAndSectorIdentifier(nil, user.Id, client.SectorIdentifier)
+	if err != nil {
+		return "", err
+	} else if pairwiseSubject != nil {
+		return pairwiseSubject.Subject, nil
+	}
+
+	// the secret salt keeps the clients, which know the public subject of the user,
+	// from computing the subject of other sectors
+	hash := sha256.New()
+	hash.Write([]byte(client.SectorIdentifier))
+	hash.Write([]byte(user.Subject.String()))
+	hash.Write(settings.AESEncryptionKey)
+	pairwiseSubject = &models.PairwiseSubject{
+		UserId:           user.Id,
+		SectorIdentifier: client.SectorIdentifier,
+		Subject:          base64.RawURLEncoding.EncodeToString(hash.Sum(nil)),
+	}
+
+	if err = database.CreatePairwiseSubject(nil, pairwiseSubject); err != nil {
+		// a concurrent request may have recorded the subject first
+		existing, getErr := database.GetPairwiseSubjectByUserIdAndSectorIdentifier(nil, user.Id, client.SectorIdentifier)
+		if getErr != nil || existing == nil {
+			return "", err
+		}
+		return existing.Subject, nil
+	}
+
+	return pairwiseSubject.Subject, nil
+}
+
+// ResolveSubject returns the user identified by the sub claim of a token issued to the client,
+// or nil when the subject doesn't match any user. A nil client resolves the public subject.
+func ResolveSubject(database database.Database, client *models.Client, subject string) (*models.User, error) {
+	if client == nil || !client.UsesPairwiseSubject() {
+		return database.GetUserBySubject(nil, subject)
+	}
+
+	pairwiseSubject, err := database.GetPairwiseSubjectBySectorIdentifierAndSubject(nil, client.SectorIdentifier, subject)
+	if err != nil || pairwiseSubject == nil {
+		return nil, err
+	}
+
+	return database.GetUserById(nil, pairwiseSubject.UserId)
+}
@@ -0,0 +1,7 @@
+package oauth
+
+// PushedAuthorizationResponse is the pushed authorization response (RFC 9126, section 2.2).
+type PushedAuthorizationResponse struct {
+	RequestURI string `json:"request_uri"`
+	ExpiresIn  int    `json:"expires_in"`
+}
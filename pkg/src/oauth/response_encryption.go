@@ -0,0 +1,109 @@
+package oauth
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+const maxClientJWKSResponseSize = 64 * 1024
+
+// encryptionKeysHttpClient fetches the jwks_uri of the clients whose responses are encrypted with their public key
+var encryptionKeysHttpClient = &http.Client{Timeout: 10 * time.Second}
+
+// EncryptForClient nests a signed JWT in a JWE for the client (OpenID Connect Core, section 16.14).
+// RSA-OAEP-256 encrypts with the RSA key the client registered, A256KW and dir with a key
+// derived from the client secret (OpenID Connect Core, section 10.2).
+func EncryptForClient(settings *models.Settings, client *models.Client, alg string, enc string, signedJwt string) (string, error) {
+	header := &encryption.JWEHeader{
+		Algorithm:   alg,
+		Encryption:  enc,
+		ContentType: "JWT",
+	}
+
+	var key interface{}
+	if alg == encryption.JWEAlgorithmRSAOAEP256 {
+		encryptionKey, err := getClientEncryptionKey(client)
+		if err != nil {
+			return "", err
+		}
+
+		header.KeyIdentifier = encryptionKey.KeyIdentifier
+		key = encryptionKey.Key
+	} else {
+		if len(client.ClientSecretEncrypted) == 0 {
+			return "", errors.WithStack(errors.New("the client has no secret to derive the encryption key from"))
+		}
+
+		clientSecret, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to decrypt the client secret")
+		}
+
+		if key, err = encryption.DeriveClientSecretKey(clientSecret, alg, enc); err != nil {
+			return "", err
+		}
+	}
+
+	jwe, err := encryption.EncryptJWE([]byte(signedJwt), header, key)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to encrypt the JWT for client "+client.ClientIdentifier)
+	}
+	return jwe, nil
+}
+
+// GenerateUserInfoJwt signs the userinfo claims of a client receiving them as a JWT,
+// adding the iss and aud claims (OpenID Connect Core, section 5.3.2)
+func GenerateUserInfoJwt(keyPair *models.KeyPair, issuer string, clientId string, userInfoClaims map[string]interface{}) (string, error) {
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to parse private key from PEM")
+	}
+
+	claims := jwt.MapClaims{
+		"iss": issuer,
+		"aud": clientId,
+	}
+	for name, value := range userInfoClaims {
+		claims[name] = value
+	}
+
+	return signToken(claims, privKey, keyPair.KeyIdentifier)
+}
+
+// getClientEncryptionKey returns the RSA encryption key registered inline or published at the jwks_uri of the client
+func getClientEncryptionKey(client *models.Client) (*keyutil.PublicJWK, error) {
+	jwks := client.JWKS
+	if len(client.JWKSURI) > 0 {
+		resp, err := encryptionKeysHttpClient.Get(client.JWKSURI)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to fetch the client JWKS")
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.WithStack(errors.Errorf("unable to fetch the client JWKS, status code %v", resp.StatusCode))
+		}
+
+		if jwks, err = io.ReadAll(io.LimitReader(resp.Body, maxClientJWKSResponseSize)); err != nil {
+			return nil, errors.Wrap(err, "unable to read the client JWKS")
+		}
+	}
+
+	encryptionKeys, err := keyutil.ParseEncryptionJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionKey := keyutil.SelectRSAEncryptionKey(encryptionKeys, encryption.JWEAlgorithmRSAOAEP256)
+	if encryptionKey == nil {
+		return nil, errors.WithStack(errors.New("the client JWKS has no RSA encryption key"))
+	}
+	return encryptionKey, nil
+}
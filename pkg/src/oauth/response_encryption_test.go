@@ -0,0 +1,81 @@
+package oauth
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEncryptionKeyPair(t *testing.T) ([]byte, *rsa.PrivateKey, string) {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmRS256)
+	require.NoError(t, err)
+
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+
+	jwk := strings.NewReplacer(`"sig"`, `"enc"`, `"RS256"`, `"RSA-OAEP-256"`).Replace(string(keyPair.PublicKeyJWK))
+	return []byte(`{"keys":[` + jwk + `]}`), privateKey.(*rsa.PrivateKey), keyPair.KeyIdentifier
+}
+
+func TestEncryptForClient_RSA(t *testing.T) {
+	jwks, privateKey, keyIdentifier := newEncryptionKeyPair(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(jwks) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		client models.Client
+	}{
+		{"Inline JWKS", models.Client{ClientIdentifier: "test-client", JWKS: jwks}},
+		{"jwks_uri", models.Client{ClientIdentifier: "test-client", JWKSURI: server.URL}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwe, err := EncryptForClient(&models.Settings{}, &tt.client, encryption.JWEAlgorithmRSAOAEP256, encryption.JWEEncryptionA128CBCHS256, "signed.jwt.value")
+			require.NoError(t, err)
+
+			plaintext, header, err := encryption.DecryptJWE(jwe, privateKey)
+			require.NoError(t, err)
+			assert.Equal(t, "signed.jwt.value", string(plaintext))
+			assert.Equal(t, keyIdentifier, header.KeyIdentifier)
+			assert.Equal(t, "JWT", header.ContentType)
+		})
+	}
+}
+
+func TestEncryptForClient_ClientSecret(t *testing.T) {
+	settings := &models.Settings{AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef")}
+	clientSecretEncrypted, err := encryption.EncryptText("client-secret", settings.AESEncryptionKey)
+	require.NoError(t, err)
+
+	client := &models.Client{ClientIdentifier: "test-client", ClientSecretEncrypted: clientSecretEncrypted}
+	jwe, err := EncryptForClient(settings, client, encryption.JWEAlgorithmDir, encryption.JWEEncryptionA256CBCHS512, "signed.jwt.value")
+	require.NoError(t, err)
+
+	key, err := encryption.DeriveClientSecretKey("client-secret", encryption.JWEAlgorithmDir, encryption.JWEEncryptionA256CBCHS512)
+	require.NoError(t, err)
+	plaintext, _, err := encryption.DecryptJWE(jwe, key)
+	require.NoError(t, err)
+	assert.Equal(t, "signed.jwt.value", string(plaintext))
+}
+
+func TestEncryptForClient_MissingKey(t *testing.T) {
+	_, err := EncryptForClient(&models.Settings{}, &models.Client{JWKS: []byte(`{"keys":[]}`)},
+		encryption.JWEAlgorithmRSAOAEP256, encryption.JWEEncryptionA128GCM, "signed.jwt.value")
+	assert.Error(t, err)
+
+	_, err = EncryptForClient(&models.Settings{}, &models.Client{},
+		encryption.JWEAlgorithmA256KW, encryption.JWEEncryptionA128GCM, "signed.jwt.value")
+	assert.Error(t, err)
+}
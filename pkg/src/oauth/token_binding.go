@@ -0,0 +1,82 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+)
+
+const (
+	// ConfirmationMethodX5tS256 is the cnf member with the thumbprint
+	// of the certificate a token is bound to (RFC 8705, section 3.1)
+	ConfirmationMethodX5tS256 = "x5t#S256"
+	// ConfirmationMethodJkt is the cnf member with the JWK thumbprint
+	// of the DPoP key a token is bound to (RFC 9449, section 6.1)
+	ConfirmationMethodJkt = "jkt"
+)
+
+// GetClientCertificates returns the certificate chain the client presented
+// in the TLS handshake, or nil if the request was not made over mutual TLS
+func GetClientCertificates(r *http.Request) []*x509.Certificate {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return r.TLS.PeerCertificates
+}
+
+// CertificateThumbprint returns the base64url-encoded SHA-256 hash of the DER encoding of the certificate
+func CertificateThumbprint(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// IsCertificateBindingValid reports whether the token can be used by a client presenting the certificate
+// chain. Tokens without an x5t#S256 confirmation can be used with or without a certificate.
+func IsCertificateBindingValid(token *Jwt, certs []*x509.Certificate) bool {
+	thumbprint := token.GetConfirmationClaim(ConfirmationMethodX5tS256)
+	if len(thumbprint) == 0 {
+		return true
+	}
+	return len(certs) > 0 && CertificateThumbprint(certs[0]) == thumbprint
+}
+
+// IsDPoPBindingValid reports whether the token can be used with a DPoP proof signed by the key
+// with the JWK thumbprint. Tokens without a jkt confirmation can't be used as DPoP-bound tokens.
+func IsDPoPBindingValid(token *Jwt, jkt string) bool {
+	return token.GetConfirmationClaim(ConfirmationMethodJkt) == jkt
+}
+
+// getConfirmationClaim returns the cnf claim (RFC 7800) binding the issued access tokens to the
+// client certificate and the DPoP key of the token request, or nil when they are not bound
+func getConfirmationClaim(ctx context.Context) map[string]interface{} {
+	cnf := getCertificateConfirmationClaim(ctx)
+	if jkt := getDPoPKeyThumbprint(ctx); len(jkt) > 0 {
+		if cnf == nil {
+			cnf = make(map[string]interface{})
+		}
+		cnf[ConfirmationMethodJkt] = jkt
+	}
+	return cnf
+}
+
+// getCertificateConfirmationClaim returns the cnf claim binding the issued refresh tokens to the client
+// certificate of the token request. Refresh tokens are bound to a DPoP key in the database instead,
+// and only for public clients (RFC 9449, section 5).
+func getCertificateConfirmationClaim(ctx context.Context) map[string]interface{} {
+	thumbprint, _ := ctx.Value(constants.ContextKeyClientCertificateThumbprint).(string)
+	if len(thumbprint) == 0 {
+		return nil
+	}
+	return map[string]interface{}{ConfirmationMethodX5tS256: thumbprint}
+}
+
+// getDPoPKeyThumbprint returns the JWK thumbprint of the key that signed the DPoP proof
+// of the token request, or an empty string when the request had no DPoP proof
+func getDPoPKeyThumbprint(ctx context.Context) string {
+	jkt, _ := ctx.Value(constants.ContextKeyDPoPKeyThumbprint).(string)
+	return jkt
+}
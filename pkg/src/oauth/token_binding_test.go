@@ -0,0 +1,72 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateThumbprint(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("certificate")}
+	hash := sha256.Sum256([]byte("certificate"))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(hash[:]), CertificateThumbprint(cert))
+}
+
+func TestGetClientCertificates(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("certificate")}
+	assert.Nil(t, GetClientCertificates(&http.Request{}))
+	assert.Nil(t, GetClientCertificates(&http.Request{TLS: &tls.ConnectionState{}}))
+	assert.Equal(t, []*x509.Certificate{cert}, GetClientCertificates(&http.Request{
+		TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+	}))
+}
+
+func TestIsCertificateBindingValid(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("certificate")}
+	otherCert := &x509.Certificate{Raw: []byte("other certificate")}
+	boundToken := &Jwt{Claims: map[string]interface{}{
+		"cnf": map[string]interface{}{ConfirmationMethodX5tS256: CertificateThumbprint(cert)},
+	}}
+	unboundToken := &Jwt{Claims: map[string]interface{}{}}
+
+	assert.True(t, IsCertificateBindingValid(boundToken, []*x509.Certificate{cert}))
+	assert.False(t, IsCertificateBindingValid(boundToken, []*x509.Certificate{otherCert}))
+	assert.False(t, IsCertificateBindingValid(boundToken, nil))
+	assert.True(t, IsCertificateBindingValid(unboundToken, nil))
+	assert.True(t, IsCertificateBindingValid(unboundToken, []*x509.Certificate{cert}))
+}
+
+func TestIsDPoPBindingValid(t *testing.T) {
+	boundToken := &Jwt{Claims: map[string]interface{}{
+		"cnf": map[string]interface{}{ConfirmationMethodJkt: "jkt"},
+	}}
+	unboundToken := &Jwt{Claims: map[string]interface{}{}}
+
+	assert.True(t, IsDPoPBindingValid(boundToken, "jkt"))
+	assert.False(t, IsDPoPBindingValid(boundToken, "other-jkt"))
+	assert.False(t, IsDPoPBindingValid(boundToken, ""))
+	assert.True(t, IsDPoPBindingValid(unboundToken, ""))
+	assert.False(t, IsDPoPBindingValid(unboundToken, "jkt"))
+}
+
+func TestGetConfirmationClaimFromContext(t *testing.T) {
+	assert.Nil(t, getConfirmationClaim(context.Background()))
+
+	ctx := context.WithValue(context.Background(), constants.ContextKeyClientCertificateThumbprint, "thumbprint")
+	assert.Equal(t, map[string]interface{}{ConfirmationMethodX5tS256: "thumbprint"}, getConfirmationClaim(ctx))
+
+	ctx = context.WithValue(ctx, constants.ContextKeyDPoPKeyThumbprint, "jkt")
+	assert.Equal(t, map[string]interface{}{ConfirmationMethodX5tS256: "thumbprint", ConfirmationMethodJkt: "jkt"}, getConfirmationClaim(ctx))
+	assert.Equal(t, map[string]interface{}{ConfirmationMethodX5tS256: "thumbprint"}, getCertificateConfirmationClaim(ctx))
+
+	ctx = context.WithValue(context.Background(), constants.ContextKeyDPoPKeyThumbprint, "jkt")
+	assert.Equal(t, map[string]interface{}{ConfirmationMethodJkt: "jkt"}, getConfirmationClaim(ctx))
+	assert.Nil(t, getCertificateConfirmationClaim(ctx))
+}
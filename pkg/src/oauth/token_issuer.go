@@ -2,8 +2,11 @@ package oauth
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strings"
@@ -15,6 +18,8 @@ import (
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/database"
 	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/keyutil"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/pchchv/aas/pkg/src/oidc"
 	"github.com/pkg/errors"
@@ -25,6 +30,16 @@ type GenerateTokenForRefreshInput struct {
 	RefreshToken     *models.RefreshToken
 	RefreshTokenInfo *Jwt
 	ScopeRequested   string
+	// Resource restricts the access token to one resource of the grant (RFC 8707)
+	Resource string
+}
+
+type GenerateTokenForTokenExchangeInput struct {
+	Client  *models.Client
+	Subject string
+	Scope   string
+	// Actor is the act claim of the issued token, nil when the client impersonates the subject
+	Actor map[string]interface{}
 }
 
 type TokenIssuer struct {
@@ -51,7 +66,7 @@ func (t *TokenIssuer) GenerateTokenResponseForAuthCode(ctx context.Context, code
 	}
 
 	var tokenResponse = TokenResponse{
-		TokenType: enums.TokenTypeBearer.String(),
+		TokenType: getTokenType(ctx),
 		ExpiresIn: int64(tokenExpirationInSeconds),
 	}
 
@@ -60,12 +75,13 @@ func (t *TokenIssuer) GenerateTokenResponseForAuthCode(ctx context.Context, code
 		return nil, err
 	}
 
-	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse private key from PEM")
 	}
 
 	now := time.Now().UTC()
+	cnf := getConfirmationClaim(ctx)
 
 	// access_token -----------------------------------------------------------------------
 
@@ -85,13 +101,19 @@ func (t *TokenIssuer) GenerateTokenResponseForAuthCode(ctx context.Context, code
 		return nil, err
 	}
 
-	accessTokenStr, scopeFromAccessToken, err := t.generateAccessToken(settings, code, code.Scope, now, privKey, keyPair.KeyIdentifier)
+	accessTokenStr, scopeFromAccessToken, err := t.generateAccessToken(settings, code, code.Scope, code.Resource, now, privKey, keyPair.KeyIdentifier, cnf)
 	if err != nil {
 		return nil, err
 	}
 	tokenResponse.AccessToken = accessTokenStr
 	tokenResponse.Scope = scopeFromAccessToken
 
+	// the refresh token keeps the whole grant, so tokens for the other resources can be requested with it
+	refreshTokenScope := scopeFromAccessToken
+	if len(code.Resource) > 0 {
+		refreshTokenScope = code.Scope
+	}
+
 	// id_token ---------------------------------------------------------------------------
 
 	scopes := strings.Split(code.Scope, " ")
@@ -105,7 +127,8 @@ func (t *TokenIssuer) GenerateTokenResponseForAuthCode(ctx context.Context, code
 
 	// refresh_token ----------------------------------------------------------------------
 
-	refreshToken, refreshExpiresIn, err := t.generateRefreshToken(settings, code, scopeFromAccessToken, now, privKey, keyPair.KeyIdentifier, nil)
+	refreshToken, refreshExpiresIn, err := t.generateRefreshToken(settings, code, refreshTokenScope, now, privKey, keyPair.KeyIdentifier, nil,
+		getCertificateConfirmationClaim(ctx), getDPoPKeyThumbprint(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +141,7 @@ func (t *TokenIssuer) GenerateTokenResponseForAuthCode(ctx context.Context, code
 func (t *TokenIssuer) GenerateTokenResponseForClientCred(ctx context.Context, client *models.Client, scope string) (*TokenResponse, error) {
 	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
 	var tokenResponse = TokenResponse{
-		TokenType: "Bearer",
+		TokenType: getTokenType(ctx),
 		ExpiresIn: int64(settings.TokenExpirationInSeconds),
 		Scope:     scope,
 	}
@@ -128,7 +151,7 @@ func (t *TokenIssuer) GenerateTokenResponseForClientCred(ctx context.Context, cl
 		return nil, err
 	}
 
-	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse private key from PEM")
 	}
@@ -141,6 +164,7 @@ func (t *TokenIssuer) GenerateTokenResponseForClientCred(ctx context.Context, cl
 
 	claims["iss"] = settings.Issuer
 	claims["sub"] = client.ClientIdentifier
+	claims["client_id"] = client.ClientIdentifier
 	claims["iat"] = now.Unix()
 	claims["jti"] = uuid.New().String()
 	audCollection := []string{}
@@ -171,11 +195,87 @@ func (t *TokenIssuer) GenerateTokenResponseForClientCred(ctx context.Context, cl
 	claims["typ"] = enums.TokenTypeBearer.String()
 	claims["exp"] = now.Add(time.Duration(time.Second * time.Duration(settings.TokenExpirationInSeconds))).Unix()
 	claims["scope"] = scope
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = keyPair.KeyIdentifier
-	accessToken, err := token.SignedString(privKey)
+	if cnf := getConfirmationClaim(ctx); cnf != nil {
+		claims["cnf"] = cnf
+	}
+
+	accessToken, err := t.encodeAccessToken(claims, client, privKey, keyPair.KeyIdentifier)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to sign access_token")
+		return nil, errors.Wrap(err, "unable to encode access_token")
+	}
+
+	tokenResponse.AccessToken = accessToken
+	return &tokenResponse, nil
+}
+
+// GenerateTokenResponseForTokenExchange issues an access token for the subject of the exchanged token,
+// narrowed to the requested scope. No refresh token is issued (RFC 8693, section 2.2.1).
+func (t *TokenIssuer) GenerateTokenResponseForTokenExchange(ctx context.Context, input *GenerateTokenForTokenExchangeInput) (*TokenResponse, error) {
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	tokenExpirationInSeconds := settings.TokenExpirationInSeconds
+	if input.Client.TokenExpirationInSeconds > 0 {
+		tokenExpirationInSeconds = input.Client.TokenExpirationInSeconds
+	}
+
+	var tokenResponse = TokenResponse{
+		TokenType:       getTokenType(ctx),
+		IssuedTokenType: constants.TokenTypeAccessToken,
+		ExpiresIn:       int64(tokenExpirationInSeconds),
+		Scope:           input.Scope,
+	}
+
+	keyPair, err := t.database.GetCurrentSigningKey(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse private key from PEM")
+	}
+
+	now := time.Now().UTC()
+	claims := make(jwt.MapClaims)
+	claims["iss"] = settings.Issuer
+	claims["sub"] = input.Subject
+	claims["client_id"] = input.Client.ClientIdentifier
+	claims["iat"] = now.Unix()
+	claims["jti"] = uuid.New().String()
+	audCollection := []string{}
+	for _, scope := range strings.Split(input.Scope, " ") {
+		parts := strings.Split(scope, ":")
+		if len(parts) != 2 {
+			return nil, errors.WithStack(fmt.Errorf("invalid scope: %v", scope))
+		}
+
+		if !slices.Contains(audCollection, parts[0]) {
+			audCollection = append(audCollection, parts[0])
+		}
+	}
+
+	switch len(audCollection) {
+	case 0:
+		return nil, errors.WithStack(fmt.Errorf("unable to generate an access token without an audience. scope: '%v'", input.Scope))
+	case 1:
+		claims["aud"] = audCollection[0]
+	default:
+		claims["aud"] = audCollection
+	}
+
+	claims["typ"] = enums.TokenTypeBearer.String()
+	claims["exp"] = now.Add(time.Duration(time.Second * time.Duration(tokenExpirationInSeconds))).Unix()
+	claims["scope"] = input.Scope
+	if input.Actor != nil {
+		claims["act"] = input.Actor
+	}
+
+	if cnf := getConfirmationClaim(ctx); cnf != nil {
+		claims["cnf"] = cnf
+	}
+
+	accessToken, err := t.encodeAccessToken(claims, input.Client, privKey, keyPair.KeyIdentifier)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode access_token")
 	}
 
 	tokenResponse.AccessToken = accessToken
@@ -199,7 +299,7 @@ func (t *TokenIssuer) GenerateTokenResponseForRefresh(ctx context.Context, input
 	}
 
 	var tokenResponse = TokenResponse{
-		TokenType: enums.TokenTypeBearer.String(),
+		TokenType: getTokenType(ctx),
 		ExpiresIn: int64(tokenExpirationInSeconds),
 	}
 
@@ -208,12 +308,13 @@ func (t *TokenIssuer) GenerateTokenResponseForRefresh(ctx context.Context, input
 		return nil, err
 	}
 
-	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	privKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse private key from PEM")
 	}
 
 	now := time.Now().UTC()
+	cnf := getConfirmationClaim(ctx)
 
 	// access_token -----------------------------------------------------------------------
 
@@ -233,13 +334,18 @@ func (t *TokenIssuer) GenerateTokenResponseForRefresh(ctx context.Context, input
 		return nil, err
 	}
 
-	accessTokenStr, scopeFromAccessToken, err := t.generateAccessToken(settings, input.Code, scopeToUse, now, privKey, keyPair.KeyIdentifier)
+	accessTokenStr, scopeFromAccessToken, err := t.generateAccessToken(settings, input.Code, scopeToUse, input.Resource, now, privKey, keyPair.KeyIdentifier, cnf)
 	if err != nil {
 		return nil, err
 	}
 	tokenResponse.AccessToken = accessTokenStr
 	tokenResponse.Scope = scopeFromAccessToken
 
+	refreshTokenScope := scopeFromAccessToken
+	if len(input.Resource) > 0 {
+		refreshTokenScope = scopeToUse
+	}
+
 	// id_token ---------------------------------------------------------------------------
 
 	scopes := strings.Split(scopeToUse, " ")
@@ -253,7 +359,8 @@ func (t *TokenIssuer) GenerateTokenResponseForRefresh(ctx context.Context, input
 
 	// refresh_token ----------------------------------------------------------------------
 
-	if refreshToken, refreshExpiresIn, err := t.generateRefreshToken(settings, input.Code, scopeFromAccessToken, now, privKey, keyPair.KeyIdentifier, input.RefreshToken); err != nil {
+	if refreshToken, refreshExpiresIn, err := t.generateRefreshToken(settings, input.Code, refreshTokenScope, now, privKey, keyPair.KeyIdentifier, input.RefreshToken,
+		getCertificateConfirmationClaim(ctx), getDPoPKeyThumbprint(ctx)); err != nil {
 		return nil, err
 	} else {
 		tokenResponse.RefreshToken = refreshToken
@@ -263,6 +370,14 @@ func (t *TokenIssuer) GenerateTokenResponseForRefresh(ctx context.Context, input
 	return &tokenResponse, nil
 }
 
+// getTokenType returns DPoP when the tokens are bound to the DPoP key of the token request (RFC 9449, section 5)
+func getTokenType(ctx context.Context) string {
+	if len(getDPoPKeyThumbprint(ctx)) > 0 {
+		return enums.TokenTypeDPoP.String()
+	}
+	return enums.TokenTypeBearer.String()
+}
+
 func (t *TokenIssuer) addClaimIfNotEmpty(claims jwt.MapClaims, claimName string, claimValue string) {
 	if len(strings.TrimSpace(claimValue)) > 0 {
 		claims[claimName] = claimValue
@@ -308,10 +423,15 @@ func (t *TokenIssuer) addOpenIdConnectClaims(claims jwt.MapClaims, code *models.
 }
 
 func (t *TokenIssuer) generateIdToken(settings *models.Settings, code *models.Code, scope string,
-	now time.Time, signingKey *rsa.PrivateKey, keyIdentifier string) (idToken string, err error) {
+	now time.Time, signingKey crypto.PrivateKey, keyIdentifier string) (idToken string, err error) {
+	subject, err := GetSubject(t.database, settings, &code.Client, &code.User)
+	if err != nil {
+		return "", err
+	}
+
 	claims := make(jwt.MapClaims)
 	claims["iss"] = settings.Issuer
-	claims["sub"] = code.User.Subject
+	claims["sub"] = subject
 	claims["iat"] = now.Unix()
 	claims["auth_time"] = code.AuthenticatedAt.Unix()
 	claims["jti"] = uuid.New().String()
@@ -332,6 +452,11 @@ func (t *TokenIssuer) generateIdToken(settings *models.Settings, code *models.Co
 	}
 	t.addOpenIdConnectClaims(claims, code)
 
+	// claims requested individually for the ID token
+	if claimsRequest, err := oidc.ParseClaimsRequest(code.Claims); err == nil && claimsRequest != nil {
+		AddRequestedClaims(claims, &code.User, claimsRequest.IdToken)
+	}
+
 	// groups
 	if slices.Contains(scopes, "groups") {
 		groups := []string{}
@@ -368,20 +493,30 @@ func (t *TokenIssuer) generateIdToken(settings *models.Settings, code *models.Co
 		}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = keyIdentifier
-	if idToken, err = token.SignedString(signingKey); err != nil {
+	if idToken, err = signToken(claims, signingKey, keyIdentifier); err != nil {
 		return "", errors.Wrap(err, "unable to sign id_token")
 	}
 
+	// the ID token is signed, then encrypted (OpenID Connect Core, section 10.2)
+	if code.Client.EncryptsIdToken() {
+		idToken, err = EncryptForClient(settings, &code.Client, code.Client.IdTokenEncryptedResponseAlg,
+			code.Client.IdTokenEncryptedResponseEnc, idToken)
+	}
+
 	return
 }
 
-func (t *TokenIssuer) generateAccessToken(settings *models.Settings, code *models.Code, scope string,
-	now time.Time, signingKey *rsa.PrivateKey, keyIdentifier string) (string, string, error) {
+func (t *TokenIssuer) generateAccessToken(settings *models.Settings, code *models.Code, scope string, resource string,
+	now time.Time, signingKey crypto.PrivateKey, keyIdentifier string, cnf map[string]interface{}) (string, string, error) {
+	subject, err := GetSubject(t.database, settings, &code.Client, &code.User)
+	if err != nil {
+		return "", "", err
+	}
+
 	claims := make(jwt.MapClaims)
 	claims["iss"] = settings.Issuer
-	claims["sub"] = code.User.Subject
+	claims["sub"] = subject
+	claims["client_id"] = code.Client.ClientIdentifier
 	claims["iat"] = now.Unix()
 	claims["auth_time"] = code.AuthenticatedAt.Unix()
 	claims["jti"] = uuid.New().String()
@@ -389,11 +524,25 @@ func (t *TokenIssuer) generateAccessToken(settings *models.Settings, code *model
 	claims["amr"] = code.AuthMethods
 	claims["sid"] = code.SessionIdentifier
 	scopes := strings.Split(scope, " ")
+	if len(resource) > 0 {
+		// the access token is restricted to the requested resource (RFC 8707),
+		// the OpenID Connect scopes are kept for the claims they add
+		scopes = slices.DeleteFunc(scopes, func(s string) bool {
+			resourceIdentifier, _, found := strings.Cut(s, ":")
+			return found && resourceIdentifier != resource
+		})
+		scope = strings.Join(scopes, " ")
+	}
+
 	addUserInfoScope := false
 	audCollection := []string{}
 	for _, s := range scopes {
 		if oidc.IsIdTokenScope(s) {
 			// if an OIDC scope is present, give access to the userinfo endpoint
+			if len(resource) > 0 && resource != constants.AuthServerResourceIdentifier {
+				continue
+			}
+
 			if !slices.Contains(audCollection, constants.AuthServerResourceIdentifier) {
 				audCollection = append(audCollection, constants.AuthServerResourceIdentifier)
 			}
@@ -428,6 +577,11 @@ func (t *TokenIssuer) generateAccessToken(settings *models.Settings, code *model
 			scopes = append(scopes, userInfoScopeStr)
 		}
 		scope = strings.Join(scopes, " ")
+
+		// the userinfo endpoint learns the claims requested individually from the access token
+		if claimsRequest, err := oidc.ParseClaimsRequest(code.Claims); err == nil && claimsRequest != nil && len(claimsRequest.UserInfo) > 0 {
+			claims[userInfoClaimsClaim] = claimsRequest.UserInfo
+		}
 	}
 
 	claims["typ"] = enums.TokenTypeBearer.String()
@@ -442,6 +596,10 @@ func (t *TokenIssuer) generateAccessToken(settings *models.Settings, code *model
 		claims["nonce"] = code.Nonce
 	}
 
+	if cnf != nil {
+		claims["cnf"] = cnf
+	}
+
 	includeOpenIDConnectClaimsInAccessToken := settings.IncludeOpenIDConnectClaimsInAccessToken
 	if code.Client.IncludeOpenIDConnectClaimsInAccessToken == enums.ThreeStateSettingOn.String() ||
 		code.Client.IncludeOpenIDConnectClaimsInAccessToken == enums.ThreeStateSettingOff.String() {
@@ -488,11 +646,9 @@ func (t *TokenIssuer) generateAccessToken(settings *models.Settings, code *model
 		}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = keyIdentifier
-	accessToken, err := token.SignedString(signingKey)
+	accessToken, err := t.encodeAccessToken(claims, &code.Client, signingKey, keyIdentifier)
 	if err != nil {
-		return "", "", errors.Wrap(err, "unable to sign access_token")
+		return "", "", errors.Wrap(err, "unable to encode access_token")
 	}
 
 	return accessToken, scope, nil
@@ -538,14 +694,19 @@ func (t *TokenIssuer) getRefreshTokenMaxLifetime(refreshTokenType string, now ti
 	return 0, errors.WithStack(fmt.Errorf("invalid refresh token type: %v", refreshTokenType))
 }
 
-func (t *TokenIssuer) generateRefreshToken(settings *models.Settings, code *models.Code, scope string, now time.Time, signingKey *rsa.PrivateKey, keyIdentifier string, refreshToken *models.RefreshToken) (string, int64, error) {
+func (t *TokenIssuer) generateRefreshToken(settings *models.Settings, code *models.Code, scope string, now time.Time, signingKey crypto.PrivateKey, keyIdentifier string, refreshToken *models.RefreshToken, cnf map[string]interface{}, dpopJkt string) (string, int64, error) {
+	subject, err := GetSubject(t.database, settings, &code.Client, &code.User)
+	if err != nil {
+		return "", 0, err
+	}
+
 	claims := make(jwt.MapClaims)
 	jti := uuid.New().String()
 	claims["iss"] = settings.Issuer
 	claims["iat"] = now.Unix()
 	claims["jti"] = jti
 	claims["aud"] = settings.Issuer
-	claims["sub"] = code.User.Subject
+	claims["sub"] = subject
 	scopes := strings.Split(scope, " ")
 	if slices.Contains(scopes, oidc.OfflineAccessScope) {
 		// offline refresh token (not related to user session)
@@ -592,6 +753,9 @@ func (t *TokenIssuer) generateRefreshToken(settings *models.Settings, code *mode
 		}
 	}
 	claims["scope"] = scope
+	if cnf != nil {
+		claims["cnf"] = cnf
+	}
 
 	// save 1st refresh token
 	refreshTokenEntity := &models.RefreshToken{
@@ -611,6 +775,12 @@ func (t *TokenIssuer) generateRefreshToken(settings *models.Settings, code *mode
 		refreshTokenEntity.FirstRefreshTokenJti = jti
 	}
 
+	// refresh tokens of public clients are bound to the DPoP key,
+	// confidential clients are already bound by their authentication (RFC 9449, section 5)
+	if code.Client.IsPublic {
+		refreshTokenEntity.DPoPJkt = dpopJkt
+	}
+
 	if slices.Contains(scopes, oidc.OfflineAccessScope) {
 		t := time.Unix(claims["offline_access_max_lifetime"].(int64), 0)
 		refreshTokenEntity.MaxLifetime = sql.NullTime{Time: t, Valid: true}
@@ -622,9 +792,7 @@ func (t *TokenIssuer) generateRefreshToken(settings *models.Settings, code *mode
 		return "", 0, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = keyIdentifier
-	rt, err := token.SignedString(signingKey)
+	rt, err := signToken(claims, signingKey, keyIdentifier)
 	if err != nil {
 		return "", 0, errors.Wrap(err, "unable to sign refresh_token")
 	}
@@ -632,3 +800,71 @@ func (t *TokenIssuer) generateRefreshToken(settings *models.Settings, code *mode
 
 	return rt, refreshExpiresIn, nil
 }
+
+// encodeAccessToken signs the claims as a JWT or, for clients using opaque access tokens,
+// stores them and returns a random reference to them, so the client can't read the claims
+func (t *TokenIssuer) encodeAccessToken(claims jwt.MapClaims, client *models.Client, signingKey crypto.PrivateKey, keyIdentifier string) (string, error) {
+	if !client.UsesOpaqueAccessTokens() {
+		return signToken(claims, signingKey, keyIdentifier)
+	}
+
+	exp, ok := claims["exp"].(int64)
+	if !ok {
+		return "", errors.WithStack(errors.New("the access token has no expiration"))
+	}
+
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to marshal the access token claims")
+	}
+
+	accessToken, err := generateOpaqueAccessToken()
+	if err != nil {
+		return "", err
+	}
+
+	tokenHash, err := hashutil.HashString(accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	jti, _ := claims["jti"].(string)
+	if err = t.database.CreateOpaqueAccessToken(nil, &models.OpaqueAccessToken{
+		TokenHash: tokenHash,
+		Jti:       jti,
+		ClientId:  client.Id,
+		Claims:    string(claimsJson),
+		ExpiresAt: sql.NullTime{Time: time.Unix(exp, 0).UTC(), Valid: true},
+	}); err != nil {
+		return "", err
+	}
+
+	return accessToken, nil
+}
+
+// generateOpaqueAccessToken returns a random reference without dots, so it can't be taken for a JWT
+func generateOpaqueAccessToken() (string, error) {
+	b := make([]byte, 48)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "unable to generate the opaque access token")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// isOpaqueAccessToken tells opaque access tokens apart from JWTs, which always contain dots
+func isOpaqueAccessToken(token string) bool {
+	return !strings.Contains(token, ".")
+}
+
+// signToken signs the claims with the algorithm that matches the type of the signing key
+func signToken(claims jwt.MapClaims, signingKey crypto.PrivateKey, keyIdentifier string) (string, error) {
+	signingMethod, err := keyutil.GetSigningMethod(signingKey)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	token.Header["kid"] = keyIdentifier
+	return token.SignedString(signingKey)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -14,6 +15,8 @@ import (
 	"github.com/pchchv/aas/pkg/src/config"
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/hashutil"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -864,6 +867,7 @@ func TestGenerateTokenResponseForClientCred(t *testing.T) {
 
 			assert.Equal(t, settings.Issuer, claims["iss"])
 			assert.Equal(t, tt.client.ClientIdentifier, claims["sub"])
+			assert.Equal(t, tt.client.ClientIdentifier, claims["client_id"])
 			assert.Equal(t, tt.expectedAud, claims["aud"])
 			assert.Equal(t, "Bearer", claims["typ"])
 			assert.Equal(t, tt.scope, claims["scope"])
@@ -917,6 +921,163 @@ func TestGenerateTokenResponseForClientCred_InvalidScope(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestGenerateTokenResponseForClientCred_CertificateBound(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 3600,
+	}
+
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+	ctx = context.WithValue(ctx, constants.ContextKeyClientCertificateThumbprint, "thumbprint")
+
+	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+		KeyIdentifier: "test-key-id",
+		PrivateKeyPEM: getTestPrivateKey(t),
+	}, nil)
+
+	response, err := tokenIssuer.GenerateTokenResponseForClientCred(ctx, &models.Client{
+		Id:               1,
+		ClientIdentifier: "test-client-1",
+	}, "resource1:read")
+	assert.NoError(t, err)
+
+	claims := verifyAndDecodeToken(t, response.AccessToken, getTestPublicKey(t))
+	assert.Equal(t, map[string]interface{}{ConfirmationMethodX5tS256: "thumbprint"}, claims["cnf"])
+}
+
+func TestGenerateTokenResponseForClientCred_OpaqueAccessToken(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 3600,
+	}
+
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+		KeyIdentifier: "test-key-id",
+		PrivateKeyPEM: getTestPrivateKey(t),
+	}, nil)
+
+	var opaqueAccessToken *models.OpaqueAccessToken
+	mockDB.On("CreateOpaqueAccessToken", mock.Anything, mock.AnythingOfType("*models.OpaqueAccessToken")).
+		Run(func(args mock.Arguments) {
+			opaqueAccessToken = args.Get(1).(*models.OpaqueAccessToken)
+		}).Return(nil)
+
+	response, err := tokenIssuer.GenerateTokenResponseForClientCred(ctx, &models.Client{
+		Id:                7,
+		ClientIdentifier:  "partner-client",
+		AccessTokenFormat: constants.AccessTokenFormatOpaque,
+	}, "resource1:read")
+	assert.NoError(t, err)
+	assert.NotContains(t, response.AccessToken, ".")
+
+	tokenHash, _ := hashutil.HashString(response.AccessToken)
+	assert.Equal(t, tokenHash, opaqueAccessToken.TokenHash)
+	assert.Equal(t, int64(7), opaqueAccessToken.ClientId)
+	assert.NotEmpty(t, opaqueAccessToken.Jti)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), opaqueAccessToken.ExpiresAt.Time, 5*time.Second)
+
+	var claims map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(opaqueAccessToken.Claims), &claims))
+	assert.Equal(t, "partner-client", claims["client_id"])
+	assert.Equal(t, opaqueAccessToken.Jti, claims["jti"])
+	assert.Equal(t, "resource1:read", claims["scope"])
+	assert.Equal(t, "resource1", claims["aud"])
+}
+
+func TestGenerateTokenResponseForClientCred_DPoPBound(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 3600,
+	}
+
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+	ctx = context.WithValue(ctx, constants.ContextKeyDPoPKeyThumbprint, "jkt")
+
+	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+		KeyIdentifier: "test-key-id",
+		PrivateKeyPEM: getTestPrivateKey(t),
+	}, nil)
+
+	response, err := tokenIssuer.GenerateTokenResponseForClientCred(ctx, &models.Client{
+		Id:               1,
+		ClientIdentifier: "test-client-1",
+	}, "resource1:read")
+	assert.NoError(t, err)
+	assert.Equal(t, "DPoP", response.TokenType)
+
+	claims := verifyAndDecodeToken(t, response.AccessToken, getTestPublicKey(t))
+	assert.Equal(t, map[string]interface{}{ConfirmationMethodJkt: "jkt"}, claims["cnf"])
+}
+
+func TestGenerateTokenResponseForTokenExchange(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 3600,
+	}
+
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
+		KeyIdentifier: "test-key-id",
+		PrivateKeyPEM: getTestPrivateKey(t),
+	}, nil)
+
+	t.Run("Delegation", func(t *testing.T) {
+		response, err := tokenIssuer.GenerateTokenResponseForTokenExchange(ctx, &GenerateTokenForTokenExchangeInput{
+			Client:  &models.Client{Id: 1, ClientIdentifier: "gateway", TokenExpirationInSeconds: 600},
+			Subject: "user-subject",
+			Scope:   "resource1:read resource2:write",
+			Actor: map[string]interface{}{
+				"sub": "gateway",
+				"act": map[string]interface{}{"sub": "frontend"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer", response.TokenType)
+		assert.Equal(t, constants.TokenTypeAccessToken, response.IssuedTokenType)
+		assert.Equal(t, int64(600), response.ExpiresIn)
+		assert.Equal(t, "resource1:read resource2:write", response.Scope)
+		assert.Empty(t, response.RefreshToken)
+		assert.Empty(t, response.IdToken)
+
+		claims := verifyAndDecodeToken(t, response.AccessToken, getTestPublicKey(t))
+		assert.Equal(t, "user-subject", claims["sub"])
+		assert.Equal(t, "gateway", claims["client_id"])
+		assert.Equal(t, []interface{}{"resource1", "resource2"}, claims["aud"])
+		assert.Equal(t, "resource1:read resource2:write", claims["scope"])
+		assert.Equal(t, map[string]interface{}{
+			"sub": "gateway",
+			"act": map[string]interface{}{"sub": "frontend"},
+		}, claims["act"])
+	})
+
+	t.Run("Impersonation", func(t *testing.T) {
+		response, err := tokenIssuer.GenerateTokenResponseForTokenExchange(ctx, &GenerateTokenForTokenExchangeInput{
+			Client:  &models.Client{Id: 1, ClientIdentifier: "support-tool"},
+			Subject: "user-subject",
+			Scope:   "resource1:read",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3600), response.ExpiresIn)
+
+		claims := verifyAndDecodeToken(t, response.AccessToken, getTestPublicKey(t))
+		assert.Equal(t, "resource1", claims["aud"])
+		assert.NotContains(t, claims, "act")
+	})
+}
+
 func TestGenerateTokenResponseForRefresh(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	mockTokenParser := &TokenParser{}
@@ -1721,7 +1882,7 @@ func TestGenerateAccessToken(t *testing.T) {
 	code.Client = *client
 	code.User = *user
 	config.Get().BaseURL = "http://localhost:8081"
-	accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, now, privKey, "test-key-id")
+	accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, "", now, privKey, "test-key-id", nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, accessToken)
 	assert.Equal(t, "openid profile email authserver:userinfo", scope)
@@ -1754,6 +1915,56 @@ func TestGenerateAccessToken(t *testing.T) {
 	assertTimeClaimWithinRange(t, claims, "updated_at", -1*time.Hour, "updated_at should be 1 hour ago")
 }
 
+func TestGenerateAccessToken_ResourceIndicator(t *testing.T) {
+	tokenIssuer := NewTokenIssuer(mocks.NewDatabase(t), &TokenParser{})
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 600,
+	}
+
+	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(getTestPrivateKey(t))
+	assert.NoError(t, err)
+
+	code := &models.Code{
+		Scope:           "openid groups resource1:read resource1:write resource2:write",
+		AuthenticatedAt: time.Now().UTC(),
+		Client:          models.Client{ClientIdentifier: "test-client"},
+		User:            models.User{Subject: uuid.New()},
+	}
+
+	tests := []struct {
+		name          string
+		resource      string
+		expectedScope string
+		expectedAud   interface{}
+	}{
+		{
+			name:          "Resource of a resource scope",
+			resource:      "resource1",
+			expectedScope: "openid groups resource1:read resource1:write",
+			expectedAud:   "resource1",
+		},
+		{
+			name:          "Authserver resource",
+			resource:      constants.AuthServerResourceIdentifier,
+			expectedScope: "openid groups authserver:userinfo",
+			expectedAud:   constants.AuthServerResourceIdentifier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, tt.resource, time.Now().UTC(), privKey, "test-key-id", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedScope, scope)
+
+			claims := verifyAndDecodeToken(t, accessToken, getTestPublicKey(t))
+			assert.Equal(t, tt.expectedAud, claims["aud"])
+			assert.Equal(t, tt.expectedScope, claims["scope"])
+		})
+	}
+}
+
 func TestGenerateAccessToken_CustomScope(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	mockTokenParser := &TokenParser{}
@@ -1798,7 +2009,7 @@ func TestGenerateAccessToken_CustomScope(t *testing.T) {
 	code.Client = *client
 	code.User = *user
 
-	accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, now, privKey, "test-key-id")
+	accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, "", now, privKey, "test-key-id", nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, accessToken)
 	assert.Equal(t, "resource1:read resource2:write", scope)
@@ -1807,6 +2018,7 @@ func TestGenerateAccessToken_CustomScope(t *testing.T) {
 
 	assert.Equal(t, settings.Issuer, claims["iss"])
 	assert.Equal(t, user.Subject.String(), claims["sub"])
+	assert.Equal(t, "custom-client", claims["client_id"])
 	assert.Equal(t, []interface{}{"resource1", "resource2"}, claims["aud"])
 	assert.Equal(t, code.Nonce, claims["nonce"])
 	assert.Equal(t, code.AcrLevel, claims["acr"])
@@ -1892,7 +2104,7 @@ func TestGenerateAccessToken_WithGroupsAndAttributes(t *testing.T) {
 	code.User = *user
 
 	config.Get().BaseURL = "http://localhost:8081"
-	accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, now, privKey, "test-key-id")
+	accessToken, scope, err := tokenIssuer.generateAccessToken(settings, code, code.Scope, "", now, privKey, "test-key-id", nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, accessToken)
 	assert.Equal(t, "openid profile email groups attributes authserver:userinfo", scope)
@@ -1982,7 +2194,7 @@ func TestGenerateAccessToken_InvalidScope(t *testing.T) {
 	code.Client = *client
 	code.User = *user
 
-	_, _, err = tokenIssuer.generateAccessToken(settings, code, code.Scope, now, privKey, "test-key-id")
+	_, _, err = tokenIssuer.generateAccessToken(settings, code, code.Scope, "", now, privKey, "test-key-id", nil)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid scope")
 }
@@ -2190,6 +2402,88 @@ func TestGenerateIdToken_MinimalScope(t *testing.T) {
 	assert.NotContains(t, claims, "attributes")
 }
 
+func TestGenerateIdToken_RequestedClaims(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 300,
+	}
+
+	now := time.Now().UTC()
+	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(getTestPrivateKey(t))
+	assert.NoError(t, err)
+
+	code := &models.Code{
+		Scope:           "openid",
+		AuthenticatedAt: now,
+		AcrLevel:        "urn:goiabada:level1",
+		Claims:          `{"id_token":{"email":{"essential":true},"email_verified":{"value":false},"phone_number":null}}`,
+		Client:          models.Client{ClientIdentifier: "claims-client"},
+		User: models.User{
+			Subject:       uuid.New(),
+			Email:         "user@example.com",
+			EmailVerified: true,
+		},
+	}
+
+	idToken, err := tokenIssuer.generateIdToken(settings, code, code.Scope, now, privKey, "test-key-id")
+	assert.NoError(t, err)
+
+	claims := verifyAndDecodeToken(t, idToken, getTestPublicKey(t))
+	assert.Equal(t, "user@example.com", claims["email"])
+	// the value of the user doesn't match the requested one
+	assert.NotContains(t, claims, "email_verified")
+	// the user has no phone number
+	assert.NotContains(t, claims, "phone_number")
+}
+
+func TestGenerateIdToken_Encrypted(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+
+	settings := &models.Settings{
+		Issuer:                   "https://test-issuer.com",
+		TokenExpirationInSeconds: 300,
+		AESEncryptionKey:         []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	clientSecretEncrypted, err := encryption.EncryptText("client-secret", settings.AESEncryptionKey)
+	assert.NoError(t, err)
+
+	now := time.Now().UTC()
+	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(getTestPrivateKey(t))
+	assert.NoError(t, err)
+
+	code := &models.Code{
+		Scope:           "openid",
+		AuthenticatedAt: now,
+		AcrLevel:        "urn:goiabada:level1",
+		Nonce:           "nonce-1",
+		Client: models.Client{
+			ClientIdentifier:            "encrypting-client",
+			ClientSecretEncrypted:       clientSecretEncrypted,
+			IdTokenEncryptedResponseAlg: encryption.JWEAlgorithmA256KW,
+			IdTokenEncryptedResponseEnc: encryption.JWEEncryptionA256GCM,
+		},
+		User: models.User{Subject: uuid.New()},
+	}
+
+	idToken, err := tokenIssuer.generateIdToken(settings, code, code.Scope, now, privKey, "test-key-id")
+	assert.NoError(t, err)
+
+	key, err := encryption.DeriveClientSecretKey("client-secret", encryption.JWEAlgorithmA256KW, encryption.JWEEncryptionA256GCM)
+	assert.NoError(t, err)
+	signedIdToken, header, err := encryption.DecryptJWE(idToken, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "JWT", header.ContentType)
+
+	claims := verifyAndDecodeToken(t, string(signedIdToken), getTestPublicKey(t))
+	assert.Equal(t, "encrypting-client", claims["aud"])
+	assert.Equal(t, "nonce-1", claims["nonce"])
+}
+
 func TestGenerateIdToken_ClientOverride(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	mockTokenParser := &TokenParser{}
@@ -2320,7 +2614,7 @@ func TestGenerateRefreshToken_Offline(t *testing.T) {
 
 	mockDB.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
-	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, now, privKey, "test-key-id", nil)
+	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, now, privKey, "test-key-id", nil, nil, "")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, refreshToken)
@@ -2344,6 +2638,50 @@ func TestGenerateRefreshToken_Offline(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestGenerateRefreshToken_DPoPBound(t *testing.T) {
+	privKey, err := jwt.ParseRSAPrivateKeyFromPEM(getTestPrivateKey(t))
+	assert.NoError(t, err)
+
+	settings := &models.Settings{
+		Issuer:                                  "https://test-issuer.com",
+		RefreshTokenOfflineIdleTimeoutInSeconds: 3600,
+		RefreshTokenOfflineMaxLifetimeInSeconds: 86400,
+	}
+
+	tests := []struct {
+		name            string
+		isPublic        bool
+		expectedDPoPJkt string
+	}{
+		{"Public client", true, "jkt"},
+		{"Confidential client", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDatabase(t)
+			tokenIssuer := NewTokenIssuer(mockDB, &TokenParser{})
+			code := &models.Code{
+				Id:     1,
+				Scope:  "openid offline_access",
+				Client: models.Client{Id: 1, ClientIdentifier: "test-client", IsPublic: tt.isPublic},
+				User:   models.User{Id: 1, Subject: uuid.New()},
+			}
+
+			mockDB.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(refreshToken *models.RefreshToken) bool {
+				return refreshToken.DPoPJkt == tt.expectedDPoPJkt
+			})).Return(nil)
+
+			refreshToken, _, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, time.Now().UTC(), privKey, "test-key-id", nil, nil, "jkt")
+			assert.NoError(t, err)
+
+			// the DPoP key is recorded in the database, not in the refresh token
+			claims := verifyAndDecodeToken(t, refreshToken, getTestPublicKey(t))
+			assert.Nil(t, claims["cnf"])
+		})
+	}
+}
+
 func TestGenerateRefreshToken_Refresh(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	mockTokenParser := &TokenParser{}
@@ -2394,7 +2732,7 @@ func TestGenerateRefreshToken_Refresh(t *testing.T) {
 		LastAccessed: now.Add(-5 * time.Minute),
 	}, nil)
 
-	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, now, privKey, "test-key-id", nil)
+	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, now, privKey, "test-key-id", nil, nil, "")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, refreshToken)
@@ -2469,7 +2807,7 @@ func TestGenerateRefreshToken_WithExistingRefreshToken(t *testing.T) {
 
 	mockDB.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
-	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, now, privKey, "test-key-id", existingRefreshToken)
+	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, now, privKey, "test-key-id", existingRefreshToken, nil, "")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, refreshToken)
@@ -2558,7 +2896,7 @@ func TestGenerateRefreshToken_OfflineMaxLifetimeLimit(t *testing.T) {
 
 	// Now generate the third refresh token
 	thirdRefreshTime := initialTime
-	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, thirdRefreshTime, privKey, "test-key-id", secondRefreshToken)
+	refreshToken, refreshExpiresIn, err := tokenIssuer.generateRefreshToken(settings, code, code.Scope, thirdRefreshTime, privKey, "test-key-id", secondRefreshToken, nil, "")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, refreshToken)
@@ -1,14 +1,32 @@
 package oauth
 
 import (
-	"crypto/rsa"
+	"crypto"
+	"encoding/json"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/customerrors"
 	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// publicKeysMaxAge bounds how long a revoked or retired key keeps being accepted
+	publicKeysMaxAge = time.Minute
+	// publicKeysMinReloadInterval stops tokens with unknown kids from hitting the database on every request
+	publicKeysMinReloadInterval = 5 * time.Second
 )
 
 type TokenParser struct {
-	database database.Database
+	database       database.Database
+	publicKeysLock sync.RWMutex
+	publicKeys     map[string]crypto.PublicKey
+	publicKeysAt   time.Time
 }
 
 func NewTokenParser(database database.Database) *TokenParser {
@@ -17,18 +35,21 @@ func NewTokenParser(database database.Database) *TokenParser {
 	}
 }
 
-func (tp *TokenParser) DecodeAndValidateTokenString(token string, pubKey *rsa.PublicKey, withExpirationCheck bool) (t *Jwt, err error) {
-	if pubKey == nil {
-		if pubKey, err = tp.getPublicKey(); err != nil {
-			return nil, err
-		}
-	}
-
+// DecodeAndValidateTokenString verifies the token with pubKey or, when it is nil,
+// with the published signing key matching the kid in the token header.
+// Opaque access tokens are resolved to the claims stored when they were issued.
+func (tp *TokenParser) DecodeAndValidateTokenString(token string, pubKey crypto.PublicKey, withExpirationCheck bool) (t *Jwt, err error) {
 	t = &Jwt{
 		TokenBase64: token,
 	}
 
-	if len(token) > 0 {
+	if len(token) > 0 && isOpaqueAccessToken(token) {
+		if t.Claims, err = tp.resolveOpaqueAccessToken(token, withExpirationCheck); err != nil {
+			return nil, err
+		} else if err = tp.checkAccessTokenRevocation(t); err != nil {
+			return nil, err
+		}
+	} else if len(token) > 0 {
 		claims, opts := jwt.MapClaims{}, []jwt.ParserOption{}
 		if withExpirationCheck {
 			opts = append(opts, jwt.WithExpirationRequired())
@@ -37,40 +58,96 @@ func (tp *TokenParser) DecodeAndValidateTokenString(token string, pubKey *rsa.Pu
 		}
 
 		if _, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-			return pubKey, nil
+			verificationKey := pubKey
+			if verificationKey == nil {
+				kid, _ := token.Header["kid"].(string)
+				key, err := tp.getPublicKey(kid)
+				if err != nil {
+					return nil, err
+				}
+				verificationKey = key
+			}
+
+			// only the algorithm of the verification key is accepted,
+			// so a token can't pick a weaker one in its header
+			signingMethod, err := keyutil.GetSigningMethod(verificationKey)
+			if err != nil {
+				return nil, err
+			} else if token.Method.Alg() != signingMethod.Alg() {
+				return nil, errors.Wrapf(jwt.ErrTokenSignatureInvalid, "signing method %v is invalid", token.Method.Alg())
+			}
+
+			return verificationKey, nil
 		}, opts...); err != nil {
 			return nil, err
 		}
 		t.Claims = claims
+
+		if err = tp.checkAccessTokenRevocation(t); err != nil {
+			return nil, err
+		}
 	}
 
 	return
 }
 
-func (tp *TokenParser) DecodeAndValidateTokenResponse(tokenResponse *TokenResponse) (token *JwtInfo, err error) {
-	pubKey, err := tp.getPublicKey()
+// checkAccessTokenRevocation rejects revoked access tokens, which stay in the deny list until they expire
+func (tp *TokenParser) checkAccessTokenRevocation(t *Jwt) error {
+	if jti := t.GetStringClaim("jti"); len(jti) > 0 && t.GetStringClaim("typ") == enums.TokenTypeBearer.String() {
+		revokedAccessToken, err := tp.database.GetRevokedAccessTokenByJti(nil, jti)
+		if err != nil {
+			return err
+		} else if revokedAccessToken != nil {
+			return customerrors.ErrTokenRevoked
+		}
+	}
+
+	return nil
+}
+
+// resolveOpaqueAccessToken looks up the claims the opaque access token stands for
+func (tp *TokenParser) resolveOpaqueAccessToken(token string, withExpirationCheck bool) (jwt.MapClaims, error) {
+	tokenHash, err := hashutil.HashString(token)
 	if err != nil {
 		return nil, err
 	}
 
+	opaqueAccessToken, err := tp.database.GetOpaqueAccessTokenByTokenHash(nil, tokenHash)
+	if err != nil {
+		return nil, err
+	} else if opaqueAccessToken == nil {
+		return nil, customerrors.ErrUnknownOpaqueToken
+	} else if withExpirationCheck && opaqueAccessToken.IsExpired() {
+		return nil, jwt.ErrTokenExpired
+	}
+
+	claims := jwt.MapClaims{}
+	if err = json.Unmarshal([]byte(opaqueAccessToken.Claims), &claims); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal the opaque access token claims")
+	}
+
+	return claims, nil
+}
+
+func (tp *TokenParser) DecodeAndValidateTokenResponse(tokenResponse *TokenResponse) (token *JwtInfo, err error) {
 	token = &JwtInfo{
 		TokenResponse: *tokenResponse,
 	}
 
 	if len(tokenResponse.AccessToken) > 0 {
-		if token.AccessToken, err = tp.DecodeAndValidateTokenString(tokenResponse.AccessToken, pubKey, true); err != nil {
+		if token.AccessToken, err = tp.DecodeAndValidateTokenString(tokenResponse.AccessToken, nil, true); err != nil {
 			return nil, err
 		}
 	}
 
 	if len(tokenResponse.IdToken) > 0 {
-		if token.IdToken, err = tp.DecodeAndValidateTokenString(tokenResponse.IdToken, pubKey, true); err != nil {
+		if token.IdToken, err = tp.DecodeAndValidateTokenString(tokenResponse.IdToken, nil, true); err != nil {
 			return nil, err
 		}
 	}
 
 	if len(tokenResponse.RefreshToken) > 0 {
-		if token.RefreshToken, err = tp.DecodeAndValidateTokenString(tokenResponse.RefreshToken, pubKey, false); err != nil {
+		if token.RefreshToken, err = tp.DecodeAndValidateTokenString(tokenResponse.RefreshToken, nil, false); err != nil {
 			return nil, err
 		}
 	}
@@ -78,16 +155,55 @@ func (tp *TokenParser) DecodeAndValidateTokenResponse(tokenResponse *TokenRespon
 	return
 }
 
-func (tp *TokenParser) getPublicKey() (pubKey *rsa.PublicKey, err error) {
-	keyPair, err := tp.database.GetCurrentSigningKey(nil)
+// getPublicKey returns the public key of the current, previous or next signing key with the kid.
+// The parsed keys are cached, and reloaded when they get stale or the kid is not among them,
+// which is the case right after the keys were rotated on another server instance.
+func (tp *TokenParser) getPublicKey(kid string) (crypto.PublicKey, error) {
+	if len(kid) == 0 {
+		return nil, customerrors.ErrUnknownSigningKey
+	}
+
+	tp.publicKeysLock.RLock()
+	pubKey, ok := tp.publicKeys[kid]
+	age := time.Since(tp.publicKeysAt)
+	tp.publicKeysLock.RUnlock()
+	if ok && age < publicKeysMaxAge {
+		return pubKey, nil
+	} else if !ok && age < publicKeysMinReloadInterval {
+		return nil, customerrors.ErrUnknownSigningKey
+	}
+
+	publicKeys, err := tp.loadPublicKeys()
 	if err != nil {
 		return nil, err
 	}
 
-	pubKey, err = jwt.ParseRSAPublicKeyFromPEM(keyPair.PublicKeyPEM)
+	if pubKey, ok = publicKeys[kid]; !ok {
+		return nil, customerrors.ErrUnknownSigningKey
+	}
+
+	return pubKey, nil
+}
+
+func (tp *TokenParser) loadPublicKeys() (map[string]crypto.PublicKey, error) {
+	keyPairs, err := tp.database.GetAllSigningKeys(nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return
+	publicKeys := make(map[string]crypto.PublicKey, len(keyPairs))
+	for _, keyPair := range keyPairs {
+		pubKey, err := keyutil.ParsePublicKeyFromPEM(keyPair.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys[keyPair.KeyIdentifier] = pubKey
+	}
+
+	tp.publicKeysLock.Lock()
+	tp.publicKeys = publicKeys
+	tp.publicKeysAt = time.Now()
+	tp.publicKeysLock.Unlock()
+
+	return publicKeys, nil
 }
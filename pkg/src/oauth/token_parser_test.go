@@ -4,17 +4,24 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"database/sql"
 	"encoding/pem"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/customerrors"
 	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/keyutil"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+const testKeyIdentifier = "test-key"
+
 func TestDecodeAndValidateTokenString(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	tp := NewTokenParser(mockDB)
@@ -86,6 +93,91 @@ func TestDecodeAndValidateTokenString_InvalidSignature(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestDecodeAndValidateTokenString_RevokedAccessToken(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tp := NewTokenParser(mockDB)
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tokenString := createTestToken(privateKey, map[string]interface{}{"typ": "Bearer", "jti": "revoked-jti"}, time.Now().Add(time.Hour))
+	mockDB.On("GetRevokedAccessTokenByJti", mock.Anything, "revoked-jti").Return(&models.RevokedAccessToken{Jti: "revoked-jti"}, nil)
+
+	result, err := tp.DecodeAndValidateTokenString(tokenString, &privateKey.PublicKey, true)
+	assert.ErrorIs(t, err, customerrors.ErrTokenRevoked)
+	assert.Nil(t, result)
+}
+
+func TestDecodeAndValidateTokenString_AccessTokenNotRevoked(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tp := NewTokenParser(mockDB)
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tokenString := createTestToken(privateKey, map[string]interface{}{"typ": "Bearer", "jti": "valid-jti"}, time.Now().Add(time.Hour))
+	mockDB.On("GetRevokedAccessTokenByJti", mock.Anything, "valid-jti").Return(nil, nil)
+
+	result, err := tp.DecodeAndValidateTokenString(tokenString, &privateKey.PublicKey, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-jti", result.GetStringClaim("jti"))
+}
+
+func TestDecodeAndValidateTokenString_OpaqueAccessToken(t *testing.T) {
+	const opaqueToken = "c2VjcmV0LW9wYXF1ZS10b2tlbg"
+	tokenHash, _ := hashutil.HashString(opaqueToken)
+	claims := `{"typ":"Bearer","jti":"opaque-jti","sub":"user-subject","exp":1893456000}`
+
+	t.Run("Resolves the stored claims", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		tp := NewTokenParser(mockDB)
+		mockDB.On("GetOpaqueAccessTokenByTokenHash", mock.Anything, tokenHash).Return(&models.OpaqueAccessToken{
+			TokenHash: tokenHash,
+			Jti:       "opaque-jti",
+			Claims:    claims,
+			ExpiresAt: sql.NullTime{Time: time.Now().UTC().Add(time.Hour), Valid: true},
+		}, nil)
+		mockDB.On("GetRevokedAccessTokenByJti", mock.Anything, "opaque-jti").Return(nil, nil)
+
+		result, err := tp.DecodeAndValidateTokenString(opaqueToken, nil, true)
+		assert.NoError(t, err)
+		assert.Equal(t, opaqueToken, result.TokenBase64)
+		assert.Equal(t, "user-subject", result.GetStringClaim("sub"))
+		assert.Equal(t, float64(1893456000), result.Claims["exp"])
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		tp := NewTokenParser(mockDB)
+		mockDB.On("GetOpaqueAccessTokenByTokenHash", mock.Anything, tokenHash).Return(&models.OpaqueAccessToken{
+			Claims:    claims,
+			ExpiresAt: sql.NullTime{Time: time.Now().UTC().Add(time.Hour), Valid: true},
+		}, nil)
+		mockDB.On("GetRevokedAccessTokenByJti", mock.Anything, "opaque-jti").Return(&models.RevokedAccessToken{Jti: "opaque-jti"}, nil)
+
+		result, err := tp.DecodeAndValidateTokenString(opaqueToken, nil, true)
+		assert.ErrorIs(t, err, customerrors.ErrTokenRevoked)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		tp := NewTokenParser(mockDB)
+		mockDB.On("GetOpaqueAccessTokenByTokenHash", mock.Anything, tokenHash).Return(&models.OpaqueAccessToken{
+			Claims:    claims,
+			ExpiresAt: sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true},
+		}, nil)
+
+		result, err := tp.DecodeAndValidateTokenString(opaqueToken, nil, true)
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		tp := NewTokenParser(mockDB)
+		mockDB.On("GetOpaqueAccessTokenByTokenHash", mock.Anything, tokenHash).Return(nil, nil)
+
+		result, err := tp.DecodeAndValidateTokenString(opaqueToken, nil, true)
+		assert.ErrorIs(t, err, customerrors.ErrUnknownOpaqueToken)
+		assert.Nil(t, result)
+	})
+}
+
 func TestDecodeAndValidateTokenString_EmptyToken(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	tp := NewTokenParser(mockDB)
@@ -103,8 +195,8 @@ func TestDecodeAndValidateTokenResponse_ValidTokens(t *testing.T) {
 	tp := NewTokenParser(mockDB)
 	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
 	publicKeyPEM := exportRSAPublicKeyAsPEMStr(&privateKey.PublicKey)
-	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
-		PublicKeyPEM: []byte(publicKeyPEM),
+	mockDB.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		{KeyIdentifier: testKeyIdentifier, PublicKeyPEM: []byte(publicKeyPEM)},
 	}, nil)
 	now := time.Now()
 	expirationTime := now.Add(time.Hour)
@@ -189,11 +281,6 @@ func TestDecodeAndValidateTokenResponse_ValidTokens(t *testing.T) {
 func TestDecodeAndValidateTokenResponse_EmptyTokens(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	tp := NewTokenParser(mockDB)
-	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
-	publicKeyPEM := exportRSAPublicKeyAsPEMStr(&privateKey.PublicKey)
-	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
-		PublicKeyPEM: []byte(publicKeyPEM),
-	}, nil)
 
 	tokenResponse := &TokenResponse{
 		AccessToken:  "",
@@ -216,8 +303,8 @@ func TestDecodeAndValidateTokenResponse_ExpiredAccessToken(t *testing.T) {
 	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
 	publicKeyPEM := exportRSAPublicKeyAsPEMStr(&privateKey.PublicKey)
 
-	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
-		PublicKeyPEM: []byte(publicKeyPEM),
+	mockDB.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		{KeyIdentifier: testKeyIdentifier, PublicKeyPEM: []byte(publicKeyPEM)},
 	}, nil)
 
 	tokenResponse := &TokenResponse{
@@ -239,8 +326,8 @@ func TestDecodeAndValidateTokenResponse_InvalidSignature(t *testing.T) {
 	privateKeyAlternate, _ := rsa.GenerateKey(rand.Reader, 2048)
 	publicKeyPEM := exportRSAPublicKeyAsPEMStr(&privateKey.PublicKey)
 
-	mockDB.On("GetCurrentSigningKey", mock.Anything).Return(&models.KeyPair{
-		PublicKeyPEM: []byte(publicKeyPEM),
+	mockDB.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{
+		{KeyIdentifier: testKeyIdentifier, PublicKeyPEM: []byte(publicKeyPEM)},
 	}, nil)
 
 	tokenResponse := &TokenResponse{
@@ -256,6 +343,7 @@ func TestDecodeAndValidateTokenResponse_InvalidSignature(t *testing.T) {
 
 func createTestToken(privateKey *rsa.PrivateKey, claims map[string]interface{}, expirationTime time.Time) string {
 	token := jwt.New(jwt.SigningMethodRS256)
+	token.Header["kid"] = testKeyIdentifier
 	claims["exp"] = expirationTime.Unix()
 	for k, v := range claims {
 		token.Claims.(jwt.MapClaims)[k] = v
@@ -279,3 +367,98 @@ func exportRSAPublicKeyAsPEMStr(pubkey *rsa.PublicKey) string {
 	)
 	return string(pubkeyPem)
 }
+
+func TestDecodeAndValidateTokenResponse_KeyAlgorithms(t *testing.T) {
+	for _, algorithm := range []string{keyutil.AlgorithmES256, keyutil.AlgorithmES384, keyutil.AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			mockDB := mocks.NewDatabase(t)
+			tp := NewTokenParser(mockDB)
+			keyPair, err := keyutil.GenerateKeyPair(algorithm)
+			assert.NoError(t, err)
+			mockDB.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{*keyPair}, nil)
+
+			privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+			assert.NoError(t, err)
+			idToken, err := signToken(jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Hour).Unix()}, privateKey, keyPair.KeyIdentifier)
+			assert.NoError(t, err)
+
+			result, err := tp.DecodeAndValidateTokenResponse(&TokenResponse{IdToken: idToken})
+			assert.NoError(t, err)
+			assert.Equal(t, "user", result.IdToken.GetStringClaim("sub"))
+		})
+	}
+}
+
+func TestDecodeAndValidateTokenString_AlgorithmMismatch(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tp := NewTokenParser(mockDB)
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	assert.NoError(t, err)
+	publicKey, err := keyutil.ParsePublicKeyFromPEM(keyPair.PublicKeyPEM)
+	assert.NoError(t, err)
+
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tokenString := createTestToken(privateKey, map[string]interface{}{"sub": "user"}, time.Now().Add(time.Hour))
+
+	result, err := tp.DecodeAndValidateTokenString(tokenString, publicKey, true)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	assert.Nil(t, result)
+}
+
+func TestDecodeAndValidateTokenString_SelectsKeyByKid(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tp := NewTokenParser(mockDB)
+	previousKeyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmRS256)
+	assert.NoError(t, err)
+	currentKeyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	assert.NoError(t, err)
+	previousKeyPair.State = enums.KeyStatePrevious.String()
+	currentKeyPair.State = enums.KeyStateCurrent.String()
+
+	// the parsed keys are cached, so the database is only queried once
+	mockDB.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{*previousKeyPair, *currentKeyPair}, nil).Once()
+
+	for _, keyPair := range []*models.KeyPair{previousKeyPair, currentKeyPair} {
+		privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+		assert.NoError(t, err)
+		tokenString, err := signToken(jwt.MapClaims{"sub": keyPair.State, "exp": time.Now().Add(time.Hour).Unix()}, privateKey, keyPair.KeyIdentifier)
+		assert.NoError(t, err)
+
+		result, err := tp.DecodeAndValidateTokenString(tokenString, nil, true)
+		assert.NoError(t, err)
+		assert.Equal(t, keyPair.State, result.GetStringClaim("sub"))
+	}
+}
+
+func TestDecodeAndValidateTokenString_UnknownKid(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tp := NewTokenParser(mockDB)
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmRS256)
+	assert.NoError(t, err)
+	mockDB.On("GetAllSigningKeys", mock.Anything).Return([]models.KeyPair{*keyPair}, nil).Once()
+
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tokenString := createTestToken(privateKey, map[string]interface{}{"sub": "user"}, time.Now().Add(time.Hour))
+
+	result, err := tp.DecodeAndValidateTokenString(tokenString, nil, true)
+	assert.ErrorIs(t, err, customerrors.ErrUnknownSigningKey)
+	assert.Nil(t, result)
+
+	// a second unknown kid right away doesn't reload the keys
+	result, err = tp.DecodeAndValidateTokenString(tokenString, nil, true)
+	assert.ErrorIs(t, err, customerrors.ErrUnknownSigningKey)
+	assert.Nil(t, result)
+}
+
+func TestDecodeAndValidateTokenString_MissingKid(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	tp := NewTokenParser(mockDB)
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user", "exp": time.Now().Add(time.Hour).Unix()}).SignedString(privateKey)
+	assert.NoError(t, err)
+
+	result, err := tp.DecodeAndValidateTokenString(tokenString, nil, true)
+	assert.ErrorIs(t, err, customerrors.ErrUnknownSigningKey)
+	assert.Nil(t, result)
+	mockDB.AssertNotCalled(t, "GetAllSigningKeys", mock.Anything)
+}
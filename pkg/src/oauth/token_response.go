@@ -8,4 +8,5 @@ type TokenResponse struct {
 	RefreshToken     string `json:"refresh_token,omitempty"`
 	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
 	Scope            string `json:"scope,omitempty"`
+	IssuedTokenType  string `json:"issued_token_type,omitempty"`
 }
@@ -0,0 +1,82 @@
+package oauth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+// GetUserClaim returns the value of a standard claim of the user (OpenID Connect Core, section 5.1),
+// false when the claim is unknown or the user has no value for it
+func GetUserClaim(user *models.User, claim string) (interface{}, bool) {
+	var value interface{}
+	switch claim {
+	case "name":
+		value = user.GetFullName()
+	case "given_name":
+		value = user.GivenName
+	case "middle_name":
+		value = user.MiddleName
+	case "family_name":
+		value = user.FamilyName
+	case "nickname":
+		value = user.Nickname
+	case "preferred_username":
+		value = user.Username
+	case "profile":
+		value = fmt.Sprintf("%v/account/profile", config.Get().BaseURL)
+	case "website":
+		value = user.Website
+	case "gender":
+		value = user.Gender
+	case "birthdate":
+		if !user.BirthDate.Valid {
+			return nil, false
+		}
+		value = user.BirthDate.Time.Format("2006-01-02")
+	case "zoneinfo":
+		value = user.ZoneInfo
+	case "locale":
+		value = user.Locale
+	case "updated_at":
+		value = user.UpdatedAt.Time.UTC().Unix()
+	case "email":
+		value = user.Email
+	case "email_verified":
+		value = user.EmailVerified
+	case "address":
+		if !user.HasAddress() {
+			return nil, false
+		}
+		value = user.GetAddressClaim()
+	case "phone_number":
+		value = user.PhoneNumber
+	case "phone_number_verified":
+		value = user.PhoneNumberVerified
+	default:
+		return nil, false
+	}
+
+	if s, ok := value.(string); ok && len(strings.TrimSpace(s)) == 0 {
+		return nil, false
+	}
+	return value, true
+}
+
+// AddRequestedClaims adds the claims of the user requested individually with the claims parameter
+// (OpenID Connect Core, section 5.5). A claim requested with values is only added when the value
+// of the user is one of them, so the client learns nothing more than a match.
+func AddRequestedClaims(claims map[string]interface{}, user *models.User, requestedClaims map[string]*oidc.ClaimRequest) {
+	for name, claimRequest := range requestedClaims {
+		if !oidc.IsUserClaim(name) {
+			continue
+		}
+
+		if value, ok := GetUserClaim(user, name); ok && claimRequest.Matches(value) {
+			claims[name] = value
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// claimScopes maps the standard claims of the user to the scope granting them (OpenID Connect Core, section 5.4)
+var claimScopes = map[string]string{
+	"name":                  "profile",
+	"given_name":            "profile",
+	"middle_name":           "profile",
+	"family_name":           "profile",
+	"nickname":              "profile",
+	"preferred_username":    "profile",
+	"profile":               "profile",
+	"website":               "profile",
+	"gender":                "profile",
+	"birthdate":             "profile",
+	"zoneinfo":              "profile",
+	"locale":                "profile",
+	"updated_at":            "profile",
+	"email":                 "email",
+	"email_verified":        "email",
+	"address":               "address",
+	"phone_number":          "phone",
+	"phone_number_verified": "phone",
+}
+
+// ClaimRequest is the request of an individual claim (OpenID Connect Core, section 5.5.1).
+// A claim requested with null is a voluntary claim with no constraint.
+type ClaimRequest struct {
+	Essential bool          `json:"essential,omitempty"`
+	Value     interface{}   `json:"value,omitempty"`
+	Values    []interface{} `json:"values,omitempty"`
+}
+
+// ClaimsRequest is the claims parameter of an authorization request, asking for individual
+// claims returned from the userinfo endpoint or in the ID token (OpenID Connect Core, section 5.5)
+type ClaimsRequest struct {
+	UserInfo map[string]*ClaimRequest `json:"userinfo,omitempty"`
+	IdToken  map[string]*ClaimRequest `json:"id_token,omitempty"`
+}
+
+// ParseClaimsRequest parses the claims parameter, an empty parameter gives a nil request
+func ParseClaimsRequest(claims string) (*ClaimsRequest, error) {
+	if len(strings.TrimSpace(claims)) == 0 {
+		return nil, nil
+	}
+
+	var claimsRequest ClaimsRequest
+	if err := json.Unmarshal([]byte(claims), &claimsRequest); err != nil {
+		return nil, err
+	}
+
+	for _, member := range []map[string]*ClaimRequest{claimsRequest.UserInfo, claimsRequest.IdToken} {
+		for name, claim := range member {
+			if len(name) == 0 {
+				return nil, errors.New("empty claim name")
+			} else if claim != nil && claim.Value != nil && len(claim.Values) > 0 {
+				return nil, errors.New("claim " + name + " has both a value and values")
+			}
+		}
+	}
+
+	return &claimsRequest, nil
+}
+
+// IsUserClaim tells if the claim is a standard claim of the user the claims parameter can request
+func IsUserClaim(claim string) bool {
+	_, ok := claimScopes[claim]
+	return ok
+}
+
+// GetClaimScope returns the scope granting the standard claim of the user, or an empty string
+func GetClaimScope(claim string) string {
+	return claimScopes[claim]
+}
+
+// IsEssential tells if the claim is needed for a smooth authorization for the specific task requested by the client
+func (c *ClaimRequest) IsEssential() bool {
+	return c != nil && c.Essential
+}
+
+// GetValues returns the values the claim is requested with, empty when any value is accepted
+func (c *ClaimRequest) GetValues() []interface{} {
+	if c == nil {
+		return nil
+	} else if c.Value != nil {
+		return []interface{}{c.Value}
+	}
+	return c.Values
+}
+
+// Matches tells if the value of the claim is one of the requested values. Values are compared
+// by their JSON representation, so numbers of the request match the integers of the user.
+func (c *ClaimRequest) Matches(value interface{}) bool {
+	requestedValues := c.GetValues()
+	if len(requestedValues) == 0 {
+		return true
+	}
+
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	for _, requestedValue := range requestedValues {
+		if requestedValueJson, err := json.Marshal(requestedValue); err == nil && string(requestedValueJson) == string(valueJson) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetUserClaimNames returns the sorted standard claims of the user requested in any member,
+// those are the claims the user consents to release
+func (cr *ClaimsRequest) GetUserClaimNames() []string {
+	names := []string{}
+	if cr == nil {
+		return names
+	}
+
+	for _, member := range []map[string]*ClaimRequest{cr.UserInfo, cr.IdToken} {
+		for name := range member {
+			if IsUserClaim(name) && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// IsEssentialClaim tells if the claim is essential in any member
+func (cr *ClaimsRequest) IsEssentialClaim(claim string) bool {
+	return cr != nil && (cr.UserInfo[claim].IsEssential() || cr.IdToken[claim].IsEssential())
+}
+
+// GetAcrValues returns the values of the acr claim requested for the ID token, in order of preference
+func (cr *ClaimsRequest) GetAcrValues() []string {
+	if cr == nil {
+		return nil
+	}
+
+	acrValues := []string{}
+	for _, value := range cr.IdToken["acr"].GetValues() {
+		if s, ok := value.(string); ok {
+			acrValues = append(acrValues, s)
+		}
+	}
+	return acrValues
+}
+
+// HasEssentialAcr tells if the acr claim is requested as essential with values, the authentication
+// then fails unless it returns one of them (OpenID Connect Core, section 5.5.1.1)
+func (cr *ClaimsRequest) HasEssentialAcr() bool {
+	return cr != nil && cr.IdToken["acr"].IsEssential() && len(cr.GetAcrValues()) > 0
+}
+
+// Filter returns the request without the standard claims of the user that are not granted.
+// Other claims, like acr, do not disclose user data and are kept.
+func (cr *ClaimsRequest) Filter(grantedClaims []string) *ClaimsRequest {
+	if cr == nil {
+		return nil
+	}
+
+	filter := func(member map[string]*ClaimRequest) map[string]*ClaimRequest {
+		filtered := map[string]*ClaimRequest{}
+		for name, claim := range member {
+			if !IsUserClaim(name) || slices.Contains(grantedClaims, name) {
+				filtered[name] = claim
+			}
+		}
+
+		if len(filtered) == 0 {
+			return nil
+		}
+		return filtered
+	}
+
+	return &ClaimsRequest{
+		UserInfo: filter(cr.UserInfo),
+		IdToken:  filter(cr.IdToken),
+	}
+}
+
+// String returns the JSON representation of the request, an empty string for an empty request
+func (cr *ClaimsRequest) String() string {
+	if cr == nil || (len(cr.UserInfo) == 0 && len(cr.IdToken) == 0) {
+		return ""
+	}
+
+	claimsJson, err := json.Marshal(cr)
+	if err != nil {
+		return ""
+	}
+	return string(claimsJson)
+}
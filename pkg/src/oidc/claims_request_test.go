@@ -0,0 +1,80 @@
+package oidc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseClaimsRequest(t *testing.T) {
+	claimsRequest, err := ParseClaimsRequest(`{"userinfo":{"email":null,"custom":null},"id_token":{"birthdate":{"essential":true},"acr":{"essential":true,"values":["urn:goiabada:level2_mandatory",1]}}}`)
+	if err != nil {
+		t.Fatalf("ParseClaimsRequest returned an error: %v", err)
+	}
+
+	if names := claimsRequest.GetUserClaimNames(); !reflect.DeepEqual(names, []string{"birthdate", "email"}) {
+		t.Errorf("GetUserClaimNames() = %v; want [birthdate email]", names)
+	}
+
+	if !claimsRequest.IsEssentialClaim("birthdate") || claimsRequest.IsEssentialClaim("email") {
+		t.Errorf("IsEssentialClaim() should only be true for birthdate")
+	}
+
+	if acrValues := claimsRequest.GetAcrValues(); !reflect.DeepEqual(acrValues, []string{"urn:goiabada:level2_mandatory"}) {
+		t.Errorf("GetAcrValues() = %v; want [urn:goiabada:level2_mandatory]", acrValues)
+	}
+
+	if !claimsRequest.HasEssentialAcr() {
+		t.Errorf("HasEssentialAcr() = false; want true")
+	}
+
+	filtered := claimsRequest.Filter([]string{"email"}).String()
+	expected := `{"userinfo":{"custom":null,"email":null},"id_token":{"acr":{"essential":true,"values":["urn:goiabada:level2_mandatory",1]}}}`
+	if filtered != expected {
+		t.Errorf("Filter() = %v; want %v", filtered, expected)
+	}
+}
+
+func TestParseClaimsRequest_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		claims string
+	}{
+		{"Not JSON", "email"},
+		{"Array", `["email"]`},
+		{"Member not an object", `{"userinfo":["email"]}`},
+		{"Value and values", `{"id_token":{"acr":{"value":"a","values":["b"]}}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseClaimsRequest(tc.claims); err == nil {
+				t.Errorf("ParseClaimsRequest(%q) should return an error", tc.claims)
+			}
+		})
+	}
+}
+
+func TestClaimRequestMatches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		claim    *ClaimRequest
+		value    interface{}
+		expected bool
+	}{
+		{"Voluntary claim", nil, "anything", true},
+		{"Essential claim", &ClaimRequest{Essential: true}, "anything", true},
+		{"Matching value", &ClaimRequest{Value: "a"}, "a", true},
+		{"Other value", &ClaimRequest{Value: "a"}, "b", false},
+		{"One of the values", &ClaimRequest{Values: []interface{}{"a", "b"}}, "b", true},
+		{"Boolean", &ClaimRequest{Value: false}, false, true},
+		{"Number and integer", &ClaimRequest{Value: float64(1700000000)}, int64(1700000000), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.claim.Matches(tc.value); result != tc.expected {
+				t.Errorf("Matches(%v) = %v; want %v", tc.value, result, tc.expected)
+			}
+		})
+	}
+}
@@ -1,19 +1,60 @@
 package oidc
 
 type WellKnownConfig struct {
-	Issuer                            string   `json:"issuer"`
-	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
-	TokenEndpoint                     string   `json:"token_endpoint"`
-	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
-	EndSessionEndpoint                string   `json:"end_session_endpoint"`
-	JWKsURI                           string   `json:"jwks_uri"`
-	GrantTypesSupported               []string `json:"grant_types_supported"`
-	ResponseTypesSupported            []string `json:"response_types_supported"`
-	ACRValuesSupported                []string `json:"acr_values_supported"`
-	SubjectTypesSupported             []string `json:"subject_types_supported"`
-	IdTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
-	ScopesSupported                   []string `json:"scopes_supported"`
-	ClaimsSupported                   []string `json:"claims_supported"`
-	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
-	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	Issuer                                     string               `json:"issuer"`
+	AuthorizationEndpoint                      string               `json:"authorization_endpoint"`
+	TokenEndpoint                              string               `json:"token_endpoint"`
+	UserInfoEndpoint                           string               `json:"userinfo_endpoint"`
+	EndSessionEndpoint                         string               `json:"end_session_endpoint,omitempty"`
+	IntrospectionEndpoint                      string               `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint                         string               `json:"revocation_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint                string               `json:"device_authorization_endpoint,omitempty"`
+	RegistrationEndpoint                       string               `json:"registration_endpoint,omitempty"`
+	PushedAuthorizationRequestEndpoint         string               `json:"pushed_authorization_request_endpoint,omitempty"`
+	BackChannelAuthenticationEndpoint          string               `json:"backchannel_authentication_endpoint,omitempty"`
+	JWKsURI                                    string               `json:"jwks_uri"`
+	GrantTypesSupported                        []string             `json:"grant_types_supported"`
+	ResponseTypesSupported                     []string             `json:"response_types_supported"`
+	ResponseModesSupported                     []string             `json:"response_modes_supported,omitempty"`
+	ACRValuesSupported                         []string             `json:"acr_values_supported"`
+	SubjectTypesSupported                      []string             `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported           []string             `json:"id_token_signing_alg_values_supported"`
+	IdTokenEncryptionAlgValuesSupported        []string             `json:"id_token_encryption_alg_values_supported,omitempty"`
+	IdTokenEncryptionEncValuesSupported        []string             `json:"id_token_encryption_enc_values_supported,omitempty"`
+	UserInfoEncryptionAlgValuesSupported       []string             `json:"userinfo_encryption_alg_values_supported,omitempty"`
+	UserInfoEncryptionEncValuesSupported       []string             `json:"userinfo_encryption_enc_values_supported,omitempty"`
+	ScopesSupported                            []string             `json:"scopes_supported"`
+	ClaimsSupported                            []string             `json:"claims_supported"`
+	ClaimsParameterSupported                   bool                 `json:"claims_parameter_supported"`
+	TokenEndpointAuthMethodsSupported          []string             `json:"token_endpoint_auth_methods_supported"`
+	TokenEndpointAuthSigningAlgValuesSupported []string             `json:"token_endpoint_auth_signing_alg_values_supported,omitempty"`
+	IntrospectionEndpointAuthMethodsSupported  []string             `json:"introspection_endpoint_auth_methods_supported,omitempty"`
+	RevocationEndpointAuthMethodsSupported     []string             `json:"revocation_endpoint_auth_methods_supported,omitempty"`
+	CodeChallengeMethodsSupported              []string             `json:"code_challenge_methods_supported"`
+	TLSClientCertificateBoundAccessTokens      bool                 `json:"tls_client_certificate_bound_access_tokens,omitempty"`
+	MTLSEndpointAliases                        *MTLSEndpointAliases `json:"mtls_endpoint_aliases,omitempty"`
+	DPoPSigningAlgValuesSupported              []string             `json:"dpop_signing_alg_values_supported,omitempty"`
+	RequestParameterSupported                  bool                 `json:"request_parameter_supported"`
+	RequestURIParameterSupported               bool                 `json:"request_uri_parameter_supported"`
+	RequestObjectSigningAlgValuesSupported     []string             `json:"request_object_signing_alg_values_supported,omitempty"`
+	AuthorizationSigningAlgValuesSupported     []string             `json:"authorization_signing_alg_values_supported,omitempty"`
+	AuthorizationEncryptionAlgValuesSupported  []string             `json:"authorization_encryption_alg_values_supported,omitempty"`
+	AuthorizationEncryptionEncValuesSupported  []string             `json:"authorization_encryption_enc_values_supported,omitempty"`
+	FrontChannelLogoutSupported                bool                 `json:"frontchannel_logout_supported"`
+	FrontChannelLogoutSessionSupported         bool                 `json:"frontchannel_logout_session_supported"`
+	BackChannelLogoutSupported                 bool                 `json:"backchannel_logout_supported"`
+	BackChannelLogoutSessionSupported          bool                 `json:"backchannel_logout_session_supported"`
+	BackChannelTokenDeliveryModesSupported     []string             `json:"backchannel_token_delivery_modes_supported,omitempty"`
+	BackChannelUserCodeParameterSupported      bool                 `json:"backchannel_user_code_parameter_supported"`
+}
+
+// MTLSEndpointAliases are the endpoints of the listener that requests client certificates (RFC 8705, section 5)
+type MTLSEndpointAliases struct {
+	TokenEndpoint                      string `json:"token_endpoint"`
+	IntrospectionEndpoint              string `json:"introspection_endpoint"`
+	RevocationEndpoint                 string `json:"revocation_endpoint"`
+	PushedAuthorizationRequestEndpoint string `json:"pushed_authorization_request_endpoint"`
+	BackChannelAuthenticationEndpoint  string `json:"backchannel_authentication_endpoint"`
+	DeviceAuthorizationEndpoint        string `json:"device_authorization_endpoint"`
+	UserInfoEndpoint                   string `json:"userinfo_endpoint"`
 }
@@ -7,6 +7,14 @@ import (
 
 const OfflineAccessScope = "offline_access"
 
+// values of the prompt parameter (OpenID Connect Core, section 3.1.2.1)
+const (
+	PromptNone          = "none"
+	PromptLogin         = "login"
+	PromptConsent       = "consent"
+	PromptSelectAccount = "select_account"
+)
+
 func GetIdTokenScopeDescription(scope string) string {
 	switch scope {
 	case "openid":
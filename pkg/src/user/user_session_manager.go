@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +19,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// maxOtherSessionsPerBrowser limits how many sessions of other accounts a browser remembers
+const maxOtherSessionsPerBrowser = 4
+
 type UserSessionManager struct {
 	codeIssuer   *oauth.CodeIssuer
 	sessionStore sessions.Store
@@ -113,6 +117,11 @@ func (u *UserSessionManager) StartNewUserSession(w http.ResponseWriter, r *http.
 		return nil, errors.Wrap(err, "unable to get the session")
 	}
 
+	// the previous session of the browser can still be picked with prompt=select_account
+	previousSessionIdentifier, _ := sess.Values[constants.SessionKeySessionIdentifier].(string)
+	otherSessionIdentifiers, _ := sess.Values[constants.SessionKeyOtherSessionIdentifiers].(string)
+	sess.Values[constants.SessionKeyOtherSessionIdentifiers] = UpdateOtherSessionIdentifiers(otherSessionIdentifiers,
+		previousSessionIdentifier, userSession.SessionIdentifier)
 	sess.Values[constants.SessionKeySessionIdentifier] = userSession.SessionIdentifier
 	if err = u.sessionStore.Save(r, w, sess); err != nil {
 		return nil, err
@@ -202,3 +211,17 @@ func (u *UserSessionManager) BumpUserSession(r *http.Request, sessionIdentifier
 
 	return nil, errors.WithStack(errors.New("Unexpected: can't bump user session because user session is nil"))
 }
+
+// UpdateOtherSessionIdentifiers returns the space separated identifiers of the sessions a browser keeps
+// besides its active session. The previous session goes first, the oldest ones are dropped.
+func UpdateOtherSessionIdentifiers(otherSessionIdentifiers string, previousSessionIdentifier string, activeSessionIdentifier string) string {
+	identifiers := []string{}
+	for _, identifier := range append([]string{previousSessionIdentifier}, strings.Fields(otherSessionIdentifiers)...) {
+		if len(identifiers) == maxOtherSessionsPerBrowser {
+			break
+		} else if len(identifier) > 0 && identifier != activeSessionIdentifier && !slices.Contains(identifiers, identifier) {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return strings.Join(identifiers, " ")
+}
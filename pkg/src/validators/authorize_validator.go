@@ -7,10 +7,14 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/customerrors"
 	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
 	"github.com/pchchv/aas/pkg/src/oidc"
 )
 
@@ -25,15 +29,25 @@ type ValidateRequestInput struct {
 	ResponseMode        string
 	CodeChallenge       string
 	CodeChallengeMethod string
+	Prompt              string
+}
+
+type ValidateIdTokenHintInput struct {
+	ClientId    string
+	IdTokenHint string
 }
 
 type AuthorizeValidator struct {
-	database database.Database
+	database    database.Database
+	tokenParser TokenParser
+	httpClient  *http.Client
 }
 
 func NewAuthorizeValidator(database database.Database) *AuthorizeValidator {
 	return &AuthorizeValidator{
-		database: database,
+		database:    database,
+		tokenParser: oauth.NewTokenParser(database),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
@@ -109,9 +123,20 @@ func (val *AuthorizeValidator) ValidateRequest(input *ValidateRequestInput) erro
 		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "The code_challenge parameter is either missing or incorrect. It should be 43 to 128 characters long.", http.StatusBadRequest)
 	}
 
+	prompts := strings.Fields(input.Prompt)
+	for _, prompt := range prompts {
+		if !slices.Contains([]string{oidc.PromptNone, oidc.PromptLogin, oidc.PromptConsent, oidc.PromptSelectAccount}, prompt) {
+			return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "Invalid prompt parameter. Supported values are: none, login, consent, select_account.", http.StatusBadRequest)
+		}
+	}
+
+	if slices.Contains(prompts, oidc.PromptNone) && len(prompts) > 1 {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "The prompt value none cannot be combined with other values.", http.StatusBadRequest)
+	}
+
 	if len(input.ResponseMode) > 0 {
-		if !slices.Contains([]string{"query", "fragment", "form_post"}, input.ResponseMode) {
-			return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "Invalid response_mode parameter. Supported values are: query, fragment, form_post.", http.StatusBadRequest)
+		if !slices.Contains([]string{"query", "fragment", "form_post"}, input.ResponseMode) && !oauth.IsJwtResponseMode(input.ResponseMode) {
+			return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "Invalid response_mode parameter. Supported values are: query, fragment, form_post, jwt, query.jwt, fragment.jwt, form_post.jwt.", http.StatusBadRequest)
 		}
 	}
 
@@ -157,3 +182,33 @@ func (val *AuthorizeValidator) ValidateClientAndRedirectURI(input *ValidateClien
 
 	return nil
 }
+
+// ValidateIdTokenHint returns the user of an ID token previously issued to the client.
+// An expired ID token is still a valid hint, it identifies the user but doesn't authenticate it.
+func (val *AuthorizeValidator) ValidateIdTokenHint(input *ValidateIdTokenHintInput) (*models.User, error) {
+	invalidIdTokenHint := customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+		"The id_token_hint parameter is invalid.", http.StatusBadRequest)
+
+	idToken, err := val.tokenParser.DecodeAndValidateTokenString(input.IdTokenHint, nil, false)
+	if err != nil || idToken == nil {
+		return nil, invalidIdTokenHint
+	} else if idToken.GetStringClaim("typ") != enums.TokenTypeId.String() || !slices.Contains(idToken.GetAudience(), input.ClientId) {
+		return nil, invalidIdTokenHint
+	}
+
+	// the sub claim is pairwise when the client belongs to a sector
+	client, err := val.database.GetClientByClientIdentifier(nil, input.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := oauth.ResolveSubject(val.database, client, idToken.GetStringClaim("sub"))
+	if err != nil {
+		return nil, err
+	} else if user == nil || !user.Enabled {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("login_required",
+			"The user of the id_token_hint is no longer available.", http.StatusBadRequest)
+	}
+
+	return user, nil
+}
@@ -5,10 +5,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/customerrors"
 	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/enums"
 	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	mocksOAuth "github.com/pchchv/aas/pkg/src/oauth/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -209,7 +214,7 @@ func TestValidateRequest_InvalidResponseMode(t *testing.T) {
 	err := validator.ValidateRequest(&input)
 	assert.Error(t, err)
 	customErr := err.(*customerrors.ErrorDetail)
-	assert.Equal(t, "Invalid response_mode parameter. Supported values are: query, fragment, form_post.", customErr.GetDescription())
+	assert.Equal(t, "Invalid response_mode parameter. Supported values are: query, fragment, form_post, jwt, query.jwt, fragment.jwt, form_post.jwt.", customErr.GetDescription())
 }
 
 func TestValidateRequest_ValidInput(t *testing.T) {
@@ -226,6 +231,22 @@ func TestValidateRequest_ValidInput(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateRequest_JwtResponseModes(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	validator := NewAuthorizeValidator(mockDB)
+	for _, responseMode := range []string{"jwt", "query.jwt", "fragment.jwt", "form_post.jwt"} {
+		input := ValidateRequestInput{
+			ResponseType:        "code",
+			CodeChallengeMethod: "S256",
+			CodeChallenge:       "a_valid_code_challenge_that_meets_length_requirements",
+			ResponseMode:        responseMode,
+		}
+
+		err := validator.ValidateRequest(&input)
+		assert.NoError(t, err, responseMode)
+	}
+}
+
 func TestValidateRequest_EmptyResponseMode(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	validator := NewAuthorizeValidator(mockDB)
@@ -240,6 +261,129 @@ func TestValidateRequest_EmptyResponseMode(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateRequest_Prompt(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	validator := NewAuthorizeValidator(mockDB)
+	tests := []struct {
+		prompt        string
+		expectedError string
+	}{
+		{prompt: "login consent"},
+		{prompt: "select_account"},
+		{prompt: "none"},
+		{prompt: "unknown", expectedError: "Invalid prompt parameter. Supported values are: none, login, consent, select_account."},
+		{prompt: "none login", expectedError: "The prompt value none cannot be combined with other values."},
+	}
+
+	for _, tt := range tests {
+		input := ValidateRequestInput{
+			ResponseType:        "code",
+			CodeChallengeMethod: "S256",
+			CodeChallenge:       "a_valid_code_challenge_that_meets_length_requirements",
+			Prompt:              tt.prompt,
+		}
+
+		err := validator.ValidateRequest(&input)
+		if len(tt.expectedError) == 0 {
+			assert.NoError(t, err, tt.prompt)
+			continue
+		}
+
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		if assert.True(t, ok, tt.prompt) {
+			assert.Equal(t, "invalid_request", customErr.GetCode())
+			assert.Equal(t, tt.expectedError, customErr.GetDescription())
+		}
+	}
+}
+
+func TestValidateIdTokenHint(t *testing.T) {
+	idToken := &oauth.Jwt{Claims: jwt.MapClaims{
+		"typ": enums.TokenTypeId.String(),
+		"aud": "test-client",
+		"sub": "user-subject",
+	}}
+	user := &models.User{Id: 1, Subject: uuid.MustParse("11111111-1111-1111-1111-111111111111"), Enabled: true}
+
+	t.Run("Valid", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		validator := NewAuthorizeValidator(mockDB)
+		validator.tokenParser = mockTokenParser
+
+		mockTokenParser.On("DecodeAndValidateTokenString", "hint", mock.Anything, false).Return(idToken, nil)
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{ClientIdentifier: "test-client"}, nil)
+		mockDB.On("GetUserBySubject", mock.Anything, "user-subject").Return(user, nil)
+
+		result, err := validator.ValidateIdTokenHint(&ValidateIdTokenHintInput{ClientId: "test-client", IdTokenHint: "hint"})
+		assert.NoError(t, err)
+		assert.Equal(t, user, result)
+	})
+
+	t.Run("Pairwise subject", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		validator := NewAuthorizeValidator(mockDB)
+		validator.tokenParser = mockTokenParser
+
+		mockTokenParser.On("DecodeAndValidateTokenString", "hint", mock.Anything, false).Return(idToken, nil)
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{
+			ClientIdentifier: "test-client",
+			SubjectType:      constants.SubjectTypePairwise,
+			SectorIdentifier: "app.example.com",
+		}, nil)
+		mockDB.On("GetPairwiseSubjectBySectorIdentifierAndSubject", mock.Anything, "app.example.com", "user-subject").
+			Return(&models.PairwiseSubject{UserId: 1, SectorIdentifier: "app.example.com", Subject: "user-subject"}, nil)
+		mockDB.On("GetUserById", mock.Anything, int64(1)).Return(user, nil)
+
+		result, err := validator.ValidateIdTokenHint(&ValidateIdTokenHintInput{ClientId: "test-client", IdTokenHint: "hint"})
+		assert.NoError(t, err)
+		assert.Equal(t, user, result)
+	})
+
+	t.Run("Issued to another client", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		validator := NewAuthorizeValidator(mockDB)
+		validator.tokenParser = mockTokenParser
+
+		mockTokenParser.On("DecodeAndValidateTokenString", "hint", mock.Anything, false).Return(idToken, nil)
+
+		result, err := validator.ValidateIdTokenHint(&ValidateIdTokenHintInput{ClientId: "other-client", IdTokenHint: "hint"})
+		assert.Nil(t, result)
+		assert.Equal(t, "The id_token_hint parameter is invalid.", err.(*customerrors.ErrorDetail).GetDescription())
+	})
+
+	t.Run("Not an id token", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		validator := NewAuthorizeValidator(mockDB)
+		validator.tokenParser = mockTokenParser
+
+		accessToken := &oauth.Jwt{Claims: jwt.MapClaims{"typ": enums.TokenTypeBearer.String(), "aud": "test-client", "sub": "user-subject"}}
+		mockTokenParser.On("DecodeAndValidateTokenString", "hint", mock.Anything, false).Return(accessToken, nil)
+
+		result, err := validator.ValidateIdTokenHint(&ValidateIdTokenHintInput{ClientId: "test-client", IdTokenHint: "hint"})
+		assert.Nil(t, result)
+		assert.Equal(t, "invalid_request", err.(*customerrors.ErrorDetail).GetCode())
+	})
+
+	t.Run("User is disabled", func(t *testing.T) {
+		mockDB := mocks.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		validator := NewAuthorizeValidator(mockDB)
+		validator.tokenParser = mockTokenParser
+
+		mockTokenParser.On("DecodeAndValidateTokenString", "hint", mock.Anything, false).Return(idToken, nil)
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(&models.Client{ClientIdentifier: "test-client"}, nil)
+		mockDB.On("GetUserBySubject", mock.Anything, "user-subject").Return(&models.User{Id: 1, Enabled: false}, nil)
+
+		result, err := validator.ValidateIdTokenHint(&ValidateIdTokenHintInput{ClientId: "test-client", IdTokenHint: "hint"})
+		assert.Nil(t, result)
+		assert.Equal(t, "login_required", err.(*customerrors.ErrorDetail).GetCode())
+	})
+}
+
 func TestValidateClientAndRedirectURI_ExtremelyLongClientId(t *testing.T) {
 	mockDB := mocks.NewDatabase(t)
 	validator := NewAuthorizeValidator(mockDB)
@@ -0,0 +1,35 @@
+package validators
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+)
+
+// ValidateBackChannelTokenDelivery validates how a client using backchannel authentication is told the
+// result is ready, and returns the delivery mode, poll when it is omitted. The ping mode requires an https
+// client notification endpoint (OpenID Connect CIBA, section 4). The push mode is not supported.
+func ValidateBackChannelTokenDelivery(mode string, clientNotificationEndpoint string) (string, error) {
+	if len(mode) == 0 {
+		mode = constants.CIBATokenDeliveryModePoll
+	}
+
+	if mode != constants.CIBATokenDeliveryModePoll && mode != constants.CIBATokenDeliveryModePing {
+		return "", customerrors.NewErrorDetail("", "Unsupported backchannel_token_delivery_mode: "+mode+
+			". Supported values are "+constants.CIBATokenDeliveryModePoll+", "+constants.CIBATokenDeliveryModePing+".")
+	}
+
+	if len(clientNotificationEndpoint) > 0 {
+		if u, err := url.Parse(clientNotificationEndpoint); err != nil || u.Scheme != "https" || len(u.Host) == 0 ||
+			strings.Contains(clientNotificationEndpoint, "#") {
+			return "", customerrors.NewErrorDetail("", "Invalid backchannel_client_notification_endpoint: "+
+				clientNotificationEndpoint+". It must be an https URL without a fragment.")
+		}
+	} else if mode == constants.CIBATokenDeliveryModePing {
+		return "", customerrors.NewErrorDetail("", "The ping mode requires a backchannel_client_notification_endpoint.")
+	}
+
+	return mode, nil
+}
@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBackChannelTokenDelivery(t *testing.T) {
+	tests := []struct {
+		name                       string
+		mode                       string
+		clientNotificationEndpoint string
+		expectedMode               string
+		expectedError              string
+	}{
+		{name: "Poll by default", expectedMode: constants.CIBATokenDeliveryModePoll},
+		{name: "Ping", mode: "ping", clientNotificationEndpoint: "https://client.example.com/cb", expectedMode: constants.CIBATokenDeliveryModePing},
+		{
+			name:          "Push is not supported",
+			mode:          "push",
+			expectedError: "Unsupported backchannel_token_delivery_mode: push. Supported values are poll, ping.",
+		},
+		{
+			name:          "Ping without endpoint",
+			mode:          "ping",
+			expectedError: "The ping mode requires a backchannel_client_notification_endpoint.",
+		},
+		{
+			name:                       "Endpoint without https",
+			mode:                       "ping",
+			clientNotificationEndpoint: "http://client.example.com/cb",
+			expectedError:              "Invalid backchannel_client_notification_endpoint: http://client.example.com/cb. It must be an https URL without a fragment.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := ValidateBackChannelTokenDelivery(tt.mode, tt.clientNotificationEndpoint)
+			if len(tt.expectedError) == 0 {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedMode, mode)
+				return
+			}
+
+			customErr, ok := err.(*customerrors.ErrorDetail)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedError, customErr.GetDescription())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/enums"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+// ValidateClaimsRequest checks the claims parameter of an authorization request (OpenID Connect Core, section 5.5).
+// An essential acr claim must name a supported acr level, as the authentication would fail otherwise.
+func ValidateClaimsRequest(scope string, claims string) error {
+	if len(strings.TrimSpace(claims)) == 0 {
+		return nil
+	}
+
+	if !slices.Contains(strings.Fields(scope), "openid") {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The claims parameter requires the openid scope.", http.StatusBadRequest)
+	}
+
+	claimsRequest, err := oidc.ParseClaimsRequest(claims)
+	if err != nil {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The claims parameter must be a JSON object with the userinfo and id_token members.", http.StatusBadRequest)
+	}
+
+	if claimsRequest.HasEssentialAcr() {
+		for _, acrValue := range claimsRequest.GetAcrValues() {
+			if _, err = enums.AcrLevelFromString(acrValue); err == nil {
+				return nil
+			}
+		}
+
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"None of the values of the essential acr claim is supported.", http.StatusBadRequest)
+	}
+
+	return nil
+}
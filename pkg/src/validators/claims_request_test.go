@@ -0,0 +1,60 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateClaimsRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		scope         string
+		claims        string
+		expectedError string
+	}{
+		{name: "No claims parameter", scope: "resource1:read", claims: ""},
+		{name: "Voluntary claims", scope: "openid", claims: `{"userinfo":{"email":null},"id_token":{"birthdate":{"essential":true}}}`},
+		{name: "Essential acr", scope: "openid", claims: `{"id_token":{"acr":{"essential":true,"values":["urn:other:acr","urn:goiabada:level2_mandatory"]}}}`},
+		{
+			name:          "Without the openid scope",
+			scope:         "resource1:read",
+			claims:        `{"userinfo":{"email":null}}`,
+			expectedError: "The claims parameter requires the openid scope.",
+		},
+		{
+			name:          "Not a JSON object",
+			scope:         "openid",
+			claims:        `["email"]`,
+			expectedError: "The claims parameter must be a JSON object with the userinfo and id_token members.",
+		},
+		{
+			name:          "Both value and values",
+			scope:         "openid",
+			claims:        `{"id_token":{"acr":{"value":"urn:goiabada:level1","values":["urn:goiabada:level1"]}}}`,
+			expectedError: "The claims parameter must be a JSON object with the userinfo and id_token members.",
+		},
+		{
+			name:          "Unsupported essential acr",
+			scope:         "openid",
+			claims:        `{"id_token":{"acr":{"essential":true,"value":"urn:other:acr"}}}`,
+			expectedError: "None of the values of the essential acr claim is supported.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClaimsRequest(tt.scope, tt.claims)
+			if len(tt.expectedError) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			customErr, ok := err.(*customerrors.ErrorDetail)
+			assert.True(t, ok)
+			assert.Equal(t, "invalid_request", customErr.GetCode())
+			assert.Equal(t, tt.expectedError, customErr.GetDescription())
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package validators
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+const maxJWKSResponseSize = 64 * 1024
+
+var (
+	clientSecretJwtAlgorithms = []string{"HS256", "HS384", "HS512"}
+	privateKeyJwtAlgorithms   = []string{
+		"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", keyutil.AlgorithmES256, keyutil.AlgorithmES384, keyutil.AlgorithmEdDSA,
+	}
+)
+
+// ClientAssertionSigningAlgorithms lists the algorithms accepted for client assertions
+func ClientAssertionSigningAlgorithms() []string {
+	return slices.Concat(privateKeyJwtAlgorithms, clientSecretJwtAlgorithms)
+}
+
+type ValidateClientAssertionInput struct {
+	ClientId            string
+	ClientAssertionType string
+	ClientAssertion     string
+}
+
+type ClientAssertionValidator struct {
+	database   database.Database
+	httpClient *http.Client
+}
+
+func NewClientAssertionValidator(database database.Database) *ClientAssertionValidator {
+	return &ClientAssertionValidator{
+		database:   database,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ValidateClientAssertion authenticates a client with the JWT it sent as client_assertion
+// (RFC 7523, sections 2.2 and 3) and returns the client. The client_id is optional,
+// since the client is identified by the iss and sub claims of the assertion.
+func (val *ClientAssertionValidator) ValidateClientAssertion(ctx context.Context, input *ValidateClientAssertionInput) (*models.Client, error) {
+	if input.ClientAssertionType != constants.ClientAssertionTypeJwtBearer {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The client_assertion_type must be "+constants.ClientAssertionTypeJwtBearer+".", http.StatusBadRequest)
+	} else if len(input.ClientAssertion) == 0 {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"Missing required client_assertion parameter.", http.StatusBadRequest)
+	}
+
+	unverifiedClaims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(input.ClientAssertion, unverifiedClaims); err != nil {
+		return nil, invalidClient("The client assertion is not a valid JWT.")
+	}
+
+	clientId, _ := unverifiedClaims.GetSubject()
+	if len(clientId) == 0 {
+		return nil, invalidClient("The client assertion must have a sub claim with the client_id.")
+	} else if len(input.ClientId) > 0 && input.ClientId != clientId {
+		return nil, invalidClient("The sub claim of the client assertion does not match the client_id.")
+	}
+
+	client, err := val.database.GetClientByClientIdentifier(nil, clientId)
+	if err != nil {
+		return nil, err
+	} else if client == nil || !client.Enabled || !client.UsesClientAssertion() {
+		return nil, invalidClient("Client authentication failed.")
+	}
+
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	keyFunc, validMethods, err := val.getVerificationKeys(client, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(input.ClientAssertion, claims, keyFunc,
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(clientId),
+		jwt.WithSubject(clientId),
+	); err != nil {
+		return nil, invalidClient("The client assertion is invalid (" + err.Error() + ").")
+	}
+
+	audience, _ := claims.GetAudience()
+	tokenEndpoint := config.GetAuthServer().BaseURL + "/auth/token"
+	if !slices.Contains(audience, settings.Issuer) && !slices.Contains(audience, tokenEndpoint) {
+		return nil, invalidClient("The aud claim of the client assertion must contain the issuer or the token endpoint URL.")
+	}
+
+	if err = val.preventReplay(client, claims); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// getVerificationKeys returns the keys the assertion of the client can be signed with:
+// the client secret for client_secret_jwt, the registered public keys for private_key_jwt
+func (val *ClientAssertionValidator) getVerificationKeys(client *models.Client, settings *models.Settings) (jwt.Keyfunc, []string, error) {
+	if client.TokenEndpointAuthMethod == constants.TokenEndpointAuthMethodClientSecretJwt {
+		clientSecret, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return func(token *jwt.Token) (interface{}, error) {
+			return []byte(clientSecret), nil
+		}, clientSecretJwtAlgorithms, nil
+	}
+
+	publicKeys, err := getClientPublicKeys(val.httpClient, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return getPublicKeysKeyfunc(publicKeys), privateKeyJwtAlgorithms, nil
+}
+
+// getPublicKeysKeyfunc selects the registered public keys of a client
+// matching the kid and the signing algorithm of the token being verified
+func getPublicKeysKeyfunc(publicKeys []keyutil.PublicJWK) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		keySet := jwt.VerificationKeySet{}
+		for _, publicKey := range publicKeys {
+			if len(kid) > 0 && publicKey.KeyIdentifier != kid {
+				continue
+			} else if len(publicKey.Algorithm) > 0 && publicKey.Algorithm != token.Method.Alg() {
+				continue
+			}
+
+			if isKeyForSigningMethod(publicKey.Key, token.Method) {
+				keySet.Keys = append(keySet.Keys, publicKey.Key)
+			}
+		}
+
+		if len(keySet.Keys) == 0 {
+			return nil, errors.WithStack(errors.New("no registered key matches the kid and alg of the token"))
+		}
+		return keySet, nil
+	}
+}
+
+// getClientPublicKeys reads the keys registered inline or fetches them from the jwks_uri of the client
+func getClientPublicKeys(httpClient *http.Client, client *models.Client) ([]keyutil.PublicJWK, error) {
+	jwks := client.JWKS
+	if len(client.JWKSURI) > 0 {
+		resp, err := httpClient.Get(client.JWKSURI)
+		if err != nil {
+			return nil, invalidClient("Unable to fetch the client JWKS from its jwks_uri.")
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, invalidClient("Unable to fetch the client JWKS from its jwks_uri.")
+		}
+
+		if jwks, err = io.ReadAll(io.LimitReader(resp.Body, maxJWKSResponseSize)); err != nil {
+			return nil, invalidClient("Unable to fetch the client JWKS from its jwks_uri.")
+		}
+	}
+
+	publicKeys, err := keyutil.ParseJWKS(jwks)
+	if err != nil {
+		return nil, invalidClient("The client JWKS is invalid.")
+	}
+
+	return publicKeys, nil
+}
+
+// preventReplay records the jti of the assertion until it expires,
+// rejecting an assertion whose jti was already used by the client
+func (val *ClientAssertionValidator) preventReplay(client *models.Client, claims jwt.MapClaims) error {
+	jti, _ := claims["jti"].(string)
+	if len(jti) == 0 {
+		return invalidClient("The client assertion must have a jti claim.")
+	}
+
+	jtiHash, err := hashutil.HashString(jti)
+	if err != nil {
+		return err
+	}
+
+	clientAssertionJti, err := val.database.GetClientAssertionJti(nil, client.Id, jtiHash)
+	if err != nil {
+		return err
+	} else if clientAssertionJti != nil {
+		return invalidClient("The client assertion has already been used.")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return err
+	}
+
+	// the unique index on client_id and jti_hash rejects concurrent replays
+	return val.database.CreateClientAssertionJti(nil, &models.ClientAssertionJti{
+		ClientId:  client.Id,
+		JtiHash:   jtiHash,
+		ExpiresAt: sql.NullTime{Time: expiresAt.UTC(), Valid: true},
+	})
+}
+
+// ValidateClientAuthentication validates what a client registers for its token endpoint
+// authentication method: private_key_jwt and self_signed_tls_client_auth clients need either
+// a JWKS or a jwks_uri, tls_client_auth clients need the subject DN of their certificate.
+// A client whose responses are encrypted with RSA-OAEP-256 needs a JWKS or a jwks_uri too.
+func ValidateClientAuthentication(tokenEndpointAuthMethod string, jwks []byte, jwksURI string, tlsClientAuthSubjectDN string, usesEncryptionKeys bool) error {
+	if tokenEndpointAuthMethod == constants.TokenEndpointAuthMethodTLSClientAuth {
+		if len(tlsClientAuthSubjectDN) == 0 {
+			return customerrors.NewErrorDetail("", "The tls_client_auth authentication method requires the subject DN of the client certificate.")
+		} else if len(tlsClientAuthSubjectDN) > 256 {
+			return customerrors.NewErrorDetail("", "The subject DN cannot exceed a maximum length of 256 characters.")
+		}
+	} else if len(tlsClientAuthSubjectDN) > 0 {
+		return customerrors.NewErrorDetail("", "A subject DN can only be registered for the tls_client_auth authentication method.")
+	}
+
+	usesSignatureKeys := tokenEndpointAuthMethod == constants.TokenEndpointAuthMethodPrivateKeyJwt ||
+		tokenEndpointAuthMethod == constants.TokenEndpointAuthMethodSelfSignedTLS
+	if !usesSignatureKeys && !usesEncryptionKeys {
+		if len(jwks) > 0 || len(jwksURI) > 0 {
+			return customerrors.NewErrorDetail("", "A JWKS or jwks_uri can only be registered for the private_key_jwt and self_signed_tls_client_auth "+
+				"authentication methods, or for responses encrypted with "+encryption.JWEAlgorithmRSAOAEP256+".")
+		}
+		return nil
+	}
+
+	if len(jwks) > 0 && len(jwksURI) > 0 {
+		return customerrors.NewErrorDetail("", "Only one of JWKS and jwks_uri can be registered.")
+	} else if len(jwksURI) > 0 {
+		if u, err := url.ParseRequestURI(jwksURI); err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+			return customerrors.NewErrorDetail("", "The jwks_uri must be a valid https URL.")
+		} else if len(jwksURI) > 256 {
+			return customerrors.NewErrorDetail("", "The jwks_uri cannot exceed a maximum length of 256 characters.")
+		}
+		return nil
+	} else if len(jwks) == 0 && usesSignatureKeys {
+		return customerrors.NewErrorDetail("", "The "+tokenEndpointAuthMethod+" authentication method requires a JWKS or a jwks_uri.")
+	} else if len(jwks) == 0 {
+		return customerrors.NewErrorDetail("", "Responses encrypted with "+encryption.JWEAlgorithmRSAOAEP256+" require a JWKS or a jwks_uri.")
+	}
+
+	if usesSignatureKeys {
+		publicKeys, err := keyutil.ParseJWKS(jwks)
+		if err != nil {
+			return customerrors.NewErrorDetail("", "The JWKS is invalid: "+errors.Cause(err).Error()+".")
+		} else if len(publicKeys) == 0 {
+			return customerrors.NewErrorDetail("", "The JWKS does not contain any signature verification key.")
+		}
+	}
+
+	if usesEncryptionKeys {
+		encryptionKeys, err := keyutil.ParseEncryptionJWKS(jwks)
+		if err != nil {
+			return customerrors.NewErrorDetail("", "The JWKS is invalid: "+errors.Cause(err).Error()+".")
+		} else if keyutil.SelectRSAEncryptionKey(encryptionKeys, encryption.JWEAlgorithmRSAOAEP256) == nil {
+			return customerrors.NewErrorDetail("", "The JWKS does not contain any RSA encryption key.")
+		}
+	}
+
+	return nil
+}
+
+func isKeyForSigningMethod(key crypto.PublicKey, method jwt.SigningMethod) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		signingMethod, err := keyutil.GetSigningMethod(key)
+		return err == nil && signingMethod.Alg() == method.Alg()
+	}
+	return false
+}
+
+func invalidClient(description string) error {
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_client", description, http.StatusUnauthorized)
+}
@@ -0,0 +1,267 @@
+package validators
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	mocksDB "github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://auth.example.com"
+
+func newClientAssertionContext() context.Context {
+	settings := &models.Settings{
+		Issuer:           testIssuer,
+		AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"), // 32-byte key for AES-256
+	}
+	return context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+}
+
+func newClientAssertionClaims(clientId, audience, jti string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss": clientId,
+		"sub": clientId,
+		"aud": audience,
+		"jti": jti,
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func newPrivateKeyJwtClient(t *testing.T, algorithm string) (*models.Client, string, interface{}) {
+	keyPair, err := keyutil.GenerateKeyPair(algorithm)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+
+	client := &models.Client{
+		Id:                      1,
+		ClientIdentifier:        "test-client",
+		Enabled:                 true,
+		TokenEndpointAuthMethod: constants.TokenEndpointAuthMethodPrivateKeyJwt,
+		JWKS:                    []byte(`{"keys":[` + string(keyPair.PublicKeyJWK) + `]}`),
+	}
+	return client, keyPair.KeyIdentifier, privateKey
+}
+
+func signClientAssertion(t *testing.T, claims jwt.MapClaims, kid string, key interface{}) string {
+	signingMethod, err := keyutil.GetSigningMethod(key)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	token.Header["kid"] = kid
+	assertion, err := token.SignedString(key)
+	require.NoError(t, err)
+	return assertion
+}
+
+func TestValidateClientAssertion_PrivateKeyJwt(t *testing.T) {
+	for _, algorithm := range keyutil.SupportedAlgorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			mockDB := mocksDB.NewDatabase(t)
+			client, kid, privateKey := newPrivateKeyJwtClient(t, algorithm)
+			assertion := signClientAssertion(t, newClientAssertionClaims("test-client", testIssuer, "jti-1"), kid, privateKey)
+
+			mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+			mockDB.On("GetClientAssertionJti", mock.Anything, int64(1), mock.Anything).Return(nil, nil)
+			mockDB.On("CreateClientAssertionJti", mock.Anything, mock.MatchedBy(func(jti *models.ClientAssertionJti) bool {
+				return jti.ClientId == 1 && len(jti.JtiHash) > 0 && jti.ExpiresAt.Valid
+			})).Return(nil)
+
+			result, err := NewClientAssertionValidator(mockDB).ValidateClientAssertion(newClientAssertionContext(), &ValidateClientAssertionInput{
+				ClientAssertionType: constants.ClientAssertionTypeJwtBearer,
+				ClientAssertion:     assertion,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, client, result)
+		})
+	}
+}
+
+func TestValidateClientAssertion_ClientSecretJwt(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	ctx := newClientAssertionContext()
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	clientSecretEncrypted, err := encryption.EncryptText("client-secret", settings.AESEncryptionKey)
+	require.NoError(t, err)
+
+	client := &models.Client{
+		Id:                      1,
+		ClientIdentifier:        "test-client",
+		Enabled:                 true,
+		ClientSecretEncrypted:   clientSecretEncrypted,
+		TokenEndpointAuthMethod: constants.TokenEndpointAuthMethodClientSecretJwt,
+	}
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodHS256, newClientAssertionClaims("test-client", testIssuer, "jti-1")).
+		SignedString([]byte("client-secret"))
+	require.NoError(t, err)
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+	mockDB.On("GetClientAssertionJti", mock.Anything, int64(1), mock.Anything).Return(nil, nil)
+	mockDB.On("CreateClientAssertionJti", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := NewClientAssertionValidator(mockDB).ValidateClientAssertion(ctx, &ValidateClientAssertionInput{
+		ClientId:            "test-client",
+		ClientAssertionType: constants.ClientAssertionTypeJwtBearer,
+		ClientAssertion:     assertion,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, client, result)
+}
+
+func TestValidateClientAssertion_Invalid(t *testing.T) {
+	client, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+	_, _, otherPrivateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+
+	expiredClaims := newClientAssertionClaims("test-client", testIssuer, "jti-1")
+	expiredClaims["exp"] = time.Now().Add(-time.Minute).Unix()
+	otherIssuerClaims := newClientAssertionClaims("test-client", testIssuer, "jti-1")
+	otherIssuerClaims["iss"] = "other-client"
+	missingJtiClaims := newClientAssertionClaims("test-client", testIssuer, "")
+	delete(missingJtiClaims, "jti")
+
+	tests := []struct {
+		name          string
+		assertion     string
+		expectedError string
+	}{
+		{
+			name:          "Wrong audience",
+			assertion:     signClientAssertion(t, newClientAssertionClaims("test-client", "https://other.example.com", "jti-1"), kid, privateKey),
+			expectedError: "The aud claim of the client assertion must contain the issuer or the token endpoint URL.",
+		},
+		{
+			name:      "Expired",
+			assertion: signClientAssertion(t, expiredClaims, kid, privateKey),
+		},
+		{
+			name:      "Issuer is not the client",
+			assertion: signClientAssertion(t, otherIssuerClaims, kid, privateKey),
+		},
+		{
+			name:      "Signed with an unregistered key",
+			assertion: signClientAssertion(t, newClientAssertionClaims("test-client", testIssuer, "jti-1"), kid, otherPrivateKey),
+		},
+		{
+			name:          "Missing jti",
+			assertion:     signClientAssertion(t, missingJtiClaims, kid, privateKey),
+			expectedError: "The client assertion must have a jti claim.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocksDB.NewDatabase(t)
+			mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+
+			result, err := NewClientAssertionValidator(mockDB).ValidateClientAssertion(newClientAssertionContext(), &ValidateClientAssertionInput{
+				ClientAssertionType: constants.ClientAssertionTypeJwtBearer,
+				ClientAssertion:     tt.assertion,
+			})
+			assert.Nil(t, result)
+			errDetail, ok := err.(*customerrors.ErrorDetail)
+			require.True(t, ok)
+			assert.Equal(t, "invalid_client", errDetail.GetCode())
+			assert.Equal(t, 401, errDetail.GetHttpStatusCode())
+			if len(tt.expectedError) > 0 {
+				assert.Equal(t, tt.expectedError, errDetail.GetDescription())
+			}
+			mockDB.AssertNotCalled(t, "CreateClientAssertionJti", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestValidateClientAssertion_Replay(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	client, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmEdDSA)
+	assertion := signClientAssertion(t, newClientAssertionClaims("test-client", testIssuer, "jti-1"), kid, privateKey)
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+	mockDB.On("GetClientAssertionJti", mock.Anything, int64(1), mock.Anything).Return(&models.ClientAssertionJti{Id: 1, ClientId: 1}, nil)
+
+	result, err := NewClientAssertionValidator(mockDB).ValidateClientAssertion(newClientAssertionContext(), &ValidateClientAssertionInput{
+		ClientAssertionType: constants.ClientAssertionTypeJwtBearer,
+		ClientAssertion:     assertion,
+	})
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	assert.Equal(t, "The client assertion has already been used.", err.(*customerrors.ErrorDetail).GetDescription())
+	mockDB.AssertNotCalled(t, "CreateClientAssertionJti", mock.Anything, mock.Anything)
+}
+
+func TestValidateClientAssertion_ClientIdMismatch(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	_, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+	assertion := signClientAssertion(t, newClientAssertionClaims("test-client", testIssuer, "jti-1"), kid, privateKey)
+
+	result, err := NewClientAssertionValidator(mockDB).ValidateClientAssertion(newClientAssertionContext(), &ValidateClientAssertionInput{
+		ClientId:            "other-client",
+		ClientAssertionType: constants.ClientAssertionTypeJwtBearer,
+		ClientAssertion:     assertion,
+	})
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	mockDB.AssertNotCalled(t, "GetClientByClientIdentifier", mock.Anything, mock.Anything)
+}
+
+func TestValidateClientAuthentication(t *testing.T) {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	jwks := []byte(`{"keys":[` + string(keyPair.PublicKeyJWK) + `]}`)
+	rsaKeyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmRS256)
+	require.NoError(t, err)
+	rsaJwk := strings.NewReplacer(`"sig"`, `"enc"`, `"RS256"`, `"RSA-OAEP-256"`).Replace(string(rsaKeyPair.PublicKeyJWK))
+	rsaJwks := []byte(`{"keys":[` + rsaJwk + `]}`)
+
+	tests := []struct {
+		name               string
+		method             string
+		jwks               []byte
+		jwksURI            string
+		subjectDN          string
+		usesEncryptionKeys bool
+		expectError        bool
+	}{
+		{"Private key JWT with JWKS", constants.TokenEndpointAuthMethodPrivateKeyJwt, jwks, "", "", false, false},
+		{"Private key JWT with jwks_uri", constants.TokenEndpointAuthMethodPrivateKeyJwt, nil, "https://app.example.com/jwks", "", false, false},
+		{"Private key JWT without keys", constants.TokenEndpointAuthMethodPrivateKeyJwt, nil, "", "", false, true},
+		{"Private key JWT with both", constants.TokenEndpointAuthMethodPrivateKeyJwt, jwks, "https://app.example.com/jwks", "", false, true},
+		{"Private key JWT with http jwks_uri", constants.TokenEndpointAuthMethodPrivateKeyJwt, nil, "http://app.example.com/jwks", "", false, true},
+		{"Private key JWT with empty JWKS", constants.TokenEndpointAuthMethodPrivateKeyJwt, []byte(`{"keys":[]}`), "", "", false, true},
+		{"Private key JWT with subject DN", constants.TokenEndpointAuthMethodPrivateKeyJwt, jwks, "", "CN=app", false, true},
+		{"Client secret JWT", constants.TokenEndpointAuthMethodClientSecretJwt, nil, "", "", false, false},
+		{"Client secret JWT with JWKS", constants.TokenEndpointAuthMethodClientSecretJwt, jwks, "", "", false, true},
+		{"Client secret", "", nil, "https://app.example.com/jwks", "", false, true},
+		{"TLS client auth", constants.TokenEndpointAuthMethodTLSClientAuth, nil, "", "CN=app,O=Example", false, false},
+		{"TLS client auth without subject DN", constants.TokenEndpointAuthMethodTLSClientAuth, nil, "", "", false, true},
+		{"TLS client auth with JWKS", constants.TokenEndpointAuthMethodTLSClientAuth, jwks, "", "CN=app", false, true},
+		{"Self-signed TLS client auth", constants.TokenEndpointAuthMethodSelfSignedTLS, jwks, "", "", false, false},
+		{"Self-signed TLS client auth without keys", constants.TokenEndpointAuthMethodSelfSignedTLS, nil, "", "", false, true},
+		{"Client secret with RSA encryption keys", "", rsaJwks, "", "", true, false},
+		{"Client secret with RSA encryption jwks_uri", "", nil, "https://app.example.com/jwks", "", true, false},
+		{"Client secret with RSA encryption without keys", "", nil, "", "", true, true},
+		{"Client secret with EC keys for RSA encryption", "", jwks, "", "", true, true},
+		{"Private key JWT with EC keys for RSA encryption", constants.TokenEndpointAuthMethodPrivateKeyJwt, jwks, "", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClientAuthentication(tt.method, tt.jwks, tt.jwksURI, tt.subjectDN, tt.usesEncryptionKeys)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package validators
+
+import (
+	"crypto"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/config"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pkg/errors"
+)
+
+// loadClientCAs reads the CA certificates trusted for tls_client_auth once.
+// It returns a nil pool when no CA file is configured.
+var loadClientCAs = sync.OnceValues(func() (*x509.CertPool, error) {
+	clientCAFile := config.GetAuthServer().ClientCAFile
+	if len(clientCAFile) == 0 {
+		return nil, nil
+	}
+
+	pemCerts, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the client CA file")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return nil, errors.WithStack(errors.New("the client CA file does not contain any PEM certificate"))
+	}
+	return pool, nil
+})
+
+type ClientCertificateValidator struct {
+	httpClient *http.Client
+	clientCAs  func() (*x509.CertPool, error)
+}
+
+func NewClientCertificateValidator() *ClientCertificateValidator {
+	return &ClientCertificateValidator{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		clientCAs:  loadClientCAs,
+	}
+}
+
+// ValidateClientCertificate authenticates a client with the certificate chain it presented
+// in the TLS handshake (RFC 8705, section 2). A tls_client_auth certificate must chain to a
+// trusted CA and have the registered subject DN, a self_signed_tls_client_auth certificate
+// must hold one of the keys registered by the client.
+func (val *ClientCertificateValidator) ValidateClientCertificate(client *models.Client, certs []*x509.Certificate) error {
+	if len(certs) == 0 {
+		return invalidClient("This client must authenticate with a TLS client certificate.")
+	}
+
+	switch client.TokenEndpointAuthMethod {
+	case constants.TokenEndpointAuthMethodTLSClientAuth:
+		roots, err := val.clientCAs()
+		if err != nil {
+			return err
+		} else if roots == nil {
+			return invalidClient("The tls_client_auth authentication method is not enabled on this server.")
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		if _, err = certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}); err != nil {
+			return invalidClient("The client certificate is not trusted.")
+		} else if certs[0].Subject.String() != client.TLSClientAuthSubjectDN {
+			return invalidClient("The subject of the client certificate does not match the registered subject DN.")
+		}
+		return nil
+	case constants.TokenEndpointAuthMethodSelfSignedTLS:
+		publicKeys, err := getClientPublicKeys(val.httpClient, client)
+		if err != nil {
+			return err
+		}
+
+		for _, publicKey := range publicKeys {
+			if key, ok := publicKey.Key.(interface{ Equal(crypto.PublicKey) bool }); ok && key.Equal(certs[0].PublicKey) {
+				return nil
+			}
+		}
+		return invalidClient("The client certificate does not match any key registered by the client.")
+	}
+
+	return invalidClient("Client authentication failed.")
+}
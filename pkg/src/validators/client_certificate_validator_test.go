@@ -0,0 +1,115 @@
+package validators
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertificate(t *testing.T, subject string, publicKey interface{}, parent *x509.Certificate, parentKey interface{}, isCA bool) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: subject},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func newTestClientCertificateValidator(roots *x509.CertPool) *ClientCertificateValidator {
+	return &ClientCertificateValidator{
+		httpClient: &http.Client{},
+		clientCAs: func() (*x509.CertPool, error) {
+			return roots, nil
+		},
+	}
+}
+
+func assertInvalidClient(t *testing.T, err error) {
+	errDetail, ok := err.(*customerrors.ErrorDetail)
+	require.True(t, ok)
+	assert.Equal(t, "invalid_client", errDetail.GetCode())
+	assert.Equal(t, 401, errDetail.GetHttpStatusCode())
+}
+
+func TestValidateClientCertificate_SelfSigned(t *testing.T) {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+	ecdsaKey := privateKey.(*ecdsa.PrivateKey)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	client := &models.Client{
+		ClientIdentifier:        "test-client",
+		TokenEndpointAuthMethod: constants.TokenEndpointAuthMethodSelfSignedTLS,
+		JWKS:                    []byte(`{"keys":[` + string(keyPair.PublicKeyJWK) + `]}`),
+	}
+	validator := newTestClientCertificateValidator(nil)
+
+	cert := newTestCertificate(t, "test-client", &ecdsaKey.PublicKey, nil, ecdsaKey, false)
+	assert.NoError(t, validator.ValidateClientCertificate(client, []*x509.Certificate{cert}))
+
+	otherCert := newTestCertificate(t, "test-client", &otherKey.PublicKey, nil, otherKey, false)
+	assertInvalidClient(t, validator.ValidateClientCertificate(client, []*x509.Certificate{otherCert}))
+	assertInvalidClient(t, validator.ValidateClientCertificate(client, nil))
+}
+
+func TestValidateClientCertificate_TLSClientAuth(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caCert := newTestCertificate(t, "Test CA", &caKey.PublicKey, nil, caKey, true)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+
+	clientCert := newTestCertificate(t, "test-client", &clientKey.PublicKey, caCert, caKey, false)
+	selfSignedCert := newTestCertificate(t, "test-client", &clientKey.PublicKey, nil, clientKey, false)
+
+	client := &models.Client{
+		ClientIdentifier:        "test-client",
+		TokenEndpointAuthMethod: constants.TokenEndpointAuthMethodTLSClientAuth,
+		TLSClientAuthSubjectDN:  "CN=test-client",
+	}
+	otherClient := &models.Client{
+		ClientIdentifier:        "other-client",
+		TokenEndpointAuthMethod: constants.TokenEndpointAuthMethodTLSClientAuth,
+		TLSClientAuthSubjectDN:  "CN=other-client",
+	}
+
+	validator := newTestClientCertificateValidator(roots)
+	assert.NoError(t, validator.ValidateClientCertificate(client, []*x509.Certificate{clientCert}))
+	assertInvalidClient(t, validator.ValidateClientCertificate(otherClient, []*x509.Certificate{clientCert}))
+	assertInvalidClient(t, validator.ValidateClientCertificate(client, []*x509.Certificate{selfSignedCert}))
+
+	// tls_client_auth is disabled without trusted CAs
+	assertInvalidClient(t, newTestClientCertificateValidator(nil).ValidateClientCertificate(client, []*x509.Certificate{clientCert}))
+}
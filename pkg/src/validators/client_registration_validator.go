@@ -0,0 +1,318 @@
+package validators
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+const maxClientNameLength = 100
+
+var (
+	supportedRegistrationGrantTypes = []string{
+		"authorization_code", "refresh_token", "client_credentials", constants.DeviceCodeGrantType,
+		constants.CIBAGrantType,
+	}
+	supportedRegistrationAuthMethods = []string{
+		"client_secret_basic", "client_secret_post",
+		constants.TokenEndpointAuthMethodPrivateKeyJwt, constants.TokenEndpointAuthMethodClientSecretJwt,
+		constants.TokenEndpointAuthMethodTLSClientAuth, constants.TokenEndpointAuthMethodSelfSignedTLS, "none",
+	}
+)
+
+type ClientRegistrationValidator struct {
+	database           database.Database
+	authorizeValidator *AuthorizeValidator
+}
+
+func NewClientRegistrationValidator(database database.Database) *ClientRegistrationValidator {
+	return &ClientRegistrationValidator{
+		database:           database,
+		authorizeValidator: NewAuthorizeValidator(database),
+	}
+}
+
+// ValidateClientMetadata validates the metadata sent to the registration endpoint,
+// normalizing it in place and applying the defaults from RFC 7591, section 2.
+// Resource scopes become client permissions, so they are only accepted when
+// allowResourceScopes is set (registration with an initial access token).
+func (val *ClientRegistrationValidator) ValidateClientMetadata(metadata *oauth.ClientMetadata, allowResourceScopes bool) error {
+	if len(metadata.GrantTypes) == 0 {
+		metadata.GrantTypes = []string{"authorization_code"}
+	}
+
+	grantTypes := make([]string, 0, len(metadata.GrantTypes))
+	for _, grantType := range metadata.GrantTypes {
+		if !slices.Contains(supportedRegistrationGrantTypes, grantType) {
+			return invalidClientMetadata("Unsupported grant type: " + grantType + ".")
+		}
+
+		if !slices.Contains(grantTypes, grantType) {
+			grantTypes = append(grantTypes, grantType)
+		}
+	}
+	metadata.GrantTypes = grantTypes
+
+	if slices.Contains(grantTypes, "refresh_token") && !slices.Contains(grantTypes, "authorization_code") &&
+		!slices.Contains(grantTypes, constants.DeviceCodeGrantType) && !slices.Contains(grantTypes, constants.CIBAGrantType) {
+		return invalidClientMetadata("The refresh_token grant type requires the authorization_code, device_code or ciba grant type.")
+	}
+
+	authorizationCodeEnabled := slices.Contains(grantTypes, "authorization_code")
+	if len(metadata.ResponseTypes) == 0 && authorizationCodeEnabled {
+		metadata.ResponseTypes = []string{"code"}
+	}
+
+	for _, responseType := range metadata.ResponseTypes {
+		if responseType != "code" {
+			return invalidClientMetadata("Unsupported response type: " + responseType + ". Only 'code' is supported.")
+		} else if !authorizationCodeEnabled {
+			return invalidClientMetadata("The 'code' response type requires the authorization_code grant type.")
+		}
+	}
+
+	if len(metadata.TokenEndpointAuthMethod) == 0 {
+		metadata.TokenEndpointAuthMethod = "client_secret_basic"
+	}
+
+	if !slices.Contains(supportedRegistrationAuthMethods, metadata.TokenEndpointAuthMethod) {
+		return invalidClientMetadata("Unsupported token endpoint authentication method: " + metadata.TokenEndpointAuthMethod + ".")
+	} else if metadata.TokenEndpointAuthMethod == "none" && slices.Contains(grantTypes, "client_credentials") {
+		return invalidClientMetadata("A public client cannot use the client_credentials grant type.")
+	} else if metadata.TokenEndpointAuthMethod == "none" && slices.Contains(grantTypes, constants.CIBAGrantType) {
+		return invalidClientMetadata("A public client cannot use the ciba grant type.")
+	} else if metadata.TokenEndpointAuthMethod == "none" && metadata.RequireSignedRequestObject {
+		return invalidClientMetadata("A public client cannot require signed request objects.")
+	}
+
+	if string(metadata.Jwks) == "null" {
+		metadata.Jwks = nil
+	}
+
+	if err := validateRegistrationResponseEncryption(metadata); err != nil {
+		return err
+	}
+
+	usesEncryptionKeys := metadata.IdTokenEncryptedResponseAlg == encryption.JWEAlgorithmRSAOAEP256 ||
+		metadata.UserInfoEncryptedResponseAlg == encryption.JWEAlgorithmRSAOAEP256 ||
+		metadata.AuthorizationEncryptedResponseAlg == encryption.JWEAlgorithmRSAOAEP256
+	metadata.JwksURI = strings.TrimSpace(metadata.JwksURI)
+	metadata.TLSClientAuthSubjectDN = strings.TrimSpace(metadata.TLSClientAuthSubjectDN)
+	if err := ValidateClientAuthentication(metadata.TokenEndpointAuthMethod, metadata.Jwks, metadata.JwksURI, metadata.TLSClientAuthSubjectDN, usesEncryptionKeys); err != nil {
+		var errDetail *customerrors.ErrorDetail
+		if errors.As(err, &errDetail) {
+			return invalidClientMetadata(errDetail.GetDescription())
+		}
+		return err
+	}
+
+	redirectURIs, err := validateRegistrationRedirectURIs(metadata.RedirectURIs)
+	if err != nil {
+		return err
+	} else if authorizationCodeEnabled && len(redirectURIs) == 0 {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_redirect_uri",
+			"At least one redirect URI is required for the authorization_code grant type.", http.StatusBadRequest)
+	}
+	metadata.RedirectURIs = redirectURIs
+
+	if err = validateRegistrationLogoutURIs(metadata); err != nil {
+		return err
+	}
+
+	if err = validateRegistrationBackChannelTokenDelivery(metadata); err != nil {
+		return err
+	}
+
+	if len(metadata.SubjectType) == 0 {
+		metadata.SubjectType = constants.SubjectTypePublic
+	}
+
+	metadata.SectorIdentifierURI = strings.TrimSpace(metadata.SectorIdentifierURI)
+	if metadata.SectorIdentifier, err = ValidateSubjectType(metadata.SubjectType, metadata.SectorIdentifierURI, redirectURIs); err != nil {
+		var errDetail *customerrors.ErrorDetail
+		if errors.As(err, &errDetail) {
+			return invalidClientMetadata(errDetail.GetDescription())
+		}
+		return err
+	}
+
+	webOrigins, err := validateRegistrationWebOrigins(metadata.WebOrigins)
+	if err != nil {
+		return err
+	}
+	metadata.WebOrigins = webOrigins
+
+	metadata.ClientName = strings.TrimSpace(metadata.ClientName)
+	if len(metadata.ClientName) > maxClientNameLength {
+		return invalidClientMetadata("The client_name cannot exceed a maximum length of 100 characters.")
+	}
+
+	return val.validateRegistrationScope(metadata, allowResourceScopes)
+}
+
+func (val *ClientRegistrationValidator) validateRegistrationScope(metadata *oauth.ClientMetadata, allowResourceScopes bool) error {
+	metadata.Scope = strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(metadata.Scope, " "))
+	if len(metadata.Scope) == 0 {
+		return nil
+	}
+
+	if err := val.authorizeValidator.ValidateScopes(metadata.Scope); err != nil {
+		var errDetail *customerrors.ErrorDetail
+		if errors.As(err, &errDetail) {
+			return invalidClientMetadata(errDetail.GetDescription())
+		}
+		return err
+	}
+
+	for _, scope := range strings.Split(metadata.Scope, " ") {
+		if oidc.IsIdTokenScope(scope) || oidc.IsOfflineAccessScope(scope) {
+			continue
+		}
+
+		resourceIdentifier := strings.Split(scope, ":")[0]
+		if resourceIdentifier == constants.AuthServerResourceIdentifier || resourceIdentifier == constants.AdminConsoleResourceIdentifier {
+			return invalidClientMetadata("The scope '" + scope + "' cannot be requested through dynamic client registration.")
+		} else if !allowResourceScopes {
+			return invalidClientMetadata("The scope '" + scope + "' requires an initial access token.")
+		}
+	}
+
+	return nil
+}
+
+// validateRegistrationResponseEncryption validates the encryption of the ID token, userinfo and authorization responses,
+// the clients authenticating with their own keys or certificate have no secret to derive a symmetric key from
+func validateRegistrationResponseEncryption(metadata *oauth.ClientMetadata) error {
+	hasClientSecret := !slices.Contains([]string{
+		"none",
+		constants.TokenEndpointAuthMethodPrivateKeyJwt,
+		constants.TokenEndpointAuthMethodTLSClientAuth,
+		constants.TokenEndpointAuthMethodSelfSignedTLS,
+	}, metadata.TokenEndpointAuthMethod)
+
+	var err error
+	if metadata.IdTokenEncryptedResponseEnc, err = ValidateResponseEncryption("id_token",
+		metadata.IdTokenEncryptedResponseAlg, metadata.IdTokenEncryptedResponseEnc, hasClientSecret); err == nil {
+		metadata.UserInfoEncryptedResponseEnc, err = ValidateResponseEncryption("userinfo",
+			metadata.UserInfoEncryptedResponseAlg, metadata.UserInfoEncryptedResponseEnc, hasClientSecret)
+	}
+	if err == nil {
+		metadata.AuthorizationEncryptedResponseEnc, err = ValidateResponseEncryption("authorization",
+			metadata.AuthorizationEncryptedResponseAlg, metadata.AuthorizationEncryptedResponseEnc, hasClientSecret)
+	}
+
+	var errDetail *customerrors.ErrorDetail
+	if errors.As(err, &errDetail) {
+		return invalidClientMetadata(errDetail.GetDescription())
+	}
+	return err
+}
+
+// validateRegistrationRedirectURIs accepts absolute URIs with a scheme and host, and no fragment
+func validateRegistrationRedirectURIs(input []string) ([]string, error) {
+	redirectURIs := make([]string, 0, len(input))
+	for _, redirectURI := range input {
+		redirectURI = strings.TrimSpace(redirectURI)
+		if u, err := url.Parse(redirectURI); err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_redirect_uri",
+				"Invalid redirect URI: "+redirectURI+".", http.StatusBadRequest)
+		} else if strings.Contains(redirectURI, "#") {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_redirect_uri",
+				"The redirect URI must not include a fragment: "+redirectURI+".", http.StatusBadRequest)
+		}
+
+		if !slices.Contains(redirectURIs, redirectURI) {
+			redirectURIs = append(redirectURIs, redirectURI)
+		}
+	}
+
+	return redirectURIs, nil
+}
+
+// validateRegistrationLogoutURIs accepts absolute URIs with a scheme and host, and no fragment
+func validateRegistrationLogoutURIs(metadata *oauth.ClientMetadata) error {
+	isValid := func(uri string) bool {
+		u, err := url.Parse(uri)
+		return err == nil && len(u.Scheme) > 0 && len(u.Host) > 0 && !strings.Contains(uri, "#")
+	}
+
+	postLogoutRedirectURIs := make([]string, 0, len(metadata.PostLogoutRedirectURIs))
+	for _, postLogoutRedirectURI := range metadata.PostLogoutRedirectURIs {
+		postLogoutRedirectURI = strings.TrimSpace(postLogoutRedirectURI)
+		if !isValid(postLogoutRedirectURI) {
+			return invalidClientMetadata("Invalid post logout redirect URI: " + postLogoutRedirectURI + ".")
+		}
+
+		if !slices.Contains(postLogoutRedirectURIs, postLogoutRedirectURI) {
+			postLogoutRedirectURIs = append(postLogoutRedirectURIs, postLogoutRedirectURI)
+		}
+	}
+	metadata.PostLogoutRedirectURIs = postLogoutRedirectURIs
+
+	metadata.FrontChannelLogoutURI = strings.TrimSpace(metadata.FrontChannelLogoutURI)
+	if len(metadata.FrontChannelLogoutURI) > 0 && !isValid(metadata.FrontChannelLogoutURI) {
+		return invalidClientMetadata("Invalid frontchannel_logout_uri: " + metadata.FrontChannelLogoutURI + ".")
+	}
+
+	metadata.BackChannelLogoutURI = strings.TrimSpace(metadata.BackChannelLogoutURI)
+	if len(metadata.BackChannelLogoutURI) > 0 && !isValid(metadata.BackChannelLogoutURI) {
+		return invalidClientMetadata("Invalid backchannel_logout_uri: " + metadata.BackChannelLogoutURI + ".")
+	}
+
+	return nil
+}
+
+// validateRegistrationBackChannelTokenDelivery defaults clients using the ciba grant type to the poll mode,
+// the delivery metadata being ignored for the other clients
+func validateRegistrationBackChannelTokenDelivery(metadata *oauth.ClientMetadata) error {
+	metadata.BackChannelClientNotificationEndpoint = strings.TrimSpace(metadata.BackChannelClientNotificationEndpoint)
+	if !slices.Contains(metadata.GrantTypes, constants.CIBAGrantType) {
+		metadata.BackChannelTokenDeliveryMode = ""
+		metadata.BackChannelClientNotificationEndpoint = ""
+		return nil
+	}
+
+	mode, err := ValidateBackChannelTokenDelivery(metadata.BackChannelTokenDeliveryMode, metadata.BackChannelClientNotificationEndpoint)
+	if err != nil {
+		var errDetail *customerrors.ErrorDetail
+		if errors.As(err, &errDetail) {
+			return invalidClientMetadata(errDetail.GetDescription())
+		}
+		return err
+	}
+
+	metadata.BackChannelTokenDeliveryMode = mode
+	return nil
+}
+
+func validateRegistrationWebOrigins(input []string) ([]string, error) {
+	webOrigins := make([]string, 0, len(input))
+	for _, webOrigin := range input {
+		webOrigin = strings.ToLower(strings.TrimSpace(webOrigin))
+		if u, err := url.Parse(webOrigin); err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			len(u.Host) == 0 || len(strings.TrimSuffix(u.Path, "/")) > 0 || len(u.RawQuery) > 0 || len(u.Fragment) > 0 {
+			return nil, invalidClientMetadata("Invalid web origin: " + webOrigin + ". It must contain only the scheme, host and optional port.")
+		}
+
+		webOrigin = strings.TrimSuffix(webOrigin, "/")
+		if !slices.Contains(webOrigins, webOrigin) {
+			webOrigins = append(webOrigins, webOrigin)
+		}
+	}
+
+	return webOrigins, nil
+}
+
+func invalidClientMetadata(description string) error {
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_client_metadata", description, http.StatusBadRequest)
+}
@@ -0,0 +1,255 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateClientMetadata_Defaults(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	validator := NewClientRegistrationValidator(mockDB)
+
+	metadata := &oauth.ClientMetadata{
+		RedirectURIs: []string{" https://app.example.com/callback ", "https://app.example.com/callback"},
+		WebOrigins:   []string{"https://APP.example.com/"},
+		ClientName:   " My App ",
+		Scope:        "openid   profile",
+	}
+	err := validator.ValidateClientMetadata(metadata, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"authorization_code"}, metadata.GrantTypes)
+	assert.Equal(t, []string{"code"}, metadata.ResponseTypes)
+	assert.Equal(t, "client_secret_basic", metadata.TokenEndpointAuthMethod)
+	assert.Equal(t, []string{"https://app.example.com/callback"}, metadata.RedirectURIs)
+	assert.Equal(t, []string{"https://app.example.com"}, metadata.WebOrigins)
+	assert.Equal(t, "My App", metadata.ClientName)
+	assert.Equal(t, "openid profile", metadata.Scope)
+}
+
+func TestValidateClientMetadata_ResponseEncryption(t *testing.T) {
+	mockDB := mocks.NewDatabase(t)
+	validator := NewClientRegistrationValidator(mockDB)
+
+	metadata := &oauth.ClientMetadata{
+		RedirectURIs:                      []string{"https://app.example.com/callback"},
+		JwksURI:                           "https://app.example.com/jwks",
+		IdTokenEncryptedResponseAlg:       "RSA-OAEP-256",
+		UserInfoEncryptedResponseAlg:      "A256KW",
+		UserInfoEncryptedResponseEnc:      "A256GCM",
+		AuthorizationEncryptedResponseAlg: "RSA-OAEP-256",
+	}
+	err := validator.ValidateClientMetadata(metadata, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "A128CBC-HS256", metadata.IdTokenEncryptedResponseEnc)
+	assert.Equal(t, "A256GCM", metadata.UserInfoEncryptedResponseEnc)
+	assert.Equal(t, "A128CBC-HS256", metadata.AuthorizationEncryptedResponseEnc)
+}
+
+func TestValidateClientMetadata_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		metadata      oauth.ClientMetadata
+		expectedCode  string
+		expectedError string
+	}{
+		{
+			name:          "Unsupported grant type",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"implicit"}},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "Unsupported grant type: implicit.",
+		},
+		{
+			name:          "Refresh token without user flow",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials", "refresh_token"}},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The refresh_token grant type requires the authorization_code, device_code or ciba grant type.",
+		},
+		{
+			name:          "Unsupported response type",
+			metadata:      oauth.ClientMetadata{RedirectURIs: []string{"https://app.example.com/cb"}, ResponseTypes: []string{"token"}},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "Unsupported response type: token. Only 'code' is supported.",
+		},
+		{
+			name:          "Code response type without authorization code grant",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, ResponseTypes: []string{"code"}},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The 'code' response type requires the authorization_code grant type.",
+		},
+		{
+			name:          "Unsupported auth method",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: "client_secret_digest"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "Unsupported token endpoint authentication method: client_secret_digest.",
+		},
+		{
+			name:          "Public client with client credentials",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: "none"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "A public client cannot use the client_credentials grant type.",
+		},
+		{
+			name:          "Public client with ciba",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"urn:openid:params:grant-type:ciba"}, TokenEndpointAuthMethod: "none"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "A public client cannot use the ciba grant type.",
+		},
+		{
+			name: "Ciba ping mode without notification endpoint",
+			metadata: oauth.ClientMetadata{GrantTypes: []string{"urn:openid:params:grant-type:ciba"},
+				BackChannelTokenDeliveryMode: "ping"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The ping mode requires a backchannel_client_notification_endpoint.",
+		},
+		{
+			name:          "Private key JWT without keys",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: "private_key_jwt"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The private_key_jwt authentication method requires a JWKS or a jwks_uri.",
+		},
+		{
+			name:         "JWKS with client secret authentication",
+			metadata:     oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, JwksURI: "https://app.example.com/jwks"},
+			expectedCode: "invalid_client_metadata",
+			expectedError: "A JWKS or jwks_uri can only be registered for the private_key_jwt and self_signed_tls_client_auth " +
+				"authentication methods, or for responses encrypted with RSA-OAEP-256.",
+		},
+		{
+			name: "ID token encrypted with RSA-OAEP-256 without keys",
+			metadata: oauth.ClientMetadata{RedirectURIs: []string{"https://app.example.com/cb"},
+				IdTokenEncryptedResponseAlg: "RSA-OAEP-256"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "Responses encrypted with RSA-OAEP-256 require a JWKS or a jwks_uri.",
+		},
+		{
+			name: "Userinfo encrypted with the secret of a public client",
+			metadata: oauth.ClientMetadata{RedirectURIs: []string{"https://app.example.com/cb"}, TokenEndpointAuthMethod: "none",
+				UserInfoEncryptedResponseAlg: "dir"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The dir encryption of the userinfo responses requires a client secret.",
+		},
+		{
+			name: "Authorization response encryption method without algorithm",
+			metadata: oauth.ClientMetadata{RedirectURIs: []string{"https://app.example.com/cb"},
+				AuthorizationEncryptedResponseEnc: "A128GCM"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The authorization_encrypted_response_enc requires the authorization_encrypted_response_alg.",
+		},
+		{
+			name:          "TLS client auth without subject DN",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: "tls_client_auth"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The tls_client_auth authentication method requires the subject DN of the client certificate.",
+		},
+		{
+			name:          "Missing redirect URI",
+			metadata:      oauth.ClientMetadata{},
+			expectedCode:  "invalid_redirect_uri",
+			expectedError: "At least one redirect URI is required for the authorization_code grant type.",
+		},
+		{
+			name:          "Relative redirect URI",
+			metadata:      oauth.ClientMetadata{RedirectURIs: []string{"/callback"}},
+			expectedCode:  "invalid_redirect_uri",
+			expectedError: "Invalid redirect URI: /callback.",
+		},
+		{
+			name:          "Redirect URI with fragment",
+			metadata:      oauth.ClientMetadata{RedirectURIs: []string{"https://app.example.com/cb#frag"}},
+			expectedCode:  "invalid_redirect_uri",
+			expectedError: "The redirect URI must not include a fragment: https://app.example.com/cb#frag.",
+		},
+		{
+			name:          "Pairwise with redirect URIs on different hosts",
+			metadata:      oauth.ClientMetadata{RedirectURIs: []string{"https://app.example.com/cb", "https://admin.example.org/cb"}, SubjectType: "pairwise"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "The redirect URIs of a pairwise client must share one host, otherwise a sector_identifier_uri is required.",
+		},
+		{
+			name:          "Web origin with path",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, WebOrigins: []string{"https://app.example.com/path"}},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "Invalid web origin: https://app.example.com/path. It must contain only the scheme, host and optional port.",
+		},
+		{
+			name:          "Invalid scope",
+			metadata:      oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, Scope: "a:b:c"},
+			expectedCode:  "invalid_client_metadata",
+			expectedError: "Invalid scope format: 'a:b:c'. Scopes must adhere to the resource-identifier:permission-identifier format. For instance: backend-service:create-product.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDatabase(t)
+			validator := NewClientRegistrationValidator(mockDB)
+
+			err := validator.ValidateClientMetadata(&tt.metadata, true)
+
+			assert.Error(t, err)
+			errDetail, ok := err.(*customerrors.ErrorDetail)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, errDetail.GetCode())
+			assert.Equal(t, tt.expectedError, errDetail.GetDescription())
+		})
+	}
+}
+
+func TestValidateClientMetadata_ResourceScopes(t *testing.T) {
+	tests := []struct {
+		name                string
+		scope               string
+		allowResourceScopes bool
+		expectedError       string
+	}{
+		{
+			name:                "Allowed with initial access token",
+			scope:               "orders:read",
+			allowResourceScopes: true,
+		},
+		{
+			name:          "Rejected for open registration",
+			scope:         "orders:read",
+			expectedError: "The scope 'orders:read' requires an initial access token.",
+		},
+		{
+			name:                "System resource is always rejected",
+			scope:               constants.AdminConsoleResourceIdentifier + ":" + constants.ManageAdminConsolePermissionIdentifier,
+			allowResourceScopes: true,
+			expectedError:       "The scope 'adminconsole:manage' cannot be requested through dynamic client registration.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDatabase(t)
+			validator := NewClientRegistrationValidator(mockDB)
+
+			resourceIdentifier, permissionIdentifier, _ := strings.Cut(tt.scope, ":")
+			mockDB.On("GetResourceByResourceIdentifier", mock.Anything, resourceIdentifier).Return(&models.Resource{Id: 1, ResourceIdentifier: resourceIdentifier}, nil)
+			mockDB.On("GetPermissionsByResourceId", mock.Anything, int64(1)).Return([]models.Permission{{PermissionIdentifier: permissionIdentifier}}, nil)
+
+			metadata := &oauth.ClientMetadata{GrantTypes: []string{"client_credentials"}, Scope: tt.scope}
+			err := validator.ValidateClientMetadata(metadata, tt.allowResourceScopes)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				errDetail, ok := err.(*customerrors.ErrorDetail)
+				assert.True(t, ok)
+				assert.Equal(t, "invalid_client_metadata", errDetail.GetCode())
+				assert.Equal(t, tt.expectedError, errDetail.GetDescription())
+			}
+		})
+	}
+}
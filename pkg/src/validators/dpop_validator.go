@@ -0,0 +1,172 @@
+package validators
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/database"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dpopProofMaxAge is how long after its iat a DPoP proof is accepted
+	dpopProofMaxAge = 5 * time.Minute
+	// dpopProofClockSkew tolerates clients whose clock is ahead of the server
+	dpopProofClockSkew = time.Minute
+)
+
+// DPoPSigningAlgorithms lists the algorithms accepted for DPoP proofs, which must be asymmetric
+func DPoPSigningAlgorithms() []string {
+	return privateKeyJwtAlgorithms
+}
+
+type ValidateDPoPProofInput struct {
+	// Proofs are the values of the DPoP header, a request must have exactly one
+	Proofs     []string
+	HttpMethod string
+	HttpURL    string
+	// AccessToken is the token a resource request is made with, the proof must hold its hash
+	AccessToken  string
+	RequireNonce bool
+}
+
+type DPoPValidator struct {
+	database database.Database
+}
+
+func NewDPoPValidator(database database.Database) *DPoPValidator {
+	return &DPoPValidator{
+		database: database,
+	}
+}
+
+// ValidateDPoPProof validates the DPoP proof of a request (RFC 9449, section 4.3)
+// and returns the JWK thumbprint of the key that signed it.
+func (val *DPoPValidator) ValidateDPoPProof(ctx context.Context, input *ValidateDPoPProofInput) (string, error) {
+	if len(input.Proofs) != 1 {
+		return "", invalidDPoPProof("The request must have exactly one DPoP header.")
+	}
+
+	var jkt string
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(input.Proofs[0], claims, func(token *jwt.Token) (interface{}, error) {
+		if typ, _ := token.Header["typ"].(string); typ != constants.DPoPProofType {
+			return nil, errors.WithStack(errors.New("the typ header must be " + constants.DPoPProofType))
+		}
+
+		jwk, err := json.Marshal(token.Header["jwk"])
+		if err != nil || token.Header["jwk"] == nil {
+			return nil, errors.WithStack(errors.New("the jwk header is missing"))
+		}
+
+		publicKey, thumbprint, err := keyutil.ParsePublicJWK(jwk)
+		if err != nil {
+			return nil, err
+		} else if !isKeyForSigningMethod(publicKey, token.Method) {
+			return nil, errors.WithStack(errors.New("the jwk header does not match the alg header"))
+		}
+
+		jkt = thumbprint
+		return publicKey, nil
+	}, jwt.WithValidMethods(DPoPSigningAlgorithms())); err != nil {
+		return "", invalidDPoPProof("The DPoP proof is invalid (" + err.Error() + ").")
+	}
+
+	if htm, _ := claims["htm"].(string); htm != input.HttpMethod {
+		return "", invalidDPoPProof("The htm claim of the DPoP proof does not match the HTTP method of the request.")
+	} else if htu, _ := claims["htu"].(string); !isDPoPTargetURI(htu, input.HttpURL) {
+		return "", invalidDPoPProof("The htu claim of the DPoP proof does not match the URL of the request.")
+	}
+
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return "", invalidDPoPProof("The DPoP proof must have an iat claim.")
+	} else if age := time.Since(issuedAt.Time); age > dpopProofMaxAge || age < -dpopProofClockSkew {
+		return "", invalidDPoPProof("The DPoP proof is too old or was issued in the future.")
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	if len(nonce) > 0 || input.RequireNonce {
+		settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+		if !oauth.IsDPoPNonceValid(settings.AESEncryptionKey, nonce) {
+			return "", customerrors.NewErrorDetailWithHttpStatusCode("use_dpop_nonce",
+				"The DPoP proof must have the nonce provided in the "+constants.DPoPNonceHeaderName+" header.", http.StatusBadRequest)
+		}
+	}
+
+	if len(input.AccessToken) > 0 {
+		hash := sha256.Sum256([]byte(input.AccessToken))
+		if ath, _ := claims["ath"].(string); ath != base64.RawURLEncoding.EncodeToString(hash[:]) {
+			return "", invalidDPoPProof("The ath claim of the DPoP proof does not match the access token.")
+		}
+	}
+
+	if err = val.preventReplay(jkt, claims, issuedAt.Time); err != nil {
+		return "", err
+	}
+
+	return jkt, nil
+}
+
+// preventReplay records the jti of the proof until it is too old to be accepted,
+// rejecting a proof whose jti was already used with the same key
+func (val *DPoPValidator) preventReplay(jkt string, claims jwt.MapClaims, issuedAt time.Time) error {
+	jti, _ := claims["jti"].(string)
+	if len(jti) == 0 {
+		return invalidDPoPProof("The DPoP proof must have a jti claim.")
+	}
+
+	jtiHash, err := hashutil.HashString(jkt + ":" + jti)
+	if err != nil {
+		return err
+	}
+
+	dpopProofJti, err := val.database.GetDPoPProofJti(nil, jtiHash)
+	if err != nil {
+		return err
+	} else if dpopProofJti != nil {
+		return invalidDPoPProof("The DPoP proof has already been used.")
+	}
+
+	// the unique index on jti_hash rejects concurrent replays
+	return val.database.CreateDPoPProofJti(nil, &models.DPoPProofJti{
+		JtiHash:   jtiHash,
+		ExpiresAt: sql.NullTime{Time: issuedAt.Add(dpopProofMaxAge).UTC(), Valid: true},
+	})
+}
+
+// isDPoPTargetURI compares the htu claim with the URL of the request,
+// ignoring the query and fragment parts (RFC 9449, section 4.3)
+func isDPoPTargetURI(htu string, requestURL string) bool {
+	htuURL, err := url.Parse(htu)
+	if err != nil || len(htuURL.Host) == 0 {
+		return false
+	}
+
+	reqURL, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(htuURL.Scheme, reqURL.Scheme) &&
+		strings.EqualFold(htuURL.Host, reqURL.Host) &&
+		strings.TrimSuffix(htuURL.Path, "/") == strings.TrimSuffix(reqURL.Path, "/")
+}
+
+func invalidDPoPProof(description string) error {
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_dpop_proof", description, http.StatusBadRequest)
+}
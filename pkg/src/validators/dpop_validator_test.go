@@ -0,0 +1,252 @@
+package validators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	mocksDB "github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/pchchv/aas/pkg/src/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenEndpoint = "https://auth.example.com/auth/token"
+
+type dpopTestKey struct {
+	privateKey interface{}
+	jwk        json.RawMessage
+	jkt        string
+}
+
+func newDPoPTestKey(t *testing.T) *dpopTestKey {
+	keyPair, err := keyutil.GenerateKeyPair(keyutil.AlgorithmES256)
+	require.NoError(t, err)
+	privateKey, err := keyutil.ParsePrivateKeyFromPEM(keyPair.PrivateKeyPEM)
+	require.NoError(t, err)
+	_, jkt, err := keyutil.ParsePublicJWK(keyPair.PublicKeyJWK)
+	require.NoError(t, err)
+
+	return &dpopTestKey{
+		privateKey: privateKey,
+		jwk:        keyPair.PublicKeyJWK,
+		jkt:        jkt,
+	}
+}
+
+func newDPoPProofClaims(ctx context.Context, htm, htu, jti string) jwt.MapClaims {
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	return jwt.MapClaims{
+		"htm":   htm,
+		"htu":   htu,
+		"jti":   jti,
+		"iat":   time.Now().Unix(),
+		"nonce": oauth.NewDPoPNonce(settings.AESEncryptionKey),
+	}
+}
+
+func signDPoPProof(t *testing.T, key *dpopTestKey, claims jwt.MapClaims) string {
+	signingMethod, err := keyutil.GetSigningMethod(key.privateKey)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(signingMethod, claims)
+	token.Header["typ"] = constants.DPoPProofType
+	token.Header["jwk"] = key.jwk
+	proof, err := token.SignedString(key.privateKey)
+	require.NoError(t, err)
+	return proof
+}
+
+func TestValidateDPoPProof(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	ctx := newClientAssertionContext()
+	key := newDPoPTestKey(t)
+	proof := signDPoPProof(t, key, newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1"))
+
+	mockDB.On("GetDPoPProofJti", mock.Anything, mock.Anything).Return(nil, nil)
+	mockDB.On("CreateDPoPProofJti", mock.Anything, mock.MatchedBy(func(jti *models.DPoPProofJti) bool {
+		return len(jti.JtiHash) > 0 && jti.ExpiresAt.Valid
+	})).Return(nil)
+
+	jkt, err := NewDPoPValidator(mockDB).ValidateDPoPProof(ctx, &ValidateDPoPProofInput{
+		Proofs:       []string{proof},
+		HttpMethod:   "POST",
+		HttpURL:      testTokenEndpoint,
+		RequireNonce: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, key.jkt, jkt)
+}
+
+func TestValidateDPoPProof_ResourceRequest(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	ctx := newClientAssertionContext()
+	key := newDPoPTestKey(t)
+	hash := sha256.Sum256([]byte("access-token"))
+
+	claims := newDPoPProofClaims(ctx, "GET", "https://auth.example.com/userinfo?query=ignored", "jti-1")
+	claims["ath"] = base64.RawURLEncoding.EncodeToString(hash[:])
+	delete(claims, "nonce")
+
+	mockDB.On("GetDPoPProofJti", mock.Anything, mock.Anything).Return(nil, nil)
+	mockDB.On("CreateDPoPProofJti", mock.Anything, mock.Anything).Return(nil)
+
+	jkt, err := NewDPoPValidator(mockDB).ValidateDPoPProof(ctx, &ValidateDPoPProofInput{
+		Proofs:      []string{signDPoPProof(t, key, claims)},
+		HttpMethod:  "GET",
+		HttpURL:     "https://auth.example.com/userinfo",
+		AccessToken: "access-token",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, key.jkt, jkt)
+}
+
+func TestValidateDPoPProof_Invalid(t *testing.T) {
+	ctx := newClientAssertionContext()
+	key := newDPoPTestKey(t)
+	validProof := signDPoPProof(t, key, newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1"))
+
+	oldClaims := newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1")
+	oldClaims["iat"] = time.Now().Add(-10 * time.Minute).Unix()
+	missingJtiClaims := newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "")
+	delete(missingJtiClaims, "jti")
+
+	withoutTyp := jwt.NewWithClaims(jwt.SigningMethodES256, newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1"))
+	withoutTyp.Header["jwk"] = key.jwk
+	withoutTypProof, err := withoutTyp.SignedString(key.privateKey)
+	require.NoError(t, err)
+
+	symmetric := jwt.NewWithClaims(jwt.SigningMethodHS256, newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1"))
+	symmetric.Header["typ"] = constants.DPoPProofType
+	symmetric.Header["jwk"] = json.RawMessage(`{"kty":"oct","k":"c2VjcmV0"}`)
+	symmetricProof, err := symmetric.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		proofs        []string
+		accessToken   string
+		expectedError string
+	}{
+		{
+			name:          "No proof",
+			expectedError: "The request must have exactly one DPoP header.",
+		},
+		{
+			name:          "Two proofs",
+			proofs:        []string{validProof, validProof},
+			expectedError: "The request must have exactly one DPoP header.",
+		},
+		{
+			name:          "Wrong HTTP method",
+			proofs:        []string{signDPoPProof(t, key, newDPoPProofClaims(ctx, "GET", testTokenEndpoint, "jti-1"))},
+			expectedError: "The htm claim of the DPoP proof does not match the HTTP method of the request.",
+		},
+		{
+			name:          "Wrong URL",
+			proofs:        []string{signDPoPProof(t, key, newDPoPProofClaims(ctx, "POST", "https://auth.example.com/auth/par", "jti-1"))},
+			expectedError: "The htu claim of the DPoP proof does not match the URL of the request.",
+		},
+		{
+			name:          "Too old",
+			proofs:        []string{signDPoPProof(t, key, oldClaims)},
+			expectedError: "The DPoP proof is too old or was issued in the future.",
+		},
+		{
+			name:          "Wrong access token hash",
+			proofs:        []string{validProof},
+			accessToken:   "access-token",
+			expectedError: "The ath claim of the DPoP proof does not match the access token.",
+		},
+		{
+			name:   "Missing typ header",
+			proofs: []string{withoutTypProof},
+		},
+		{
+			name:   "Symmetric key",
+			proofs: []string{symmetricProof},
+		},
+		{
+			name:          "Missing jti",
+			proofs:        []string{signDPoPProof(t, key, missingJtiClaims)},
+			expectedError: "The DPoP proof must have a jti claim.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocksDB.NewDatabase(t)
+			jkt, err := NewDPoPValidator(mockDB).ValidateDPoPProof(ctx, &ValidateDPoPProofInput{
+				Proofs:       tt.proofs,
+				HttpMethod:   "POST",
+				HttpURL:      testTokenEndpoint,
+				AccessToken:  tt.accessToken,
+				RequireNonce: true,
+			})
+			assert.Empty(t, jkt)
+			errDetail, ok := err.(*customerrors.ErrorDetail)
+			require.True(t, ok)
+			assert.Equal(t, "invalid_dpop_proof", errDetail.GetCode())
+			assert.Equal(t, 400, errDetail.GetHttpStatusCode())
+			if len(tt.expectedError) > 0 {
+				assert.Equal(t, tt.expectedError, errDetail.GetDescription())
+			}
+			mockDB.AssertNotCalled(t, "CreateDPoPProofJti", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestValidateDPoPProof_Nonce(t *testing.T) {
+	ctx := newClientAssertionContext()
+	key := newDPoPTestKey(t)
+
+	withoutNonce := newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1")
+	delete(withoutNonce, "nonce")
+	otherNonce := newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1")
+	otherNonce["nonce"] = oauth.NewDPoPNonce([]byte("another key"))
+
+	for name, claims := range map[string]jwt.MapClaims{"Missing nonce": withoutNonce, "Nonce issued by another server": otherNonce} {
+		t.Run(name, func(t *testing.T) {
+			mockDB := mocksDB.NewDatabase(t)
+			jkt, err := NewDPoPValidator(mockDB).ValidateDPoPProof(ctx, &ValidateDPoPProofInput{
+				Proofs:       []string{signDPoPProof(t, key, claims)},
+				HttpMethod:   "POST",
+				HttpURL:      testTokenEndpoint,
+				RequireNonce: true,
+			})
+			assert.Empty(t, jkt)
+			errDetail, ok := err.(*customerrors.ErrorDetail)
+			require.True(t, ok)
+			assert.Equal(t, "use_dpop_nonce", errDetail.GetCode())
+		})
+	}
+}
+
+func TestValidateDPoPProof_Replay(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	ctx := newClientAssertionContext()
+	key := newDPoPTestKey(t)
+	proof := signDPoPProof(t, key, newDPoPProofClaims(ctx, "POST", testTokenEndpoint, "jti-1"))
+
+	mockDB.On("GetDPoPProofJti", mock.Anything, mock.Anything).Return(&models.DPoPProofJti{Id: 1}, nil)
+
+	jkt, err := NewDPoPValidator(mockDB).ValidateDPoPProof(ctx, &ValidateDPoPProofInput{
+		Proofs:       []string{proof},
+		HttpMethod:   "POST",
+		HttpURL:      testTokenEndpoint,
+		RequireNonce: true,
+	})
+	assert.Empty(t, jkt)
+	assert.Error(t, err)
+	assert.Equal(t, "The DPoP proof has already been used.", err.(*customerrors.ErrorDetail).GetDescription())
+	mockDB.AssertNotCalled(t, "CreateDPoPProofJti", mock.Anything, mock.Anything)
+}
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+
+	models "github.com/pchchv/aas/pkg/src/models"
 	validators "github.com/pchchv/aas/pkg/src/validators"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -30,6 +33,36 @@ func (_m *AuthorizeValidator) ValidateClientAndRedirectURI(input *validators.Val
 	return r0
 }
 
+// ValidateIdTokenHint provides a mock function with given fields: input
+func (_m *AuthorizeValidator) ValidateIdTokenHint(input *validators.ValidateIdTokenHintInput) (*models.User, error) {
+	ret := _m.Called(input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateIdTokenHint")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*validators.ValidateIdTokenHintInput) (*models.User, error)); ok {
+		return rf(input)
+	}
+	if rf, ok := ret.Get(0).(func(*validators.ValidateIdTokenHintInput) *models.User); ok {
+		r0 = rf(input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*validators.ValidateIdTokenHintInput) error); ok {
+		r1 = rf(input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateRequest provides a mock function with given fields: input
 func (_m *AuthorizeValidator) ValidateRequest(input *validators.ValidateRequestInput) error {
 	ret := _m.Called(input)
@@ -48,6 +81,36 @@ func (_m *AuthorizeValidator) ValidateRequest(input *validators.ValidateRequestI
 	return r0
 }
 
+// ValidateRequestObject provides a mock function with given fields: ctx, input
+func (_m *AuthorizeValidator) ValidateRequestObject(ctx context.Context, input *validators.ValidateRequestObjectInput) (*validators.ValidateRequestObjectResult, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateRequestObject")
+	}
+
+	var r0 *validators.ValidateRequestObjectResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *validators.ValidateRequestObjectInput) (*validators.ValidateRequestObjectResult, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *validators.ValidateRequestObjectInput) *validators.ValidateRequestObjectResult); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*validators.ValidateRequestObjectResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *validators.ValidateRequestObjectInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateScopes provides a mock function with given fields: scope
 func (_m *AuthorizeValidator) ValidateScopes(scope string) error {
 	ret := _m.Called(scope)
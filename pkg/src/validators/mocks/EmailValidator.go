@@ -12,12 +12,12 @@ type EmailValidator struct {
 	mock.Mock
 }
 
-// ValidateEmailAddress provides a mock function with given fields: emailAddress
-func (_m *EmailValidator) ValidateEmailAddress(emailAddress string) error {
+// ValidateEmail provides a mock function with given fields: emailAddress
+func (_m *EmailValidator) ValidateEmail(emailAddress string) error {
 	ret := _m.Called(emailAddress)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ValidateEmailAddress")
+		panic("no return value specified for ValidateEmail")
 	}
 
 	var r0 error
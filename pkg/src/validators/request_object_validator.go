@@ -0,0 +1,212 @@
+package validators
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/hashutil"
+	"github.com/pchchv/aas/pkg/src/models"
+)
+
+// maxRequestObjectLifetimeInSeconds bounds how long a request object can be used,
+// so its jti does not have to be remembered for long (RFC 9101, section 10.8)
+const maxRequestObjectLifetimeInSeconds = 3600
+
+// requestObjectRegisteredClaims are JWT claims of the request object itself, not authorization parameters
+var requestObjectRegisteredClaims = []string{"iss", "aud", "exp", "iat", "nbf", "jti", "sub"}
+
+// RequestObjectSigningAlgorithms lists the algorithms accepted for request objects
+func RequestObjectSigningAlgorithms() []string {
+	return slices.Concat(privateKeyJwtAlgorithms, clientSecretJwtAlgorithms)
+}
+
+type ValidateRequestObjectInput struct {
+	ClientId      string
+	RequestObject string
+}
+
+type ValidateRequestObjectResult struct {
+	Client     *models.Client
+	Parameters url.Values
+}
+
+// ValidateRequestObject verifies the signature of a request object (RFC 9101) with the keys
+// registered by the client, or its client secret for the HMAC algorithms, and returns its
+// claims as authorization parameters. Unsigned request objects are not accepted, and a request
+// object must expire within the hour and carry a jti, so it can only be used once.
+func (val *AuthorizeValidator) ValidateRequestObject(ctx context.Context, input *ValidateRequestObjectInput) (*ValidateRequestObjectResult, error) {
+	if len(input.ClientId) == 0 {
+		return nil, customerrors.NewErrorDetail("", "The client_id parameter is missing.")
+	}
+
+	client, err := val.database.GetClientByClientIdentifier(nil, input.ClientId)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, customerrors.NewErrorDetail("", "Invalid client_id parameter. The client does not exist.")
+	} else if !client.Enabled {
+		return nil, customerrors.NewErrorDetail("", "Invalid client_id parameter. The client is disabled.")
+	}
+
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	keyFunc, validMethods, err := val.getRequestObjectVerificationKeys(client, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(input.RequestObject, claims, keyFunc,
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(client.ClientIdentifier),
+		jwt.WithAudience(settings.Issuer),
+	); err != nil {
+		return nil, invalidRequestObject("The request object is invalid (" + err.Error() + ").")
+	}
+
+	if err = checkRequestObjectLifetime(claims); err != nil {
+		return nil, err
+	}
+
+	parameters := url.Values{}
+	for name, value := range claims {
+		if slices.Contains(requestObjectRegisteredClaims, name) {
+			continue
+		} else if name == "request" || name == "request_uri" {
+			return nil, invalidRequestObject("The request object must not contain the request or request_uri parameters.")
+		}
+
+		switch v := value.(type) {
+		case string:
+			parameters.Set(name, v)
+		case float64:
+			parameters.Set(name, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			parameters.Set(name, strconv.FormatBool(v))
+		case []interface{}:
+			for _, item := range v {
+				if itemStr, ok := item.(string); ok {
+					parameters.Add(name, itemStr)
+				}
+			}
+		default:
+			// structured parameters, such as claims, are passed on as JSON
+			valueBytes, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			parameters.Set(name, string(valueBytes))
+		}
+	}
+
+	if clientId := parameters.Get("client_id"); len(clientId) > 0 && clientId != client.ClientIdentifier {
+		return nil, invalidRequestObject("The client_id of the request object does not match the client_id parameter.")
+	}
+	parameters.Set("client_id", client.ClientIdentifier)
+
+	if err = val.preventRequestObjectReplay(client, claims); err != nil {
+		return nil, err
+	}
+
+	return &ValidateRequestObjectResult{
+		Client:     client,
+		Parameters: parameters,
+	}, nil
+}
+
+// getRequestObjectVerificationKeys returns the keys a request object of the client can be signed with:
+// the registered public keys, or the client secret of a confidential client without registered keys
+func (val *AuthorizeValidator) getRequestObjectVerificationKeys(client *models.Client, settings *models.Settings) (jwt.Keyfunc, []string, error) {
+	if len(client.JWKS) > 0 || len(client.JWKSURI) > 0 {
+		publicKeys, err := getClientPublicKeys(val.httpClient, client)
+		if err != nil {
+			return nil, nil, invalidRequestObject("Unable to load the registered keys of the client to verify the request object.")
+		}
+		return getPublicKeysKeyfunc(publicKeys), privateKeyJwtAlgorithms, nil
+	}
+
+	if client.IsPublic || len(client.ClientSecretEncrypted) == 0 {
+		return nil, nil, invalidRequestObject("The client has no registered keys to verify the request object.")
+	}
+
+	clientSecret, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return func(token *jwt.Token) (interface{}, error) {
+		return []byte(clientSecret), nil
+	}, clientSecretJwtAlgorithms, nil
+}
+
+// checkRequestObjectLifetime rejects the request objects valid for longer than the maximum lifetime,
+// counted from the iat claim when there is one
+func checkRequestObjectLifetime(claims jwt.MapClaims) error {
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return err
+	}
+
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil {
+		return invalidRequestObject("The request object is invalid (" + err.Error() + ").")
+	}
+
+	lifetimeStart := time.Now()
+	if issuedAt != nil {
+		lifetimeStart = issuedAt.Time
+	}
+
+	if expiresAt.Sub(lifetimeStart) > maxRequestObjectLifetimeInSeconds*time.Second {
+		return invalidRequestObject("The request object must not be valid for more than " +
+			strconv.Itoa(maxRequestObjectLifetimeInSeconds) + " seconds.")
+	}
+	return nil
+}
+
+// preventRequestObjectReplay records the jti of the request object until it expires,
+// rejecting a request object whose jti was already used by the client
+func (val *AuthorizeValidator) preventRequestObjectReplay(client *models.Client, claims jwt.MapClaims) error {
+	jti, _ := claims["jti"].(string)
+	if len(jti) == 0 {
+		return invalidRequestObject("The request object must have a jti claim.")
+	}
+
+	jtiHash, err := hashutil.HashString(jti)
+	if err != nil {
+		return err
+	}
+
+	requestObjectJti, err := val.database.GetRequestObjectJti(nil, client.Id, jtiHash)
+	if err != nil {
+		return err
+	} else if requestObjectJti != nil {
+		return invalidRequestObject("The request object has already been used.")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return err
+	}
+
+	// the unique index on client_id and jti_hash rejects concurrent replays
+	return val.database.CreateRequestObjectJti(nil, &models.RequestObjectJti{
+		ClientId:  client.Id,
+		JtiHash:   jtiHash,
+		ExpiresAt: sql.NullTime{Time: expiresAt.UTC(), Valid: true},
+	})
+}
+
+func invalidRequestObject(description string) error {
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_request_object", description, http.StatusBadRequest)
+}
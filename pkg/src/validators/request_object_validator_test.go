@@ -0,0 +1,207 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	mocksDB "github.com/pchchv/aas/pkg/src/database/mocks"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/keyutil"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestObjectClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":           "test-client",
+		"aud":           testIssuer,
+		"exp":           time.Now().Add(time.Minute).Unix(),
+		"jti":           "request-object-1",
+		"client_id":     "test-client",
+		"response_type": "code",
+		"redirect_uri":  "https://example.com/callback",
+		"scope":         "openid profile",
+		"state":         "abc",
+		"max_age":       300,
+		"resource":      []interface{}{"resource1"},
+		"claims":        map[string]interface{}{"id_token": map[string]interface{}{"email": nil}},
+	}
+}
+
+func TestValidateRequestObject_PrivateKey(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	client, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+	requestObject := signClientAssertion(t, newRequestObjectClaims(), kid, privateKey)
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+	mockDB.On("GetRequestObjectJti", mock.Anything, client.Id, mock.Anything).Return(nil, nil)
+	mockDB.On("CreateRequestObjectJti", mock.Anything, mock.MatchedBy(func(requestObjectJti *models.RequestObjectJti) bool {
+		return requestObjectJti.ClientId == client.Id && len(requestObjectJti.JtiHash) > 0 && requestObjectJti.ExpiresAt.Valid
+	})).Return(nil)
+
+	result, err := NewAuthorizeValidator(mockDB).ValidateRequestObject(newClientAssertionContext(), &ValidateRequestObjectInput{
+		ClientId:      "test-client",
+		RequestObject: requestObject,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, client, result.Client)
+	assert.Equal(t, "test-client", result.Parameters.Get("client_id"))
+	assert.Equal(t, "code", result.Parameters.Get("response_type"))
+	assert.Equal(t, "https://example.com/callback", result.Parameters.Get("redirect_uri"))
+	assert.Equal(t, "openid profile", result.Parameters.Get("scope"))
+	assert.Equal(t, "300", result.Parameters.Get("max_age"))
+	assert.Equal(t, "resource1", result.Parameters.Get("resource"))
+	assert.JSONEq(t, `{"id_token":{"email":null}}`, result.Parameters.Get("claims"))
+	assert.False(t, result.Parameters.Has("iss"))
+	assert.False(t, result.Parameters.Has("aud"))
+	assert.False(t, result.Parameters.Has("exp"))
+}
+
+func TestValidateRequestObject_ClientSecret(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	ctx := newClientAssertionContext()
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	clientSecretEncrypted, err := encryption.EncryptText("client-secret", settings.AESEncryptionKey)
+	require.NoError(t, err)
+
+	client := &models.Client{
+		Id:                    1,
+		ClientIdentifier:      "test-client",
+		Enabled:               true,
+		ClientSecretEncrypted: clientSecretEncrypted,
+	}
+	requestObject, err := jwt.NewWithClaims(jwt.SigningMethodHS256, newRequestObjectClaims()).SignedString([]byte("client-secret"))
+	require.NoError(t, err)
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+	mockDB.On("GetRequestObjectJti", mock.Anything, client.Id, mock.Anything).Return(nil, nil)
+	mockDB.On("CreateRequestObjectJti", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := NewAuthorizeValidator(mockDB).ValidateRequestObject(ctx, &ValidateRequestObjectInput{
+		ClientId:      "test-client",
+		RequestObject: requestObject,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", result.Parameters.Get("state"))
+}
+
+func TestValidateRequestObject_Invalid(t *testing.T) {
+	client, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+	_, _, otherPrivateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+
+	expiredClaims := newRequestObjectClaims()
+	expiredClaims["exp"] = time.Now().Add(-time.Minute).Unix()
+	otherIssuerClaims := newRequestObjectClaims()
+	otherIssuerClaims["iss"] = "other-client"
+	otherAudienceClaims := newRequestObjectClaims()
+	otherAudienceClaims["aud"] = "https://other.example.com"
+	otherClientIdClaims := newRequestObjectClaims()
+	otherClientIdClaims["client_id"] = "other-client"
+	nestedRequestClaims := newRequestObjectClaims()
+	nestedRequestClaims["request_uri"] = "urn:ietf:params:oauth:request_uri:abc"
+	withoutExpirationClaims := newRequestObjectClaims()
+	delete(withoutExpirationClaims, "exp")
+	longLivedClaims := newRequestObjectClaims()
+	longLivedClaims["exp"] = time.Now().Add(2 * time.Hour).Unix()
+	longLivedSinceIssuedClaims := newRequestObjectClaims()
+	longLivedSinceIssuedClaims["iat"] = time.Now().Add(-59 * time.Minute).Unix()
+	longLivedSinceIssuedClaims["exp"] = time.Now().Add(2 * time.Minute).Unix()
+	withoutJtiClaims := newRequestObjectClaims()
+	delete(withoutJtiClaims, "jti")
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, newRequestObjectClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		requestObject string
+		expectedError string
+	}{
+		{name: "Unsigned", requestObject: unsigned},
+		{name: "Expired", requestObject: signClientAssertion(t, expiredClaims, kid, privateKey)},
+		{name: "Issuer is not the client", requestObject: signClientAssertion(t, otherIssuerClaims, kid, privateKey)},
+		{name: "Wrong audience", requestObject: signClientAssertion(t, otherAudienceClaims, kid, privateKey)},
+		{name: "Signed with an unregistered key", requestObject: signClientAssertion(t, newRequestObjectClaims(), kid, otherPrivateKey)},
+		{
+			name:          "Different client_id",
+			requestObject: signClientAssertion(t, otherClientIdClaims, kid, privateKey),
+			expectedError: "The client_id of the request object does not match the client_id parameter.",
+		},
+		{
+			name:          "Nested request_uri",
+			requestObject: signClientAssertion(t, nestedRequestClaims, kid, privateKey),
+			expectedError: "The request object must not contain the request or request_uri parameters.",
+		},
+		{name: "Without expiration", requestObject: signClientAssertion(t, withoutExpirationClaims, kid, privateKey)},
+		{
+			name:          "Valid for too long",
+			requestObject: signClientAssertion(t, longLivedClaims, kid, privateKey),
+			expectedError: "The request object must not be valid for more than 3600 seconds.",
+		},
+		{
+			name:          "Valid for too long since issued",
+			requestObject: signClientAssertion(t, longLivedSinceIssuedClaims, kid, privateKey),
+			expectedError: "The request object must not be valid for more than 3600 seconds.",
+		},
+		{
+			name:          "Without jti",
+			requestObject: signClientAssertion(t, withoutJtiClaims, kid, privateKey),
+			expectedError: "The request object must have a jti claim.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocksDB.NewDatabase(t)
+			mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+
+			result, err := NewAuthorizeValidator(mockDB).ValidateRequestObject(newClientAssertionContext(), &ValidateRequestObjectInput{
+				ClientId:      "test-client",
+				RequestObject: tt.requestObject,
+			})
+			assert.Nil(t, result)
+			errDetail, ok := err.(*customerrors.ErrorDetail)
+			require.True(t, ok)
+			assert.Equal(t, "invalid_request_object", errDetail.GetCode())
+			if len(tt.expectedError) > 0 {
+				assert.Equal(t, tt.expectedError, errDetail.GetDescription())
+			}
+		})
+	}
+}
+
+func TestValidateRequestObject_Replayed(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	client, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+	mockDB.On("GetRequestObjectJti", mock.Anything, client.Id, mock.Anything).Return(&models.RequestObjectJti{Id: 1, ClientId: client.Id}, nil)
+
+	result, err := NewAuthorizeValidator(mockDB).ValidateRequestObject(newClientAssertionContext(), &ValidateRequestObjectInput{
+		ClientId:      "test-client",
+		RequestObject: signClientAssertion(t, newRequestObjectClaims(), kid, privateKey),
+	})
+	assert.Nil(t, result)
+	assert.Equal(t, "The request object has already been used.", err.(*customerrors.ErrorDetail).GetDescription())
+	mockDB.AssertNotCalled(t, "CreateRequestObjectJti", mock.Anything, mock.Anything)
+}
+
+func TestValidateRequestObject_PublicClientWithoutKeys(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	_, kid, privateKey := newPrivateKeyJwtClient(t, keyutil.AlgorithmES256)
+	client := &models.Client{Id: 1, ClientIdentifier: "test-client", Enabled: true, IsPublic: true}
+
+	mockDB.On("GetClientByClientIdentifier", mock.Anything, "test-client").Return(client, nil)
+
+	result, err := NewAuthorizeValidator(mockDB).ValidateRequestObject(newClientAssertionContext(), &ValidateRequestObjectInput{
+		ClientId:      "test-client",
+		RequestObject: signClientAssertion(t, newRequestObjectClaims(), kid, privateKey),
+	})
+	assert.Nil(t, result)
+	assert.Equal(t, "The client has no registered keys to verify the request object.", err.(*customerrors.ErrorDetail).GetDescription())
+}
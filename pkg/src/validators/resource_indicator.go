@@ -0,0 +1,50 @@
+package validators
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/oidc"
+)
+
+// ValidateResourceIndicator checks that the resource parameter (RFC 8707) names a resource
+// the scope grants at least one permission of. Resources are identified by their resource
+// identifier, the OpenID Connect scopes grant access to the authserver resource.
+func ValidateResourceIndicator(scope string, resource string) error {
+	if len(resource) == 0 {
+		return nil
+	}
+
+	for _, scopeStr := range strings.Fields(scope) {
+		if oidc.IsIdTokenScope(scopeStr) {
+			if resource == constants.AuthServerResourceIdentifier {
+				return nil
+			}
+			continue
+		}
+
+		if resourceIdentifier, _, found := strings.Cut(scopeStr, ":"); found && resourceIdentifier == resource {
+			return nil
+		}
+	}
+
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_target",
+		"The resource '"+resource+"' is not granted by the requested scope. Please request a resource with at least one permission in the scope.",
+		http.StatusBadRequest)
+}
+
+// getResourceIndicator returns the single resource of a token request, a token can only be
+// audience-restricted to one resource at a time
+func getResourceIndicator(resources []string) (string, error) {
+	switch len(resources) {
+	case 0:
+		return "", nil
+	case 1:
+		return strings.TrimSpace(resources[0]), nil
+	default:
+		return "", customerrors.NewErrorDetailWithHttpStatusCode("invalid_target",
+			"Only one resource can be requested per token request.", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,72 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResourceIndicator(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    string
+		resource string
+		valid    bool
+	}{
+		{name: "No resource", scope: "openid resource1:read", resource: "", valid: true},
+		{name: "Resource of a resource scope", scope: "openid resource1:read resource2:write", resource: "resource2", valid: true},
+		{name: "Authserver with an OpenID Connect scope", scope: "openid resource1:read", resource: "authserver", valid: true},
+		{name: "Authserver with an authserver scope", scope: "authserver:manage-account", resource: "authserver", valid: true},
+		{name: "Authserver without OpenID Connect scopes", scope: "resource1:read offline_access", resource: "authserver", valid: false},
+		{name: "Resource not in the scope", scope: "openid resource1:read", resource: "resource2", valid: false},
+		{name: "Resource matching only a permission", scope: "resource1:read", resource: "read", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResourceIndicator(tt.scope, tt.resource)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				customErr, ok := err.(*customerrors.ErrorDetail)
+				assert.True(t, ok)
+				assert.Equal(t, "invalid_target", customErr.GetCode())
+			}
+		})
+	}
+}
+
+func TestValidateCodeResource(t *testing.T) {
+	validator := &TokenValidator{}
+
+	t.Run("Defaults to the resource of the authorization request", func(t *testing.T) {
+		code := &models.Code{Scope: "openid resource1:read resource2:read", Resource: "resource1"}
+		assert.NoError(t, validator.validateCodeResource(code, nil))
+		assert.Equal(t, "resource1", code.Resource)
+	})
+
+	t.Run("Token request names another resource of the grant", func(t *testing.T) {
+		code := &models.Code{Scope: "openid resource1:read resource2:read", Resource: "resource1"}
+		assert.NoError(t, validator.validateCodeResource(code, []string{"resource2"}))
+		assert.Equal(t, "resource2", code.Resource)
+	})
+
+	t.Run("Resource outside of the grant", func(t *testing.T) {
+		code := &models.Code{Scope: "openid resource1:read"}
+		err := validator.validateCodeResource(code, []string{"resource3"})
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_target", customErr.GetCode())
+		assert.Empty(t, code.Resource)
+	})
+
+	t.Run("More than one resource", func(t *testing.T) {
+		code := &models.Code{Scope: "resource1:read resource2:read"}
+		err := validator.validateCodeResource(code, []string{"resource1", "resource2"})
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_target", customErr.GetCode())
+	})
+}
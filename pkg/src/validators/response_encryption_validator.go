@@ -0,0 +1,38 @@
+package validators
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/encryption"
+)
+
+// ValidateResponseEncryption validates the encryption of the ID token or userinfo responses of a client,
+// named by the prefix of its metadata, and returns the content encryption algorithm, A128CBC-HS256 when
+// it is omitted (OpenID Connect Dynamic Client Registration, section 2). A256KW and dir derive their key
+// from the client secret, so only a client with a secret can use them.
+func ValidateResponseEncryption(name string, alg string, enc string, hasClientSecret bool) (string, error) {
+	if len(alg) == 0 {
+		if len(enc) > 0 {
+			return "", customerrors.NewErrorDetail("", "The "+name+"_encrypted_response_enc requires the "+name+"_encrypted_response_alg.")
+		}
+		return "", nil
+	}
+
+	if !slices.Contains(encryption.JWEAlgorithms, alg) {
+		return "", customerrors.NewErrorDetail("", "Unsupported "+name+"_encrypted_response_alg: "+alg+
+			". Supported values are "+strings.Join(encryption.JWEAlgorithms, ", ")+".")
+	} else if alg != encryption.JWEAlgorithmRSAOAEP256 && !hasClientSecret {
+		return "", customerrors.NewErrorDetail("", "The "+alg+" encryption of the "+name+" responses requires a client secret.")
+	}
+
+	if len(enc) == 0 {
+		return encryption.JWEEncryptionA128CBCHS256, nil
+	} else if !slices.Contains(encryption.JWEEncryptionMethods, enc) {
+		return "", customerrors.NewErrorDetail("", "Unsupported "+name+"_encrypted_response_enc: "+enc+
+			". Supported values are "+strings.Join(encryption.JWEEncryptionMethods, ", ")+".")
+	}
+
+	return enc, nil
+}
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResponseEncryption(t *testing.T) {
+	tests := []struct {
+		name            string
+		alg             string
+		enc             string
+		hasClientSecret bool
+		expectedEnc     string
+		expectedError   string
+	}{
+		{name: "Not encrypted", alg: "", enc: ""},
+		{name: "RSA-OAEP-256 with the default enc", alg: encryption.JWEAlgorithmRSAOAEP256, expectedEnc: encryption.JWEEncryptionA128CBCHS256},
+		{name: "A256KW", alg: encryption.JWEAlgorithmA256KW, enc: encryption.JWEEncryptionA256GCM, hasClientSecret: true, expectedEnc: encryption.JWEEncryptionA256GCM},
+		{name: "dir", alg: encryption.JWEAlgorithmDir, enc: encryption.JWEEncryptionA256CBCHS512, hasClientSecret: true, expectedEnc: encryption.JWEEncryptionA256CBCHS512},
+		{
+			name:          "enc without alg",
+			enc:           encryption.JWEEncryptionA128GCM,
+			expectedError: "The id_token_encrypted_response_enc requires the id_token_encrypted_response_alg.",
+		},
+		{
+			name:          "Unsupported alg",
+			alg:           "RSA1_5",
+			expectedError: "Unsupported id_token_encrypted_response_alg: RSA1_5. Supported values are RSA-OAEP-256, A256KW, dir.",
+		},
+		{
+			name:          "Unsupported enc",
+			alg:           encryption.JWEAlgorithmRSAOAEP256,
+			enc:           "A192GCM",
+			expectedError: "Unsupported id_token_encrypted_response_enc: A192GCM. Supported values are A128CBC-HS256, A256CBC-HS512, A128GCM, A256GCM.",
+		},
+		{
+			name:          "Symmetric alg without a client secret",
+			alg:           encryption.JWEAlgorithmA256KW,
+			expectedError: "The A256KW encryption of the id_token responses requires a client secret.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := ValidateResponseEncryption("id_token", tt.alg, tt.enc, tt.hasClientSecret)
+			if len(tt.expectedError) == 0 {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedEnc, enc)
+				return
+			}
+
+			customErr, ok := err.(*customerrors.ErrorDetail)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedError, customErr.GetDescription())
+		})
+	}
+}
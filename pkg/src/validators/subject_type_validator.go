@@ -0,0 +1,98 @@
+package validators
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+)
+
+const maxSectorIdentifierResponseSize = 64 * 1024
+
+var sectorIdentifierHttpClient = &http.Client{Timeout: 10 * time.Second}
+
+// ValidateSubjectType validates the subject type of a client and returns its sector identifier.
+// The sector of a pairwise client is the host of its sector_identifier_uri, which must list all the
+// redirect URIs of the client, or else the host shared by the redirect URIs (OpenID Connect Core, section 8.1).
+func ValidateSubjectType(subjectType string, sectorIdentifierURI string, redirectURIs []string) (string, error) {
+	if subjectType != constants.SubjectTypePublic && subjectType != constants.SubjectTypePairwise {
+		return "", customerrors.NewErrorDetail("", "Unsupported subject type: "+subjectType+". Supported values are public and pairwise.")
+	} else if subjectType == constants.SubjectTypePublic {
+		if len(sectorIdentifierURI) > 0 {
+			return "", customerrors.NewErrorDetail("", "A sector_identifier_uri can only be registered for the pairwise subject type.")
+		}
+		return "", nil
+	}
+
+	if len(sectorIdentifierURI) > 0 {
+		u, err := url.ParseRequestURI(sectorIdentifierURI)
+		if err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+			return "", customerrors.NewErrorDetail("", "The sector_identifier_uri must be a valid https URL.")
+		} else if len(sectorIdentifierURI) > 256 {
+			return "", customerrors.NewErrorDetail("", "The sector_identifier_uri cannot exceed a maximum length of 256 characters.")
+		}
+
+		sectorRedirectURIs, err := getSectorRedirectURIs(sectorIdentifierURI)
+		if err != nil {
+			return "", err
+		}
+
+		for _, redirectURI := range redirectURIs {
+			if !slices.Contains(sectorRedirectURIs, redirectURI) {
+				return "", customerrors.NewErrorDetail("", "The redirect URI "+redirectURI+" is not listed in the sector_identifier_uri document.")
+			}
+		}
+
+		return u.Hostname(), nil
+	}
+
+	if len(redirectURIs) == 0 {
+		return "", customerrors.NewErrorDetail("", "A pairwise client without redirect URIs requires a sector_identifier_uri.")
+	}
+
+	sectorIdentifier := ""
+	for _, redirectURI := range redirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil {
+			return "", customerrors.NewErrorDetail("", "Invalid redirect URI: "+redirectURI+".")
+		}
+
+		if len(sectorIdentifier) == 0 {
+			sectorIdentifier = u.Hostname()
+		} else if u.Hostname() != sectorIdentifier {
+			return "", customerrors.NewErrorDetail("", "The redirect URIs of a pairwise client must share one host, otherwise a sector_identifier_uri is required.")
+		}
+	}
+
+	return sectorIdentifier, nil
+}
+
+// getSectorRedirectURIs fetches the JSON array of redirect URIs published at the sector_identifier_uri
+func getSectorRedirectURIs(sectorIdentifierURI string) ([]string, error) {
+	resp, err := sectorIdentifierHttpClient.Get(sectorIdentifierURI)
+	if err != nil {
+		return nil, customerrors.NewErrorDetail("", "Unable to fetch the sector_identifier_uri document.")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, customerrors.NewErrorDetail("", "Unable to fetch the sector_identifier_uri document.")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSectorIdentifierResponseSize))
+	if err != nil {
+		return nil, customerrors.NewErrorDetail("", "Unable to fetch the sector_identifier_uri document.")
+	}
+
+	var sectorRedirectURIs []string
+	if err = json.Unmarshal(body, &sectorRedirectURIs); err != nil {
+		return nil, customerrors.NewErrorDetail("", "The sector_identifier_uri document must be a JSON array of redirect URIs.")
+	}
+
+	return sectorRedirectURIs, nil
+}
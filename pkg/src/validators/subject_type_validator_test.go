@@ -0,0 +1,117 @@
+package validators
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pchchv/aas/pkg/src/constants"
+	"github.com/pchchv/aas/pkg/src/customerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSubjectType(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sector.json":
+			w.Write([]byte(`["https://app.example.com/callback", "https://admin.example.org/callback"]`)) //nolint:errcheck
+		case "/invalid.json":
+			w.Write([]byte(`{"redirect_uris": []}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalHttpClient := sectorIdentifierHttpClient
+	sectorIdentifierHttpClient = server.Client()
+	defer func() { sectorIdentifierHttpClient = originalHttpClient }()
+
+	tests := []struct {
+		name                     string
+		subjectType              string
+		sectorIdentifierURI      string
+		redirectURIs             []string
+		expectedSectorIdentifier string
+		expectedError            string
+	}{
+		{
+			name:         "Public",
+			subjectType:  constants.SubjectTypePublic,
+			redirectURIs: []string{"https://app.example.com/callback"},
+		},
+		{
+			name:          "Unsupported subject type",
+			subjectType:   "anonymous",
+			expectedError: "Unsupported subject type: anonymous. Supported values are public and pairwise.",
+		},
+		{
+			name:                "Public with a sector identifier URI",
+			subjectType:         constants.SubjectTypePublic,
+			sectorIdentifierURI: server.URL + "/sector.json",
+			expectedError:       "A sector_identifier_uri can only be registered for the pairwise subject type.",
+		},
+		{
+			name:                     "Pairwise by the host of the redirect URIs",
+			subjectType:              constants.SubjectTypePairwise,
+			redirectURIs:             []string{"https://app.example.com/callback", "https://app.example.com:8443/other"},
+			expectedSectorIdentifier: "app.example.com",
+		},
+		{
+			name:          "Pairwise with redirect URIs on different hosts",
+			subjectType:   constants.SubjectTypePairwise,
+			redirectURIs:  []string{"https://app.example.com/callback", "https://admin.example.org/callback"},
+			expectedError: "The redirect URIs of a pairwise client must share one host, otherwise a sector_identifier_uri is required.",
+		},
+		{
+			name:          "Pairwise without redirect URIs",
+			subjectType:   constants.SubjectTypePairwise,
+			expectedError: "A pairwise client without redirect URIs requires a sector_identifier_uri.",
+		},
+		{
+			name:                     "Pairwise with a sector identifier URI",
+			subjectType:              constants.SubjectTypePairwise,
+			sectorIdentifierURI:      server.URL + "/sector.json",
+			redirectURIs:             []string{"https://app.example.com/callback", "https://admin.example.org/callback"},
+			expectedSectorIdentifier: "127.0.0.1",
+		},
+		{
+			name:                "Redirect URI missing from the sector identifier document",
+			subjectType:         constants.SubjectTypePairwise,
+			sectorIdentifierURI: server.URL + "/sector.json",
+			redirectURIs:        []string{"https://evil.example.net/callback"},
+			expectedError:       "The redirect URI https://evil.example.net/callback is not listed in the sector_identifier_uri document.",
+		},
+		{
+			name:                "Sector identifier URI over http",
+			subjectType:         constants.SubjectTypePairwise,
+			sectorIdentifierURI: "http://app.example.com/sector.json",
+			expectedError:       "The sector_identifier_uri must be a valid https URL.",
+		},
+		{
+			name:                "Sector identifier document that is not an array",
+			subjectType:         constants.SubjectTypePairwise,
+			sectorIdentifierURI: server.URL + "/invalid.json",
+			expectedError:       "The sector_identifier_uri document must be a JSON array of redirect URIs.",
+		},
+		{
+			name:                "Sector identifier document not found",
+			subjectType:         constants.SubjectTypePairwise,
+			sectorIdentifierURI: server.URL + "/missing.json",
+			expectedError:       "Unable to fetch the sector_identifier_uri document.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sectorIdentifier, err := ValidateSubjectType(tt.subjectType, tt.sectorIdentifierURI, tt.redirectURIs)
+			if len(tt.expectedError) > 0 {
+				assert.Equal(t, tt.expectedError, err.(*customerrors.ErrorDetail).GetDescription())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSectorIdentifier, sectorIdentifier)
+		})
+	}
+}
@@ -2,17 +2,22 @@ package validators
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto"
+	"crypto/x509"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/pchchv/aas/pkg/src/config"
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/customerrors"
 	"github.com/pchchv/aas/pkg/src/database"
 	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/enums"
 	"github.com/pchchv/aas/pkg/src/hashutil"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/pchchv/aas/pkg/src/oauth"
@@ -25,7 +30,7 @@ type PermissionChecker interface {
 }
 
 type TokenParser interface {
-	DecodeAndValidateTokenString(token string, pubKey *rsa.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error)
+	DecodeAndValidateTokenString(token string, pubKey crypto.PublicKey, withExpirationCheck bool) (*oauth.Jwt, error)
 }
 
 type AuditLogger interface {
@@ -33,14 +38,27 @@ type AuditLogger interface {
 }
 
 type ValidateTokenRequestInput struct {
-	GrantType    string
-	Code         string
-	RedirectURI  string
-	CodeVerifier string
-	ClientId     string
-	ClientSecret string
-	Scope        string
-	RefreshToken string
+	GrantType           string
+	Code                string
+	RedirectURI         string
+	CodeVerifier        string
+	ClientId            string
+	ClientSecret        string
+	ClientAssertionType string
+	ClientAssertion     string
+	ClientCertificates  []*x509.Certificate
+	DPoPProofs          []string
+	Scope               string
+	Resources           []string
+	RefreshToken        string
+	DeviceCode          string
+	AuthReqId           string
+	SubjectToken        string
+	SubjectTokenType    string
+	ActorToken          string
+	ActorTokenType      string
+	RequestedTokenType  string
+	Audience            []string
 }
 
 type ValidateTokenRequestResult struct {
@@ -49,42 +67,116 @@ type ValidateTokenRequestResult struct {
 	CodeEntity       *models.Code
 	RefreshToken     *models.RefreshToken
 	RefreshTokenInfo *oauth.Jwt
+	DeviceCode       *models.DeviceCode
+	CIBARequest      *models.CIBARequest
+	// DPoPKeyThumbprint is the JWK thumbprint of the key the issued tokens are bound to
+	DPoPKeyThumbprint string
+	// Subject and Actor are the sub and act claims of the token issued by a token exchange
+	Subject string
+	Actor   map[string]interface{}
+	// Resource is the resource indicator the access token issued for a refresh token is restricted to
+	Resource string
 }
 
 type TokenValidator struct {
-	database          database.Database
-	tokenParser       TokenParser
-	permissionChecker PermissionChecker
-	auditLogger       AuditLogger
+	database                   database.Database
+	tokenParser                TokenParser
+	permissionChecker          PermissionChecker
+	auditLogger                AuditLogger
+	clientAssertionValidator   *ClientAssertionValidator
+	clientCertificateValidator *ClientCertificateValidator
+	dpopValidator              *DPoPValidator
 }
 
 func NewTokenValidator(database database.Database, tokenParser TokenParser,
 	permissionChecker PermissionChecker, auditLogger AuditLogger) *TokenValidator {
 	return &TokenValidator{
-		database:          database,
-		tokenParser:       tokenParser,
-		permissionChecker: permissionChecker,
-		auditLogger:       auditLogger,
+		database:                   database,
+		tokenParser:                tokenParser,
+		permissionChecker:          permissionChecker,
+		auditLogger:                auditLogger,
+		clientAssertionValidator:   NewClientAssertionValidator(database),
+		clientCertificateValidator: NewClientCertificateValidator(),
+		dpopValidator:              NewDPoPValidator(database),
 	}
 }
 
-func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *ValidateTokenRequestInput) (*ValidateTokenRequestResult, error) {
-	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+// getClient loads the client of the token request. Clients sending a client assertion or
+// using mutual TLS are authenticated here, clients using their secret are authenticated by each grant type.
+func (val *TokenValidator) getClient(ctx context.Context, input *ValidateTokenRequestInput) (client *models.Client, clientAuthenticated bool, err error) {
+	if len(input.ClientAssertion) > 0 || len(input.ClientAssertionType) > 0 {
+		if len(input.ClientSecret) > 0 {
+			return nil, false, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Only one client authentication method can be used. Please send either a client_secret or a client_assertion.",
+				http.StatusBadRequest)
+		}
+
+		client, err = val.clientAssertionValidator.ValidateClientAssertion(ctx, &ValidateClientAssertionInput{
+			ClientId:            input.ClientId,
+			ClientAssertionType: input.ClientAssertionType,
+			ClientAssertion:     input.ClientAssertion,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		input.ClientId = client.ClientIdentifier
+		return client, true, nil
+	}
+
 	if len(input.ClientId) == 0 {
-		return nil, customerrors.NewErrorDetailWithHttpStatusCode(
+		return nil, false, customerrors.NewErrorDetailWithHttpStatusCode(
 			"invalid_request",
 			"Missing required client_id parameter.",
 			http.StatusBadRequest,
 		)
 	}
 
-	client, err := val.database.GetClientByClientIdentifier(nil, input.ClientId)
+	client, err = val.database.GetClientByClientIdentifier(nil, input.ClientId)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	} else if client == nil {
-		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "Client does not exist.", http.StatusBadRequest)
+		return nil, false, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "Client does not exist.", http.StatusBadRequest)
 	} else if !client.Enabled {
-		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "Client is disabled.", http.StatusBadRequest)
+		return nil, false, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "Client is disabled.", http.StatusBadRequest)
+	} else if client.UsesClientAssertion() {
+		return nil, false, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+			"This client must authenticate with a client assertion (client_assertion and client_assertion_type).",
+			http.StatusUnauthorized)
+	} else if client.UsesTLSClientAuth() {
+		if len(input.ClientSecret) > 0 {
+			return nil, false, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"This client authenticates with its TLS client certificate. Please remove the client_secret from your request.",
+				http.StatusBadRequest)
+		}
+
+		if err = val.clientCertificateValidator.ValidateClientCertificate(client, input.ClientCertificates); err != nil {
+			return nil, false, err
+		}
+		return client, true, nil
+	}
+
+	return client, false, nil
+}
+
+func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *ValidateTokenRequestInput) (*ValidateTokenRequestResult, error) {
+	settings := ctx.Value(constants.ContextKeySettings).(*models.Settings)
+	client, clientAuthenticated, err := val.getClient(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var dpopKeyThumbprint string
+	if len(input.DPoPProofs) > 0 {
+		// the token endpoint always requires a server-issued nonce (RFC 9449, section 8)
+		if dpopKeyThumbprint, err = val.dpopValidator.ValidateDPoPProof(ctx, &ValidateDPoPProofInput{
+			Proofs:       input.DPoPProofs,
+			HttpMethod:   http.MethodPost,
+			HttpURL:      config.GetAuthServer().BaseURL + "/auth/token",
+			RequireNonce: true,
+		}); err != nil {
+			return nil, err
+		}
 	}
 
 	clientSecretRequiredErrorMsg := "This client is configured as confidential (not public), which means a client_secret is required for authentication. Please provide a valid client_secret to proceed."
@@ -158,7 +250,7 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 				"Code has expired.", http.StatusBadRequest)
 		}
 
-		if !client.IsPublic {
+		if !client.IsPublic && !clientAuthenticated {
 			if len(input.ClientSecret) == 0 {
 				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
 					clientSecretRequiredErrorMsg, http.StatusBadRequest)
@@ -187,8 +279,13 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "Invalid code_verifier (PKCE).", http.StatusBadRequest)
 		}
 
+		if err = val.validateCodeResource(codeEntity, input.Resources); err != nil {
+			return nil, err
+		}
+
 		return &ValidateTokenRequestResult{
-			CodeEntity: codeEntity,
+			CodeEntity:        codeEntity,
+			DPoPKeyThumbprint: dpopKeyThumbprint,
 		}, nil
 	case "client_credentials":
 		if !client.ClientCredentialsEnabled {
@@ -203,17 +300,19 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 				http.StatusBadRequest)
 		}
 
-		if len(input.ClientSecret) == 0 {
-			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", clientSecretRequiredErrorMsg,
-				http.StatusBadRequest)
-		}
+		if !clientAuthenticated {
+			if len(input.ClientSecret) == 0 {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", clientSecretRequiredErrorMsg,
+					http.StatusBadRequest)
+			}
 
-		clientSecretDescrypted, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
-		if err != nil {
-			return nil, err
-		} else if clientSecretDescrypted != input.ClientSecret {
-			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
-				"Client authentication failed.", http.StatusUnauthorized)
+			clientSecretDescrypted, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+			if err != nil {
+				return nil, err
+			} else if clientSecretDescrypted != input.ClientSecret {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+					"Client authentication failed.", http.StatusUnauthorized)
+			}
 		}
 
 		if err = val.database.ClientLoadPermissions(nil, client); err != nil {
@@ -241,17 +340,207 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 		}
 
 		return &ValidateTokenRequestResult{
-			Client: client,
-			Scope:  input.Scope,
+			Client:            client,
+			Scope:             input.Scope,
+			DPoPKeyThumbprint: dpopKeyThumbprint,
+		}, nil
+	case constants.DeviceCodeGrantType:
+		if !client.DeviceCodeEnabled {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"The client associated with the provided client_id does not support device authorization flow.",
+				http.StatusBadRequest)
+		}
+
+		if !client.IsPublic && !clientAuthenticated {
+			if len(input.ClientSecret) == 0 {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+					clientSecretRequiredErrorMsg, http.StatusBadRequest)
+			}
+
+			if clientSecretDecrypted, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey); err != nil {
+				return nil, err
+			} else if clientSecretDecrypted != input.ClientSecret {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+					"Client authentication failed.", http.StatusUnauthorized)
+			}
+		} else if len(input.ClientSecret) > 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode(
+				"invalid_request",
+				"This client is configured as public, which means a client_secret is not required. To proceed, please remove the client_secret from your request.",
+				http.StatusBadRequest,
+			)
+		}
+
+		if len(input.DeviceCode) == 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Missing required device_code parameter.", http.StatusBadRequest)
+		}
+
+		deviceCodeHash, err := hashutil.HashString(input.DeviceCode)
+		if err != nil {
+			return nil, err
+		}
+
+		deviceCode, err := val.database.GetDeviceCodeByDeviceCodeHash(nil, deviceCodeHash)
+		if err != nil {
+			return nil, err
+		} else if deviceCode == nil || deviceCode.ClientId != client.Id {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "The device code is invalid.",
+				http.StatusBadRequest)
+		} else if deviceCode.IsExpired() {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("expired_token", "The device code has expired.",
+				http.StatusBadRequest)
+		}
+
+		switch deviceCode.Status {
+		case enums.DeviceCodeStatusPending.String():
+			return nil, val.pollPendingDeviceCode(deviceCode)
+		case enums.DeviceCodeStatusDenied.String():
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("access_denied",
+				"The user denied the authorization request.", http.StatusBadRequest)
+		case enums.DeviceCodeStatusApproved.String():
+			// the authorization code created when the user approved the request
+		default:
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The device code has already been used.", http.StatusBadRequest)
+		}
+
+		codeEntity, err := val.database.GetCodeById(nil, deviceCode.CodeId.Int64)
+		if err != nil {
+			return nil, err
+		} else if codeEntity == nil || codeEntity.Used {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "The device code is invalid.",
+				http.StatusBadRequest)
+		}
+
+		if err = val.database.CodeLoadClient(nil, codeEntity); err != nil {
+			return nil, err
+		}
+
+		if err = val.database.CodeLoadUser(nil, codeEntity); err != nil {
+			return nil, err
+		}
+
+		if !codeEntity.User.Enabled {
+			val.auditLogger.Log(constants.AuditUserDisabled, map[string]interface{}{
+				"userId": codeEntity.User.Id,
+			})
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The user account is disabled.",
+				http.StatusBadRequest)
+		}
+
+		if err = val.validateCodeResource(codeEntity, input.Resources); err != nil {
+			return nil, err
+		}
+
+		return &ValidateTokenRequestResult{
+			CodeEntity:        codeEntity,
+			Client:            client,
+			DeviceCode:        deviceCode,
+			DPoPKeyThumbprint: dpopKeyThumbprint,
+		}, nil
+	case constants.CIBAGrantType:
+		if !client.CIBAEnabled {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"The client associated with the provided client_id does not support backchannel authentication.",
+				http.StatusBadRequest)
+		}
+
+		if client.IsPublic {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"Public clients can't use backchannel authentication.", http.StatusBadRequest)
+		} else if !clientAuthenticated {
+			if len(input.ClientSecret) == 0 {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+					clientSecretRequiredErrorMsg, http.StatusBadRequest)
+			}
+
+			if clientSecretDecrypted, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey); err != nil {
+				return nil, err
+			} else if clientSecretDecrypted != input.ClientSecret {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+					"Client authentication failed.", http.StatusUnauthorized)
+			}
+		}
+
+		if len(input.AuthReqId) == 0 {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Missing required auth_req_id parameter.", http.StatusBadRequest)
+		}
+
+		authReqIdHash, err := hashutil.HashString(input.AuthReqId)
+		if err != nil {
+			return nil, err
+		}
+
+		cibaRequest, err := val.database.GetCIBARequestByAuthReqIdHash(nil, authReqIdHash)
+		if err != nil {
+			return nil, err
+		} else if cibaRequest == nil || cibaRequest.ClientId != client.Id {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "The auth_req_id is invalid.",
+				http.StatusBadRequest)
+		} else if cibaRequest.IsExpired() {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("expired_token", "The auth_req_id has expired.",
+				http.StatusBadRequest)
+		}
+
+		switch cibaRequest.Status {
+		case enums.CIBARequestStatusPending.String():
+			return nil, val.pollPendingCIBARequest(cibaRequest)
+		case enums.CIBARequestStatusDenied.String():
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("access_denied",
+				"The user denied the authentication request.", http.StatusBadRequest)
+		case enums.CIBARequestStatusApproved.String():
+			// the authorization code created when the user approved the request
+		default:
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The auth_req_id has already been used.", http.StatusBadRequest)
+		}
+
+		codeEntity, err := val.database.GetCodeById(nil, cibaRequest.CodeId.Int64)
+		if err != nil {
+			return nil, err
+		} else if codeEntity == nil || codeEntity.Used {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "The auth_req_id is invalid.",
+				http.StatusBadRequest)
+		}
+
+		if err = val.database.CodeLoadClient(nil, codeEntity); err != nil {
+			return nil, err
+		}
+
+		if err = val.database.CodeLoadUser(nil, codeEntity); err != nil {
+			return nil, err
+		}
+
+		if !codeEntity.User.Enabled {
+			val.auditLogger.Log(constants.AuditUserDisabled, map[string]interface{}{
+				"userId": codeEntity.User.Id,
+			})
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The user account is disabled.",
+				http.StatusBadRequest)
+		}
+
+		if err = val.validateCodeResource(codeEntity, input.Resources); err != nil {
+			return nil, err
+		}
+
+		return &ValidateTokenRequestResult{
+			CodeEntity:        codeEntity,
+			Client:            client,
+			CIBARequest:       cibaRequest,
+			DPoPKeyThumbprint: dpopKeyThumbprint,
 		}, nil
 	case "refresh_token":
-		if !client.AuthorizationCodeEnabled {
+		if !client.AuthorizationCodeEnabled && !client.DeviceCodeEnabled && !client.CIBAEnabled {
 			return nil, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
 				"The client associated with the provided client_id does not support authorization code flow.",
 				http.StatusBadRequest)
 		}
 
-		if !client.IsPublic {
+		if !client.IsPublic && !clientAuthenticated {
 			if len(input.ClientSecret) == 0 {
 				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
 					clientSecretRequiredErrorMsg, http.StatusBadRequest)
@@ -287,11 +576,17 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 			return nil, errors.WithStack(errors.New("the refresh token is invalid because it does not exist in the database"))
 		}
 
-		if err = val.database.RefreshTokenLoadCode(nil, refreshToken); err != nil {
-			return nil, err
+		// ownership and binding are checked first, so only the holder of the refresh token
+		// can trigger the reuse detection, which revokes the tokens and ends the user session
+		if !oauth.IsCertificateBindingValid(refreshTokenInfo, input.ClientCertificates) {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The refresh token is bound to a different client certificate.", http.StatusBadRequest)
+		} else if len(refreshToken.DPoPJkt) > 0 && refreshToken.DPoPJkt != dpopKeyThumbprint {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The refresh token is bound to a different DPoP key.", http.StatusBadRequest)
 		}
 
-		if err = val.database.CodeLoadUser(nil, &refreshToken.Code); err != nil {
+		if err = val.database.RefreshTokenLoadCode(nil, refreshToken); err != nil {
 			return nil, err
 		}
 
@@ -299,6 +594,16 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", "The refresh token is invalid because it does not belong to the client.", http.StatusBadRequest)
 		}
 
+		if refreshToken.IsSuperseded() {
+			return nil, val.checkRefreshTokenReuse(client, refreshToken, settings)
+		} else if refreshToken.Revoked {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "The refresh token has been revoked.", http.StatusBadRequest)
+		}
+
+		if err = val.database.CodeLoadUser(nil, &refreshToken.Code); err != nil {
+			return nil, err
+		}
+
 		if !refreshToken.Code.User.Enabled {
 			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant", "The user account is disabled.", http.StatusBadRequest)
 		}
@@ -368,13 +673,16 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 			scopes = input.Scope
 		}
 
-		inputScopes := strings.Split(scopes, " ")
-		sub := refreshTokenInfo.GetStringClaim("sub")
-		user, err := val.database.GetUserBySubject(nil, sub)
+		resource, err := getResourceIndicator(input.Resources)
 		if err != nil {
 			return nil, err
+		} else if err = ValidateResourceIndicator(scopes, resource); err != nil {
+			return nil, err
 		}
 
+		// the user is taken from the code, the sub claim is pairwise for the clients of a sector
+		inputScopes := strings.Split(scopes, " ")
+		user := &refreshToken.Code.User
 		for _, inputScopeStr := range inputScopes {
 			if client.ConsentRequired || refreshTokenType == "Offline" {
 				// check if user still consents to this scope
@@ -421,16 +729,361 @@ func (val *TokenValidator) ValidateTokenRequest(ctx context.Context, input *Vali
 		}
 
 		return &ValidateTokenRequestResult{
-			CodeEntity:       &refreshToken.Code,
-			Client:           client,
-			RefreshToken:     refreshToken,
-			RefreshTokenInfo: refreshTokenInfo,
+			CodeEntity:        &refreshToken.Code,
+			Client:            client,
+			RefreshToken:      refreshToken,
+			RefreshTokenInfo:  refreshTokenInfo,
+			DPoPKeyThumbprint: dpopKeyThumbprint,
+			Resource:          resource,
+		}, nil
+	case constants.TokenExchangeGrantType:
+		if !client.TokenExchangeEnabled {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"The client associated with the provided client_id does not support token exchange.",
+				http.StatusBadRequest)
+		}
+
+		if client.IsPublic {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("unauthorized_client",
+				"A public client is not eligible for token exchange. Please review the client configuration.",
+				http.StatusBadRequest)
+		}
+
+		if !clientAuthenticated {
+			if len(input.ClientSecret) == 0 {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request", clientSecretRequiredErrorMsg,
+					http.StatusBadRequest)
+			}
+
+			clientSecretDescrypted, err := encryption.DecryptText(client.ClientSecretEncrypted, settings.AESEncryptionKey)
+			if err != nil {
+				return nil, err
+			} else if clientSecretDescrypted != input.ClientSecret {
+				return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_client",
+					"Client authentication failed.", http.StatusUnauthorized)
+			}
+		}
+
+		if len(input.RequestedTokenType) > 0 && input.RequestedTokenType != constants.TokenTypeAccessToken {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+				"Only access tokens can be requested. The requested_token_type must be "+constants.TokenTypeAccessToken+".",
+				http.StatusBadRequest)
+		}
+
+		subjectToken, err := val.parseExchangedToken("subject_token", input.SubjectToken, input.SubjectTokenType)
+		if err != nil {
+			return nil, err
+		}
+
+		// a client can only exchange the tokens issued to it or meant for it,
+		// and those of the clients it was explicitly allowed to exchange tokens of
+		subjectTokenClientId := subjectToken.GetStringClaim("client_id")
+		if subjectTokenClientId != client.ClientIdentifier && !slices.Contains(subjectToken.GetAudience(), client.ClientIdentifier) &&
+			!client.CanExchangeTokensOf(subjectTokenClientId) {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The client is not allowed to exchange the subject_token.", http.StatusBadRequest)
+		}
+
+		// the sub claim is pairwise when the subject_token was issued to a client of a sector
+		var subjectTokenClient *models.Client
+		if subjectTokenClientId == client.ClientIdentifier {
+			subjectTokenClient = client
+		} else if len(subjectTokenClientId) > 0 {
+			if subjectTokenClient, err = val.database.GetClientByClientIdentifier(nil, subjectTokenClientId); err != nil {
+				return nil, err
+			}
+		}
+
+		user, err := oauth.ResolveSubject(val.database, subjectTokenClient, subjectToken.GetStringClaim("sub"))
+		if err != nil {
+			return nil, err
+		} else if user == nil || !user.Enabled {
+			return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+				"The subject_token must belong to an existing and enabled user.", http.StatusBadRequest)
+		}
+
+		actor, err := val.getTokenExchangeActor(client, subjectToken, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = val.database.ClientLoadPermissions(nil, client); err != nil {
+			return nil, err
+		}
+
+		if err = val.database.PermissionsLoadResources(nil, client.Permissions); err != nil {
+			return nil, err
+		}
+
+		scope, err := val.getTokenExchangeScope(client, subjectToken, input)
+		if err != nil {
+			return nil, err
+		}
+
+		// the issued token identifies the user to the client doing the exchange
+		subject, err := oauth.GetSubject(val.database, settings, client, user)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ValidateTokenRequestResult{
+			Client:            client,
+			Scope:             scope,
+			Subject:           subject,
+			Actor:             actor,
+			DPoPKeyThumbprint: dpopKeyThumbprint,
 		}, nil
 	default:
 		return nil, customerrors.NewErrorDetailWithHttpStatusCode("unsupported_grant_type", "Unsupported grant_type.", http.StatusBadRequest)
 	}
 }
 
+// validateCodeResource validates the resource of a token request redeeming a code. It defaults to the
+// resource of the authorization request, and replaces it on the code so the access token is restricted to it.
+func (val *TokenValidator) validateCodeResource(codeEntity *models.Code, resources []string) error {
+	resource, err := getResourceIndicator(resources)
+	if err != nil {
+		return err
+	} else if len(resource) == 0 {
+		resource = codeEntity.Resource
+	}
+
+	if err = ValidateResourceIndicator(codeEntity.Scope, resource); err != nil {
+		return err
+	}
+
+	codeEntity.Resource = resource
+	return nil
+}
+
+// pollPendingDeviceCode records the polling attempt and returns slow_down
+// if the client polls faster than the interval, authorization_pending otherwise (RFC 8628, section 3.5)
+func (val *TokenValidator) pollPendingDeviceCode(deviceCode *models.DeviceCode) error {
+	now := time.Now().UTC()
+	tooFast := deviceCode.LastPolledAt.Valid &&
+		now.Before(deviceCode.LastPolledAt.Time.Add(time.Duration(deviceCode.PollingIntervalSeconds)*time.Second))
+	if tooFast {
+		deviceCode.PollingIntervalSeconds += 5
+	}
+
+	deviceCode.LastPolledAt = sql.NullTime{Time: now, Valid: true}
+	if err := val.database.UpdateDeviceCode(nil, deviceCode); err != nil {
+		return err
+	}
+
+	if tooFast {
+		return customerrors.NewErrorDetailWithHttpStatusCode("slow_down",
+			"The client is polling too quickly. Please increase the polling interval.", http.StatusBadRequest)
+	}
+
+	return customerrors.NewErrorDetailWithHttpStatusCode("authorization_pending",
+		"The user has not yet completed the authorization.", http.StatusBadRequest)
+}
+
+// pollPendingCIBARequest records the polling attempt and returns slow_down if the
+// client polls faster than the interval, authorization_pending otherwise (OpenID Connect CIBA, section 11)
+func (val *TokenValidator) pollPendingCIBARequest(cibaRequest *models.CIBARequest) error {
+	now := time.Now().UTC()
+	tooFast := cibaRequest.LastPolledAt.Valid &&
+		now.Before(cibaRequest.LastPolledAt.Time.Add(time.Duration(cibaRequest.PollingIntervalSeconds)*time.Second))
+	if tooFast {
+		cibaRequest.PollingIntervalSeconds += 5
+	}
+
+	cibaRequest.LastPolledAt = sql.NullTime{Time: now, Valid: true}
+	if err := val.database.UpdateCIBARequest(nil, cibaRequest); err != nil {
+		return err
+	}
+
+	if tooFast {
+		return customerrors.NewErrorDetailWithHttpStatusCode("slow_down",
+			"The client is polling too quickly. Please increase the polling interval.", http.StatusBadRequest)
+	}
+
+	return customerrors.NewErrorDetailWithHttpStatusCode("authorization_pending",
+		"The user has not yet completed the authentication.", http.StatusBadRequest)
+}
+
+// checkRefreshTokenReuse rejects a refresh token, with its code loaded, presented again after it was rotated. Within the
+// grace period, which covers clients refreshing concurrently, the token is only rejected: rotating it
+// again would split the chain in two. Past it, the token is assumed stolen: its whole chain is
+// revoked and the user session terminated (OAuth 2.0 Security BCP, section 4.14.2).
+func (val *TokenValidator) checkRefreshTokenReuse(client *models.Client, refreshToken *models.RefreshToken, settings *models.Settings) error {
+	gracePeriodInSeconds := settings.RefreshTokenReuseGracePeriodInSeconds
+	if client.RefreshTokenReuseGracePeriodInSeconds.Valid {
+		gracePeriodInSeconds = int(client.RefreshTokenReuseGracePeriodInSeconds.Int32)
+	}
+
+	if time.Now().UTC().Before(refreshToken.SupersededAt.Time.Add(time.Duration(gracePeriodInSeconds) * time.Second)) {
+		return customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+			"The refresh token has already been used. Please use the refresh token issued in its place.", http.StatusBadRequest)
+	}
+
+	// offline refresh tokens are not bound to the session, but it was started with the same authorization
+	sessionIdentifier := refreshToken.SessionIdentifier
+	if len(sessionIdentifier) == 0 {
+		sessionIdentifier = refreshToken.Code.SessionIdentifier
+	}
+
+	tx, err := val.database.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	defer val.database.RollbackTransaction(tx) //nolint:errcheck
+
+	if err = val.database.RevokeRefreshTokensByFirstRefreshTokenJti(tx, refreshToken.FirstRefreshTokenJti); err != nil {
+		return err
+	}
+
+	if len(sessionIdentifier) > 0 {
+		userSession, err := val.database.GetUserSessionBySessionIdentifier(tx, sessionIdentifier)
+		if err != nil {
+			return err
+		}
+
+		if userSession != nil {
+			if err = val.database.RevokeRefreshTokensBySessionIdentifier(tx, sessionIdentifier); err != nil {
+				return err
+			}
+
+			if err = val.database.DeleteUserSession(tx, userSession.Id); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = val.database.CommitTransaction(tx); err != nil {
+		return err
+	}
+
+	val.auditLogger.Log(constants.AuditRefreshTokenReuseDetected, map[string]interface{}{
+		"clientId":             refreshToken.Code.ClientId,
+		"userId":               refreshToken.Code.UserId,
+		"firstRefreshTokenJti": refreshToken.FirstRefreshTokenJti,
+		"sessionIdentifier":    sessionIdentifier,
+	})
+
+	return customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+		"The refresh token has already been used. The tokens issued from it have been revoked.", http.StatusBadRequest)
+}
+
+// parseExchangedToken validates a subject_token or actor_token of a token exchange,
+// which must be an unexpired access token issued by this server
+func (val *TokenValidator) parseExchangedToken(name string, token string, tokenType string) (*oauth.Jwt, error) {
+	if len(token) == 0 {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			fmt.Sprintf("Missing required %v parameter.", name), http.StatusBadRequest)
+	} else if tokenType != constants.TokenTypeAccessToken {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			fmt.Sprintf("Unsupported %v_type. Only access tokens (%v) can be exchanged.", name, constants.TokenTypeAccessToken),
+			http.StatusBadRequest)
+	}
+
+	parsedToken, err := val.tokenParser.DecodeAndValidateTokenString(token, nil, true)
+	if err != nil || parsedToken.GetStringClaim("typ") != enums.TokenTypeBearer.String() {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_grant",
+			fmt.Sprintf("The %v is invalid, expired or revoked.", name), http.StatusBadRequest)
+	}
+
+	return parsedToken, nil
+}
+
+// getTokenExchangeActor returns the act claim of the token issued by a token exchange (RFC 8693, section 4.1).
+// The actor is the subject of the actor_token or, without one, the client itself. Clients allowed to
+// impersonate can leave out the actor_token to get a token without an act claim. The act claim of the
+// subject_token is nested, so the whole delegation chain is kept.
+func (val *TokenValidator) getTokenExchangeActor(client *models.Client, subjectToken *oauth.Jwt, input *ValidateTokenRequestInput) (map[string]interface{}, error) {
+	priorActor, _ := subjectToken.Claims["act"].(map[string]interface{})
+	actor := map[string]interface{}{}
+	if len(input.ActorToken) > 0 {
+		actorToken, err := val.parseExchangedToken("actor_token", input.ActorToken, input.ActorTokenType)
+		if err != nil {
+			return nil, err
+		}
+		actor["sub"] = actorToken.GetStringClaim("sub")
+	} else if len(input.ActorTokenType) > 0 {
+		return nil, customerrors.NewErrorDetailWithHttpStatusCode("invalid_request",
+			"The actor_token_type can only be sent with an actor_token.", http.StatusBadRequest)
+	} else if client.TokenExchangeImpersonationEnabled {
+		// impersonation does not hide an existing delegation
+		return priorActor, nil
+	} else {
+		actor["sub"] = client.ClientIdentifier
+	}
+
+	if priorActor != nil {
+		actor["act"] = priorActor
+	}
+	return actor, nil
+}
+
+// getTokenExchangeScope returns the scope of the token issued by a token exchange. It can only
+// narrow the scope of the subject_token, to the resource permissions granted to the client.
+// The audience parameter limits the scope to the permissions of the requested resources.
+func (val *TokenValidator) getTokenExchangeScope(client *models.Client, subjectToken *oauth.Jwt, input *ValidateTokenRequestInput) (string, error) {
+	subjectScopes := []string{}
+	for _, scopeStr := range strings.Fields(subjectToken.GetStringClaim("scope")) {
+		if !oidc.IsIdTokenScope(scopeStr) && !oidc.IsOfflineAccessScope(scopeStr) {
+			subjectScopes = append(subjectScopes, scopeStr)
+		}
+	}
+
+	requestedScopes := strings.Fields(input.Scope)
+	if len(requestedScopes) == 0 {
+		// no scope was passed, let's include the permissions of the subject_token the client may have
+		for _, scopeStr := range subjectScopes {
+			for _, perm := range client.Permissions {
+				if scopeStr == perm.Resource.ResourceIdentifier+":"+perm.PermissionIdentifier {
+					requestedScopes = append(requestedScopes, scopeStr)
+					break
+				}
+			}
+		}
+	}
+
+	scopes := []string{}
+	for _, scopeStr := range requestedScopes {
+		if !slices.Contains(subjectScopes, scopeStr) {
+			return "", customerrors.NewErrorDetailWithHttpStatusCode("invalid_scope",
+				fmt.Sprintf("Scope '%v' is not granted by the subject_token. Token exchange can only narrow the scope.", scopeStr),
+				http.StatusBadRequest)
+		}
+
+		resourceIdentifier, _, _ := strings.Cut(scopeStr, ":")
+		if len(input.Audience) == 0 || slices.Contains(input.Audience, resourceIdentifier) {
+			scopes = append(scopes, scopeStr)
+		}
+	}
+
+	for _, audience := range input.Audience {
+		found := false
+		for _, scopeStr := range scopes {
+			if strings.HasPrefix(scopeStr, audience+":") {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return "", customerrors.NewErrorDetailWithHttpStatusCode("invalid_target",
+				fmt.Sprintf("A token for the audience '%v' cannot be issued with the requested scope.", audience),
+				http.StatusBadRequest)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return "", customerrors.NewErrorDetailWithHttpStatusCode("invalid_scope",
+			"None of the scopes of the subject_token is granted to the client.", http.StatusBadRequest)
+	}
+
+	scope := strings.Join(scopes, " ")
+	if err := val.validateClientCredentialsScopes(scope, client); err != nil {
+		return "", err
+	}
+
+	return scope, nil
+}
+
 func (val *TokenValidator) validateClientCredentialsScopes(scope string, client *models.Client) error {
 	if len(scope) == 0 {
 		return nil
@@ -492,9 +1145,10 @@ func (val *TokenValidator) validateClientCredentialsScopes(scope string, client
 				http.StatusBadRequest)
 		}
 
+		// the same permission identifier can exist on other resources
 		clientHasPermission := false
 		for _, perm := range client.Permissions {
-			if perm.PermissionIdentifier == parts[1] {
+			if perm.ResourceId == res.Id && perm.PermissionIdentifier == parts[1] {
 				clientHasPermission = true
 				break
 			}
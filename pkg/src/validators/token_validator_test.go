@@ -2,19 +2,23 @@ package validators
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto/x509"
 	"database/sql"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	mocksAudit "github.com/pchchv/aas/pkg/src/audit/mocks"
 	"github.com/pchchv/aas/pkg/src/constants"
 	"github.com/pchchv/aas/pkg/src/customerrors"
 	mocksDB "github.com/pchchv/aas/pkg/src/database/mocks"
 	"github.com/pchchv/aas/pkg/src/encryption"
+	"github.com/pchchv/aas/pkg/src/hashutil"
 	"github.com/pchchv/aas/pkg/src/models"
 	"github.com/pchchv/aas/pkg/src/oauth"
 	mocksOAuth "github.com/pchchv/aas/pkg/src/oauth/mocks"
@@ -898,7 +902,7 @@ func TestValidateTokenRequest_ClientCredentials(t *testing.T) {
 			ClientCredentialsEnabled: true,
 			IsPublic:                 false,
 			ClientSecretEncrypted:    clientSecretEncrypted,
-			Permissions:              []models.Permission{{PermissionIdentifier: "permission"}},
+			Permissions:              []models.Permission{{ResourceId: 1, PermissionIdentifier: "permission"}},
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "valid_client").Return(client, nil)
@@ -978,7 +982,7 @@ func TestValidateTokenRequest_ClientCredentials(t *testing.T) {
 			ClientCredentialsEnabled: true,
 			IsPublic:                 false,
 			ClientSecretEncrypted:    clientSecretEncrypted,
-			Permissions:              []models.Permission{{PermissionIdentifier: "read"}, {PermissionIdentifier: "write"}},
+			Permissions:              []models.Permission{{ResourceId: 1, PermissionIdentifier: "read"}, {ResourceId: 2, PermissionIdentifier: "write"}},
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "valid_client").Return(client, nil)
@@ -1232,7 +1236,7 @@ func TestValidateTokenRequest_ClientCredentials(t *testing.T) {
 			ClientCredentialsEnabled: true,
 			IsPublic:                 false,
 			ClientSecretEncrypted:    clientSecretEncrypted,
-			Permissions:              []models.Permission{{PermissionIdentifier: "read"}, {PermissionIdentifier: "write"}, {PermissionIdentifier: "delete"}},
+			Permissions:              []models.Permission{{ResourceId: 1, PermissionIdentifier: "read"}, {ResourceId: 2, PermissionIdentifier: "write"}, {ResourceId: 3, PermissionIdentifier: "delete"}},
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "valid_client").Return(client, nil)
@@ -1250,6 +1254,48 @@ func TestValidateTokenRequest_ClientCredentials(t *testing.T) {
 		assert.NotNil(t, result)
 		assert.Equal(t, "resource1:read resource2:write resource3:delete", result.Scope)
 	})
+
+	t.Run("Permission granted on a different resource", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+		settings := &models.Settings{
+			AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "client_credentials",
+			ClientId:     "valid_client",
+			ClientSecret: "valid_secret",
+			Scope:        "resource2:read",
+		}
+
+		// the client has the read permission of resource1 only
+		clientSecretEncrypted, _ := encryption.EncryptText("valid_secret", settings.AESEncryptionKey)
+		client := &models.Client{
+			ClientIdentifier:         "valid_client",
+			Enabled:                  true,
+			ClientCredentialsEnabled: true,
+			ClientSecretEncrypted:    clientSecretEncrypted,
+			Permissions:              []models.Permission{{ResourceId: 1, PermissionIdentifier: "read"}},
+		}
+
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "valid_client").Return(client, nil)
+		mockDB.On("ClientLoadPermissions", mock.Anything, client).Return(nil)
+		mockDB.On("PermissionsLoadResources", mock.Anything, mock.AnythingOfType("[]models.Permission")).Return(nil)
+		mockDB.On("GetResourceByResourceIdentifier", mock.Anything, "resource2").Return(&models.Resource{Id: 2, ResourceIdentifier: "resource2"}, nil)
+		mockDB.On("GetPermissionsByResourceId", mock.Anything, int64(2)).Return([]models.Permission{{ResourceId: 2, PermissionIdentifier: "read"}}, nil)
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_scope", customErr.GetCode())
+		assert.Equal(t, "Permission to access scope 'resource2:read' is not granted to the client.", customErr.GetDescription())
+	})
 }
 
 func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
@@ -1429,7 +1475,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil).Once()
-		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_refresh_token", (*rsa.PublicKey)(nil), true).Return(nil, errors.New("token is expired")).Once()
+		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_refresh_token", nil, true).Return(nil, errors.New("token is expired")).Once()
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
 		assert.Nil(t, result)
@@ -1468,7 +1514,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil).Once()
 		// Mock a JwtToken without a JTI claim
 		mockJwtToken := &oauth.Jwt{}
-		mockTokenParser.On("DecodeAndValidateTokenString", "refresh_token_without_jti", (*rsa.PublicKey)(nil), true).Return(mockJwtToken, nil).Once()
+		mockTokenParser.On("DecodeAndValidateTokenString", "refresh_token_without_jti", nil, true).Return(mockJwtToken, nil).Once()
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
 		assert.Nil(t, result)
@@ -1507,7 +1553,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 			},
 		}
 
-		mockTokenParser.On("DecodeAndValidateTokenString", "non_existent_refresh_token", (*rsa.PublicKey)(nil), true).Return(mockJwtToken, nil).Once()
+		mockTokenParser.On("DecodeAndValidateTokenString", "non_existent_refresh_token", nil, true).Return(mockJwtToken, nil).Once()
 		mockDB.On("GetRefreshTokenByJti", (*sql.Tx)(nil), "non_existent_jti").Return(nil, nil).Once()
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
@@ -1516,6 +1562,274 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		assert.Contains(t, err.Error(), "the refresh token is invalid because it does not exist in the database")
 	})
 
+	t.Run("Revoked refresh token", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+		settings := &models.Settings{
+			AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "refresh_token",
+			ClientId:     "client1",
+			RefreshToken: "revoked_refresh_token",
+		}
+
+		client := &models.Client{
+			ClientIdentifier:         "client1",
+			Enabled:                  true,
+			AuthorizationCodeEnabled: true,
+			IsPublic:                 true,
+		}
+
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil).Once()
+		mockJwtToken := &oauth.Jwt{
+			Claims: jwt.MapClaims{
+				"jti": "revoked_jti",
+			},
+		}
+
+		mockTokenParser.On("DecodeAndValidateTokenString", "revoked_refresh_token", nil, true).Return(mockJwtToken, nil).Once()
+		mockDB.On("GetRefreshTokenByJti", (*sql.Tx)(nil), "revoked_jti").Return(&models.RefreshToken{
+			RefreshTokenJti: "revoked_jti",
+			Revoked:         true,
+		}, nil).Once()
+		mockDB.On("RefreshTokenLoadCode", (*sql.Tx)(nil), mock.Anything).Return(nil).Once()
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_grant", customErr.GetCode())
+		assert.Equal(t, "The refresh token has been revoked.", customErr.GetDescription())
+	})
+
+	t.Run("Reused refresh token", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+		settings := &models.Settings{
+			AESEncryptionKey:                      []byte("0123456789abcdef0123456789abcdef"),
+			RefreshTokenReuseGracePeriodInSeconds: 10,
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "refresh_token",
+			ClientId:     "client1",
+			RefreshToken: "reused_refresh_token",
+		}
+
+		client := &models.Client{
+			Id:                       1,
+			ClientIdentifier:         "client1",
+			Enabled:                  true,
+			AuthorizationCodeEnabled: true,
+			IsPublic:                 true,
+		}
+
+		refreshToken := &models.RefreshToken{
+			RefreshTokenJti:      "reused_jti",
+			FirstRefreshTokenJti: "first_jti",
+			SessionIdentifier:    "session1",
+			Revoked:              true,
+			SupersededAt:         sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true},
+			Code:                 models.Code{ClientId: 1, UserId: 3},
+		}
+
+		tx := &sql.Tx{}
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "reused_refresh_token", nil, true).Return(&oauth.Jwt{
+			Claims: jwt.MapClaims{"jti": "reused_jti", "typ": "Refresh"},
+		}, nil)
+		mockDB.On("GetRefreshTokenByJti", mock.Anything, "reused_jti").Return(refreshToken, nil)
+		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
+		mockDB.On("BeginTransaction").Return(tx, nil)
+		mockDB.On("RevokeRefreshTokensByFirstRefreshTokenJti", tx, "first_jti").Return(nil)
+		mockDB.On("GetUserSessionBySessionIdentifier", tx, "session1").Return(&models.UserSession{Id: 5, SessionIdentifier: "session1"}, nil)
+		mockDB.On("RevokeRefreshTokensBySessionIdentifier", tx, "session1").Return(nil)
+		mockDB.On("DeleteUserSession", tx, int64(5)).Return(nil)
+		mockDB.On("CommitTransaction", tx).Return(nil)
+		mockDB.On("RollbackTransaction", tx).Return(nil)
+		mockAuditLogger.On("Log", constants.AuditRefreshTokenReuseDetected, map[string]interface{}{
+			"clientId":             int64(1),
+			"userId":               int64(3),
+			"firstRefreshTokenJti": "first_jti",
+			"sessionIdentifier":    "session1",
+		}).Return()
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_grant", customErr.GetCode())
+		assert.Equal(t, "The refresh token has already been used. The tokens issued from it have been revoked.", customErr.GetDescription())
+	})
+
+	t.Run("Reused refresh token within the grace period", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+		settings := &models.Settings{
+			AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "refresh_token",
+			ClientId:     "client1",
+			RefreshToken: "concurrent_refresh_token",
+		}
+
+		client := &models.Client{
+			Id:                                    1,
+			ClientIdentifier:                      "client1",
+			Enabled:                               true,
+			AuthorizationCodeEnabled:              true,
+			IsPublic:                              true,
+			RefreshTokenReuseGracePeriodInSeconds: sql.NullInt32{Int32: 30, Valid: true},
+		}
+
+		// the token is rejected without rotating it again, and the chain is not revoked
+		refreshToken := &models.RefreshToken{
+			RefreshTokenJti: "concurrent_jti",
+			Revoked:         true,
+			SupersededAt:    sql.NullTime{Time: time.Now().UTC().Add(-5 * time.Second), Valid: true},
+			Code:            models.Code{ClientId: 1, UserId: 3},
+		}
+
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "concurrent_refresh_token", nil, true).Return(&oauth.Jwt{
+			Claims: jwt.MapClaims{"jti": "concurrent_jti", "typ": "Refresh"},
+		}, nil)
+		mockDB.On("GetRefreshTokenByJti", mock.Anything, "concurrent_jti").Return(refreshToken, nil)
+		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_grant", customErr.GetCode())
+		assert.Equal(t, "The refresh token has already been used. Please use the refresh token issued in its place.", customErr.GetDescription())
+		mockDB.AssertNotCalled(t, "RevokeRefreshTokensByFirstRefreshTokenJti", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reused refresh token with a client grace period of zero", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+		settings := &models.Settings{
+			AESEncryptionKey:                      []byte("0123456789abcdef0123456789abcdef"),
+			RefreshTokenReuseGracePeriodInSeconds: 30,
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "refresh_token",
+			ClientId:     "client1",
+			RefreshToken: "reused_refresh_token",
+		}
+
+		// the client is stricter than the settings, any reuse revokes the chain
+		client := &models.Client{
+			Id:                                    1,
+			ClientIdentifier:                      "client1",
+			Enabled:                               true,
+			AuthorizationCodeEnabled:              true,
+			IsPublic:                              true,
+			RefreshTokenReuseGracePeriodInSeconds: sql.NullInt32{Int32: 0, Valid: true},
+		}
+
+		refreshToken := &models.RefreshToken{
+			RefreshTokenJti:      "reused_jti",
+			FirstRefreshTokenJti: "first_jti",
+			Revoked:              true,
+			SupersededAt:         sql.NullTime{Time: time.Now().UTC().Add(-5 * time.Second), Valid: true},
+			Code:                 models.Code{ClientId: 1, UserId: 3},
+		}
+
+		tx := &sql.Tx{}
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "reused_refresh_token", nil, true).Return(&oauth.Jwt{
+			Claims: jwt.MapClaims{"jti": "reused_jti", "typ": "Refresh"},
+		}, nil)
+		mockDB.On("GetRefreshTokenByJti", mock.Anything, "reused_jti").Return(refreshToken, nil)
+		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
+		mockDB.On("BeginTransaction").Return(tx, nil)
+		mockDB.On("RevokeRefreshTokensByFirstRefreshTokenJti", tx, "first_jti").Return(nil)
+		mockDB.On("CommitTransaction", tx).Return(nil)
+		mockDB.On("RollbackTransaction", tx).Return(nil)
+		mockAuditLogger.On("Log", constants.AuditRefreshTokenReuseDetected, mock.Anything).Return()
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "The refresh token has already been used. The tokens issued from it have been revoked.", customErr.GetDescription())
+	})
+
+	t.Run("Reused refresh token presented by another client", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+		settings := &models.Settings{
+			AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "refresh_token",
+			ClientId:     "client2",
+			RefreshToken: "reused_refresh_token",
+		}
+
+		client := &models.Client{
+			Id:                       2,
+			ClientIdentifier:         "client2",
+			Enabled:                  true,
+			AuthorizationCodeEnabled: true,
+			IsPublic:                 true,
+		}
+
+		// the refresh token was issued to client 1, so its chain and session are left alone
+		refreshToken := &models.RefreshToken{
+			RefreshTokenJti:      "reused_jti",
+			FirstRefreshTokenJti: "first_jti",
+			SessionIdentifier:    "session1",
+			Revoked:              true,
+			SupersededAt:         sql.NullTime{Time: time.Now().UTC().Add(-time.Hour), Valid: true},
+			Code:                 models.Code{ClientId: 1, UserId: 3},
+		}
+
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client2").Return(client, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "reused_refresh_token", nil, true).Return(&oauth.Jwt{
+			Claims: jwt.MapClaims{"jti": "reused_jti", "typ": "Refresh"},
+		}, nil)
+		mockDB.On("GetRefreshTokenByJti", mock.Anything, "reused_jti").Return(refreshToken, nil)
+		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "The refresh token is invalid because it does not belong to the client.", customErr.GetDescription())
+		mockDB.AssertNotCalled(t, "RevokeRefreshTokensByFirstRefreshTokenJti", mock.Anything, mock.Anything)
+		mockDB.AssertNotCalled(t, "DeleteUserSession", mock.Anything, mock.Anything)
+		mockAuditLogger.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+	})
+
 	t.Run("Refresh token with mismatched client", func(t *testing.T) {
 		mockDB := mocksDB.NewDatabase(t)
 		mockTokenParser := mocksOAuth.NewTokenParser(t)
@@ -1563,10 +1877,9 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "mismatched_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "mismatched_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "mismatched_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
-		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
 		assert.Nil(t, result)
@@ -1577,6 +1890,100 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		assert.Contains(t, customErr.GetDescription(), "The refresh token is invalid because it does not belong to the client")
 	})
 
+	t.Run("Certificate-bound refresh token without the client certificate", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+
+		settings := &models.Settings{
+			AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:          "refresh_token",
+			ClientId:           "client1",
+			RefreshToken:       "bound_refresh_token",
+			ClientCertificates: []*x509.Certificate{{Raw: []byte("other certificate")}},
+		}
+
+		client := &models.Client{
+			Id:                       1,
+			ClientIdentifier:         "client1",
+			Enabled:                  true,
+			AuthorizationCodeEnabled: true,
+			IsPublic:                 true,
+		}
+
+		refreshTokenJwt := &oauth.Jwt{
+			Claims: jwt.MapClaims{
+				"jti": "bound_jti",
+				"typ": "Refresh",
+				"cnf": map[string]interface{}{
+					oauth.ConfirmationMethodX5tS256: oauth.CertificateThumbprint(&x509.Certificate{Raw: []byte("certificate")}),
+				},
+			},
+		}
+
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "bound_refresh_token", nil, true).Return(refreshTokenJwt, nil)
+		mockDB.On("GetRefreshTokenByJti", mock.Anything, "bound_jti").Return(&models.RefreshToken{RefreshTokenJti: "bound_jti"}, nil)
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_grant", customErr.GetCode())
+		assert.Equal(t, "The refresh token is bound to a different client certificate.", customErr.GetDescription())
+	})
+
+	t.Run("DPoP-bound refresh token without a DPoP proof", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+		mockAuditLogger := mocksAudit.NewAuditLogger(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+
+		settings := &models.Settings{
+			AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+		}
+
+		ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+		input := &ValidateTokenRequestInput{
+			GrantType:    "refresh_token",
+			ClientId:     "client1",
+			RefreshToken: "bound_refresh_token",
+		}
+
+		client := &models.Client{
+			Id:                       1,
+			ClientIdentifier:         "client1",
+			Enabled:                  true,
+			AuthorizationCodeEnabled: true,
+			IsPublic:                 true,
+		}
+
+		refreshTokenJwt := &oauth.Jwt{
+			Claims: jwt.MapClaims{
+				"jti": "bound_jti",
+				"typ": "Refresh",
+			},
+		}
+
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "bound_refresh_token", nil, true).Return(refreshTokenJwt, nil)
+		mockDB.On("GetRefreshTokenByJti", mock.Anything, "bound_jti").Return(&models.RefreshToken{RefreshTokenJti: "bound_jti", DPoPJkt: "jkt"}, nil)
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_grant", customErr.GetCode())
+		assert.Equal(t, "The refresh token is bound to a different DPoP key.", customErr.GetDescription())
+	})
+
 	t.Run("Refresh token for disabled user", func(t *testing.T) {
 		mockDB := mocksDB.NewDatabase(t)
 		mockTokenParser := mocksOAuth.NewTokenParser(t)
@@ -1623,7 +2030,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "disabled_user_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "disabled_user_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "disabled_user_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -1685,7 +2092,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "nil_session_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "nil_session_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "nil_session_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -1754,7 +2161,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_session_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_session_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "invalid_session_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -1816,7 +2223,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "expired_offline_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "expired_offline_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "expired_offline_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -1876,7 +2283,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_offline_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_offline_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "invalid_offline_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -1931,7 +2338,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_typ_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_typ_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "invalid_typ_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -1998,7 +2405,7 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_scope_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "invalid_scope_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "invalid_scope_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
@@ -2071,11 +2478,10 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "valid_offline_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "valid_offline_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "valid_offline_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
-		mockDB.On("GetUserBySubject", mock.Anything, "user123").Return(&models.User{Id: 1, Enabled: true}, nil)
 		mockDB.On("GetConsentByUserIdAndClientId", mock.Anything, int64(1), int64(1)).Return(userConsent, nil)
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
@@ -2143,12 +2549,11 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "valid_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "valid_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "valid_refresh_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
 		mockDB.On("GetUserSessionBySessionIdentifier", mock.Anything, "test_session").Return(userSession, nil)
-		mockDB.On("GetUserBySubject", mock.Anything, "user123").Return(&models.User{Id: 1, Enabled: true}, nil)
 		mockPermissionChecker.On("UserHasScopePermission", int64(1), "srv1:read").Return(true, nil)
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
@@ -2216,12 +2621,11 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "revoked_consent_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "revoked_consent_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "revoked_consent_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
 		mockDB.On("GetUserSessionBySessionIdentifier", mock.Anything, "test_session").Return(userSession, nil)
-		mockDB.On("GetUserBySubject", mock.Anything, "user123").Return(&models.User{Id: 1, Enabled: true}, nil)
 		mockDB.On("GetConsentByUserIdAndClientId", mock.Anything, int64(1), int64(1)).Return(nil, nil) // Consent not found
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
@@ -2290,12 +2694,11 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		}
 
 		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil)
-		mockTokenParser.On("DecodeAndValidateTokenString", "revoked_permission_refresh_token", (*rsa.PublicKey)(nil), true).Return(refreshTokenJwt, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "revoked_permission_refresh_token", nil, true).Return(refreshTokenJwt, nil)
 		mockDB.On("GetRefreshTokenByJti", mock.Anything, "revoked_permission_jti").Return(refreshToken, nil)
 		mockDB.On("RefreshTokenLoadCode", mock.Anything, refreshToken).Return(nil)
 		mockDB.On("CodeLoadUser", mock.Anything, &refreshToken.Code).Return(nil)
 		mockDB.On("GetUserSessionBySessionIdentifier", mock.Anything, "test_session").Return(userSession, nil)
-		mockDB.On("GetUserBySubject", mock.Anything, "user123").Return(&models.User{Id: 1, Enabled: true}, nil)
 		mockPermissionChecker.On("UserHasScopePermission", int64(1), "resource:read").Return(false, nil) // Permission revoked
 		result, err := validator.ValidateTokenRequest(ctx, input)
 
@@ -2308,3 +2711,447 @@ func TestValidateTokenRequest_RefreshToken_AuthCodeDisabled(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, customErr.GetHttpStatusCode())
 	})
 }
+
+func TestValidateTokenRequest_DeviceCode(t *testing.T) {
+	settings := &models.Settings{
+		AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"),
+	}
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+	client := &models.Client{
+		Id:                1,
+		ClientIdentifier:  "tv-app",
+		Enabled:           true,
+		IsPublic:          true,
+		DeviceCodeEnabled: true,
+	}
+	deviceCodeHash, err := hashutil.HashString("device-code")
+	assert.NoError(t, err)
+
+	newValidator := func(t *testing.T) (*TokenValidator, *mocksDB.Database) {
+		mockDB := mocksDB.NewDatabase(t)
+		validator := NewTokenValidator(mockDB, mocksOAuth.NewTokenParser(t), mocksUser.NewPermissionChecker(t), mocksAudit.NewAuditLogger(t))
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "tv-app").Return(client, nil)
+		return validator, mockDB
+	}
+
+	input := &ValidateTokenRequestInput{
+		GrantType:  constants.DeviceCodeGrantType,
+		ClientId:   "tv-app",
+		DeviceCode: "device-code",
+	}
+
+	tests := []struct {
+		name         string
+		deviceCode   *models.DeviceCode
+		expectUpdate bool
+		expectedCode string
+	}{
+		{
+			name:         "unknown device code",
+			expectedCode: "invalid_grant",
+		},
+		{
+			name:         "device code of another client",
+			deviceCode:   &models.DeviceCode{Id: 1, ClientId: 2, Status: "pending", ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}},
+			expectedCode: "invalid_grant",
+		},
+		{
+			name:         "expired",
+			deviceCode:   &models.DeviceCode{Id: 1, ClientId: 1, Status: "pending", ExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}},
+			expectedCode: "expired_token",
+		},
+		{
+			name:         "first poll",
+			deviceCode:   &models.DeviceCode{Id: 1, ClientId: 1, Status: "pending", PollingIntervalSeconds: 5, ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}},
+			expectUpdate: true,
+			expectedCode: "authorization_pending",
+		},
+		{
+			name: "polling too fast",
+			deviceCode: &models.DeviceCode{Id: 1, ClientId: 1, Status: "pending", PollingIntervalSeconds: 5,
+				LastPolledAt: sql.NullTime{Time: time.Now().UTC().Add(-time.Second), Valid: true},
+				ExpiresAt:    sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}},
+			expectUpdate: true,
+			expectedCode: "slow_down",
+		},
+		{
+			name:         "denied",
+			deviceCode:   &models.DeviceCode{Id: 1, ClientId: 1, Status: "denied", ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}},
+			expectedCode: "access_denied",
+		},
+		{
+			name:         "already used",
+			deviceCode:   &models.DeviceCode{Id: 1, ClientId: 1, Status: "used", ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}},
+			expectedCode: "invalid_grant",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, mockDB := newValidator(t)
+			mockDB.On("GetDeviceCodeByDeviceCodeHash", mock.Anything, deviceCodeHash).Return(tt.deviceCode, nil)
+			if tt.expectUpdate {
+				initialInterval := tt.deviceCode.PollingIntervalSeconds
+				mockDB.On("UpdateDeviceCode", mock.Anything, mock.MatchedBy(func(deviceCode *models.DeviceCode) bool {
+					if tt.expectedCode == "slow_down" {
+						return deviceCode.PollingIntervalSeconds == initialInterval+5 && deviceCode.LastPolledAt.Valid
+					}
+					return deviceCode.PollingIntervalSeconds == initialInterval && deviceCode.LastPolledAt.Valid
+				})).Return(nil)
+			}
+
+			result, err := validator.ValidateTokenRequest(ctx, input)
+
+			assert.Nil(t, result)
+			customErr, ok := err.(*customerrors.ErrorDetail)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, customErr.GetCode())
+		})
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		validator, mockDB := newValidator(t)
+		deviceCode := &models.DeviceCode{Id: 1, ClientId: 1, Status: "approved",
+			CodeId:    sql.NullInt64{Int64: 7, Valid: true},
+			ExpiresAt: sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}}
+		code := &models.Code{Id: 7, ClientId: 1, UserId: 9}
+		mockDB.On("GetDeviceCodeByDeviceCodeHash", mock.Anything, deviceCodeHash).Return(deviceCode, nil)
+		mockDB.On("GetCodeById", mock.Anything, int64(7)).Return(code, nil)
+		mockDB.On("CodeLoadClient", mock.Anything, code).Return(nil)
+		mockDB.On("CodeLoadUser", mock.Anything, code).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Code).User = models.User{Id: 9, Enabled: true}
+		}).Return(nil)
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, code, result.CodeEntity)
+		assert.Equal(t, deviceCode, result.DeviceCode)
+	})
+
+	t.Run("device flow disabled", func(t *testing.T) {
+		mockDB := mocksDB.NewDatabase(t)
+		validator := NewTokenValidator(mockDB, mocksOAuth.NewTokenParser(t), mocksUser.NewPermissionChecker(t), mocksAudit.NewAuditLogger(t))
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "tv-app").Return(&models.Client{Id: 1, ClientIdentifier: "tv-app", Enabled: true, IsPublic: true}, nil)
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "unauthorized_client", customErr.GetCode())
+	})
+}
+
+func TestValidateTokenRequest_ClientAssertionRequired(t *testing.T) {
+	mockDB := mocksDB.NewDatabase(t)
+	mockTokenParser := mocksOAuth.NewTokenParser(t)
+	mockPermissionChecker := mocksUser.NewPermissionChecker(t)
+	mockAuditLogger := mocksAudit.NewAuditLogger(t)
+	validator := NewTokenValidator(mockDB, mockTokenParser, mockPermissionChecker, mockAuditLogger)
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, &models.Settings{})
+
+	t.Run("Client secret sent by a private_key_jwt client", func(t *testing.T) {
+		client := &models.Client{
+			ClientIdentifier:         "client1",
+			Enabled:                  true,
+			ClientCredentialsEnabled: true,
+			TokenEndpointAuthMethod:  constants.TokenEndpointAuthMethodPrivateKeyJwt,
+		}
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "client1").Return(client, nil).Once()
+
+		result, err := validator.ValidateTokenRequest(ctx, &ValidateTokenRequestInput{
+			GrantType:    "client_credentials",
+			ClientId:     "client1",
+			ClientSecret: "secret",
+		})
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_client", customErr.GetCode())
+		assert.Equal(t, http.StatusUnauthorized, customErr.GetHttpStatusCode())
+	})
+
+	t.Run("Client assertion and client secret", func(t *testing.T) {
+		result, err := validator.ValidateTokenRequest(ctx, &ValidateTokenRequestInput{
+			GrantType:           "client_credentials",
+			ClientSecret:        "secret",
+			ClientAssertionType: constants.ClientAssertionTypeJwtBearer,
+			ClientAssertion:     "assertion",
+		})
+		assert.Nil(t, result)
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, "invalid_request", customErr.GetCode())
+	})
+}
+
+func TestValidateTokenRequest_TokenExchange(t *testing.T) {
+	settings := &models.Settings{
+		AESEncryptionKey: []byte("0123456789abcdef0123456789abcdef"), // 32-byte key for AES-256
+	}
+	ctx := context.WithValue(context.Background(), constants.ContextKeySettings, settings)
+	clientSecretEncrypted, _ := encryption.EncryptText("gateway_secret", settings.AESEncryptionKey)
+	user := &models.User{Subject: uuid.New(), Enabled: true}
+	// the subject_token was issued to a frontend to call the gateway
+	subjectToken := &oauth.Jwt{Claims: jwt.MapClaims{
+		"sub":   user.Subject.String(),
+		"aud":   "gateway",
+		"typ":   "Bearer",
+		"scope": "openid resource1:read resource2:write resource3:delete",
+	}}
+
+	newClient := func(impersonationEnabled bool) *models.Client {
+		return &models.Client{
+			ClientIdentifier:                  "gateway",
+			Enabled:                           true,
+			ClientSecretEncrypted:             clientSecretEncrypted,
+			TokenExchangeEnabled:              true,
+			TokenExchangeImpersonationEnabled: impersonationEnabled,
+			Permissions: []models.Permission{
+				{ResourceId: 1, PermissionIdentifier: "read", Resource: models.Resource{Id: 1, ResourceIdentifier: "resource1"}},
+				{ResourceId: 2, PermissionIdentifier: "write", Resource: models.Resource{Id: 2, ResourceIdentifier: "resource2"}},
+			},
+		}
+	}
+
+	newInput := func() *ValidateTokenRequestInput {
+		return &ValidateTokenRequestInput{
+			GrantType:        constants.TokenExchangeGrantType,
+			ClientId:         "gateway",
+			ClientSecret:     "gateway_secret",
+			SubjectToken:     "subject-token",
+			SubjectTokenType: constants.TokenTypeAccessToken,
+		}
+	}
+
+	setup := func(t *testing.T, client *models.Client) (*TokenValidator, *mocksDB.Database, *mocksOAuth.TokenParser) {
+		mockDB := mocksDB.NewDatabase(t)
+		mockTokenParser := mocksOAuth.NewTokenParser(t)
+		validator := NewTokenValidator(mockDB, mockTokenParser, mocksUser.NewPermissionChecker(t), mocksAudit.NewAuditLogger(t))
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "gateway").Return(client, nil)
+		return validator, mockDB, mockTokenParser
+	}
+
+	expectScopeChecks := func(mockDB *mocksDB.Database, client *models.Client, resources ...string) {
+		mockDB.On("GetUserBySubject", mock.Anything, user.Subject.String()).Return(user, nil)
+		mockDB.On("ClientLoadPermissions", mock.Anything, client).Return(nil)
+		mockDB.On("PermissionsLoadResources", mock.Anything, client.Permissions).Return(nil)
+		// resourceN has the id N
+		for _, resource := range resources {
+			resourceId, _ := strconv.ParseInt(strings.TrimPrefix(resource, "resource"), 10, 64)
+			mockDB.On("GetResourceByResourceIdentifier", mock.Anything, resource).
+				Return(&models.Resource{Id: resourceId, ResourceIdentifier: resource}, nil)
+			mockDB.On("GetPermissionsByResourceId", mock.Anything, resourceId).
+				Return([]models.Permission{{PermissionIdentifier: "read"}, {PermissionIdentifier: "write"}}, nil)
+		}
+	}
+
+	assertErrorCode := func(t *testing.T, err error, code string) {
+		customErr, ok := err.(*customerrors.ErrorDetail)
+		assert.True(t, ok)
+		assert.Equal(t, code, customErr.GetCode())
+		assert.Equal(t, http.StatusBadRequest, customErr.GetHttpStatusCode())
+	}
+
+	t.Run("Token exchange not enabled", func(t *testing.T) {
+		client := newClient(false)
+		client.TokenExchangeEnabled = false
+		validator, _, _ := setup(t, client)
+
+		result, err := validator.ValidateTokenRequest(ctx, newInput())
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "unauthorized_client")
+	})
+
+	t.Run("Invalid subject token", func(t *testing.T) {
+		validator, _, mockTokenParser := setup(t, newClient(false))
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(nil, customerrors.ErrTokenRevoked)
+
+		result, err := validator.ValidateTokenRequest(ctx, newInput())
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_grant")
+	})
+
+	t.Run("Unsupported subject token type", func(t *testing.T) {
+		validator, _, _ := setup(t, newClient(false))
+		input := newInput()
+		input.SubjectTokenType = "urn:ietf:params:oauth:token-type:id_token"
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_request")
+	})
+
+	t.Run("Client acts for the subject without an actor token", func(t *testing.T) {
+		client := newClient(false)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		expectScopeChecks(mockDB, client, "resource1", "resource2")
+
+		result, err := validator.ValidateTokenRequest(ctx, newInput())
+		assert.NoError(t, err)
+		assert.Equal(t, "resource1:read resource2:write", result.Scope)
+		assert.Equal(t, user.Subject.String(), result.Subject)
+		assert.Equal(t, map[string]interface{}{"sub": "gateway"}, result.Actor)
+	})
+
+	t.Run("Audience narrows the scope", func(t *testing.T) {
+		client := newClient(false)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		expectScopeChecks(mockDB, client, "resource2")
+		input := newInput()
+		input.Audience = []string{"resource2"}
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "resource2:write", result.Scope)
+	})
+
+	t.Run("Audience the client may not request", func(t *testing.T) {
+		client := newClient(false)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		expectScopeChecks(mockDB, client)
+		input := newInput()
+		input.Audience = []string{"resource3"}
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_target")
+	})
+
+	t.Run("Scope not granted by the subject token", func(t *testing.T) {
+		client := newClient(false)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		expectScopeChecks(mockDB, client)
+		input := newInput()
+		input.Scope = "resource1:write"
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_scope")
+	})
+
+	t.Run("Scope not granted to the client", func(t *testing.T) {
+		client := newClient(false)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		expectScopeChecks(mockDB, client)
+		mockDB.On("GetResourceByResourceIdentifier", mock.Anything, "resource3").
+			Return(&models.Resource{Id: 3, ResourceIdentifier: "resource3"}, nil)
+		mockDB.On("GetPermissionsByResourceId", mock.Anything, int64(3)).
+			Return([]models.Permission{{PermissionIdentifier: "delete"}}, nil)
+		input := newInput()
+		input.Scope = "resource3:delete"
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_scope")
+	})
+
+	t.Run("Delegation chains the act claim", func(t *testing.T) {
+		client := newClient(false)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		delegatedSubjectToken := &oauth.Jwt{Claims: jwt.MapClaims{
+			"sub":   user.Subject.String(),
+			"aud":   "gateway",
+			"typ":   "Bearer",
+			"scope": "resource1:read",
+			"act":   map[string]interface{}{"sub": "frontend"},
+		}}
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(delegatedSubjectToken, nil)
+		mockTokenParser.On("DecodeAndValidateTokenString", "actor-token", nil, true).
+			Return(&oauth.Jwt{Claims: jwt.MapClaims{"sub": "support-agent", "typ": "Bearer"}}, nil)
+		expectScopeChecks(mockDB, client, "resource1")
+		input := newInput()
+		input.ActorToken = "actor-token"
+		input.ActorTokenType = constants.TokenTypeAccessToken
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"sub": "support-agent",
+			"act": map[string]interface{}{"sub": "frontend"},
+		}, result.Actor)
+	})
+
+	t.Run("Impersonation", func(t *testing.T) {
+		client := newClient(true)
+		validator, mockDB, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		expectScopeChecks(mockDB, client, "resource1")
+		input := newInput()
+		input.Scope = "resource1:read"
+
+		result, err := validator.ValidateTokenRequest(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "resource1:read", result.Scope)
+		assert.Nil(t, result.Actor)
+	})
+
+	t.Run("Pairwise subjects", func(t *testing.T) {
+		client := newClient(false)
+		client.SubjectType = constants.SubjectTypePairwise
+		client.SectorIdentifier = "gateway.example.com"
+		client.TokenExchangeSubjectClients = "webapp"
+		validator, mockDB, mockTokenParser := setup(t, client)
+		pairwiseSubjectToken := &oauth.Jwt{Claims: jwt.MapClaims{
+			"sub":       "webapp-subject",
+			"client_id": "webapp",
+			"typ":       "Bearer",
+			"scope":     "resource1:read",
+		}}
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(pairwiseSubjectToken, nil)
+		mockDB.On("GetClientByClientIdentifier", mock.Anything, "webapp").Return(&models.Client{
+			ClientIdentifier: "webapp",
+			SubjectType:      constants.SubjectTypePairwise,
+			SectorIdentifier: "webapp.example.com",
+		}, nil)
+		mockDB.On("GetPairwiseSubjectBySectorIdentifierAndSubject", mock.Anything, "webapp.example.com", "webapp-subject").
+			Return(&models.PairwiseSubject{UserId: 5, SectorIdentifier: "webapp.example.com", Subject: "webapp-subject"}, nil)
+		mockDB.On("GetUserById", mock.Anything, int64(5)).Return(&models.User{Id: 5, Subject: user.Subject, Enabled: true}, nil)
+		mockDB.On("ClientLoadPermissions", mock.Anything, client).Return(nil)
+		mockDB.On("PermissionsLoadResources", mock.Anything, client.Permissions).Return(nil)
+		mockDB.On("GetResourceByResourceIdentifier", mock.Anything, "resource1").
+			Return(&models.Resource{Id: 1, ResourceIdentifier: "resource1"}, nil)
+		mockDB.On("GetPermissionsByResourceId", mock.Anything, int64(1)).Return([]models.Permission{{PermissionIdentifier: "read"}}, nil)
+		mockDB.On("GetPairwiseSubjectByUserIdAndSectorIdentifier", mock.Anything, int64(5), "gateway.example.com").
+			Return(&models.PairwiseSubject{UserId: 5, SectorIdentifier: "gateway.example.com", Subject: "gateway-subject"}, nil)
+
+		result, err := validator.ValidateTokenRequest(ctx, newInput())
+		assert.NoError(t, err)
+		assert.Equal(t, "resource1:read", result.Scope)
+		assert.Equal(t, "gateway-subject", result.Subject)
+	})
+
+	t.Run("Subject token of a client the client may not exchange tokens of", func(t *testing.T) {
+		client := newClient(false)
+		client.TokenExchangeSubjectClients = "webapp"
+		validator, _, mockTokenParser := setup(t, client)
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(&oauth.Jwt{Claims: jwt.MapClaims{
+			"sub":       user.Subject.String(),
+			"aud":       "resource1",
+			"client_id": "mobile-app",
+			"typ":       "Bearer",
+			"scope":     "resource1:read",
+		}}, nil)
+
+		result, err := validator.ValidateTokenRequest(ctx, newInput())
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_grant")
+		assert.Equal(t, "The client is not allowed to exchange the subject_token.", err.(*customerrors.ErrorDetail).GetDescription())
+	})
+
+	t.Run("Disabled user", func(t *testing.T) {
+		validator, mockDB, mockTokenParser := setup(t, newClient(false))
+		mockTokenParser.On("DecodeAndValidateTokenString", "subject-token", nil, true).Return(subjectToken, nil)
+		mockDB.On("GetUserBySubject", mock.Anything, user.Subject.String()).Return(&models.User{Subject: user.Subject}, nil)
+
+		result, err := validator.ValidateTokenRequest(ctx, newInput())
+		assert.Nil(t, result)
+		assertErrorCode(t, err, "invalid_grant")
+	})
+}